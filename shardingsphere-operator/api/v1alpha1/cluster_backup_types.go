@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ClusterBackupPhase string
+
+const (
+	// ClusterBackupPhasePending means the cluster is not locked for the backup yet
+	ClusterBackupPhasePending ClusterBackupPhase = "Pending"
+	// ClusterBackupPhaseRunning means the storage nodes are being backed up by the agents
+	ClusterBackupPhaseRunning ClusterBackupPhase = "Running"
+	// ClusterBackupPhaseCompleted means all the storage nodes are backed up
+	ClusterBackupPhaseCompleted ClusterBackupPhase = "Completed"
+	// ClusterBackupPhaseFailed means the backup can not be taken
+	ClusterBackupPhaseFailed ClusterBackupPhase = "Failed"
+	// ClusterBackupPhaseScheduled means the ClusterBackup creates the backups on its schedule
+	ClusterBackupPhaseScheduled ClusterBackupPhase = "Scheduled"
+)
+
+// ClusterBackupMode is the backup mode of gs_probackup
+type ClusterBackupMode string
+
+const (
+	ClusterBackupModeFull   ClusterBackupMode = "FULL"
+	ClusterBackupModePTrack ClusterBackupMode = "PTRACK"
+)
+
+// LabelClusterBackupSchedule is the name of the scheduled ClusterBackup which creates the ClusterBackup
+const LabelClusterBackupSchedule = "shardingsphere.apache.org/cluster-backup-schedule"
+
+// +kubebuilder:printcolumn:JSONPath=".spec.computeNodeName",name=ComputeNode,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.mode",name=Mode,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.schedule",name=Schedule,type=string,priority=1
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name=Phase,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.csn",name=CSN,type=string,priority=1
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=Age,type=date
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// ClusterBackup is a consistent backup of the openGauss storage nodes of a ShardingSphere cluster.
+// The cluster is locked while the backups of the storage nodes are started by the PITR agents,
+// and the metadata of the cluster is kept with the backup.
+type ClusterBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterBackupSpec `json:"spec,omitempty"`
+	// +optional
+	Status ClusterBackupStatus `json:"status,omitempty"`
+}
+
+// ClusterBackupSpec defines the ComputeNode to back up and the backup options of the agents
+type ClusterBackupSpec struct {
+	// ComputeNodeName is the name of the ComputeNode in the same namespace
+	// +kubebuilder:validation:Required
+	ComputeNodeName string `json:"computeNodeName"`
+	// Mode is the backup mode of the storage nodes, PTRACK backs up the pages changed since the last backup
+	// +kubebuilder:validation:Enum=FULL;PTRACK
+	// +kubebuilder:default:=FULL
+	// +optional
+	Mode ClusterBackupMode `json:"mode,omitempty"`
+	// BackupPath is the backup directory of gs_probackup on the storage nodes
+	// +kubebuilder:validation:Required
+	BackupPath string `json:"backupPath"`
+	// Instance is the backup instance of gs_probackup
+	// +kubebuilder:default:=ins-default-ss
+	// +optional
+	Instance string `json:"instance,omitempty"`
+	// ThreadsNum is the number of threads of gs_probackup
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=1
+	// +optional
+	ThreadsNum int32 `json:"threadsNum,omitempty"`
+	// AgentPort is the port of the PITR agents on the storage nodes
+	// +kubebuilder:default:=443
+	// +optional
+	AgentPort int32 `json:"agentPort,omitempty"`
+	// AgentSecretName is the Secret in the same namespace with the credentials of the PITR agents:
+	// the bearer token in `token`, or the client certificate in `tls.crt` and `tls.key` with the role in its OU,
+	// and the CA verifying the certificates of the agents in `ca.crt`. The role must be admin to restore.
+	// +optional
+	AgentSecretName string `json:"agentSecretName,omitempty"`
+	// Schedule is a cron expression, e.g. `0 2 * * *`. If it is set, the ClusterBackup does not back up the cluster
+	// itself, but creates a ClusterBackup with the same spec on the schedule.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// StartingDeadlineSeconds is the deadline in seconds to create the ClusterBackup of a missed schedule,
+	// the missed schedule is skipped after the deadline. Only the latest missed schedule is created.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// BackupsHistoryLimit is the number of the finished ClusterBackups created on the schedule to keep,
+	// all of them are kept if it is 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackupsHistoryLimit int32 `json:"backupsHistoryLimit,omitempty"`
+}
+
+// ClusterBackupStatus defines the observed state of ClusterBackup
+type ClusterBackupStatus struct {
+	// Phase is a brief summary of the backup life cycle
+	// +optional
+	Phase ClusterBackupPhase `json:"phase,omitempty"`
+	// Message is the reason of the Pending or Failed phase
+	// +optional
+	Message string `json:"message,omitempty"`
+	// CSN is the commit sequence number locked when the backup is taken
+	// +optional
+	CSN string `json:"csn,omitempty"`
+	// Databases are the logic databases in the metadata of the backup
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+	// MetadataSecretName is the Secret owned by the ClusterBackup holding the exported metadata and storage nodes,
+	// which contain the passwords of the storage nodes.
+	// +optional
+	MetadataSecretName string `json:"metadataSecretName,omitempty"`
+	// DataNodes are the backups of the storage nodes
+	// +optional
+	DataNodes []ClusterBackupDataNode `json:"dataNodes,omitempty"`
+	// StartTime is the time the cluster is locked for the backup
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time all the storage nodes are backed up
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// LastScheduleTime is the last time a ClusterBackup is created on the schedule
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+}
+
+// ClusterBackupDataNode is the backup of a storage node taken by the agent on it
+type ClusterBackupDataNode struct {
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+	// BackupID is the backup id of gs_probackup
+	BackupID string `json:"backupID"`
+	// Status is the backup status reported by the agent, e.g. Running, Completed or Failed
+	// +optional
+	Status string `json:"status,omitempty"`
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterBackupList contains a list of ClusterBackup
+type ClusterBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterBackup{}, &ClusterBackupList{})
+}
@@ -0,0 +1,119 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ClusterRestorePhase string
+
+const (
+	// ClusterRestorePhasePending means the restore is waiting for the backup to be completed and the agents to be available
+	ClusterRestorePhasePending ClusterRestorePhase = "Pending"
+	// ClusterRestorePhaseRestoring means the storage nodes are being restored by the agents
+	ClusterRestorePhaseRestoring ClusterRestorePhase = "Restoring"
+	// ClusterRestorePhaseImportingMetadata means the logic databases are being replaced with the metadata of the backup
+	ClusterRestorePhaseImportingMetadata ClusterRestorePhase = "ImportingMetadata"
+	// ClusterRestorePhaseCompleted means the cluster is restored
+	ClusterRestorePhaseCompleted ClusterRestorePhase = "Completed"
+	// ClusterRestorePhaseFailed means the cluster can not be restored
+	ClusterRestorePhaseFailed ClusterRestorePhase = "Failed"
+)
+
+// +kubebuilder:printcolumn:JSONPath=".spec.backupName",name=Backup,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name=Phase,type=string
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=Age,type=date
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// ClusterRestore restores a ShardingSphere cluster with a ClusterBackup. The storage nodes are restored by the PITR agents,
+// then the logic databases of the cluster are dropped and replaced with the metadata of the backup.
+type ClusterRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterRestoreSpec `json:"spec,omitempty"`
+	// +optional
+	Status ClusterRestoreStatus `json:"status,omitempty"`
+}
+
+// ClusterRestoreSpec defines the backup to restore
+type ClusterRestoreSpec struct {
+	// BackupName is the name of the completed ClusterBackup in the same namespace
+	// +kubebuilder:validation:Required
+	BackupName string `json:"backupName"`
+	// ComputeNodeName is the ComputeNode to import the metadata into, it defaults to the one of the backup
+	// +optional
+	ComputeNodeName string `json:"computeNodeName,omitempty"`
+	// ThreadsNum is the number of threads of gs_probackup
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=1
+	// +optional
+	ThreadsNum int32 `json:"threadsNum,omitempty"`
+}
+
+// ClusterRestoreStatus defines the observed state of ClusterRestore
+type ClusterRestoreStatus struct {
+	// Phase is a brief summary of the restore life cycle
+	// +optional
+	Phase ClusterRestorePhase `json:"phase,omitempty"`
+	// Message is the reason of the Pending or Failed phase
+	// +optional
+	Message string `json:"message,omitempty"`
+	// DataNodes are the restores of the storage nodes
+	// +optional
+	DataNodes []ClusterRestoreDataNode `json:"dataNodes,omitempty"`
+	// DroppedDatabases are the logic databases dropped before the metadata is imported
+	// +optional
+	DroppedDatabases []string `json:"droppedDatabases,omitempty"`
+	// StartTime is the time the storage nodes start being restored
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the cluster is restored
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// ClusterRestoreDataNode is the restore of a storage node by the agent on it
+type ClusterRestoreDataNode struct {
+	Host string `json:"host"`
+	Port int32  `json:"port"`
+	// JobID is the id of the restore job of the agent, it is polled until the job is done
+	// +optional
+	JobID string `json:"jobID,omitempty"`
+	// Status is Running, Completed or Failed
+	// +optional
+	Status string `json:"status,omitempty"`
+	// Message is the error of the restore job
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterRestoreList contains a list of ClusterRestore
+type ClusterRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterRestore{}, &ClusterRestoreList{})
+}
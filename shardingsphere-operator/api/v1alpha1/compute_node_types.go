@@ -19,7 +19,9 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +kubebuilder:object:root=true
@@ -64,8 +66,14 @@ type ComputeNodePrivilege struct {
 // user:<username>@<hostname>,hostname is % or empty string means do not care about authorized host
 // password:<password>
 type ComputeNodeUser struct {
-	User     string `json:"user" yaml:"user"`
-	Password string `json:"password" yaml:"password"`
+	User string `json:"user" yaml:"user"`
+	// Password in plain text, a password is generated and kept in the Secret <name>-authority
+	// if neither Password nor PasswordSecretRef is set
+	// +optional
+	Password string `json:"password,omitempty" yaml:"password"`
+	// PasswordSecretRef refers to the password in a Secret of the same namespace
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty" yaml:"-"`
 }
 
 // ComputeNodeAuthority  is used to set up initial user to login compute node, and authority data of storage node.
@@ -84,6 +92,7 @@ const (
 )
 
 // Repository is the metadata persistent store for ShardingSphere
+// +kubebuilder:validation:XValidation:rule="self.type != 'Etcd' || !has(self.managed) || !has(oldSelf.managed) || self.managed.replicas == oldSelf.managed.replicas",message="the replicas of the managed Etcd can not be changed"
 type Repository struct {
 	// +kubebuilder:validation:Enum=ZooKeeper;Etcd
 	// type of metadata repository
@@ -91,6 +100,30 @@ type Repository struct {
 	// properties of metadata repository
 	// +optional
 	Props Properties `json:"props,omitempty" yaml:"props,omitempty"`
+	// Managed provisions the metadata repository by the operator,
+	// and its endpoints are filled in the server-lists property
+	// +optional
+	Managed *ManagedRepository `json:"managed,omitempty" yaml:"-"`
+}
+
+// ManagedRepository is a ZooKeeper or Etcd cluster provisioned by the operator for the ComputeNode
+type ManagedRepository struct {
+	// Replicas is the number of the members, an odd number is recommended.
+	// The members of Etcd can not be changed after the cluster is bootstrapped
+	// +kubebuilder:default=3
+	// +optional
+	Replicas int32 `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	// Image defaults to zookeeper:3.8.1 for ZooKeeper and quay.io/coreos/etcd:v3.4.27 for Etcd,
+	// an image of Etcd must have sh and etcdctl to rejoin a member which lost its data
+	// +optional
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// Storage is the size of the persistent volume of every member, it defaults to 1Gi
+	// +optional
+	Storage *resource.Quantity `json:"storage,omitempty" yaml:"storage,omitempty"`
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty" yaml:"storageClassName,omitempty"`
 }
 
 type ModeType string
@@ -285,6 +318,33 @@ type ComputeNodeSpec struct {
 
 	// +optional
 	Bootstrap BootstrapConfig `json:"bootstrap,omitempty" yaml:"bootstrap,omitempty"`
+
+	// rollingUpdate controls how the pods are rolled when the spec, the configuration or the referenced Secrets change
+	// +optional
+	RollingUpdate *ComputeNodeRollingUpdate `json:"rollingUpdate,omitempty" yaml:"rollingUpdate,omitempty"`
+
+	// tls enables the SSL of the ShardingSphere-Proxy frontend
+	// +optional
+	TLS *ComputeNodeTLS `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// ComputeNodeTLS defines the SSL props of the ShardingSphere-Proxy frontend. The proxy serves
+// a self-signed certificate generated at startup, a certificate can not be provided to it
+type ComputeNodeTLS struct {
+	// versions are the enabled TLS protocol versions, e.g. TLSv1.2 and TLSv1.3
+	// +optional
+	Versions []string `json:"versions,omitempty" yaml:"versions,omitempty"`
+	// ciphers are the enabled cipher suites
+	// +optional
+	Ciphers []string `json:"ciphers,omitempty" yaml:"ciphers,omitempty"`
+}
+
+// ComputeNodeRollingUpdate defines the rolling update of ShardingSphere-Proxy pods
+type ComputeNodeRollingUpdate struct {
+	// maxUnavailable is the maximum number or percentage of the pods that can be unavailable during the update.
+	// Defaults to 0, which means no pod is stopped until its replacement is ready.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty" yaml:"maxUnavailable,omitempty"`
 }
 
 // ComputeNodeStatus defines the observed state of ShardingSphere Proxy
@@ -313,6 +373,41 @@ type ComputeNodeStatus struct {
 	// if one is present.
 	// +optional
 	LoadBalancer LoadBalancerStatus `json:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty"`
+
+	// Proxies are the results of the SQL-level probes of the ready ShardingSphere-Proxy pods
+	// +optional
+	Proxies []ProxyInstanceStatus `json:"proxies,omitempty" yaml:"proxies,omitempty"`
+
+	// LogicDatabases are the logic databases registered in ShardingSphere-Proxy and their storage units
+	// +optional
+	LogicDatabases []LogicDatabaseStatus `json:"logicDatabases,omitempty" yaml:"logicDatabases,omitempty"`
+}
+
+// ProxyInstanceStatus is the result of the SQL-level probe of a ShardingSphere-Proxy pod
+type ProxyInstanceStatus struct {
+	PodName string `json:"podName" yaml:"podName"`
+	// +optional
+	PodIP string `json:"podIP,omitempty" yaml:"podIP,omitempty"`
+	// InstanceID is the id of the proxy instance returned by `SHOW COMPUTE NODES`
+	// +optional
+	InstanceID string `json:"instanceID,omitempty" yaml:"instanceID,omitempty"`
+	// State is the state of the proxy instance returned by `SHOW COMPUTE NODES`, e.g. OK and CIRCUIT_BREAK
+	// +optional
+	State string `json:"state,omitempty" yaml:"state,omitempty"`
+
+	RegistryConnected   bool `json:"registryConnected" yaml:"registryConnected"`
+	StorageUnitsHealthy bool `json:"storageUnitsHealthy" yaml:"storageUnitsHealthy"`
+
+	// Message is the reason why the probe failed
+	// +optional
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// LogicDatabaseStatus is a logic database registered in ShardingSphere-Proxy
+type LogicDatabaseStatus struct {
+	Name string `json:"name" yaml:"name"`
+	// +optional
+	StorageUnits []string `json:"storageUnits,omitempty" yaml:"storageUnits,omitempty"`
 }
 
 // LoadBalancerStatus represents the status of service endpoints
@@ -358,6 +453,14 @@ const (
 	ComputeNodeConditionFailed ComputeNodeConditionType = "Failed"
 	// ComputeNodeConditionInitialized indicates that at least one pod is succeed
 	ComputeNodeConditionSucceed ComputeNodeConditionType = "Succeed"
+	// ComputeNodeConditionUpdating indicates that the pods are being rolled to the latest spec and configuration
+	ComputeNodeConditionUpdating ComputeNodeConditionType = "Updating"
+	// ComputeNodeConditionRegistryConnected indicates that all the ready pods are connected to the governance center
+	ComputeNodeConditionRegistryConnected ComputeNodeConditionType = "RegistryConnected"
+	// ComputeNodeConditionStorageUnitsHealthy indicates that all the ready pods serve and reach the same storage units of the logic databases
+	ComputeNodeConditionStorageUnitsHealthy ComputeNodeConditionType = "StorageUnitsHealthy"
+	// ComputeNodeConditionGovernanceReady indicates that the managed metadata repository reaches quorum
+	ComputeNodeConditionGovernanceReady ComputeNodeConditionType = "GovernanceReady"
 )
 
 // ConditionStatus represents the validation status of a condition
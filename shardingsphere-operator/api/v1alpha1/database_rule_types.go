@@ -0,0 +1,253 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DatabaseRulePhaseStatus string
+
+const (
+	DatabaseRulePhasePending  DatabaseRulePhaseStatus = "Pending"
+	DatabaseRulePhaseSynced   DatabaseRulePhaseStatus = "Synced"
+	DatabaseRulePhaseFailed   DatabaseRulePhaseStatus = "Failed"
+	DatabaseRulePhaseDeleting DatabaseRulePhaseStatus = "Deleting"
+)
+
+type DatabaseRuleConditionType string
+
+// DatabaseRuleConditionType shows the sync states between the DatabaseRule and the logic database.
+const (
+	// DatabaseRuleConditionTypeSynced means all the rules in spec are applied to the logic database.
+	DatabaseRuleConditionTypeSynced DatabaseRuleConditionType = "Synced"
+	// DatabaseRuleConditionTypeDrifted means some managed rules were changed or removed outside of the DatabaseRule,
+	// and have been applied again.
+	DatabaseRuleConditionTypeDrifted DatabaseRuleConditionType = "Drifted"
+)
+
+type DatabaseRuleConditions []*DatabaseRuleCondition
+
+// DatabaseRuleCondition contains details for the current condition of this DatabaseRule.
+type DatabaseRuleCondition struct {
+	Type           DatabaseRuleConditionType `json:"type"`
+	Status         corev1.ConditionStatus    `json:"status"`
+	LastUpdateTime metav1.Time               `json:"lastUpdateTime,omitempty"`
+	Reason         string                    `json:"reason"`
+	Message        string                    `json:"message"`
+}
+
+// AlgorithmSpec defines a ShardingSphere algorithm with its type name and properties.
+type AlgorithmSpec struct {
+	// Type is the algorithm type name, like 'MOD', 'AES', 'MASK_FROM_X_TO_Y'
+	Type string `json:"type"`
+	// +optional
+	Props map[string]string `json:"props,omitempty"`
+}
+
+// ShardingStrategySpec defines the database or table sharding strategy of a sharding table rule.
+type ShardingStrategySpec struct {
+	// +kubebuilder:validation:Enum=standard;complex;hint;none
+	// +kubebuilder:default=standard
+	Type string `json:"type"`
+	// ShardingColumns is a single column for standard strategy, or several columns for complex strategy.
+	// +optional
+	ShardingColumns []string `json:"shardingColumns,omitempty"`
+	// +optional
+	ShardingAlgorithm *AlgorithmSpec `json:"shardingAlgorithm,omitempty"`
+}
+
+// KeyGenerateStrategySpec defines how to generate the key of a sharding table.
+type KeyGenerateStrategySpec struct {
+	Column       string        `json:"column"`
+	KeyGenerator AlgorithmSpec `json:"keyGenerator"`
+}
+
+// ShardingTableRuleSpec defines a sharding table rule.
+// An auto table rule is defined with StorageUnits, ShardingColumn and ShardingAlgorithm,
+// while a standard table rule is defined with DataNodes, DatabaseStrategy and TableStrategy.
+type ShardingTableRuleSpec struct {
+	Table string `json:"table"`
+	// +optional
+	StorageUnits []string `json:"storageUnits,omitempty"`
+	// +optional
+	ShardingColumn string `json:"shardingColumn,omitempty"`
+	// +optional
+	ShardingAlgorithm *AlgorithmSpec `json:"shardingAlgorithm,omitempty"`
+	// +optional
+	DataNodes []string `json:"dataNodes,omitempty"`
+	// +optional
+	DatabaseStrategy *ShardingStrategySpec `json:"databaseStrategy,omitempty"`
+	// +optional
+	TableStrategy *ShardingStrategySpec `json:"tableStrategy,omitempty"`
+	// +optional
+	KeyGenerateStrategy *KeyGenerateStrategySpec `json:"keyGenerateStrategy,omitempty"`
+}
+
+// EncryptColumnSpec defines how a logic column is encrypted.
+type EncryptColumnSpec struct {
+	Name   string `json:"name"`
+	Cipher string `json:"cipher"`
+	// +optional
+	Plain string `json:"plain,omitempty"`
+	// +optional
+	AssistedQuery string `json:"assistedQuery,omitempty"`
+	// +optional
+	LikeQuery        string        `json:"likeQuery,omitempty"`
+	EncryptAlgorithm AlgorithmSpec `json:"encryptAlgorithm"`
+	// +optional
+	AssistedQueryAlgorithm *AlgorithmSpec `json:"assistedQueryAlgorithm,omitempty"`
+	// +optional
+	LikeQueryAlgorithm *AlgorithmSpec `json:"likeQueryAlgorithm,omitempty"`
+}
+
+// EncryptRuleSpec defines an encrypt rule of a table.
+type EncryptRuleSpec struct {
+	Table   string              `json:"table"`
+	Columns []EncryptColumnSpec `json:"columns"`
+	// +optional
+	QueryWithCipherColumn *bool `json:"queryWithCipherColumn,omitempty"`
+}
+
+// MaskColumnSpec defines how a column is masked.
+type MaskColumnSpec struct {
+	Name      string        `json:"name"`
+	Algorithm AlgorithmSpec `json:"algorithm"`
+}
+
+// MaskRuleSpec defines a mask rule of a table.
+type MaskRuleSpec struct {
+	Table   string           `json:"table"`
+	Columns []MaskColumnSpec `json:"columns"`
+}
+
+// ShadowTableSpec defines the shadow algorithms of a table.
+type ShadowTableSpec struct {
+	Name       string          `json:"name"`
+	Algorithms []AlgorithmSpec `json:"algorithms"`
+}
+
+// ShadowRuleSpec defines a shadow rule between a source storage unit and a shadow storage unit.
+type ShadowRuleSpec struct {
+	Name   string            `json:"name"`
+	Source string            `json:"source"`
+	Shadow string            `json:"shadow"`
+	Tables []ShadowTableSpec `json:"tables"`
+}
+
+// ReadwriteSplittingRuleSpec defines a readwrite-splitting rule.
+type ReadwriteSplittingRuleSpec struct {
+	Name             string   `json:"name"`
+	WriteStorageUnit string   `json:"writeStorageUnit"`
+	ReadStorageUnits []string `json:"readStorageUnits"`
+	// +kubebuilder:validation:Enum=PRIMARY;FIXED;DYNAMIC
+	// +optional
+	TransactionalReadQueryStrategy string `json:"transactionalReadQueryStrategy,omitempty"`
+	// +optional
+	LoadBalancer *AlgorithmSpec `json:"loadBalancer,omitempty"`
+}
+
+// DatabaseRuleSpec defines the desired rules of a logic database
+type DatabaseRuleSpec struct {
+	// ComputeNodeName is the name of the ComputeNode which serves the logic database
+	// +kubebuilder:validation:Required
+	ComputeNodeName string `json:"computeNodeName"`
+	// DatabaseName is the name of the logic database
+	// +kubebuilder:validation:Required
+	DatabaseName string `json:"databaseName"`
+
+	// +optional
+	Sharding []ShardingTableRuleSpec `json:"sharding,omitempty"`
+	// +optional
+	Encrypt []EncryptRuleSpec `json:"encrypt,omitempty"`
+	// +optional
+	Mask []MaskRuleSpec `json:"mask,omitempty"`
+	// +optional
+	Shadow []ShadowRuleSpec `json:"shadow,omitempty"`
+	// +optional
+	ReadwriteSplitting []ReadwriteSplittingRuleSpec `json:"readwriteSplitting,omitempty"`
+}
+
+// AppliedRule is a rule which has been applied to the logic database by the DatabaseRule
+type AppliedRule struct {
+	// Type is the rule type, one of sharding, encrypt, mask, shadow and readwrite_splitting
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Hash is the hash of the DistSQL used to create the rule
+	Hash string `json:"hash"`
+}
+
+// DatabaseRuleStatus defines the observed state of DatabaseRule
+type DatabaseRuleStatus struct {
+	// The generation observed by the DatabaseRule controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase is a brief summary of the DatabaseRule sync state
+	// +optional
+	Phase DatabaseRulePhaseStatus `json:"phase"`
+
+	// Conditions The conditions array, the reason and message fields
+	// +optional
+	Conditions DatabaseRuleConditions `json:"conditions"`
+
+	// Rules are the rules managed by this DatabaseRule
+	// +optional
+	Rules []AppliedRule `json:"rules,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DatabaseRuleList contains a list of DatabaseRule
+type DatabaseRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseRule `json:"items"`
+}
+
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name=Phase,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.computeNodeName",name=ComputeNode,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.databaseName",name=Database,type=string
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=Age,type=date
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// DatabaseRule is the Schema for the rules of a ShardingSphere logic database
+type DatabaseRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DatabaseRuleSpec `json:"spec,omitempty"`
+	// +optional
+	Status DatabaseRuleStatus `json:"status,omitempty"`
+}
+
+// UpsertCondition updates the given condition in the DatabaseRuleConditions.
+func (c *DatabaseRuleConditions) UpsertCondition(condition *DatabaseRuleCondition) {
+	for i, existing := range *c {
+		if existing.Type == condition.Type {
+			(*c)[i] = condition
+			return
+		}
+	}
+	*c = append(*c, condition)
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseRule{}, &DatabaseRuleList{})
+}
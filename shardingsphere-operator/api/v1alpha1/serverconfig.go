@@ -17,13 +17,21 @@
 
 package v1alpha1
 
+import corev1 "k8s.io/api/core/v1"
+
 // User is a slice about authorized host and password for compute node.
 // Format:
 // user:<username>@<hostname>,hostname is % or empty string means do not care about authorized host
 // password:<password>
 type User struct {
-	User     string `json:"user" yaml:"user"`
-	Password string `json:"password" yaml:"password"`
+	User string `json:"user" yaml:"user"`
+	// Password in plain text, a password is generated and kept in the Secret <name>-authority
+	// if neither Password nor PasswordSecretRef is set
+	// +optional
+	Password string `json:"password,omitempty" yaml:"password"`
+	// PasswordSecretRef refers to the password in a Secret of the same namespace
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty" yaml:"-"`
 }
 
 // Privilege for storage node, the default value is ALL_PRIVILEGES_PERMITTED
@@ -88,7 +96,7 @@ type ClusterProps struct {
 
 type RepositoryConfig struct {
 
-	// +kubebuilder:validation:Enum=ZooKeeper
+	// +kubebuilder:validation:Enum=ZooKeeper;Etcd
 
 	//Type of persist repository
 	Type string `json:"type" yaml:"type"`
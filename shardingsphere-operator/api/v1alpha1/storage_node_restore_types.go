@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type StorageNodeRestorePhase string
+
+const (
+	// StorageNodeRestorePhasePending means the restore is waiting for the snapshot to be ready
+	StorageNodeRestorePhasePending StorageNodeRestorePhase = "Pending"
+	// StorageNodeRestorePhaseRestoring means the StorageNode is being restored from the snapshot
+	StorageNodeRestorePhaseRestoring StorageNodeRestorePhase = "Restoring"
+	// StorageNodeRestorePhaseCompleted means the StorageNode is restored and ready
+	StorageNodeRestorePhaseCompleted StorageNodeRestorePhase = "Completed"
+	// StorageNodeRestorePhaseFailed means the StorageNode can not be restored
+	StorageNodeRestorePhaseFailed StorageNodeRestorePhase = "Failed"
+)
+
+// +kubebuilder:printcolumn:JSONPath=".spec.snapshotName",name=Snapshot,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.storageNodeName",name=StorageNode,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name=Phase,type=string
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=Age,type=date
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// StorageNodeRestore restores a StorageNodeSnapshot into a new StorageNode
+type StorageNodeRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StorageNodeRestoreSpec `json:"spec,omitempty"`
+	// +optional
+	Status StorageNodeRestoreStatus `json:"status,omitempty"`
+}
+
+// StorageNodeRestoreSpec defines the snapshot to restore and the StorageNode to restore it into
+type StorageNodeRestoreSpec struct {
+	// SnapshotName is the name of the StorageNodeSnapshot in the same namespace
+	// +kubebuilder:validation:Required
+	SnapshotName string `json:"snapshotName"`
+	// StorageNodeName is the name of the new StorageNode, it defaults to the name of the StorageNodeRestore.
+	// The StorageNode must not exist.
+	// +optional
+	StorageNodeName string `json:"storageNodeName,omitempty"`
+	// StorageProviderName is the StorageProvider of the new StorageNode, it defaults to the one of the snapshot.
+	// The provisioner must be the same as the one of the snapshot.
+	// +optional
+	StorageProviderName string `json:"storageProviderName,omitempty"`
+	// RegisterStorageUnit registers the new StorageNode under the name of the storage unit of the snapshot,
+	// the storage unit is altered to the new StorageNode if it exists, and the source StorageNode stops managing it.
+	// +optional
+	RegisterStorageUnit bool `json:"registerStorageUnit,omitempty"`
+}
+
+// StorageNodeRestoreStatus defines the observed state of StorageNodeRestore
+type StorageNodeRestoreStatus struct {
+	// Phase is a brief summary of the restore life cycle
+	// +optional
+	Phase StorageNodeRestorePhase `json:"phase,omitempty"`
+	// Message is the reason of the Pending or Failed phase
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StorageNodeName is the name of the StorageNode created by the restore
+	// +optional
+	StorageNodeName string `json:"storageNodeName,omitempty"`
+	// CompletionTime is the time the StorageNode is restored and ready
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StorageNodeRestoreList contains a list of StorageNodeRestore
+type StorageNodeRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StorageNodeRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StorageNodeRestore{}, &StorageNodeRestoreList{})
+}
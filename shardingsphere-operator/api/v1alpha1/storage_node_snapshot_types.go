@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type StorageNodeSnapshotPhase string
+
+const (
+	// StorageNodeSnapshotPhasePending means the snapshot is waiting for the storage node to be ready
+	StorageNodeSnapshotPhasePending StorageNodeSnapshotPhase = "Pending"
+	// StorageNodeSnapshotPhaseCreating means the snapshot is being taken by the provider
+	StorageNodeSnapshotPhaseCreating StorageNodeSnapshotPhase = "Creating"
+	// StorageNodeSnapshotPhaseReady means the snapshot can be restored
+	StorageNodeSnapshotPhaseReady StorageNodeSnapshotPhase = "Ready"
+	// StorageNodeSnapshotPhaseFailed means the snapshot can not be taken
+	StorageNodeSnapshotPhaseFailed StorageNodeSnapshotPhase = "Failed"
+)
+
+// SnapshotDeletionPolicy decides what happens to the snapshot in the provider when the StorageNodeSnapshot is deleted
+type SnapshotDeletionPolicy string
+
+const (
+	// SnapshotDeletionPolicyDelete deletes the snapshot in the provider with the StorageNodeSnapshot
+	SnapshotDeletionPolicyDelete SnapshotDeletionPolicy = "Delete"
+	// SnapshotDeletionPolicyRetain keeps the snapshot in the provider, it is the default policy
+	SnapshotDeletionPolicyRetain SnapshotDeletionPolicy = "Retain"
+)
+
+// +kubebuilder:printcolumn:JSONPath=".spec.storageNodeName",name=StorageNode,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name=Phase,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.snapshotIdentifier",name=Identifier,type=string,priority=1
+// +kubebuilder:printcolumn:JSONPath=".status.creationTime",name=CreationTime,type=date,priority=1
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=Age,type=date
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// StorageNodeSnapshot is an on-demand snapshot of the databases of a StorageNode
+type StorageNodeSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StorageNodeSnapshotSpec `json:"spec,omitempty"`
+	// +optional
+	Status StorageNodeSnapshotStatus `json:"status,omitempty"`
+}
+
+// StorageNodeSnapshotSpec defines the StorageNode to take the snapshot of
+type StorageNodeSnapshotSpec struct {
+	// StorageNodeName is the name of the StorageNode in the same namespace
+	// +kubebuilder:validation:Required
+	StorageNodeName string `json:"storageNodeName"`
+	// SnapshotIdentifier is the identifier of the snapshot in the provider, e.g. the DB snapshot identifier of aws
+	// or the name of the Backup of CloudNativePG. It defaults to the namespace, the name and a fragment of the uid
+	// of the StorageNodeSnapshot joined by hyphens, e.g. default-snapshot-1a2b3c4d.
+	// +optional
+	SnapshotIdentifier string `json:"snapshotIdentifier,omitempty"`
+	// DeletionPolicy decides whether the snapshot in the provider is deleted with the StorageNodeSnapshot
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default:=Retain
+	// +optional
+	DeletionPolicy SnapshotDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// StorageNodeSnapshotStatus defines the observed state of StorageNodeSnapshot
+type StorageNodeSnapshotStatus struct {
+	// Phase is a brief summary of the snapshot life cycle
+	// +optional
+	Phase StorageNodeSnapshotPhase `json:"phase,omitempty"`
+	// Message is the reason of the Pending or Failed phase
+	// +optional
+	Message string `json:"message,omitempty"`
+	// SnapshotIdentifier is the identifier of the snapshot in the provider, it is set once the snapshot is started
+	// +optional
+	SnapshotIdentifier string `json:"snapshotIdentifier,omitempty"`
+	// StorageProviderName is the StorageProvider of the StorageNode when the snapshot is taken
+	// +optional
+	StorageProviderName string `json:"storageProviderName,omitempty"`
+	// Source is the StorageNode when the snapshot is taken, which is restored with the snapshot
+	// +optional
+	Source *StorageNodeSnapshotSource `json:"source,omitempty"`
+	// CredentialsSecretRef references the copy of the master user when the snapshot is taken,
+	// the Secret is owned by the StorageNodeSnapshot.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+	// CreationTime is the time the snapshot is taken by the provider
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+	// AllocatedStorage is the storage of the snapshot in GiB if the provider reports it
+	// +optional
+	AllocatedStorage int32 `json:"allocatedStorage,omitempty"`
+}
+
+// StorageNodeSnapshotSource is the StorageNode when the snapshot is taken
+type StorageNodeSnapshotSource struct {
+	// Spec is the spec of the StorageNode
+	Spec StorageNodeSpec `json:"spec"`
+	// DatabaseName is the annotation `storageproviders.shardingsphere.apache.org/instance-db-name` of the StorageNode
+	// +optional
+	DatabaseName string `json:"databaseName,omitempty"`
+	// StorageUnit is the storage unit registered for the StorageNode, it is nil if the StorageNode is not registered
+	// +optional
+	StorageUnit *SnapshotStorageUnit `json:"storageUnit,omitempty"`
+}
+
+// SnapshotStorageUnit is the storage unit of a ShardingSphere logic database
+type SnapshotStorageUnit struct {
+	Name              string `json:"name"`
+	LogicDatabaseName string `json:"logicDatabaseName"`
+	ComputeNodeName   string `json:"computeNodeName"`
+}
+
+// +kubebuilder:object:root=true
+
+// StorageNodeSnapshotList contains a list of StorageNodeSnapshot
+type StorageNodeSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StorageNodeSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StorageNodeSnapshot{}, &StorageNodeSnapshotList{})
+}
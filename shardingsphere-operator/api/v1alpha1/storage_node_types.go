@@ -29,6 +29,14 @@ const (
 	StorageNodePhaseNotReady       StorageNodePhaseStatus = "NotReady"
 	StorageNodePhaseDeleting       StorageNodePhaseStatus = "Deleting"
 	StorageNodePhaseDeleteComplete StorageNodePhaseStatus = "DeleteComplete"
+	StorageNodePhaseResizing       StorageNodePhaseStatus = "Resizing"
+)
+
+const (
+	// StorageNodeCredentialsUsernameKey is the key of the master username in the credentials Secret of a StorageNode
+	StorageNodeCredentialsUsernameKey = "username"
+	// StorageNodeCredentialsPasswordKey is the key of the master password in the credentials Secret of a StorageNode
+	StorageNodeCredentialsPasswordKey = "password"
 )
 
 type StorageNodeConditionType string
@@ -41,6 +49,8 @@ const (
 	StorageNodeConditionTypeClusterReady StorageNodeConditionType = "ClusterReady"
 	// StorageNodeConditionTypeRegistered means the storage node is registered to the cluster.
 	StorageNodeConditionTypeRegistered StorageNodeConditionType = "Registered"
+	// StorageNodeConditionTypeResizing means the database is being resized to the resources in the spec.
+	StorageNodeConditionTypeResizing StorageNodeConditionType = "Resizing"
 )
 
 type StorageNodeConditions []*StorageNodeCondition
@@ -55,7 +65,8 @@ type StorageNodeCondition struct {
 }
 
 // ClusterStatus is the status of a database cluster, including the primary endpoint, reader endpoints, and other properties.
-// Properties are some additional information about the cluster, like 'arn, identifier, credentials, etc.'
+// Properties are some additional information about the cluster, like 'arn, identifier, etc.'
+// Credentials must never be put in the properties, they are kept in the Secret referenced by the StorageNode.
 type ClusterStatus struct {
 	Status          string   `json:"status"`
 	PrimaryEndpoint Endpoint `json:"primaryEndpoint"`
@@ -65,10 +76,6 @@ type ClusterStatus struct {
 	Properties map[string]string `json:"properties"`
 }
 
-type CredentialType struct {
-	BasicCredential `json:"basic_credential"`
-}
-
 type InstanceStatus struct {
 	Status   string   `json:"status"`
 	Endpoint Endpoint `json:"primaryEndpoint"`
@@ -76,11 +83,6 @@ type InstanceStatus struct {
 	Properties map[string]string `json:"properties"`
 }
 
-type BasicCredential struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-}
-
 type Endpoint struct {
 	Address string `json:"address"`
 	Port    int32  `json:"port"`
@@ -122,11 +124,36 @@ type StorageNodeSpec struct {
 	// if not set, will NOT create database
 	Schema string `json:"schema"`
 	// +optional
-	// Only for aws aurora storage provider right now. And the default value is 1.
+	// Only for aws aurora and cloudnative-pg storage providers right now. And the default value is 1.
 	// aws rds instance is always 1.
 	// aws rds cluster will auto create 3 instances(1 primary and 2 replicas).
+	// Changing it adds or removes the reader instances of aws aurora.
+	// Changing it scales the instances of cloudnative-pg, overriding the parameter `instances` of the storage provider.
 	// +kubebuilder:default=1
 	Replicas int32 `json:"replicas"`
+	// InstanceClass is the compute and memory capacity of the instances, e.g. db.t3.micro.
+	// It overrides the parameter `instanceClass` of the storage provider, and changing it resizes the instances.
+	// Only for aws storage providers.
+	// +optional
+	InstanceClass string `json:"instanceClass,omitempty"`
+	// AllocatedStorage is the storage of the instances in GiB.
+	// It overrides the parameter `allocatedStorage` of aws or `storage.size` of CloudNativePG,
+	// and changing it expands the storage, the storage can not be shrunk.
+	// Not for aws aurora, whose storage grows automatically.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	AllocatedStorage int32 `json:"allocatedStorage,omitempty"`
+	// IOPS is the provisioned IOPS of the storage.
+	// It overrides the parameter `iops` of the storage provider, and changing it modifies the storage.
+	// Only for aws rds instance and aws rds cluster.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IOPS int32 `json:"iops,omitempty"`
+	// CredentialsSecretRef references the Secret in the namespace of the StorageNode holding the master user,
+	// the password is read from the key `password`, and the username from the key `username` if it exists.
+	// If not set, a Secret with a generated password is created and owned by the StorageNode.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
 }
 
 // StorageNodeStatus defines the actual state of a set of storage units
@@ -155,6 +182,14 @@ type StorageNodeStatus struct {
 	// Registered indicates whether the StorageNode has been registered to shardingsphere
 	// +optional
 	Registered bool `json:"registered,omitempty"`
+
+	// ReaderStorageUnits are the names of the storage units registered for the reader endpoints of the cluster
+	// +optional
+	ReaderStorageUnits []string `json:"readerStorageUnits,omitempty"`
+
+	// CredentialsSecretRef references the Secret holding the master user of the databases in use
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
 }
 
 // AddCondition adds the given condition to the StorageNodeConditions.
@@ -40,6 +40,8 @@ const (
 	ProvisionerAWSRDSCluster  = "storageproviders.shardingsphere.apache.org/aws-rds-cluster"
 	ProvisionerAWSAurora      = "storageproviders.shardingsphere.apache.org/aws-aurora"
 	ProvisionerCloudNativePG  = "storageproviders.shardingsphere.apache.org/cloud-native-pg"
+	// ProvisionerLocalStatefulSet provisions a single MySQL or PostgreSQL instance with a StatefulSet in the cluster
+	ProvisionerLocalStatefulSet = "storageproviders.shardingsphere.apache.org/local-statefulset"
 )
 
 // StorageReclaimPolicy defines the reclaim policy for storage
@@ -69,13 +71,30 @@ type StorageProviderSpec struct {
 
 // StorageProviderStatus defines the observed state of StorageProvider
 type StorageProviderStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Available is true if the provisioner of the StorageProvider is registered and healthy
+	// +optional
+	Available bool `json:"available"`
+	// Provisioners are all the provisioners registered in the operator
+	// +optional
+	Provisioners []ProvisionerStatus `json:"provisioners,omitempty"`
+}
+
+// ProvisionerStatus is the status of a provisioner registered in the operator
+type ProvisionerStatus struct {
+	// Name is the provisioner name used by StorageProvider
+	Name string `json:"name"`
+	// Healthy is true if the provisioner is able to provision databases
+	Healthy bool `json:"healthy"`
+	// Message is the reason why the provisioner is not healthy
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:scope=Cluster,shortName=sp
+//+kubebuilder:printcolumn:JSONPath=".spec.provisioner",name=Provisioner,type=string
+//+kubebuilder:printcolumn:JSONPath=".status.available",name=Available,type=boolean
 
 // StorageProvider is the Schema for the storageproviders API
 type StorageProvider struct {
@@ -28,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	autoscaling_k8s_iov1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 )
 
@@ -81,13 +82,52 @@ func (in *AgentPlugin) DeepCopy() *AgentPlugin {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlgorithmSpec) DeepCopyInto(out *AlgorithmSpec) {
+	*out = *in
+	if in.Props != nil {
+		in, out := &in.Props, &out.Props
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlgorithmSpec.
+func (in *AlgorithmSpec) DeepCopy() *AlgorithmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlgorithmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedRule) DeepCopyInto(out *AppliedRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedRule.
+func (in *AppliedRule) DeepCopy() *AppliedRule {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Auth) DeepCopyInto(out *Auth) {
 	*out = *in
 	if in.Users != nil {
 		in, out := &in.Users, &out.Users
 		*out = make([]User, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Privilege != nil {
 		in, out := &in.Privilege, &out.Privilege
@@ -247,21 +287,6 @@ func (in *AutomaticScaling) DeepCopy() *AutomaticScaling {
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BasicCredential) DeepCopyInto(out *BasicCredential) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicCredential.
-func (in *BasicCredential) DeepCopy() *BasicCredential {
-	if in == nil {
-		return nil
-	}
-	out := new(BasicCredential)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapConfig) DeepCopyInto(out *BootstrapConfig) {
 	*out = *in
@@ -405,6 +430,137 @@ func (in *ChaosStatus) DeepCopy() *ChaosStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackup) DeepCopyInto(out *ClusterBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackup.
+func (in *ClusterBackup) DeepCopy() *ClusterBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackupDataNode) DeepCopyInto(out *ClusterBackupDataNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupDataNode.
+func (in *ClusterBackupDataNode) DeepCopy() *ClusterBackupDataNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackupDataNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackupList) DeepCopyInto(out *ClusterBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupList.
+func (in *ClusterBackupList) DeepCopy() *ClusterBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackupSpec) DeepCopyInto(out *ClusterBackupSpec) {
+	*out = *in
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupSpec.
+func (in *ClusterBackupSpec) DeepCopy() *ClusterBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterBackupStatus) DeepCopyInto(out *ClusterBackupStatus) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataNodes != nil {
+		in, out := &in.DataNodes, &out.DataNodes
+		*out = make([]ClusterBackupDataNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupStatus.
+func (in *ClusterBackupStatus) DeepCopy() *ClusterBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterConfig) DeepCopyInto(out *ClusterConfig) {
 	*out = *in
@@ -436,6 +592,128 @@ func (in *ClusterProps) DeepCopy() *ClusterProps {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRestore) DeepCopyInto(out *ClusterRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRestore.
+func (in *ClusterRestore) DeepCopy() *ClusterRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRestoreDataNode) DeepCopyInto(out *ClusterRestoreDataNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRestoreDataNode.
+func (in *ClusterRestoreDataNode) DeepCopy() *ClusterRestoreDataNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRestoreDataNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRestoreList) DeepCopyInto(out *ClusterRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRestoreList.
+func (in *ClusterRestoreList) DeepCopy() *ClusterRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRestoreSpec) DeepCopyInto(out *ClusterRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRestoreSpec.
+func (in *ClusterRestoreSpec) DeepCopy() *ClusterRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRestoreStatus) DeepCopyInto(out *ClusterRestoreStatus) {
+	*out = *in
+	if in.DataNodes != nil {
+		in, out := &in.DataNodes, &out.DataNodes
+		*out = make([]ClusterRestoreDataNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.DroppedDatabases != nil {
+		in, out := &in.DroppedDatabases, &out.DroppedDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRestoreStatus.
+func (in *ClusterRestoreStatus) DeepCopy() *ClusterRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
@@ -497,7 +775,9 @@ func (in *ComputeNodeAuthority) DeepCopyInto(out *ComputeNodeAuthority) {
 	if in.Users != nil {
 		in, out := &in.Users, &out.Users
 		*out = make([]ComputeNodeUser, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	out.Privilege = in.Privilege
 }
@@ -576,6 +856,26 @@ func (in *ComputeNodePrivilege) DeepCopy() *ComputeNodePrivilege {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeNodeRollingUpdate) DeepCopyInto(out *ComputeNodeRollingUpdate) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeNodeRollingUpdate.
+func (in *ComputeNodeRollingUpdate) DeepCopy() *ComputeNodeRollingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeNodeRollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComputeNodeServerMode) DeepCopyInto(out *ComputeNodeServerMode) {
 	*out = *in
@@ -629,6 +929,16 @@ func (in *ComputeNodeSpec) DeepCopyInto(out *ComputeNodeSpec) {
 		copy(*out, *in)
 	}
 	in.Bootstrap.DeepCopyInto(&out.Bootstrap)
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(ComputeNodeRollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ComputeNodeTLS)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeNodeSpec.
@@ -652,6 +962,18 @@ func (in *ComputeNodeStatus) DeepCopyInto(out *ComputeNodeStatus) {
 		}
 	}
 	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	if in.Proxies != nil {
+		in, out := &in.Proxies, &out.Proxies
+		*out = make([]ProxyInstanceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogicDatabases != nil {
+		in, out := &in.LogicDatabases, &out.LogicDatabases
+		*out = make([]LogicDatabaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeNodeStatus.
@@ -665,13 +987,43 @@ func (in *ComputeNodeStatus) DeepCopy() *ComputeNodeStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComputeNodeUser) DeepCopyInto(out *ComputeNodeUser) {
+func (in *ComputeNodeTLS) DeepCopyInto(out *ComputeNodeTLS) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeNodeUser.
-func (in *ComputeNodeUser) DeepCopy() *ComputeNodeUser {
-	if in == nil {
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ciphers != nil {
+		in, out := &in.Ciphers, &out.Ciphers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeNodeTLS.
+func (in *ComputeNodeTLS) DeepCopy() *ComputeNodeTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeNodeTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeNodeUser) DeepCopyInto(out *ComputeNodeUser) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeNodeUser.
+func (in *ComputeNodeUser) DeepCopy() *ComputeNodeUser {
+	if in == nil {
 		return nil
 	}
 	out := new(ComputeNodeUser)
@@ -752,17 +1104,182 @@ func (in *CorruptionParams) DeepCopy() *CorruptionParams {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CredentialType) DeepCopyInto(out *CredentialType) {
+func (in *DatabaseRule) DeepCopyInto(out *DatabaseRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRule.
+func (in *DatabaseRule) DeepCopy() *DatabaseRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRuleCondition) DeepCopyInto(out *DatabaseRuleCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRuleCondition.
+func (in *DatabaseRuleCondition) DeepCopy() *DatabaseRuleCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRuleCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in DatabaseRuleConditions) DeepCopyInto(out *DatabaseRuleConditions) {
+	{
+		in := &in
+		*out = make(DatabaseRuleConditions, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(DatabaseRuleCondition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRuleConditions.
+func (in DatabaseRuleConditions) DeepCopy() DatabaseRuleConditions {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRuleConditions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRuleList) DeepCopyInto(out *DatabaseRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatabaseRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRuleList.
+func (in *DatabaseRuleList) DeepCopy() *DatabaseRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatabaseRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRuleSpec) DeepCopyInto(out *DatabaseRuleSpec) {
+	*out = *in
+	if in.Sharding != nil {
+		in, out := &in.Sharding, &out.Sharding
+		*out = make([]ShardingTableRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Encrypt != nil {
+		in, out := &in.Encrypt, &out.Encrypt
+		*out = make([]EncryptRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Mask != nil {
+		in, out := &in.Mask, &out.Mask
+		*out = make([]MaskRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Shadow != nil {
+		in, out := &in.Shadow, &out.Shadow
+		*out = make([]ShadowRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadwriteSplitting != nil {
+		in, out := &in.ReadwriteSplitting, &out.ReadwriteSplitting
+		*out = make([]ReadwriteSplittingRuleSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRuleSpec.
+func (in *DatabaseRuleSpec) DeepCopy() *DatabaseRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRuleStatus) DeepCopyInto(out *DatabaseRuleStatus) {
 	*out = *in
-	out.BasicCredential = in.BasicCredential
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(DatabaseRuleConditions, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(DatabaseRuleCondition)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]AppliedRule, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialType.
-func (in *CredentialType) DeepCopy() *CredentialType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRuleStatus.
+func (in *DatabaseRuleStatus) DeepCopy() *DatabaseRuleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CredentialType)
+	out := new(DatabaseRuleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -842,6 +1359,59 @@ func (in *EmbedChaos) DeepCopy() *EmbedChaos {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptColumnSpec) DeepCopyInto(out *EncryptColumnSpec) {
+	*out = *in
+	in.EncryptAlgorithm.DeepCopyInto(&out.EncryptAlgorithm)
+	if in.AssistedQueryAlgorithm != nil {
+		in, out := &in.AssistedQueryAlgorithm, &out.AssistedQueryAlgorithm
+		*out = new(AlgorithmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LikeQueryAlgorithm != nil {
+		in, out := &in.LikeQueryAlgorithm, &out.LikeQueryAlgorithm
+		*out = new(AlgorithmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptColumnSpec.
+func (in *EncryptColumnSpec) DeepCopy() *EncryptColumnSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptColumnSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EncryptRuleSpec) DeepCopyInto(out *EncryptRuleSpec) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]EncryptColumnSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueryWithCipherColumn != nil {
+		in, out := &in.QueryWithCipherColumn, &out.QueryWithCipherColumn
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EncryptRuleSpec.
+func (in *EncryptRuleSpec) DeepCopy() *EncryptRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EncryptRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
@@ -927,6 +1497,22 @@ func (in *JobSpec) DeepCopy() *JobSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyGenerateStrategySpec) DeepCopyInto(out *KeyGenerateStrategySpec) {
+	*out = *in
+	in.KeyGenerator.DeepCopyInto(&out.KeyGenerator)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyGenerateStrategySpec.
+func (in *KeyGenerateStrategySpec) DeepCopy() *KeyGenerateStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyGenerateStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerStatus) DeepCopyInto(out *LoadBalancerStatus) {
 	*out = *in
@@ -971,6 +1557,26 @@ func (in *LoggingFile) DeepCopy() *LoggingFile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicDatabaseStatus) DeepCopyInto(out *LogicDatabaseStatus) {
+	*out = *in
+	if in.StorageUnits != nil {
+		in, out := &in.StorageUnits, &out.StorageUnits
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicDatabaseStatus.
+func (in *LogicDatabaseStatus) DeepCopy() *LogicDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LossParams) DeepCopyInto(out *LossParams) {
 	*out = *in
@@ -986,6 +1592,70 @@ func (in *LossParams) DeepCopy() *LossParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedRepository) DeepCopyInto(out *ManagedRepository) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRepository.
+func (in *ManagedRepository) DeepCopy() *ManagedRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaskColumnSpec) DeepCopyInto(out *MaskColumnSpec) {
+	*out = *in
+	in.Algorithm.DeepCopyInto(&out.Algorithm)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaskColumnSpec.
+func (in *MaskColumnSpec) DeepCopy() *MaskColumnSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaskColumnSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaskRuleSpec) DeepCopyInto(out *MaskRuleSpec) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]MaskColumnSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaskRuleSpec.
+func (in *MaskRuleSpec) DeepCopy() *MaskRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaskRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemoryStressParams) DeepCopyInto(out *MemoryStressParams) {
 	*out = *in
@@ -1479,6 +2149,21 @@ func (in *Props) DeepCopy() *Props {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisionerStatus) DeepCopyInto(out *ProvisionerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionerStatus.
+func (in *ProvisionerStatus) DeepCopy() *ProvisionerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisionerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyConfigSpec) DeepCopyInto(out *ProxyConfigSpec) {
 	*out = *in
@@ -1516,6 +2201,21 @@ func (in *ProxyConfigStatus) DeepCopy() *ProxyConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyInstanceStatus) DeepCopyInto(out *ProxyInstanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyInstanceStatus.
+func (in *ProxyInstanceStatus) DeepCopy() *ProxyInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxyProbe) DeepCopyInto(out *ProxyProbe) {
 	*out = *in
@@ -1615,6 +2315,31 @@ func (in *ProxyStatus) DeepCopy() *ProxyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadwriteSplittingRuleSpec) DeepCopyInto(out *ReadwriteSplittingRuleSpec) {
+	*out = *in
+	if in.ReadStorageUnits != nil {
+		in, out := &in.ReadStorageUnits, &out.ReadStorageUnits
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(AlgorithmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadwriteSplittingRuleSpec.
+func (in *ReadwriteSplittingRuleSpec) DeepCopy() *ReadwriteSplittingRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadwriteSplittingRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Repository) DeepCopyInto(out *Repository) {
 	*out = *in
@@ -1625,6 +2350,11 @@ func (in *Repository) DeepCopyInto(out *Repository) {
 			(*out)[key] = val
 		}
 	}
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(ManagedRepository)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Repository.
@@ -1761,6 +2491,50 @@ func (in *ServiceType) DeepCopy() *ServiceType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowRuleSpec) DeepCopyInto(out *ShadowRuleSpec) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]ShadowTableSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowRuleSpec.
+func (in *ShadowRuleSpec) DeepCopy() *ShadowRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowTableSpec) DeepCopyInto(out *ShadowTableSpec) {
+	*out = *in
+	if in.Algorithms != nil {
+		in, out := &in.Algorithms, &out.Algorithms
+		*out = make([]AlgorithmSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowTableSpec.
+func (in *ShadowTableSpec) DeepCopy() *ShadowTableSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowTableSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ShardingSphereProxy) DeepCopyInto(out *ShardingSphereProxy) {
 	*out = *in
@@ -1879,12 +2653,97 @@ func (in *ShardingSphereProxyServerConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardingStrategySpec) DeepCopyInto(out *ShardingStrategySpec) {
+	*out = *in
+	if in.ShardingColumns != nil {
+		in, out := &in.ShardingColumns, &out.ShardingColumns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ShardingAlgorithm != nil {
+		in, out := &in.ShardingAlgorithm, &out.ShardingAlgorithm
+		*out = new(AlgorithmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardingStrategySpec.
+func (in *ShardingStrategySpec) DeepCopy() *ShardingStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardingStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardingTableRuleSpec) DeepCopyInto(out *ShardingTableRuleSpec) {
+	*out = *in
+	if in.StorageUnits != nil {
+		in, out := &in.StorageUnits, &out.StorageUnits
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ShardingAlgorithm != nil {
+		in, out := &in.ShardingAlgorithm, &out.ShardingAlgorithm
+		*out = new(AlgorithmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DataNodes != nil {
+		in, out := &in.DataNodes, &out.DataNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DatabaseStrategy != nil {
+		in, out := &in.DatabaseStrategy, &out.DatabaseStrategy
+		*out = new(ShardingStrategySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TableStrategy != nil {
+		in, out := &in.TableStrategy, &out.TableStrategy
+		*out = new(ShardingStrategySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KeyGenerateStrategy != nil {
+		in, out := &in.KeyGenerateStrategy, &out.KeyGenerateStrategy
+		*out = new(KeyGenerateStrategySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardingTableRuleSpec.
+func (in *ShardingTableRuleSpec) DeepCopy() *ShardingTableRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardingTableRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotStorageUnit) DeepCopyInto(out *SnapshotStorageUnit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotStorageUnit.
+func (in *SnapshotStorageUnit) DeepCopy() *SnapshotStorageUnit {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotStorageUnit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageNode) DeepCopyInto(out *StorageNode) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -1994,9 +2853,231 @@ func (in *StorageNodeList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeRestore) DeepCopyInto(out *StorageNodeRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeRestore.
+func (in *StorageNodeRestore) DeepCopy() *StorageNodeRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageNodeRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeRestoreList) DeepCopyInto(out *StorageNodeRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StorageNodeRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeRestoreList.
+func (in *StorageNodeRestoreList) DeepCopy() *StorageNodeRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageNodeRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeRestoreSpec) DeepCopyInto(out *StorageNodeRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeRestoreSpec.
+func (in *StorageNodeRestoreSpec) DeepCopy() *StorageNodeRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeRestoreStatus) DeepCopyInto(out *StorageNodeRestoreStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeRestoreStatus.
+func (in *StorageNodeRestoreStatus) DeepCopy() *StorageNodeRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeSnapshot) DeepCopyInto(out *StorageNodeSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeSnapshot.
+func (in *StorageNodeSnapshot) DeepCopy() *StorageNodeSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageNodeSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeSnapshotList) DeepCopyInto(out *StorageNodeSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StorageNodeSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeSnapshotList.
+func (in *StorageNodeSnapshotList) DeepCopy() *StorageNodeSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageNodeSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeSnapshotSource) DeepCopyInto(out *StorageNodeSnapshotSource) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.StorageUnit != nil {
+		in, out := &in.StorageUnit, &out.StorageUnit
+		*out = new(SnapshotStorageUnit)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeSnapshotSource.
+func (in *StorageNodeSnapshotSource) DeepCopy() *StorageNodeSnapshotSource {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeSnapshotSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeSnapshotSpec) DeepCopyInto(out *StorageNodeSnapshotSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeSnapshotSpec.
+func (in *StorageNodeSnapshotSpec) DeepCopy() *StorageNodeSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageNodeSnapshotStatus) DeepCopyInto(out *StorageNodeSnapshotStatus) {
+	*out = *in
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(StorageNodeSnapshotSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeSnapshotStatus.
+func (in *StorageNodeSnapshotStatus) DeepCopy() *StorageNodeSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageNodeSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageNodeSpec) DeepCopyInto(out *StorageNodeSpec) {
 	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeSpec.
@@ -2031,6 +3112,16 @@ func (in *StorageNodeStatus) DeepCopyInto(out *StorageNodeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ReaderStorageUnits != nil {
+		in, out := &in.ReaderStorageUnits, &out.ReaderStorageUnits
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageNodeStatus.
@@ -2048,7 +3139,7 @@ func (in *StorageProvider) DeepCopyInto(out *StorageProvider) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
@@ -2127,6 +3218,11 @@ func (in *StorageProviderSpec) DeepCopy() *StorageProviderSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageProviderStatus) DeepCopyInto(out *StorageProviderStatus) {
 	*out = *in
+	if in.Provisioners != nil {
+		in, out := &in.Provisioners, &out.Provisioners
+		*out = make([]ProvisionerStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProviderStatus.
@@ -2142,6 +3238,11 @@ func (in *StorageProviderStatus) DeepCopy() *StorageProviderStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *User) DeepCopyInto(out *User) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new User.
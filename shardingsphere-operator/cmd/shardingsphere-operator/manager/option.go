@@ -25,12 +25,16 @@ import (
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/controllers"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/chaosmesh"
-	cloudnativepg "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/cloudnative-pg"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/configmap"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/job"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/autoscaler"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/computenode"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/aws"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/cloudnativepg"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/local"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/webhook"
 
 	chaosv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 	cnpgv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -146,22 +150,103 @@ var featureGatesHandlers = map[string]FeatureGateHandler{
 		return nil
 	},
 	"StorageNode": func(mgr manager.Manager) error {
+		provisioners := provisioner.NewRegistry()
+		aws.RegisterProvisioners(provisioners, AwsRegion, AwsAccessKeyID, AwsSecretAccessKey)
+		provisioners.Register(v1alpha1.ProvisionerCloudNativePG, cloudnativepg.NewProvisioner(mgr.GetClient()))
+		provisioners.Register(v1alpha1.ProvisionerLocalStatefulSet, local.NewProvisioner(mgr.GetClient()))
+
 		reconciler := &controllers.StorageNodeReconciler{
-			Client:             mgr.GetClient(),
-			Scheme:             mgr.GetScheme(),
-			Log:                mgr.GetLogger(),
-			Recorder:           mgr.GetEventRecorderFor(controllers.StorageNodeControllerName),
-			Service:            service.NewServiceClient(mgr.GetClient()),
-			CNPG:               cloudnativepg.NewCloudNativePGClient(mgr.GetClient()),
-			AwsAccessKeyID:     AwsAccessKeyID,
-			AwsSecretAccessKey: AwsSecretAccessKey,
-			AwsRegion:          AwsRegion,
+			Client:       mgr.GetClient(),
+			Scheme:       mgr.GetScheme(),
+			Log:          mgr.GetLogger(),
+			Recorder:     mgr.GetEventRecorderFor(controllers.StorageNodeControllerName),
+			Service:      service.NewServiceClient(mgr.GetClient()),
+			Provisioners: provisioners,
 		}
 
 		if err := reconciler.SetupWithManager(mgr); err != nil {
 			logger.Error(err, "unable to create controller", "controller", "StorageNode")
 			return err
 		}
+
+		if err := (&controllers.StorageProviderReconciler{
+			Client:       mgr.GetClient(),
+			Scheme:       mgr.GetScheme(),
+			Log:          mgr.GetLogger(),
+			Provisioners: provisioners,
+		}).SetupWithManager(mgr); err != nil {
+			logger.Error(err, "unable to create controller", "controller", "StorageProvider")
+			return err
+		}
+
+		if err := (&controllers.StorageNodeSnapshotReconciler{
+			Client:       mgr.GetClient(),
+			Scheme:       mgr.GetScheme(),
+			Log:          mgr.GetLogger(),
+			Recorder:     mgr.GetEventRecorderFor(controllers.StorageNodeSnapshotControllerName),
+			Provisioners: provisioners,
+		}).SetupWithManager(mgr); err != nil {
+			logger.Error(err, "unable to create controller", "controller", "StorageNodeSnapshot")
+			return err
+		}
+
+		if err := (&controllers.StorageNodeRestoreReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Log:      mgr.GetLogger(),
+			Recorder: mgr.GetEventRecorderFor(controllers.StorageNodeRestoreControllerName),
+		}).SetupWithManager(mgr); err != nil {
+			logger.Error(err, "unable to create controller", "controller", "StorageNodeRestore")
+			return err
+		}
+		return nil
+	},
+	// StorageNodeWebhook validates the resources in the spec of StorageNode, which requires the serving certificates of the webhook server
+	"StorageNodeWebhook": func(mgr manager.Manager) error {
+		if err := webhook.NewWebhookManagedBy(mgr).
+			For(&v1alpha1.StorageNode{}).
+			WithValidator(&webhook.StorageNodeValidator{Client: mgr.GetClient()}).
+			Complete(); err != nil {
+			logger.Error(err, "unable to create webhook", "webhook", "StorageNode")
+			return err
+		}
+		return nil
+	},
+	"DatabaseRule": func(mgr manager.Manager) error {
+		if err := (&controllers.DatabaseRuleReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Log:      mgr.GetLogger(),
+			Recorder: mgr.GetEventRecorderFor(controllers.DatabaseRuleControllerName),
+			Service:  service.NewServiceClient(mgr.GetClient()),
+		}).SetupWithManager(mgr); err != nil {
+			logger.Error(err, "unable to create controller", "controller", "DatabaseRule")
+			return err
+		}
+		return nil
+	},
+	// ClusterBackup backs up and restores the openGauss storage nodes of ComputeNodes with the PITR agents
+	"ClusterBackup": func(mgr manager.Manager) error {
+		if err := (&controllers.ClusterBackupReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Log:      mgr.GetLogger(),
+			Recorder: mgr.GetEventRecorderFor(controllers.ClusterBackupControllerName),
+			Service:  service.NewServiceClient(mgr.GetClient()),
+		}).SetupWithManager(mgr); err != nil {
+			logger.Error(err, "unable to create controller", "controller", "ClusterBackup")
+			return err
+		}
+		if err := (&controllers.ClusterRestoreReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Log:      mgr.GetLogger(),
+			Recorder: mgr.GetEventRecorderFor(controllers.ClusterRestoreControllerName),
+			Service:  service.NewServiceClient(mgr.GetClient()),
+		}).SetupWithManager(mgr); err != nil {
+			logger.Error(err, "unable to create controller", "controller", "ClusterRestore")
+			return err
+		}
 		return nil
 	},
 	"Chaos": func(mgr manager.Manager) error {
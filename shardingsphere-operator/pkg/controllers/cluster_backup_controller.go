@@ -0,0 +1,488 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/pitr"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+
+	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ClusterBackupControllerName = "cluster-backup-controller"
+
+	// the keys of the Secret holding the exported metadata and storage nodes of a backup
+	clusterBackupMetadataKey     = "metadata"
+	clusterBackupStorageNodesKey = "storage-nodes"
+
+	// the keys of the bearer token and the CA of the agents in the agent Secret,
+	// the client certificate is in the keys of the kubernetes.io/tls Secret
+	agentSecretTokenKey = "token"
+	agentSecretCAKey    = "ca.crt"
+)
+
+// ClusterBackupReconciler backs up the openGauss storage nodes of ComputeNodes with the PITR agents
+type ClusterBackupReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	Service  service.Service
+}
+
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=clusterbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=clusterbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=computenodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=event,verbs=create;patch
+
+// Reconcile handles main function of this controller
+func (r *ClusterBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues(ClusterBackupControllerName, req.NamespacedName)
+
+	backup := &v1alpha1.ClusterBackup{}
+	if err := r.Get(ctx, req.NamespacedName, backup); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// the backup is done
+	if backup.Status.Phase == v1alpha1.ClusterBackupPhaseCompleted || backup.Status.Phase == v1alpha1.ClusterBackupPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	status := backup.Status.DeepCopy()
+	result := ctrl.Result{RequeueAfter: defaultRequeueTime}
+	var err error
+	switch {
+	case backup.Spec.Schedule != "":
+		result, err = r.reconcileSchedule(ctx, backup)
+	case backup.Status.Phase == v1alpha1.ClusterBackupPhaseRunning:
+		err = r.reconcileDataNodes(ctx, backup)
+	default:
+		err = r.startBackup(ctx, backup)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile cluster backup")
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "ReconcileFailed", err.Error())
+	}
+
+	if !reflect.DeepEqual(status, &backup.Status) {
+		if err := r.Status().Update(ctx, backup); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if backup.Status.Phase == v1alpha1.ClusterBackupPhaseCompleted || backup.Status.Phase == v1alpha1.ClusterBackupPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+	return result, nil
+}
+
+func (r *ClusterBackupReconciler) fail(backup *v1alpha1.ClusterBackup, msg string) {
+	backup.Status.Phase = v1alpha1.ClusterBackupPhaseFailed
+	backup.Status.Message = msg
+	r.Recorder.Event(backup, corev1.EventTypeWarning, "BackupFailed", msg)
+}
+
+// startBackup locks the cluster, exports the metadata and the storage nodes, starts the backups of the storage nodes,
+// then unlocks the cluster. The backups are taken by the agents after the cluster is unlocked.
+func (r *ClusterBackupReconciler) startBackup(ctx context.Context, backup *v1alpha1.ClusterBackup) error {
+	ss, err := getShardingSphereServer(ctx, r.Client, r.Service, types.NamespacedName{
+		Namespace: backup.Namespace,
+		Name:      backup.Spec.ComputeNodeName,
+	}, shardingsphere.DriverOpenGauss)
+	if err != nil {
+		backup.Status.Phase = v1alpha1.ClusterBackupPhasePending
+		backup.Status.Message = err.Error()
+		return nil
+	}
+	defer ss.Close()
+
+	if err := ss.LockClusterForBackup(); err != nil {
+		backup.Status.Phase = v1alpha1.ClusterBackupPhasePending
+		backup.Status.Message = err.Error()
+		return nil
+	}
+	start := metav1.Now()
+	r.Recorder.Event(backup, corev1.EventTypeNormal, "ClusterLocked", "Cluster is locked for backup")
+
+	md, nodes, dataNodes, err := r.backupStorageNodes(ctx, backup, ss)
+
+	// the cluster is always unlocked, the backups are not affected by the writes after the CSN
+	if uerr := ss.UnlockCluster(); uerr != nil {
+		r.Recorder.Eventf(backup, corev1.EventTypeWarning, "UnlockFailed", "Cluster is not unlocked: %s", uerr)
+		if err == nil {
+			err = uerr
+		}
+	} else {
+		r.Recorder.Event(backup, corev1.EventTypeNormal, "ClusterUnlocked", "Cluster is unlocked")
+	}
+	if err != nil {
+		r.fail(backup, err.Error())
+		return nil
+	}
+
+	name, err := r.saveMetadata(ctx, backup, md, nodes)
+	if err != nil {
+		r.fail(backup, err.Error())
+		return nil
+	}
+
+	backup.Status.Phase = v1alpha1.ClusterBackupPhaseRunning
+	backup.Status.Message = ""
+	backup.Status.CSN = md.CSN
+	backup.Status.Databases = md.Databases
+	backup.Status.MetadataSecretName = name
+	backup.Status.DataNodes = dataNodes
+	backup.Status.StartTime = &start
+	r.Recorder.Eventf(backup, corev1.EventTypeNormal, "BackupStarted", "Backups of %d storage nodes are started with CSN %s", len(dataNodes), md.CSN)
+	return nil
+}
+
+// backupStorageNodes exports the metadata and the storage nodes of the locked cluster, and starts the backups of the storage nodes
+func (r *ClusterBackupReconciler) backupStorageNodes(ctx context.Context, backup *v1alpha1.ClusterBackup, ss shardingsphere.IServer) (*shardingsphere.ClusterMetadata, []*shardingsphere.ExportedStorageNode, []v1alpha1.ClusterBackupDataNode, error) {
+	md, err := ss.ExportMetadata()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	nodes, err := ss.ExportStorageNodes()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, nil, nil, fmt.Errorf("no storage node in compute node %s", backup.Spec.ComputeNodeName)
+	}
+
+	opts, err := agentOptions(ctx, r.Client, backup)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// all the agents must be available before any backup is started
+	for _, node := range nodes {
+		if err := agentOf(node, backup.Spec.AgentPort, opts).CheckStatus(ctx, agentDatabase(node)); err != nil {
+			return nil, nil, nil, fmt.Errorf("agent of storage node %s:%d is not available: %w", node.IP, node.Port, err)
+		}
+	}
+
+	dataNodes := make([]v1alpha1.ClusterBackupDataNode, 0, len(nodes))
+	for _, node := range nodes {
+		id, err := agentOf(node, backup.Spec.AgentPort, opts).Backup(ctx, &pitr.BackupIn{
+			Database:     *agentDatabase(node),
+			DnBackupPath: backup.Spec.BackupPath,
+			DnThreadsNum: uint8(backup.Spec.ThreadsNum),
+			DnBackupMode: string(backup.Spec.Mode),
+			Instance:     backupInstance(backup),
+			RequestKey:   backupRequestKey(backup, node),
+		})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("backup storage node %s:%d failed: %w", node.IP, node.Port, err)
+		}
+		dataNodes = append(dataNodes, v1alpha1.ClusterBackupDataNode{
+			Host:     node.IP,
+			Port:     int32(node.Port),
+			BackupID: id,
+			Status:   pitr.BackupStatusRunning,
+		})
+	}
+	return md, nodes, dataNodes, nil
+}
+
+// backupRequestKey is the request key of the backup of a storage node. If the status is not updated after the backups
+// are started, the agents return the backups started before instead of starting another ones on the next reconcile.
+func backupRequestKey(backup *v1alpha1.ClusterBackup, node *shardingsphere.ExportedStorageNode) string {
+	return fmt.Sprintf("%s-%s-%d", backup.UID, node.IP, node.Port)
+}
+
+// saveMetadata keeps the metadata and the storage nodes in a Secret owned by the backup, as they contain passwords
+func (r *ClusterBackupReconciler) saveMetadata(ctx context.Context, backup *v1alpha1.ClusterBackup, md *shardingsphere.ClusterMetadata, nodes []*shardingsphere.ExportedStorageNode) (string, error) {
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return "", fmt.Errorf("marshal storage nodes failed: %w", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-metadata", backup.Name),
+			Namespace: backup.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(backup.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("ClusterBackup")),
+			},
+		},
+		Data: map[string][]byte{
+			clusterBackupMetadataKey:     []byte(md.Data),
+			clusterBackupStorageNodesKey: data,
+		},
+	}
+	if err := r.Create(ctx, secret); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return "", fmt.Errorf("create metadata secret failed: %w", err)
+		}
+		if err := r.Update(ctx, secret); err != nil {
+			return "", fmt.Errorf("update metadata secret failed: %w", err)
+		}
+	}
+	return secret.Name, nil
+}
+
+// reconcileDataNodes follows the backups of the storage nodes until all of them are completed or any is failed
+func (r *ClusterBackupReconciler) reconcileDataNodes(ctx context.Context, backup *v1alpha1.ClusterBackup) error {
+	nodes, err := getBackupStorageNodes(ctx, r.Client, backup)
+	if err != nil {
+		return err
+	}
+	opts, err := agentOptions(ctx, r.Client, backup)
+	if err != nil {
+		return err
+	}
+
+	completed := 0
+	for i := range backup.Status.DataNodes {
+		dn := &backup.Status.DataNodes[i]
+		if dn.Status == pitr.BackupStatusCompleted {
+			completed++
+			continue
+		}
+		node := findStorageNode(nodes, dn.Host, dn.Port)
+		if node == nil {
+			r.fail(backup, fmt.Sprintf("storage node %s:%d is not found in the metadata", dn.Host, dn.Port))
+			return nil
+		}
+
+		info, err := agentOf(node, backup.Spec.AgentPort, opts).ShowDetail(ctx, &pitr.ShowDetailIn{
+			Database:     *agentDatabase(node),
+			DnBackupID:   dn.BackupID,
+			DnBackupPath: backup.Spec.BackupPath,
+			Instance:     backupInstance(backup),
+		})
+		if err != nil {
+			return fmt.Errorf("show backup %s of %s:%d failed: %w", dn.BackupID, dn.Host, dn.Port, err)
+		}
+		dn.Status, dn.StartTime, dn.EndTime = info.Status, info.StartTime, info.EndTime
+
+		switch info.Status {
+		case pitr.BackupStatusCompleted:
+			completed++
+		case pitr.BackupStatusFailed:
+			r.fail(backup, fmt.Sprintf("backup %s of storage node %s:%d is failed", dn.BackupID, dn.Host, dn.Port))
+			return nil
+		}
+	}
+
+	if completed == len(backup.Status.DataNodes) {
+		now := metav1.Now()
+		backup.Status.Phase = v1alpha1.ClusterBackupPhaseCompleted
+		backup.Status.CompletionTime = &now
+		r.Recorder.Eventf(backup, corev1.EventTypeNormal, "BackupCompleted", "Backups of %d storage nodes are completed", completed)
+	}
+	return nil
+}
+
+// reconcileSchedule creates a ClusterBackup at each time of the schedule, and removes the finished ones beyond the history limit
+func (r *ClusterBackupReconciler) reconcileSchedule(ctx context.Context, backup *v1alpha1.ClusterBackup) (ctrl.Result, error) {
+	schedule, err := cron.ParseStandard(backup.Spec.Schedule)
+	if err != nil {
+		r.fail(backup, fmt.Sprintf("invalid schedule %s: %s", backup.Spec.Schedule, err))
+		return ctrl.Result{}, nil
+	}
+	backup.Status.Phase = v1alpha1.ClusterBackupPhaseScheduled
+
+	last := backup.CreationTimestamp.Time
+	if backup.Status.LastScheduleTime != nil {
+		last = backup.Status.LastScheduleTime.Time
+	}
+	now := time.Now()
+	earliest := last
+	if backup.Spec.StartingDeadlineSeconds != nil {
+		if t := now.Add(-time.Duration(*backup.Spec.StartingDeadlineSeconds) * time.Second); t.After(earliest) {
+			earliest = t
+		}
+	}
+
+	// only the latest missed schedule is created, e.g. after the operator is down for a while
+	var missed time.Time
+	for next := schedule.Next(earliest); !next.After(now); next = schedule.Next(next) {
+		missed = next
+	}
+	if !missed.IsZero() {
+		if err := r.createScheduledBackup(ctx, backup, missed); err != nil {
+			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+		}
+		backup.Status.LastScheduleTime = &metav1.Time{Time: missed}
+	} else if next := schedule.Next(last); !next.After(now) {
+		r.Recorder.Eventf(backup, corev1.EventTypeWarning, "BackupSkipped", "ClusterBackup scheduled at %s missed the starting deadline", next.UTC().Format(time.RFC3339))
+		backup.Status.LastScheduleTime = &metav1.Time{Time: earliest}
+	}
+
+	if err := r.pruneScheduledBackups(ctx, backup); err != nil {
+		return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+	}
+	return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+}
+
+func (r *ClusterBackupReconciler) createScheduledBackup(ctx context.Context, backup *v1alpha1.ClusterBackup, scheduled time.Time) error {
+	spec := *backup.Spec.DeepCopy()
+	spec.Schedule = ""
+	spec.BackupsHistoryLimit = 0
+
+	child := &v1alpha1.ClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", backup.Name, scheduled.UTC().Format("20060102150405")),
+			Namespace: backup.Namespace,
+			Labels:    map[string]string{v1alpha1.LabelClusterBackupSchedule: backup.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(backup.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("ClusterBackup")),
+			},
+		},
+		Spec: spec,
+	}
+	if err := r.Create(ctx, child); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("create scheduled backup failed: %w", err)
+	}
+	r.Recorder.Eventf(backup, corev1.EventTypeNormal, "BackupScheduled", "ClusterBackup %s is created", child.Name)
+	return nil
+}
+
+func (r *ClusterBackupReconciler) pruneScheduledBackups(ctx context.Context, backup *v1alpha1.ClusterBackup) error {
+	if backup.Spec.BackupsHistoryLimit == 0 {
+		return nil
+	}
+
+	list := &v1alpha1.ClusterBackupList{}
+	if err := r.List(ctx, list, client.InNamespace(backup.Namespace), client.MatchingLabels{v1alpha1.LabelClusterBackupSchedule: backup.Name}); err != nil {
+		return fmt.Errorf("list scheduled backups failed: %w", err)
+	}
+
+	finished := make([]v1alpha1.ClusterBackup, 0, len(list.Items))
+	for _, item := range list.Items {
+		if item.Status.Phase == v1alpha1.ClusterBackupPhaseCompleted || item.Status.Phase == v1alpha1.ClusterBackupPhaseFailed {
+			finished = append(finished, item)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CreationTimestamp.Before(&finished[j].CreationTimestamp) ||
+			(finished[i].CreationTimestamp.Equal(&finished[j].CreationTimestamp) && finished[i].Name < finished[j].Name)
+	})
+
+	for i := 0; i < len(finished)-int(backup.Spec.BackupsHistoryLimit); i++ {
+		if err := r.Delete(ctx, &finished[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete scheduled backup %s failed: %w", finished[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// getBackupStorageNodes returns the storage nodes exported when the backup is taken
+func getBackupStorageNodes(ctx context.Context, c client.Client, backup *v1alpha1.ClusterBackup) ([]*shardingsphere.ExportedStorageNode, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: backup.Namespace, Name: backup.Status.MetadataSecretName}, secret); err != nil {
+		return nil, fmt.Errorf("get metadata secret failed: %w", err)
+	}
+	nodes := []*shardingsphere.ExportedStorageNode{}
+	if err := json.Unmarshal(secret.Data[clusterBackupStorageNodesKey], &nodes); err != nil {
+		return nil, fmt.Errorf("unmarshal storage nodes failed: %w", err)
+	}
+	return nodes, nil
+}
+
+func findStorageNode(nodes []*shardingsphere.ExportedStorageNode, host string, port int32) *shardingsphere.ExportedStorageNode {
+	for _, node := range nodes {
+		if node.IP == host && int32(node.Port) == port {
+			return node
+		}
+	}
+	return nil
+}
+
+// agentOptions returns the credentials of the agents in the agent Secret of the backup
+func agentOptions(ctx context.Context, c client.Client, backup *v1alpha1.ClusterBackup) (*pitr.Options, error) {
+	opts := &pitr.Options{}
+	if backup.Spec.AgentSecretName == "" {
+		return opts, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: backup.Namespace, Name: backup.Spec.AgentSecretName}, secret); err != nil {
+		return nil, fmt.Errorf("get agent secret failed: %w", err)
+	}
+	opts.Token = string(secret.Data[agentSecretTokenKey])
+	if len(secret.Data[corev1.TLSCertKey]) > 0 {
+		cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate in agent secret %s: %w", secret.Name, err)
+		}
+		opts.Certificates = []tls.Certificate{cert}
+	}
+	if ca := secret.Data[agentSecretCAKey]; len(ca) > 0 {
+		opts.RootCAs = x509.NewCertPool()
+		if !opts.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate in %s of agent secret %s", agentSecretCAKey, secret.Name)
+		}
+	}
+	return opts, nil
+}
+
+// agentOf returns the agent on the host of the storage node
+func agentOf(node *shardingsphere.ExportedStorageNode, port int32, opts *pitr.Options) pitr.Agent {
+	return pitr.NewAgent(fmt.Sprintf("%s:%d", node.IP, port), opts)
+}
+
+func agentDatabase(node *shardingsphere.ExportedStorageNode) *pitr.Database {
+	return &pitr.Database{
+		Port:     node.Port,
+		Name:     node.Database,
+		Username: node.Username,
+		Password: node.Password,
+	}
+}
+
+func backupInstance(backup *v1alpha1.ClusterBackup) string {
+	if backup.Spec.Instance != "" {
+		return backup.Spec.Instance
+	}
+	return pitr.DefaultInstance
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *ClusterBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterBackup{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
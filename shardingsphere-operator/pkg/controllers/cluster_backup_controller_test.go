@@ -0,0 +1,506 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/pitr"
+	mock_pitr "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/pitr/mocks"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+	mock_shardingsphere "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere/mocks"
+
+	"bou.ke/monkey"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultTestClusterBackup  = "test-cluster-backup"
+	defaultTestClusterRestore = "test-cluster-restore"
+)
+
+var _ = Describe("ClusterBackup Controller Mock Test", func() {
+	var (
+		cbReconciler *ClusterBackupReconciler
+		crReconciler *ClusterRestoreReconciler
+		mockAgent    *mock_pitr.MockAgent
+		agentAddrs   []string
+		backupName   = types.NamespacedName{Name: defaultTestClusterBackup, Namespace: defaultTestNamespace}
+		restoreName  = types.NamespacedName{Name: defaultTestClusterRestore, Namespace: defaultTestNamespace}
+		storageNodes = []*shardingsphere.ExportedStorageNode{
+			{IP: "10.0.0.1", Port: 5432, Username: "gaussdb", Password: "password", Database: "ds_0"},
+			{IP: "10.0.0.2", Port: 5432, Username: "gaussdb", Password: "password", Database: "ds_1"},
+		}
+		metadata = &shardingsphere.ClusterMetadata{
+			ID:        "734bb036-b15d-4af0-be87-2372d8b6a0cd",
+			Data:      "eyJtZXRhX2RhdGEiOnt9fQ==",
+			CSN:       "16842752",
+			Databases: []string{"sharding_db"},
+		}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSS = mock_shardingsphere.NewMockIServer(mockCtrl)
+		mockAgent = mock_pitr.NewMockAgent(mockCtrl)
+		agentAddrs = []string{}
+		monkey.Patch(shardingsphere.NewServer, func(_, _ string, _ uint, _, _ string) (shardingsphere.IServer, error) {
+			return mockSS, nil
+		})
+		monkey.Patch(pitr.NewAgent, func(addr string, _ *pitr.Options) pitr.Agent {
+			agentAddrs = append(agentAddrs, addr)
+			return mockAgent
+		})
+
+		cbReconciler = &ClusterBackupReconciler{
+			Client:   fakeClient,
+			Log:      logf.Log,
+			Recorder: record.NewFakeRecorder(100),
+			Service:  service.NewServiceClient(fakeClient),
+		}
+		crReconciler = &ClusterRestoreReconciler{
+			Client:   fakeClient,
+			Log:      logf.Log,
+			Recorder: record.NewFakeRecorder(100),
+			Service:  service.NewServiceClient(fakeClient),
+		}
+
+		Expect(fakeClient.Create(ctx, &v1alpha1.ComputeNode{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestComputeNode, Namespace: defaultTestNamespace},
+			Spec: v1alpha1.ComputeNodeSpec{
+				Bootstrap: v1alpha1.BootstrapConfig{
+					ServerConfig: v1alpha1.ServerConfig{
+						Authority: v1alpha1.ComputeNodeAuthority{
+							Users: []v1alpha1.ComputeNodeUser{{User: "root@%", Password: "root"}},
+						},
+					},
+				},
+			},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestComputeNode, Namespace: defaultTestNamespace},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "proxy", Protocol: "TCP", Port: 5432}},
+			},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &v1alpha1.ClusterBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestClusterBackup, Namespace: defaultTestNamespace},
+			Spec: v1alpha1.ClusterBackupSpec{
+				ComputeNodeName: defaultTestComputeNode,
+				Mode:            v1alpha1.ClusterBackupModeFull,
+				BackupPath:      "/home/omm/data",
+				Instance:        pitr.DefaultInstance,
+				ThreadsNum:      1,
+				AgentPort:       443,
+			},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+		monkey.UnpatchAll()
+	})
+
+	// backUp takes the backup and completes it
+	backUp := func() {
+		gomock.InOrder(
+			mockSS.EXPECT().LockClusterForBackup().Return(nil),
+			mockSS.EXPECT().ExportMetadata().Return(metadata, nil),
+			mockSS.EXPECT().ExportStorageNodes().Return(storageNodes, nil),
+		)
+		mockAgent.EXPECT().CheckStatus(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+		mockAgent.EXPECT().Backup(gomock.Any(), gomock.Any()).Return("RTKQ1T", nil).Times(2)
+		mockSS.EXPECT().UnlockCluster().Return(nil)
+		mockSS.EXPECT().Close().Return(nil)
+		_, err := cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+
+		mockAgent.EXPECT().ShowDetail(gomock.Any(), gomock.Any()).Return(&pitr.BackupInfo{ID: "RTKQ1T", Status: pitr.BackupStatusCompleted}, nil).Times(2)
+		_, err = cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+	}
+
+	It("should read the credentials of the agents from the agent secret", func() {
+		backup := &v1alpha1.ClusterBackup{}
+		Expect(fakeClient.Get(ctx, backupName, backup)).To(Succeed())
+		opts, err := agentOptions(ctx, fakeClient, backup)
+		Expect(err).To(BeNil())
+		Expect(opts).To(Equal(&pitr.Options{}))
+
+		backup.Spec.AgentSecretName = "agent-credentials"
+		_, err = agentOptions(ctx, fakeClient, backup)
+		Expect(err).NotTo(BeNil())
+
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-credentials", Namespace: defaultTestNamespace},
+			Data:       map[string][]byte{agentSecretTokenKey: []byte("token")},
+		})).To(Succeed())
+		opts, err = agentOptions(ctx, fakeClient, backup)
+		Expect(err).To(BeNil())
+		Expect(opts.Token).To(Equal("token"))
+		Expect(opts.RootCAs).To(BeNil())
+
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: "agent-credentials"}, secret)).To(Succeed())
+		secret.Data[agentSecretCAKey] = []byte("invalid")
+		Expect(fakeClient.Update(ctx, secret)).To(Succeed())
+		_, err = agentOptions(ctx, fakeClient, backup)
+		Expect(err).NotTo(BeNil())
+		Expect(fakeClient.Delete(ctx, secret)).To(Succeed())
+	})
+
+	It("should lock the cluster, back up the storage nodes and unlock the cluster", func() {
+		gomock.InOrder(
+			mockSS.EXPECT().LockClusterForBackup().Return(nil),
+			mockSS.EXPECT().ExportMetadata().Return(metadata, nil),
+			mockSS.EXPECT().ExportStorageNodes().Return(storageNodes, nil),
+		)
+		mockAgent.EXPECT().CheckStatus(gomock.Any(), &pitr.Database{Port: 5432, Name: "ds_0", Username: "gaussdb", Password: "password"}).Return(nil)
+		mockAgent.EXPECT().CheckStatus(gomock.Any(), &pitr.Database{Port: 5432, Name: "ds_1", Username: "gaussdb", Password: "password"}).Return(nil)
+		mockAgent.EXPECT().Backup(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, in *pitr.BackupIn) (string, error) {
+			Expect(in.DnBackupMode).To(Equal(pitr.BackupModeFull))
+			Expect(in.DnBackupPath).To(Equal("/home/omm/data"))
+			Expect(in.Instance).To(Equal(pitr.DefaultInstance))
+			Expect(in.RequestKey).To(MatchRegexp(`-10\.0\.0\.[12]-5432$`))
+			return "RTKQ1T-" + in.Name, nil
+		}).Times(2)
+		mockSS.EXPECT().UnlockCluster().Return(nil)
+		mockSS.EXPECT().Close().Return(nil)
+
+		res, err := cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(Equal(defaultRequeueTime))
+		Expect(agentAddrs).To(ContainElements("10.0.0.1:443", "10.0.0.2:443"))
+
+		backup := &v1alpha1.ClusterBackup{}
+		Expect(fakeClient.Get(ctx, backupName, backup)).To(Succeed())
+		Expect(backup.Status.Phase).To(Equal(v1alpha1.ClusterBackupPhaseRunning))
+		Expect(backup.Status.CSN).To(Equal("16842752"))
+		Expect(backup.Status.Databases).To(Equal([]string{"sharding_db"}))
+		Expect(backup.Status.StartTime).NotTo(BeNil())
+		Expect(backup.Status.DataNodes).To(Equal([]v1alpha1.ClusterBackupDataNode{
+			{Host: "10.0.0.1", Port: 5432, BackupID: "RTKQ1T-ds_0", Status: pitr.BackupStatusRunning},
+			{Host: "10.0.0.2", Port: 5432, BackupID: "RTKQ1T-ds_1", Status: pitr.BackupStatusRunning},
+		}))
+
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: backup.Status.MetadataSecretName}, secret)).To(Succeed())
+		Expect(string(secret.Data[clusterBackupMetadataKey])).To(Equal(metadata.Data))
+		Expect(string(secret.Data[clusterBackupStorageNodesKey])).To(ContainSubstring(`"password":"password"`))
+		Expect(secret.OwnerReferences[0].Name).To(Equal(defaultTestClusterBackup))
+
+		mockAgent.EXPECT().ShowDetail(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, in *pitr.ShowDetailIn) (*pitr.BackupInfo, error) {
+			if in.Name == "ds_0" {
+				return &pitr.BackupInfo{ID: in.DnBackupID, Status: pitr.BackupStatusCompleted, StartTime: "2023-05-01 00:00:00"}, nil
+			}
+			return &pitr.BackupInfo{ID: in.DnBackupID, Status: pitr.BackupStatusRunning}, nil
+		}).Times(2)
+		_, err = cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+		Expect(fakeClient.Get(ctx, backupName, backup)).To(Succeed())
+		Expect(backup.Status.Phase).To(Equal(v1alpha1.ClusterBackupPhaseRunning))
+		Expect(backup.Status.DataNodes[0].Status).To(Equal(pitr.BackupStatusCompleted))
+		Expect(backup.Status.DataNodes[0].StartTime).To(Equal("2023-05-01 00:00:00"))
+
+		// the completed backup is not shown again
+		mockAgent.EXPECT().ShowDetail(gomock.Any(), gomock.Any()).Return(&pitr.BackupInfo{Status: pitr.BackupStatusCompleted}, nil)
+		res, err = cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(ctrl.Result{}))
+		Expect(fakeClient.Get(ctx, backupName, backup)).To(Succeed())
+		Expect(backup.Status.Phase).To(Equal(v1alpha1.ClusterBackupPhaseCompleted))
+		Expect(backup.Status.CompletionTime).NotTo(BeNil())
+	})
+
+	It("should stay pending if the cluster can not be locked", func() {
+		mockSS.EXPECT().LockClusterForBackup().Return(errors.New("cluster is locked"))
+		mockSS.EXPECT().Close().Return(nil)
+
+		_, err := cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+		backup := &v1alpha1.ClusterBackup{}
+		Expect(fakeClient.Get(ctx, backupName, backup)).To(Succeed())
+		Expect(backup.Status.Phase).To(Equal(v1alpha1.ClusterBackupPhasePending))
+		Expect(backup.Status.Message).To(Equal("cluster is locked"))
+	})
+
+	It("should unlock the cluster and fail if an agent is not available", func() {
+		gomock.InOrder(
+			mockSS.EXPECT().LockClusterForBackup().Return(nil),
+			mockSS.EXPECT().ExportMetadata().Return(metadata, nil),
+			mockSS.EXPECT().ExportStorageNodes().Return(storageNodes, nil),
+			mockAgent.EXPECT().CheckStatus(gomock.Any(), gomock.Any()).Return(errors.New("connection refused")),
+			mockSS.EXPECT().UnlockCluster().Return(nil),
+		)
+		mockSS.EXPECT().Close().Return(nil)
+
+		res, err := cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(ctrl.Result{}))
+		backup := &v1alpha1.ClusterBackup{}
+		Expect(fakeClient.Get(ctx, backupName, backup)).To(Succeed())
+		Expect(backup.Status.Phase).To(Equal(v1alpha1.ClusterBackupPhaseFailed))
+		Expect(backup.Status.Message).To(ContainSubstring("10.0.0.1:5432"))
+	})
+
+	It("should create the backups on the schedule and keep the history limit", func() {
+		scheduled := &v1alpha1.ClusterBackup{}
+		Expect(fakeClient.Get(ctx, backupName, scheduled)).To(Succeed())
+		last := time.Now().Add(-2 * time.Hour).Truncate(time.Hour)
+		scheduled.Spec.Schedule = "0 * * * *"
+		scheduled.Spec.BackupsHistoryLimit = 1
+		scheduled.Status.LastScheduleTime = &metav1.Time{Time: last}
+		Expect(fakeClient.Update(ctx, scheduled)).To(Succeed())
+
+		for _, name := range []string{"test-cluster-backup-old-0", "test-cluster-backup-old-1"} {
+			old := &v1alpha1.ClusterBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: defaultTestNamespace,
+					Labels:    map[string]string{v1alpha1.LabelClusterBackupSchedule: defaultTestClusterBackup},
+				},
+				Spec: v1alpha1.ClusterBackupSpec{ComputeNodeName: defaultTestComputeNode, BackupPath: "/home/omm/data"},
+			}
+			Expect(fakeClient.Create(ctx, old)).To(Succeed())
+			old.Status.Phase = v1alpha1.ClusterBackupPhaseCompleted
+			Expect(fakeClient.Status().Update(ctx, old)).To(Succeed())
+		}
+
+		res, err := cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(res.RequeueAfter).To(BeNumerically("<=", time.Hour))
+
+		Expect(fakeClient.Get(ctx, backupName, scheduled)).To(Succeed())
+		Expect(scheduled.Status.Phase).To(Equal(v1alpha1.ClusterBackupPhaseScheduled))
+		// only the latest of the missed schedules is created
+		next := last.Add(2 * time.Hour)
+		Expect(scheduled.Status.LastScheduleTime.Time).To(BeTemporally("==", next))
+
+		child := &v1alpha1.ClusterBackup{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{
+			Namespace: defaultTestNamespace,
+			Name:      defaultTestClusterBackup + "-" + next.UTC().Format("20060102150405"),
+		}, child)).To(Succeed())
+		Expect(child.Spec.Schedule).To(BeEmpty())
+		Expect(child.Spec.BackupPath).To(Equal("/home/omm/data"))
+		Expect(child.OwnerReferences[0].Name).To(Equal(defaultTestClusterBackup))
+
+		list := &v1alpha1.ClusterBackupList{}
+		Expect(fakeClient.List(ctx, list, client.MatchingLabels{v1alpha1.LabelClusterBackupSchedule: defaultTestClusterBackup})).To(Succeed())
+		names := []string{}
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		Expect(names).To(ConsistOf(child.Name, "test-cluster-backup-old-1"))
+	})
+
+	It("should skip the schedule missing the starting deadline", func() {
+		scheduled := &v1alpha1.ClusterBackup{}
+		Expect(fakeClient.Get(ctx, backupName, scheduled)).To(Succeed())
+		last := time.Now().Add(-48 * time.Hour)
+		deadline := int64(60)
+		scheduled.Spec.Schedule = "0 0 1 1 *"
+		scheduled.Spec.StartingDeadlineSeconds = &deadline
+		scheduled.Status.LastScheduleTime = &metav1.Time{Time: last.AddDate(-1, 0, 0)}
+		Expect(fakeClient.Update(ctx, scheduled)).To(Succeed())
+
+		_, err := cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+		Expect(fakeClient.Get(ctx, backupName, scheduled)).To(Succeed())
+		Expect(scheduled.Status.Phase).To(Equal(v1alpha1.ClusterBackupPhaseScheduled))
+		Expect(scheduled.Status.LastScheduleTime.Time).To(BeTemporally("~", time.Now().Add(-time.Minute), 5*time.Second))
+
+		list := &v1alpha1.ClusterBackupList{}
+		Expect(fakeClient.List(ctx, list, client.MatchingLabels{v1alpha1.LabelClusterBackupSchedule: defaultTestClusterBackup})).To(Succeed())
+		Expect(list.Items).To(BeEmpty())
+	})
+
+	It("should fail with an invalid schedule", func() {
+		scheduled := &v1alpha1.ClusterBackup{}
+		Expect(fakeClient.Get(ctx, backupName, scheduled)).To(Succeed())
+		scheduled.Spec.Schedule = "every day"
+		Expect(fakeClient.Update(ctx, scheduled)).To(Succeed())
+
+		_, err := cbReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: backupName})
+		Expect(err).To(BeNil())
+		Expect(fakeClient.Get(ctx, backupName, scheduled)).To(Succeed())
+		Expect(scheduled.Status.Phase).To(Equal(v1alpha1.ClusterBackupPhaseFailed))
+	})
+
+	Context("ClusterRestore", func() {
+		BeforeEach(func() {
+			Expect(fakeClient.Create(ctx, &v1alpha1.ClusterRestore{
+				ObjectMeta: metav1.ObjectMeta{Name: defaultTestClusterRestore, Namespace: defaultTestNamespace},
+				Spec:       v1alpha1.ClusterRestoreSpec{BackupName: defaultTestClusterBackup, ThreadsNum: 2},
+			})).To(Succeed())
+		})
+
+		It("should wait for the backup to be completed", func() {
+			_, err := crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			restore := &v1alpha1.ClusterRestore{}
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhasePending))
+			Expect(restore.Status.Message).To(ContainSubstring("not completed"))
+		})
+
+		It("should restore the storage nodes and import the metadata", func() {
+			backUp()
+
+			mockAgent.EXPECT().CheckStatus(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			_, err := crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			restore := &v1alpha1.ClusterRestore{}
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhaseRestoring))
+			Expect(restore.Status.DataNodes).To(HaveLen(2))
+
+			mockAgent.EXPECT().SubmitRestore(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, in *pitr.RestoreIn) (string, error) {
+				Expect(in.DnBackupID).To(Equal("RTKQ1T"))
+				Expect(in.DnThreadsNum).To(Equal(uint8(2)))
+				Expect(in.RequestKey).To(MatchRegexp(`-10\.0\.0\.[12]-5432$`))
+				return "job-" + in.Name, nil
+			}).Times(2)
+			_, err = crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhaseRestoring))
+			Expect(restore.Status.DataNodes[0].JobID).To(Equal("job-ds_0"))
+			Expect(restore.Status.DataNodes[0].Status).To(Equal(pitr.BackupStatusRunning))
+
+			// the restore waits for the running jobs
+			mockAgent.EXPECT().GetJob(gomock.Any(), "job-ds_0").Return(&pitr.Job{ID: "job-ds_0", State: pitr.JobStateSucceeded}, nil)
+			mockAgent.EXPECT().GetJob(gomock.Any(), "job-ds_1").Return(&pitr.Job{ID: "job-ds_1", State: pitr.JobStateRunning}, nil)
+			_, err = crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhaseRestoring))
+			Expect(restore.Status.DataNodes[0].Status).To(Equal(pitr.BackupStatusCompleted))
+
+			mockAgent.EXPECT().GetJob(gomock.Any(), "job-ds_1").Return(&pitr.Job{ID: "job-ds_1", State: pitr.JobStateSucceeded}, nil)
+			_, err = crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhaseImportingMetadata))
+			Expect(restore.Status.DataNodes[0].Status).To(Equal(pitr.BackupStatusCompleted))
+
+			gomock.InOrder(
+				mockSS.EXPECT().ExportMetadata().Return(&shardingsphere.ClusterMetadata{Data: "previous", Databases: []string{"new_db", "sharding_db"}}, nil),
+				mockSS.EXPECT().ExportMetadata().Return(&shardingsphere.ClusterMetadata{Data: "previous", Databases: []string{"new_db", "sharding_db"}}, nil),
+				mockSS.EXPECT().DropDatabase("new_db").Return(nil),
+				mockSS.EXPECT().DropDatabase("sharding_db").Return(nil),
+				mockSS.EXPECT().ImportMetadata(metadata.Data).Return(nil),
+			)
+			mockSS.EXPECT().Close().Return(nil)
+			res, err := crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(ctrl.Result{}))
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhaseCompleted))
+			Expect(restore.Status.DroppedDatabases).To(Equal([]string{"new_db", "sharding_db"}))
+			Expect(restore.Status.CompletionTime).NotTo(BeNil())
+		})
+
+		It("should import the previous metadata again and retry if the metadata of the backup is not imported", func() {
+			backUp()
+			restore := &v1alpha1.ClusterRestore{}
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			restore.Status.Phase = v1alpha1.ClusterRestorePhaseImportingMetadata
+			Expect(fakeClient.Status().Update(ctx, restore)).To(Succeed())
+
+			gomock.InOrder(
+				mockSS.EXPECT().ExportMetadata().Return(&shardingsphere.ClusterMetadata{Data: "previous", Databases: []string{"sharding_db"}}, nil),
+				mockSS.EXPECT().ExportMetadata().Return(&shardingsphere.ClusterMetadata{Data: "previous", Databases: []string{"sharding_db"}}, nil),
+				mockSS.EXPECT().DropDatabase("sharding_db").Return(nil),
+				mockSS.EXPECT().ImportMetadata(metadata.Data).Return(errors.New("invalid metadata")),
+				mockSS.EXPECT().ExportMetadata().Return(&shardingsphere.ClusterMetadata{}, nil),
+				mockSS.EXPECT().ImportMetadata("previous").Return(nil),
+			)
+			mockSS.EXPECT().Close().Return(nil)
+			res, err := crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(ctrl.Result{RequeueAfter: defaultRequeueTime}))
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhaseImportingMetadata))
+			Expect(restore.Status.Message).To(Equal("import metadata of backup test-cluster-backup failed: invalid metadata"))
+			previous := &corev1.Secret{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: defaultTestClusterRestore + "-previous-metadata"}, previous)).To(Succeed())
+			Expect(string(previous.Data["metadata"])).To(Equal("previous"))
+
+			// the kept metadata is not exported again
+			gomock.InOrder(
+				mockSS.EXPECT().ExportMetadata().Return(&shardingsphere.ClusterMetadata{Data: "previous", Databases: []string{"sharding_db"}}, nil),
+				mockSS.EXPECT().DropDatabase("sharding_db").Return(nil),
+				mockSS.EXPECT().ImportMetadata(metadata.Data).Return(nil),
+			)
+			mockSS.EXPECT().Close().Return(nil)
+			_, err = crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhaseCompleted))
+			Expect(restore.Status.DroppedDatabases).To(Equal([]string{"sharding_db"}))
+		})
+
+		It("should fail if a storage node is not restored", func() {
+			backUp()
+
+			mockAgent.EXPECT().CheckStatus(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			_, err := crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+
+			mockAgent.EXPECT().SubmitRestore(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, in *pitr.RestoreIn) (string, error) {
+				if in.Name == "ds_1" {
+					return "", errors.New("agent is unavailable")
+				}
+				return "job-" + in.Name, nil
+			}).Times(2)
+			_, err = crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+
+			// the failed submission is retried with the same request key
+			mockAgent.EXPECT().GetJob(gomock.Any(), "job-ds_0").Return(&pitr.Job{ID: "job-ds_0", State: pitr.JobStateSucceeded}, nil)
+			mockAgent.EXPECT().SubmitRestore(gomock.Any(), gomock.Any()).Return("job-ds_1", nil)
+			_, err = crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+
+			mockAgent.EXPECT().GetJob(gomock.Any(), "job-ds_1").Return(&pitr.Job{ID: "job-ds_1", State: pitr.JobStateFailed, Error: "gs_probackup failed"}, nil)
+			res, err := crReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: restoreName})
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(ctrl.Result{}))
+			restore := &v1alpha1.ClusterRestore{}
+			Expect(fakeClient.Get(ctx, restoreName, restore)).To(Succeed())
+			Expect(restore.Status.Phase).To(Equal(v1alpha1.ClusterRestorePhaseFailed))
+			Expect(restore.Status.DataNodes[1]).To(Equal(v1alpha1.ClusterRestoreDataNode{
+				Host: "10.0.0.2", Port: 5432, JobID: "job-ds_1", Status: pitr.BackupStatusFailed, Message: "restore job job-ds_1 is failed: gs_probackup failed",
+			}))
+		})
+	})
+})
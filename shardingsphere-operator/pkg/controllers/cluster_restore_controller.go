@@ -0,0 +1,367 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/pitr"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ClusterRestoreControllerName = "cluster-restore-controller"
+)
+
+// ClusterRestoreReconciler restores the openGauss storage nodes and the metadata of ComputeNodes with ClusterBackups
+type ClusterRestoreReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	Service  service.Service
+}
+
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=clusterrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=clusterrestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=clusterbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=computenodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=event,verbs=create;patch
+
+// Reconcile handles main function of this controller
+func (r *ClusterRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues(ClusterRestoreControllerName, req.NamespacedName)
+
+	restore := &v1alpha1.ClusterRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restore); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// the restore is done, it is never retried as the cluster may be partially restored
+	if restore.Status.Phase == v1alpha1.ClusterRestorePhaseCompleted || restore.Status.Phase == v1alpha1.ClusterRestorePhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	status := restore.Status.DeepCopy()
+	err := r.reconcileRestore(ctx, restore)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile cluster restore")
+		r.Recorder.Event(restore, corev1.EventTypeWarning, "ReconcileFailed", err.Error())
+	}
+
+	if !reflect.DeepEqual(status, &restore.Status) {
+		if err := r.Status().Update(ctx, restore); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if restore.Status.Phase == v1alpha1.ClusterRestorePhaseCompleted || restore.Status.Phase == v1alpha1.ClusterRestorePhaseFailed {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
+}
+
+func (r *ClusterRestoreReconciler) fail(restore *v1alpha1.ClusterRestore, msg string) {
+	restore.Status.Phase = v1alpha1.ClusterRestorePhaseFailed
+	restore.Status.Message = msg
+	r.Recorder.Event(restore, corev1.EventTypeWarning, "RestoreFailed", msg)
+}
+
+func (r *ClusterRestoreReconciler) reconcileRestore(ctx context.Context, restore *v1alpha1.ClusterRestore) error {
+	backup := &v1alpha1.ClusterBackup{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.BackupName}, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			restore.Status.Phase = v1alpha1.ClusterRestorePhasePending
+			restore.Status.Message = fmt.Sprintf("backup %s is not found", restore.Spec.BackupName)
+			return nil
+		}
+		return fmt.Errorf("get cluster backup failed: %w", err)
+	}
+
+	switch restore.Status.Phase {
+	case v1alpha1.ClusterRestorePhaseRestoring:
+		return r.restoreDataNodes(ctx, restore, backup)
+	case v1alpha1.ClusterRestorePhaseImportingMetadata:
+		return r.importMetadata(ctx, restore, backup)
+	default:
+		return r.checkBackup(ctx, restore, backup)
+	}
+}
+
+// checkBackup waits for the backup to be completed and all the agents to be available before any storage node is restored
+func (r *ClusterRestoreReconciler) checkBackup(ctx context.Context, restore *v1alpha1.ClusterRestore, backup *v1alpha1.ClusterBackup) error {
+	switch backup.Status.Phase {
+	case v1alpha1.ClusterBackupPhaseCompleted:
+	case v1alpha1.ClusterBackupPhaseFailed, v1alpha1.ClusterBackupPhaseScheduled:
+		r.fail(restore, fmt.Sprintf("backup %s is %s, it can not be restored", backup.Name, backup.Status.Phase))
+		return nil
+	default:
+		restore.Status.Phase = v1alpha1.ClusterRestorePhasePending
+		restore.Status.Message = fmt.Sprintf("backup %s is not completed", backup.Name)
+		return nil
+	}
+
+	nodes, err := getBackupStorageNodes(ctx, r.Client, backup)
+	if err != nil {
+		return err
+	}
+	opts, err := agentOptions(ctx, r.Client, backup)
+	if err != nil {
+		return err
+	}
+
+	dataNodes := make([]v1alpha1.ClusterRestoreDataNode, 0, len(backup.Status.DataNodes))
+	for _, dn := range backup.Status.DataNodes {
+		node := findStorageNode(nodes, dn.Host, dn.Port)
+		if node == nil {
+			r.fail(restore, fmt.Sprintf("storage node %s:%d is not found in the metadata", dn.Host, dn.Port))
+			return nil
+		}
+		if err := agentOf(node, backup.Spec.AgentPort, opts).CheckStatus(ctx, agentDatabase(node)); err != nil {
+			restore.Status.Phase = v1alpha1.ClusterRestorePhasePending
+			restore.Status.Message = fmt.Sprintf("agent of storage node %s:%d is not available: %s", dn.Host, dn.Port, err)
+			return nil
+		}
+		dataNodes = append(dataNodes, v1alpha1.ClusterRestoreDataNode{Host: dn.Host, Port: dn.Port})
+	}
+
+	now := metav1.Now()
+	restore.Status.Phase = v1alpha1.ClusterRestorePhaseRestoring
+	restore.Status.Message = ""
+	restore.Status.DataNodes = dataNodes
+	restore.Status.StartTime = &now
+	r.Recorder.Eventf(restore, corev1.EventTypeNormal, "RestoreStarted", "Restoring %d storage nodes with backup %s", len(dataNodes), backup.Name)
+	return nil
+}
+
+// restoreDataNodes submits a restore job to the agent of each storage node and polls the jobs until all of them are done.
+// The request key of a job is derived from the restore, so a job submitted before a failed status update is not submitted twice.
+func (r *ClusterRestoreReconciler) restoreDataNodes(ctx context.Context, restore *v1alpha1.ClusterRestore, backup *v1alpha1.ClusterBackup) error {
+	nodes, err := getBackupStorageNodes(ctx, r.Client, backup)
+	if err != nil {
+		return err
+	}
+
+	opts, err := agentOptions(ctx, r.Client, backup)
+	if err != nil {
+		return err
+	}
+
+	threads := restore.Spec.ThreadsNum
+	if threads == 0 {
+		threads = 1
+	}
+
+	for i := range restore.Status.DataNodes {
+		dn := &restore.Status.DataNodes[i]
+		if dn.Status == pitr.BackupStatusCompleted || dn.Status == pitr.BackupStatusFailed {
+			continue
+		}
+
+		node := findStorageNode(nodes, dn.Host, dn.Port)
+		var backupID string
+		for _, b := range backup.Status.DataNodes {
+			if b.Host == dn.Host && b.Port == dn.Port {
+				backupID = b.BackupID
+			}
+		}
+		if node == nil || backupID == "" {
+			dn.Status = pitr.BackupStatusFailed
+			dn.Message = "storage node is not found in the backup"
+			continue
+		}
+
+		agent := agentOf(node, backup.Spec.AgentPort, opts)
+		if dn.JobID == "" {
+			id, err := agent.SubmitRestore(ctx, &pitr.RestoreIn{
+				Database:     *agentDatabase(node),
+				Instance:     backupInstance(backup),
+				DnBackupPath: backup.Spec.BackupPath,
+				DnBackupID:   backupID,
+				DnThreadsNum: uint8(threads),
+				RequestKey:   fmt.Sprintf("%s-%s-%d", restore.UID, dn.Host, dn.Port),
+			})
+			if err != nil {
+				dn.Message = err.Error()
+				continue
+			}
+			dn.JobID = id
+			dn.Status = pitr.BackupStatusRunning
+			dn.Message = ""
+			continue
+		}
+
+		job, err := agent.GetJob(ctx, dn.JobID)
+		if err != nil {
+			dn.Message = err.Error()
+			continue
+		}
+		switch job.State {
+		case pitr.JobStateSucceeded:
+			dn.Status = pitr.BackupStatusCompleted
+			dn.Message = ""
+		case pitr.JobStateFailed, pitr.JobStateCanceled, pitr.JobStateInterrupted:
+			dn.Status = pitr.BackupStatusFailed
+			dn.Message = fmt.Sprintf("restore job %s is %s: %s", job.ID, job.State, job.Error)
+		default:
+			dn.Status = pitr.BackupStatusRunning
+			dn.Message = ""
+		}
+	}
+
+	// the restore is done once the jobs of all the storage nodes are done
+	for _, dn := range restore.Status.DataNodes {
+		if dn.Status != pitr.BackupStatusCompleted && dn.Status != pitr.BackupStatusFailed {
+			return nil
+		}
+	}
+	for _, dn := range restore.Status.DataNodes {
+		if dn.Status == pitr.BackupStatusFailed {
+			r.fail(restore, fmt.Sprintf("storage node %s:%d is not restored: %s", dn.Host, dn.Port, dn.Message))
+			return nil
+		}
+	}
+
+	restore.Status.Phase = v1alpha1.ClusterRestorePhaseImportingMetadata
+	r.Recorder.Event(restore, corev1.EventTypeNormal, "DataNodesRestored", "Storage nodes are restored")
+	return nil
+}
+
+// importMetadata replaces the logic databases of the compute node with the ones in the metadata of the backup.
+// The metadata of the compute node is kept in a Secret before its logic databases are dropped, it is imported again
+// if the metadata of the backup fails to be imported, and the import is retried.
+func (r *ClusterRestoreReconciler) importMetadata(ctx context.Context, restore *v1alpha1.ClusterRestore, backup *v1alpha1.ClusterBackup) error {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: backup.Namespace, Name: backup.Status.MetadataSecretName}, secret); err != nil {
+		return fmt.Errorf("get metadata secret failed: %w", err)
+	}
+
+	computeNode := restore.Spec.ComputeNodeName
+	if computeNode == "" {
+		computeNode = backup.Spec.ComputeNodeName
+	}
+	ss, err := getShardingSphereServer(ctx, r.Client, r.Service, types.NamespacedName{
+		Namespace: restore.Namespace,
+		Name:      computeNode,
+	}, shardingsphere.DriverOpenGauss)
+	if err != nil {
+		restore.Status.Message = err.Error()
+		return nil
+	}
+	defer ss.Close()
+
+	previous, err := r.keepMetadata(ctx, restore, ss)
+	if err != nil {
+		restore.Status.Message = err.Error()
+		return nil
+	}
+
+	dropped, err := replaceMetadata(ss, string(secret.Data[clusterBackupMetadataKey]))
+	if err != nil {
+		msg := fmt.Sprintf("import metadata of backup %s failed: %s", backup.Name, err)
+		if _, err := replaceMetadata(ss, previous); err != nil {
+			msg = fmt.Sprintf("%s, and the previous metadata is not imported again: %s", msg, err)
+		}
+		restore.Status.Message = msg
+		r.Recorder.Event(restore, corev1.EventTypeWarning, "ImportMetadataFailed", msg)
+		return nil
+	}
+
+	now := metav1.Now()
+	restore.Status.Phase = v1alpha1.ClusterRestorePhaseCompleted
+	restore.Status.Message = ""
+	restore.Status.DroppedDatabases = dropped
+	restore.Status.CompletionTime = &now
+	r.Recorder.Eventf(restore, corev1.EventTypeNormal, "RestoreCompleted", "Cluster is restored with backup %s", backup.Name)
+	return nil
+}
+
+// keepMetadata returns the metadata of the compute node before the restore, which is exported into a Secret on the first import,
+// so it is not lost if the operator restarts after the logic databases are dropped.
+func (r *ClusterRestoreReconciler) keepMetadata(ctx context.Context, restore *v1alpha1.ClusterRestore, ss shardingsphere.IServer) (string, error) {
+	name := types.NamespacedName{Namespace: restore.Namespace, Name: fmt.Sprintf("%s-previous-metadata", restore.Name)}
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, name, secret)
+	if err == nil {
+		return string(secret.Data[clusterBackupMetadataKey]), nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("get previous metadata secret failed: %w", err)
+	}
+
+	md, err := ss.ExportMetadata()
+	if err != nil {
+		return "", err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(restore.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("ClusterRestore")),
+			},
+		},
+		Data: map[string][]byte{
+			clusterBackupMetadataKey: []byte(md.Data),
+		},
+	}
+	if err := r.Create(ctx, secret); err != nil {
+		return "", fmt.Errorf("create previous metadata secret failed: %w", err)
+	}
+	return md.Data, nil
+}
+
+// replaceMetadata drops all the logic databases of the compute node and imports the metadata, it returns the dropped databases
+func replaceMetadata(ss shardingsphere.IServer, data string) ([]string, error) {
+	md, err := ss.ExportMetadata()
+	if err != nil {
+		return nil, err
+	}
+	for _, db := range md.Databases {
+		if err := ss.DropDatabase(db); err != nil {
+			return nil, err
+		}
+	}
+	if err := ss.ImportMetadata(data); err != nil {
+		return nil, err
+	}
+	return md.Databases, nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *ClusterRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterRestore{}).
+		Complete(r)
+}
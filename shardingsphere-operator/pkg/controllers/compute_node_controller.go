@@ -33,6 +33,7 @@ import (
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -69,15 +70,21 @@ func (r *ComputeNodeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Complete(r)
 }
 
 // +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=computenodes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=computenodes/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile handles main function of this controller
 func (r *ComputeNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -98,7 +105,25 @@ func (r *ComputeNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	errors := []error{}
-	if err := r.reconcileDeployment(ctx, cn); err != nil {
+	// the configmap and the secrets are updated before the deployment, so that the rolled pods start with the latest configuration
+	if err := r.reconcileConfigMap(ctx, cn); err != nil {
+		logger.Error(err, "Failed to reconcile configmap")
+		errors = append(errors, err)
+	}
+	if err := r.reconcileAuthority(ctx, cn); err != nil {
+		logger.Error(err, "Failed to reconcile authority")
+		errors = append(errors, err)
+	}
+	if err := r.reconcileServerConfigSecret(ctx, cn); err != nil {
+		logger.Error(err, "Failed to reconcile server config secret")
+		errors = append(errors, err)
+	}
+	governanceReady, err := r.reconcileGovernance(ctx, cn)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile governance")
+		errors = append(errors, err)
+	}
+	if err := r.reconcileDeployment(ctx, cn, governanceReady); err != nil {
 		logger.Error(err, "Failed to reconcile deployement")
 		errors = append(errors, err)
 	}
@@ -106,10 +131,6 @@ func (r *ComputeNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		logger.Error(err, "Failed to reconcile service")
 		errors = append(errors, err)
 	}
-	if err := r.reconcileConfigMap(ctx, cn); err != nil {
-		logger.Error(err, "Failed to reconcile configmap")
-		errors = append(errors, err)
-	}
 
 	if len(errors) != 0 {
 		return ctrl.Result{Requeue: true}, errors[0]
@@ -118,20 +139,45 @@ func (r *ComputeNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
 }
 
-func (r *ComputeNodeReconciler) reconcileDeployment(ctx context.Context, cn *v1alpha1.ComputeNode) error {
+// reconcileDeployment creates the deployment after the governance is ready, the existing deployment is always updated
+func (r *ComputeNodeReconciler) reconcileDeployment(ctx context.Context, cn *v1alpha1.ComputeNode, governanceReady bool) error {
 	deploy, err := r.getDeploymentByNamespacedName(ctx, types.NamespacedName{Namespace: cn.Namespace, Name: cn.Name})
 	if err != nil {
 		return err
 	}
+	if deploy == nil && !governanceReady {
+		return nil
+	}
+
+	secrets, err := getReferencedSecrets(ctx, r.Client, cn)
+	if err != nil {
+		return err
+	}
 
 	if deploy != nil {
-		return r.updateDeployment(ctx, cn, deploy)
+		return r.updateDeployment(ctx, cn, deploy, secrets)
 	}
-	return r.createDeployment(ctx, cn)
+	return r.createDeployment(ctx, cn, secrets)
 }
 
-func (r *ComputeNodeReconciler) createDeployment(ctx context.Context, cn *v1alpha1.ComputeNode) error {
-	deploy := r.Builder.BuildDeployment(ctx, cn)
+// getReferencedSecrets returns the Secrets referenced by the ComputeNode, the missing ones are skipped
+func getReferencedSecrets(ctx context.Context, c client.Client, cn *v1alpha1.ComputeNode) ([]*corev1.Secret, error) {
+	secrets := []*corev1.Secret{}
+	for _, name := range reconcile.ReferencedSecretNames(cn) {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: cn.Namespace, Name: name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+func (r *ComputeNodeReconciler) createDeployment(ctx context.Context, cn *v1alpha1.ComputeNode, secrets []*corev1.Secret) error {
+	deploy := r.Builder.BuildDeployment(ctx, cn, secrets)
 	err := r.Resources.Deployment().Create(ctx, deploy)
 	if err != nil && apierrors.IsAlreadyExists(err) || err == nil {
 		return nil
@@ -139,8 +185,8 @@ func (r *ComputeNodeReconciler) createDeployment(ctx context.Context, cn *v1alph
 	return err
 }
 
-func (r *ComputeNodeReconciler) updateDeployment(ctx context.Context, cn *v1alpha1.ComputeNode, deploy *appsv1.Deployment) error {
-	exp := r.Builder.BuildDeployment(ctx, cn)
+func (r *ComputeNodeReconciler) updateDeployment(ctx context.Context, cn *v1alpha1.ComputeNode, deploy *appsv1.Deployment, secrets []*corev1.Secret) error {
+	exp := r.Builder.BuildDeployment(ctx, cn, secrets)
 	exp.ObjectMeta = deploy.ObjectMeta
 	exp.Labels = deploy.Labels
 	exp.Annotations = deploy.Annotations
@@ -317,6 +363,140 @@ func (r *ComputeNodeReconciler) reconcileConfigMap(ctx context.Context, cn *v1al
 	return r.createConfigMap(ctx, cn)
 }
 
+// getSecret returns the Secret or nil if it is not found
+func (r *ComputeNodeReconciler) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// applySecret creates the Secret or updates the data of the current one
+func (r *ComputeNodeReconciler) applySecret(ctx context.Context, cur, exp *corev1.Secret) error {
+	if cur == nil {
+		return r.Create(ctx, exp)
+	}
+	if !reflect.DeepEqual(cur.Data, exp.Data) {
+		cur.Data = exp.Data
+		return r.Update(ctx, cur)
+	}
+	return nil
+}
+
+// reconcileAuthority keeps the Secret of the generated passwords, the generated ones are never rotated by the operator
+func (r *ComputeNodeReconciler) reconcileAuthority(ctx context.Context, cn *v1alpha1.ComputeNode) error {
+	cur, err := r.getSecret(ctx, cn.Namespace, reconcile.AuthoritySecretName(cn))
+	if err != nil {
+		return err
+	}
+	exp, err := r.Builder.BuildAuthoritySecret(ctx, cn, cur)
+	if err != nil || exp == nil {
+		return err
+	}
+	return r.applySecret(ctx, cur, exp)
+}
+
+// reconcileServerConfigSecret renders server.yaml with the resolved passwords into a Secret
+func (r *ComputeNodeReconciler) reconcileServerConfigSecret(ctx context.Context, cn *v1alpha1.ComputeNode) error {
+	secrets, err := getReferencedSecrets(ctx, r.Client, cn)
+	if err != nil {
+		return err
+	}
+	exp, err := r.Builder.BuildServerConfigSecret(ctx, cn, secrets)
+	if err != nil {
+		return err
+	}
+	cur, err := r.getSecret(ctx, exp.Namespace, exp.Name)
+	if err != nil {
+		return err
+	}
+	return r.applySecret(ctx, cur, exp)
+}
+
+// reconcileGovernance provisions the managed metadata repository and returns whether it reaches quorum,
+// it is always ready when the metadata repository is not managed
+func (r *ComputeNodeReconciler) reconcileGovernance(ctx context.Context, cn *v1alpha1.ComputeNode) (bool, error) {
+	if cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed == nil {
+		return true, nil
+	}
+
+	if err := r.reconcileGovernanceService(ctx, cn); err != nil {
+		return false, err
+	}
+	if err := r.reconcileGovernancePodDisruptionBudget(ctx, cn); err != nil {
+		return false, err
+	}
+	sts, err := r.reconcileGovernanceStatefulSet(ctx, cn)
+	if err != nil {
+		return false, err
+	}
+	return reconcile.GetGovernanceReadyConditionFromStatefulSet(sts).Status == v1alpha1.ConditionStatusTrue, nil
+}
+
+func (r *ComputeNodeReconciler) reconcileGovernanceService(ctx context.Context, cn *v1alpha1.ComputeNode) error {
+	exp := r.Builder.BuildGovernanceService(ctx, cn)
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: exp.Namespace, Name: exp.Name}, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.Create(ctx, exp)
+		}
+		return err
+	}
+
+	// only the ports and the selector are compared, the other fields are defaulted by the api server
+	if !reflect.DeepEqual(svc.Spec.Ports, exp.Spec.Ports) || !reflect.DeepEqual(svc.Spec.Selector, exp.Spec.Selector) {
+		svc.Spec.Ports = exp.Spec.Ports
+		svc.Spec.Selector = exp.Spec.Selector
+		return r.Update(ctx, svc)
+	}
+	return nil
+}
+
+func (r *ComputeNodeReconciler) reconcileGovernancePodDisruptionBudget(ctx context.Context, cn *v1alpha1.ComputeNode) error {
+	exp := r.Builder.BuildGovernancePodDisruptionBudget(ctx, cn)
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: exp.Namespace, Name: exp.Name}, pdb); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.Create(ctx, exp)
+		}
+		return err
+	}
+
+	if !reflect.DeepEqual(pdb.Spec, exp.Spec) {
+		pdb.Spec = exp.Spec
+		return r.Update(ctx, pdb)
+	}
+	return nil
+}
+
+// reconcileGovernanceStatefulSet returns the current StatefulSet, the immutable volume claim templates are kept as they are
+func (r *ComputeNodeReconciler) reconcileGovernanceStatefulSet(ctx context.Context, cn *v1alpha1.ComputeNode) (*appsv1.StatefulSet, error) {
+	exp := r.Builder.BuildGovernanceStatefulSet(ctx, cn)
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: exp.Namespace, Name: exp.Name}, sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return exp, r.Create(ctx, exp)
+		}
+		return nil, err
+	}
+
+	// the membership of Etcd is bootstrapped statically, the members can not be added or removed by scaling
+	if cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Type == v1alpha1.RepositoryTypeEtcd && *sts.Spec.Replicas != *exp.Spec.Replicas {
+		return nil, fmt.Errorf("the replicas of the managed Etcd can not be changed from %d to %d", *sts.Spec.Replicas, *exp.Spec.Replicas)
+	}
+
+	exp.Spec.VolumeClaimTemplates = sts.Spec.VolumeClaimTemplates
+	if !reflect.DeepEqual(sts.Spec, exp.Spec) {
+		sts.Spec = exp.Spec
+		return sts, r.Update(ctx, sts)
+	}
+	return sts, nil
+}
+
 func (r *ComputeNodeReconciler) reconcileStatus(ctx context.Context, cn *v1alpha1.ComputeNode) error {
 	selector, err := metav1.LabelSelectorAsSelector(cn.Spec.Selector)
 	if err != nil {
@@ -340,6 +520,31 @@ func (r *ComputeNodeReconciler) reconcileStatus(ctx context.Context, cn *v1alpha
 	}
 
 	status := reconcileComputeNodeStatus(podlist, service, cn)
+
+	deploy, err := r.getDeploymentByNamespacedName(ctx, types.NamespacedName{Namespace: cn.Namespace, Name: cn.Name})
+	if err != nil {
+		return err
+	}
+	if deploy != nil {
+		status.Conditions = setComputeNodeCondition(status.Conditions, reconcile.GetUpdatingConditionFromDeployment(deploy))
+	}
+
+	if cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed != nil {
+		sts := &appsv1.StatefulSet{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cn.Namespace, Name: reconcile.GovernanceName(cn)}, sts); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			sts = r.Builder.BuildGovernanceStatefulSet(ctx, cn)
+		}
+		status.Conditions = setComputeNodeCondition(status.Conditions, reconcile.GetGovernanceReadyConditionFromStatefulSet(sts))
+	}
+	secrets, err := getReferencedSecrets(ctx, r.Client, cn)
+	if err != nil {
+		return err
+	}
+	setProbeStatus(status, cn, podlist, secrets)
+
 	rt, err := r.getRuntimeComputeNode(ctx, types.NamespacedName{
 		Namespace: cn.Namespace,
 		Name:      cn.Name,
@@ -412,6 +617,21 @@ func updateComputeNodeStatusCondition(conditions []v1alpha1.ComputeNodeCondition
 	return conditions
 }
 
+// setComputeNodeCondition replaces the condition of the same type, the transition time is kept if the status is not changed
+func setComputeNodeCondition(conditions []v1alpha1.ComputeNodeCondition, cond v1alpha1.ComputeNodeCondition) []v1alpha1.ComputeNodeCondition {
+	for i := range conditions {
+		if conditions[i].Type != cond.Type {
+			continue
+		}
+		if conditions[i].Status == cond.Status {
+			cond.LastTransitionTime = conditions[i].LastTransitionTime
+		}
+		conditions[i] = cond
+		return conditions
+	}
+	return append(conditions, cond)
+}
+
 func reconcileComputeNodeStatus(podlist *corev1.PodList, svc *corev1.Service, cn *v1alpha1.ComputeNode) *v1alpha1.ComputeNodeStatus {
 	conds := reconcile.GetConditionFromPods(podlist)
 
@@ -0,0 +1,448 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes"
+	reconcile "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/computenode"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+	mock_shardingsphere "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere/mocks"
+
+	"bou.ke/monkey"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("ComputeNode Controller Mock Test", func() {
+	var (
+		c            client.Client
+		cnReconciler *ComputeNodeReconciler
+		namespaced   = types.NamespacedName{Name: defaultTestComputeNode, Namespace: defaultTestNamespace}
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(policyv1.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		cnReconciler = &ComputeNodeReconciler{
+			Client:    c,
+			Scheme:    scheme,
+			Log:       logf.Log,
+			Builder:   reconcile.NewBuilder(),
+			Resources: kubernetes.NewResources(c),
+		}
+
+		maxUnavailable := intstr.FromString("25%")
+		cn := &v1alpha1.ComputeNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      defaultTestComputeNode,
+				Namespace: defaultTestNamespace,
+				Labels:    map[string]string{"app": "proxy"},
+			},
+			Spec: v1alpha1.ComputeNodeSpec{
+				ServerVersion: "5.3.1",
+				Replicas:      2,
+				Selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"app": "proxy"}},
+				Env: []corev1.EnvVar{{
+					Name: "DB_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "proxy-secret"},
+							Key:                  "password",
+						},
+					},
+				}},
+				RollingUpdate: &v1alpha1.ComputeNodeRollingUpdate{MaxUnavailable: &maxUnavailable},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "proxy-secret", Namespace: defaultTestNamespace},
+			Data:       map[string][]byte{"password": []byte("p1")},
+		}
+		Expect(c.Create(ctx, cn)).Should(Succeed())
+		Expect(c.Create(ctx, secret)).Should(Succeed())
+	})
+
+	reconcileOnce := func() {
+		_, err := cnReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+	}
+	getDeployment := func() *appsv1.Deployment {
+		deploy := &appsv1.Deployment{}
+		Expect(c.Get(ctx, namespaced, deploy)).Should(Succeed())
+		return deploy
+	}
+	getUpdating := func() *v1alpha1.ComputeNodeCondition {
+		cn := &v1alpha1.ComputeNode{}
+		Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+		for i := range cn.Status.Conditions {
+			if cn.Status.Conditions[i].Type == v1alpha1.ComputeNodeConditionUpdating {
+				return &cn.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	It("should roll the pods when the configuration or the referenced secret changes", func() {
+		reconcileOnce()
+		deploy := getDeployment()
+		Expect(*deploy.Spec.Strategy.RollingUpdate.MaxUnavailable).To(Equal(intstr.FromString("25%")))
+		hash := deploy.Spec.Template.Annotations[reconcile.AnnotationConfigHash]
+		Expect(hash).NotTo(BeEmpty())
+
+		reconcileOnce()
+		Expect(getDeployment().Spec.Template.Annotations[reconcile.AnnotationConfigHash]).To(Equal(hash))
+
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: "proxy-secret", Namespace: defaultTestNamespace}, secret)).Should(Succeed())
+		secret.Data["password"] = []byte("p2")
+		Expect(c.Update(ctx, secret)).Should(Succeed())
+		reconcileOnce()
+		secretHash := getDeployment().Spec.Template.Annotations[reconcile.AnnotationConfigHash]
+		Expect(secretHash).NotTo(Equal(hash))
+
+		cn := &v1alpha1.ComputeNode{}
+		Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+		cn.Spec.Bootstrap.ServerConfig.Props = v1alpha1.Properties{"proxy-frontend-flush-threshold": "64"}
+		Expect(c.Update(ctx, cn)).Should(Succeed())
+		reconcileOnce()
+		Expect(getDeployment().Spec.Template.Annotations[reconcile.AnnotationConfigHash]).NotTo(Equal(secretHash))
+
+		secret = &corev1.Secret{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: defaultTestComputeNode + "-server-config", Namespace: defaultTestNamespace}, secret)).Should(Succeed())
+		Expect(string(secret.Data["server.yaml"])).To(ContainSubstring("proxy-frontend-flush-threshold"))
+	})
+
+	It("should report the rollout in the Updating condition", func() {
+		reconcileOnce()
+
+		deploy := getDeployment()
+		deploy.Status = appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2}
+		Expect(c.Status().Update(ctx, deploy)).Should(Succeed())
+		reconcileOnce()
+		cond := getUpdating()
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(v1alpha1.ConditionStatusTrue))
+		Expect(cond.Reason).To(Equal("PodUpdating"))
+		Expect(cond.Message).To(Equal("1 of 2 pods are updated"))
+
+		deploy = getDeployment()
+		deploy.Status = appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+		Expect(c.Status().Update(ctx, deploy)).Should(Succeed())
+		reconcileOnce()
+		cond = getUpdating()
+		Expect(cond.Status).To(Equal(v1alpha1.ConditionStatusFalse))
+		Expect(cond.Reason).To(Equal("RolloutComplete"))
+	})
+
+	Context("SQL-level probes", func() {
+		getCondition := func(t v1alpha1.ComputeNodeConditionType) *v1alpha1.ComputeNodeCondition {
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			for i := range cn.Status.Conditions {
+				if cn.Status.Conditions[i].Type == t {
+					return &cn.Status.Conditions[i]
+				}
+			}
+			return nil
+		}
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockSS = mock_shardingsphere.NewMockIServer(mockCtrl)
+
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			cn.Spec.PortBindings = []v1alpha1.PortBinding{{Name: "server", ContainerPort: 3307, ServicePort: 3307}}
+			cn.Spec.Bootstrap.ServerConfig.Mode.Type = v1alpha1.ModeTypeCluster
+			cn.Spec.Bootstrap.ServerConfig.Authority.Users = []v1alpha1.ComputeNodeUser{{User: "root@%", Password: "root"}}
+			Expect(c.Update(ctx, cn)).Should(Succeed())
+
+			for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("proxy-%d", i),
+						Namespace: defaultTestNamespace,
+						Labels:    map[string]string{"app": "proxy"},
+					},
+					Status: corev1.PodStatus{
+						Phase:      corev1.PodRunning,
+						PodIP:      ip,
+						Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+					},
+				}
+				Expect(c.Create(ctx, pod)).Should(Succeed())
+			}
+
+			mockSS.EXPECT().ShowComputeNodes().Return([]*shardingsphere.ComputeNodeInstance{
+				{InstanceID: "id-0", Host: "10.0.0.1", Port: 3307, Status: "OK", ModeType: "Cluster"},
+				{InstanceID: "id-1", Host: "10.0.0.2", Port: 3307, Status: "OK", ModeType: "Cluster"},
+			}, nil).AnyTimes()
+			mockSS.EXPECT().ShowDatabases().Return([]string{"sharding_db"}, nil).AnyTimes()
+			mockSS.EXPECT().ShowStorageUnits("sharding_db").Return([]*shardingsphere.StorageUnit{{Name: "ds_1"}, {Name: "ds_0"}}, nil).AnyTimes()
+			mockSS.EXPECT().Close().Return(nil).AnyTimes()
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+			monkey.UnpatchAll()
+		})
+
+		It("should report the registry and the storage units of every pod", func() {
+			monkey.Patch(shardingsphere.NewServerWithDeadline, func(_, _ string, _ uint, _, _ string, deadline time.Time) (shardingsphere.IServer, error) {
+				Expect(deadline).To(BeTemporally("~", time.Now().Add(defaultProbeTimeout), time.Second))
+				return mockSS, nil
+			})
+			mockSS.EXPECT().PingStorageUnit("sharding_db", gomock.Any()).Return(nil).MinTimes(4)
+			reconcileOnce()
+
+			Expect(getCondition(v1alpha1.ComputeNodeConditionRegistryConnected).Status).To(Equal(v1alpha1.ConditionStatusTrue))
+			Expect(getCondition(v1alpha1.ComputeNodeConditionStorageUnitsHealthy).Status).To(Equal(v1alpha1.ConditionStatusTrue))
+
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			Expect(cn.Status.LogicDatabases).To(Equal([]v1alpha1.LogicDatabaseStatus{{Name: "sharding_db", StorageUnits: []string{"ds_0", "ds_1"}}}))
+			Expect(cn.Status.Proxies).To(HaveLen(2))
+			Expect(cn.Status.Proxies[1]).To(Equal(v1alpha1.ProxyInstanceStatus{
+				PodName:             "proxy-1",
+				PodIP:               "10.0.0.2",
+				InstanceID:          "id-1",
+				State:               "OK",
+				RegistryConnected:   true,
+				StorageUnitsHealthy: true,
+			}))
+		})
+
+		It("should report the pod failed to probe", func() {
+			monkey.Patch(shardingsphere.NewServerWithDeadline, func(_, host string, _ uint, _, _ string, _ time.Time) (shardingsphere.IServer, error) {
+				if host == "10.0.0.2" {
+					return nil, errors.New("connection refused")
+				}
+				return mockSS, nil
+			})
+			mockSS.EXPECT().PingStorageUnit("sharding_db", gomock.Any()).Return(nil).AnyTimes()
+			reconcileOnce()
+
+			cond := getCondition(v1alpha1.ComputeNodeConditionRegistryConnected)
+			Expect(cond.Status).To(Equal(v1alpha1.ConditionStatusFalse))
+			Expect(cond.Reason).To(Equal("PodDisconnected"))
+			Expect(cond.Message).To(Equal("1 of 2 pods are connected to the registry; proxy-1: connect failed: connection refused"))
+			Expect(getCondition(v1alpha1.ComputeNodeConditionStorageUnitsHealthy).Status).To(Equal(v1alpha1.ConditionStatusFalse))
+		})
+
+		It("should report the storage units unreachable through the pods", func() {
+			monkey.Patch(shardingsphere.NewServerWithDeadline, func(_, _ string, _ uint, _, _ string, _ time.Time) (shardingsphere.IServer, error) {
+				return mockSS, nil
+			})
+			mockSS.EXPECT().PingStorageUnit("sharding_db", "ds_0").Return(nil).AnyTimes()
+			mockSS.EXPECT().PingStorageUnit("sharding_db", "ds_1").Return(errors.New("connection refused")).AnyTimes()
+			reconcileOnce()
+
+			cond := getCondition(v1alpha1.ComputeNodeConditionStorageUnitsHealthy)
+			Expect(cond.Status).To(Equal(v1alpha1.ConditionStatusFalse))
+			Expect(cond.Reason).To(Equal("StorageUnitsUnhealthy"))
+			Expect(cond.Message).To(ContainSubstring("proxy-0: storage units are unreachable: sharding_db.ds_1: connection refused"))
+			Expect(getCondition(v1alpha1.ComputeNodeConditionRegistryConnected).Status).To(Equal(v1alpha1.ConditionStatusTrue))
+
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			Expect(cn.Status.LogicDatabases).To(Equal([]v1alpha1.LogicDatabaseStatus{{Name: "sharding_db", StorageUnits: []string{"ds_0", "ds_1"}}}))
+			Expect(cn.Status.Proxies[0].StorageUnitsHealthy).To(BeFalse())
+		})
+	})
+
+	Context("Managed governance", func() {
+		BeforeEach(func() {
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			cn.Spec.Bootstrap.ServerConfig.Mode = v1alpha1.ComputeNodeServerMode{
+				Type: v1alpha1.ModeTypeCluster,
+				Repository: v1alpha1.Repository{
+					Type:    v1alpha1.RepositoryTypeZookeeper,
+					Managed: &v1alpha1.ManagedRepository{Replicas: 3},
+				},
+			}
+			Expect(c.Update(ctx, cn)).Should(Succeed())
+		})
+
+		It("should start the proxies after the governance reaches quorum", func() {
+			governance := types.NamespacedName{Name: defaultTestComputeNode + "-governance", Namespace: defaultTestNamespace}
+
+			reconcileOnce()
+			Expect(c.Get(ctx, governance, &corev1.Service{})).Should(Succeed())
+			Expect(c.Get(ctx, governance, &policyv1.PodDisruptionBudget{})).Should(Succeed())
+			sts := &appsv1.StatefulSet{}
+			Expect(c.Get(ctx, governance, sts)).Should(Succeed())
+			Expect(c.Get(ctx, namespaced, &appsv1.Deployment{})).ShouldNot(Succeed())
+
+			secret := &corev1.Secret{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: defaultTestComputeNode + "-server-config", Namespace: defaultTestNamespace}, secret)).Should(Succeed())
+			Expect(string(secret.Data["server.yaml"])).To(ContainSubstring("test-compute-node-governance-0.test-compute-node-governance.test-namespace.svc:2181"))
+
+			sts.Status.ReadyReplicas = 2
+			Expect(c.Status().Update(ctx, sts)).Should(Succeed())
+			reconcileOnce()
+			Expect(c.Get(ctx, namespaced, &appsv1.Deployment{})).Should(Succeed())
+
+			reconcileOnce()
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			var cond *v1alpha1.ComputeNodeCondition
+			for i := range cn.Status.Conditions {
+				if cn.Status.Conditions[i].Type == v1alpha1.ComputeNodeConditionGovernanceReady {
+					cond = &cn.Status.Conditions[i]
+				}
+			}
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(v1alpha1.ConditionStatusTrue))
+			Expect(cond.Message).To(Equal("2 of 3 members are ready, the quorum is 2"))
+		})
+
+		It("should reject changing the replicas of Etcd", func() {
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Type = v1alpha1.RepositoryTypeEtcd
+			Expect(c.Update(ctx, cn)).Should(Succeed())
+			reconcileOnce()
+
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed.Replicas = 5
+			Expect(c.Update(ctx, cn)).Should(Succeed())
+			_, err := cnReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+			Expect(err).To(MatchError("the replicas of the managed Etcd can not be changed from 3 to 5"))
+
+			sts := &appsv1.StatefulSet{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: defaultTestComputeNode + "-governance", Namespace: defaultTestNamespace}, sts)).Should(Succeed())
+			Expect(*sts.Spec.Replicas).To(Equal(int32(3)))
+		})
+	})
+
+	Context("Secret-backed users", func() {
+		serverConfig := types.NamespacedName{Name: defaultTestComputeNode + "-server-config", Namespace: defaultTestNamespace}
+		getServerYAML := func() string {
+			secret := &corev1.Secret{}
+			Expect(c.Get(ctx, serverConfig, secret)).Should(Succeed())
+			return string(secret.Data["server.yaml"])
+		}
+
+		BeforeEach(func() {
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			cn.Spec.Bootstrap.ServerConfig.Authority.Users = []v1alpha1.ComputeNodeUser{
+				{User: "root@%"},
+				{User: "app@%", PasswordSecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "proxy-secret"},
+					Key:                  "password",
+				}},
+			}
+			Expect(c.Update(ctx, cn)).Should(Succeed())
+		})
+
+		It("should mount server.yaml from a Secret with the resolved passwords", func() {
+			reconcileOnce()
+
+			authority := &corev1.Secret{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: defaultTestComputeNode + "-authority", Namespace: defaultTestNamespace}, authority)).Should(Succeed())
+			generated := string(authority.Data["root"])
+			Expect(generated).NotTo(BeEmpty())
+
+			Expect(getServerYAML()).To(ContainSubstring("password: " + generated))
+			Expect(getServerYAML()).To(ContainSubstring("password: p1"))
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, namespaced, cm)).Should(Succeed())
+			Expect(cm.Data).NotTo(HaveKey("server.yaml"))
+
+			volume := getDeployment().Spec.Template.Spec.Volumes[0]
+			Expect(volume.Projected).NotTo(BeNil())
+			Expect(volume.Projected.Sources[1].Secret.Name).To(Equal(serverConfig.Name))
+
+			reconcileOnce()
+			Expect(c.Get(ctx, types.NamespacedName{Name: authority.Name, Namespace: defaultTestNamespace}, authority)).Should(Succeed())
+			Expect(string(authority.Data["root"])).To(Equal(generated))
+		})
+
+		It("should roll the pods when a password is rotated", func() {
+			reconcileOnce()
+			hash := getDeployment().Spec.Template.Annotations[reconcile.AnnotationConfigHash]
+
+			secret := &corev1.Secret{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: "proxy-secret", Namespace: defaultTestNamespace}, secret)).Should(Succeed())
+			secret.Data["password"] = []byte("rotated")
+			Expect(c.Update(ctx, secret)).Should(Succeed())
+			reconcileOnce()
+
+			Expect(getServerYAML()).To(ContainSubstring("password: rotated"))
+			Expect(getDeployment().Spec.Template.Annotations[reconcile.AnnotationConfigHash]).NotTo(Equal(hash))
+		})
+
+		It("should fail without leaking a password into the status", func() {
+			Expect(c.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "proxy-secret", Namespace: defaultTestNamespace}})).Should(Succeed())
+			_, err := cnReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+			Expect(err).NotTo(BeNil())
+			Expect(c.Get(ctx, serverConfig, &corev1.Secret{})).ShouldNot(Succeed())
+
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			authority := &corev1.Secret{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: defaultTestComputeNode + "-authority", Namespace: defaultTestNamespace}, authority)).Should(Succeed())
+			for _, cond := range cn.Status.Conditions {
+				Expect(cond.Message).NotTo(ContainSubstring(string(authority.Data["root"])))
+			}
+		})
+	})
+
+	Context("Frontend TLS", func() {
+		It("should enable the frontend SSL and roll the pods", func() {
+			reconcileOnce()
+			hash := getDeployment().Spec.Template.Annotations[reconcile.AnnotationConfigHash]
+
+			cn := &v1alpha1.ComputeNode{}
+			Expect(c.Get(ctx, namespaced, cn)).Should(Succeed())
+			cn.Spec.TLS = &v1alpha1.ComputeNodeTLS{Versions: []string{"TLSv1.3"}}
+			Expect(c.Update(ctx, cn)).Should(Succeed())
+			reconcileOnce()
+
+			server := &corev1.Secret{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: defaultTestComputeNode + "-server-config", Namespace: defaultTestNamespace}, server)).Should(Succeed())
+			Expect(string(server.Data["server.yaml"])).To(ContainSubstring("proxy-frontend-ssl-enabled"))
+			Expect(string(server.Data["server.yaml"])).To(ContainSubstring("proxy-frontend-ssl-version: TLSv1.3"))
+			Expect(getDeployment().Spec.Template.Annotations[reconcile.AnnotationConfigHash]).NotTo(Equal(hash))
+		})
+	})
+})
@@ -0,0 +1,272 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultProbeTimeout is the deadline of the SQL-level probes of all pods, including connecting and every query
+	defaultProbeTimeout = 5 * time.Second
+
+	// computeNodeStateOK is the state of a healthy instance returned by `SHOW COMPUTE NODES`
+	computeNodeStateOK = "OK"
+)
+
+// proxyProbeResult is the result of the SQL-level probe of a proxy pod
+type proxyProbeResult struct {
+	status v1alpha1.ProxyInstanceStatus
+	// databases are the storage unit names of every logic database seen by the pod
+	databases map[string][]string
+}
+
+// probeProxies connects to every ready proxy pod through DistSQL, the results are sorted by pod name.
+// The pods not answering in defaultProbeTimeout are reported as failed, their connections are closed at the deadline.
+func probeProxies(cn *v1alpha1.ComputeNode, podlist *corev1.PodList, secrets []*corev1.Secret) ([]*proxyProbeResult, error) {
+	driver, username, password, err := computeNodeCredential(cn, "", secrets)
+	if err != nil {
+		return nil, err
+	}
+	if len(cn.Spec.PortBindings) == 0 {
+		return nil, fmt.Errorf("no port binding in compute node %s/%s", cn.Namespace, cn.Name)
+	}
+	port := uint(cn.Spec.PortBindings[0].ContainerPort)
+
+	pods := []*corev1.Pod{}
+	for i := range podlist.Items {
+		pod := &podlist.Items[i]
+		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" && isTrueReadyPod(pod) {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	deadline := time.Now().Add(defaultProbeTimeout)
+	ch := make(chan *proxyProbeResult, len(pods))
+	for _, pod := range pods {
+		go func(pod *corev1.Pod) {
+			ch <- probeProxy(driver, pod, port, username, password, deadline)
+		}(pod)
+	}
+
+	probed := map[string]*proxyProbeResult{}
+	timeout := time.After(time.Until(deadline))
+	for len(probed) < len(pods) {
+		select {
+		case result := <-ch:
+			probed[result.status.PodName] = result
+		case <-timeout:
+			for _, pod := range pods {
+				if _, ok := probed[pod.Name]; !ok {
+					probed[pod.Name] = &proxyProbeResult{status: v1alpha1.ProxyInstanceStatus{
+						PodName: pod.Name,
+						PodIP:   pod.Status.PodIP,
+						Message: "probe timed out",
+					}}
+				}
+			}
+		}
+	}
+
+	results := make([]*proxyProbeResult, 0, len(pods))
+	for _, pod := range pods {
+		results = append(results, probed[pod.Name])
+	}
+	return results, nil
+}
+
+// probeProxy runs `SHOW COMPUTE NODES` and `SHOW STORAGE UNITS` of every logic database on the pod,
+// and queries every storage unit through the pod, since the storage units listed by the registry may be unreachable.
+func probeProxy(driver string, pod *corev1.Pod, port uint, username, password string, deadline time.Time) *proxyProbeResult {
+	result := &proxyProbeResult{status: v1alpha1.ProxyInstanceStatus{
+		PodName: pod.Name,
+		PodIP:   pod.Status.PodIP,
+	}}
+
+	ss, err := shardingsphere.NewServerWithDeadline(driver, pod.Status.PodIP, port, username, password, deadline)
+	if err != nil {
+		result.status.Message = fmt.Sprintf("connect failed: %s", err)
+		return result
+	}
+	defer ss.Close()
+
+	instances, err := ss.ShowComputeNodes()
+	if err != nil {
+		result.status.Message = err.Error()
+		return result
+	}
+	for _, instance := range instances {
+		if instance.Host == pod.Status.PodIP && instance.Port == port {
+			result.status.InstanceID = instance.InstanceID
+			result.status.State = instance.Status
+			result.status.RegistryConnected = strings.EqualFold(instance.ModeType, string(v1alpha1.ModeTypeCluster)) && instance.Status == computeNodeStateOK
+		}
+	}
+	if result.status.InstanceID == "" {
+		result.status.Message = "instance is not found in the compute nodes of the cluster"
+	}
+
+	databases, err := ss.ShowDatabases()
+	if err != nil {
+		result.status.Message = err.Error()
+		return result
+	}
+	result.databases = map[string][]string{}
+	unreachable := []string{}
+	for _, db := range databases {
+		units, err := ss.ShowStorageUnits(db)
+		if err != nil {
+			result.status.Message = err.Error()
+			result.databases = nil
+			return result
+		}
+		names := make([]string, 0, len(units))
+		for _, unit := range units {
+			names = append(names, unit.Name)
+			if err := ss.PingStorageUnit(db, unit.Name); err != nil {
+				unreachable = append(unreachable, fmt.Sprintf("%s.%s: %s", db, unit.Name, err))
+			}
+		}
+		sort.Strings(names)
+		result.databases[db] = names
+	}
+	if len(unreachable) != 0 {
+		sort.Strings(unreachable)
+		result.status.Message = fmt.Sprintf("storage units are unreachable: %s", strings.Join(unreachable, "; "))
+		return result
+	}
+	result.status.StorageUnitsHealthy = true
+	return result
+}
+
+// setProbeStatus probes the ready pods and sets the per-pod results, the logic databases
+// and the RegistryConnected and StorageUnitsHealthy conditions of the status.
+// RegistryConnected is only set in Cluster mode. The passwords are resolved from the secrets.
+func setProbeStatus(status *v1alpha1.ComputeNodeStatus, cn *v1alpha1.ComputeNode, podlist *corev1.PodList, secrets []*corev1.Secret) {
+	results, err := probeProxies(cn, podlist, secrets)
+
+	var registry, storageUnits v1alpha1.ComputeNodeCondition
+	switch {
+	case err != nil:
+		registry = newProbeCondition(v1alpha1.ComputeNodeConditionRegistryConnected, v1alpha1.ConditionStatusUnknown, "ProbeNotConfigured", err.Error())
+		storageUnits = newProbeCondition(v1alpha1.ComputeNodeConditionStorageUnitsHealthy, v1alpha1.ConditionStatusUnknown, "ProbeNotConfigured", err.Error())
+	case len(results) == 0:
+		registry = newProbeCondition(v1alpha1.ComputeNodeConditionRegistryConnected, v1alpha1.ConditionStatusUnknown, "NoReadyPod", "No ready pod to probe")
+		storageUnits = newProbeCondition(v1alpha1.ComputeNodeConditionStorageUnitsHealthy, v1alpha1.ConditionStatusUnknown, "NoReadyPod", "No ready pod to probe")
+	default:
+		registry = getRegistryConnectedCondition(results)
+		storageUnits = getStorageUnitsHealthyCondition(results)
+	}
+
+	status.Proxies = nil
+	for _, result := range results {
+		status.Proxies = append(status.Proxies, result.status)
+	}
+	status.LogicDatabases = getLogicDatabases(results)
+
+	if cn.Spec.Bootstrap.ServerConfig.Mode.Type == v1alpha1.ModeTypeCluster {
+		status.Conditions = setComputeNodeCondition(status.Conditions, registry)
+	}
+	status.Conditions = setComputeNodeCondition(status.Conditions, storageUnits)
+}
+
+func getRegistryConnectedCondition(results []*proxyProbeResult) v1alpha1.ComputeNodeCondition {
+	failures := []string{}
+	for _, result := range results {
+		if result.status.RegistryConnected {
+			continue
+		}
+		msg := result.status.Message
+		if msg == "" {
+			msg = fmt.Sprintf("instance state is %s", result.status.State)
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", result.status.PodName, msg))
+	}
+
+	connected := fmt.Sprintf("%d of %d pods are connected to the registry", len(results)-len(failures), len(results))
+	if len(failures) != 0 {
+		return newProbeCondition(v1alpha1.ComputeNodeConditionRegistryConnected, v1alpha1.ConditionStatusFalse, "PodDisconnected", fmt.Sprintf("%s; %s", connected, strings.Join(failures, "; ")))
+	}
+	return newProbeCondition(v1alpha1.ComputeNodeConditionRegistryConnected, v1alpha1.ConditionStatusTrue, "AllPodsConnected", connected)
+}
+
+func getStorageUnitsHealthyCondition(results []*proxyProbeResult) v1alpha1.ComputeNodeCondition {
+	failures := []string{}
+	var expected *proxyProbeResult
+	for _, result := range results {
+		if !result.status.StorageUnitsHealthy {
+			failures = append(failures, fmt.Sprintf("%s: %s", result.status.PodName, result.status.Message))
+			continue
+		}
+		if expected == nil {
+			expected = result
+		}
+	}
+	if len(failures) != 0 {
+		return newProbeCondition(v1alpha1.ComputeNodeConditionStorageUnitsHealthy, v1alpha1.ConditionStatusFalse, "StorageUnitsUnhealthy", strings.Join(failures, "; "))
+	}
+
+	// every pod should see the same logic databases and storage units from the governance center
+	for _, result := range results {
+		if !reflect.DeepEqual(result.databases, expected.databases) {
+			return newProbeCondition(v1alpha1.ComputeNodeConditionStorageUnitsHealthy, v1alpha1.ConditionStatusFalse, "StorageUnitsInconsistent",
+				fmt.Sprintf("the storage units on %s differ from %s", result.status.PodName, expected.status.PodName))
+		}
+	}
+	return newProbeCondition(v1alpha1.ComputeNodeConditionStorageUnitsHealthy, v1alpha1.ConditionStatusTrue, "StorageUnitsConsistent",
+		fmt.Sprintf("%d pods serve the storage units of %d logic databases", len(results), len(expected.databases)))
+}
+
+// getLogicDatabases returns the logic databases seen by the first pod listing the storage units, sorted by name.
+func getLogicDatabases(results []*proxyProbeResult) []v1alpha1.LogicDatabaseStatus {
+	for _, result := range results {
+		if result.databases == nil {
+			continue
+		}
+		databases := make([]v1alpha1.LogicDatabaseStatus, 0, len(result.databases))
+		for name, units := range result.databases {
+			databases = append(databases, v1alpha1.LogicDatabaseStatus{Name: name, StorageUnits: units})
+		}
+		sort.Slice(databases, func(i, j int) bool { return databases[i].Name < databases[j].Name })
+		return databases
+	}
+	return nil
+}
+
+func newProbeCondition(t v1alpha1.ComputeNodeConditionType, status v1alpha1.ConditionStatus, reason, message string) v1alpha1.ComputeNodeCondition {
+	now := metav1.NewTime(time.Now())
+	return v1alpha1.ComputeNodeCondition{
+		Type:               t,
+		Status:             status,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	}
+}
@@ -0,0 +1,333 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/databaserule"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/strings/slices"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DatabaseRuleControllerName = "database-rule-controller"
+)
+
+// DatabaseRuleReconciler is a controller for the rules of logic databases
+type DatabaseRuleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	Service  service.Service
+}
+
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=databaserules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=databaserules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=databaserules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=computenodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=event,verbs=create;patch
+
+// Reconcile handles main function of this controller
+func (r *DatabaseRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues(DatabaseRuleControllerName, req.NamespacedName)
+
+	dr := &v1alpha1.DatabaseRule{}
+	if err := r.Get(ctx, req.NamespacedName, dr); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if dr.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !slices.Contains(dr.ObjectMeta.Finalizers, FinalizerName) {
+			dr.ObjectMeta.Finalizers = append(dr.ObjectMeta.Finalizers, FinalizerName)
+			if err := r.Update(ctx, dr); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if slices.Contains(dr.ObjectMeta.Finalizers, FinalizerName) {
+		return r.finalize(ctx, dr)
+	}
+
+	if err := r.reconcile(ctx, dr); err != nil {
+		logger.Error(err, "Failed to reconcile database rule")
+		r.Recorder.Event(dr, corev1.EventTypeWarning, "ReconcileFailed", err.Error())
+		return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
+}
+
+func (r *DatabaseRuleReconciler) finalize(ctx context.Context, dr *v1alpha1.DatabaseRule) (ctrl.Result, error) {
+	if dr.Status.Phase != v1alpha1.DatabaseRulePhaseDeleting {
+		dr.Status.Phase = v1alpha1.DatabaseRulePhaseDeleting
+		if err := r.Status().Update(ctx, dr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	ss, err := r.getShardingSphereServer(ctx, dr)
+	if err != nil {
+		// the rules are gone with the compute node
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+		}
+	} else {
+		defer ss.Close()
+		if err := r.dropRules(dr, ss, dr.Status.Rules); err != nil {
+			r.Recorder.Event(dr, corev1.EventTypeWarning, "DropRulesFailed", err.Error())
+			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+		}
+	}
+
+	dr.ObjectMeta.Finalizers = slices.Filter([]string{}, dr.ObjectMeta.Finalizers, func(f string) bool {
+		return f != FinalizerName
+	})
+	if err := r.Update(ctx, dr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// dropRules drops the given rules which still exist in the logic database
+func (r *DatabaseRuleReconciler) dropRules(dr *v1alpha1.DatabaseRule, ss shardingsphere.IServer, rules []v1alpha1.AppliedRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	rules = append([]v1alpha1.AppliedRule{}, rules...)
+	databaserule.SortForDrop(rules)
+
+	existing, err := showRules(ss, dr.Spec.DatabaseName, rules)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if existing[ruleKey(rule.Type, rule.Name)] == nil {
+			continue
+		}
+		if err := ss.ExecuteDistSQL(dr.Spec.DatabaseName, databaserule.DropStatement(rule.Type, rule.Name)); err != nil {
+			return fmt.Errorf("drop %s rule %s failed: %w", rule.Type, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// reconcile compares the rules in spec with the rules in the logic database and the rules applied before.
+// A rule missing in the logic database will be created, a rule whose definition changed will be altered,
+// and a rule removed from spec will be dropped. A managed rule dropped or altered outside of the DatabaseRule,
+// whose rows shown by the logic database differ from spec, is reported as drifted before it is applied again.
+func (r *DatabaseRuleReconciler) reconcile(ctx context.Context, dr *v1alpha1.DatabaseRule) error {
+	ss, err := r.getShardingSphereServer(ctx, dr)
+	if err != nil {
+		return r.updateStatus(ctx, dr, dr.Status.Rules, drift{}, err)
+	}
+	defer ss.Close()
+
+	stmts := databaserule.Build(dr)
+
+	applied := map[string]v1alpha1.AppliedRule{}
+	for _, rule := range dr.Status.Rules {
+		applied[ruleKey(rule.Type, rule.Name)] = rule
+	}
+
+	desired := map[string]bool{}
+	desiredRules := make([]v1alpha1.AppliedRule, 0, len(stmts))
+	for _, s := range stmts {
+		desired[ruleKey(s.Type, s.Name)] = true
+		desiredRules = append(desiredRules, v1alpha1.AppliedRule{Type: s.Type, Name: s.Name})
+	}
+
+	// rules removed from spec
+	removed := []v1alpha1.AppliedRule{}
+	kept := []v1alpha1.AppliedRule{}
+	for _, rule := range dr.Status.Rules {
+		if desired[ruleKey(rule.Type, rule.Name)] {
+			kept = append(kept, rule)
+		} else {
+			removed = append(removed, rule)
+		}
+	}
+	if err := r.dropRules(dr, ss, removed); err != nil {
+		return r.updateStatus(ctx, dr, dr.Status.Rules, drift{}, err)
+	}
+
+	existing, err := showRules(ss, dr.Spec.DatabaseName, desiredRules)
+	if err != nil {
+		return r.updateStatus(ctx, dr, kept, drift{}, err)
+	}
+
+	var (
+		drifted drift
+		rules   = make([]v1alpha1.AppliedRule, 0, len(stmts))
+	)
+	for i, s := range stmts {
+		key := ruleKey(s.Type, s.Name)
+		rule, managed := applied[key]
+
+		var distSQL string
+		switch shown := existing[key]; {
+		case shown == nil:
+			if managed {
+				drifted.dropped = append(drifted.dropped, key)
+			}
+			distSQL = s.Create
+		case !managed || rule.Hash != s.Hash():
+			distSQL = s.Alter
+		case !s.Matches(shown.Rows):
+			drifted.altered = append(drifted.altered, key)
+			distSQL = s.Alter
+		}
+
+		if distSQL != "" {
+			if err := ss.ExecuteDistSQL(dr.Spec.DatabaseName, distSQL); err != nil {
+				// keep the rules not applied yet as they were, they will be retried in the next reconciliation
+				for _, s := range stmts[i:] {
+					if rule, ok := applied[ruleKey(s.Type, s.Name)]; ok {
+						rules = append(rules, rule)
+					}
+				}
+				return r.updateStatus(ctx, dr, rules, drifted, fmt.Errorf("apply %s rule %s failed: %w", s.Type, s.Name, err))
+			}
+		}
+
+		rules = append(rules, v1alpha1.AppliedRule{Type: s.Type, Name: s.Name, Hash: s.Hash()})
+	}
+
+	if msg := drifted.String(); msg != "" {
+		r.Recorder.Eventf(dr, corev1.EventTypeWarning, "RulesDrifted", "%s and have been applied again", msg)
+	}
+
+	return r.updateStatus(ctx, dr, rules, drifted, nil)
+}
+
+// drift is the keys of the managed rules dropped or altered outside of the DatabaseRule
+type drift struct {
+	dropped []string
+	altered []string
+}
+
+func (d drift) String() string {
+	msgs := []string{}
+	if len(d.dropped) > 0 {
+		msgs = append(msgs, fmt.Sprintf("rules %s were dropped", strings.Join(d.dropped, ",")))
+	}
+	if len(d.altered) > 0 {
+		msgs = append(msgs, fmt.Sprintf("rules %s were altered", strings.Join(d.altered, ",")))
+	}
+	if len(msgs) == 0 {
+		return ""
+	}
+	return strings.Join(msgs, ", ") + " outside of the DatabaseRule"
+}
+
+func (r *DatabaseRuleReconciler) updateStatus(ctx context.Context, dr *v1alpha1.DatabaseRule, rules []v1alpha1.AppliedRule, drifted drift, syncErr error) error {
+	now := metav1.Now()
+	dr.Status.ObservedGeneration = dr.Generation
+	dr.Status.Rules = rules
+
+	synced := &v1alpha1.DatabaseRuleCondition{
+		Type:           v1alpha1.DatabaseRuleConditionTypeSynced,
+		Status:         corev1.ConditionTrue,
+		LastUpdateTime: now,
+		Reason:         "Synced",
+		Message:        "All rules are applied to the logic database",
+	}
+	dr.Status.Phase = v1alpha1.DatabaseRulePhaseSynced
+	if syncErr != nil {
+		synced.Status = corev1.ConditionFalse
+		synced.Reason = "SyncFailed"
+		synced.Message = syncErr.Error()
+		dr.Status.Phase = v1alpha1.DatabaseRulePhaseFailed
+	}
+	dr.Status.Conditions.UpsertCondition(synced)
+
+	driftedCondition := &v1alpha1.DatabaseRuleCondition{
+		Type:           v1alpha1.DatabaseRuleConditionTypeDrifted,
+		Status:         corev1.ConditionFalse,
+		LastUpdateTime: now,
+		Reason:         "NoDrift",
+		Message:        "No rule has been changed outside of the DatabaseRule",
+	}
+	if msg := drifted.String(); msg != "" {
+		driftedCondition.Status = corev1.ConditionTrue
+		driftedCondition.Reason = "RulesDrifted"
+		driftedCondition.Message = strings.ToUpper(msg[:1]) + msg[1:]
+	}
+	dr.Status.Conditions.UpsertCondition(driftedCondition)
+
+	if err := r.Status().Update(ctx, dr); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+func (r *DatabaseRuleReconciler) getShardingSphereServer(ctx context.Context, dr *v1alpha1.DatabaseRule) (shardingsphere.IServer, error) {
+	return getShardingSphereServer(ctx, r.Client, r.Service, types.NamespacedName{
+		Name:      dr.Spec.ComputeNodeName,
+		Namespace: dr.Namespace,
+	}, "")
+}
+
+// showRules returns the rules existing in the logic database by their keys, only the types of the given rules are queried
+func showRules(ss shardingsphere.IServer, logicDBName string, rules []v1alpha1.AppliedRule) (map[string]*shardingsphere.Rule, error) {
+	existing := map[string]*shardingsphere.Rule{}
+	queried := map[string]bool{}
+	for _, rule := range rules {
+		if queried[rule.Type] {
+			continue
+		}
+		queried[rule.Type] = true
+
+		rs, err := ss.ShowRules(logicDBName, rule.Type)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			existing[ruleKey(r.Type, r.Name)] = r
+		}
+	}
+	return existing, nil
+}
+
+func ruleKey(ruleType, name string) string {
+	return fmt.Sprintf("%s/%s", ruleType, name)
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *DatabaseRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.DatabaseRule{}).
+		Complete(r)
+}
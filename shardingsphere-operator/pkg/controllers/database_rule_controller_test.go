@@ -0,0 +1,262 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/databaserule"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+	mock_shardingsphere "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere/mocks"
+
+	"bou.ke/monkey"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultTestComputeNode  = "test-compute-node"
+	defaultTestDatabaseRule = "test-database-rule"
+	defaultTestDatabase     = "sharding_db"
+)
+
+var _ = Describe("DatabaseRule Controller Mock Test", func() {
+	var (
+		drReconciler *DatabaseRuleReconciler
+		namespaced   = types.NamespacedName{Name: defaultTestDatabaseRule, Namespace: defaultTestNamespace}
+		shardingRule = v1alpha1.ShardingTableRuleSpec{
+			Table:             "t_order",
+			StorageUnits:      []string{"ds_0", "ds_1"},
+			ShardingColumn:    "order_id",
+			ShardingAlgorithm: &v1alpha1.AlgorithmSpec{Type: "MOD", Props: map[string]string{"sharding-count": "4"}},
+		}
+		maskRule = v1alpha1.MaskRuleSpec{
+			Table: "t_user",
+			Columns: []v1alpha1.MaskColumnSpec{
+				{Name: "phone", Algorithm: v1alpha1.AlgorithmSpec{Type: "MD5"}},
+			},
+		}
+	)
+
+	BeforeEach(func() {
+		drReconciler = &DatabaseRuleReconciler{
+			Client:   fakeClient,
+			Log:      logf.Log,
+			Recorder: record.NewFakeRecorder(100),
+			Service:  service.NewServiceClient(fakeClient),
+		}
+
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSS = mock_shardingsphere.NewMockIServer(mockCtrl)
+		monkey.Patch(shardingsphere.NewServer, func(_, _ string, _ uint, _, _ string) (shardingsphere.IServer, error) {
+			return mockSS, nil
+		})
+
+		cn := &v1alpha1.ComputeNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      defaultTestComputeNode,
+				Namespace: defaultTestNamespace,
+			},
+			Spec: v1alpha1.ComputeNodeSpec{
+				Bootstrap: v1alpha1.BootstrapConfig{
+					ServerConfig: v1alpha1.ServerConfig{
+						Authority: v1alpha1.ComputeNodeAuthority{
+							Users: []v1alpha1.ComputeNodeUser{{User: "root@%", Password: "root"}},
+						},
+					},
+				},
+			},
+		}
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      defaultTestComputeNode,
+				Namespace: defaultTestNamespace,
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Name: "proxy", Protocol: "TCP", Port: 3307}},
+			},
+		}
+		Expect(fakeClient.Create(ctx, cn)).Should(Succeed())
+		Expect(fakeClient.Create(ctx, svc)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+		monkey.UnpatchAll()
+	})
+
+	newDatabaseRule := func() *v1alpha1.DatabaseRule {
+		return &v1alpha1.DatabaseRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       defaultTestDatabaseRule,
+				Namespace:  defaultTestNamespace,
+				Finalizers: []string{FinalizerName},
+			},
+			Spec: v1alpha1.DatabaseRuleSpec{
+				ComputeNodeName: defaultTestComputeNode,
+				DatabaseName:    defaultTestDatabase,
+				Sharding:        []v1alpha1.ShardingTableRuleSpec{shardingRule},
+				Mask:            []v1alpha1.MaskRuleSpec{maskRule},
+			},
+		}
+	}
+
+	It("should create the rules missing in the logic database", func() {
+		dr := newDatabaseRule()
+		Expect(fakeClient.Create(ctx, dr)).Should(Succeed())
+
+		stmts := databaserule.Build(dr)
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeSharding).Return([]*shardingsphere.Rule{}, nil)
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeMask).Return([]*shardingsphere.Rule{}, nil)
+		gomock.InOrder(
+			mockSS.EXPECT().ExecuteDistSQL(defaultTestDatabase, stmts[0].Create).Return(nil),
+			mockSS.EXPECT().ExecuteDistSQL(defaultTestDatabase, stmts[1].Create).Return(nil),
+		)
+		mockSS.EXPECT().Close().Return(nil)
+
+		_, err := drReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		Expect(fakeClient.Get(ctx, namespaced, dr)).Should(Succeed())
+		Expect(dr.Status.Phase).To(Equal(v1alpha1.DatabaseRulePhaseSynced))
+		Expect(dr.Status.Rules).To(Equal([]v1alpha1.AppliedRule{
+			{Type: shardingsphere.RuleTypeSharding, Name: "t_order", Hash: stmts[0].Hash()},
+			{Type: shardingsphere.RuleTypeMask, Name: "t_user", Hash: stmts[1].Hash()},
+		}))
+	})
+
+	It("should alter changed rules, drop removed rules and report drifted rules", func() {
+		dr := newDatabaseRule()
+		stmts := databaserule.Build(dr)
+		dr.Spec.Sharding[0].ShardingAlgorithm = &v1alpha1.AlgorithmSpec{Type: "HASH_MOD", Props: map[string]string{"sharding-count": "8"}}
+		dr.Spec.Mask = nil
+		dr.Spec.Encrypt = []v1alpha1.EncryptRuleSpec{
+			{
+				Table: "t_user",
+				Columns: []v1alpha1.EncryptColumnSpec{
+					{Name: "password", Cipher: "password_cipher", EncryptAlgorithm: v1alpha1.AlgorithmSpec{Type: "MD5"}},
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, dr)).Should(Succeed())
+
+		dr.Status.Rules = []v1alpha1.AppliedRule{
+			{Type: shardingsphere.RuleTypeSharding, Name: "t_order", Hash: stmts[0].Hash()},
+			{Type: shardingsphere.RuleTypeMask, Name: "t_user", Hash: stmts[1].Hash()},
+			{Type: shardingsphere.RuleTypeEncrypt, Name: "t_user", Hash: "dropped-outside"},
+		}
+		Expect(fakeClient.Status().Update(ctx, dr)).Should(Succeed())
+
+		changed := databaserule.Build(dr)
+		// drop the mask rule which is removed from spec
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeMask).Return([]*shardingsphere.Rule{{Type: shardingsphere.RuleTypeMask, Name: "t_user"}}, nil)
+		mockSS.EXPECT().ExecuteDistSQL(defaultTestDatabase, "DROP MASK RULE t_user").Return(nil)
+		// the encrypt rule is dropped outside
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeSharding).Return([]*shardingsphere.Rule{{Type: shardingsphere.RuleTypeSharding, Name: "t_order"}}, nil)
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeEncrypt).Return([]*shardingsphere.Rule{}, nil)
+		mockSS.EXPECT().ExecuteDistSQL(defaultTestDatabase, changed[0].Alter).Return(nil)
+		mockSS.EXPECT().ExecuteDistSQL(defaultTestDatabase, changed[1].Create).Return(nil)
+		mockSS.EXPECT().Close().Return(nil)
+
+		_, err := drReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		Expect(fakeClient.Get(ctx, namespaced, dr)).Should(Succeed())
+		Expect(dr.Status.Phase).To(Equal(v1alpha1.DatabaseRulePhaseSynced))
+		Expect(dr.Status.Rules).To(Equal([]v1alpha1.AppliedRule{
+			{Type: shardingsphere.RuleTypeSharding, Name: "t_order", Hash: changed[0].Hash()},
+			{Type: shardingsphere.RuleTypeEncrypt, Name: "t_user", Hash: changed[1].Hash()},
+		}))
+		for _, c := range dr.Status.Conditions {
+			if c.Type == v1alpha1.DatabaseRuleConditionTypeDrifted {
+				Expect(c.Status).To(Equal(corev1.ConditionTrue))
+				Expect(c.Message).To(ContainSubstring("encrypt/t_user"))
+			}
+		}
+	})
+
+	It("should alter the rules altered outside and report them as drifted", func() {
+		dr := newDatabaseRule()
+		Expect(fakeClient.Create(ctx, dr)).Should(Succeed())
+		stmts := databaserule.Build(dr)
+		dr.Status.Rules = []v1alpha1.AppliedRule{
+			{Type: shardingsphere.RuleTypeSharding, Name: "t_order", Hash: stmts[0].Hash()},
+			{Type: shardingsphere.RuleTypeMask, Name: "t_user", Hash: stmts[1].Hash()},
+		}
+		Expect(fakeClient.Status().Update(ctx, dr)).Should(Succeed())
+
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeSharding).Return([]*shardingsphere.Rule{{
+			Type: shardingsphere.RuleTypeSharding, Name: "t_order", Rows: []map[string]string{{
+				"table": "t_order", "actual_data_sources": "ds_0,ds_1", "table_sharding_column": "order_id",
+				"table_sharding_algorithm_type": "MOD", "table_sharding_algorithm_props": `{"sharding-count":"4"}`,
+			}},
+		}}, nil)
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeMask).Return([]*shardingsphere.Rule{{
+			Type: shardingsphere.RuleTypeMask, Name: "t_user", Rows: []map[string]string{{
+				"table": "t_user", "column": "phone", "algorithm_type": "KEEP_FIRST_N_LAST_M", "algorithm_props": "",
+			}},
+		}}, nil)
+		mockSS.EXPECT().ExecuteDistSQL(defaultTestDatabase, stmts[1].Alter).Return(nil)
+		mockSS.EXPECT().Close().Return(nil)
+
+		_, err := drReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		Expect(fakeClient.Get(ctx, namespaced, dr)).Should(Succeed())
+		Expect(dr.Status.Phase).To(Equal(v1alpha1.DatabaseRulePhaseSynced))
+		var drifted *v1alpha1.DatabaseRuleCondition
+		for _, c := range dr.Status.Conditions {
+			if c.Type == v1alpha1.DatabaseRuleConditionTypeDrifted {
+				drifted = c
+			}
+		}
+		Expect(drifted).NotTo(BeNil())
+		Expect(drifted.Status).To(Equal(corev1.ConditionTrue))
+		Expect(drifted.Message).To(Equal("Rules mask/t_user were altered outside of the DatabaseRule"))
+	})
+
+	It("should drop the managed rules when the DatabaseRule is deleted", func() {
+		dr := newDatabaseRule()
+		Expect(fakeClient.Create(ctx, dr)).Should(Succeed())
+		dr.Status.Rules = []v1alpha1.AppliedRule{
+			{Type: shardingsphere.RuleTypeSharding, Name: "t_order", Hash: "hash"},
+			{Type: shardingsphere.RuleTypeMask, Name: "t_user", Hash: "hash"},
+		}
+		Expect(fakeClient.Status().Update(ctx, dr)).Should(Succeed())
+		Expect(fakeClient.Delete(ctx, dr)).Should(Succeed())
+
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeMask).Return([]*shardingsphere.Rule{{Type: shardingsphere.RuleTypeMask, Name: "t_user"}}, nil)
+		mockSS.EXPECT().ShowRules(defaultTestDatabase, shardingsphere.RuleTypeSharding).Return([]*shardingsphere.Rule{{Type: shardingsphere.RuleTypeSharding, Name: "t_order"}}, nil)
+		gomock.InOrder(
+			mockSS.EXPECT().ExecuteDistSQL(defaultTestDatabase, "DROP MASK RULE t_user").Return(nil),
+			mockSS.EXPECT().ExecuteDistSQL(defaultTestDatabase, "DROP SHARDING TABLE RULE  t_order").Return(nil),
+		)
+		mockSS.EXPECT().Close().Return(nil)
+
+		_, err := drReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+		Expect(fakeClient.Get(ctx, namespaced, dr)).ShouldNot(Succeed())
+	})
+})
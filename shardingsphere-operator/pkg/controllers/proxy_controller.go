@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/computenode"
 	reconcile "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/proxy"
 	"github.com/go-logr/logr"
 
@@ -60,6 +61,8 @@ type ProxyReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=services/status,verbs=get
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers/status,verbs=get
 
@@ -116,11 +119,17 @@ func (r *ProxyReconciler) reconcileDeployment(ctx context.Context, namespacedNam
 		return ctrl.Result{}, err
 	}
 
+	hash, err := r.getConfigHash(ctx, proxy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	deploy := &appsv1.Deployment{}
 	err = r.Get(ctx, namespacedName, deploy)
 
 	if apierrors.IsNotFound(err) {
 		exp := reconcile.NewDeployment(proxy)
+		reconcile.SetConfigHash(exp, hash)
 		if err := r.Create(ctx, exp); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -132,6 +141,7 @@ func (r *ProxyReconciler) reconcileDeployment(ctx context.Context, namespacedNam
 
 	act := deploy.DeepCopy()
 	exp := reconcile.UpdateDeployment(proxy, act)
+	reconcile.SetConfigHash(exp, hash)
 
 	if err := r.Update(ctx, exp); err != nil {
 		return ctrl.Result{Requeue: true}, err
@@ -139,6 +149,28 @@ func (r *ProxyReconciler) reconcileDeployment(ctx context.Context, namespacedNam
 	return ctrl.Result{}, nil
 }
 
+// getConfigHash returns the content hash of the Configmap and the Secret cascaded from the ShardingSphereProxyServerConfig,
+// the missing ones are skipped
+func (r *ProxyReconciler) getConfigHash(ctx context.Context, proxy *v1alpha1.ShardingSphereProxy) (string, error) {
+	namespacedName := types.NamespacedName{Namespace: proxy.Namespace, Name: proxy.Spec.ProxyConfigName}
+
+	cm := &v1.ConfigMap{}
+	if err := r.Get(ctx, namespacedName, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		cm = nil
+	}
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, namespacedName, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		secret = nil
+	}
+	return computenode.ConfigHash(cm, []*v1.Secret{secret}), nil
+}
+
 func (r *ProxyReconciler) reconcileHPA(ctx context.Context, namespacedName types.NamespacedName) (ctrl.Result, error) {
 	proxy, err := r.getRuntimeShardingSphereProxy(ctx, namespacedName)
 	if err != nil {
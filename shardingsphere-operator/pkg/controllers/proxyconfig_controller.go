@@ -30,8 +30,11 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -49,6 +52,7 @@ type ProxyConfigReconciler struct {
 // +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=shardingsphereproxyserverconfigs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=shardingsphereproxyserverconfigs/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile the ProxyConfig
 func (r *ProxyConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -64,6 +68,12 @@ func (r *ProxyConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// the secrets are reconciled before the configmap, which returns once created
+	if err := r.reconcileSecrets(ctx, run); err != nil {
+		logger.Error(err, "Error reconciling cascaded secrets")
+		return ctrl.Result{Requeue: true}, err
+	}
+
 	cm := &v1.ConfigMap{}
 	configmap := reconcile.ConstructCascadingConfigmap(run)
 	err = r.Get(ctx, req.NamespacedName, cm)
@@ -106,10 +116,85 @@ func (r *ProxyConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
+// reconcileSecrets keeps the generated passwords and renders server.yaml with the resolved passwords into a Secret
+func (r *ProxyConfigReconciler) reconcileSecrets(ctx context.Context, run *shardingspherev1alpha1.ShardingSphereProxyServerConfig) error {
+	authority := &v1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: run.Namespace, Name: reconcile.AuthoritySecretName(run)}, authority); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		authority = nil
+	}
+	exp, err := reconcile.ConstructAuthoritySecret(run, authority)
+	if err != nil {
+		return err
+	}
+	if err := r.createOrUpdateSecret(ctx, exp); err != nil {
+		return err
+	}
+
+	secrets := []*v1.Secret{}
+	for _, name := range reconcile.ReferencedSecretNames(run) {
+		secret := &v1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: run.Namespace, Name: name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		secrets = append(secrets, secret)
+	}
+	exp, err = reconcile.ConstructCascadingSecret(run, secrets)
+	if err != nil {
+		return err
+	}
+	return r.createOrUpdateSecret(ctx, exp)
+}
+
+func (r *ProxyConfigReconciler) createOrUpdateSecret(ctx context.Context, exp *v1.Secret) error {
+	if exp == nil {
+		return nil
+	}
+
+	cur := &v1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: exp.Namespace, Name: exp.Name}, cur); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.Create(ctx, exp)
+		}
+		return err
+	}
+	if !equality.Semantic.DeepEqual(exp.Data, cur.Data) {
+		cur.Data = exp.Data
+		return r.Update(ctx, cur)
+	}
+	return nil
+}
+
+// findConfigsForSecret returns the ShardingSphereProxyServerConfigs referring to the Secret, so that a rotated password is rendered
+func (r *ProxyConfigReconciler) findConfigsForSecret(obj client.Object) []ctrl.Request {
+	list := &shardingspherev1alpha1.ShardingSphereProxyServerConfigList{}
+	if err := r.List(context.Background(), list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []ctrl.Request{}
+	for i := range list.Items {
+		for _, name := range reconcile.ReferencedSecretNames(&list.Items[i]) {
+			if name == obj.GetName() {
+				requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ProxyConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&shardingspherev1alpha1.ShardingSphereProxyServerConfig{}).
 		Owns(&v1.ConfigMap{}).
+		Owns(&v1.Secret{}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.findConfigsForSecret)).
 		Complete(r)
 }
@@ -19,12 +19,17 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/databaserule"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/aws"
 	mock_aws "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/aws/mocks"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/cloudnativepg"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+	mock_provisioner "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner/mocks"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
 	mock_shardingsphere "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere/mocks"
 
@@ -70,14 +75,16 @@ func fakeStorageNodeReconciler() {
 	Expect(corev1.AddToScheme(scheme)).To(Succeed())
 	fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
 
+	provisioners := provisioner.NewRegistry()
+	aws.RegisterProvisioners(provisioners, "AwsRegion", "AwsAccessKeyID", "AwsSecretAccessKey")
+	provisioners.Register(v1alpha1.ProvisionerCloudNativePG, cloudnativepg.NewProvisioner(fakeClient))
+
 	reconciler = &StorageNodeReconciler{
-		Client:             fakeClient,
-		Log:                logf.Log,
-		Recorder:           record.NewFakeRecorder(100),
-		AwsRegion:          "AwsRegion",
-		AwsAccessKeyID:     "AwsAccessKeyID",
-		AwsSecretAccessKey: "AwsSecretAccessKey",
-		Service:            service.NewServiceClient(fakeClient),
+		Client:       fakeClient,
+		Log:          logf.Log,
+		Recorder:     record.NewFakeRecorder(100),
+		Service:      service.NewServiceClient(fakeClient),
+		Provisioners: provisioners,
 	}
 }
 
@@ -94,6 +101,8 @@ var _ = Describe("StorageNode Controller Mock Test For AWS Rds Instance", func()
 		monkey.Patch(aws.NewRdsClient, func(rds dbmeshawsrds.RDS) aws.IRdsClient {
 			return mockAws
 		})
+		// the instance has the resources in the spec unless a test resizes it
+		mockAws.EXPECT().GetInstanceResources(gomock.Any(), gomock.Any()).Return(provisioner.Resources{}, false, nil).AnyTimes()
 
 		// create default resource
 		dbClass := &v1alpha1.StorageProvider{
@@ -555,10 +564,79 @@ var _ = Describe("StorageNode Controller Mock Test For AWS Rds Instance", func()
 				},
 			}
 
-			ss, err := reconciler.getShardingsphereServer(ctx, sn)
+			ss, err := reconciler.getShardingsphereServer(ctx, sn, nil)
 			Expect(err).To(BeNil())
 			Expect(ss).ToNot(BeNil())
 		})
+
+		It("should choose the driver by the frontend protocol type and the storage provider", func() {
+			var driver string
+			monkey.Patch(shardingsphere.NewServer, func(d, _ string, _ uint, _, _ string) (shardingsphere.IServer, error) {
+				driver = d
+				return mockSS, nil
+			})
+
+			cn := &v1alpha1.ComputeNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-get-shardingsphere-server-driver",
+					Namespace: defaultTestNamespace,
+				},
+				Spec: v1alpha1.ComputeNodeSpec{
+					Bootstrap: v1alpha1.BootstrapConfig{
+						ServerConfig: v1alpha1.ServerConfig{
+							Authority: v1alpha1.ComputeNodeAuthority{
+								Users: []v1alpha1.ComputeNodeUser{{User: "root", Password: "root"}},
+							},
+						},
+					},
+				},
+			}
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-get-shardingsphere-server-driver",
+					Namespace: defaultTestNamespace,
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Name: "http", Protocol: "TCP", Port: 5432}},
+				},
+			}
+			Expect(fakeClient.Create(ctx, cn)).Should(Succeed())
+			Expect(fakeClient.Create(ctx, svc)).Should(Succeed())
+
+			sn := &v1alpha1.StorageNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-get-shardingsphere-server-driver",
+					Namespace: defaultTestNamespace,
+					Annotations: map[string]string{
+						AnnotationKeyComputeNodeName: "test-get-shardingsphere-server-driver",
+					},
+				},
+			}
+			cnpgProvider := &v1alpha1.StorageProvider{Spec: v1alpha1.StorageProviderSpec{Provisioner: v1alpha1.ProvisionerCloudNativePG}}
+			rdsProvider := &v1alpha1.StorageProvider{Spec: v1alpha1.StorageProviderSpec{
+				Provisioner: v1alpha1.ProvisionerAWSRDSInstance,
+				Parameters:  map[string]string{"engine": "postgres"},
+			}}
+
+			_, err := reconciler.getShardingsphereServer(ctx, sn, nil)
+			Expect(err).To(BeNil())
+			Expect(driver).To(Equal(shardingsphere.DriverMySQL))
+
+			_, err = reconciler.getShardingsphereServer(ctx, sn, cnpgProvider)
+			Expect(err).To(BeNil())
+			Expect(driver).To(Equal(shardingsphere.DriverPostgres))
+
+			_, err = reconciler.getShardingsphereServer(ctx, sn, rdsProvider)
+			Expect(err).To(BeNil())
+			Expect(driver).To(Equal(shardingsphere.DriverPostgres))
+
+			// the frontend protocol type of the compute node takes precedence
+			cn.Spec.Bootstrap.ServerConfig.Props = v1alpha1.Properties{ShardingSphereProtocolType: "openGauss"}
+			Expect(fakeClient.Update(ctx, cn)).Should(Succeed())
+			_, err = reconciler.getShardingsphereServer(ctx, sn, cnpgProvider)
+			Expect(err).To(BeNil())
+			Expect(driver).To(Equal(shardingsphere.DriverOpenGauss))
+		})
 	})
 
 	Context("Test registerStorageUnit", func() {
@@ -635,8 +713,16 @@ var _ = Describe("StorageNode Controller Mock Test For AWS Rds Instance", func()
 							Endpoint: v1alpha1.Endpoint{},
 						},
 					},
+					CredentialsSecretRef: &corev1.LocalObjectReference{Name: testName},
 				},
 			}
+			Expect(fakeClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: defaultTestNamespace},
+				Data: map[string][]byte{
+					v1alpha1.StorageNodeCredentialsUsernameKey: []byte(testName),
+					v1alpha1.StorageNodeCredentialsPasswordKey: []byte(testName),
+				},
+			})).Should(Succeed())
 
 			storageProvider := &v1alpha1.StorageProvider{
 				ObjectMeta: metav1.ObjectMeta{
@@ -644,21 +730,115 @@ var _ = Describe("StorageNode Controller Mock Test For AWS Rds Instance", func()
 				},
 				Spec: v1alpha1.StorageProviderSpec{
 					Provisioner: v1alpha1.ProvisionerAWSRDSInstance,
-					Parameters: map[string]string{
-						"masterUsername":     testName,
-						"masterUserPassword": testName,
-					},
 				},
 			}
 
 			mockSS.EXPECT().CreateDatabase(gomock.Any()).Return(nil)
 			mockSS.EXPECT().Close().Return(nil)
-			mockSS.EXPECT().RegisterStorageUnit(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockSS.EXPECT().RegisterStorageUnit(testName, gomock.Any(), gomock.Any(), gomock.Any(), testName, testName, testName).Return(nil)
 
 			Expect(reconciler.registerStorageUnit(ctx, sn, storageProvider)).To(BeNil())
 			Expect(sn.Status.Registered).To(BeTrue())
 		})
 
+		It("should register the readers and keep the readwrite-splitting rule up to date", func() {
+			testName := "test-register-reader-storage-units"
+			cn := &v1alpha1.ComputeNode{
+				ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: defaultTestNamespace},
+				Spec: v1alpha1.ComputeNodeSpec{
+					Bootstrap: v1alpha1.BootstrapConfig{
+						ServerConfig: v1alpha1.ServerConfig{
+							Authority: v1alpha1.ComputeNodeAuthority{
+								Users: []v1alpha1.ComputeNodeUser{{User: "root", Password: "root"}},
+							},
+						},
+					},
+				},
+			}
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: defaultTestNamespace},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Name: "http", Protocol: "TCP", Port: 3307}},
+				},
+			}
+			Expect(fakeClient.Create(ctx, cn)).Should(Succeed())
+			Expect(fakeClient.Create(ctx, svc)).Should(Succeed())
+
+			sn := &v1alpha1.StorageNode{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      testName,
+					Namespace: defaultTestNamespace,
+					Annotations: map[string]string{
+						AnnotationKeyComputeNodeName:            testName,
+						AnnotationKeyRegisterStorageUnitEnabled: "true",
+						AnnotationKeyLogicDatabaseName:          "sharding_db",
+						v1alpha1.AnnotationsInstanceDBName:      "ds",
+					},
+				},
+				Status: v1alpha1.StorageNodeStatus{
+					Phase:      v1alpha1.StorageNodePhaseReady,
+					Registered: true,
+					Cluster: v1alpha1.ClusterStatus{
+						Status:          "available",
+						PrimaryEndpoint: v1alpha1.Endpoint{Address: "primary", Port: 3306},
+						ReaderEndpoints: []v1alpha1.Endpoint{
+							{Address: "reader-0", Port: 3306},
+							{Address: "reader-1", Port: 3306},
+							{Address: "", Port: 3306},
+						},
+					},
+					CredentialsSecretRef: &corev1.LocalObjectReference{Name: testName},
+				},
+			}
+			Expect(fakeClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: defaultTestNamespace},
+				Data: map[string][]byte{
+					v1alpha1.StorageNodeCredentialsUsernameKey: []byte("user"),
+					v1alpha1.StorageNodeCredentialsPasswordKey: []byte("password"),
+				},
+			})).Should(Succeed())
+			storageProvider := &v1alpha1.StorageProvider{
+				Spec: v1alpha1.StorageProviderSpec{
+					Provisioner: v1alpha1.ProvisionerAWSAurora,
+				},
+			}
+
+			readers := getReaderStorageUnits(sn, sn.Status.Cluster.ReaderEndpoints)
+			Expect(readers).To(HaveLen(2))
+			names := readerStorageUnitNames(readers)
+			Expect(names[0]).To(HavePrefix(getDSName(sn) + "_read_"))
+			Expect(names[0]).ToNot(Equal(names[1]))
+
+			// reader-0 is registered, and an old reader is gone
+			sn.Status.ReaderStorageUnits = []string{names[0], "ds_old_reader"}
+			var reader1 readerStorageUnit
+			for _, r := range readers {
+				if r.name == names[1] {
+					reader1 = r
+				}
+			}
+
+			stmt := databaserule.BuildReadwriteSplitting(&v1alpha1.ReadwriteSplittingRuleSpec{
+				Name:             getReadwriteSplittingRuleName(sn),
+				WriteStorageUnit: getDSName(sn),
+				ReadStorageUnits: names,
+			})
+			gomock.InOrder(
+				mockSS.EXPECT().RegisterStorageUnit("sharding_db", names[1], reader1.endpoint.Address, uint(3306), "ds", "user", "password").Return(nil),
+				mockSS.EXPECT().ShowRules("sharding_db", shardingsphere.RuleTypeReadwriteSplitting).
+					Return([]*shardingsphere.Rule{{Type: shardingsphere.RuleTypeReadwriteSplitting, Name: getReadwriteSplittingRuleName(sn)}}, nil),
+				mockSS.EXPECT().ExecuteDistSQL("sharding_db", stmt.Alter).Return(nil),
+				mockSS.EXPECT().UnRegisterStorageUnit("sharding_db", "ds_old_reader").Return(nil),
+			)
+			mockSS.EXPECT().Close().Return(nil)
+
+			Expect(reconciler.registerStorageUnit(ctx, sn, storageProvider)).To(BeNil())
+			Expect(sn.Status.ReaderStorageUnits).To(Equal(names))
+
+			// nothing to do when the readers are registered
+			Expect(reconciler.registerStorageUnit(ctx, sn, storageProvider)).To(BeNil())
+		})
+
 		Context("Test unregisterStorageUnit", func() {
 			BeforeEach(func() {
 				mockCtrl = gomock.NewController(GinkgoT())
@@ -730,7 +910,61 @@ var _ = Describe("StorageNode Controller Mock Test For AWS Rds Instance", func()
 
 				mockSS.EXPECT().UnRegisterStorageUnit(gomock.Any(), gomock.Any()).Return(nil)
 				mockSS.EXPECT().Close().Return(nil)
-				Expect(reconciler.unregisterStorageUnit(ctx, sn)).To(BeNil())
+				Expect(reconciler.unregisterStorageUnit(ctx, sn, nil)).To(BeNil())
+			})
+
+			It("should unregister the readers before the primary storage unit", func() {
+				testName := "test-unregister-reader-storage-units"
+				cn := &v1alpha1.ComputeNode{
+					ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: defaultTestNamespace},
+					Spec: v1alpha1.ComputeNodeSpec{
+						Bootstrap: v1alpha1.BootstrapConfig{
+							ServerConfig: v1alpha1.ServerConfig{
+								Authority: v1alpha1.ComputeNodeAuthority{
+									Users: []v1alpha1.ComputeNodeUser{{User: "root", Password: "root"}},
+								},
+							},
+						},
+					},
+				}
+				svc := &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: defaultTestNamespace},
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{{Name: "http", Protocol: "TCP", Port: 3307}},
+					},
+				}
+				Expect(fakeClient.Create(ctx, cn)).Should(Succeed())
+				Expect(fakeClient.Create(ctx, svc)).Should(Succeed())
+
+				sn := &v1alpha1.StorageNode{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testName,
+						Namespace: defaultTestNamespace,
+						Annotations: map[string]string{
+							AnnotationKeyLogicDatabaseName:     "sharding_db",
+							v1alpha1.AnnotationsInstanceDBName: "ds",
+							AnnotationKeyComputeNodeName:       testName,
+						},
+					},
+					Status: v1alpha1.StorageNodeStatus{
+						Registered:         true,
+						ReaderStorageUnits: []string{"ds_reader_0"},
+					},
+				}
+
+				ruleName := getReadwriteSplittingRuleName(sn)
+				gomock.InOrder(
+					mockSS.EXPECT().ShowRules("sharding_db", shardingsphere.RuleTypeReadwriteSplitting).
+						Return([]*shardingsphere.Rule{{Type: shardingsphere.RuleTypeReadwriteSplitting, Name: ruleName}}, nil),
+					mockSS.EXPECT().DropRule("sharding_db", shardingsphere.RuleTypeReadwriteSplitting, ruleName).Return(nil),
+					mockSS.EXPECT().UnRegisterStorageUnit("sharding_db", "ds_reader_0").Return(nil),
+					mockSS.EXPECT().UnRegisterStorageUnit("sharding_db", getDSName(sn)).Return(nil),
+				)
+				mockSS.EXPECT().Close().Return(nil)
+
+				Expect(reconciler.unregisterStorageUnit(ctx, sn, nil)).To(BeNil())
+				Expect(sn.Status.Registered).To(BeFalse())
+				Expect(sn.Status.ReaderStorageUnits).To(BeEmpty())
 			})
 		})
 	})
@@ -762,6 +996,7 @@ var _ = Describe("StorageNode Controller Mock Test For AWS Aurora", func() {
 		monkey.Patch(aws.NewRdsClient, func(rds dbmeshawsrds.RDS) aws.IRdsClient {
 			return mockAws
 		})
+		mockAws.EXPECT().GetAuroraClusterResources(gomock.Any(), gomock.Any()).Return(provisioner.Resources{}, false, nil).AnyTimes()
 		mockSS = mock_shardingsphere.NewMockIServer(mockCtrl)
 		monkey.Patch(shardingsphere.NewServer, func(_, _ string, _ uint, _, _ string) (shardingsphere.IServer, error) {
 			return mockSS, nil
@@ -1067,7 +1302,9 @@ var _ = Describe("StorageNode Controller Mock Test For AWS Aurora", func() {
 				},
 			}, nil).Times(1)
 
-			host, port, username, password := getDatasourceInfoFromCluster(storageNode, provider)
+			host, port := storageNode.Status.Cluster.PrimaryEndpoint.Address, storageNode.Status.Cluster.PrimaryEndpoint.Port
+			// the generated credentials Secret keeps the master user of the storage provider
+			username, password := provider.Spec.Parameters["masterUsername"], provider.Spec.Parameters["masterUserPassword"]
 
 			// mock shardingsphere
 			mockSS.EXPECT().CreateDatabase(gomock.Any()).Return(nil).Times(1)
@@ -1213,6 +1450,7 @@ var _ = Describe("StorageNode Controller Mock Test For AWS RDS Cluster", func()
 		monkey.Patch(aws.NewRdsClient, func(rds dbmeshawsrds.RDS) aws.IRdsClient {
 			return mockAws
 		})
+		mockAws.EXPECT().GetRDSClusterResources(gomock.Any(), gomock.Any()).Return(provisioner.Resources{}, false, nil).AnyTimes()
 		mockSS = mock_shardingsphere.NewMockIServer(mockCtrl)
 		monkey.Patch(shardingsphere.NewServer, func(_, _ string, _ uint, _, _ string) (shardingsphere.IServer, error) {
 			return mockSS, nil
@@ -1518,7 +1756,9 @@ var _ = Describe("StorageNode Controller Mock Test For AWS RDS Cluster", func()
 				},
 			}, nil).Times(1)
 
-			host, port, username, password := getDatasourceInfoFromCluster(storageNode, provider)
+			host, port := storageNode.Status.Cluster.PrimaryEndpoint.Address, storageNode.Status.Cluster.PrimaryEndpoint.Port
+			// the generated credentials Secret keeps the master user of the storage provider
+			username, password := provider.Spec.Parameters["masterUsername"], provider.Spec.Parameters["masterUserPassword"]
 
 			// mock shardingsphere
 			mockSS.EXPECT().CreateDatabase(gomock.Any()).Return(nil).Times(1)
@@ -1635,3 +1875,261 @@ var _ = Describe("StorageNode Controller Mock Test For AWS RDS Cluster", func()
 		})
 	})
 })
+
+var _ = Describe("StorageNode Controller Test For Credentials", func() {
+	var (
+		node     *v1alpha1.StorageNode
+		provider *v1alpha1.StorageProvider
+	)
+
+	BeforeEach(func() {
+		node = &v1alpha1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-credentials",
+				Namespace: defaultTestNamespace,
+				UID:       "test-credentials-uid",
+			},
+			Spec: v1alpha1.StorageNodeSpec{
+				StorageProviderName: defaultTestStorageProvider,
+			},
+		}
+		provider = &v1alpha1.StorageProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestStorageProvider},
+			Spec: v1alpha1.StorageProviderSpec{
+				Provisioner: v1alpha1.ProvisionerAWSRDSInstance,
+				Parameters:  map[string]string{"engine": "mysql"},
+			},
+		}
+	})
+
+	It("should generate the credentials secret owned by the storage node", func() {
+		Expect(fakeClient.Create(ctx, node)).Should(Succeed())
+		Expect(reconciler.ensureCredentials(ctx, node, provider)).To(Succeed())
+		Expect(node.Status.CredentialsSecretRef).To(Equal(&corev1.LocalObjectReference{Name: "test-credentials-credentials"}))
+
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: "test-credentials-credentials"}, secret)).Should(Succeed())
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].UID).To(Equal(node.UID))
+		Expect(string(secret.Data[v1alpha1.StorageNodeCredentialsUsernameKey])).To(Equal("root"))
+		Expect(secret.Data[v1alpha1.StorageNodeCredentialsPasswordKey]).To(HaveLen(24))
+
+		creds, err := reconciler.getCredentials(ctx, node, provider)
+		Expect(err).To(BeNil())
+		Expect(creds.username).To(Equal("root"))
+		Expect(creds.password).To(Equal(string(secret.Data[v1alpha1.StorageNodeCredentialsPasswordKey])))
+
+		// the password is generated only once
+		Expect(reconciler.ensureCredentials(ctx, node, provider)).To(Succeed())
+		again, err := reconciler.getCredentials(ctx, node, provider)
+		Expect(err).To(BeNil())
+		Expect(again).To(Equal(creds))
+	})
+
+	It("should keep the credentials secret of a retained database", func() {
+		Expect(fakeClient.Create(ctx, node)).Should(Succeed())
+		Expect(reconciler.ensureCredentials(ctx, node, provider)).To(Succeed())
+
+		provider.Spec.ReclaimPolicy = v1alpha1.StorageReclaimPolicyRetain
+		node.Status.Phase = v1alpha1.StorageNodePhaseDeleteComplete
+		_, err := reconciler.finalize(ctx, node, provider)
+		Expect(err).To(BeNil())
+
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: "test-credentials-credentials"}, secret)).Should(Succeed())
+		Expect(secret.OwnerReferences).To(BeEmpty())
+	})
+
+	It("should use the credentials secret referenced by the storage node", func() {
+		node.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "user-secret"}
+		Expect(fakeClient.Create(ctx, node)).Should(Succeed())
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "user-secret", Namespace: defaultTestNamespace},
+			Data:       map[string][]byte{v1alpha1.StorageNodeCredentialsPasswordKey: []byte("secret-password")},
+		})).Should(Succeed())
+
+		Expect(reconciler.ensureCredentials(ctx, node, provider)).To(Succeed())
+		Expect(node.Status.CredentialsSecretRef).To(Equal(node.Spec.CredentialsSecretRef))
+		Expect(apierrors.IsNotFound(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: "test-credentials-credentials"}, &corev1.Secret{}))).To(BeTrue())
+
+		// the username defaults to the one of the engine
+		creds, err := reconciler.getCredentials(ctx, node, provider)
+		Expect(err).To(BeNil())
+		Expect(creds).To(Equal(credentials{username: "root", password: "secret-password"}))
+	})
+
+	It("should move the password in the annotations into the credentials secret", func() {
+		node.Annotations = map[string]string{
+			v1alpha1.AnnotationsMasterUsername:     "admin",
+			v1alpha1.AnnotationsMasterUserPassword: "annotation-password",
+		}
+		Expect(fakeClient.Create(ctx, node)).Should(Succeed())
+
+		Expect(reconciler.ensureCredentials(ctx, node, provider)).To(Succeed())
+		Expect(node.Annotations).ToNot(HaveKey(v1alpha1.AnnotationsMasterUserPassword))
+		Expect(node.Status.CredentialsSecretRef).ToNot(BeNil())
+
+		stored := &v1alpha1.StorageNode{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: node.Name}, stored)).Should(Succeed())
+		Expect(stored.Annotations).ToNot(HaveKey(v1alpha1.AnnotationsMasterUserPassword))
+
+		creds, err := reconciler.getCredentials(ctx, node, provider)
+		Expect(err).To(BeNil())
+		Expect(creds).To(Equal(credentials{username: "admin", password: "annotation-password"}))
+	})
+
+	It("should fail without the password in the credentials secret", func() {
+		node.Status.CredentialsSecretRef = &corev1.LocalObjectReference{Name: "empty-secret"}
+		_, err := reconciler.getCredentials(ctx, node, provider)
+		Expect(err).ToNot(BeNil())
+
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "empty-secret", Namespace: defaultTestNamespace},
+			Data:       map[string][]byte{v1alpha1.StorageNodeCredentialsUsernameKey: []byte("root")},
+		})).Should(Succeed())
+		_, err = reconciler.getCredentials(ctx, node, provider)
+		Expect(err).ToNot(BeNil())
+	})
+})
+
+// resizableProvisioner is a provisioner supporting resizing
+type resizableProvisioner struct {
+	*mock_provisioner.MockProvisioner
+	*mock_provisioner.MockResizer
+}
+
+var _ = Describe("StorageNode Controller Test For Resizing", func() {
+	var (
+		node     *v1alpha1.StorageNode
+		provider *v1alpha1.StorageProvider
+		p        resizableProvisioner
+		resizer  *mock_provisioner.MockResizer
+		recorder *record.FakeRecorder
+		db       = &provisioner.Database{}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		resizer = mock_provisioner.NewMockResizer(mockCtrl)
+		p = resizableProvisioner{MockProvisioner: mock_provisioner.NewMockProvisioner(mockCtrl), MockResizer: resizer}
+		recorder = record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+
+		node = &v1alpha1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-resizing", Namespace: defaultTestNamespace},
+			Spec: v1alpha1.StorageNodeSpec{
+				StorageProviderName: defaultTestStorageProvider,
+				InstanceClass:       "db.t3.small",
+				AllocatedStorage:    30,
+				Replicas:            1,
+			},
+		}
+		provider = &v1alpha1.StorageProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestStorageProvider},
+			Spec:       v1alpha1.StorageProviderSpec{Provisioner: v1alpha1.ProvisionerAWSRDSInstance},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should resize the database with the changed resources", func() {
+		current := provisioner.Resources{InstanceClass: "db.t3.micro", AllocatedStorage: 20}
+		resizer.EXPECT().Resources(gomock.Any(), node, provider, db).Return(current, false, nil)
+		resizer.EXPECT().Resize(gomock.Any(), node, provider, db, provisioner.DesiredResources(node)).Return(nil)
+
+		Expect(reconciler.resizeDatabase(ctx, p, node, provider, db)).To(Succeed())
+		Expect(isResizing(node.Status)).To(BeTrue())
+		Expect(node.Status.Conditions[0].Message).To(Equal("instanceClass: db.t3.micro -> db.t3.small, allocatedStorage: 20Gi -> 30Gi"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("instanceClass: db.t3.micro -> db.t3.small")))
+		Expect(computeDesiredState(node.Status).Phase).To(Equal(v1alpha1.StorageNodePhaseResizing))
+	})
+
+	It("should wait for the database being resized", func() {
+		current := provisioner.Resources{InstanceClass: "db.t3.micro", AllocatedStorage: 30}
+		resizer.EXPECT().Resources(gomock.Any(), node, provider, db).Return(current, true, nil)
+
+		Expect(reconciler.resizeDatabase(ctx, p, node, provider, db)).To(Succeed())
+		Expect(isResizing(node.Status)).To(BeTrue())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should mark the database resized when the resources are applied", func() {
+		node.Status.Conditions.UpsertCondition(&v1alpha1.StorageNodeCondition{
+			Type:   v1alpha1.StorageNodeConditionTypeResizing,
+			Status: corev1.ConditionTrue,
+		})
+		current := provisioner.DesiredResources(node)
+
+		resizer.EXPECT().Resources(gomock.Any(), node, provider, db).Return(current, true, nil)
+		Expect(reconciler.resizeDatabase(ctx, p, node, provider, db)).To(Succeed())
+		Expect(isResizing(node.Status)).To(BeTrue())
+
+		resizer.EXPECT().Resources(gomock.Any(), node, provider, db).Return(current, false, nil)
+		Expect(reconciler.resizeDatabase(ctx, p, node, provider, db)).To(Succeed())
+		Expect(isResizing(node.Status)).To(BeFalse())
+		Expect(node.Status.Conditions[0].Reason).To(Equal("Resized"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("is resized")))
+	})
+
+	It("should report the failure of resizing", func() {
+		resizer.EXPECT().Resources(gomock.Any(), node, provider, db).Return(provisioner.Resources{InstanceClass: "db.t3.micro"}, false, nil)
+		resizer.EXPECT().Resize(gomock.Any(), node, provider, db, gomock.Any()).Return(errors.New("invalid instance class"))
+
+		Expect(reconciler.resizeDatabase(ctx, p, node, provider, db)).ToNot(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring("ResizeFailed")))
+	})
+})
+
+var _ = Describe("StorageNode Controller Test For Deletion", func() {
+	var (
+		node *v1alpha1.StorageNode
+		p    *mock_provisioner.MockProvisioner
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		p = mock_provisioner.NewMockProvisioner(mockCtrl)
+		reconciler.Provisioners.Register("unreachable", p)
+
+		node = &v1alpha1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "test-deletion",
+				Namespace:  defaultTestNamespace,
+				Finalizers: []string{FinalizerName},
+			},
+			Spec: v1alpha1.StorageNodeSpec{StorageProviderName: "test-unreachable-provider"},
+		}
+		Expect(fakeClient.Create(ctx, node)).Should(Succeed())
+		node.Status.Phase = v1alpha1.StorageNodePhaseDeleting
+		Expect(fakeClient.Status().Update(ctx, node)).Should(Succeed())
+		Expect(fakeClient.Delete(ctx, node)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should finalize the storage node without checking the health of the provisioner", func() {
+		Expect(fakeClient.Create(ctx, &v1alpha1.StorageProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-unreachable-provider"},
+			Spec:       v1alpha1.StorageProviderSpec{Provisioner: "unreachable"},
+		})).Should(Succeed())
+		p.EXPECT().Health(gomock.Any()).Times(0)
+		p.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		p.EXPECT().Status(gomock.Any(), gomock.Any(), gomock.Any()).Return(v1alpha1.ClusterStatus{}, nil, nil)
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: node.Name, Namespace: node.Namespace}})
+		Expect(err).To(BeNil())
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(node), node)).Should(Succeed())
+		Expect(node.Status.Phase).To(Equal(v1alpha1.StorageNodePhaseDeleteComplete))
+	})
+
+	It("should release the storage node whose storage provider is removed", func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: node.Name, Namespace: node.Namespace}})
+		Expect(err).To(BeNil())
+		err = fakeClient.Get(ctx, client.ObjectKeyFromObject(node), &v1alpha1.StorageNode{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -19,19 +19,21 @@ package controllers
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"hash/fnv"
+	"math/big"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
-	cloudnativepg "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/cloudnative-pg"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
-	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/aws"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/computenode"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/databaserule"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
 
-	cnpg "github.com/cloudnative-pg/cloudnative-pg/api/v1"
-	cnpgutils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
-	dbmeshaws "github.com/database-mesh/golang-sdk/aws"
 	dbmeshawsrds "github.com/database-mesh/golang-sdk/aws/client/rds"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -43,7 +45,6 @@ import (
 	"k8s.io/utils/strings/slices"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
@@ -53,6 +54,9 @@ const (
 	AnnotationKeyRegisterStorageUnitEnabled = "shardingsphere.apache.org/register-storage-unit-enabled"
 	AnnotationKeyComputeNodeName            = "shardingsphere.apache.org/compute-node-name"
 	AnnotationKeyLogicDatabaseName          = "shardingsphere.apache.org/logic-database-name"
+	// AnnotationKeyStorageUnitName is the name of the storage unit registered for the storage node instead of the derived one,
+	// the storage unit is altered to the storage node if it exists, e.g. the storage node is restored from a snapshot of it.
+	AnnotationKeyStorageUnitName = "shardingsphere.apache.org/storage-unit-name"
 
 	ShardingSphereProtocolType = "proxy-frontend-database-protocol-type"
 )
@@ -65,12 +69,8 @@ type StorageNodeReconciler struct {
 	Recorder record.EventRecorder
 	Service  service.Service
 
-	AwsRegion          string
-	AwsAccessKeyID     string
-	AwsSecretAccessKey string
-	AwsSessions        dbmeshaws.Sessions
-
-	CNPG cloudnativepg.CloudNativePG
+	// Provisioners provision the databases, keyed by the provisioner of StorageProvider
+	Provisioners *provisioner.Registry
 }
 
 // +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodes,verbs=get;list;watch;create;update;patch;delete
@@ -78,6 +78,9 @@ type StorageNodeReconciler struct {
 // +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodes/finalizers,verbs=update
 // +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storageproviders,verbs=get;list;watch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;delete;deletecollection
 // +kubebuilder:rbac:groups="",resources=event,verbs=create;patch
 
 // Reconcile handles main function of this controller
@@ -91,9 +94,14 @@ func (r *StorageNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	// Get storageProvider with storagenode.Spec.StorageProviderName
-	storageProvider, err := r.getStorageProvider(ctx, node)
+	// Get storageProvider with storagenode.Spec.StorageProviderName,
+	// the provisioner is not required to be healthy to finalize the storage node
+	deleting := !node.ObjectMeta.DeletionTimestamp.IsZero()
+	storageProvider, err := r.getStorageProvider(ctx, node, !deleting)
 	if err != nil {
+		if deleting && apierrors.IsNotFound(err) && slices.Contains(node.ObjectMeta.Finalizers, FinalizerName) {
+			return r.releaseOrphan(ctx, node)
+		}
 		r.Log.Error(err, fmt.Sprintf("unable to fetch storageProvider %s", node.Spec.StorageProviderName))
 		return ctrl.Result{Requeue: true}, err
 	}
@@ -116,37 +124,20 @@ func (r *StorageNodeReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return r.reconcile(ctx, storageProvider, node)
 }
 
-func (r *StorageNodeReconciler) finalizeCloudNativePG(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) (ctrl.Result, error) {
-	namespacedName := types.NamespacedName{
-		Name:      node.Name,
-		Namespace: node.Namespace,
-	}
-
-	cluster, err := r.CNPG.GetClusterByNamespacedName(ctx, namespacedName)
-	if err != nil {
-		return ctrl.Result{Requeue: true}, err
-	}
+func (r *StorageNodeReconciler) finalize(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) (ctrl.Result, error) {
+	var err error
+	var oldStatus = node.Status.DeepCopy()
 
-	if cluster != nil {
-		if err := r.CNPG.Delete(ctx, cluster); err != nil {
+	// the retained database is still reachable with the credentials, which are not collected with the storage node
+	if storageProvider.Spec.ReclaimPolicy == v1alpha1.StorageReclaimPolicyRetain {
+		if err = r.retainCredentials(ctx, node); err != nil {
+			r.Log.Error(err, "failed to retain credentials")
 			return ctrl.Result{Requeue: true}, err
 		}
 	}
 
-	controllerutil.RemoveFinalizer(node, ChaosFinalizerName)
-	if err := r.Update(ctx, node); err != nil {
-		return ctrl.Result{}, err
-	}
-
-	return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
-}
-
-func (r *StorageNodeReconciler) finalize(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) (ctrl.Result, error) {
-	var err error
-	var oldStatus = node.Status.DeepCopy()
-
 	switch node.Status.Phase {
-	case v1alpha1.StorageNodePhaseReady, v1alpha1.StorageNodePhaseNotReady:
+	case v1alpha1.StorageNodePhaseReady, v1alpha1.StorageNodePhaseNotReady, v1alpha1.StorageNodePhaseResizing:
 		// set storage node status to deleting
 		node.Status.Phase = v1alpha1.StorageNodePhaseDeleting
 	case v1alpha1.StorageNodePhaseDeleting:
@@ -161,12 +152,8 @@ func (r *StorageNodeReconciler) finalize(ctx context.Context, node *v1alpha1.Sto
 		return ctrl.Result{}, nil
 	}
 
-	if storageProvider.Spec.Provisioner == v1alpha1.ProvisionerCloudNativePG {
-		return r.finalizeCloudNativePG(ctx, node, storageProvider)
-	}
-
 	// Try to unregister storage unit in shardingsphere.
-	if err = r.unregisterStorageUnit(ctx, node); err != nil {
+	if err = r.unregisterStorageUnit(ctx, node, storageProvider); err != nil {
 		r.Log.Error(err, "failed to delete storage unit")
 		return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
 	}
@@ -190,36 +177,49 @@ func (r *StorageNodeReconciler) finalize(ctx context.Context, node *v1alpha1.Sto
 	return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
 }
 
+// releaseOrphan removes the finalizer of the storage node whose storage provider is removed,
+// the database can not be deleted without the provider so it is left behind
+func (r *StorageNodeReconciler) releaseOrphan(ctx context.Context, node *v1alpha1.StorageNode) (ctrl.Result, error) {
+	r.Recorder.Eventf(node, corev1.EventTypeWarning, "StorageProviderNotFound", "storageProvider %s not found, the database of node %s/%s is left behind", node.Spec.StorageProviderName, node.GetNamespace(), node.GetName())
+	if err := r.retainCredentials(ctx, node); err != nil {
+		r.Log.Error(err, "failed to retain credentials")
+		return ctrl.Result{Requeue: true}, err
+	}
+	node.ObjectMeta.Finalizers = slices.Filter([]string{}, node.ObjectMeta.Finalizers, func(f string) bool {
+		return f != FinalizerName
+	})
+	if err := r.Update(ctx, node); err != nil {
+		r.Log.Error(err, "failed to remove finalizer")
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{}, nil
+}
+
 func (r *StorageNodeReconciler) reconcile(ctx context.Context, storageProvider *v1alpha1.StorageProvider, node *v1alpha1.StorageNode) (ctrl.Result, error) {
 	var err error
 	var oldStatus = node.Status.DeepCopy()
 
-	// reconcile storage node with storageProvider
-	switch storageProvider.Spec.Provisioner {
-	case v1alpha1.ProvisionerAWSRDSInstance:
-		if err := r.reconcileAwsRdsInstance(ctx, r.getAwsRdsClient(), node, storageProvider); err != nil {
-			r.Recorder.Eventf(node, corev1.EventTypeWarning, "Reconcile Failed", fmt.Sprintf("unable to reconcile AWS RDS Instance %s/%s, err:%s", node.GetNamespace(), node.GetName(), err.Error()))
-			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
-		}
-	case v1alpha1.ProvisionerAWSRDSCluster:
-		if err := r.reconcileAwsRDSCluster(ctx, r.getAwsRdsClient(), node, storageProvider); err != nil {
-			r.Recorder.Eventf(node, corev1.EventTypeWarning, "Reconcile Failed", fmt.Sprintf("unable to reconcile AWS RDS Cluster %s/%s, err:%s", node.GetNamespace(), node.GetName(), err.Error()))
-			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
-		}
-	case v1alpha1.ProvisionerAWSAurora:
-		if err := r.reconcileAwsAurora(ctx, r.getAwsRdsClient(), node, storageProvider); err != nil {
-			r.Recorder.Eventf(node, corev1.EventTypeWarning, "Reconcile Failed", fmt.Sprintf("unable to reconcile AWS Aurora %s/%s, err:%s", node.GetNamespace(), node.GetName(), err.Error()))
-			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
-		}
-	case v1alpha1.ProvisionerCloudNativePG:
-		if err := r.reconcileCloudNativePG(ctx, node, storageProvider); err != nil {
-			r.Recorder.Eventf(node, corev1.EventTypeWarning, "Reconcile Failed", fmt.Sprintf("unable to reconcile CloudNative PG %s/%s, err:%s", node.GetNamespace(), node.GetName(), err.Error()))
-			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
-		}
-	default:
+	// reconcile storage node with the provisioner of storageProvider
+	p := r.getProvisioner(storageProvider)
+	if p == nil {
 		r.Recorder.Event(node, corev1.EventTypeWarning, "UnsupportedDatabaseProvisioner", fmt.Sprintf("unsupported database provisioner %s", storageProvider.Spec.Provisioner))
 		return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
 	}
+	// the databases are created with the master user kept in the credentials Secret
+	if err := r.ensureCredentials(ctx, node, storageProvider); err != nil {
+		r.Recorder.Eventf(node, corev1.EventTypeWarning, "CredentialsNotReady", "unable to get the credentials of %s/%s, err:%s", node.GetNamespace(), node.GetName(), err.Error())
+		return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+	}
+	creds, err := r.getCredentials(ctx, node, storageProvider)
+	if err != nil {
+		r.Recorder.Eventf(node, corev1.EventTypeWarning, "CredentialsNotReady", "unable to get the credentials of %s/%s, err:%s", node.GetNamespace(), node.GetName(), err.Error())
+		return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+	}
+
+	if err := r.reconcileDatabase(ctx, p, node, withCredentials(storageProvider, creds)); err != nil {
+		r.Recorder.Eventf(node, corev1.EventTypeWarning, "Reconcile Failed", fmt.Sprintf("unable to reconcile %s %s/%s, err:%s", storageProvider.Spec.Provisioner, node.GetNamespace(), node.GetName(), err.Error()))
+		return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+	}
 
 	// register storage unit if needed.
 	if err := r.registerStorageUnit(ctx, node, storageProvider); err != nil {
@@ -241,7 +241,8 @@ func (r *StorageNodeReconciler) reconcile(ctx context.Context, storageProvider *
 	return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
 }
 
-func (r *StorageNodeReconciler) getStorageProvider(ctx context.Context, node *v1alpha1.StorageNode) (storageProvider *v1alpha1.StorageProvider, err error) {
+// getStorageProvider returns the storage provider of the node, the provisioner is checked to be healthy if checkHealth is true
+func (r *StorageNodeReconciler) getStorageProvider(ctx context.Context, node *v1alpha1.StorageNode, checkHealth bool) (storageProvider *v1alpha1.StorageProvider, err error) {
 	if node.Spec.StorageProviderName == "" {
 		r.Recorder.Event(node, corev1.EventTypeWarning, "storageProviderNameIsNil", "storageProviderName is nil")
 		return nil, fmt.Errorf("storageProviderName is nil")
@@ -255,14 +256,11 @@ func (r *StorageNodeReconciler) getStorageProvider(ctx context.Context, node *v1
 		return nil, err
 	}
 
-	// check provisioner
-	// aws-like provisioner need aws rds client
-	if storageProvider.Spec.Provisioner == v1alpha1.ProvisionerAWSRDSInstance ||
-		storageProvider.Spec.Provisioner == v1alpha1.ProvisionerAWSAurora ||
-		storageProvider.Spec.Provisioner == v1alpha1.ProvisionerAWSRDSCluster {
-		if r.AwsRegion == "" || r.AwsAccessKeyID == "" || r.AwsSecretAccessKey == "" {
-			r.Recorder.Eventf(node, corev1.EventTypeWarning, "awsCredentialsNotSet", "aws credentials not set")
-			return nil, fmt.Errorf("aws credentials not set")
+	// check provisioner, e.g. aws-like provisioner needs aws credentials
+	if p := r.getProvisioner(storageProvider); p != nil && checkHealth {
+		if err := p.Health(ctx); err != nil {
+			r.Recorder.Eventf(node, corev1.EventTypeWarning, "ProvisionerUnhealthy", "provisioner %s is unhealthy: %s", storageProvider.Spec.Provisioner, err.Error())
+			return nil, err
 		}
 	}
 
@@ -281,8 +279,10 @@ func computeDesiredState(status v1alpha1.StorageNodeStatus) v1alpha1.StorageNode
 			desiredState.Phase = v1alpha1.StorageNodePhaseDeleteComplete
 		}
 	} else {
-		// If the storage node is not being deleted, check if all instances are ready.
-		if (clusterStatus == "" || clusterStatus == string(dbmeshawsrds.DBClusterStatusAvailable)) && allInstancesReady(status.Instances) {
+		// If the storage node is not being deleted, check if it is being resized and all instances are ready.
+		if isResizing(status) {
+			desiredState.Phase = v1alpha1.StorageNodePhaseResizing
+		} else if (clusterStatus == "" || clusterStatus == string(dbmeshawsrds.DBClusterStatusAvailable)) && allInstancesReady(status.Instances) {
 			desiredState.Phase = v1alpha1.StorageNodePhaseReady
 		} else {
 			desiredState.Phase = v1alpha1.StorageNodePhaseNotReady
@@ -371,347 +371,542 @@ func allInstancesReady(instances []v1alpha1.InstanceStatus) bool {
 	return true
 }
 
-func (r *StorageNodeReconciler) getAwsRdsClient() aws.IRdsClient {
-	if _, ok := r.AwsSessions[r.AwsRegion]; !ok {
-		sessions := dbmeshaws.NewSessions().SetCredential(r.AwsRegion, r.AwsAccessKeyID, r.AwsSecretAccessKey).Build()
-		r.AwsSessions = sessions
+// getProvisioner returns the provisioner of the storage provider, or nil if it is not registered
+func (r *StorageNodeReconciler) getProvisioner(storageProvider *v1alpha1.StorageProvider) provisioner.Provisioner {
+	p, ok := r.Provisioners.Get(storageProvider.Spec.Provisioner)
+	if !ok {
+		return nil
 	}
-	return aws.NewRdsClient(dbmeshawsrds.NewService(r.AwsSessions[r.AwsRegion]))
+	return p
 }
 
-func (r *StorageNodeReconciler) reconcileAwsRdsInstance(ctx context.Context, rdsClient aws.IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
-	instance, err := rdsClient.GetInstance(ctx, node)
+// reconcileDatabase creates the database of the storage node if it does not exist,
+// updates it otherwise, then refreshes the status of the storage node with it.
+func (r *StorageNodeReconciler) reconcileDatabase(ctx context.Context, p provisioner.Provisioner, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+	db, err := p.Get(ctx, node, storageProvider)
 	if err != nil {
 		return err
 	}
 
-	if instance == nil && node.Status.Phase != v1alpha1.StorageNodePhaseDeleting {
-		err = rdsClient.CreateInstance(ctx, node, storageProvider.Spec.Parameters)
-		if err != nil {
+	if db == nil && node.Status.Phase != v1alpha1.StorageNodePhaseDeleting {
+		if err := p.Create(ctx, node, storageProvider); err != nil {
 			return err
 		}
 
-		instance, err = rdsClient.GetInstance(ctx, node)
+		db, err = p.Get(ctx, node, storageProvider)
 		if err != nil {
 			return err
 		}
+	} else if db != nil && !db.Deleting {
+		if err := p.Update(ctx, node, storageProvider, db); err != nil {
+			return err
+		}
+		if err := r.resizeDatabase(ctx, p, node, storageProvider, db); err != nil {
+			return err
+		}
 	}
 
-	if err := updateAWSRDSInstanceStatus(node, instance); err != nil {
-		return fmt.Errorf("updateAWSRDSInstanceStatus failed: %w", err)
+	if err := updateDatabaseStatus(ctx, p, node, db); err != nil {
+		return fmt.Errorf("updateDatabaseStatus failed: %w", err)
 	}
-
 	return nil
 }
 
-func updateAWSRDSInstanceStatus(node *v1alpha1.StorageNode, instance *dbmeshawsrds.DescInstance) error {
-	instances := make([]v1alpha1.InstanceStatus, 0)
-
-	if instance == nil {
-		node.Status.Instances = instances
+// resizeDatabase resizes the database to the resources in the spec of the storage node if the provisioner supports it,
+// and keeps the Resizing condition of the storage node until the database is resized.
+func (r *StorageNodeReconciler) resizeDatabase(ctx context.Context, p provisioner.Provisioner, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, db *provisioner.Database) error {
+	resizer, ok := p.(provisioner.Resizer)
+	if !ok {
 		return nil
 	}
 
-	instances = append(instances, v1alpha1.InstanceStatus{
-		Endpoint: v1alpha1.Endpoint{
-			Address: instance.Endpoint.Address,
-			Port:    instance.Endpoint.Port,
-		},
-		Status: string(instance.DBInstanceStatus),
-	})
-
-	node.Status.Instances = instances
-	return nil
-}
-
-// nolint:dupl
-func (r *StorageNodeReconciler) reconcileAwsRDSCluster(ctx context.Context, rdsClient aws.IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
-	rc, err := rdsClient.GetRDSCluster(ctx, node)
+	current, resizing, err := resizer.Resources(ctx, node, storageProvider, db)
 	if err != nil {
-		return err
+		return fmt.Errorf("get resources failed: %w", err)
 	}
 
-	if rc == nil {
-		// create instance
-		err = rdsClient.CreateRDSCluster(ctx, node, storageProvider.Spec.Parameters)
-		if err != nil {
-			return err
-		}
-		rc, err = rdsClient.GetRDSCluster(ctx, node)
-		if err != nil {
-			return err
+	desired := provisioner.DesiredResources(node)
+	changes := provisioner.Changes(current, desired)
+	wasResizing := isResizing(node.Status)
+
+	// the database can not be modified until the previous modification is done
+	if len(changes) > 0 && !resizing {
+		if err := resizer.Resize(ctx, node, storageProvider, db, desired); err != nil {
+			r.Recorder.Eventf(node, corev1.EventTypeWarning, "ResizeFailed", "Failed to resize %s/%s (%s): %s", node.GetNamespace(), node.GetName(), strings.Join(changes, ", "), err.Error())
+			return fmt.Errorf("resize failed: %w", err)
 		}
+		r.Recorder.Eventf(node, corev1.EventTypeNormal, "Resizing", "Resizing %s/%s: %s", node.GetNamespace(), node.GetName(), strings.Join(changes, ", "))
 	}
 
-	// update storage node status
-	if err := updateClusterStatus(ctx, rdsClient, node, rc); err != nil {
-		return fmt.Errorf("updateClusterStatus failed: %w", err)
+	switch {
+	case len(changes) > 0:
+		node.Status.Conditions.UpsertCondition(&v1alpha1.StorageNodeCondition{
+			Type:           v1alpha1.StorageNodeConditionTypeResizing,
+			Status:         corev1.ConditionTrue,
+			LastUpdateTime: metav1.Now(),
+			Reason:         "Resizing",
+			Message:        strings.Join(changes, ", "),
+		})
+	case wasResizing && resizing:
+		// the changes are accepted, wait for them to be applied
+	case wasResizing:
+		node.Status.Conditions.UpsertCondition(&v1alpha1.StorageNodeCondition{
+			Type:           v1alpha1.StorageNodeConditionTypeResizing,
+			Status:         corev1.ConditionFalse,
+			LastUpdateTime: metav1.Now(),
+			Reason:         "Resized",
+		})
+		r.Recorder.Eventf(node, corev1.EventTypeNormal, "Resized", "%s/%s is resized", node.GetNamespace(), node.GetName())
 	}
-
 	return nil
 }
 
-// nolint:dupl
-func (r *StorageNodeReconciler) reconcileAwsAurora(ctx context.Context, rdsClient aws.IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
-	ac, err := rdsClient.GetAuroraCluster(ctx, node)
+// isResizing returns true if the Resizing condition of the storage node is true
+func isResizing(status v1alpha1.StorageNodeStatus) bool {
+	for _, cond := range status.Conditions {
+		if cond.Type == v1alpha1.StorageNodeConditionTypeResizing {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func updateDatabaseStatus(ctx context.Context, p provisioner.Provisioner, node *v1alpha1.StorageNode, db *provisioner.Database) error {
+	clusterStatus, instances, err := p.Status(ctx, node, db)
 	if err != nil {
 		return err
 	}
+	node.Status.Cluster = clusterStatus
+	node.Status.Instances = instances
+	return nil
+}
+
+// deleteDatabaseCluster
+func (r *StorageNodeReconciler) deleteDatabaseCluster(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+	p := r.getProvisioner(storageProvider)
+	if p == nil {
+		return fmt.Errorf("unsupported database provisioner %s", storageProvider.Spec.Provisioner)
+	}
 
-	if ac == nil {
-		// create instance
-		err = rdsClient.CreateAuroraCluster(ctx, node, storageProvider.Spec.Parameters)
-		if err != nil {
-			return err
-		}
-		ac, err = rdsClient.GetAuroraCluster(ctx, node)
-		if err != nil {
+	db, err := p.Get(ctx, node, storageProvider)
+	if err != nil {
+		return fmt.Errorf("get database failed: %w", err)
+	}
+
+	if db != nil && !db.Deleting {
+		if err := p.Delete(ctx, node, storageProvider, db); err != nil {
+			r.Recorder.Eventf(node, corev1.EventTypeWarning, "DeleteFailed", "Failed to delete %s database of node %s/%s: %s", storageProvider.Spec.Provisioner, node.GetNamespace(), node.GetName(), err.Error())
 			return err
 		}
+		r.Recorder.Eventf(node, corev1.EventTypeNormal, "Deleting", "%s database of node %s/%s is deleting", storageProvider.Spec.Provisioner, node.GetNamespace(), node.GetName())
 	}
 
 	// update storage node status
-	if err := updateClusterStatus(ctx, rdsClient, node, ac); err != nil {
-		return fmt.Errorf("updateClusterStatus failed: %w", err)
+	if err := updateDatabaseStatus(ctx, p, node, db); err != nil {
+		return fmt.Errorf("updateDatabaseStatus failed: %w", err)
 	}
-
 	return nil
 }
 
-func updateClusterStatus(ctx context.Context, rdsClient aws.IRdsClient, node *v1alpha1.StorageNode, cluster *dbmeshawsrds.DescCluster) error {
-	// update cluster status
-	clusterStatus := v1alpha1.ClusterStatus{}
-	if cluster != nil {
-		clusterStatus = v1alpha1.ClusterStatus{
-			Status: cluster.Status,
-			PrimaryEndpoint: v1alpha1.Endpoint{
-				Address: cluster.PrimaryEndpoint,
-				Port:    cluster.Port,
-			},
-			ReaderEndpoints: []v1alpha1.Endpoint{
-				{
-					Address: cluster.ReaderEndpoint,
-					Port:    cluster.Port,
-				},
-			},
-		}
+// registerStorageUnit
+func (r *StorageNodeReconciler) registerStorageUnit(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+	// if register storage unit is not enabled, return
+	if node.Annotations[AnnotationKeyRegisterStorageUnitEnabled] != "true" {
+		return nil
 	}
-	node.Status.Cluster = clusterStatus
 
-	// update instances status
-	identifier := node.Annotations[v1alpha1.AnnotationsClusterIdentifier]
-	filters := map[string][]string{
-		"db-cluster-id": {identifier},
-	}
-	instances, err := rdsClient.GetInstancesByFilters(ctx, filters)
-	if err != nil {
-		return fmt.Errorf("GetInstances failed, err:%w", err)
+	if err := r.validateComputeNodeAnnotations(node); err != nil {
+		return err
 	}
 
-	var instanceStatus []v1alpha1.InstanceStatus
-	for _, instance := range instances {
-		instanceStatus = append(instanceStatus, v1alpha1.InstanceStatus{
-			Status: string(instance.DBInstanceStatus),
-			Endpoint: v1alpha1.Endpoint{
-				Address: instance.Endpoint.Address,
-				Port:    instance.Endpoint.Port,
-			}})
+	// if storage unit and the reader storage units are already registered, return
+	_, readerEndpoints := r.getProvisioner(storageProvider).Endpoints(node)
+	readers := getReaderStorageUnits(node, readerEndpoints)
+	if node.Status.Registered && slices.Equal(readerStorageUnitNames(readers), node.Status.ReaderStorageUnits) {
+		return nil
 	}
-	node.Status.Instances = instanceStatus
-	return nil
-}
 
-// deleteDatabaseCluster
-func (r *StorageNodeReconciler) deleteDatabaseCluster(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
-	switch storageProvider.Spec.Provisioner {
-	case v1alpha1.ProvisionerAWSRDSInstance:
-		if err := r.deleteAWSRDSInstance(ctx, r.getAwsRdsClient(), node, storageProvider); err != nil {
-			return fmt.Errorf("delete aws rds instance failed: %w", err)
-		}
-	case v1alpha1.ProvisionerAWSRDSCluster:
-		if err := r.deleteAWSRDSCluster(ctx, r.getAwsRdsClient(), node, storageProvider); err != nil {
-			return fmt.Errorf("delete aws rds cluster failed: %w", err)
-		}
-	case v1alpha1.ProvisionerAWSAurora:
-		if err := r.deleteAWSAurora(ctx, r.getAwsRdsClient(), node, storageProvider); err != nil {
-			return fmt.Errorf("delete aws aurora cluster failed: %w", err)
-		}
-	default:
-		return fmt.Errorf("unsupported database provisioner %s", storageProvider.Spec.Provisioner)
+	// if node is not ready, return
+	if node.Status.Phase != v1alpha1.StorageNodePhaseReady {
+		r.Recorder.Eventf(node, corev1.EventTypeWarning, "RegisterWaiting", "Waiting to register storage unit for node %s/%s: node is not ready", node.GetNamespace(), node.GetName())
+		return nil
 	}
-	return nil
-}
 
-func (r *StorageNodeReconciler) deleteAWSRDSInstance(ctx context.Context, rdsClient aws.IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
-	if node.Annotations[v1alpha1.AnnotationsInstanceIdentifier] == "" {
-		return nil
+	creds, err := r.getCredentials(ctx, node, storageProvider)
+	if err != nil {
+		return fmt.Errorf("getCredentials failed: %w", err)
 	}
 
-	instance, err := rdsClient.GetInstance(ctx, node)
+	ssServer, err := r.getShardingsphereServer(ctx, node, storageProvider)
 	if err != nil {
-		return err
+		return fmt.Errorf("getShardingsphereServer failed: %w", err)
 	}
 
-	if instance != nil && instance.DBInstanceStatus != dbmeshawsrds.DBInstanceStatusDeleting {
-		if err := rdsClient.DeleteInstance(ctx, node, storageProvider); err != nil {
-			r.Recorder.Eventf(node, corev1.EventTypeWarning, "DeleteFailed", "Failed to delete instance %s: %s", node.Annotations[v1alpha1.AnnotationsInstanceIdentifier], err.Error())
+	defer ssServer.Close()
+
+	if !node.Status.Registered {
+		if err := r.registerPrimaryStorageUnit(node, storageProvider, ssServer, creds); err != nil {
 			return err
 		}
-		r.Recorder.Event(node, corev1.EventTypeNormal, "Deleting", fmt.Sprintf("instance %s is deleting", node.Annotations[v1alpha1.AnnotationsInstanceIdentifier]))
-	}
-
-	// update instance status
-	if err := updateAWSRDSInstanceStatus(node, instance); err != nil {
-		return fmt.Errorf("updateAWSRDSInstanceStatus failed: %w", err)
 	}
 
-	return nil
+	return r.syncReaderStorageUnits(node, creds, ssServer, readers)
 }
 
-// nolint:dupl
-func (r *StorageNodeReconciler) deleteAWSRDSCluster(ctx context.Context, rdsClient aws.IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
-	if node.Annotations[v1alpha1.AnnotationsClusterIdentifier] == "" {
-		return nil
+func (r *StorageNodeReconciler) registerPrimaryStorageUnit(node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, ssServer shardingsphere.IServer, creds credentials) error {
+	logicDBName := node.Annotations[AnnotationKeyLogicDatabaseName]
+	dbName := node.Annotations[v1alpha1.AnnotationsInstanceDBName]
+
+	if err := ssServer.CreateDatabase(logicDBName); err != nil {
+		return fmt.Errorf("create database failed: %w", err)
 	}
+	r.Recorder.Eventf(node, corev1.EventTypeNormal, "LogicDatabaseCreated", "LogicDatabase %s is created", logicDBName)
 
-	cluster, err := rdsClient.GetRDSCluster(ctx, node)
+	primary, _ := r.getProvisioner(storageProvider).Endpoints(node)
+	host, port := primary.Address, primary.Port
+
+	exists, err := storageUnitAdopted(node, ssServer, logicDBName)
 	if err != nil {
-		return fmt.Errorf("get rds cluster failed: %w", err)
+		return fmt.Errorf("show storage units failed: %w", err)
 	}
-	if cluster != nil && cluster.Status != string(dbmeshawsrds.DBClusterStatusDeleting) {
-		if err := rdsClient.DeleteRDSCluster(ctx, node, storageProvider); err != nil {
-			r.Recorder.Eventf(node, corev1.EventTypeWarning, "DeleteFailed", "Failed to delete rds cluster %s: %s", node.Annotations[v1alpha1.AnnotationsClusterIdentifier], err.Error())
-			return err
+	if exists {
+		if err := ssServer.AlterStorageUnit(logicDBName, getDSName(node), host, uint(port), dbName, creds.username, creds.password); err != nil {
+			return fmt.Errorf("alter storage unit failed: %w", err)
 		}
-		r.Recorder.Event(node, corev1.EventTypeNormal, "Deleting", fmt.Sprintf("rds cluster %s is deleting", node.Annotations[v1alpha1.AnnotationsClusterIdentifier]))
+		r.Recorder.Eventf(node, corev1.EventTypeNormal, "StorageUnitAltered", "StorageUnit %s is altered to %s:%d/%s", getDSName(node), host, port, dbName)
+	} else {
+		if err := ssServer.RegisterStorageUnit(logicDBName, getDSName(node), host, uint(port), dbName, creds.username, creds.password); err != nil {
+			return fmt.Errorf("register storage node failed: %w", err)
+		}
+		r.Recorder.Eventf(node, corev1.EventTypeNormal, "StorageUnitRegistered", "StorageUnit %s:%d/%s is registered", host, port, dbName)
 	}
 
-	// update storage node status
-	if err := updateClusterStatus(ctx, rdsClient, node, cluster); err != nil {
-		return fmt.Errorf("updateClusterStatus failed: %w", err)
-	}
+	node.Status.Registered = true
 	return nil
 }
 
-// nolint:dupl
-func (r *StorageNodeReconciler) deleteAWSAurora(ctx context.Context, rdsClient aws.IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
-	if node.Annotations[v1alpha1.AnnotationsClusterIdentifier] == "" {
-		return nil
+// storageUnitAdopted returns true if the storage node is registered under the name in its annotations
+// and the storage unit exists, which is altered instead of registered.
+func storageUnitAdopted(node *v1alpha1.StorageNode, ssServer shardingsphere.IServer, logicDBName string) (bool, error) {
+	if node.Annotations[AnnotationKeyStorageUnitName] == "" {
+		return false, nil
 	}
-
-	auroraCluster, err := rdsClient.GetAuroraCluster(ctx, node)
+	units, err := ssServer.ShowStorageUnits(logicDBName)
 	if err != nil {
-		return fmt.Errorf("get aurora cluster failed: %w", err)
+		return false, err
 	}
-	if auroraCluster != nil && auroraCluster.Status != string(dbmeshawsrds.DBClusterStatusDeleting) {
-		if err := rdsClient.DeleteAuroraCluster(ctx, node, storageProvider); err != nil {
-			r.Recorder.Eventf(node, corev1.EventTypeWarning, "DeleteFailed", "Failed to delete aurora cluster %s: %s", node.Annotations[v1alpha1.AnnotationsClusterIdentifier], err.Error())
-			return err
+	for _, unit := range units {
+		if unit.Name == getDSName(node) {
+			return true, nil
 		}
-		r.Recorder.Event(node, corev1.EventTypeNormal, "Deleting", fmt.Sprintf("aurora cluster %s is deleting", node.Annotations[v1alpha1.AnnotationsClusterIdentifier]))
 	}
+	return false, nil
+}
 
-	// update storage node status
-	if err := updateClusterStatus(ctx, rdsClient, node, auroraCluster); err != nil {
-		return fmt.Errorf("updateClusterStatus failed: %w", err)
-	}
-	return nil
+// readerStorageUnit is the storage unit registered for a reader endpoint of the cluster
+type readerStorageUnit struct {
+	name     string
+	endpoint v1alpha1.Endpoint
 }
 
-// registerStorageUnit
-func (r *StorageNodeReconciler) registerStorageUnit(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
-	// if register storage unit is not enabled, return
-	if node.Annotations[AnnotationKeyRegisterStorageUnitEnabled] != "true" {
-		return nil
-	}
+// getReaderStorageUnits returns the storage units of the reader endpoints sorted by name.
+// The name is derived from the endpoint, so it stays the same when other readers come and go.
+func getReaderStorageUnits(node *v1alpha1.StorageNode, endpoints []v1alpha1.Endpoint) []readerStorageUnit {
+	units := []readerStorageUnit{}
+	seen := map[string]bool{}
+	for _, ep := range endpoints {
+		if ep.Address == "" {
+			continue
+		}
 
-	if err := r.validateComputeNodeAnnotations(node); err != nil {
-		return err
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(fmt.Sprintf("%s:%d", ep.Address, ep.Port)))
+		name := fmt.Sprintf("%s_read_%08x", getDSName(node), h.Sum32())
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		units = append(units, readerStorageUnit{name: name, endpoint: ep})
 	}
 
-	// if storage unit is already registered, return
-	if node.Status.Registered {
-		return nil
-	}
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].name < units[j].name
+	})
+	return units
+}
 
-	// if node is not ready, return
-	if node.Status.Phase != v1alpha1.StorageNodePhaseReady {
-		r.Recorder.Eventf(node, corev1.EventTypeWarning, "RegisterWaiting", "Waiting to register storage unit for node %s/%s: node is not ready", node.GetNamespace(), node.GetName())
-		return nil
+func readerStorageUnitNames(readers []readerStorageUnit) []string {
+	var names []string
+	for _, reader := range readers {
+		names = append(names, reader.name)
 	}
+	return names
+}
 
+// getReadwriteSplittingRuleName returns the name of the readwrite-splitting rule
+// over the primary and the reader storage units of the storage node.
+func getReadwriteSplittingRuleName(node *v1alpha1.StorageNode) string {
+	return fmt.Sprintf("rw_%s", strings.ReplaceAll(node.GetName(), "-", "_"))
+}
+
+// syncReaderStorageUnits registers a storage unit for each reader, keeps the readwrite-splitting rule
+// up to date with the readers, then unregisters the storage units of the readers which are gone.
+func (r *StorageNodeReconciler) syncReaderStorageUnits(node *v1alpha1.StorageNode, creds credentials, ssServer shardingsphere.IServer, readers []readerStorageUnit) error {
 	logicDBName := node.Annotations[AnnotationKeyLogicDatabaseName]
 	dbName := node.Annotations[v1alpha1.AnnotationsInstanceDBName]
 
-	ssServer, err := r.getShardingsphereServer(ctx, node)
-	if err != nil {
-		return fmt.Errorf("getShardingsphereServer failed: %w", err)
+	names := readerStorageUnitNames(readers)
+	if len(names) == 0 && len(node.Status.ReaderStorageUnits) == 0 {
+		return nil
 	}
 
-	defer ssServer.Close()
+	for _, reader := range readers {
+		if slices.Contains(node.Status.ReaderStorageUnits, reader.name) {
+			continue
+		}
 
-	if err := ssServer.CreateDatabase(logicDBName); err != nil {
-		return fmt.Errorf("create database failed: %w", err)
+		if err := ssServer.RegisterStorageUnit(logicDBName, reader.name, reader.endpoint.Address, uint(reader.endpoint.Port), dbName, creds.username, creds.password); err != nil {
+			return fmt.Errorf("register reader storage unit failed: %w", err)
+		}
+		r.Recorder.Eventf(node, corev1.EventTypeNormal, "StorageUnitRegistered", "StorageUnit %s:%d/%s is registered", reader.endpoint.Address, reader.endpoint.Port, dbName)
 	}
-	r.Recorder.Eventf(node, corev1.EventTypeNormal, "LogicDatabaseCreated", "LogicDatabase %s is created", logicDBName)
 
-	var host string
-	var port int32
-	var username, password string
-	// get storage unit info from instance
-	if node.Status.Cluster.Status == "" {
-		host, port, username, password = getDatasourceInfoFromInstance(node, storageProvider)
-	} else {
-		host, port, username, password = getDatasourceInfoFromCluster(node, storageProvider)
+	// the rule must stop using the readers which are gone before they are unregistered
+	if err := syncReadwriteSplittingRule(ssServer, logicDBName, getReadwriteSplittingRuleName(node), getDSName(node), names); err != nil {
+		return fmt.Errorf("sync readwrite-splitting rule failed: %w", err)
 	}
 
-	if err := ssServer.RegisterStorageUnit(logicDBName, getDSName(node), host, uint(port), dbName, username, password); err != nil {
-		return fmt.Errorf("register storage node failed: %w", err)
+	for _, name := range node.Status.ReaderStorageUnits {
+		if slices.Contains(names, name) {
+			continue
+		}
+
+		if err := ssServer.UnRegisterStorageUnit(logicDBName, name); err != nil {
+			return fmt.Errorf("unregister reader storage unit failed: %w", err)
+		}
+		r.Recorder.Eventf(node, corev1.EventTypeNormal, "StorageUnitUnRegistered", "StorageUnit %s of node %s/%s is unregistered", name, node.GetNamespace(), node.GetName())
 	}
-	r.Recorder.Eventf(node, corev1.EventTypeNormal, "StorageUnitRegistered", "StorageUnit %s:%d/%s is registered", host, port, dbName)
 
-	node.Status.Registered = true
+	node.Status.ReaderStorageUnits = names
 	return nil
 }
 
-// getDSName returns the datasource name of the storage node.
+// syncReadwriteSplittingRule creates or alters the readwrite-splitting rule with the given readers,
+// the rule is dropped when there is no reader.
+func syncReadwriteSplittingRule(ssServer shardingsphere.IServer, logicDBName, ruleName, writeStorageUnit string, readStorageUnits []string) error {
+	rules, err := ssServer.ShowRules(logicDBName, shardingsphere.RuleTypeReadwriteSplitting)
+	if err != nil {
+		return err
+	}
+
+	exists := false
+	for _, rule := range rules {
+		if rule.Name == ruleName {
+			exists = true
+			break
+		}
+	}
+
+	if len(readStorageUnits) == 0 {
+		if exists {
+			return ssServer.DropRule(logicDBName, shardingsphere.RuleTypeReadwriteSplitting, ruleName)
+		}
+		return nil
+	}
+
+	stmt := databaserule.BuildReadwriteSplitting(&v1alpha1.ReadwriteSplittingRuleSpec{
+		Name:             ruleName,
+		WriteStorageUnit: writeStorageUnit,
+		ReadStorageUnits: readStorageUnits,
+	})
+	if exists {
+		return ssServer.ExecuteDistSQL(logicDBName, stmt.Alter)
+	}
+	return ssServer.ExecuteDistSQL(logicDBName, stmt.Create)
+}
+
+// getDSName returns the datasource name of the storage node, unless it is set in the annotations.
 // datasource name only allows letters, numbers and _, and must start with a letter.
 // ref: https://shardingsphere.apache.org/document/current/en/user-manual/shardingsphere-proxy/distsql/syntax/rdl/storage-unit-definition/register-storage-unit/
 func getDSName(node *v1alpha1.StorageNode) string {
+	if name := node.Annotations[AnnotationKeyStorageUnitName]; name != "" {
+		return name
+	}
 	return fmt.Sprintf("ds_%s", strings.ReplaceAll(node.GetName(), "-", "_"))
 }
 
-func getDatasourceInfoFromInstance(node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) (host string, port int32, username, password string) {
-	ins := node.Status.Instances[0]
-	host = ins.Endpoint.Address
-	port = ins.Endpoint.Port
-	username = node.Annotations[v1alpha1.AnnotationsMasterUsername]
-	if username == "" {
-		username = storageProvider.Spec.Parameters["masterUsername"]
+// credentials is the master user of the databases of a storage node
+type credentials struct {
+	username string
+	password string
+}
+
+// credentialsSecretName returns the name of the Secret generated for the storage node without credentialsSecretRef
+func credentialsSecretName(node *v1alpha1.StorageNode) string {
+	return fmt.Sprintf("%s-credentials", node.GetName())
+}
+
+// ensureCredentials records the Secret holding the master user in the status of the storage node.
+// The Secret is generated and owned by the storage node if the storage node does not reference one.
+func (r *StorageNodeReconciler) ensureCredentials(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+	if node.Spec.CredentialsSecretRef != nil {
+		node.Status.CredentialsSecretRef = node.Spec.CredentialsSecretRef.DeepCopy()
+	} else {
+		name := credentialsSecretName(node)
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: node.Namespace, Name: name}, secret)
+		if apierrors.IsNotFound(err) {
+			if err := r.Create(ctx, newCredentialsSecret(node, storageProvider)); err != nil && !apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("create credentials secret failed: %w", err)
+			}
+			r.Recorder.Eventf(node, corev1.EventTypeNormal, "CredentialsSecretCreated", "Secret %s holding the master user is created", name)
+		} else if err != nil {
+			return fmt.Errorf("get credentials secret failed: %w", err)
+		}
+		node.Status.CredentialsSecretRef = &corev1.LocalObjectReference{Name: name}
 	}
-	password = node.Annotations[v1alpha1.AnnotationsMasterUserPassword]
-	if password == "" {
-		password = storageProvider.Spec.Parameters["masterUserPassword"]
+
+	// the password must not stay in the annotations, it has been moved into the Secret if there was no Secret
+	if _, ok := node.Annotations[v1alpha1.AnnotationsMasterUserPassword]; ok {
+		status := node.Status
+		delete(node.Annotations, v1alpha1.AnnotationsMasterUserPassword)
+		if err := r.Update(ctx, node); err != nil {
+			return fmt.Errorf("remove password annotation failed: %w", err)
+		}
+		node.Status = status
 	}
-	return
+	return nil
 }
 
-func getDatasourceInfoFromCluster(node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) (host string, port int32, username, password string) {
-	cluster := node.Status.Cluster
-	host = cluster.PrimaryEndpoint.Address
-	port = cluster.PrimaryEndpoint.Port
-	username = node.Annotations[v1alpha1.AnnotationsMasterUsername]
-	if username == "" {
-		username = storageProvider.Spec.Parameters["masterUsername"]
+// retainCredentials removes the owner reference to the storage node from the generated credentials Secret,
+// so that the Secret is kept after the storage node is deleted.
+func (r *StorageNodeReconciler) retainCredentials(ctx context.Context, node *v1alpha1.StorageNode) error {
+	if node.Spec.CredentialsSecretRef != nil {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: node.Namespace, Name: credentialsSecretName(node)}, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	refs := []metav1.OwnerReference{}
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID != node.UID {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == len(secret.OwnerReferences) {
+		return nil
 	}
-	password = node.Annotations[v1alpha1.AnnotationsMasterUserPassword]
+	secret.OwnerReferences = refs
+	if err := r.Update(ctx, secret); err != nil {
+		return fmt.Errorf("release credentials secret failed: %w", err)
+	}
+	r.Recorder.Eventf(node, corev1.EventTypeNormal, "CredentialsSecretRetained", "Secret %s holding the master user is retained with the database", secret.Name)
+	return nil
+}
+
+// newCredentialsSecret returns the Secret holding the master user of the storage node.
+// The existing password of the storage node is kept, so the databases created with it are still accessible,
+// otherwise a random password is generated.
+func newCredentialsSecret(node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) *corev1.Secret {
+	password := node.Annotations[v1alpha1.AnnotationsMasterUserPassword]
 	if password == "" {
 		password = storageProvider.Spec.Parameters["masterUserPassword"]
 	}
-	return
+	if password == "" {
+		password = generatePassword()
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName(node),
+			Namespace: node.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(node.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("StorageNode")),
+			},
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			v1alpha1.StorageNodeCredentialsUsernameKey: []byte(defaultUsername(node, storageProvider)),
+			v1alpha1.StorageNodeCredentialsPasswordKey: []byte(password),
+		},
+	}
+}
+
+// defaultUsername returns the master username of the storage node when the credentials Secret does not contain one
+func defaultUsername(node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) string {
+	if username := node.Annotations[v1alpha1.AnnotationsMasterUsername]; username != "" {
+		return username
+	}
+	if username := storageProvider.Spec.Parameters["masterUsername"]; username != "" {
+		return username
+	}
+	if storageProviderDriver(storageProvider) == shardingsphere.DriverPostgres {
+		return "postgres"
+	}
+	return "root"
 }
 
-func (r *StorageNodeReconciler) unregisterStorageUnit(ctx context.Context, node *v1alpha1.StorageNode) error {
+// generatePassword returns a random password accepted by all the provisioners,
+// e.g. AWS RDS requires 8 to 41 printable characters except '/', '"', '@' and space.
+func generatePassword() string {
+	const (
+		chars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+		length = 24
+	)
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			panic(fmt.Sprintf("generate password failed: %s", err))
+		}
+		b[i] = chars[n.Int64()]
+	}
+	return string(b)
+}
+
+// getCredentials returns the master user kept in the credentials Secret of the storage node
+func (r *StorageNodeReconciler) getCredentials(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) (credentials, error) {
+	ref := node.Status.CredentialsSecretRef
+	if ref == nil {
+		ref = node.Spec.CredentialsSecretRef
+	}
+	if ref == nil {
+		return credentials{}, fmt.Errorf("credentials secret of %s/%s is not set", node.GetNamespace(), node.GetName())
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: node.Namespace, Name: ref.Name}, secret); err != nil {
+		return credentials{}, fmt.Errorf("get credentials secret %s failed: %w", ref.Name, err)
+	}
+
+	creds := credentials{
+		username: string(secret.Data[v1alpha1.StorageNodeCredentialsUsernameKey]),
+		password: string(secret.Data[v1alpha1.StorageNodeCredentialsPasswordKey]),
+	}
+	if creds.password == "" {
+		return credentials{}, fmt.Errorf("key %s of credentials secret %s is empty", v1alpha1.StorageNodeCredentialsPasswordKey, ref.Name)
+	}
+	if creds.username == "" {
+		creds.username = defaultUsername(node, storageProvider)
+	}
+	return creds, nil
+}
+
+// withCredentials returns a copy of the storage provider whose master user is the given one,
+// so the provisioners create the databases with the credentials in the Secret instead of the parameters.
+func withCredentials(storageProvider *v1alpha1.StorageProvider, creds credentials) *v1alpha1.StorageProvider {
+	sp := storageProvider.DeepCopy()
+	if sp.Spec.Parameters == nil {
+		sp.Spec.Parameters = map[string]string{}
+	}
+	sp.Spec.Parameters["masterUsername"] = creds.username
+	sp.Spec.Parameters["masterUserPassword"] = creds.password
+	return sp
+}
+
+func (r *StorageNodeReconciler) unregisterStorageUnit(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
 	if !node.Status.Registered {
 		return nil
 	}
@@ -721,13 +916,20 @@ func (r *StorageNodeReconciler) unregisterStorageUnit(ctx context.Context, node
 
 	logicDBName := node.Annotations[AnnotationKeyLogicDatabaseName]
 
-	ssServer, err := r.getShardingsphereServer(ctx, node)
+	ssServer, err := r.getShardingsphereServer(ctx, node, storageProvider)
 	if err != nil {
 		return fmt.Errorf("getShardingsphereServer failed: %w", err)
 	}
 
 	defer ssServer.Close()
 
+	// the readwrite-splitting rule and the reader storage units go first, as they use the primary one
+	if len(node.Status.ReaderStorageUnits) > 0 {
+		if err := r.syncReaderStorageUnits(node, credentials{}, ssServer, nil); err != nil {
+			return err
+		}
+	}
+
 	if err := ssServer.UnRegisterStorageUnit(logicDBName, getDSName(node)); err != nil {
 		return fmt.Errorf("unregister storage unit failed: %w", err)
 	}
@@ -755,41 +957,61 @@ func (r *StorageNodeReconciler) validateComputeNodeAnnotations(node *v1alpha1.St
 	return nil
 }
 
-func (r *StorageNodeReconciler) getShardingsphereServer(ctx context.Context, node *v1alpha1.StorageNode) (shardingsphere.IServer, error) {
+func (r *StorageNodeReconciler) getShardingsphereServer(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) (shardingsphere.IServer, error) {
+	return getShardingSphereServer(ctx, r.Client, r.Service, types.NamespacedName{
+		Name:      node.Annotations[AnnotationKeyComputeNodeName],
+		Namespace: node.Namespace,
+	}, storageProviderDriver(storageProvider))
+}
+
+// storageProviderDriver returns the driver of the databases provisioned by the storage provider,
+// or empty if it is unknown.
+func storageProviderDriver(storageProvider *v1alpha1.StorageProvider) string {
+	if storageProvider == nil {
+		return ""
+	}
+	if storageProvider.Spec.Provisioner == v1alpha1.ProvisionerCloudNativePG {
+		return shardingsphere.DriverPostgres
+	}
+
+	engine := strings.ToLower(storageProvider.Spec.Parameters["engine"])
+	switch {
+	case strings.Contains(engine, "postgres"):
+		return shardingsphere.DriverPostgres
+	case strings.Contains(engine, "mysql"), strings.Contains(engine, "mariadb"):
+		return shardingsphere.DriverMySQL
+	default:
+		return ""
+	}
+}
+
+// getShardingSphereServer returns a DistSQL connection to the ComputeNode with the given name.
+// The driver is decided by the frontend database protocol type of the ComputeNode,
+// and falls back to defaultDriver, then MySQL, when the protocol type is not set.
+func getShardingSphereServer(ctx context.Context, c client.Client, s service.Service, computeNode types.NamespacedName, defaultDriver string) (shardingsphere.IServer, error) {
 	var (
-		driver, host, username, password string
-		port                             uint
+		host string
+		port uint
 	)
 
 	// get compute node
 	cn := &v1alpha1.ComputeNode{}
-	if err := r.Client.Get(ctx, types.NamespacedName{
-		Name:      node.Annotations[AnnotationKeyComputeNodeName],
-		Namespace: node.Namespace,
-	}, cn); err != nil {
+	if err := c.Get(ctx, computeNode, cn); err != nil {
 		return nil, fmt.Errorf("get compute node failed: %w", err)
 	}
 
-	serverConf := cn.Spec.Bootstrap.ServerConfig
-
-	driver, ok := serverConf.Props[ShardingSphereProtocolType]
-	if !ok || driver == "" {
-		driver = "mysql"
+	secrets, err := getReferencedSecrets(ctx, c, cn)
+	if err != nil {
+		return nil, fmt.Errorf("get secrets of compute node failed: %w", err)
 	}
-	driver = strings.ToLower(driver)
 
-	if len(serverConf.Authority.Users) == 0 {
-		return nil, fmt.Errorf("no user in compute node %s/%s", cn.Namespace, cn.Name)
+	driver, username, password, err := computeNodeCredential(cn, defaultDriver, secrets)
+	if err != nil {
+		return nil, err
 	}
 
-	username = strings.Split(serverConf.Authority.Users[0].User, "@")[0]
-	password = serverConf.Authority.Users[0].Password
-
 	// get service of compute node
-	svc, err := r.Service.GetByNamespacedName(ctx, types.NamespacedName{
-		Name:      node.Annotations[AnnotationKeyComputeNodeName],
-		Namespace: node.Namespace,
-	})
+	svc, err := s.GetByNamespacedName(ctx, computeNode)
 
 	if err != nil || svc == nil {
 		return nil, fmt.Errorf("get service failed: %w", err)
@@ -807,100 +1029,29 @@ func (r *StorageNodeReconciler) getShardingsphereServer(ctx context.Context, nod
 	return ssServer, nil
 }
 
-func (r *StorageNodeReconciler) reconcileCloudNativePG(ctx context.Context, sn *v1alpha1.StorageNode, sp *v1alpha1.StorageProvider) error {
-	cluster, err := r.getCloudNativePGCluster(ctx, types.NamespacedName{Namespace: sn.Namespace, Name: sn.Name})
-	if err != nil {
-		return err
-	}
-
-	if err := updateCloudNativePGCluster(ctx, sn, cluster); err != nil {
-		return err
-	}
-
-	if cluster != nil {
-		return r.updateCloudNativePGCluster(ctx, sn, sp, cluster)
-	}
-	return r.createCloudNativePGCluster(ctx, sn, sp)
-}
+// computeNodeCredential returns the driver and the first authority user of the ComputeNode,
+// the password is resolved from the secrets referenced by the ComputeNode.
+func computeNodeCredential(cn *v1alpha1.ComputeNode, defaultDriver string, secrets []*corev1.Secret) (driver, username, password string, err error) {
+	serverConf := cn.Spec.Bootstrap.ServerConfig
 
-func updateCloudNativePGCluster(ctx context.Context, node *v1alpha1.StorageNode, cluster *cnpg.Cluster) error {
-	if cluster == nil {
-		return nil
-	}
-	var cs string
-	if cluster.Status.Phase == cnpg.PhaseHealthy {
-		node.Status.Phase = v1alpha1.StorageNodePhaseReady
-		cs = "available"
-	} else {
-		node.Status.Phase = v1alpha1.StorageNodePhaseNotReady
+	driver = shardingsphere.DriverOf(serverConf.Props[ShardingSphereProtocolType])
+	if driver == "" {
+		driver = defaultDriver
 	}
-
-	node.Status.Cluster = v1alpha1.ClusterStatus{
-		Status: cs,
-		PrimaryEndpoint: v1alpha1.Endpoint{
-			Address: cluster.Status.WriteService,
-			Port:    5432,
-		},
-		ReaderEndpoints: []v1alpha1.Endpoint{
-			{
-				Address: cluster.Status.ReadService,
-				Port:    5432,
-			},
-		},
+	if driver == "" {
+		driver = shardingsphere.DriverMySQL
 	}
 
-	node.Status.Instances = []v1alpha1.InstanceStatus{}
-
-	for s, pgins := range cluster.Status.InstancesStatus {
-		var stat string
-		if s == cnpgutils.PodHealthy {
-			stat = "available"
-		} else {
-			stat = string(s)
-		}
-		for _, pg := range pgins {
-			ins := v1alpha1.InstanceStatus{
-				Status: stat,
-				Endpoint: v1alpha1.Endpoint{
-					Address: pg,
-					Port:    5432,
-				},
-			}
-
-			node.Status.Instances = append(node.Status.Instances, ins)
-		}
+	if len(serverConf.Authority.Users) == 0 {
+		return "", "", "", fmt.Errorf("no user in compute node %s/%s", cn.Namespace, cn.Name)
 	}
 
-	return nil
-}
-
-func (r *StorageNodeReconciler) getCloudNativePGCluster(ctx context.Context, namespacedName types.NamespacedName) (*cnpg.Cluster, error) {
-	c, err := r.CNPG.GetClusterByNamespacedName(ctx, namespacedName)
+	username = strings.Split(serverConf.Authority.Users[0].User, "@")[0]
+	password, err = computenode.UserPassword(cn, serverConf.Authority.Users[0], secrets)
 	if err != nil {
-		return nil, err
-	}
-	return c, nil
-}
-
-func (r *StorageNodeReconciler) createCloudNativePGCluster(ctx context.Context, sn *v1alpha1.StorageNode, sp *v1alpha1.StorageProvider) error {
-	cluster := r.CNPG.Build(ctx, sn, sp)
-	err := r.CNPG.Create(ctx, cluster)
-	if err != nil && apierrors.IsAlreadyExists(err) || err == nil {
-		return nil
+		return "", "", "", err
 	}
-	return err
-}
-
-func (r *StorageNodeReconciler) updateCloudNativePGCluster(ctx context.Context, sn *v1alpha1.StorageNode, sp *v1alpha1.StorageProvider, cluster *cnpg.Cluster) error {
-	exp := r.CNPG.Build(ctx, sn, sp)
-	exp.ObjectMeta = cluster.ObjectMeta
-	exp.Labels = cluster.Labels
-	exp.Annotations = cluster.Annotations
-
-	if !reflect.DeepEqual(cluster.Spec, exp.Spec) {
-		return r.CNPG.Update(ctx, exp)
-	}
-	return nil
+	return driver, username, password, nil
 }
 
 // SetupWithManager sets up the controller with the Manager
@@ -0,0 +1,317 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	StorageNodeRestoreControllerName = "storage-node-restore-controller"
+
+	// AnnotationKeyRestoredBy is the StorageNodeRestore which creates the storage node
+	AnnotationKeyRestoredBy = "shardingsphere.apache.org/restored-by"
+)
+
+// StorageNodeRestoreReconciler restores the snapshots of storage nodes into new storage nodes
+type StorageNodeRestoreReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenoderestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenoderestores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodesnapshots,verbs=get;list;watch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodes,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storageproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=event,verbs=create;patch
+
+// Reconcile handles main function of this controller
+func (r *StorageNodeRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues(StorageNodeRestoreControllerName, req.NamespacedName)
+
+	restore := &v1alpha1.StorageNodeRestore{}
+	if err := r.Get(ctx, req.NamespacedName, restore); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// the restore is done
+	if restore.Status.Phase == v1alpha1.StorageNodeRestorePhaseCompleted || restore.Status.Phase == v1alpha1.StorageNodeRestorePhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	status := restore.Status.DeepCopy()
+	if err := r.reconcile(ctx, restore); err != nil {
+		logger.Error(err, "Failed to reconcile storage node restore")
+		r.Recorder.Event(restore, corev1.EventTypeWarning, "ReconcileFailed", err.Error())
+	}
+
+	if status.Phase != restore.Status.Phase || status.Message != restore.Status.Message || status.StorageNodeName != restore.Status.StorageNodeName {
+		if err := r.Status().Update(ctx, restore); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if restore.Status.Phase == v1alpha1.StorageNodeRestorePhaseCompleted || restore.Status.Phase == v1alpha1.StorageNodeRestorePhaseFailed {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
+}
+
+// reconcile creates the storage node from the snapshot once the snapshot is ready,
+// then waits for the storage node to be ready and registered.
+func (r *StorageNodeRestoreReconciler) reconcile(ctx context.Context, restore *v1alpha1.StorageNodeRestore) error {
+	snapshot := &v1alpha1.StorageNodeSnapshot{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.SnapshotName}, snapshot); err != nil {
+		if apierrors.IsNotFound(err) {
+			restore.Status.Phase = v1alpha1.StorageNodeRestorePhasePending
+			restore.Status.Message = fmt.Sprintf("snapshot %s is not found", restore.Spec.SnapshotName)
+			return nil
+		}
+		return fmt.Errorf("get snapshot failed: %w", err)
+	}
+	switch snapshot.Status.Phase {
+	case v1alpha1.StorageNodeSnapshotPhaseReady:
+	case v1alpha1.StorageNodeSnapshotPhaseFailed:
+		return r.fail(restore, fmt.Sprintf("snapshot %s is failed", snapshot.Name))
+	default:
+		restore.Status.Phase = v1alpha1.StorageNodeRestorePhasePending
+		restore.Status.Message = fmt.Sprintf("snapshot %s is not ready", snapshot.Name)
+		return nil
+	}
+	if snapshot.Status.Source == nil {
+		return r.fail(restore, fmt.Sprintf("snapshot %s has no source storage node", snapshot.Name))
+	}
+	if restore.Spec.RegisterStorageUnit && snapshot.Status.Source.StorageUnit == nil {
+		return r.fail(restore, fmt.Sprintf("storage node of snapshot %s was not registered as a storage unit", snapshot.Name))
+	}
+
+	name := restoredStorageNodeName(restore)
+	node := &v1alpha1.StorageNode{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: name}, node)
+	if apierrors.IsNotFound(err) {
+		return r.createStorageNode(ctx, restore, snapshot, name)
+	}
+	if err != nil {
+		return fmt.Errorf("get storage node failed: %w", err)
+	}
+	if node.Annotations[AnnotationKeyRestoredBy] != restore.Name {
+		return r.fail(restore, fmt.Sprintf("storage node %s already exists", name))
+	}
+	restore.Status.StorageNodeName = name
+
+	if node.Status.Phase != v1alpha1.StorageNodePhaseReady || (restore.Spec.RegisterStorageUnit && !node.Status.Registered) {
+		restore.Status.Phase = v1alpha1.StorageNodeRestorePhaseRestoring
+		restore.Status.Message = ""
+		return nil
+	}
+
+	if restore.Spec.RegisterStorageUnit {
+		if err := r.handOverStorageUnit(ctx, restore, snapshot.Spec.StorageNodeName, name); err != nil {
+			return err
+		}
+	}
+
+	restore.Status.Phase = v1alpha1.StorageNodeRestorePhaseCompleted
+	restore.Status.Message = ""
+	now := metav1.Now()
+	restore.Status.CompletionTime = &now
+	r.Recorder.Eventf(restore, corev1.EventTypeNormal, "RestoreCompleted", "StorageNode %s is restored from snapshot %s", name, snapshot.Name)
+	return nil
+}
+
+func (r *StorageNodeRestoreReconciler) fail(restore *v1alpha1.StorageNodeRestore, msg string) error {
+	restore.Status.Phase = v1alpha1.StorageNodeRestorePhaseFailed
+	restore.Status.Message = msg
+	r.Recorder.Event(restore, corev1.EventTypeWarning, "RestoreFailed", msg)
+	return nil
+}
+
+// restoredStorageNodeName returns the storage node name in the spec, or the name of the restore
+func restoredStorageNodeName(restore *v1alpha1.StorageNodeRestore) string {
+	if restore.Spec.StorageNodeName != "" {
+		return restore.Spec.StorageNodeName
+	}
+	return restore.Name
+}
+
+// createStorageNode creates the storage node restored from the snapshot, with the master user of the snapshot
+func (r *StorageNodeRestoreReconciler) createStorageNode(ctx context.Context, restore *v1alpha1.StorageNodeRestore, snapshot *v1alpha1.StorageNodeSnapshot, name string) error {
+	providerName := snapshot.Status.StorageProviderName
+	if restore.Spec.StorageProviderName != "" && restore.Spec.StorageProviderName != providerName {
+		same, err := r.sameProvisioner(ctx, providerName, restore.Spec.StorageProviderName)
+		if err != nil {
+			return err
+		}
+		if !same {
+			return r.fail(restore, fmt.Sprintf("provisioner of storage provider %s differs from the one of snapshot %s", restore.Spec.StorageProviderName, snapshot.Name))
+		}
+		providerName = restore.Spec.StorageProviderName
+	}
+
+	node := newRestoredStorageNode(restore, snapshot, name, providerName)
+
+	var secret *corev1.Secret
+	if snapshot.Status.CredentialsSecretRef != nil {
+		var err error
+		if secret, err = r.copyCredentials(ctx, snapshot, node); err != nil {
+			return err
+		}
+		node.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: secret.Name}
+	}
+
+	if err := r.Create(ctx, node); err != nil {
+		return fmt.Errorf("create storage node failed: %w", err)
+	}
+	r.Recorder.Eventf(restore, corev1.EventTypeNormal, "StorageNodeCreated", "StorageNode %s is created from snapshot %s", name, snapshot.Name)
+
+	// the Secret goes with the storage node
+	if secret != nil {
+		secret.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(node.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("StorageNode")),
+		}
+		if err := r.Update(ctx, secret); err != nil {
+			return fmt.Errorf("update credentials secret failed: %w", err)
+		}
+	}
+
+	restore.Status.StorageNodeName = name
+	restore.Status.Phase = v1alpha1.StorageNodeRestorePhaseRestoring
+	restore.Status.Message = ""
+	return nil
+}
+
+// newRestoredStorageNode returns the storage node with the spec of the source storage node of the snapshot,
+// which the provisioner restores from the snapshot identifier in the annotations.
+func newRestoredStorageNode(restore *v1alpha1.StorageNodeRestore, snapshot *v1alpha1.StorageNodeSnapshot, name, providerName string) *v1alpha1.StorageNode {
+	source := snapshot.Status.Source
+	node := &v1alpha1.StorageNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: restore.Namespace,
+			Annotations: map[string]string{
+				AnnotationKeyRestoredBy:                restore.Name,
+				v1alpha1.AnnotationsSnapshotIdentifier: snapshot.Status.SnapshotIdentifier,
+				// the aws provisioners take the identifier of the instance or the cluster, and the others ignore them
+				v1alpha1.AnnotationsInstanceIdentifier: name,
+				v1alpha1.AnnotationsClusterIdentifier:  name,
+			},
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+	node.Spec.StorageProviderName = providerName
+	node.Spec.CredentialsSecretRef = nil
+	if source.DatabaseName != "" {
+		node.Annotations[v1alpha1.AnnotationsInstanceDBName] = source.DatabaseName
+	}
+	if restore.Spec.RegisterStorageUnit {
+		node.Annotations[AnnotationKeyRegisterStorageUnitEnabled] = "true"
+		node.Annotations[AnnotationKeyLogicDatabaseName] = source.StorageUnit.LogicDatabaseName
+		node.Annotations[AnnotationKeyComputeNodeName] = source.StorageUnit.ComputeNodeName
+		node.Annotations[AnnotationKeyStorageUnitName] = source.StorageUnit.Name
+	}
+	return node
+}
+
+// copyCredentials copies the master user of the snapshot into the credentials Secret of the storage node
+func (r *StorageNodeRestoreReconciler) copyCredentials(ctx context.Context, snapshot *v1alpha1.StorageNodeSnapshot, node *v1alpha1.StorageNode) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Status.CredentialsSecretRef.Name}, secret); err != nil {
+		return nil, fmt.Errorf("get credentials secret of snapshot failed: %w", err)
+	}
+
+	cp := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentialsSecretName(node),
+			Namespace: node.Namespace,
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+	if err := r.Create(ctx, cp); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("create credentials secret failed: %w", err)
+		}
+		// left by a previous attempt
+		if err := r.Get(ctx, client.ObjectKeyFromObject(cp), cp); err != nil {
+			return nil, fmt.Errorf("get credentials secret failed: %w", err)
+		}
+	}
+	return cp, nil
+}
+
+func (r *StorageNodeRestoreReconciler) sameProvisioner(ctx context.Context, a, b string) (bool, error) {
+	spa, spb := &v1alpha1.StorageProvider{}, &v1alpha1.StorageProvider{}
+	if err := r.Get(ctx, types.NamespacedName{Name: a}, spa); err != nil {
+		return false, fmt.Errorf("get storage provider %s failed: %w", a, err)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Name: b}, spb); err != nil {
+		return false, fmt.Errorf("get storage provider %s failed: %w", b, err)
+	}
+	return spa.Spec.Provisioner == spb.Spec.Provisioner, nil
+}
+
+// handOverStorageUnit stops the source storage node managing the storage unit now altered to the restored one,
+// so the storage unit is not unregistered when the source storage node is deleted.
+func (r *StorageNodeRestoreReconciler) handOverStorageUnit(ctx context.Context, restore *v1alpha1.StorageNodeRestore, sourceName, name string) error {
+	if sourceName == name {
+		return nil
+	}
+	source := &v1alpha1.StorageNode{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: restore.Namespace, Name: sourceName}, source); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if source.Annotations[AnnotationKeyRegisterStorageUnitEnabled] != "true" && !source.Status.Registered {
+		return nil
+	}
+
+	source.Annotations[AnnotationKeyRegisterStorageUnitEnabled] = "false"
+	if err := r.Update(ctx, source); err != nil {
+		return fmt.Errorf("update source storage node failed: %w", err)
+	}
+	source.Status.Registered = false
+	if err := r.Status().Update(ctx, source); err != nil {
+		return fmt.Errorf("update status of source storage node failed: %w", err)
+	}
+	r.Recorder.Eventf(restore, corev1.EventTypeNormal, "StorageUnitHandedOver", "StorageUnit of StorageNode %s is handed over to %s", sourceName, name)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *StorageNodeRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.StorageNodeRestore{}).
+		Complete(r)
+}
@@ -0,0 +1,327 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/strings/slices"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	StorageNodeSnapshotControllerName = "storage-node-snapshot-controller"
+
+	// maxSnapshotIdentifierLength is the max length of the names of kubernetes objects, which is shorter than the one of aws
+	maxSnapshotIdentifierLength = 253
+)
+
+// StorageNodeSnapshotReconciler takes the snapshots of the databases of storage nodes
+type StorageNodeSnapshotReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+
+	// Provisioners take the snapshots, keyed by the provisioner of StorageProvider
+	Provisioners *provisioner.Registry
+}
+
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodesnapshots,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodesnapshots/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodesnapshots/finalizers,verbs=update
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storagenodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storageproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=backups,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=event,verbs=create;patch
+
+// Reconcile handles main function of this controller
+func (r *StorageNodeSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues(StorageNodeSnapshotControllerName, req.NamespacedName)
+
+	snapshot := &v1alpha1.StorageNodeSnapshot{}
+	if err := r.Get(ctx, req.NamespacedName, snapshot); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if snapshot.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !slices.Contains(snapshot.ObjectMeta.Finalizers, FinalizerName) {
+			snapshot.ObjectMeta.Finalizers = append(snapshot.ObjectMeta.Finalizers, FinalizerName)
+			if err := r.Update(ctx, snapshot); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if slices.Contains(snapshot.ObjectMeta.Finalizers, FinalizerName) {
+		return r.finalize(ctx, snapshot)
+	}
+
+	// the snapshot is done
+	if snapshot.Status.Phase == v1alpha1.StorageNodeSnapshotPhaseReady || snapshot.Status.Phase == v1alpha1.StorageNodeSnapshotPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+
+	status := snapshot.Status.DeepCopy()
+	if err := r.reconcile(ctx, snapshot); err != nil {
+		logger.Error(err, "Failed to reconcile storage node snapshot")
+		r.Recorder.Event(snapshot, corev1.EventTypeWarning, "ReconcileFailed", err.Error())
+	}
+
+	if !equalSnapshotStatus(status, &snapshot.Status) {
+		if err := r.Status().Update(ctx, snapshot); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if snapshot.Status.Phase == v1alpha1.StorageNodeSnapshotPhaseReady || snapshot.Status.Phase == v1alpha1.StorageNodeSnapshotPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: defaultRequeueTime}, nil
+}
+
+func equalSnapshotStatus(a, b *v1alpha1.StorageNodeSnapshotStatus) bool {
+	return a.Phase == b.Phase && a.Message == b.Message && a.SnapshotIdentifier == b.SnapshotIdentifier &&
+		a.AllocatedStorage == b.AllocatedStorage && a.CreationTime.Equal(b.CreationTime)
+}
+
+// reconcile starts the snapshot once the storage node is ready, then follows it until it is ready or failed
+func (r *StorageNodeSnapshotReconciler) reconcile(ctx context.Context, snapshot *v1alpha1.StorageNodeSnapshot) error {
+	if snapshot.Status.SnapshotIdentifier == "" {
+		return r.createSnapshot(ctx, snapshot)
+	}
+
+	sp, snapshotter, err := r.getSnapshotter(ctx, snapshot.Status.StorageProviderName)
+	if err != nil {
+		return err
+	}
+	s, err := snapshotter.GetSnapshot(ctx, sp, snapshot)
+	if err != nil {
+		return fmt.Errorf("get snapshot failed: %w", err)
+	}
+
+	switch {
+	case s == nil:
+		snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhaseFailed
+		snapshot.Status.Message = fmt.Sprintf("snapshot %s is not found", snapshot.Status.SnapshotIdentifier)
+		r.Recorder.Event(snapshot, corev1.EventTypeWarning, "SnapshotFailed", snapshot.Status.Message)
+	case s.Failed:
+		snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhaseFailed
+		snapshot.Status.Message = s.Message
+		r.Recorder.Eventf(snapshot, corev1.EventTypeWarning, "SnapshotFailed", "Snapshot %s failed: %s", snapshot.Status.SnapshotIdentifier, s.Message)
+	case s.Ready:
+		snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhaseReady
+		snapshot.Status.Message = ""
+		snapshot.Status.AllocatedStorage = s.AllocatedStorage
+		if !s.CreationTime.IsZero() {
+			snapshot.Status.CreationTime = &metav1.Time{Time: s.CreationTime}
+		}
+		r.Recorder.Eventf(snapshot, corev1.EventTypeNormal, "SnapshotReady", "Snapshot %s is ready", snapshot.Status.SnapshotIdentifier)
+	default:
+		snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhaseCreating
+	}
+	return nil
+}
+
+// createSnapshot records the storage node and its master user, then starts taking the snapshot
+func (r *StorageNodeSnapshotReconciler) createSnapshot(ctx context.Context, snapshot *v1alpha1.StorageNodeSnapshot) error {
+	node := &v1alpha1.StorageNode{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Spec.StorageNodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.pending(snapshot, fmt.Sprintf("storage node %s is not found", snapshot.Spec.StorageNodeName))
+		}
+		return fmt.Errorf("get storage node failed: %w", err)
+	}
+	if node.Status.Phase != v1alpha1.StorageNodePhaseReady {
+		return r.pending(snapshot, fmt.Sprintf("storage node %s is not ready", node.Name))
+	}
+
+	sp, snapshotter, err := r.getSnapshotter(ctx, node.Spec.StorageProviderName)
+	if err != nil {
+		if _, ok := err.(unsupportedSnapshotError); ok {
+			snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhaseFailed
+			snapshot.Status.Message = err.Error()
+			return nil
+		}
+		return err
+	}
+
+	if node.Status.CredentialsSecretRef != nil {
+		ref, err := r.copyCredentials(ctx, snapshot, node)
+		if err != nil {
+			return err
+		}
+		snapshot.Status.CredentialsSecretRef = ref
+	}
+
+	snapshot.Status.StorageProviderName = sp.Name
+	snapshot.Status.Source = snapshotSource(node)
+	snapshot.Status.SnapshotIdentifier = snapshotIdentifier(snapshot)
+	if err := snapshotter.CreateSnapshot(ctx, node, sp, snapshot); err != nil {
+		snapshot.Status.SnapshotIdentifier = ""
+		return fmt.Errorf("create snapshot failed: %w", err)
+	}
+
+	snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhaseCreating
+	snapshot.Status.Message = ""
+	r.Recorder.Eventf(snapshot, corev1.EventTypeNormal, "SnapshotCreating", "Snapshot %s of storage node %s is being taken", snapshot.Status.SnapshotIdentifier, node.Name)
+	return nil
+}
+
+func (r *StorageNodeSnapshotReconciler) pending(snapshot *v1alpha1.StorageNodeSnapshot, msg string) error {
+	snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhasePending
+	snapshot.Status.Message = msg
+	return nil
+}
+
+// snapshotIdentifier returns the identifier in the spec, or the namespace, the name and a fragment of the uid of the snapshot,
+// so that the snapshots of the same name in different namespaces or recreated ones do not share the identifier in the provider.
+// The default identifier is made of letters, digits and single hyphens as required by aws.
+func snapshotIdentifier(snapshot *v1alpha1.StorageNodeSnapshot) string {
+	if snapshot.Spec.SnapshotIdentifier != "" {
+		return snapshot.Spec.SnapshotIdentifier
+	}
+
+	parts := []string{snapshot.Namespace, snapshot.Name}
+	if uid := string(snapshot.UID); uid != "" {
+		parts = append(parts, strings.SplitN(uid, "-", 2)[0])
+	}
+	id := strings.Join(strings.FieldsFunc(strings.ToLower(strings.Join(parts, "-")), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "-")
+	if id == "" || id[0] < 'a' || id[0] > 'z' {
+		id = "snapshot-" + id
+	}
+	if len(id) > maxSnapshotIdentifierLength {
+		id = strings.TrimRight(id[:maxSnapshotIdentifierLength], "-")
+	}
+	return id
+}
+
+// snapshotSource returns the storage node to restore with the snapshot
+func snapshotSource(node *v1alpha1.StorageNode) *v1alpha1.StorageNodeSnapshotSource {
+	source := &v1alpha1.StorageNodeSnapshotSource{
+		Spec:         *node.Spec.DeepCopy(),
+		DatabaseName: node.Annotations[v1alpha1.AnnotationsInstanceDBName],
+	}
+	source.Spec.CredentialsSecretRef = nil
+	if node.Status.Registered {
+		source.StorageUnit = &v1alpha1.SnapshotStorageUnit{
+			Name:              getDSName(node),
+			LogicDatabaseName: node.Annotations[AnnotationKeyLogicDatabaseName],
+			ComputeNodeName:   node.Annotations[AnnotationKeyComputeNodeName],
+		}
+	}
+	return source
+}
+
+// copyCredentials copies the master user of the storage node into a Secret owned by the snapshot,
+// as the databases restored from the snapshot have the master user when the snapshot is taken.
+func (r *StorageNodeSnapshotReconciler) copyCredentials(ctx context.Context, snapshot *v1alpha1.StorageNodeSnapshot, node *v1alpha1.StorageNode) (*corev1.LocalObjectReference, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: node.Namespace, Name: node.Status.CredentialsSecretRef.Name}, secret); err != nil {
+		return nil, fmt.Errorf("get credentials secret failed: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-credentials", snapshot.Name)
+	cp := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: snapshot.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(snapshot.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("StorageNodeSnapshot")),
+			},
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+	if err := r.Create(ctx, cp); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("create credentials secret failed: %w", err)
+	}
+	return &corev1.LocalObjectReference{Name: name}, nil
+}
+
+// unsupportedSnapshotError is returned if the provisioner of the storage provider does not take snapshots
+type unsupportedSnapshotError struct {
+	provisioner string
+}
+
+func (e unsupportedSnapshotError) Error() string {
+	return fmt.Sprintf("provisioner %s does not support snapshots", e.provisioner)
+}
+
+func (r *StorageNodeSnapshotReconciler) getSnapshotter(ctx context.Context, storageProviderName string) (*v1alpha1.StorageProvider, provisioner.Snapshotter, error) {
+	sp := &v1alpha1.StorageProvider{}
+	if err := r.Get(ctx, types.NamespacedName{Name: storageProviderName}, sp); err != nil {
+		return nil, nil, fmt.Errorf("get storage provider %s failed: %w", storageProviderName, err)
+	}
+
+	p, ok := r.Provisioners.Get(sp.Spec.Provisioner)
+	if !ok {
+		return nil, nil, fmt.Errorf("provisioner %s is not registered", sp.Spec.Provisioner)
+	}
+	snapshotter, ok := p.(provisioner.Snapshotter)
+	if !ok {
+		return nil, nil, unsupportedSnapshotError{provisioner: sp.Spec.Provisioner}
+	}
+	return sp, snapshotter, nil
+}
+
+// finalize deletes the snapshot in the provider if the deletion policy is Delete
+func (r *StorageNodeSnapshotReconciler) finalize(ctx context.Context, snapshot *v1alpha1.StorageNodeSnapshot) (ctrl.Result, error) {
+	if snapshot.Spec.DeletionPolicy == v1alpha1.SnapshotDeletionPolicyDelete && snapshot.Status.SnapshotIdentifier != "" {
+		sp, snapshotter, err := r.getSnapshotter(ctx, snapshot.Status.StorageProviderName)
+		if err != nil {
+			r.Recorder.Event(snapshot, corev1.EventTypeWarning, "DeleteSnapshotFailed", err.Error())
+			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+		}
+		if err := snapshotter.DeleteSnapshot(ctx, sp, snapshot); err != nil {
+			r.Recorder.Event(snapshot, corev1.EventTypeWarning, "DeleteSnapshotFailed", err.Error())
+			return ctrl.Result{RequeueAfter: defaultRequeueTime}, err
+		}
+		r.Recorder.Eventf(snapshot, corev1.EventTypeNormal, "SnapshotDeleted", "Snapshot %s is deleted", snapshot.Status.SnapshotIdentifier)
+	}
+
+	snapshot.ObjectMeta.Finalizers = slices.Filter([]string{}, snapshot.ObjectMeta.Finalizers, func(f string) bool {
+		return f != FinalizerName
+	})
+	if err := r.Update(ctx, snapshot); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *StorageNodeSnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.StorageNodeSnapshot{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
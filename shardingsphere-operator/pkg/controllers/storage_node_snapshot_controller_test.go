@@ -0,0 +1,351 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+	mock_provisioner "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner/mocks"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultTestStorageNodeSnapshot = "test-snapshot"
+	defaultTestStorageNodeRestore  = "test-restore"
+)
+
+// snapshotProvisioner is a provisioner supporting snapshots
+type snapshotProvisioner struct {
+	*mock_provisioner.MockProvisioner
+	*mock_provisioner.MockSnapshotter
+}
+
+var _ = Describe("StorageNodeSnapshot Controller Mock Test", func() {
+	var (
+		snReconciler *StorageNodeSnapshotReconciler
+		snapshotter  *mock_provisioner.MockSnapshotter
+		namespaced   = types.NamespacedName{Name: defaultTestStorageNodeSnapshot, Namespace: defaultTestNamespace}
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		snapshotter = mock_provisioner.NewMockSnapshotter(mockCtrl)
+		provisioners := provisioner.NewRegistry()
+		provisioners.Register(v1alpha1.ProvisionerAWSRDSInstance, snapshotProvisioner{
+			MockProvisioner: mock_provisioner.NewMockProvisioner(mockCtrl),
+			MockSnapshotter: snapshotter,
+		})
+		snReconciler = &StorageNodeSnapshotReconciler{
+			Client:       fakeClient,
+			Log:          logf.Log,
+			Recorder:     record.NewFakeRecorder(100),
+			Provisioners: provisioners,
+		}
+
+		Expect(fakeClient.Create(ctx, &v1alpha1.StorageProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestStorageProvider},
+			Spec:       v1alpha1.StorageProviderSpec{Provisioner: v1alpha1.ProvisionerAWSRDSInstance},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-storage-node-credentials", Namespace: defaultTestNamespace},
+			Data: map[string][]byte{
+				v1alpha1.StorageNodeCredentialsUsernameKey: []byte("root"),
+				v1alpha1.StorageNodeCredentialsPasswordKey: []byte("password"),
+			},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &v1alpha1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      defaultTestStorageNode,
+				Namespace: defaultTestNamespace,
+				Annotations: map[string]string{
+					v1alpha1.AnnotationsInstanceIdentifier: defaultTestInstanceIdentifier,
+					v1alpha1.AnnotationsInstanceDBName:     "test_db",
+					AnnotationKeyLogicDatabaseName:         "sharding_db",
+					AnnotationKeyComputeNodeName:           "test-compute-node",
+				},
+			},
+			Spec: v1alpha1.StorageNodeSpec{StorageProviderName: defaultTestStorageProvider, InstanceClass: "db.t3.small"},
+			Status: v1alpha1.StorageNodeStatus{
+				Phase:                v1alpha1.StorageNodePhaseReady,
+				Registered:           true,
+				CredentialsSecretRef: &corev1.LocalObjectReference{Name: "test-storage-node-credentials"},
+			},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &v1alpha1.StorageNodeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestStorageNodeSnapshot, Namespace: defaultTestNamespace, UID: "1a2b3c4d-0000-0000-0000-000000000000"},
+			Spec: v1alpha1.StorageNodeSnapshotSpec{
+				StorageNodeName: defaultTestStorageNode,
+				DeletionPolicy:  v1alpha1.SnapshotDeletionPolicyDelete,
+			},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should take the snapshot and follow it until it is ready", func() {
+		snapshotter.EXPECT().CreateSnapshot(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		_, err := snReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		snapshot := &v1alpha1.StorageNodeSnapshot{}
+		Expect(fakeClient.Get(ctx, namespaced, snapshot)).To(Succeed())
+		Expect(snapshot.Finalizers).To(ContainElement(FinalizerName))
+		Expect(snapshot.Status.Phase).To(Equal(v1alpha1.StorageNodeSnapshotPhaseCreating))
+		Expect(snapshot.Status.SnapshotIdentifier).To(Equal("test-namespace-test-snapshot-1a2b3c4d"))
+		Expect(snapshot.Status.StorageProviderName).To(Equal(defaultTestStorageProvider))
+		Expect(snapshot.Status.Source.DatabaseName).To(Equal("test_db"))
+		Expect(snapshot.Status.Source.Spec.InstanceClass).To(Equal("db.t3.small"))
+		Expect(snapshot.Status.Source.StorageUnit).To(Equal(&v1alpha1.SnapshotStorageUnit{
+			Name:              "ds_test_storage_node",
+			LogicDatabaseName: "sharding_db",
+			ComputeNodeName:   "test-compute-node",
+		}))
+
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: snapshot.Status.CredentialsSecretRef.Name}, secret)).To(Succeed())
+		Expect(secret.Data[v1alpha1.StorageNodeCredentialsPasswordKey]).To(Equal([]byte("password")))
+		Expect(secret.OwnerReferences[0].Name).To(Equal(defaultTestStorageNodeSnapshot))
+
+		created := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+		snapshotter.EXPECT().GetSnapshot(gomock.Any(), gomock.Any(), gomock.Any()).Return(&provisioner.Snapshot{Status: "creating"}, nil)
+		_, err = snReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+		Expect(fakeClient.Get(ctx, namespaced, snapshot)).To(Succeed())
+		Expect(snapshot.Status.Phase).To(Equal(v1alpha1.StorageNodeSnapshotPhaseCreating))
+
+		snapshotter.EXPECT().GetSnapshot(gomock.Any(), gomock.Any(), gomock.Any()).Return(&provisioner.Snapshot{
+			Status:           "available",
+			Ready:            true,
+			CreationTime:     created,
+			AllocatedStorage: 20,
+		}, nil)
+		result, err := snReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(fakeClient.Get(ctx, namespaced, snapshot)).To(Succeed())
+		Expect(snapshot.Status.Phase).To(Equal(v1alpha1.StorageNodeSnapshotPhaseReady))
+		Expect(snapshot.Status.AllocatedStorage).To(Equal(int32(20)))
+		Expect(snapshot.Status.CreationTime.Time.Equal(created)).To(BeTrue())
+	})
+
+	It("should wait for the storage node being ready", func() {
+		node := &v1alpha1.StorageNode{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: defaultTestStorageNode}, node)).To(Succeed())
+		node.Status.Phase = v1alpha1.StorageNodePhaseNotReady
+		Expect(fakeClient.Status().Update(ctx, node)).To(Succeed())
+
+		_, err := snReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		snapshot := &v1alpha1.StorageNodeSnapshot{}
+		Expect(fakeClient.Get(ctx, namespaced, snapshot)).To(Succeed())
+		Expect(snapshot.Status.Phase).To(Equal(v1alpha1.StorageNodeSnapshotPhasePending))
+		Expect(snapshot.Status.SnapshotIdentifier).To(BeEmpty())
+	})
+
+	It("should fail if the snapshot is failed in the provider", func() {
+		snapshot := &v1alpha1.StorageNodeSnapshot{}
+		Expect(fakeClient.Get(ctx, namespaced, snapshot)).To(Succeed())
+		snapshot.Status.SnapshotIdentifier = defaultTestStorageNodeSnapshot
+		snapshot.Status.StorageProviderName = defaultTestStorageProvider
+		Expect(fakeClient.Status().Update(ctx, snapshot)).To(Succeed())
+
+		snapshotter.EXPECT().GetSnapshot(gomock.Any(), gomock.Any(), gomock.Any()).Return(&provisioner.Snapshot{Status: "failed", Failed: true, Message: "snapshot is failed"}, nil)
+		_, err := snReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		Expect(fakeClient.Get(ctx, namespaced, snapshot)).To(Succeed())
+		Expect(snapshot.Status.Phase).To(Equal(v1alpha1.StorageNodeSnapshotPhaseFailed))
+		Expect(snapshot.Status.Message).To(Equal("snapshot is failed"))
+	})
+
+	It("should delete the snapshot in the provider with the Delete policy", func() {
+		snapshot := &v1alpha1.StorageNodeSnapshot{}
+		Expect(fakeClient.Get(ctx, namespaced, snapshot)).To(Succeed())
+		snapshot.Finalizers = []string{FinalizerName}
+		Expect(fakeClient.Update(ctx, snapshot)).To(Succeed())
+		snapshot.Status.SnapshotIdentifier = defaultTestStorageNodeSnapshot
+		snapshot.Status.StorageProviderName = defaultTestStorageProvider
+		snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhaseReady
+		Expect(fakeClient.Status().Update(ctx, snapshot)).To(Succeed())
+		Expect(fakeClient.Delete(ctx, snapshot)).To(Succeed())
+
+		snapshotter.EXPECT().DeleteSnapshot(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		_, err := snReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		err = fakeClient.Get(ctx, namespaced, snapshot)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should default to the identifiers unique across namespaces", func() {
+		snapshot := func(namespace, name string) *v1alpha1.StorageNodeSnapshot {
+			return &v1alpha1.StorageNodeSnapshot{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		}
+		Expect(snapshotIdentifier(snapshot("team-a", "daily"))).To(Equal("team-a-daily"))
+		Expect(snapshotIdentifier(snapshot("team-b", "daily"))).To(Equal("team-b-daily"))
+		Expect(snapshotIdentifier(snapshot("1st", "db.daily--01"))).To(Equal("snapshot-1st-db-daily-01"))
+
+		custom := snapshot("team-a", "daily")
+		custom.Spec.SnapshotIdentifier = "custom"
+		Expect(snapshotIdentifier(custom)).To(Equal("custom"))
+	})
+})
+
+var _ = Describe("StorageNodeRestore Controller Mock Test", func() {
+	var (
+		srReconciler *StorageNodeRestoreReconciler
+		namespaced   = types.NamespacedName{Name: defaultTestStorageNodeRestore, Namespace: defaultTestNamespace}
+	)
+
+	BeforeEach(func() {
+		srReconciler = &StorageNodeRestoreReconciler{
+			Client:   fakeClient,
+			Log:      logf.Log,
+			Recorder: record.NewFakeRecorder(100),
+		}
+
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-snapshot-credentials", Namespace: defaultTestNamespace},
+			Data: map[string][]byte{
+				v1alpha1.StorageNodeCredentialsUsernameKey: []byte("root"),
+				v1alpha1.StorageNodeCredentialsPasswordKey: []byte("password"),
+			},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &v1alpha1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        defaultTestStorageNode,
+				Namespace:   defaultTestNamespace,
+				Annotations: map[string]string{AnnotationKeyRegisterStorageUnitEnabled: "true"},
+			},
+			Spec:   v1alpha1.StorageNodeSpec{StorageProviderName: defaultTestStorageProvider},
+			Status: v1alpha1.StorageNodeStatus{Phase: v1alpha1.StorageNodePhaseReady, Registered: true},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &v1alpha1.StorageNodeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestStorageNodeSnapshot, Namespace: defaultTestNamespace},
+			Spec:       v1alpha1.StorageNodeSnapshotSpec{StorageNodeName: defaultTestStorageNode},
+			Status: v1alpha1.StorageNodeSnapshotStatus{
+				Phase:               v1alpha1.StorageNodeSnapshotPhaseReady,
+				SnapshotIdentifier:  "test-snapshot-identifier",
+				StorageProviderName: defaultTestStorageProvider,
+				Source: &v1alpha1.StorageNodeSnapshotSource{
+					Spec:         v1alpha1.StorageNodeSpec{StorageProviderName: defaultTestStorageProvider, InstanceClass: "db.t3.small"},
+					DatabaseName: "test_db",
+					StorageUnit: &v1alpha1.SnapshotStorageUnit{
+						Name:              "ds_test_storage_node",
+						LogicDatabaseName: "sharding_db",
+						ComputeNodeName:   "test-compute-node",
+					},
+				},
+				CredentialsSecretRef: &corev1.LocalObjectReference{Name: "test-snapshot-credentials"},
+			},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &v1alpha1.StorageNodeRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: defaultTestStorageNodeRestore, Namespace: defaultTestNamespace},
+			Spec: v1alpha1.StorageNodeRestoreSpec{
+				SnapshotName:        defaultTestStorageNodeSnapshot,
+				RegisterStorageUnit: true,
+			},
+		})).To(Succeed())
+	})
+
+	It("should restore the snapshot into a new storage node and hand over the storage unit", func() {
+		_, err := srReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		restore := &v1alpha1.StorageNodeRestore{}
+		Expect(fakeClient.Get(ctx, namespaced, restore)).To(Succeed())
+		Expect(restore.Status.Phase).To(Equal(v1alpha1.StorageNodeRestorePhaseRestoring))
+		Expect(restore.Status.StorageNodeName).To(Equal(defaultTestStorageNodeRestore))
+
+		node := &v1alpha1.StorageNode{}
+		Expect(fakeClient.Get(ctx, namespaced, node)).To(Succeed())
+		Expect(node.Spec.InstanceClass).To(Equal("db.t3.small"))
+		Expect(node.Spec.CredentialsSecretRef.Name).To(Equal("test-restore-credentials"))
+		Expect(node.Annotations).To(HaveKeyWithValue(v1alpha1.AnnotationsSnapshotIdentifier, "test-snapshot-identifier"))
+		Expect(node.Annotations).To(HaveKeyWithValue(v1alpha1.AnnotationsInstanceIdentifier, defaultTestStorageNodeRestore))
+		Expect(node.Annotations).To(HaveKeyWithValue(v1alpha1.AnnotationsInstanceDBName, "test_db"))
+		Expect(node.Annotations).To(HaveKeyWithValue(AnnotationKeyStorageUnitName, "ds_test_storage_node"))
+		Expect(node.Annotations).To(HaveKeyWithValue(AnnotationKeyRegisterStorageUnitEnabled, "true"))
+
+		secret := &corev1.Secret{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: "test-restore-credentials"}, secret)).To(Succeed())
+		Expect(secret.Data[v1alpha1.StorageNodeCredentialsPasswordKey]).To(Equal([]byte("password")))
+		Expect(secret.OwnerReferences[0].Name).To(Equal(defaultTestStorageNodeRestore))
+
+		node.Status.Phase = v1alpha1.StorageNodePhaseReady
+		node.Status.Registered = true
+		Expect(fakeClient.Status().Update(ctx, node)).To(Succeed())
+
+		result, err := srReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(fakeClient.Get(ctx, namespaced, restore)).To(Succeed())
+		Expect(restore.Status.Phase).To(Equal(v1alpha1.StorageNodeRestorePhaseCompleted))
+		Expect(restore.Status.CompletionTime).ToNot(BeNil())
+
+		source := &v1alpha1.StorageNode{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: defaultTestStorageNode}, source)).To(Succeed())
+		Expect(source.Annotations).To(HaveKeyWithValue(AnnotationKeyRegisterStorageUnitEnabled, "false"))
+		Expect(source.Status.Registered).To(BeFalse())
+	})
+
+	It("should wait for the snapshot being ready", func() {
+		snapshot := &v1alpha1.StorageNodeSnapshot{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: defaultTestNamespace, Name: defaultTestStorageNodeSnapshot}, snapshot)).To(Succeed())
+		snapshot.Status.Phase = v1alpha1.StorageNodeSnapshotPhaseCreating
+		Expect(fakeClient.Status().Update(ctx, snapshot)).To(Succeed())
+
+		_, err := srReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		restore := &v1alpha1.StorageNodeRestore{}
+		Expect(fakeClient.Get(ctx, namespaced, restore)).To(Succeed())
+		Expect(restore.Status.Phase).To(Equal(v1alpha1.StorageNodeRestorePhasePending))
+		err = fakeClient.Get(ctx, namespaced, &v1alpha1.StorageNode{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should fail if the storage node exists", func() {
+		restore := &v1alpha1.StorageNodeRestore{}
+		Expect(fakeClient.Get(ctx, namespaced, restore)).To(Succeed())
+		restore.Spec.StorageNodeName = defaultTestStorageNode
+		Expect(fakeClient.Update(ctx, restore)).To(Succeed())
+
+		_, err := srReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: namespaced})
+		Expect(err).To(BeNil())
+
+		Expect(fakeClient.Get(ctx, namespaced, restore)).To(Succeed())
+		Expect(restore.Status.Phase).To(Equal(v1alpha1.StorageNodeRestorePhaseFailed))
+		Expect(restore.Status.Message).To(ContainSubstring("already exists"))
+	})
+})
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	StorageProviderControllerName = "storage-provider-controller"
+
+	// the health of the provisioners is checked periodically, as it depends on things out of the cluster
+	storageProviderRequeueTime = time.Minute
+)
+
+// StorageProviderReconciler reports the provisioners available to the storage providers
+type StorageProviderReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	Log          logr.Logger
+	Provisioners *provisioner.Registry
+}
+
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storageproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=shardingsphere.apache.org,resources=storageproviders/status,verbs=get;update;patch
+
+// Reconcile handles main function of this controller
+func (r *StorageProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	sp := &v1alpha1.StorageProvider{}
+	if err := r.Get(ctx, req.NamespacedName, sp); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	status := r.computeStatus(ctx, sp)
+	if !reflect.DeepEqual(sp.Status, status) {
+		sp.Status = status
+		if err := r.Status().Update(ctx, sp); err != nil {
+			r.Log.Error(err, fmt.Sprintf("unable to update StorageProvider %s", sp.GetName()))
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: storageProviderRequeueTime}, nil
+}
+
+// computeStatus checks the health of all the registered provisioners,
+// the storage provider is available when its own provisioner is healthy.
+func (r *StorageProviderReconciler) computeStatus(ctx context.Context, sp *v1alpha1.StorageProvider) v1alpha1.StorageProviderStatus {
+	status := v1alpha1.StorageProviderStatus{}
+	for _, name := range r.Provisioners.Names() {
+		p, _ := r.Provisioners.Get(name)
+
+		ps := v1alpha1.ProvisionerStatus{Name: name, Healthy: true}
+		if err := p.Health(ctx); err != nil {
+			ps.Healthy = false
+			ps.Message = err.Error()
+		}
+		status.Provisioners = append(status.Provisioners, ps)
+
+		if name == sp.Spec.Provisioner {
+			status.Available = ps.Healthy
+		}
+	}
+	return status
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *StorageProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.StorageProvider{}).
+		Complete(r)
+}
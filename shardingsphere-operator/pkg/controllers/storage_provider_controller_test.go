@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"errors"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+	mock_provisioner "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner/mocks"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("StorageProvider Controller Mock Test", func() {
+	var (
+		r                *StorageProviderReconciler
+		healthy, failing *mock_provisioner.MockProvisioner
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		healthy = mock_provisioner.NewMockProvisioner(mockCtrl)
+		failing = mock_provisioner.NewMockProvisioner(mockCtrl)
+
+		provisioners := provisioner.NewRegistry()
+		provisioners.Register("healthy", healthy)
+		provisioners.Register("failing", failing)
+
+		r = &StorageProviderReconciler{
+			Client:       fakeClient,
+			Log:          logf.Log,
+			Provisioners: provisioners,
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should report the health of all the provisioners", func() {
+		sp := &v1alpha1.StorageProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-healthy-provider"},
+			Spec:       v1alpha1.StorageProviderSpec{Provisioner: "healthy"},
+		}
+		Expect(fakeClient.Create(ctx, sp)).Should(Succeed())
+
+		healthy.EXPECT().Health(gomock.Any()).Return(nil)
+		failing.EXPECT().Health(gomock.Any()).Return(errors.New("credentials not set"))
+
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: sp.Name}})
+		Expect(err).To(BeNil())
+		Expect(res.RequeueAfter).To(Equal(storageProviderRequeueTime))
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: sp.Name}, sp)).Should(Succeed())
+		Expect(sp.Status).To(Equal(v1alpha1.StorageProviderStatus{
+			Available: true,
+			Provisioners: []v1alpha1.ProvisionerStatus{
+				{Name: "failing", Healthy: false, Message: "credentials not set"},
+				{Name: "healthy", Healthy: true},
+			},
+		}))
+	})
+
+	It("should not be available when the provisioner is not healthy or not registered", func() {
+		failing.EXPECT().Health(gomock.Any()).Return(errors.New("credentials not set")).Times(2)
+		healthy.EXPECT().Health(gomock.Any()).Return(nil).Times(2)
+
+		for _, name := range []string{"failing", "unknown"} {
+			sp := &v1alpha1.StorageProvider{Spec: v1alpha1.StorageProviderSpec{Provisioner: name}}
+			status := r.computeStatus(ctx, sp)
+			Expect(status.Available).To(BeFalse())
+			Expect(status.Provisioners).To(HaveLen(2))
+		}
+	})
+})
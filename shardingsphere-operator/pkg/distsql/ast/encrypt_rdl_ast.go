@@ -43,7 +43,7 @@ func (createEncryptRule *CreateEncryptRule) ToString() string {
 			}
 		}
 	}
-	return fmt.Sprintf("CREATE ENCRYPT RULE%s %s;", ifNotExists, strings.Join(allEncryptRuleDefinitionList, ","))
+	return fmt.Sprintf("CREATE ENCRYPT RULE %s %s;", ifNotExists, strings.Join(allEncryptRuleDefinitionList, ","))
 }
 
 type AlterEncryptRule struct {
@@ -116,7 +116,7 @@ func (encryptRuleDefinition *EncryptRuleDefinition) ToString() string {
 	}
 
 	if encryptRuleDefinition.ResourceDefinition != nil {
-		resourceDefinition = encryptRuleDefinition.ResourceDefinition.ToString()
+		resourceDefinition = fmt.Sprintf("%s,", encryptRuleDefinition.ResourceDefinition.ToString())
 	}
 
 	if encryptRuleDefinition.EncryptColumnDefinition != nil {
@@ -195,8 +195,8 @@ func (encryptColumnDefinition *EncryptColumnDefinition) ToString() string {
 		assistedQueryColumnDefinition = fmt.Sprintf(",%s", encryptColumnDefinition.AssistedQueryColumnDefinition.ToString())
 	}
 
-	if encryptColumnDefinition.LikeQueryAlgorithm != nil {
-		likeQueryColumnDefinition = fmt.Sprintf(",%s", encryptColumnDefinition.LikeQueryAlgorithm.ToString())
+	if encryptColumnDefinition.LikeQueryColumnDefinition != nil {
+		likeQueryColumnDefinition = fmt.Sprintf(",%s", encryptColumnDefinition.LikeQueryColumnDefinition.ToString())
 	}
 
 	if encryptColumnDefinition.AssistedQueryAlgorithm != nil {
@@ -231,7 +231,7 @@ type ColumnDefinition struct {
 func (columnDefinition *ColumnDefinition) ToString() string {
 	var dataType string
 	if columnDefinition.DataType != nil {
-		dataType = fmt.Sprintf(",DATA_TYP=%s", columnDefinition.DataType.ToString())
+		dataType = fmt.Sprintf(",DATA_TYPE=%s", columnDefinition.DataType.ToString())
 	}
 
 	return fmt.Sprintf("NAME=%s%s", columnDefinition.ColumnName.ToString(), dataType)
@@ -252,9 +252,9 @@ func (plainColumnDefinition *PlainColumnDefinition) ToString() string {
 	}
 
 	if plainColumnDefinition.DataType != nil {
-		dataType = plainColumnDefinition.DataType.ToString()
+		dataType = fmt.Sprintf(",PLAIN_DATA_TYPE=%s", plainColumnDefinition.DataType.ToString())
 	}
-	return fmt.Sprintf("PLAIN = %s, PLAIN_DATA_TYPE = %s", plainColumnName, dataType)
+	return fmt.Sprintf("%s%s", plainColumnName, dataType)
 }
 
 type CipherColumnDefinition struct {
@@ -265,7 +265,7 @@ type CipherColumnDefinition struct {
 func (cipherColumnDefinition *CipherColumnDefinition) ToString() string {
 	var dataType string
 	if cipherColumnDefinition.DataType != nil {
-		dataType = fmt.Sprintf(",CIPHER_DATA_TYPE=%s", dataType)
+		dataType = fmt.Sprintf(",CIPHER_DATA_TYPE=%s", cipherColumnDefinition.DataType.ToString())
 	}
 	return fmt.Sprintf("CIPHER=%s%s", cipherColumnDefinition.CipherColumnName.ToString(), dataType)
 }
@@ -309,7 +309,7 @@ type AssistedQueryAlgorithm struct {
 }
 
 func (assistedQueryAlgorithm *AssistedQueryAlgorithm) ToString() string {
-	return assistedQueryAlgorithm.AlgorithmDefinition.ToString()
+	return fmt.Sprintf("ASSISTED_QUERY_ALGORITHM(%s)", assistedQueryAlgorithm.AlgorithmDefinition.ToString())
 }
 
 type AlgorithmDefinition struct {
@@ -342,11 +342,12 @@ type Properties struct {
 	Properties []*Property
 }
 
-func (properties *Properties) ToString() (sql string) {
+func (properties *Properties) ToString() string {
+	var allProperty []string
 	for _, property := range properties.Properties {
-		sql += property.ToString()
+		allProperty = append(allProperty, property.ToString())
 	}
-	return
+	return strings.Join(allProperty, ",")
 }
 
 type LikeQueryAlgorithm struct {
@@ -355,7 +356,7 @@ type LikeQueryAlgorithm struct {
 
 func (likeQueryAlgorithm *LikeQueryAlgorithm) ToString() (sql string) {
 	if likeQueryAlgorithm.AlgorithmDefinition != nil {
-		sql += likeQueryAlgorithm.ToString()
+		sql = fmt.Sprintf("LIKE_QUERY_ALGORITHM(%s)", likeQueryAlgorithm.AlgorithmDefinition.ToString())
 	}
 	return
 }
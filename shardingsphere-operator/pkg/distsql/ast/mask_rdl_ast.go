@@ -38,6 +38,8 @@ func (createMaskRule *CreateMaskRule) ToString() string {
 		distSQL = fmt.Sprintf("%s %s", distSQL, createMaskRule.Table)
 	}
 
+	distSQL = fmt.Sprintf("%s RULE", distSQL)
+
 	if createMaskRule.IfNotExists != nil {
 		distSQL = fmt.Sprintf("%s %s", distSQL, createMaskRule.IfNotExists.ToString())
 	}
@@ -52,7 +54,7 @@ func (createMaskRule *CreateMaskRule) ToString() string {
 
 type MaskRuleDefinition struct {
 	RuleName         *CommonIdentifier
-	ColumnDefinition []*ColumnDefinition
+	ColumnDefinition []*MaskColumnDefinition
 }
 
 func (maskRuleDefinition *MaskRuleDefinition) ToString() string {
@@ -66,7 +68,29 @@ func (maskRuleDefinition *MaskRuleDefinition) ToString() string {
 		}
 	}
 
-	return fmt.Sprintf("%s (( %s ))", maskRuleDefinition.RuleName.ToString(), strings.Join(columnDefinition, ","))
+	return fmt.Sprintf("%s (COLUMNS(%s))", maskRuleDefinition.RuleName.ToString(), strings.Join(columnDefinition, ","))
+}
+
+type MaskColumnDefinition struct {
+	ColumnName          *CommonIdentifier
+	AlgorithmDefinition *AlgorithmDefinition
+}
+
+func (maskColumnDefinition *MaskColumnDefinition) ToString() string {
+	var (
+		columnName          string
+		algorithmDefinition string
+	)
+
+	if maskColumnDefinition.ColumnName != nil {
+		columnName = maskColumnDefinition.ColumnName.ToString()
+	}
+
+	if maskColumnDefinition.AlgorithmDefinition != nil {
+		algorithmDefinition = maskColumnDefinition.AlgorithmDefinition.ToString()
+	}
+
+	return fmt.Sprintf("(NAME=%s,%s)", columnName, algorithmDefinition)
 }
 
 type AlterMaskRule struct {
@@ -76,15 +100,18 @@ type AlterMaskRule struct {
 
 func (alterMaskRule *AlterMaskRule) ToString() string {
 	var (
-		distSQL         = fmt.Sprintf("ALTER MASK %s RULE", alterMaskRule.Table)
+		distSQL         = "ALTER MASK"
 		ruleDefinitions []string
 	)
+	if alterMaskRule.Table != "" {
+		distSQL = fmt.Sprintf("%s %s", distSQL, alterMaskRule.Table)
+	}
 	if alterMaskRule.AllMaskRuleDefinition != nil {
 		for _, rule := range alterMaskRule.AllMaskRuleDefinition {
 			ruleDefinitions = append(ruleDefinitions, rule.ToString())
 		}
 	}
-	return fmt.Sprintf("%s %s", distSQL, strings.Join(ruleDefinitions, ","))
+	return fmt.Sprintf("%s RULE %s", distSQL, strings.Join(ruleDefinitions, ","))
 }
 
 type DropMaskRule struct {
@@ -101,6 +128,7 @@ func (dropMaskRule *DropMaskRule) ToString() string {
 	if dropMaskRule.Table != "" {
 		distSQL = fmt.Sprintf("%s %s", distSQL, dropMaskRule.Table)
 	}
+	distSQL = fmt.Sprintf("%s RULE", distSQL)
 	if dropMaskRule.IfExists != nil {
 		distSQL = fmt.Sprintf("%s %s", distSQL, dropMaskRule.IfExists.ToString())
 	}
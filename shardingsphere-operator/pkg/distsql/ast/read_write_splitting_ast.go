@@ -53,10 +53,8 @@ type ReadWriteSplittingRuleDefinition struct {
 
 func (readWriteSplittingRuleDefinition *ReadWriteSplittingRuleDefinition) ToString() string {
 	var (
-		ruleName                       string
-		dataSourceDefinition           string
-		transactionalReadQueryStrategy string
-		algorithmDefinition            string
+		ruleName    string
+		definitions []string
 	)
 
 	if readWriteSplittingRuleDefinition.RuleName != nil {
@@ -64,18 +62,18 @@ func (readWriteSplittingRuleDefinition *ReadWriteSplittingRuleDefinition) ToStri
 	}
 
 	if readWriteSplittingRuleDefinition.DataSourceDefinition != nil {
-		dataSourceDefinition = readWriteSplittingRuleDefinition.DataSourceDefinition.ToString()
+		definitions = append(definitions, readWriteSplittingRuleDefinition.DataSourceDefinition.ToString())
 	}
 
 	if readWriteSplittingRuleDefinition.TransactionalReadQueryStrategy != nil {
-		transactionalReadQueryStrategy = readWriteSplittingRuleDefinition.TransactionalReadQueryStrategy.ToString()
+		definitions = append(definitions, readWriteSplittingRuleDefinition.TransactionalReadQueryStrategy.ToString())
 	}
 
 	if readWriteSplittingRuleDefinition.AlgorithmDefinition != nil {
-		algorithmDefinition = readWriteSplittingRuleDefinition.AlgorithmDefinition.ToString()
+		definitions = append(definitions, readWriteSplittingRuleDefinition.AlgorithmDefinition.ToString())
 	}
 
-	return fmt.Sprintf("%s (%s %s %s)", ruleName, dataSourceDefinition, transactionalReadQueryStrategy, algorithmDefinition)
+	return fmt.Sprintf("%s (%s)", ruleName, strings.Join(definitions, ", "))
 }
 
 type DataSourceDefinition struct {
@@ -130,7 +128,7 @@ type TransactionalReadQueryStrategy struct {
 }
 
 func (transactionalReadQueryStrategy *TransactionalReadQueryStrategy) ToString() string {
-	return transactionalReadQueryStrategy.TransactionalReadQueryStrategyName.ToString()
+	return fmt.Sprintf("TRANSACTIONAL_READ_QUERY_STRATEGY = %s", transactionalReadQueryStrategy.TransactionalReadQueryStrategyName.ToString())
 }
 
 type AlterReadwriteSplittingRule struct {
@@ -33,7 +33,7 @@ func (createShadowRule *CreateShadowRule) ToString() string {
 		allRule = []string{}
 	)
 	if createShadowRule.IfNotExists != nil {
-		distSQL = fmt.Sprintf("%s %s", distSQL, createShadowRule.IfNotExists)
+		distSQL = fmt.Sprintf("%s %s", distSQL, createShadowRule.IfNotExists.ToString())
 	}
 	if createShadowRule.AllShadowRuleDefinition != nil {
 		for _, r := range createShadowRule.AllShadowRuleDefinition {
@@ -60,7 +60,7 @@ func (shadowRuleDefinition *ShadowRuleDefinition) ToString() string {
 	}
 
 	if shadowRuleDefinition.Source != nil {
-		distSQL = fmt.Sprintf("%s SOURCE = %s, ", distSQL, shadowRuleDefinition.ToString())
+		distSQL = fmt.Sprintf("%s SOURCE = %s, ", distSQL, shadowRuleDefinition.Source.ToString())
 	}
 
 	if shadowRuleDefinition.Shadow != nil {
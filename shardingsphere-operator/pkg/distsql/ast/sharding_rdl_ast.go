@@ -112,33 +112,29 @@ type ShardingAutoTableRule struct {
 // nolint
 func (shardingAutoTableRule *ShardingAutoTableRule) ToString() string {
 	var (
-		tableName                    string
-		storageUnits                 string
-		autoShardingColumnDefinition string
-		algorithmDefinition          string
-		keyGenerateDefinition        string
-		auditDefinition              string
+		tableName   string
+		definitions []string
 	)
 
 	if shardingAutoTableRule.TableName != nil {
 		tableName = shardingAutoTableRule.TableName.ToString()
 	}
 	if shardingAutoTableRule.StorageUnits != nil {
-		storageUnits = shardingAutoTableRule.StorageUnits.ToString()
+		definitions = append(definitions, shardingAutoTableRule.StorageUnits.ToString())
 	}
 	if shardingAutoTableRule.AutoShardingColumnDefinition != nil {
-		autoShardingColumnDefinition = shardingAutoTableRule.AutoShardingColumnDefinition.ToString()
+		definitions = append(definitions, shardingAutoTableRule.AutoShardingColumnDefinition.ToString())
 	}
 	if shardingAutoTableRule.AlgorithmDefinition != nil {
-		algorithmDefinition = shardingAutoTableRule.AlgorithmDefinition.ToString()
+		definitions = append(definitions, shardingAutoTableRule.AlgorithmDefinition.ToString())
 	}
 	if shardingAutoTableRule.KeyGenerateDefinition != nil {
-		keyGenerateDefinition = shardingAutoTableRule.KeyGenerateDefinition.ToString()
+		definitions = append(definitions, shardingAutoTableRule.KeyGenerateDefinition.ToString())
 	}
 	if shardingAutoTableRule.AuditDefinition != nil {
-		auditDefinition = shardingAutoTableRule.AuditDefinition.ToString()
+		definitions = append(definitions, shardingAutoTableRule.AuditDefinition.ToString())
 	}
-	return fmt.Sprintf("%s (%s,%s,%s,%s,%s)", tableName, storageUnits, autoShardingColumnDefinition, algorithmDefinition, keyGenerateDefinition, auditDefinition)
+	return fmt.Sprintf("%s (%s)", tableName, strings.Join(definitions, ","))
 }
 
 type StorageUnits struct {
@@ -185,7 +181,7 @@ type ShardingColumn struct {
 }
 
 func (shardingColumn *ShardingColumn) ToString() string {
-	return fmt.Sprintf("SHARDING_COLUMN EQ_ %s", shardingColumn.ColumnName.ToString())
+	return fmt.Sprintf("SHARDING_COLUMN = %s", shardingColumn.ColumnName.ToString())
 }
 
 type KeyGenerateDefinition struct {
@@ -258,32 +254,28 @@ type ShardingTableRule struct {
 // nolint
 func (shardingTableRule *ShardingTableRule) ToString() string {
 	var (
-		tableName             string
-		datanodes             string
-		databaseStrategy      string
-		tableStrategy         string
-		keyGenerateDefinition string
-		auditDefinition       string
+		tableName   string
+		definitions []string
 	)
 	if shardingTableRule.TableName != nil {
 		tableName = shardingTableRule.TableName.ToString()
 	}
 	if shardingTableRule.DataNodes != nil {
-		datanodes = shardingTableRule.DataNodes.ToString()
+		definitions = append(definitions, shardingTableRule.DataNodes.ToString())
 	}
 	if shardingTableRule.DatabaseStrategy != nil {
-		databaseStrategy = shardingTableRule.DatabaseStrategy.ToString()
+		definitions = append(definitions, shardingTableRule.DatabaseStrategy.ToString())
 	}
 	if shardingTableRule.TableStrategy != nil {
-		tableStrategy = shardingTableRule.TableStrategy.ToString()
+		definitions = append(definitions, shardingTableRule.TableStrategy.ToString())
 	}
 	if shardingTableRule.KeyGenerateDefinition != nil {
-		keyGenerateDefinition = shardingTableRule.KeyGenerateDefinition.ToString()
+		definitions = append(definitions, shardingTableRule.KeyGenerateDefinition.ToString())
 	}
 	if shardingTableRule.AuditDefinition != nil {
-		auditDefinition = shardingTableRule.AuditDefinition.ToString()
+		definitions = append(definitions, shardingTableRule.AuditDefinition.ToString())
 	}
-	return fmt.Sprintf("%s(%s,%s,%s,%s,%s)", tableName, datanodes, databaseStrategy, tableStrategy, keyGenerateDefinition, auditDefinition)
+	return fmt.Sprintf("%s(%s)", tableName, strings.Join(definitions, ","))
 }
 
 type DataNode struct {
@@ -318,21 +310,21 @@ type ShardingStrategy struct {
 
 func (shardingStrategy *ShardingStrategy) ToString() string {
 	var (
-		strategyType             string
-		shardingColumnDefinition string
-		shardingAlgorithm        string
+		strategyType string
+		definitions  []string
 	)
 	if shardingStrategy.StrategyType != nil {
 		strategyType = shardingStrategy.StrategyType.ToString()
 	}
+	definitions = append(definitions, fmt.Sprintf("TYPE = %s", strategyType))
 	if shardingStrategy.ShardingColumnDefinition != nil {
-		shardingColumnDefinition = shardingStrategy.ShardingColumnDefinition.ToString()
+		definitions = append(definitions, shardingStrategy.ShardingColumnDefinition.ToString())
 	}
 	if shardingStrategy.ShardingAlgorithm != nil {
-		shardingAlgorithm = shardingStrategy.ShardingAlgorithm.ToString()
+		definitions = append(definitions, shardingStrategy.ShardingAlgorithm.ToString())
 	}
 
-	return fmt.Sprintf("TYPE = %s,%s,%s", strategyType, shardingColumnDefinition, shardingAlgorithm)
+	return strings.Join(definitions, ",")
 }
 
 type StrategyType struct {
@@ -371,19 +363,16 @@ type ShardingColumns struct {
 }
 
 func (shardingColumns *ShardingColumns) ToString() string {
-	var (
-		columnName    string
-		allColumnName []string
-	)
-	if shardingColumns.ColumnName != nil {
-		columnName = shardingColumns.ColumnName.ToString()
-	}
+	var allColumnName []string
+	// AllColumnName holds every column of the definition, and ColumnName is the first one of them.
 	if shardingColumns.AllColumnName != nil {
 		for _, n := range shardingColumns.AllColumnName {
 			allColumnName = append(allColumnName, n.ToString())
 		}
+	} else if shardingColumns.ColumnName != nil {
+		allColumnName = append(allColumnName, shardingColumns.ColumnName.ToString())
 	}
-	return fmt.Sprintf("SHARDING_COLUMNS = %s,%s", columnName, strings.Join(allColumnName, ","))
+	return fmt.Sprintf("SHARDING_COLUMNS = %s", strings.Join(allColumnName, ","))
 }
 
 type ShardingAlgorithm struct {
@@ -567,7 +556,10 @@ func (shardingAlgorithmDefinition *ShardingAlgorithmDefinition) ToString() strin
 	if shardingAlgorithmDefinition.PropertiesDefinition != nil {
 		propertiesDefinition = shardingAlgorithmDefinition.PropertiesDefinition.ToString()
 	}
-	return fmt.Sprintf("TYPE ( NAME = %s ,%s)", shardingAlgorithmTypeName, propertiesDefinition)
+	if propertiesDefinition != "" {
+		return fmt.Sprintf("TYPE ( NAME = %s ,%s)", shardingAlgorithmTypeName, propertiesDefinition)
+	}
+	return fmt.Sprintf("TYPE ( NAME = %s )", shardingAlgorithmTypeName)
 }
 
 type ShardingAlgorithmTypeName struct {
@@ -78,14 +78,20 @@ func (v *MaskVisitor) VisitMaskRuleDefinition(ctx *parser.MaskRuleDefinitionCont
 	if ctx.RuleName() != nil {
 		stmt.RuleName = v.VisitRuleName(ctx.RuleName().(*parser.RuleNameContext))
 	}
+	for _, c := range ctx.AllColumnDefinition() {
+		stmt.ColumnDefinition = append(stmt.ColumnDefinition, v.VisitColumnDefinition(c.(*parser.ColumnDefinitionContext)))
+	}
 	return stmt
 }
 
-func (v *MaskVisitor) VisitColumnDefinition(ctx *parser.ColumnDefinitionContext) *ast.ColumnDefinition {
-	stmt := &ast.ColumnDefinition{}
+func (v *MaskVisitor) VisitColumnDefinition(ctx *parser.ColumnDefinitionContext) *ast.MaskColumnDefinition {
+	stmt := &ast.MaskColumnDefinition{}
 	if ctx.ColumnName() != nil {
 		stmt.ColumnName = v.VisitColumnName(ctx.ColumnName().(*parser.ColumnNameContext))
 	}
+	if ctx.AlgorithmDefinition() != nil {
+		stmt.AlgorithmDefinition = v.VisitAlgorithmDefinition(ctx.AlgorithmDefinition().(*parser.AlgorithmDefinitionContext))
+	}
 	return stmt
 }
 
@@ -128,6 +128,18 @@ func NewCluster(sn *v1alpha1.StorageNode, sp *v1alpha1.StorageProvider) *cnpgv1.
 		builder.SetBackupTarget(sp.Spec.Parameters["backup.target"])
 	}
 
+	if len(sp.Spec.Parameters["backup.barmanObjectStore.destinationPath"]) > 0 {
+		builder.SetBackupBarmanObjectStore(
+			sp.Spec.Parameters["backup.barmanObjectStore.destinationPath"],
+			sp.Spec.Parameters["backup.barmanObjectStore.endpointURL"],
+			sp.Spec.Parameters["backup.barmanObjectStore.s3Credentials.secretName"])
+	}
+
+	// the cluster is restored from the backup taken by a StorageNodeSnapshot
+	if len(sn.Annotations[v1alpha1.AnnotationsSnapshotIdentifier]) > 0 {
+		builder.SetRecoveryBackup(sn.Annotations[v1alpha1.AnnotationsSnapshotIdentifier])
+	}
+
 	if len(sp.Spec.Parameters["instances"]) > 0 {
 		ins, _ := strconv.Atoi(sp.Spec.Parameters["instances"])
 		builder.SetInstances(ins)
@@ -176,6 +188,8 @@ type ClusterBuilder interface {
 	SetPrimaryUpdateMethod(m string) ClusterBuilder
 	SetBackupRetentionPolicy(r string) ClusterBuilder
 	SetBackupTarget(t string) ClusterBuilder
+	SetBackupBarmanObjectStore(destinationPath, endpointURL, s3CredentialsSecret string) ClusterBuilder
+	SetRecoveryBackup(name string) ClusterBuilder
 	SetLogLevel(l string) ClusterBuilder
 	Build() *cnpgv1.Cluster
 }
@@ -258,7 +272,7 @@ func (b *clusterBuilder) SetReplicaClusterSource(s string) ClusterBuilder {
 
 // SetSuperuserSecret sets the secret containing the superuser password
 func (b *clusterBuilder) SetSuperuserSecret(s string) ClusterBuilder {
-	b.cluster.Spec.SuperuserSecret.Name = s
+	b.cluster.Spec.SuperuserSecret = &cnpgv1.LocalObjectReference{Name: s}
 	return b
 }
 
@@ -324,15 +338,57 @@ func (b *clusterBuilder) SetPrimaryUpdateMethod(m string) ClusterBuilder {
 	return b
 }
 
+func (b *clusterBuilder) backup() *cnpgv1.BackupConfiguration {
+	if b.cluster.Spec.Backup == nil {
+		b.cluster.Spec.Backup = &cnpgv1.BackupConfiguration{}
+	}
+	return b.cluster.Spec.Backup
+}
+
 // SetBackupRetentionPolicy sets the backup retention policy of the cluster
 func (b *clusterBuilder) SetBackupRetentionPolicy(r string) ClusterBuilder {
-	b.cluster.Spec.Backup.RetentionPolicy = r
+	b.backup().RetentionPolicy = r
 	return b
 }
 
 // SetBackupTarget sets the backup target of the cluster
 func (b *clusterBuilder) SetBackupTarget(t string) ClusterBuilder {
-	b.cluster.Spec.Backup.Target = cnpgv1.BackupTarget(t)
+	b.backup().Target = cnpgv1.BackupTarget(t)
+	return b
+}
+
+// SetBackupBarmanObjectStore sets the object store of the backups of the cluster, which is required by the Backups.
+// The s3 credentials are read from the keys ACCESS_KEY_ID and ACCESS_SECRET_KEY of the Secret.
+func (b *clusterBuilder) SetBackupBarmanObjectStore(destinationPath, endpointURL, s3CredentialsSecret string) ClusterBuilder {
+	store := &cnpgv1.BarmanObjectStoreConfiguration{
+		DestinationPath: destinationPath,
+		EndpointURL:     endpointURL,
+	}
+	if s3CredentialsSecret != "" {
+		store.BarmanCredentials.AWS = &cnpgv1.S3Credentials{
+			AccessKeyIDReference: &cnpgv1.SecretKeySelector{
+				LocalObjectReference: cnpgv1.LocalObjectReference{Name: s3CredentialsSecret},
+				Key:                  "ACCESS_KEY_ID",
+			},
+			SecretAccessKeyReference: &cnpgv1.SecretKeySelector{
+				LocalObjectReference: cnpgv1.LocalObjectReference{Name: s3CredentialsSecret},
+				Key:                  "ACCESS_SECRET_KEY",
+			},
+		}
+	}
+	b.backup().BarmanObjectStore = store
+	return b
+}
+
+// SetRecoveryBackup bootstraps the cluster by recovering the Backup with the name
+func (b *clusterBuilder) SetRecoveryBackup(name string) ClusterBuilder {
+	b.cluster.Spec.Bootstrap = &cnpgv1.BootstrapConfiguration{
+		Recovery: &cnpgv1.BootstrapRecovery{
+			Backup: &cnpgv1.BackupSource{
+				LocalObjectReference: cnpgv1.LocalObjectReference{Name: name},
+			},
+		},
+	}
 	return b
 }
 
@@ -62,6 +62,7 @@ type Builder interface {
 // Getter get CloudNativePG Cluster from different parameters
 type Getter interface {
 	GetClusterByNamespacedName(context.Context, types.NamespacedName) (*cnpgv1.Cluster, error)
+	GetBackupByNamespacedName(context.Context, types.NamespacedName) (*cnpgv1.Backup, error)
 }
 
 // Setter set CloudNativePG Cluster from different parameters
@@ -69,6 +70,8 @@ type Setter interface {
 	Create(context.Context, *cnpgv1.Cluster) error
 	Update(context.Context, *cnpgv1.Cluster) error
 	Delete(context.Context, *cnpgv1.Cluster) error
+	CreateBackup(context.Context, *cnpgv1.Backup) error
+	DeleteBackup(context.Context, *cnpgv1.Backup) error
 }
 
 type getter struct {
@@ -87,6 +90,18 @@ func (cg getter) GetClusterByNamespacedName(ctx context.Context, namespacedName
 	return c, nil
 }
 
+// GetBackupByNamespacedName returns a ClusterNativePG Backup
+func (cg getter) GetBackupByNamespacedName(ctx context.Context, namespacedName types.NamespacedName) (*cnpgv1.Backup, error) {
+	b := &cnpgv1.Backup{}
+	if err := cg.Get(ctx, namespacedName, b); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
 type builder struct{}
 
 // Build builds a new CloudNative PG Cluster
@@ -112,3 +127,13 @@ func (cs setter) Update(ctx context.Context, cluster *cnpgv1.Cluster) error {
 func (cs setter) Delete(ctx context.Context, cluster *cnpgv1.Cluster) error {
 	return cs.Client.Delete(ctx, cluster)
 }
+
+// CreateBackup creates a new CloudNative PG Backup
+func (cs setter) CreateBackup(ctx context.Context, backup *cnpgv1.Backup) error {
+	return cs.Client.Create(ctx, backup)
+}
+
+// DeleteBackup deletes a existing CloudNative PG Backup
+func (cs setter) DeleteBackup(ctx context.Context, backup *cnpgv1.Backup) error {
+	return cs.Client.Delete(ctx, backup)
+}
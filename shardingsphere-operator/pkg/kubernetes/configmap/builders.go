@@ -193,15 +193,7 @@ func (c *computeNodeConfigMapBuilder) Build() *corev1.ConfigMap {
 		data[ConfigDataKeyForLogback] = DefaultLogback
 	}
 
-	// NOTE: ShardingSphere Proxy 5.3.0 needs a server.yaml no matter if it is empty
-	if !reflect.DeepEqual(cn.Spec.Bootstrap.ServerConfig, v1alpha1.ServerConfig{}) {
-		servconf := cn.Spec.Bootstrap.ServerConfig.DeepCopy()
-		if y, err := yaml.Marshal(servconf); err == nil {
-			data[ConfigDataKeyForServer] = string(y)
-		}
-	} else {
-		data[ConfigDataKeyForServer] = DefaultServerConfig
-	}
+	// NOTE: server.yaml carries the passwords, it is rendered into a Secret by the ComputeNode reconciler
 
 	// load java agent config to configmap if needed
 	if !reflect.DeepEqual(cn.Spec.Bootstrap.AgentConfig, v1alpha1.AgentConfig{}) {
@@ -35,6 +35,7 @@ type DeploymentBuilder interface {
 	SetSelectors(selectors *metav1.LabelSelector) DeploymentBuilder
 	SetReplicas(r *int32) DeploymentBuilder
 	SetRollingUpdateStrategy(maxUnavailable, maxSurge int) DeploymentBuilder
+	SetMaxUnavailable(maxUnavailable *intstr.IntOrString) DeploymentBuilder
 
 	SetPodTemplateMetadata(obj *metav1.ObjectMeta) DeploymentBuilder
 	SetPodTemplateSpec(tpl *corev1.PodTemplateSpec) DeploymentBuilder
@@ -84,6 +85,20 @@ func (d *deploymentBuilder) SetRollingUpdateStrategy(maxUnavailable, maxSurge in
 	return d
 }
 
+// SetMaxUnavailable sets the maxUnavailable of the rolling update, which is kept if it is nil
+func (d *deploymentBuilder) SetMaxUnavailable(maxUnavailable *intstr.IntOrString) DeploymentBuilder {
+	if maxUnavailable == nil {
+		return d
+	}
+	if d.deployment.Spec.Strategy.RollingUpdate == nil {
+		d.deployment.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+	}
+
+	mu := *maxUnavailable
+	d.deployment.Spec.Strategy.RollingUpdate.MaxUnavailable = &mu
+	return d
+}
+
 // SetPodTemplateMetadata sets Deployment PodTemplateMetadata for ShardingSphereProxy Pod
 func (d *deploymentBuilder) SetPodTemplateMetadata(obj *metav1.ObjectMeta) DeploymentBuilder {
 	d.deployment.Spec.Template.ObjectMeta = *obj
@@ -122,6 +137,7 @@ type SharedVolumeAndMountBuilder interface {
 	SetVolumeMountSize(size int) SharedVolumeAndMountBuilder
 	SetVolumeSourceEmptyDir() SharedVolumeAndMountBuilder
 	SetVolumeSourceConfigMap(name string, kps ...corev1.KeyToPath) SharedVolumeAndMountBuilder
+	SetVolumeSourceProjected(sources ...corev1.VolumeProjection) SharedVolumeAndMountBuilder
 	Build() (*corev1.Volume, []*corev1.VolumeMount)
 }
 
@@ -204,6 +220,15 @@ func (b *sharedVolumeAndMountBuilder) SetVolumeSourceConfigMap(name string, kps
 	return b
 }
 
+// SetVolumeSourceProjected sets a Volume projecting the sources, e.g. a ConfigMap and a Secret, into one directory
+func (b *sharedVolumeAndMountBuilder) SetVolumeSourceProjected(sources ...corev1.VolumeProjection) SharedVolumeAndMountBuilder {
+	if b.volume.Projected == nil {
+		b.volume.Projected = &corev1.ProjectedVolumeSource{}
+	}
+	b.volume.Projected.Sources = sources
+	return b
+}
+
 // Build creates a new volume and volumeMounts
 func (b *sharedVolumeAndMountBuilder) Build() (*corev1.Volume, []*corev1.VolumeMount) {
 	return b.volume, b.volumeMounts
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	passwordLength = 24
+	passwordChars  = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// GeneratePassword returns a random password of letters and digits
+func GeneratePassword() (string, error) {
+	max := big.NewInt(int64(len(passwordChars)))
+	b := make([]byte, passwordLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = passwordChars[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// UserKey returns the key of a user in the Secret of the generated passwords, the authorized host is stripped
+func UserKey(user string) string {
+	return strings.Split(user, "@")[0]
+}
+
+// GeneratePasswords returns the data with a password for each of the users, the passwords in cur are kept
+func GeneratePasswords(users []string, cur map[string][]byte) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for _, user := range users {
+		key := UserKey(user)
+		if p, ok := cur[key]; ok && len(p) > 0 {
+			data[key] = p
+			continue
+		}
+		p, err := GeneratePassword()
+		if err != nil {
+			return nil, fmt.Errorf("generate password for user %s: %w", key, err)
+		}
+		data[key] = []byte(p)
+	}
+	return data, nil
+}
+
+// ResolvePassword returns the password of a user, it is the plain text password, the one referenced by ref,
+// or the generated one kept in the Secret named generated in turn.
+// The errors never carry the secret material.
+func ResolvePassword(user, password string, ref *corev1.SecretKeySelector, generated string, secrets []*corev1.Secret) (string, error) {
+	if password != "" {
+		return password, nil
+	}
+
+	name, key := generated, UserKey(user)
+	if ref != nil {
+		name, key = ref.Name, ref.Key
+	}
+
+	for _, s := range secrets {
+		if s == nil || s.Name != name {
+			continue
+		}
+		if v, ok := s.Data[key]; ok {
+			return string(v), nil
+		}
+		if v, ok := s.StringData[key]; ok {
+			return v, nil
+		}
+		break
+	}
+
+	if ref != nil && ref.Optional != nil && *ref.Optional {
+		return "", nil
+	}
+	return "", fmt.Errorf("password of user %s not found in key %s of secret %s", UserKey(user), key, name)
+}
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_GeneratePasswords(t *testing.T) {
+	cur := map[string][]byte{"root": []byte("kept")}
+	data, err := GeneratePasswords([]string{"root@%", "sharding"}, cur)
+	assert.NoError(t, err)
+	assert.Equal(t, "kept", string(data["root"]))
+	assert.Len(t, data["sharding"], passwordLength)
+
+	again, err := GeneratePasswords([]string{"sharding"}, data)
+	assert.NoError(t, err)
+	assert.Equal(t, data["sharding"], again["sharding"])
+	assert.NotContains(t, again, "root")
+}
+
+func Test_ResolvePassword(t *testing.T) {
+	secrets := []*corev1.Secret{
+		{Data: map[string][]byte{"root": []byte("generated")}},
+		{Data: map[string][]byte{"password": []byte("referenced")}},
+	}
+	secrets[0].Name = "foo-authority"
+	secrets[1].Name = "foo-users"
+	ref := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "foo-users"}, Key: "password"}
+	optional := true
+
+	cases := []struct {
+		name     string
+		password string
+		ref      *corev1.SecretKeySelector
+		exp      string
+		err      bool
+	}{
+		{name: "plain text", password: "plain", ref: ref, exp: "plain"},
+		{name: "referenced", ref: ref, exp: "referenced"},
+		{name: "generated", exp: "generated"},
+		{name: "missing key", ref: &corev1.SecretKeySelector{LocalObjectReference: ref.LocalObjectReference, Key: "none"}, err: true},
+		{name: "missing secret", ref: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "none"}, Key: "password"}, err: true},
+		{name: "optional", ref: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "none"}, Key: "password", Optional: &optional}},
+	}
+
+	for _, c := range cases {
+		act, err := ResolvePassword("root@%", c.password, c.ref, "foo-authority", secrets)
+		if c.err {
+			assert.Error(t, err, c.name)
+			continue
+		}
+		assert.NoError(t, err, c.name)
+		assert.Equal(t, c.exp, act, c.name)
+	}
+}
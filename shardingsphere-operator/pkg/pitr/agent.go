@@ -0,0 +1,289 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pitr is the client of the openGauss PITR agent, which backs up and restores the storage nodes
+// of a ShardingSphere cluster with gs_probackup.
+package pitr
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Backup modes of gs_probackup
+const (
+	BackupModeFull   = "FULL"
+	BackupModePTrack = "PTRACK"
+)
+
+// Backup statuses reported by the agent
+const (
+	BackupStatusRunning   = "Running"
+	BackupStatusCompleted = "Completed"
+	BackupStatusFailed    = "Failed"
+	BackupStatusOther     = "Other"
+)
+
+// DefaultInstance is the backup instance name of gs_probackup used by the PITR cli
+const DefaultInstance = "ins-default-ss"
+
+// Database is the openGauss database backed up by the agent on the same host
+type Database struct {
+	Port     uint16 `json:"db_port"`
+	Name     string `json:"db_name"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// BackupIn is the request of a backup
+type BackupIn struct {
+	Database
+	DnBackupPath string `json:"dn_backup_path"`
+	DnThreadsNum uint8  `json:"dn_threads_num"`
+	DnBackupMode string `json:"dn_backup_mode"`
+	Instance     string `json:"instance"`
+	// RequestKey makes the backup idempotent, the agent returns the backup started by the previous request of the key
+	RequestKey string `json:"request_key,omitempty"`
+}
+
+// ShowDetailIn is the request of the detail of a backup
+type ShowDetailIn struct {
+	Database
+	DnBackupID   string `json:"dn_backup_id"`
+	DnBackupPath string `json:"dn_backup_path"`
+	Instance     string `json:"instance"`
+}
+
+// RestoreIn is the request of a restore
+type RestoreIn struct {
+	Database
+	Instance     string `json:"instance"`
+	DnBackupPath string `json:"dn_backup_path"`
+	DnBackupID   string `json:"dn_backup_id"`
+	DnThreadsNum uint8  `json:"dn_threads_num"`
+	// RequestKey makes the restore idempotent, the agent returns the job submitted by the previous request of the key
+	RequestKey string `json:"request_key,omitempty"`
+}
+
+// restoreIn runs the restore as a job of the agent
+type restoreIn struct {
+	*RestoreIn
+	Async bool `json:"async"`
+}
+
+// Job states reported by the agent
+const (
+	JobStateRunning     = "running"
+	JobStateSucceeded   = "succeeded"
+	JobStateFailed      = "failed"
+	JobStateCanceled    = "canceled"
+	JobStateInterrupted = "interrupted"
+)
+
+// Job is a job running in the background of the agent
+type Job struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	State    string `json:"state"`
+	Progress int    `json:"progress"`
+	Error    string `json:"error,omitempty"`
+}
+
+// BackupInfo is the detail of a backup
+type BackupInfo struct {
+	ID        string `json:"dn_backup_id"`
+	Path      string `json:"dn_backup_path"`
+	Mode      string `json:"db_backup_mode"`
+	Instance  string `json:"instance"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Status    string `json:"status"`
+}
+
+// Agent is the PITR agent on a storage node
+type Agent interface {
+	// CheckStatus returns an error if the agent is not available or can not access the database
+	CheckStatus(ctx context.Context, db *Database) error
+	// Backup starts a backup and returns its id without waiting for it
+	Backup(ctx context.Context, in *BackupIn) (string, error)
+	// ShowDetail returns the detail of a backup
+	ShowDetail(ctx context.Context, in *ShowDetailIn) (*BackupInfo, error)
+	// SubmitRestore submits a job restoring the database with a backup and returns the id of the job
+	SubmitRestore(ctx context.Context, in *RestoreIn) (string, error)
+	// GetJob returns the job of the id
+	GetJob(ctx context.Context, id string) (*Job, error)
+}
+
+// DefaultTimeout is the timeout of a request to the agent
+const DefaultTimeout = 30 * time.Second
+
+// Options are the credentials of the client and the options of the requests
+type Options struct {
+	// Token is the bearer token signed with the token key of the agent
+	Token string
+	// Certificates are the client certificates, the OU of which is the role of the client
+	Certificates []tls.Certificate
+	// RootCAs verify the certificate of the agent, which is not verified if it is nil
+	RootCAs *x509.CertPool
+	// Timeout is the timeout of a request, it is DefaultTimeout if it is zero
+	Timeout time.Duration
+}
+
+type agent struct {
+	addr   string
+	token  string
+	client *http.Client
+}
+
+var _ Agent = (*agent)(nil)
+
+// NewAgent returns the client of the agent listening on addr, e.g. `10.0.0.1:443`.
+// The agent serves HTTPS with a self-signed certificate by default, so the certificate is not verified without the root CAs.
+func NewAgent(addr string, opts *Options) Agent {
+	if !strings.HasPrefix(addr, "http") {
+		addr = fmt.Sprintf("https://%s", addr)
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	return &agent{
+		addr:  addr,
+		token: opts.Token,
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				//nolint:gosec
+				TLSClientConfig: &tls.Config{
+					Certificates:       opts.Certificates,
+					RootCAs:            opts.RootCAs,
+					InsecureSkipVerify: opts.RootCAs == nil,
+				},
+			},
+		},
+	}
+}
+
+// response is the uniform response of the agent
+type response struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (a *agent) do(ctx context.Context, method, api string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("marshal request error: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.addr+api, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request error: %w", err)
+	}
+	// the agent rejects the requests without a request id
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-request-id", uuid.New().String())
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request agent %s error: %w", a.addr, err)
+	}
+	defer resp.Body.Close()
+
+	all, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response error: %w", err)
+	}
+	r := &response{}
+	if err := json.Unmarshal(all, r); err != nil {
+		return fmt.Errorf("unmarshal response error: %w, status code: %d", err, resp.StatusCode)
+	}
+	if r.Code != 0 {
+		return fmt.Errorf("agent %s error: code=%d, msg=%s", a.addr, r.Code, r.Msg)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("agent %s error: status code=%d", a.addr, resp.StatusCode)
+	}
+	if out != nil && len(r.Data) > 0 {
+		if err := json.Unmarshal(r.Data, out); err != nil {
+			return fmt.Errorf("unmarshal response data error: %w", err)
+		}
+	}
+	return nil
+}
+
+func (a *agent) CheckStatus(ctx context.Context, db *Database) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return a.do(ctx, http.MethodPost, "/api/healthz", db, nil)
+}
+
+func (a *agent) Backup(ctx context.Context, in *BackupIn) (string, error) {
+	out := struct {
+		ID string `json:"backup_id"`
+	}{}
+	if err := a.do(ctx, http.MethodPost, "/api/backup", in, &out); err != nil {
+		return "", err
+	}
+	return out.ID, nil
+}
+
+func (a *agent) ShowDetail(ctx context.Context, in *ShowDetailIn) (*BackupInfo, error) {
+	out := &BackupInfo{}
+	if err := a.do(ctx, http.MethodPost, "/api/show", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (a *agent) SubmitRestore(ctx context.Context, in *RestoreIn) (string, error) {
+	out := struct {
+		JobID string `json:"job_id"`
+	}{}
+	if err := a.do(ctx, http.MethodPost, "/api/restore", &restoreIn{RestoreIn: in, Async: true}, &out); err != nil {
+		return "", err
+	}
+	if out.JobID == "" {
+		return "", fmt.Errorf("agent %s returns no restore job", a.addr)
+	}
+	return out.JobID, nil
+}
+
+func (a *agent) GetJob(ctx context.Context, id string) (*Job, error) {
+	out := &Job{}
+	if err := a.do(ctx, http.MethodGet, "/api/jobs/"+url.PathEscape(id), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
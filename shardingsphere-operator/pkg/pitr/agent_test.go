@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pitr
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAgent(t *testing.T, handler func(api string, body map[string]any) (int, string, any)) Agent {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		code, msg, data := handler(r.URL.Path, body)
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"code": code, "msg": msg, "data": data}))
+	}))
+	t.Cleanup(server.Close)
+	return NewAgent(server.URL, nil)
+}
+
+func TestAgent_Backup(t *testing.T) {
+	a := newTestAgent(t, func(api string, body map[string]any) (int, string, any) {
+		assert.Equal(t, "/api/backup", api)
+		assert.Equal(t, "ds_0", body["db_name"])
+		assert.Equal(t, float64(5432), body["db_port"])
+		assert.Equal(t, BackupModePTrack, body["dn_backup_mode"])
+		return 0, "", map[string]string{"backup_id": "RTKQ1T"}
+	})
+
+	id, err := a.Backup(context.TODO(), &BackupIn{
+		Database:     Database{Port: 5432, Name: "ds_0", Username: "gaussdb", Password: "password"},
+		DnBackupPath: "/home/omm/data",
+		DnThreadsNum: 1,
+		DnBackupMode: BackupModePTrack,
+		Instance:     DefaultInstance,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "RTKQ1T", id)
+}
+
+func TestAgent_ShowDetail(t *testing.T) {
+	a := newTestAgent(t, func(api string, body map[string]any) (int, string, any) {
+		assert.Equal(t, "/api/show", api)
+		assert.Equal(t, "RTKQ1T", body["dn_backup_id"])
+		return 0, "", BackupInfo{ID: "RTKQ1T", Status: BackupStatusCompleted}
+	})
+
+	info, err := a.ShowDetail(context.TODO(), &ShowDetailIn{DnBackupID: "RTKQ1T"})
+	assert.NoError(t, err)
+	assert.Equal(t, &BackupInfo{ID: "RTKQ1T", Status: BackupStatusCompleted}, info)
+}
+
+func TestAgent_SubmitRestore(t *testing.T) {
+	a := newTestAgent(t, func(api string, body map[string]any) (int, string, any) {
+		assert.Equal(t, "/api/restore", api)
+		assert.Equal(t, "RTKQ1T", body["dn_backup_id"])
+		assert.Equal(t, "key", body["request_key"])
+		assert.Equal(t, true, body["async"])
+		return 0, "", map[string]string{"job_id": "job-1"}
+	})
+
+	id, err := a.SubmitRestore(context.TODO(), &RestoreIn{DnBackupID: "RTKQ1T", RequestKey: "key"})
+	assert.NoError(t, err)
+	assert.Equal(t, "job-1", id)
+}
+
+func TestAgent_GetJob(t *testing.T) {
+	a := newTestAgent(t, func(api string, body map[string]any) (int, string, any) {
+		assert.Equal(t, "/api/jobs/job-1", api)
+		return 0, "", map[string]any{"id": "job-1", "kind": "instance-restore", "state": JobStateFailed, "error": "restore failed"}
+	})
+
+	j, err := a.GetJob(context.TODO(), "job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Job{ID: "job-1", Kind: "instance-restore", State: JobStateFailed, Error: "restore failed"}, j)
+}
+
+func TestAgent_Error(t *testing.T) {
+	a := newTestAgent(t, func(api string, body map[string]any) (int, string, any) {
+		return 10001, "restore failed", nil
+	})
+
+	_, err := a.SubmitRestore(context.TODO(), &RestoreIn{DnBackupID: "RTKQ1T"})
+	assert.ErrorContains(t, err, "code=10001, msg=restore failed")
+	assert.ErrorContains(t, a.CheckStatus(context.TODO(), &Database{}), "restore failed")
+}
+
+func TestAgent_Headers(t *testing.T) {
+	ids := map[string]bool{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the same headers are required by the middlewares of the agent
+		id := r.Header.Get("x-request-id")
+		assert.NotEmpty(t, id)
+		assert.False(t, ids[id], "request id %s is reused", id)
+		ids[id] = true
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"code": 0, "msg": "success"}))
+	}))
+	t.Cleanup(server.Close)
+
+	// the certificate of the agent is verified with the root CAs
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	a := NewAgent(server.URL, &Options{Token: "token", RootCAs: pool})
+	assert.NoError(t, a.CheckStatus(context.TODO(), &Database{}))
+	_, err := a.GetJob(context.TODO(), "job-1")
+	assert.NoError(t, err)
+	assert.Len(t, ids, 2)
+
+	assert.Error(t, NewAgent(server.URL, &Options{Token: "token", RootCAs: x509.NewCertPool()}).CheckStatus(context.TODO(), &Database{}))
+}
+
+func TestAgent_Timeout(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	t.Cleanup(server.Close)
+
+	a := NewAgent(server.URL, &Options{Timeout: 100 * time.Millisecond})
+	_, err := a.SubmitRestore(context.TODO(), &RestoreIn{})
+	assert.ErrorContains(t, err, "Timeout")
+}
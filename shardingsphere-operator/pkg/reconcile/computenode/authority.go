@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package computenode
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/configmap"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/secret"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	authoritySecretNameSuffix    = "-authority"
+	serverConfigSecretNameSuffix = "-server-config"
+)
+
+// AuthoritySecretName returns the name of the Secret keeping the generated passwords, keyed by the user name
+func AuthoritySecretName(cn *v1alpha1.ComputeNode) string {
+	return cn.Name + authoritySecretNameSuffix
+}
+
+// ServerConfigSecretName returns the name of the Secret of server.yaml
+func ServerConfigSecretName(cn *v1alpha1.ComputeNode) string {
+	return cn.Name + serverConfigSecretNameSuffix
+}
+
+// generatedPasswordUsers returns the users without a password nor a reference to it
+func generatedPasswordUsers(cn *v1alpha1.ComputeNode) []string {
+	users := []string{}
+	for _, u := range cn.Spec.Bootstrap.ServerConfig.Authority.Users {
+		if u.Password == "" && u.PasswordSecretRef == nil {
+			users = append(users, u.User)
+		}
+	}
+	return users
+}
+
+// UserPassword returns the password of a user of the ComputeNode, resolved from the referenced Secrets
+func UserPassword(cn *v1alpha1.ComputeNode, user v1alpha1.ComputeNodeUser, secrets []*corev1.Secret) (string, error) {
+	return secret.ResolvePassword(user.User, user.Password, user.PasswordSecretRef, AuthoritySecretName(cn), secrets)
+}
+
+func secretObjectMeta(cn *v1alpha1.ComputeNode, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: cn.Namespace,
+		Labels:    cn.Labels,
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(cn.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("ComputeNode")),
+		},
+	}
+}
+
+// BuildAuthoritySecret returns the Secret of the generated passwords, the passwords in cur are kept.
+// It returns nil if every user has a password or a reference to it.
+func (b *builder) BuildAuthoritySecret(ctx context.Context, cn *v1alpha1.ComputeNode, cur *corev1.Secret) (*corev1.Secret, error) {
+	users := generatedPasswordUsers(cn)
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	var data map[string][]byte
+	if cur != nil {
+		data = cur.Data
+	}
+	data, err := secret.GeneratePasswords(users, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: secretObjectMeta(cn, AuthoritySecretName(cn)),
+		Type:       corev1.SecretTypeOpaque,
+		Data:       data,
+	}, nil
+}
+
+// BuildServerConfigSecret returns the Secret of server.yaml, the passwords of the users are resolved from the
+// Secrets, the endpoints of the managed metadata repository and the SSL props of the frontend are filled in
+func (b *builder) BuildServerConfigSecret(ctx context.Context, cn *v1alpha1.ComputeNode, secrets []*corev1.Secret) (*corev1.Secret, error) {
+	exp := &corev1.Secret{
+		ObjectMeta: secretObjectMeta(cn, ServerConfigSecretName(cn)),
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{},
+	}
+
+	// NOTE: ShardingSphere Proxy 5.3.0 needs a server.yaml no matter if it is empty
+	if reflect.DeepEqual(cn.Spec.Bootstrap.ServerConfig, v1alpha1.ServerConfig{}) && !TLSEnabled(cn) {
+		exp.Data[configmap.ConfigDataKeyForServer] = []byte(configmap.DefaultServerConfig)
+		return exp, nil
+	}
+
+	servconf := withManagedRepository(cn).Spec.Bootstrap.ServerConfig.DeepCopy()
+	for i := range servconf.Authority.Users {
+		password, err := UserPassword(cn, servconf.Authority.Users[i], secrets)
+		if err != nil {
+			return nil, err
+		}
+		servconf.Authority.Users[i].Password = password
+	}
+	setTLSProps(cn, servconf)
+
+	y, err := yaml.Marshal(servconf)
+	if err != nil {
+		return nil, err
+	}
+	exp.Data[configmap.ConfigDataKeyForServer] = y
+	return exp, nil
+}
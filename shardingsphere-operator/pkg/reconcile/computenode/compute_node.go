@@ -23,13 +23,26 @@ import (
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 )
 
 // Builder build Deployment from given ComputeNode
 type Builder interface {
-	BuildDeployment(context.Context, *v1alpha1.ComputeNode) *appsv1.Deployment
+	// BuildDeployment builds the Deployment with the Secrets referenced by the ComputeNode
+	BuildDeployment(context.Context, *v1alpha1.ComputeNode, []*corev1.Secret) *appsv1.Deployment
 	BuildConfigMap(context.Context, *v1alpha1.ComputeNode) *corev1.ConfigMap
 	BuildService(context.Context, *v1alpha1.ComputeNode) *corev1.Service
+
+	// BuildAuthoritySecret builds the Secret of the generated passwords with the current one, nil if there is none to generate
+	BuildAuthoritySecret(context.Context, *v1alpha1.ComputeNode, *corev1.Secret) (*corev1.Secret, error)
+	// BuildServerConfigSecret builds the Secret of server.yaml with the Secrets referenced by the ComputeNode
+	BuildServerConfigSecret(context.Context, *v1alpha1.ComputeNode, []*corev1.Secret) (*corev1.Secret, error)
+
+	// BuildGovernanceStatefulSet, BuildGovernanceService and BuildGovernancePodDisruptionBudget
+	// build the managed metadata repository, the repository must be managed
+	BuildGovernanceStatefulSet(context.Context, *v1alpha1.ComputeNode) *appsv1.StatefulSet
+	BuildGovernanceService(context.Context, *v1alpha1.ComputeNode) *corev1.Service
+	BuildGovernancePodDisruptionBudget(context.Context, *v1alpha1.ComputeNode) *policyv1.PodDisruptionBudget
 }
 
 // NewBulder builds resources needed by ComputeNode
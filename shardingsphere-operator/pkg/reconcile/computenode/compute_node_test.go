@@ -18,11 +18,16 @@
 package computenode_test
 
 import (
+	"context"
+
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/computenode"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("GetConditionFromPods", func() {
@@ -276,3 +281,259 @@ func containConditionType(conds []v1alpha1.ComputeNodeCondition, ts ...v1alpha1.
 	}
 	return true
 }
+
+var _ = Describe("ConfigHash", func() {
+	cm := &corev1.ConfigMap{Data: map[string]string{"server.yaml": "props: {}", "logback.xml": "<configuration/>"}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s1"}, Data: map[string][]byte{"password": []byte("p1")}}
+
+	It("should be stable", func() {
+		other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s0"}}
+		Expect(computenode.ConfigHash(cm, []*corev1.Secret{secret, other})).To(Equal(computenode.ConfigHash(cm, []*corev1.Secret{other, secret})))
+	})
+
+	It("should change with the configuration and the secrets", func() {
+		hash := computenode.ConfigHash(cm, []*corev1.Secret{secret})
+		Expect(computenode.ConfigHash(cm, nil)).NotTo(Equal(hash))
+
+		changed := &corev1.Secret{ObjectMeta: secret.ObjectMeta, Data: map[string][]byte{"password": []byte("p2")}}
+		Expect(computenode.ConfigHash(cm, []*corev1.Secret{changed})).NotTo(Equal(hash))
+
+		cm2 := &corev1.ConfigMap{Data: map[string]string{"server.yaml": "props: {a: b}", "logback.xml": "<configuration/>"}}
+		Expect(computenode.ConfigHash(cm2, []*corev1.Secret{secret})).NotTo(Equal(hash))
+	})
+
+	It("should find the referenced secrets", func() {
+		ref := func(name string) corev1.EnvVar {
+			return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			}}}
+		}
+		cn := &v1alpha1.ComputeNode{Spec: v1alpha1.ComputeNodeSpec{Env: []corev1.EnvVar{ref("s2"), {Name: "plain", Value: "v"}, ref("s1"), ref("s2")}}}
+		Expect(computenode.ReferencedSecretNames(cn)).To(Equal([]string{"s1", "s2"}))
+	})
+})
+
+var _ = Describe("GetUpdatingConditionFromDeployment", func() {
+	var replicas int32 = 2
+	newDeployment := func(status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}, Status: status}
+	}
+
+	It("should be updating until all pods are updated and available", func() {
+		cases := map[string]appsv1.DeploymentStatus{
+			"PodUpdating":       {Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2},
+			"OldPodTerminating": {Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2},
+			"PodUnavailable":    {Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1},
+		}
+		for reason, status := range cases {
+			cond := computenode.GetUpdatingConditionFromDeployment(newDeployment(status))
+			Expect(cond.Type).To(Equal(v1alpha1.ComputeNodeConditionUpdating))
+			Expect(cond.Status).To(Equal(v1alpha1.ConditionStatusTrue))
+			Expect(cond.Reason).To(Equal(reason))
+		}
+
+		deploy := newDeployment(appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2})
+		deploy.Generation = 2
+		deploy.Status.ObservedGeneration = 1
+		Expect(computenode.GetUpdatingConditionFromDeployment(deploy).Reason).To(Equal("DeploymentNotObserved"))
+	})
+
+	It("should not be updating after the rollout is complete", func() {
+		cond := computenode.GetUpdatingConditionFromDeployment(newDeployment(appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}))
+		Expect(cond.Status).To(Equal(v1alpha1.ConditionStatusFalse))
+		Expect(cond.Reason).To(Equal("RolloutComplete"))
+	})
+})
+
+var _ = Describe("Managed governance", func() {
+	var cn *v1alpha1.ComputeNode
+	BeforeEach(func() {
+		cn = &v1alpha1.ComputeNode{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "ComputeNode"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			Spec: v1alpha1.ComputeNodeSpec{
+				Bootstrap: v1alpha1.BootstrapConfig{
+					ServerConfig: v1alpha1.ServerConfig{
+						Mode: v1alpha1.ComputeNodeServerMode{
+							Type: v1alpha1.ModeTypeCluster,
+							Repository: v1alpha1.Repository{
+								Type:    v1alpha1.RepositoryTypeZookeeper,
+								Props:   v1alpha1.Properties{"namespace": "ns"},
+								Managed: &v1alpha1.ManagedRepository{},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should fill the endpoints of the members in server.yaml", func() {
+		secret, err := computenode.NewBuilder().BuildServerConfigSecret(context.TODO(), cn, nil)
+		Expect(err).To(BeNil())
+		Expect(string(secret.Data["server.yaml"])).To(ContainSubstring("server-lists: foo-governance-0.foo-governance.bar.svc:2181,foo-governance-1.foo-governance.bar.svc:2181,foo-governance-2.foo-governance.bar.svc:2181"))
+		Expect(string(secret.Data["server.yaml"])).To(ContainSubstring("namespace: ns"))
+		Expect(string(secret.Data["server.yaml"])).NotTo(ContainSubstring("managed"))
+		Expect(cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Props).NotTo(HaveKey("server-lists"))
+
+		cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Type = v1alpha1.RepositoryTypeEtcd
+		cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed.Replicas = 1
+		Expect(computenode.GovernanceServerLists(cn)).To(Equal("http://foo-governance-0.foo-governance.bar.svc:2379"))
+	})
+
+	It("should build the ZooKeeper ensemble", func() {
+		b := computenode.NewBuilder()
+		sts := b.BuildGovernanceStatefulSet(context.TODO(), cn)
+		Expect(sts.Name).To(Equal("foo-governance"))
+		Expect(*sts.Spec.Replicas).To(Equal(int32(3)))
+		Expect(sts.Spec.ServiceName).To(Equal("foo-governance"))
+		Expect(sts.Spec.Template.Spec.Containers[0].Image).To(Equal("zookeeper:3.8.1"))
+		Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  "ZOO_SERVERS",
+			Value: "server.1=foo-governance-0.foo-governance.bar.svc:2888:3888;2181 server.2=foo-governance-1.foo-governance.bar.svc:2888:3888;2181 server.3=foo-governance-2.foo-governance.bar.svc:2888:3888;2181",
+		}))
+		Expect(sts.Spec.Template.Spec.Volumes).To(BeEmpty())
+		Expect(sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("1Gi")))
+
+		svc := b.BuildGovernanceService(context.TODO(), cn)
+		Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+		Expect(svc.Spec.PublishNotReadyAddresses).To(BeTrue())
+		Expect(svc.Spec.Selector).To(Equal(sts.Spec.Selector.MatchLabels))
+
+		pdb := b.BuildGovernancePodDisruptionBudget(context.TODO(), cn)
+		Expect(pdb.Spec.MinAvailable.IntValue()).To(Equal(2))
+	})
+
+	It("should build the Etcd cluster with persistent volumes", func() {
+		size := resource.MustParse("1Gi")
+		cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Type = v1alpha1.RepositoryTypeEtcd
+		cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed.Storage = &size
+
+		sts := computenode.NewBuilder().BuildGovernanceStatefulSet(context.TODO(), cn)
+		Expect(sts.Spec.Template.Spec.Containers[0].Args).To(ContainElements(
+			"--initial-cluster=foo-governance-0=http://foo-governance-0.foo-governance.bar.svc:2380,foo-governance-1=http://foo-governance-1.foo-governance.bar.svc:2380,foo-governance-2=http://foo-governance-2.foo-governance.bar.svc:2380",
+			"--advertise-client-urls=http://$(POD_NAME).foo-governance.bar.svc:2379",
+		))
+		Expect(sts.Spec.Template.Spec.Volumes).To(BeEmpty())
+		Expect(sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(size))
+	})
+
+	It("should back the Etcd members by volumes and rejoin an empty member by default", func() {
+		cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Type = v1alpha1.RepositoryTypeEtcd
+
+		sts := computenode.NewBuilder().BuildGovernanceStatefulSet(context.TODO(), cn)
+		Expect(sts.Spec.Template.Spec.Volumes).To(BeEmpty())
+		Expect(sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("1Gi")))
+
+		c := sts.Spec.Template.Spec.Containers[0]
+		Expect(c.Image).To(Equal("quay.io/coreos/etcd:v3.4.27"))
+		Expect(c.Command).To(HaveLen(4))
+		Expect(c.Command[:2]).To(Equal([]string{"sh", "-c"}))
+		Expect(c.Command[2]).To(ContainSubstring(`PEER_URL=http://${POD_NAME}.foo-governance.bar.svc:2380`))
+		Expect(c.Command[2]).To(ContainSubstring(`if [ ! -d "/var/run/etcd/default.etcd/member" ]; then`))
+		Expect(c.Command[2]).To(ContainSubstring(`member remove "${MEMBER%%,*}"`))
+		Expect(c.Command[2]).To(ContainSubstring(`exec etcd "$@" --initial-cluster-state=${STATE}`))
+		Expect(c.Args).NotTo(ContainElement(HavePrefix("--initial-cluster-state")))
+	})
+
+	It("should be ready once the members reach quorum", func() {
+		var replicas int32 = 3
+		sts := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: &replicas}, Status: appsv1.StatefulSetStatus{ReadyReplicas: 1}}
+		cond := computenode.GetGovernanceReadyConditionFromStatefulSet(sts)
+		Expect(cond.Status).To(Equal(v1alpha1.ConditionStatusFalse))
+		Expect(cond.Message).To(Equal("1 of 3 members are ready, the quorum is 2"))
+
+		sts.Status.ReadyReplicas = 2
+		Expect(computenode.GetGovernanceReadyConditionFromStatefulSet(sts).Status).To(Equal(v1alpha1.ConditionStatusTrue))
+	})
+})
+
+var _ = Describe("Authority", func() {
+	var cn *v1alpha1.ComputeNode
+	BeforeEach(func() {
+		cn = &v1alpha1.ComputeNode{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			Spec: v1alpha1.ComputeNodeSpec{
+				Bootstrap: v1alpha1.BootstrapConfig{
+					ServerConfig: v1alpha1.ServerConfig{
+						Authority: v1alpha1.ComputeNodeAuthority{
+							Users: []v1alpha1.ComputeNodeUser{
+								{User: "root@%"},
+								{User: "app@%", PasswordSecretRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "app-user"},
+									Key:                  "password",
+								}},
+								{User: "plain@%", Password: "plain"},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should generate the missing passwords and keep the existing ones", func() {
+		b := computenode.NewBuilder()
+		secret, err := b.BuildAuthoritySecret(context.TODO(), cn, nil)
+		Expect(err).To(BeNil())
+		Expect(secret.Name).To(Equal("foo-authority"))
+		Expect(secret.Data).To(HaveLen(1))
+		Expect(secret.Data["root"]).NotTo(BeEmpty())
+
+		again, err := b.BuildAuthoritySecret(context.TODO(), cn, secret)
+		Expect(err).To(BeNil())
+		Expect(again.Data).To(Equal(secret.Data))
+
+		cn.Spec.Bootstrap.ServerConfig.Authority.Users = cn.Spec.Bootstrap.ServerConfig.Authority.Users[1:]
+		secret, err = b.BuildAuthoritySecret(context.TODO(), cn, secret)
+		Expect(err).To(BeNil())
+		Expect(secret).To(BeNil())
+	})
+
+	It("should render the resolved passwords into the server config secret", func() {
+		secrets := []*corev1.Secret{
+			{ObjectMeta: metav1.ObjectMeta{Name: "foo-authority"}, Data: map[string][]byte{"root": []byte("generated")}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "app-user"}, Data: map[string][]byte{"password": []byte("referenced")}},
+		}
+		Expect(computenode.ReferencedSecretNames(cn)).To(Equal([]string{"app-user", "foo-authority"}))
+
+		secret, err := computenode.NewBuilder().BuildServerConfigSecret(context.TODO(), cn, secrets)
+		Expect(err).To(BeNil())
+		Expect(secret.Name).To(Equal("foo-server-config"))
+		conf := string(secret.Data["server.yaml"])
+		Expect(conf).To(ContainSubstring("password: generated"))
+		Expect(conf).To(ContainSubstring("password: referenced"))
+		Expect(conf).To(ContainSubstring("password: plain"))
+		Expect(conf).NotTo(ContainSubstring("passwordSecretRef"))
+		Expect(cn.Spec.Bootstrap.ServerConfig.Authority.Users[0].Password).To(BeEmpty())
+
+		_, err = computenode.NewBuilder().BuildServerConfigSecret(context.TODO(), cn, secrets[:1])
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).NotTo(ContainSubstring("generated"))
+	})
+
+	It("should keep server.yaml out of the ConfigMap", func() {
+		cn.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "ComputeNode"}
+		cm := computenode.NewBuilder().BuildConfigMap(context.TODO(), cn)
+		Expect(cm.Data).NotTo(HaveKey("server.yaml"))
+		Expect(cm.Data).To(HaveKey("logback.xml"))
+	})
+})
+
+var _ = Describe("TLS", func() {
+	It("should enable the frontend SSL in server.yaml", func() {
+		cn := &v1alpha1.ComputeNode{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		Expect(computenode.TLSEnabled(cn)).To(BeFalse())
+
+		cn.Spec.TLS = &v1alpha1.ComputeNodeTLS{Versions: []string{"TLSv1.2", "TLSv1.3"}, Ciphers: []string{"TLS_AES_128_GCM_SHA256"}}
+		secret, err := computenode.NewBuilder().BuildServerConfigSecret(context.TODO(), cn, nil)
+		Expect(err).To(BeNil())
+		conf := string(secret.Data["server.yaml"])
+		Expect(conf).To(ContainSubstring("proxy-frontend-ssl-enabled: \"true\""))
+		Expect(conf).To(ContainSubstring("proxy-frontend-ssl-version: TLSv1.2,TLSv1.3"))
+		Expect(conf).To(ContainSubstring("proxy-frontend-ssl-cipher: TLS_AES_128_GCM_SHA256"))
+
+		dp := computenode.NewBuilder().BuildDeployment(context.TODO(), cn, nil)
+		Expect(dp.Spec.Template.Spec.Volumes[0].Projected.Sources).To(HaveLen(2))
+	})
+})
@@ -26,7 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-// BuildConfigMap returns a config map
+// BuildConfigMap returns a config map, server.yaml is kept in the Secret built by BuildServerConfigSecret
 func (b *builder) BuildConfigMap(ctx context.Context, cn *v1alpha1.ComputeNode) *corev1.ConfigMap {
 	return configmap.NewConfigMap(cn)
 }
@@ -43,6 +43,10 @@ const (
 	commonAnnotationPrometheusMetricsPort   = "prometheus.io/port"
 	commonAnnotationPrometheusMetricsScrape = "prometheus.io/scrape"
 	commonAnnotationPrometheusMetricsScheme = "prometheus.io/scheme"
+
+	// AnnotationConfigHash is the content hash of the configuration and the referenced Secrets in the pod template,
+	// the pods are rolled when it changes
+	AnnotationConfigHash = "shardingsphere.apache.org/config-hash"
 )
 
 const (
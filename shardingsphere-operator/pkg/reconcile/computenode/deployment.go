@@ -34,12 +34,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
-// Build returns a new Deployment
-func (b builder) BuildDeployment(ctx context.Context, cn *v1alpha1.ComputeNode) *appsv1.Deployment {
+// Build returns a new Deployment, the content hash of its configuration and the referenced secrets is stamped into the pod template
+func (b builder) BuildDeployment(ctx context.Context, cn *v1alpha1.ComputeNode, secrets []*corev1.Secret) *appsv1.Deployment {
 	ssbuilder := NewShardingSphereDeploymentBuilder(cn.GetObjectMeta(), cn.GetObjectKind().GroupVersionKind())
 
+	// server.yaml is hashed with the Secrets, so a change of props or a password rotation rolls the pods.
+	// The error of an unresolved password is surfaced when reconciling the Secret of server.yaml.
+	srv, _ := b.BuildServerConfigSecret(ctx, cn, secrets)
+
 	b.buildMetadata(ssbuilder, cn)
-	b.buildSpec(ssbuilder, cn)
+	b.buildSpec(ssbuilder, cn, ConfigHash(b.BuildConfigMap(ctx, cn), append([]*corev1.Secret{srv}, secrets...)))
 
 	return ssbuilder.BuildShardingSphereDeployment()
 }
@@ -80,14 +84,19 @@ func (b builder) buildMetadata(ssbuilder ShardingSphereDeploymentBuilder, cn *v1
 		})
 }
 
-func (b builder) buildSpec(ssbuilder ShardingSphereDeploymentBuilder, cn *v1alpha1.ComputeNode) {
+func (b builder) buildSpec(ssbuilder ShardingSphereDeploymentBuilder, cn *v1alpha1.ComputeNode, configHash string) {
 	ssbuilder.SetSelectors(cn.Spec.Selector)
 	ssbuilder.SetReplicas(&cn.Spec.Replicas)
 	ssbuilder.SetRollingUpdateStrategy(0, 3)
+	if cn.Spec.RollingUpdate != nil {
+		ssbuilder.SetMaxUnavailable(cn.Spec.RollingUpdate.MaxUnavailable)
+	}
 
 	tpl := &corev1.PodTemplateSpec{}
 	tm := metadata.NewMetadataBuilder()
 	tm.SetLabels(cn.Labels)
+	// the pods are rolled once the configuration or the referenced secrets change
+	tm.SetAnnotations(map[string]string{AnnotationConfigHash: configHash})
 
 	ports := getContainerPortsFromComputeNode(cn)
 
@@ -98,10 +107,18 @@ func (b builder) buildSpec(ssbuilder ShardingSphereDeploymentBuilder, cn *v1alph
 
 	b.buildProbes(scb, cn)
 
+	// server.yaml carries the passwords so it is projected from a Secret next to the ConfigMap
 	vcb := deployment.NewSharedVolumeAndMountBuilder().
 		SetVolumeMountSize(1).
 		SetName(defaultConfigVolumeName).
-		SetVolumeSourceConfigMap(cn.Name).
+		SetVolumeSourceProjected(
+			corev1.VolumeProjection{ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cn.Name},
+			}},
+			corev1.VolumeProjection{Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ServerConfigSecretName(cn)},
+			}},
+		).
 		SetMountPath(0, defaultConfigVolumeMountPath)
 	vc, vmc := vcb.Build()
 
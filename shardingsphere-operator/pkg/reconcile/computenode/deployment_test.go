@@ -33,7 +33,7 @@ import (
 
 func testNewDeployment(cn *v1alpha1.ComputeNode) *appsv1.Deployment {
 	b := builder{}
-	return b.BuildDeployment(context.TODO(), cn)
+	return b.BuildDeployment(context.TODO(), cn, nil)
 }
 
 func Test_NewDeployment(t *testing.T) {
@@ -202,9 +202,22 @@ func Test_NewDeployment(t *testing.T) {
 								{
 									Name: defaultConfigVolumeName,
 									VolumeSource: corev1.VolumeSource{
-										ConfigMap: &corev1.ConfigMapVolumeSource{
-											LocalObjectReference: corev1.LocalObjectReference{
-												Name: "test-name",
+										Projected: &corev1.ProjectedVolumeSource{
+											Sources: []corev1.VolumeProjection{
+												{
+													ConfigMap: &corev1.ConfigMapProjection{
+														LocalObjectReference: corev1.LocalObjectReference{
+															Name: "test-name",
+														},
+													},
+												},
+												{
+													Secret: &corev1.SecretProjection{
+														LocalObjectReference: corev1.LocalObjectReference{
+															Name: "test-name-server-config",
+														},
+													},
+												},
 											},
 										},
 									},
@@ -385,9 +398,22 @@ func Test_NewDeployment(t *testing.T) {
 								{
 									Name: defaultConfigVolumeName,
 									VolumeSource: corev1.VolumeSource{
-										ConfigMap: &corev1.ConfigMapVolumeSource{
-											LocalObjectReference: corev1.LocalObjectReference{
-												Name: "test-java-agent",
+										Projected: &corev1.ProjectedVolumeSource{
+											Sources: []corev1.VolumeProjection{
+												{
+													ConfigMap: &corev1.ConfigMapProjection{
+														LocalObjectReference: corev1.LocalObjectReference{
+															Name: "test-java-agent",
+														},
+													},
+												},
+												{
+													Secret: &corev1.SecretProjection{
+														LocalObjectReference: corev1.LocalObjectReference{
+															Name: "test-java-agent-server-config",
+														},
+													},
+												},
 											},
 										},
 									},
@@ -0,0 +1,331 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package computenode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	defaultGovernanceReplicas  = 3
+	defaultGovernanceNamespace = "governance_ds"
+	defaultZookeeperImage      = "zookeeper:3.8.1"
+	defaultEtcdImage           = "quay.io/coreos/etcd:v3.4.27" // the images of Etcd 3.5 have no shell
+
+	governanceNameSuffix     = "-governance"
+	governanceContainerName  = "governance"
+	governanceDataVolumeName = "data"
+
+	zookeeperClientPort   = 2181
+	zookeeperPeerPort     = 2888
+	zookeeperElectionPort = 3888
+	zookeeperDataPath     = "/data"
+
+	etcdClientPort = 2379
+	etcdPeerPort   = 2380
+	etcdDataPath   = "/var/run/etcd"
+)
+
+// defaultGovernanceStorage is the size of the persistent volume of a member if the storage is not set
+var defaultGovernanceStorage = resource.MustParse("1Gi")
+
+// etcdBootstrapScript starts an Etcd member with the arguments of the container.
+// A member restarted with an empty data dir is still in the membership with its old member id,
+// so it is removed and added again to join the existing cluster. A member which is added but
+// never started joins the existing cluster as well, the others bootstrap a new one.
+const etcdBootstrapScript = `set -e
+PEER_URL=http://${POD_NAME}.%[1]s:%[2]d
+ETCDCTL="etcdctl --endpoints=%[3]s --dial-timeout=3s --command-timeout=5s"
+STATE=new
+if [ ! -d "%[4]s/member" ]; then
+  MEMBER=$(${ETCDCTL} member list 2>/dev/null | grep "${PEER_URL}," || true)
+  if [ -n "${MEMBER}" ]; then
+    if echo "${MEMBER}" | grep -q ", started, "; then
+      ${ETCDCTL} member remove "${MEMBER%%%%,*}"
+      ${ETCDCTL} member add "${POD_NAME}" --peer-urls="${PEER_URL}"
+    fi
+    STATE=existing
+  fi
+fi
+exec etcd "$@" --initial-cluster-state=${STATE}`
+
+// GovernanceName returns the name of the StatefulSet, the headless Service and the PodDisruptionBudget
+// of the managed metadata repository
+func GovernanceName(cn *v1alpha1.ComputeNode) string {
+	return cn.Name + governanceNameSuffix
+}
+
+// GovernanceReplicas returns the number of members of the managed metadata repository
+func GovernanceReplicas(managed *v1alpha1.ManagedRepository) int32 {
+	if managed.Replicas <= 0 {
+		return defaultGovernanceReplicas
+	}
+	return managed.Replicas
+}
+
+// GovernanceQuorum returns the least number of members to serve
+func GovernanceQuorum(replicas int32) int32 {
+	return replicas/2 + 1
+}
+
+// GovernanceServerLists returns the client endpoints of all the members, in the format of the server-lists property
+func GovernanceServerLists(cn *v1alpha1.ComputeNode) string {
+	repo := cn.Spec.Bootstrap.ServerConfig.Mode.Repository
+	servers := make([]string, 0, GovernanceReplicas(repo.Managed))
+	for i := int32(0); i < GovernanceReplicas(repo.Managed); i++ {
+		if repo.Type == v1alpha1.RepositoryTypeEtcd {
+			servers = append(servers, fmt.Sprintf("http://%s:%d", governanceMemberHost(cn, i), etcdClientPort))
+		} else {
+			servers = append(servers, fmt.Sprintf("%s:%d", governanceMemberHost(cn, i), zookeeperClientPort))
+		}
+	}
+	return strings.Join(servers, ",")
+}
+
+// governanceMemberHost returns the DNS name of the member through the headless Service
+func governanceMemberHost(cn *v1alpha1.ComputeNode, ordinal int32) string {
+	name := GovernanceName(cn)
+	return fmt.Sprintf("%s-%d.%s.%s.svc", name, ordinal, name, cn.Namespace)
+}
+
+// withManagedRepository returns a copy of the ComputeNode with the endpoints of the managed metadata repository
+// filled in the server-lists property, the ComputeNode is returned as it is if the repository is not managed
+func withManagedRepository(cn *v1alpha1.ComputeNode) *v1alpha1.ComputeNode {
+	if cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed == nil {
+		return cn
+	}
+
+	exp := cn.DeepCopy()
+	repo := &exp.Spec.Bootstrap.ServerConfig.Mode.Repository
+	if repo.Props == nil {
+		repo.Props = v1alpha1.Properties{}
+	}
+	repo.Props["server-lists"] = GovernanceServerLists(cn)
+	if repo.Props["namespace"] == "" {
+		repo.Props["namespace"] = defaultGovernanceNamespace
+	}
+	return exp
+}
+
+func governanceLabels(cn *v1alpha1.ComputeNode) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":      strings.ToLower(string(cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Type)),
+		"app.kubernetes.io/instance":  GovernanceName(cn),
+		"app.kubernetes.io/component": "governance",
+	}
+}
+
+func governanceObjectMeta(cn *v1alpha1.ComputeNode) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      GovernanceName(cn),
+		Namespace: cn.Namespace,
+		Labels:    governanceLabels(cn),
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(cn.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("ComputeNode")),
+		},
+	}
+}
+
+// BuildGovernanceService returns the headless Service of the managed metadata repository,
+// the not ready members are published to find each other before reaching quorum
+func (b *builder) BuildGovernanceService(ctx context.Context, cn *v1alpha1.ComputeNode) *corev1.Service {
+	ports := []corev1.ServicePort{
+		{Name: "client", Port: zookeeperClientPort},
+		{Name: "peer", Port: zookeeperPeerPort},
+		{Name: "election", Port: zookeeperElectionPort},
+	}
+	if cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Type == v1alpha1.RepositoryTypeEtcd {
+		ports = []corev1.ServicePort{
+			{Name: "client", Port: etcdClientPort},
+			{Name: "peer", Port: etcdPeerPort},
+		}
+	}
+
+	return &corev1.Service{
+		ObjectMeta: governanceObjectMeta(cn),
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			Selector:                 governanceLabels(cn),
+			Ports:                    ports,
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// BuildGovernancePodDisruptionBudget returns the PodDisruptionBudget keeping the quorum of the managed metadata repository
+func (b *builder) BuildGovernancePodDisruptionBudget(ctx context.Context, cn *v1alpha1.ComputeNode) *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(int(GovernanceQuorum(GovernanceReplicas(cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed))))
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: governanceObjectMeta(cn),
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: governanceLabels(cn)},
+		},
+	}
+}
+
+// BuildGovernanceStatefulSet returns the StatefulSet of the managed metadata repository
+func (b *builder) BuildGovernanceStatefulSet(ctx context.Context, cn *v1alpha1.ComputeNode) *appsv1.StatefulSet {
+	managed := cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed
+	replicas := GovernanceReplicas(managed)
+
+	var c corev1.Container
+	if cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Type == v1alpha1.RepositoryTypeEtcd {
+		c = buildEtcdContainer(cn, replicas)
+	} else {
+		c = buildZookeeperContainer(cn, replicas)
+	}
+	c.Name = governanceContainerName
+	c.Resources = managed.Resources
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: governanceObjectMeta(cn),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: GovernanceName(cn),
+			Selector:    &metav1.LabelSelector{MatchLabels: governanceLabels(cn)},
+			// all the members are started at the same time to reach quorum
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: governanceLabels(cn)},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{c},
+				},
+			},
+		},
+	}
+
+	// the members are backed by volumes, so the data is kept when they are restarted one by one
+	storage := managed.Storage
+	if storage == nil {
+		storage = &defaultGovernanceStorage
+	}
+
+	sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{
+		ObjectMeta: metav1.ObjectMeta{Name: governanceDataVolumeName},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: managed.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: *storage},
+			},
+		},
+	}}
+	return sts
+}
+
+// buildZookeeperContainer returns the container of the official ZooKeeper image,
+// the id of the member is the ordinal of the pod plus one
+func buildZookeeperContainer(cn *v1alpha1.ComputeNode, replicas int32) corev1.Container {
+	servers := make([]string, 0, replicas)
+	for i := int32(0); i < replicas; i++ {
+		servers = append(servers, fmt.Sprintf("server.%d=%s:%d:%d;%d", i+1, governanceMemberHost(cn, i), zookeeperPeerPort, zookeeperElectionPort, zookeeperClientPort))
+	}
+
+	image := cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed.Image
+	if image == "" {
+		image = defaultZookeeperImage
+	}
+
+	return corev1.Container{
+		Image:   image,
+		Command: []string{"bash", "-c", `export ZOO_MY_ID=$((${HOSTNAME##*-}+1)) && exec /docker-entrypoint.sh zkServer.sh start-foreground`},
+		Env: []corev1.EnvVar{
+			{Name: "ZOO_SERVERS", Value: strings.Join(servers, " ")},
+			{Name: "ZOO_CFG_EXTRA", Value: "quorumListenOnAllIPs=true"},
+			{Name: "ZOO_4LW_COMMANDS_WHITELIST", Value: "srvr,ruok"},
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "client", ContainerPort: zookeeperClientPort},
+			{Name: "peer", ContainerPort: zookeeperPeerPort},
+			{Name: "election", ContainerPort: zookeeperElectionPort},
+		},
+		// the member is ready once it is a leader or follower of the quorum
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				Exec: &corev1.ExecAction{Command: []string{"zkServer.sh", "status"}},
+			},
+			InitialDelaySeconds: 10,
+			PeriodSeconds:       10,
+			TimeoutSeconds:      5,
+		},
+		VolumeMounts: []corev1.VolumeMount{{Name: governanceDataVolumeName, MountPath: zookeeperDataPath}},
+	}
+}
+
+// buildEtcdContainer returns the container bootstrapping a static Etcd cluster, the image must have sh and etcdctl
+func buildEtcdContainer(cn *v1alpha1.ComputeNode, replicas int32) corev1.Container {
+	name := GovernanceName(cn)
+	peers := make([]string, 0, replicas)
+	for i := int32(0); i < replicas; i++ {
+		peers = append(peers, fmt.Sprintf("%s-%d=http://%s:%d", name, i, governanceMemberHost(cn, i), etcdPeerPort))
+	}
+	domain := fmt.Sprintf("%s.%s.svc", name, cn.Namespace)
+	host := fmt.Sprintf("$(POD_NAME).%s", domain)
+	dataDir := fmt.Sprintf("%s/default.etcd", etcdDataPath)
+
+	image := cn.Spec.Bootstrap.ServerConfig.Mode.Repository.Managed.Image
+	if image == "" {
+		image = defaultEtcdImage
+	}
+
+	return corev1.Container{
+		Image: image,
+		// the script decides the initial cluster state and passes the arguments to etcd
+		Command: []string{"sh", "-c", fmt.Sprintf(etcdBootstrapScript, domain, etcdPeerPort, GovernanceServerLists(cn), dataDir), "etcd"},
+		Args: []string{
+			"--name=$(POD_NAME)",
+			fmt.Sprintf("--data-dir=%s", dataDir),
+			fmt.Sprintf("--listen-client-urls=http://0.0.0.0:%d", etcdClientPort),
+			fmt.Sprintf("--advertise-client-urls=http://%s:%d", host, etcdClientPort),
+			fmt.Sprintf("--listen-peer-urls=http://0.0.0.0:%d", etcdPeerPort),
+			fmt.Sprintf("--initial-advertise-peer-urls=http://%s:%d", host, etcdPeerPort),
+			fmt.Sprintf("--initial-cluster=%s", strings.Join(peers, ",")),
+			fmt.Sprintf("--initial-cluster-token=%s", name),
+		},
+		Env: []corev1.EnvVar{{
+			Name:      "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+		}},
+		Ports: []corev1.ContainerPort{
+			{Name: "client", ContainerPort: etcdClientPort},
+			{Name: "peer", ContainerPort: etcdPeerPort},
+		},
+		// the health endpoint fails without a leader
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(etcdClientPort)},
+			},
+			InitialDelaySeconds: 10,
+			PeriodSeconds:       10,
+			TimeoutSeconds:      5,
+		},
+		VolumeMounts: []corev1.VolumeMount{{Name: governanceDataVolumeName, MountPath: etcdDataPath}},
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package computenode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ReferencedSecretNames returns the names of the Secrets referenced by the env and the users of the ComputeNode,
+// including the Secret of the generated passwords
+func ReferencedSecretNames(cn *v1alpha1.ComputeNode) []string {
+	names := map[string]struct{}{}
+	for i := range cn.Spec.Env {
+		if from := cn.Spec.Env[i].ValueFrom; from != nil && from.SecretKeyRef != nil {
+			names[from.SecretKeyRef.Name] = struct{}{}
+		}
+	}
+	for _, u := range cn.Spec.Bootstrap.ServerConfig.Authority.Users {
+		if u.PasswordSecretRef != nil {
+			names[u.PasswordSecretRef.Name] = struct{}{}
+		}
+	}
+	if len(generatedPasswordUsers(cn)) > 0 {
+		names[AuthoritySecretName(cn)] = struct{}{}
+	}
+
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// ConfigHash returns the content hash of the rendered ConfigMap and the referenced Secrets
+func ConfigHash(cm *corev1.ConfigMap, secrets []*corev1.Secret) string {
+	h := sha256.New()
+	write := func(prefix string, data map[string][]byte) {
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, f := range [][]byte{[]byte(prefix), []byte(k), data[k]} {
+				h.Write(f)
+				h.Write([]byte{0})
+			}
+		}
+	}
+
+	if cm != nil {
+		write("configmap", stringData(cm.Data))
+		write("configmap", cm.BinaryData)
+	}
+
+	sorted := make([]*corev1.Secret, 0, len(secrets))
+	for _, s := range secrets {
+		if s != nil {
+			sorted = append(sorted, s)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	for _, s := range sorted {
+		write("secret/"+s.Name, s.Data)
+		write("secret/"+s.Name, stringData(s.StringData))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func stringData(data map[string]string) map[string][]byte {
+	m := make(map[string][]byte, len(data))
+	for k, v := range data {
+		m[k] = []byte(v)
+	}
+	return m
+}
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package computenode
+
+import (
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+)
+
+const (
+	propFrontendSSLEnabled = "proxy-frontend-ssl-enabled"
+	propFrontendSSLVersion = "proxy-frontend-ssl-version"
+	propFrontendSSLCipher  = "proxy-frontend-ssl-cipher"
+)
+
+// TLSEnabled returns whether the SSL of the frontend is enabled by spec.tls
+func TLSEnabled(cn *v1alpha1.ComputeNode) bool {
+	return cn.Spec.TLS != nil
+}
+
+// setTLSProps enables the SSL of the frontend in the props of server.yaml, the proxy serves
+// the self-signed certificate generated by itself since no props take a certificate
+func setTLSProps(cn *v1alpha1.ComputeNode, servconf *v1alpha1.ServerConfig) {
+	if !TLSEnabled(cn) {
+		return
+	}
+	if servconf.Props == nil {
+		servconf.Props = v1alpha1.Properties{}
+	}
+	servconf.Props[propFrontendSSLEnabled] = "true"
+	if versions := cn.Spec.TLS.Versions; len(versions) > 0 {
+		servconf.Props[propFrontendSSLVersion] = strings.Join(versions, ",")
+	}
+	if ciphers := cn.Spec.TLS.Ciphers; len(ciphers) > 0 {
+		servconf.Props[propFrontendSSLCipher] = strings.Join(ciphers, ",")
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -96,6 +97,48 @@ func GetConditionFromPods(podlist *corev1.PodList) []v1alpha1.ComputeNodeConditi
 	return conds
 }
 
+// GetUpdatingConditionFromDeployment returns the Updating condition by the rollout of the deployment,
+// it is true until all the pods are updated and available
+func GetUpdatingConditionFromDeployment(deploy *appsv1.Deployment) v1alpha1.ComputeNodeCondition {
+	var replicas int32 = 1
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+
+	st := deploy.Status
+	switch {
+	case deploy.Generation > st.ObservedGeneration:
+		return newCondition(v1alpha1.ComputeNodeConditionUpdating, "DeploymentNotObserved", "Waiting for the deployment update to be observed")
+	case st.UpdatedReplicas < replicas:
+		return newCondition(v1alpha1.ComputeNodeConditionUpdating, "PodUpdating", fmt.Sprintf("%d of %d pods are updated", st.UpdatedReplicas, replicas))
+	case st.Replicas > st.UpdatedReplicas:
+		return newCondition(v1alpha1.ComputeNodeConditionUpdating, "OldPodTerminating", fmt.Sprintf("%d old pods are pending termination", st.Replicas-st.UpdatedReplicas))
+	case st.AvailableReplicas < st.UpdatedReplicas:
+		return newCondition(v1alpha1.ComputeNodeConditionUpdating, "PodUnavailable", fmt.Sprintf("%d of %d updated pods are available", st.AvailableReplicas, st.UpdatedReplicas))
+	}
+
+	cond := newCondition(v1alpha1.ComputeNodeConditionUpdating, "RolloutComplete", "All pods are updated")
+	cond.Status = v1alpha1.ConditionStatusFalse
+	return cond
+}
+
+// GetGovernanceReadyConditionFromStatefulSet returns whether the ready members of the managed metadata repository reach quorum
+func GetGovernanceReadyConditionFromStatefulSet(sts *appsv1.StatefulSet) v1alpha1.ComputeNodeCondition {
+	var replicas int32 = 1
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	quorum := GovernanceQuorum(replicas)
+	msg := fmt.Sprintf("%d of %d members are ready, the quorum is %d", sts.Status.ReadyReplicas, replicas, quorum)
+
+	if sts.Status.ReadyReplicas < quorum {
+		cond := newCondition(v1alpha1.ComputeNodeConditionGovernanceReady, "WaitingForQuorum", msg)
+		cond.Status = v1alpha1.ConditionStatusFalse
+		return cond
+	}
+	return newCondition(v1alpha1.ComputeNodeConditionGovernanceReady, "QuorumReached", msg)
+}
+
 func getPreferedConditionFromPod(pod *corev1.Pod) []v1alpha1.ComputeNodeCondition {
 	computenodeConditions := []v1alpha1.ComputeNodeCondition{}
 	if pod.Status.Phase == corev1.PodUnknown {
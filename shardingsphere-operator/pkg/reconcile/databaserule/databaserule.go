@@ -0,0 +1,496 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package databaserule
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/distsql/ast"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+)
+
+// Statement contains the DistSQLs to manage a single rule
+type Statement struct {
+	Type   string
+	Name   string
+	Create string
+	Alter  string
+	Drop   string
+	// Rows are the expected rows of `SHOW xxx RULES` of the rule, keyed by the lower case column names.
+	// The empty values are not expected.
+	Rows []map[string]string
+}
+
+// Hash returns the hash of the rule definition, which is used to find out the changed rules
+func (s *Statement) Hash() string {
+	sum := sha256.Sum256([]byte(s.Create))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Matches returns whether the rows shown by the logic database have the expected values of the rule,
+// the columns unknown to the proxy are ignored and no rows means the rule definition is unknown.
+func (s *Statement) Matches(shown []map[string]string) bool {
+	if len(shown) == 0 {
+		return true
+	}
+	if len(shown) != len(s.Rows) {
+		return false
+	}
+	for _, exp := range s.Rows {
+		found := false
+		for _, row := range shown {
+			if rowMatches(exp, row) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func rowMatches(exp, row map[string]string) bool {
+	for col, v := range exp {
+		shown, ok := row[col]
+		if !ok {
+			continue
+		}
+		if normalizeValue(col, shown) != normalizeValue(col, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeValue returns the value in lower case with the list items sorted, the properties are shown
+// either as JSON or as key=value pairs
+func normalizeValue(col, v string) string {
+	v = strings.TrimSpace(v)
+	if strings.HasSuffix(col, "_props") {
+		props := map[string]any{}
+		if err := json.Unmarshal([]byte(v), &props); err == nil {
+			pairs := make([]string, 0, len(props))
+			for k, p := range props {
+				pairs = append(pairs, fmt.Sprintf("%s=%v", k, p))
+			}
+			v = strings.Join(pairs, ",")
+		} else {
+			v = strings.TrimSuffix(strings.TrimPrefix(v, "{"), "}")
+		}
+	}
+	items := strings.Split(strings.ToLower(v), ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// row returns the expected row without the empty values
+func row(kv ...string) map[string]string {
+	r := map[string]string{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] != "" {
+			r[kv[i]] = kv[i+1]
+		}
+	}
+	return r
+}
+
+// algorithmValues returns the type and the properties of the algorithm as shown
+func algorithmValues(algorithm *v1alpha1.AlgorithmSpec) (string, string) {
+	if algorithm == nil {
+		return "", ""
+	}
+	pairs := make([]string, 0, len(algorithm.Props))
+	for k, v := range algorithm.Props {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	return algorithm.Type, strings.Join(pairs, ",")
+}
+
+// Build returns the statements of all the rules in the DatabaseRule, sorted by shardingsphere.RuleTypes,
+// e.g. a readwrite-splitting rule is created before the sharding table rules using it as a storage unit.
+func Build(dr *v1alpha1.DatabaseRule) []*Statement {
+	stmts := []*Statement{}
+
+	for i := range dr.Spec.ReadwriteSplitting {
+		stmts = append(stmts, BuildReadwriteSplitting(&dr.Spec.ReadwriteSplitting[i]))
+	}
+	for i := range dr.Spec.Shadow {
+		stmts = append(stmts, buildShadow(&dr.Spec.Shadow[i]))
+	}
+	for i := range dr.Spec.Sharding {
+		stmts = append(stmts, buildSharding(&dr.Spec.Sharding[i]))
+	}
+	for i := range dr.Spec.Encrypt {
+		stmts = append(stmts, buildEncrypt(&dr.Spec.Encrypt[i]))
+	}
+	for i := range dr.Spec.Mask {
+		stmts = append(stmts, buildMask(&dr.Spec.Mask[i]))
+	}
+
+	return stmts
+}
+
+// DropStatement returns the DistSQL to drop the rule with the given type and name
+func DropStatement(ruleType, name string) string {
+	rule := []*ast.CommonIdentifier{identifier(name)}
+	switch ruleType {
+	case shardingsphere.RuleTypeSharding:
+		return (&ast.DropShardingTableRule{AllTableName: rule}).ToString()
+	case shardingsphere.RuleTypeEncrypt:
+		return (&ast.DropEncryptRule{AllTableName: rule}).ToString()
+	case shardingsphere.RuleTypeMask:
+		return (&ast.DropMaskRule{AllRuleName: rule}).ToString()
+	case shardingsphere.RuleTypeShadow:
+		return (&ast.DropShadowRule{AllRuleName: rule}).ToString()
+	case shardingsphere.RuleTypeReadwriteSplitting:
+		return (&ast.DropReadwriteSplittingRule{AllRuleName: rule}).ToString()
+	}
+	return ""
+}
+
+// SortForDrop sorts the rules with the reverse order of shardingsphere.RuleTypes
+func SortForDrop(rules []v1alpha1.AppliedRule) {
+	order := map[string]int{}
+	for i, t := range shardingsphere.RuleTypes {
+		order[t] = i
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return order[rules[i].Type] > order[rules[j].Type]
+	})
+}
+
+func buildSharding(rule *v1alpha1.ShardingTableRuleSpec) *Statement {
+	def := &ast.ShardingTableRuleDefinition{}
+
+	if len(rule.StorageUnits) > 0 {
+		auto := &ast.ShardingAutoTableRule{
+			TableName:    identifier(rule.Table),
+			StorageUnits: &ast.StorageUnits{},
+		}
+		for _, su := range rule.StorageUnits {
+			auto.StorageUnits.AllStorageUnit = append(auto.StorageUnits.AllStorageUnit, &ast.StorageUnit{Identifier: su})
+		}
+		if rule.ShardingColumn != "" {
+			auto.AutoShardingColumnDefinition = &ast.AutoShardingColumnDefinition{
+				ShardingColumn: &ast.ShardingColumn{ColumnName: identifier(rule.ShardingColumn)},
+			}
+		}
+		if rule.ShardingAlgorithm != nil {
+			auto.AlgorithmDefinition = shardingAlgorithm(rule.ShardingAlgorithm)
+		}
+		auto.KeyGenerateDefinition = keyGenerateStrategy(rule.KeyGenerateStrategy)
+		def.ShardingAutoTableRule = auto
+	} else {
+		table := &ast.ShardingTableRule{
+			TableName: identifier(rule.Table),
+		}
+		if len(rule.DataNodes) > 0 {
+			table.DataNodes = &ast.DataNodes{}
+			for _, dn := range rule.DataNodes {
+				table.DataNodes.AllDataNode = append(table.DataNodes.AllDataNode, identifier(quote(dn)))
+			}
+		}
+		if rule.DatabaseStrategy != nil {
+			table.DatabaseStrategy = &ast.DatabaseStrategy{ShardingStrategy: shardingStrategy(rule.DatabaseStrategy)}
+		}
+		if rule.TableStrategy != nil {
+			table.TableStrategy = &ast.TableStrategy{ShardingStrategy: shardingStrategy(rule.TableStrategy)}
+		}
+		table.KeyGenerateDefinition = keyGenerateStrategy(rule.KeyGenerateStrategy)
+		def.ShardingTableRule = table
+	}
+
+	defs := []*ast.ShardingTableRuleDefinition{def}
+	return &Statement{
+		Type:   shardingsphere.RuleTypeSharding,
+		Name:   rule.Table,
+		Create: (&ast.CreateShardingTableRule{AllShardingTableRuleDefinition: defs}).ToString(),
+		Alter:  (&ast.AlterShardingTableRule{AllShardingTableRuleDefinition: defs}).ToString(),
+		Drop:   DropStatement(shardingsphere.RuleTypeSharding, rule.Table),
+		Rows:   []map[string]string{shardingRow(rule)},
+	}
+}
+
+func shardingRow(rule *v1alpha1.ShardingTableRuleSpec) map[string]string {
+	r := row("table", rule.Table,
+		"actual_data_sources", strings.Join(rule.StorageUnits, ","),
+		"actual_data_nodes", strings.Join(rule.DataNodes, ","))
+	if len(rule.StorageUnits) > 0 {
+		algType, algProps := algorithmValues(rule.ShardingAlgorithm)
+		for k, v := range row("table_sharding_column", rule.ShardingColumn, "table_sharding_algorithm_type", algType, "table_sharding_algorithm_props", algProps) {
+			r[k] = v
+		}
+	}
+	for prefix, strategy := range map[string]*v1alpha1.ShardingStrategySpec{"database": rule.DatabaseStrategy, "table": rule.TableStrategy} {
+		if strategy == nil {
+			continue
+		}
+		algType, algProps := algorithmValues(strategy.ShardingAlgorithm)
+		for k, v := range row(prefix+"_strategy_type", strategy.Type,
+			prefix+"_sharding_column", strings.Join(strategy.ShardingColumns, ","),
+			prefix+"_sharding_algorithm_type", algType,
+			prefix+"_sharding_algorithm_props", algProps) {
+			r[k] = v
+		}
+	}
+	if rule.KeyGenerateStrategy != nil {
+		algType, algProps := algorithmValues(&rule.KeyGenerateStrategy.KeyGenerator)
+		for k, v := range row("key_generate_column", rule.KeyGenerateStrategy.Column, "key_generator_type", algType, "key_generator_props", algProps) {
+			r[k] = v
+		}
+	}
+	return r
+}
+
+func shardingStrategy(strategy *v1alpha1.ShardingStrategySpec) *ast.ShardingStrategy {
+	stmt := &ast.ShardingStrategy{
+		StrategyType: &ast.StrategyType{String: quote(strategy.Type)},
+	}
+
+	switch len(strategy.ShardingColumns) {
+	case 0:
+	case 1:
+		stmt.ShardingColumnDefinition = &ast.ShardingColumnDefinition{
+			ShardingColumn: &ast.ShardingColumn{ColumnName: identifier(strategy.ShardingColumns[0])},
+		}
+	default:
+		columns := &ast.ShardingColumns{}
+		for _, c := range strategy.ShardingColumns {
+			columns.AllColumnName = append(columns.AllColumnName, identifier(c))
+		}
+		stmt.ShardingColumnDefinition = &ast.ShardingColumnDefinition{ShardingColumns: columns}
+	}
+
+	if strategy.ShardingAlgorithm != nil {
+		stmt.ShardingAlgorithm = &ast.ShardingAlgorithm{AlgorithmDefinition: shardingAlgorithm(strategy.ShardingAlgorithm)}
+	}
+	return stmt
+}
+
+func keyGenerateStrategy(strategy *v1alpha1.KeyGenerateStrategySpec) *ast.KeyGenerateDefinition {
+	if strategy == nil {
+		return nil
+	}
+	return &ast.KeyGenerateDefinition{
+		ColumnName:          identifier(strategy.Column),
+		AlgorithmDefinition: shardingAlgorithm(&strategy.KeyGenerator),
+	}
+}
+
+func shardingAlgorithm(algorithm *v1alpha1.AlgorithmSpec) *ast.ShardingAlgorithmDefinition {
+	return &ast.ShardingAlgorithmDefinition{
+		ShardingAlgorithmTypeName: &ast.ShardingAlgorithmTypeName{String: quote(algorithm.Type)},
+		PropertiesDefinition:      properties(algorithm.Props),
+	}
+}
+
+func buildEncrypt(rule *v1alpha1.EncryptRuleSpec) *Statement {
+	def := &ast.EncryptRuleDefinition{
+		TableName: identifier(rule.Table),
+	}
+	rows := make([]map[string]string, 0, len(rule.Columns))
+
+	for i := range rule.Columns {
+		column := &rule.Columns[i]
+		cd := &ast.EncryptColumnDefinition{
+			ColumnDefinition:       &ast.ColumnDefinition{ColumnName: identifier(column.Name)},
+			CipherColumnDefinition: &ast.CipherColumnDefinition{CipherColumnName: identifier(column.Cipher)},
+			EncryptAlgorithm:       &ast.EncryptAlgorithm{AlgorithmDefinition: algorithm(&column.EncryptAlgorithm)},
+		}
+		if column.Plain != "" {
+			cd.PlainColumnDefinition = &ast.PlainColumnDefinition{PlainColumnName: identifier(column.Plain)}
+		}
+		if column.AssistedQuery != "" {
+			cd.AssistedQueryColumnDefinition = &ast.AssistedQueryColumnDefinition{AssistedQueryColumnName: identifier(column.AssistedQuery)}
+		}
+		if column.LikeQuery != "" {
+			cd.LikeQueryColumnDefinition = &ast.LikeQueryColumnDefinition{LikeQueryColumnName: identifier(column.LikeQuery)}
+		}
+		if column.AssistedQueryAlgorithm != nil {
+			cd.AssistedQueryAlgorithm = &ast.AssistedQueryAlgorithm{AlgorithmDefinition: algorithm(column.AssistedQueryAlgorithm)}
+		}
+		if column.LikeQueryAlgorithm != nil {
+			cd.LikeQueryAlgorithm = &ast.LikeQueryAlgorithm{AlgorithmDefinition: algorithm(column.LikeQueryAlgorithm)}
+		}
+		def.AllEncryptColumnDefinition = append(def.AllEncryptColumnDefinition, cd)
+
+		encType, encProps := algorithmValues(&column.EncryptAlgorithm)
+		assistedType, assistedProps := algorithmValues(column.AssistedQueryAlgorithm)
+		likeType, likeProps := algorithmValues(column.LikeQueryAlgorithm)
+		rows = append(rows, row("table", rule.Table, "logic_column", column.Name, "cipher_column", column.Cipher,
+			"plain_column", column.Plain, "assisted_query_column", column.AssistedQuery, "like_query_column", column.LikeQuery,
+			"encryptor_type", encType, "encryptor_props", encProps,
+			"assisted_query_type", assistedType, "assisted_query_props", assistedProps,
+			"like_query_type", likeType, "like_query_props", likeProps))
+	}
+
+	if rule.QueryWithCipherColumn != nil {
+		def.QueryWithCipherColumn = &ast.QueryWithCipherColumn{QueryWithCipherColumn: fmt.Sprintf("%t", *rule.QueryWithCipherColumn)}
+	}
+
+	defs := []*ast.EncryptRuleDefinition{def}
+	return &Statement{
+		Type:   shardingsphere.RuleTypeEncrypt,
+		Name:   rule.Table,
+		Create: (&ast.CreateEncryptRule{AllEncryptRuleDefinition: defs}).ToString(),
+		Alter:  (&ast.AlterEncryptRule{AllEncryptRuleDefinitionList: defs}).ToString(),
+		Drop:   DropStatement(shardingsphere.RuleTypeEncrypt, rule.Table),
+		Rows:   rows,
+	}
+}
+
+func buildMask(rule *v1alpha1.MaskRuleSpec) *Statement {
+	def := &ast.MaskRuleDefinition{
+		RuleName: identifier(rule.Table),
+	}
+	rows := make([]map[string]string, 0, len(rule.Columns))
+	for i := range rule.Columns {
+		def.ColumnDefinition = append(def.ColumnDefinition, &ast.MaskColumnDefinition{
+			ColumnName:          identifier(rule.Columns[i].Name),
+			AlgorithmDefinition: algorithm(&rule.Columns[i].Algorithm),
+		})
+		algType, algProps := algorithmValues(&rule.Columns[i].Algorithm)
+		rows = append(rows, row("table", rule.Table, "column", rule.Columns[i].Name, "algorithm_type", algType, "algorithm_props", algProps))
+	}
+
+	defs := []*ast.MaskRuleDefinition{def}
+	return &Statement{
+		Type:   shardingsphere.RuleTypeMask,
+		Name:   rule.Table,
+		Create: (&ast.CreateMaskRule{AllMaskRuleDefinition: defs}).ToString(),
+		Alter:  (&ast.AlterMaskRule{AllMaskRuleDefinition: defs}).ToString(),
+		Drop:   DropStatement(shardingsphere.RuleTypeMask, rule.Table),
+		Rows:   rows,
+	}
+}
+
+func buildShadow(rule *v1alpha1.ShadowRuleSpec) *Statement {
+	def := &ast.ShadowRuleDefinition{
+		RuleName: identifier(rule.Name),
+		Source:   identifier(rule.Source),
+		Shadow:   identifier(rule.Shadow),
+	}
+	tables := make([]string, 0, len(rule.Tables))
+	for i := range rule.Tables {
+		tables = append(tables, rule.Tables[i].Name)
+		table := &ast.ShadowTableRule{TableName: identifier(rule.Tables[i].Name)}
+		for j := range rule.Tables[i].Algorithms {
+			table.AllAlgorithmDefinition = append(table.AllAlgorithmDefinition, algorithm(&rule.Tables[i].Algorithms[j]))
+		}
+		def.AllShadowTableRule = append(def.AllShadowTableRule, table)
+	}
+
+	defs := []*ast.ShadowRuleDefinition{def}
+	return &Statement{
+		Type:   shardingsphere.RuleTypeShadow,
+		Name:   rule.Name,
+		Create: (&ast.CreateShadowRule{AllShadowRuleDefinition: defs}).ToString(),
+		Alter:  (&ast.AlterShadowRule{AllShadowRuleDefinition: defs}).ToString(),
+		Drop:   DropStatement(shardingsphere.RuleTypeShadow, rule.Name),
+		Rows: []map[string]string{row("rule_name", rule.Name, "source_name", rule.Source, "shadow_name", rule.Shadow,
+			"shadow_table", strings.Join(tables, ","))},
+	}
+}
+
+// BuildReadwriteSplitting returns the statement of a readwrite-splitting rule.
+func BuildReadwriteSplitting(rule *v1alpha1.ReadwriteSplittingRuleSpec) *Statement {
+	reads := &ast.ReadStorageUnitsNames{}
+	for _, r := range rule.ReadStorageUnits {
+		reads.AllStorageUnitName = append(reads.AllStorageUnitName, identifier(r))
+	}
+
+	def := &ast.ReadWriteSplittingRuleDefinition{
+		RuleName: identifier(rule.Name),
+		DataSourceDefinition: &ast.DataSourceDefinition{
+			WriteStorageUnit: &ast.WriteStorageUnit{
+				WriteStorageUnitName: &ast.WriteStorageUnitName{StorageUnitName: identifier(rule.WriteStorageUnit)},
+			},
+			ReadStorageUnits: &ast.ReadStorageUnits{ReadStorageUnitsNames: reads},
+		},
+	}
+	if rule.TransactionalReadQueryStrategy != "" {
+		def.TransactionalReadQueryStrategy = &ast.TransactionalReadQueryStrategy{
+			TransactionalReadQueryStrategyName: &ast.TransactionalReadQueryStrategyName{String: quote(rule.TransactionalReadQueryStrategy)},
+		}
+	}
+	if rule.LoadBalancer != nil {
+		def.AlgorithmDefinition = algorithm(rule.LoadBalancer)
+	}
+
+	lbType, lbProps := algorithmValues(rule.LoadBalancer)
+	defs := []*ast.ReadWriteSplittingRuleDefinition{def}
+	return &Statement{
+		Type:   shardingsphere.RuleTypeReadwriteSplitting,
+		Name:   rule.Name,
+		Create: (&ast.CreateReadwriteSplittingRule{AllReadwriteSplittingRuleDefinition: defs}).ToString(),
+		Alter:  (&ast.AlterReadwriteSplittingRule{AllReadwriteSplittingRuleDefinition: defs}).ToString(),
+		Drop:   DropStatement(shardingsphere.RuleTypeReadwriteSplitting, rule.Name),
+		Rows: []map[string]string{row("name", rule.Name, "write_storage_unit_name", rule.WriteStorageUnit,
+			"read_storage_unit_names", strings.Join(rule.ReadStorageUnits, ","),
+			"transactional_read_query_strategy", rule.TransactionalReadQueryStrategy,
+			"load_balancer_type", lbType, "load_balancer_props", lbProps)},
+	}
+}
+
+func algorithm(algorithm *v1alpha1.AlgorithmSpec) *ast.AlgorithmDefinition {
+	return &ast.AlgorithmDefinition{
+		AlgorithmTypeName:    &ast.AlgorithmTypeName{String: quote(algorithm.Type)},
+		PropertiesDefinition: properties(algorithm.Props),
+	}
+}
+
+// properties are sorted by key to keep the statements stable between reconciliations
+func properties(props map[string]string) *ast.PropertiesDefinition {
+	if len(props) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	stmt := &ast.Properties{}
+	for _, k := range keys {
+		stmt.Properties = append(stmt.Properties, &ast.Property{
+			Key:     quote(k),
+			Literal: &ast.Literal{Literal: quote(props[k])},
+		})
+	}
+	return &ast.PropertiesDefinition{Properties: stmt}
+}
+
+func identifier(name string) *ast.CommonIdentifier {
+	return &ast.CommonIdentifier{Identifier: name}
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+}
@@ -0,0 +1,305 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package databaserule_test
+
+import (
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	encrypt "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/distsql/visitor_parser/encrypt"
+	mask "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/distsql/visitor_parser/mask"
+	rws "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/distsql/visitor_parser/read_write_splitting"
+	shadow "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/distsql/visitor_parser/shadow"
+	sharding "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/distsql/visitor_parser/sharding"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/databaserule"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type syntaxErrors struct {
+	*antlr.DefaultErrorListener
+	errors []string
+}
+
+func (l *syntaxErrors) SyntaxError(_ antlr.Recognizer, _ interface{}, _, _ int, msg string, _ antlr.RecognitionException) {
+	l.errors = append(l.errors, msg)
+}
+
+func tokens(distSQL string, newLexer func(antlr.CharStream) antlr.Lexer) *antlr.CommonTokenStream {
+	return antlr.NewCommonTokenStream(newLexer(antlr.NewInputStream(distSQL)), antlr.TokenDefaultChannel)
+}
+
+// parse parses the statement with the parser of the rule type, and returns the syntax errors
+func parse(ruleType, distSQL string, parseFunc string) []string {
+	listener := &syntaxErrors{DefaultErrorListener: antlr.NewDefaultErrorListener()}
+
+	switch ruleType {
+	case shardingsphere.RuleTypeSharding:
+		p := sharding.NewRDLStatementParser(tokens(distSQL, func(s antlr.CharStream) antlr.Lexer { return sharding.NewRDLStatementLexer(s) }))
+		p.RemoveErrorListeners()
+		p.AddErrorListener(listener)
+		map[string]func(){
+			"create": func() { p.CreateShardingTableRule() },
+			"alter":  func() { p.AlterShardingTableRule() },
+			"drop":   func() { p.DropShardingTableRule() },
+		}[parseFunc]()
+	case shardingsphere.RuleTypeEncrypt:
+		p := encrypt.NewRDLStatementParser(tokens(distSQL, func(s antlr.CharStream) antlr.Lexer { return encrypt.NewRDLStatementLexer(s) }))
+		p.RemoveErrorListeners()
+		p.AddErrorListener(listener)
+		map[string]func(){
+			"create": func() { p.CreateEncryptRule() },
+			"alter":  func() { p.AlterEncryptRule() },
+			"drop":   func() { p.DropEncryptRule() },
+		}[parseFunc]()
+	case shardingsphere.RuleTypeMask:
+		p := mask.NewRDLStatementParser(tokens(distSQL, func(s antlr.CharStream) antlr.Lexer { return mask.NewRDLStatementLexer(s) }))
+		p.RemoveErrorListeners()
+		p.AddErrorListener(listener)
+		map[string]func(){
+			"create": func() { p.CreateMaskRule() },
+			"alter":  func() { p.AlterMaskRule() },
+			"drop":   func() { p.DropMaskRule() },
+		}[parseFunc]()
+	case shardingsphere.RuleTypeShadow:
+		p := shadow.NewRDLStatementParser(tokens(distSQL, func(s antlr.CharStream) antlr.Lexer { return shadow.NewRDLStatementLexer(s) }))
+		p.RemoveErrorListeners()
+		p.AddErrorListener(listener)
+		map[string]func(){
+			"create": func() { p.CreateShadowRule() },
+			"alter":  func() { p.AlterShadowRule() },
+			"drop":   func() { p.DropShadowRule() },
+		}[parseFunc]()
+	case shardingsphere.RuleTypeReadwriteSplitting:
+		p := rws.NewRDLStatementParser(tokens(distSQL, func(s antlr.CharStream) antlr.Lexer { return rws.NewRDLStatementLexer(s) }))
+		p.RemoveErrorListeners()
+		p.AddErrorListener(listener)
+		map[string]func(){
+			"create": func() { p.CreateReadwriteSplittingRule() },
+			"alter":  func() { p.AlterReadwriteSplittingRule() },
+			"drop":   func() { p.DropReadwriteSplittingRule() },
+		}[parseFunc]()
+	}
+
+	return listener.errors
+}
+
+var _ = Describe("Build", func() {
+	queryWithCipherColumn := true
+	dr := &v1alpha1.DatabaseRule{
+		Spec: v1alpha1.DatabaseRuleSpec{
+			ComputeNodeName: "shardingsphere",
+			DatabaseName:    "sharding_db",
+			Mask: []v1alpha1.MaskRuleSpec{
+				{
+					Table: "t_user",
+					Columns: []v1alpha1.MaskColumnSpec{
+						{
+							Name: "phone",
+							Algorithm: v1alpha1.AlgorithmSpec{
+								Type:  "MASK_FROM_X_TO_Y",
+								Props: map[string]string{"to-y": "6", "from-x": "3", "replace-char": "*"},
+							},
+						},
+					},
+				},
+			},
+			Encrypt: []v1alpha1.EncryptRuleSpec{
+				{
+					Table:                 "t_user",
+					QueryWithCipherColumn: &queryWithCipherColumn,
+					Columns: []v1alpha1.EncryptColumnSpec{
+						{
+							Name:          "password",
+							Cipher:        "password_cipher",
+							Plain:         "password_plain",
+							AssistedQuery: "password_assisted",
+							LikeQuery:     "password_like",
+							EncryptAlgorithm: v1alpha1.AlgorithmSpec{
+								Type:  "AES",
+								Props: map[string]string{"aes-key-value": "123456abc"},
+							},
+							AssistedQueryAlgorithm: &v1alpha1.AlgorithmSpec{Type: "MD5"},
+							LikeQueryAlgorithm:     &v1alpha1.AlgorithmSpec{Type: "CHAR_DIGEST_LIKE"},
+						},
+					},
+				},
+			},
+			Sharding: []v1alpha1.ShardingTableRuleSpec{
+				{
+					Table:          "t_order",
+					StorageUnits:   []string{"ds_0", "ds_1"},
+					ShardingColumn: "order_id",
+					ShardingAlgorithm: &v1alpha1.AlgorithmSpec{
+						Type:  "MOD",
+						Props: map[string]string{"sharding-count": "4"},
+					},
+					KeyGenerateStrategy: &v1alpha1.KeyGenerateStrategySpec{
+						Column:       "order_id",
+						KeyGenerator: v1alpha1.AlgorithmSpec{Type: "SNOWFLAKE"},
+					},
+				},
+				{
+					Table:     "t_order_item",
+					DataNodes: []string{"ds_${0..1}.t_order_item_${0..1}"},
+					DatabaseStrategy: &v1alpha1.ShardingStrategySpec{
+						Type:            "standard",
+						ShardingColumns: []string{"user_id"},
+						ShardingAlgorithm: &v1alpha1.AlgorithmSpec{
+							Type:  "INLINE",
+							Props: map[string]string{"algorithm-expression": "ds_${user_id % 2}"},
+						},
+					},
+					TableStrategy: &v1alpha1.ShardingStrategySpec{
+						Type:            "complex",
+						ShardingColumns: []string{"order_id", "user_id"},
+						ShardingAlgorithm: &v1alpha1.AlgorithmSpec{
+							Type:  "COMPLEX_INLINE",
+							Props: map[string]string{"algorithm-expression": "t_order_item_${(order_id + user_id) % 2}"},
+						},
+					},
+				},
+			},
+			Shadow: []v1alpha1.ShadowRuleSpec{
+				{
+					Name:   "shadow_rule",
+					Source: "ds_0",
+					Shadow: "ds_shadow",
+					Tables: []v1alpha1.ShadowTableSpec{
+						{
+							Name: "t_order",
+							Algorithms: []v1alpha1.AlgorithmSpec{
+								{
+									Type:  "VALUE_MATCH",
+									Props: map[string]string{"operation": "insert", "column": "user_id", "value": "1"},
+								},
+							},
+						},
+					},
+				},
+			},
+			ReadwriteSplitting: []v1alpha1.ReadwriteSplittingRuleSpec{
+				{
+					Name:                           "ds_0",
+					WriteStorageUnit:               "write_ds",
+					ReadStorageUnits:               []string{"read_ds_0", "read_ds_1"},
+					TransactionalReadQueryStrategy: "PRIMARY",
+					LoadBalancer:                   &v1alpha1.AlgorithmSpec{Type: "random"},
+				},
+			},
+		},
+	}
+
+	stmts := databaserule.Build(dr)
+
+	It("should build statements in dependency order", func() {
+		types := []string{}
+		for _, s := range stmts {
+			types = append(types, s.Type)
+		}
+		Expect(types).To(Equal([]string{
+			shardingsphere.RuleTypeReadwriteSplitting,
+			shardingsphere.RuleTypeShadow,
+			shardingsphere.RuleTypeSharding,
+			shardingsphere.RuleTypeSharding,
+			shardingsphere.RuleTypeEncrypt,
+			shardingsphere.RuleTypeMask,
+		}))
+		Expect(stmts[2].Name).To(Equal("t_order"))
+		Expect(stmts[3].Name).To(Equal("t_order_item"))
+	})
+
+	It("should render DistSQL with quoted algorithms and sorted properties", func() {
+		Expect(stmts[2].Create).To(ContainSubstring("STORAGE_UNITS (ds_0,ds_1),SHARDING_COLUMN = order_id,TYPE ( NAME = 'MOD' ,PROPERTIES('sharding-count'='4'))"))
+		Expect(stmts[3].Create).To(ContainSubstring("DATANODES('ds_${0..1}.t_order_item_${0..1}')"))
+		Expect(stmts[3].Create).To(ContainSubstring("SHARDING_COLUMNS = order_id,user_id"))
+		Expect(stmts[5].Create).To(Equal("CREATE MASK RULE t_user (COLUMNS((NAME=phone,TYPE(NAME='MASK_FROM_X_TO_Y',PROPERTIES('from-x'='3','replace-char'='*','to-y'='6')))))"))
+		Expect(stmts[5].Drop).To(Equal("DROP MASK RULE t_user"))
+	})
+
+	It("should render DistSQL accepted by the DistSQL parser", func() {
+		for _, s := range stmts {
+			Expect(parse(s.Type, s.Create, "create")).To(BeEmpty(), s.Create)
+			Expect(parse(s.Type, s.Alter, "alter")).To(BeEmpty(), s.Alter)
+			Expect(parse(s.Type, s.Drop, "drop")).To(BeEmpty(), s.Drop)
+		}
+	})
+
+	It("should keep the hash stable and change it with the definition", func() {
+		again := databaserule.Build(dr)
+		for i := range stmts {
+			Expect(again[i].Hash()).To(Equal(stmts[i].Hash()))
+		}
+
+		changed := dr.DeepCopy()
+		changed.Spec.Sharding[0].ShardingAlgorithm.Props["sharding-count"] = "8"
+		Expect(databaserule.Build(changed)[2].Hash()).NotTo(Equal(stmts[2].Hash()))
+	})
+})
+
+var _ = Describe("Matches", func() {
+	mask := &v1alpha1.MaskRuleSpec{
+		Table: "t_user",
+		Columns: []v1alpha1.MaskColumnSpec{
+			{Name: "phone", Algorithm: v1alpha1.AlgorithmSpec{Type: "MASK_FIRST_N_LAST_M", Props: map[string]string{"first-n": "3", "last-m": "4"}}},
+		},
+	}
+	stmt := databaserule.Build(&v1alpha1.DatabaseRule{Spec: v1alpha1.DatabaseRuleSpec{Mask: []v1alpha1.MaskRuleSpec{*mask}}})[0]
+
+	It("should match the rows shown in either props format", func() {
+		Expect(stmt.Matches(nil)).To(BeTrue())
+		Expect(stmt.Matches([]map[string]string{
+			{"table": "t_user", "column": "phone", "algorithm_type": "mask_first_n_last_m", "algorithm_props": `{"last-m":"4","first-n":"3"}`},
+		})).To(BeTrue())
+		Expect(stmt.Matches([]map[string]string{
+			{"table": "t_user", "column": "phone", "algorithm_type": "MASK_FIRST_N_LAST_M", "algorithm_props": "first-n=3,last-m=4", "unknown": "x"},
+		})).To(BeTrue())
+	})
+
+	It("should not match the rows altered outside", func() {
+		Expect(stmt.Matches([]map[string]string{
+			{"table": "t_user", "column": "phone", "algorithm_type": "MD5", "algorithm_props": ""},
+		})).To(BeFalse())
+		Expect(stmt.Matches([]map[string]string{
+			{"table": "t_user", "column": "phone", "algorithm_type": "MASK_FIRST_N_LAST_M", "algorithm_props": "first-n=1,last-m=4"},
+		})).To(BeFalse())
+		Expect(stmt.Matches([]map[string]string{
+			{"table": "t_user", "column": "phone", "algorithm_type": "MASK_FIRST_N_LAST_M", "algorithm_props": "first-n=3,last-m=4"},
+			{"table": "t_user", "column": "email", "algorithm_type": "MD5"},
+		})).To(BeFalse())
+	})
+})
+
+var _ = Describe("SortForDrop", func() {
+	It("should drop rules in the reverse dependency order", func() {
+		rules := []v1alpha1.AppliedRule{
+			{Type: shardingsphere.RuleTypeReadwriteSplitting, Name: "rw"},
+			{Type: shardingsphere.RuleTypeMask, Name: "t_user"},
+			{Type: shardingsphere.RuleTypeSharding, Name: "t_order"},
+			{Type: shardingsphere.RuleTypeEncrypt, Name: "t_user"},
+		}
+		databaserule.SortForDrop(rules)
+		Expect(rules).To(Equal([]v1alpha1.AppliedRule{
+			{Type: shardingsphere.RuleTypeMask, Name: "t_user"},
+			{Type: shardingsphere.RuleTypeEncrypt, Name: "t_user"},
+			{Type: shardingsphere.RuleTypeSharding, Name: "t_order"},
+			{Type: shardingsphere.RuleTypeReadwriteSplitting, Name: "rw"},
+		}))
+	})
+})
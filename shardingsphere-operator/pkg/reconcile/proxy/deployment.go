@@ -41,6 +41,9 @@ const (
 	AnnoRollingUpdateMaxSurge = "shardingsphereproxy.shardingsphere.org/rolling-update-max-surge"
 	// AnnoRollingUpdateMaxUnavailable refers to Deployment RollingUpdate Strategy
 	AnnoRollingUpdateMaxUnavailable = "shardingsphereproxy.shardingsphere.org/rolling-update-max-unavailable"
+	// AnnoConfigHash refers to the content hash of the cascaded Configmap and Secret in the pod template,
+	// the pods are rolled once it changes, e.g. a password is rotated
+	AnnoConfigHash = "shardingsphereproxy.shardingsphere.org/config-hash"
 
 	// miniReadyCount Minimum number of replicas that can be served
 	miniReadyCount = 1
@@ -129,16 +132,7 @@ func ConstructCascadingDeployment(proxy *v1alpha1.ShardingSphereProxy) *v1.Deplo
 						},
 					},
 					Volumes: []corev1.Volume{
-						{
-							Name: "config",
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: proxy.Spec.ProxyConfigName,
-									},
-								},
-							},
-						},
+						configVolume(proxy.Spec.ProxyConfigName),
 					},
 				},
 			},
@@ -279,17 +273,41 @@ func updatePodTemplateSpec(proxy *v1alpha1.ShardingSphereProxy, act *v1.Deployme
 		}
 	}
 
-	configName := updateConfigName(proxy, act)
-	exp.Spec.Volumes[0].ConfigMap.Name = configName
+	exp.Spec.Volumes[0] = configVolume(proxy.Spec.ProxyConfigName)
 
 	return *exp
 }
 
-func updateConfigName(proxy *v1alpha1.ShardingSphereProxy, act *v1.Deployment) string {
-	if act.Spec.Template.Spec.Volumes[0].ConfigMap.Name != proxy.Spec.ProxyConfigName {
-		return proxy.Spec.ProxyConfigName
+// configVolume projects logback.xml from the Configmap and server.yaml carrying the passwords from the Secret,
+// both are named after the ShardingSphereProxyServerConfig
+func configVolume(name string) corev1.Volume {
+	return corev1.Volume{
+		Name: "config",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: name},
+						},
+					},
+					{
+						Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: name},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// SetConfigHash stamps the content hash of the cascaded Configmap and Secret into the pod template
+func SetConfigHash(dp *v1.Deployment, hash string) {
+	if dp.Spec.Template.Annotations == nil {
+		dp.Spec.Template.Annotations = map[string]string{}
 	}
-	return act.Spec.Template.Spec.Volumes[0].ConfigMap.Name
+	dp.Spec.Template.Annotations[AnnoConfigHash] = hash
 }
 
 func updateInitContainer(proxy *v1alpha1.ShardingSphereProxy, act *v1.Deployment) *corev1.Container {
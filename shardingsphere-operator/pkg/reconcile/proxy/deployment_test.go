@@ -180,9 +180,22 @@ func Test_ConstructCascadingDeployment(t *testing.T) {
 								{
 									Name: "config",
 									VolumeSource: v1.VolumeSource{
-										ConfigMap: &v1.ConfigMapVolumeSource{
-											LocalObjectReference: v1.LocalObjectReference{
-												Name: "shardingsphere-proxy-config",
+										Projected: &v1.ProjectedVolumeSource{
+											Sources: []v1.VolumeProjection{
+												{
+													ConfigMap: &v1.ConfigMapProjection{
+														LocalObjectReference: v1.LocalObjectReference{
+															Name: "shardingsphere-proxy-config",
+														},
+													},
+												},
+												{
+													Secret: &v1.SecretProjection{
+														LocalObjectReference: v1.LocalObjectReference{
+															Name: "shardingsphere-proxy-config",
+														},
+													},
+												},
 											},
 										},
 									},
@@ -434,7 +447,8 @@ func Test_UpdateDeployment(t *testing.T) {
 		exp := UpdateDeployment(c.proxy, c.deploy)
 		assert.Equal(t, fmt.Sprintf("%s:%s", imageName, c.proxy.Spec.Version), exp.Spec.Template.Spec.Containers[0].Image, c.message)
 		assert.Equal(t, c.proxy.Spec.Replicas, *exp.Spec.Replicas, c.message)
-		assert.Equal(t, c.proxy.Spec.ProxyConfigName, exp.Spec.Template.Spec.Volumes[0].ConfigMap.Name, c.message)
+		assert.Equal(t, c.proxy.Spec.ProxyConfigName, exp.Spec.Template.Spec.Volumes[0].Projected.Sources[0].ConfigMap.Name, c.message)
+		assert.Equal(t, c.proxy.Spec.ProxyConfigName, exp.Spec.Template.Spec.Volumes[0].Projected.Sources[1].Secret.Name, c.message)
 		assert.Equal(t, c.proxy.Spec.Port, exp.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort, c.message)
 		assert.EqualValues(t, c.proxy.Spec.Resources, exp.Spec.Template.Spec.Containers[0].Resources, c.message)
 		assert.EqualValues(t, c.proxy.Spec.LivenessProbe, exp.Spec.Template.Spec.Containers[0].LivenessProbe, c.message)
@@ -19,7 +19,6 @@ package proxyconfig
 
 import (
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
-	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -57,9 +56,9 @@ const defaultLogback = `<?xml version="1.0"?>
 </configuration> 
 `
 
-// ConstructCascadingConfigmap Construct spec resources to Configmap
+// ConstructCascadingConfigmap Construct spec resources to Configmap, server.yaml carries the passwords
+// so it is constructed to a Secret by ConstructCascadingSecret
 func ConstructCascadingConfigmap(proxyConfig *v1alpha1.ShardingSphereProxyServerConfig) *v1.ConfigMap {
-	serveryaml := toYaml(proxyConfig)
 	return &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      proxyConfig.Name,
@@ -69,14 +68,7 @@ func ConstructCascadingConfigmap(proxyConfig *v1alpha1.ShardingSphereProxyServer
 			},
 		},
 		Data: map[string]string{
-			"server.yaml": serveryaml,
 			"logback.xml": defaultLogback,
 		},
 	}
 }
-
-// ToYaml Convert ShardingSphereProxyServerConfig spec content to yaml format
-func toYaml(proxyConfig *v1alpha1.ShardingSphereProxyServerConfig) string {
-	y, _ := yaml.Marshal(proxyConfig.Spec)
-	return string(y)
-}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -114,8 +115,9 @@ props:
 	}
 
 	for _, c := range cases {
-		act := toYaml(c.proxyCfg)
-		assert.Equal(t, c.exp, act, c.message)
+		act, err := ConstructCascadingSecret(c.proxyCfg, nil)
+		assert.NoError(t, err, c.message)
+		assert.Equal(t, c.exp, string(act.Data["server.yaml"]), c.message)
 	}
 }
 
@@ -149,11 +151,43 @@ func TestConfigMap_ConstructCascadingConfigMap(t *testing.T) {
 		},
 	}
 
-	s := toYaml(pc)
-
 	cm := ConstructCascadingConfigmap(pc)
 	assert.Equal(t, "test", cm.Name, "name should be equal")
 	assert.Equal(t, "test", cm.Namespace, "namespace should be equal")
-	assert.Equal(t, s, cm.Data["server.yaml"], "server.yaml should be equal")
+	assert.NotContains(t, cm.Data, "server.yaml", "server.yaml should be in the secret")
 	assert.Equal(t, defaultLogback, cm.Data["logback.xml"], "logback.xml should be equal")
 }
+
+func TestSecret_ConstructCascadingSecret(t *testing.T) {
+	pc := &v1alpha1.ShardingSphereProxyServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: v1alpha1.ProxyConfigSpec{
+			Authority: v1alpha1.Auth{
+				Users: []v1alpha1.User{
+					{User: "root@%"},
+					{User: "app@%", PasswordSecretRef: &v1.SecretKeySelector{
+						LocalObjectReference: v1.LocalObjectReference{Name: "app-user"},
+						Key:                  "password",
+					}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []string{"app-user", "test-authority"}, ReferencedSecretNames(pc), "referenced secrets should be equal")
+
+	authority, err := ConstructAuthoritySecret(pc, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-authority", authority.Name, "name should be equal")
+	assert.NotEmpty(t, authority.Data["root"], "password of root should be generated")
+
+	_, err = ConstructCascadingSecret(pc, []*v1.Secret{authority})
+	assert.Error(t, err, "missing referenced secret should fail")
+
+	users := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app-user"}, Data: map[string][]byte{"password": []byte("app")}}
+	secret, err := ConstructCascadingSecret(pc, []*v1.Secret{authority, users})
+	assert.NoError(t, err)
+	assert.Equal(t, "test", secret.Name, "name should be equal")
+	assert.Contains(t, string(secret.Data["server.yaml"]), "password: "+string(authority.Data["root"]), "generated password should be resolved")
+	assert.Contains(t, string(secret.Data["server.yaml"]), "password: app", "referenced password should be resolved")
+	assert.Empty(t, pc.Spec.Authority.Users[0].Password, "spec should not be changed")
+}
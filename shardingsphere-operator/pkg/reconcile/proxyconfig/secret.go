@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxyconfig
+
+import (
+	"sort"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/secret"
+
+	"gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuthoritySecretName returns the name of the Secret keeping the generated passwords, keyed by the user name
+func AuthoritySecretName(proxyConfig *v1alpha1.ShardingSphereProxyServerConfig) string {
+	return proxyConfig.Name + "-authority"
+}
+
+// ReferencedSecretNames returns the names of the Secrets referenced by the users, including the Secret of the generated passwords
+func ReferencedSecretNames(proxyConfig *v1alpha1.ShardingSphereProxyServerConfig) []string {
+	names := map[string]struct{}{}
+	for _, u := range proxyConfig.Spec.Authority.Users {
+		if u.PasswordSecretRef != nil {
+			names[u.PasswordSecretRef.Name] = struct{}{}
+		}
+	}
+	if len(generatedPasswordUsers(proxyConfig)) > 0 {
+		names[AuthoritySecretName(proxyConfig)] = struct{}{}
+	}
+
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func generatedPasswordUsers(proxyConfig *v1alpha1.ShardingSphereProxyServerConfig) []string {
+	users := []string{}
+	for _, u := range proxyConfig.Spec.Authority.Users {
+		if u.Password == "" && u.PasswordSecretRef == nil {
+			users = append(users, u.User)
+		}
+	}
+	return users
+}
+
+func secretObjectMeta(proxyConfig *v1alpha1.ShardingSphereProxyServerConfig, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: proxyConfig.Namespace,
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(proxyConfig.GetObjectMeta(), proxyConfig.GroupVersionKind()),
+		},
+	}
+}
+
+// ConstructAuthoritySecret Construct the Secret of the generated passwords, the passwords in cur are kept.
+// It returns nil if every user has a password or a reference to it.
+func ConstructAuthoritySecret(proxyConfig *v1alpha1.ShardingSphereProxyServerConfig, cur *v1.Secret) (*v1.Secret, error) {
+	users := generatedPasswordUsers(proxyConfig)
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	var data map[string][]byte
+	if cur != nil {
+		data = cur.Data
+	}
+	data, err := secret.GeneratePasswords(users, data)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Secret{
+		ObjectMeta: secretObjectMeta(proxyConfig, AuthoritySecretName(proxyConfig)),
+		Type:       v1.SecretTypeOpaque,
+		Data:       data,
+	}, nil
+}
+
+// ConstructCascadingSecret Construct server.yaml to a Secret of the same name as the Configmap,
+// the passwords of the users are resolved from the Secrets
+func ConstructCascadingSecret(proxyConfig *v1alpha1.ShardingSphereProxyServerConfig, secrets []*v1.Secret) (*v1.Secret, error) {
+	spec := proxyConfig.Spec.DeepCopy()
+	for i, u := range spec.Authority.Users {
+		password, err := secret.ResolvePassword(u.User, u.Password, u.PasswordSecretRef, AuthoritySecretName(proxyConfig), secrets)
+		if err != nil {
+			return nil, err
+		}
+		spec.Authority.Users[i].Password = password
+	}
+
+	y, err := yaml.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Secret{
+		ObjectMeta: secretObjectMeta(proxyConfig, proxyConfig.Name),
+		Type:       v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"server.yaml": y,
+		},
+	}, nil
+}
@@ -21,11 +21,16 @@ import (
 	"context"
 
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/database-mesh/golang-sdk/aws/client/rds"
 )
 
 type RdsClient struct {
 	RDS rds.RDS
+	// Core modifies the databases with the AWS SDK, which is not supported by RDS
+	Core *awsrds.Client
 }
 
 type IRdsClient interface {
@@ -38,14 +43,30 @@ type IRdsClient interface {
 	GetInstanceByIdentifier(ctx context.Context, identifier string) (*rds.DescInstance, error)
 	GetInstancesByFilters(ctx context.Context, filters map[string][]string) (instances []*rds.DescInstance, err error)
 	DeleteInstance(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error
+	GetInstanceResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error)
+	ModifyInstance(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error
+	RestoreInstance(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error
+	CreateInstanceSnapshot(ctx context.Context, node *v1alpha1.StorageNode, identifier string) error
+	GetInstanceSnapshot(ctx context.Context, identifier string) (*provisioner.Snapshot, error)
+	DeleteInstanceSnapshot(ctx context.Context, identifier string) error
 
 	CreateRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error
 	GetRDSCluster(ctx context.Context, node *v1alpha1.StorageNode) (cluster *rds.DescCluster, err error)
 	DeleteRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error
+	GetRDSClusterResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error)
+	ModifyRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error
+	RestoreRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error
 
 	CreateAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error
 	GetAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode) (cluster *rds.DescCluster, err error)
 	DeleteAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error
+	GetAuroraClusterResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error)
+	ModifyAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error
+	RestoreAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error
+
+	CreateClusterSnapshot(ctx context.Context, node *v1alpha1.StorageNode, identifier string) error
+	GetClusterSnapshot(ctx context.Context, identifier string) (*provisioner.Snapshot, error)
+	DeleteClusterSnapshot(ctx context.Context, identifier string) error
 }
 
 func NewRdsClient(rds rds.RDS) IRdsClient {
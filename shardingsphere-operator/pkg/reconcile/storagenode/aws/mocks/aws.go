@@ -1,3 +1,20 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
 // Code generated by MockGen. DO NOT EDIT.
 // Source: aws.go
 
@@ -9,6 +26,7 @@ import (
 	reflect "reflect"
 
 	v1alpha1 "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	provisioner "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
 	rds "github.com/database-mesh/golang-sdk/aws/client/rds"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -78,6 +96,20 @@ func (mr *MockIRdsClientMockRecorder) CreateAuroraCluster(ctx, node, params inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuroraCluster", reflect.TypeOf((*MockIRdsClient)(nil).CreateAuroraCluster), ctx, node, params)
 }
 
+// CreateClusterSnapshot mocks base method.
+func (m *MockIRdsClient) CreateClusterSnapshot(ctx context.Context, node *v1alpha1.StorageNode, identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClusterSnapshot", ctx, node, identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClusterSnapshot indicates an expected call of CreateClusterSnapshot.
+func (mr *MockIRdsClientMockRecorder) CreateClusterSnapshot(ctx, node, identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClusterSnapshot", reflect.TypeOf((*MockIRdsClient)(nil).CreateClusterSnapshot), ctx, node, identifier)
+}
+
 // CreateInstance mocks base method.
 func (m *MockIRdsClient) CreateInstance(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error {
 	m.ctrl.T.Helper()
@@ -92,6 +124,20 @@ func (mr *MockIRdsClientMockRecorder) CreateInstance(ctx, node, params interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstance", reflect.TypeOf((*MockIRdsClient)(nil).CreateInstance), ctx, node, params)
 }
 
+// CreateInstanceSnapshot mocks base method.
+func (m *MockIRdsClient) CreateInstanceSnapshot(ctx context.Context, node *v1alpha1.StorageNode, identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstanceSnapshot", ctx, node, identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInstanceSnapshot indicates an expected call of CreateInstanceSnapshot.
+func (mr *MockIRdsClientMockRecorder) CreateInstanceSnapshot(ctx, node, identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstanceSnapshot", reflect.TypeOf((*MockIRdsClient)(nil).CreateInstanceSnapshot), ctx, node, identifier)
+}
+
 // CreateRDSCluster mocks base method.
 func (m *MockIRdsClient) CreateRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error {
 	m.ctrl.T.Helper()
@@ -120,6 +166,20 @@ func (mr *MockIRdsClientMockRecorder) DeleteAuroraCluster(ctx, node, storageProv
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuroraCluster", reflect.TypeOf((*MockIRdsClient)(nil).DeleteAuroraCluster), ctx, node, storageProvider)
 }
 
+// DeleteClusterSnapshot mocks base method.
+func (m *MockIRdsClient) DeleteClusterSnapshot(ctx context.Context, identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClusterSnapshot", ctx, identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClusterSnapshot indicates an expected call of DeleteClusterSnapshot.
+func (mr *MockIRdsClientMockRecorder) DeleteClusterSnapshot(ctx, identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClusterSnapshot", reflect.TypeOf((*MockIRdsClient)(nil).DeleteClusterSnapshot), ctx, identifier)
+}
+
 // DeleteInstance mocks base method.
 func (m *MockIRdsClient) DeleteInstance(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
 	m.ctrl.T.Helper()
@@ -134,6 +194,20 @@ func (mr *MockIRdsClientMockRecorder) DeleteInstance(ctx, node, storageProvider
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstance", reflect.TypeOf((*MockIRdsClient)(nil).DeleteInstance), ctx, node, storageProvider)
 }
 
+// DeleteInstanceSnapshot mocks base method.
+func (m *MockIRdsClient) DeleteInstanceSnapshot(ctx context.Context, identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstanceSnapshot", ctx, identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInstanceSnapshot indicates an expected call of DeleteInstanceSnapshot.
+func (mr *MockIRdsClientMockRecorder) DeleteInstanceSnapshot(ctx, identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstanceSnapshot", reflect.TypeOf((*MockIRdsClient)(nil).DeleteInstanceSnapshot), ctx, identifier)
+}
+
 // DeleteRDSCluster mocks base method.
 func (m *MockIRdsClient) DeleteRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
 	m.ctrl.T.Helper()
@@ -163,6 +237,37 @@ func (mr *MockIRdsClientMockRecorder) GetAuroraCluster(ctx, node interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuroraCluster", reflect.TypeOf((*MockIRdsClient)(nil).GetAuroraCluster), ctx, node)
 }
 
+// GetAuroraClusterResources mocks base method.
+func (m *MockIRdsClient) GetAuroraClusterResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuroraClusterResources", ctx, node)
+	ret0, _ := ret[0].(provisioner.Resources)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuroraClusterResources indicates an expected call of GetAuroraClusterResources.
+func (mr *MockIRdsClientMockRecorder) GetAuroraClusterResources(ctx, node interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuroraClusterResources", reflect.TypeOf((*MockIRdsClient)(nil).GetAuroraClusterResources), ctx, node)
+}
+
+// GetClusterSnapshot mocks base method.
+func (m *MockIRdsClient) GetClusterSnapshot(ctx context.Context, identifier string) (*provisioner.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterSnapshot", ctx, identifier)
+	ret0, _ := ret[0].(*provisioner.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterSnapshot indicates an expected call of GetClusterSnapshot.
+func (mr *MockIRdsClientMockRecorder) GetClusterSnapshot(ctx, identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterSnapshot", reflect.TypeOf((*MockIRdsClient)(nil).GetClusterSnapshot), ctx, identifier)
+}
+
 // GetInstance mocks base method.
 func (m *MockIRdsClient) GetInstance(ctx context.Context, node *v1alpha1.StorageNode) (*rds.DescInstance, error) {
 	m.ctrl.T.Helper()
@@ -193,6 +298,37 @@ func (mr *MockIRdsClientMockRecorder) GetInstanceByIdentifier(ctx, identifier in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceByIdentifier", reflect.TypeOf((*MockIRdsClient)(nil).GetInstanceByIdentifier), ctx, identifier)
 }
 
+// GetInstanceResources mocks base method.
+func (m *MockIRdsClient) GetInstanceResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceResources", ctx, node)
+	ret0, _ := ret[0].(provisioner.Resources)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetInstanceResources indicates an expected call of GetInstanceResources.
+func (mr *MockIRdsClientMockRecorder) GetInstanceResources(ctx, node interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceResources", reflect.TypeOf((*MockIRdsClient)(nil).GetInstanceResources), ctx, node)
+}
+
+// GetInstanceSnapshot mocks base method.
+func (m *MockIRdsClient) GetInstanceSnapshot(ctx context.Context, identifier string) (*provisioner.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceSnapshot", ctx, identifier)
+	ret0, _ := ret[0].(*provisioner.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceSnapshot indicates an expected call of GetInstanceSnapshot.
+func (mr *MockIRdsClientMockRecorder) GetInstanceSnapshot(ctx, identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceSnapshot", reflect.TypeOf((*MockIRdsClient)(nil).GetInstanceSnapshot), ctx, identifier)
+}
+
 // GetInstancesByFilters mocks base method.
 func (m *MockIRdsClient) GetInstancesByFilters(ctx context.Context, filters map[string][]string) ([]*rds.DescInstance, error) {
 	m.ctrl.T.Helper()
@@ -223,6 +359,22 @@ func (mr *MockIRdsClientMockRecorder) GetRDSCluster(ctx, node interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRDSCluster", reflect.TypeOf((*MockIRdsClient)(nil).GetRDSCluster), ctx, node)
 }
 
+// GetRDSClusterResources mocks base method.
+func (m *MockIRdsClient) GetRDSClusterResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRDSClusterResources", ctx, node)
+	ret0, _ := ret[0].(provisioner.Resources)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRDSClusterResources indicates an expected call of GetRDSClusterResources.
+func (mr *MockIRdsClientMockRecorder) GetRDSClusterResources(ctx, node interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRDSClusterResources", reflect.TypeOf((*MockIRdsClient)(nil).GetRDSClusterResources), ctx, node)
+}
+
 // Instance mocks base method.
 func (m *MockIRdsClient) Instance() rds.Instance {
 	m.ctrl.T.Helper()
@@ -236,3 +388,87 @@ func (mr *MockIRdsClientMockRecorder) Instance() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Instance", reflect.TypeOf((*MockIRdsClient)(nil).Instance))
 }
+
+// ModifyAuroraCluster mocks base method.
+func (m *MockIRdsClient) ModifyAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyAuroraCluster", ctx, node, desired)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyAuroraCluster indicates an expected call of ModifyAuroraCluster.
+func (mr *MockIRdsClientMockRecorder) ModifyAuroraCluster(ctx, node, desired interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyAuroraCluster", reflect.TypeOf((*MockIRdsClient)(nil).ModifyAuroraCluster), ctx, node, desired)
+}
+
+// ModifyInstance mocks base method.
+func (m *MockIRdsClient) ModifyInstance(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyInstance", ctx, node, desired)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyInstance indicates an expected call of ModifyInstance.
+func (mr *MockIRdsClientMockRecorder) ModifyInstance(ctx, node, desired interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyInstance", reflect.TypeOf((*MockIRdsClient)(nil).ModifyInstance), ctx, node, desired)
+}
+
+// ModifyRDSCluster mocks base method.
+func (m *MockIRdsClient) ModifyRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyRDSCluster", ctx, node, desired)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyRDSCluster indicates an expected call of ModifyRDSCluster.
+func (mr *MockIRdsClientMockRecorder) ModifyRDSCluster(ctx, node, desired interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyRDSCluster", reflect.TypeOf((*MockIRdsClient)(nil).ModifyRDSCluster), ctx, node, desired)
+}
+
+// RestoreAuroraCluster mocks base method.
+func (m *MockIRdsClient) RestoreAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAuroraCluster", ctx, node, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreAuroraCluster indicates an expected call of RestoreAuroraCluster.
+func (mr *MockIRdsClientMockRecorder) RestoreAuroraCluster(ctx, node, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAuroraCluster", reflect.TypeOf((*MockIRdsClient)(nil).RestoreAuroraCluster), ctx, node, params)
+}
+
+// RestoreInstance mocks base method.
+func (m *MockIRdsClient) RestoreInstance(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreInstance", ctx, node, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreInstance indicates an expected call of RestoreInstance.
+func (mr *MockIRdsClientMockRecorder) RestoreInstance(ctx, node, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreInstance", reflect.TypeOf((*MockIRdsClient)(nil).RestoreInstance), ctx, node, params)
+}
+
+// RestoreRDSCluster mocks base method.
+func (m *MockIRdsClient) RestoreRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRDSCluster", ctx, node, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRDSCluster indicates an expected call of RestoreRDSCluster.
+func (mr *MockIRdsClientMockRecorder) RestoreRDSCluster(ctx, node, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRDSCluster", reflect.TypeOf((*MockIRdsClient)(nil).RestoreRDSCluster), ctx, node, params)
+}
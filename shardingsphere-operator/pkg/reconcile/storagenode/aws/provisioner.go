@@ -0,0 +1,324 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	dbmeshaws "github.com/database-mesh/golang-sdk/aws"
+	"github.com/database-mesh/golang-sdk/aws/client/rds"
+)
+
+// RegisterProvisioners registers the provisioners of AWS RDS instance, AWS RDS cluster and AWS Aurora
+func RegisterProvisioners(registry *provisioner.Registry, region, accessKeyID, secretAccessKey string) {
+	c := &credential{
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+	}
+
+	registry.Register(v1alpha1.ProvisionerAWSRDSInstance, &rdsInstanceProvisioner{credential: c})
+	registry.Register(v1alpha1.ProvisionerAWSRDSCluster, &clusterProvisioner{
+		credential: c,
+		get: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode) (*rds.DescCluster, error) {
+			return c.GetRDSCluster(ctx, node)
+		},
+		create: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, params map[string]string) error {
+			return c.CreateRDSCluster(ctx, node, params)
+		},
+		delete: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+			return c.DeleteRDSCluster(ctx, node, storageProvider)
+		},
+		resources: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error) {
+			return c.GetRDSClusterResources(ctx, node)
+		},
+		modify: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, desired provisioner.Resources) error {
+			return c.ModifyRDSCluster(ctx, node, desired)
+		},
+		restore: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, params map[string]string) error {
+			return c.RestoreRDSCluster(ctx, node, params)
+		},
+	})
+	registry.Register(v1alpha1.ProvisionerAWSAurora, &clusterProvisioner{
+		credential: c,
+		get: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode) (*rds.DescCluster, error) {
+			return c.GetAuroraCluster(ctx, node)
+		},
+		create: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, params map[string]string) error {
+			return c.CreateAuroraCluster(ctx, node, params)
+		},
+		delete: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+			return c.DeleteAuroraCluster(ctx, node, storageProvider)
+		},
+		resources: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error) {
+			return c.GetAuroraClusterResources(ctx, node)
+		},
+		modify: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, desired provisioner.Resources) error {
+			return c.ModifyAuroraCluster(ctx, node, desired)
+		},
+		restore: func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, params map[string]string) error {
+			return c.RestoreAuroraCluster(ctx, node, params)
+		},
+	})
+}
+
+// credential is shared by all the AWS provisioners
+type credential struct {
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+
+	mu       sync.Mutex
+	sessions dbmeshaws.Sessions
+}
+
+func (c *credential) client() IRdsClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.sessions[c.region]; !ok {
+		c.sessions = dbmeshaws.NewSessions().SetCredential(c.region, c.accessKeyID, c.secretAccessKey).Build()
+	}
+	cli := NewRdsClient(rds.NewService(c.sessions[c.region]))
+	if rc, ok := cli.(*RdsClient); ok {
+		rc.Core = awsrds.NewFromConfig(c.sessions[c.region])
+	}
+	return cli
+}
+
+// Health returns an error if the aws credentials are not set
+func (c *credential) Health(_ context.Context) error {
+	if c.region == "" || c.accessKeyID == "" || c.secretAccessKey == "" {
+		return errors.New("aws credentials not set")
+	}
+	return nil
+}
+
+type rdsInstanceProvisioner struct {
+	*credential
+}
+
+var (
+	_ provisioner.Provisioner = (*rdsInstanceProvisioner)(nil)
+	_ provisioner.Resizer     = (*rdsInstanceProvisioner)(nil)
+	_ provisioner.Snapshotter = (*rdsInstanceProvisioner)(nil)
+)
+
+func (p *rdsInstanceProvisioner) Get(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider) (*provisioner.Database, error) {
+	// nothing is provisioned for the storage node without identifier, so there is nothing to delete
+	if node.Annotations[v1alpha1.AnnotationsInstanceIdentifier] == "" && !node.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	instance, err := p.client().GetInstance(ctx, node)
+	if err != nil || instance == nil {
+		return nil, err
+	}
+	return &provisioner.Database{
+		Deleting: instance.DBInstanceStatus == rds.DBInstanceStatusDeleting,
+		Object:   instance,
+	}, nil
+}
+
+func (p *rdsInstanceProvisioner) Create(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+	if node.Annotations[v1alpha1.AnnotationsSnapshotIdentifier] != "" {
+		return p.client().RestoreInstance(ctx, node, withSpecResources(node, storageProvider.Spec.Parameters))
+	}
+	return p.client().CreateInstance(ctx, node, withSpecResources(node, storageProvider.Spec.Parameters))
+}
+
+func (p *rdsInstanceProvisioner) Update(_ context.Context, _ *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, _ *provisioner.Database) error {
+	return nil
+}
+
+func (p *rdsInstanceProvisioner) Resources(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, _ *provisioner.Database) (provisioner.Resources, bool, error) {
+	return p.client().GetInstanceResources(ctx, node)
+}
+
+func (p *rdsInstanceProvisioner) Resize(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, _ *provisioner.Database, desired provisioner.Resources) error {
+	return p.client().ModifyInstance(ctx, node, desired)
+}
+
+func (p *rdsInstanceProvisioner) CreateSnapshot(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) error {
+	return p.client().CreateInstanceSnapshot(ctx, node, snapshot.Status.SnapshotIdentifier)
+}
+
+func (p *rdsInstanceProvisioner) GetSnapshot(ctx context.Context, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) (*provisioner.Snapshot, error) {
+	return p.client().GetInstanceSnapshot(ctx, snapshot.Status.SnapshotIdentifier)
+}
+
+func (p *rdsInstanceProvisioner) DeleteSnapshot(ctx context.Context, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) error {
+	return p.client().DeleteInstanceSnapshot(ctx, snapshot.Status.SnapshotIdentifier)
+}
+
+func (p *rdsInstanceProvisioner) Delete(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, _ *provisioner.Database) error {
+	return p.client().DeleteInstance(ctx, node, storageProvider)
+}
+
+func (p *rdsInstanceProvisioner) Status(_ context.Context, _ *v1alpha1.StorageNode, db *provisioner.Database) (v1alpha1.ClusterStatus, []v1alpha1.InstanceStatus, error) {
+	instances := make([]v1alpha1.InstanceStatus, 0)
+	if db == nil {
+		return v1alpha1.ClusterStatus{}, instances, nil
+	}
+
+	instance, ok := db.Object.(*rds.DescInstance)
+	if !ok {
+		return v1alpha1.ClusterStatus{}, nil, fmt.Errorf("unexpected database object %T", db.Object)
+	}
+
+	instances = append(instances, v1alpha1.InstanceStatus{
+		Endpoint: v1alpha1.Endpoint{
+			Address: instance.Endpoint.Address,
+			Port:    instance.Endpoint.Port,
+		},
+		Status: string(instance.DBInstanceStatus),
+	})
+	return v1alpha1.ClusterStatus{}, instances, nil
+}
+
+func (p *rdsInstanceProvisioner) Endpoints(node *v1alpha1.StorageNode) (v1alpha1.Endpoint, []v1alpha1.Endpoint) {
+	if len(node.Status.Instances) == 0 {
+		return v1alpha1.Endpoint{}, nil
+	}
+	return node.Status.Instances[0].Endpoint, nil
+}
+
+// clusterProvisioner provisions AWS RDS clusters and AWS Aurora clusters, which share the same status
+type clusterProvisioner struct {
+	*credential
+
+	get    func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode) (*rds.DescCluster, error)
+	create func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, params map[string]string) error
+	delete func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error
+
+	resources func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error)
+	modify    func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, desired provisioner.Resources) error
+	restore   func(ctx context.Context, c IRdsClient, node *v1alpha1.StorageNode, params map[string]string) error
+}
+
+var (
+	_ provisioner.Provisioner = (*clusterProvisioner)(nil)
+	_ provisioner.Resizer     = (*clusterProvisioner)(nil)
+	_ provisioner.Snapshotter = (*clusterProvisioner)(nil)
+)
+
+func (p *clusterProvisioner) Get(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider) (*provisioner.Database, error) {
+	// nothing is provisioned for the storage node without identifier, so there is nothing to delete
+	if node.Annotations[v1alpha1.AnnotationsClusterIdentifier] == "" && !node.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	cluster, err := p.get(ctx, p.client(), node)
+	if err != nil || cluster == nil {
+		return nil, err
+	}
+	return &provisioner.Database{
+		Deleting: cluster.Status == string(rds.DBClusterStatusDeleting),
+		Object:   cluster,
+	}, nil
+}
+
+func (p *clusterProvisioner) Create(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+	if node.Annotations[v1alpha1.AnnotationsSnapshotIdentifier] != "" {
+		return p.restore(ctx, p.client(), node, withSpecResources(node, storageProvider.Spec.Parameters))
+	}
+	return p.create(ctx, p.client(), node, withSpecResources(node, storageProvider.Spec.Parameters))
+}
+
+func (p *clusterProvisioner) Update(_ context.Context, _ *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, _ *provisioner.Database) error {
+	return nil
+}
+
+func (p *clusterProvisioner) Resources(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, _ *provisioner.Database) (provisioner.Resources, bool, error) {
+	return p.resources(ctx, p.client(), node)
+}
+
+func (p *clusterProvisioner) Resize(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, _ *provisioner.Database, desired provisioner.Resources) error {
+	return p.modify(ctx, p.client(), node, desired)
+}
+
+func (p *clusterProvisioner) CreateSnapshot(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) error {
+	return p.client().CreateClusterSnapshot(ctx, node, snapshot.Status.SnapshotIdentifier)
+}
+
+func (p *clusterProvisioner) GetSnapshot(ctx context.Context, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) (*provisioner.Snapshot, error) {
+	return p.client().GetClusterSnapshot(ctx, snapshot.Status.SnapshotIdentifier)
+}
+
+func (p *clusterProvisioner) DeleteSnapshot(ctx context.Context, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) error {
+	return p.client().DeleteClusterSnapshot(ctx, snapshot.Status.SnapshotIdentifier)
+}
+
+func (p *clusterProvisioner) Delete(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, _ *provisioner.Database) error {
+	return p.delete(ctx, p.client(), node, storageProvider)
+}
+
+func (p *clusterProvisioner) Status(ctx context.Context, node *v1alpha1.StorageNode, db *provisioner.Database) (v1alpha1.ClusterStatus, []v1alpha1.InstanceStatus, error) {
+	clusterStatus := v1alpha1.ClusterStatus{}
+	if db != nil {
+		cluster, ok := db.Object.(*rds.DescCluster)
+		if !ok {
+			return clusterStatus, nil, fmt.Errorf("unexpected database object %T", db.Object)
+		}
+		clusterStatus = v1alpha1.ClusterStatus{
+			Status: cluster.Status,
+			PrimaryEndpoint: v1alpha1.Endpoint{
+				Address: cluster.PrimaryEndpoint,
+				Port:    cluster.Port,
+			},
+			ReaderEndpoints: []v1alpha1.Endpoint{
+				{
+					Address: cluster.ReaderEndpoint,
+					Port:    cluster.Port,
+				},
+			},
+		}
+	}
+
+	// update instances status
+	identifier := node.Annotations[v1alpha1.AnnotationsClusterIdentifier]
+	filters := map[string][]string{
+		"db-cluster-id": {identifier},
+	}
+	instances, err := p.client().GetInstancesByFilters(ctx, filters)
+	if err != nil {
+		return clusterStatus, nil, fmt.Errorf("GetInstances failed, err:%w", err)
+	}
+
+	var instanceStatus []v1alpha1.InstanceStatus
+	for _, instance := range instances {
+		instanceStatus = append(instanceStatus, v1alpha1.InstanceStatus{
+			Status: string(instance.DBInstanceStatus),
+			Endpoint: v1alpha1.Endpoint{
+				Address: instance.Endpoint.Address,
+				Port:    instance.Endpoint.Port,
+			}})
+	}
+	return clusterStatus, instanceStatus, nil
+}
+
+func (p *clusterProvisioner) Endpoints(node *v1alpha1.StorageNode) (v1alpha1.Endpoint, []v1alpha1.Endpoint) {
+	return node.Status.Cluster.PrimaryEndpoint, node.Status.Cluster.ReaderEndpoints
+}
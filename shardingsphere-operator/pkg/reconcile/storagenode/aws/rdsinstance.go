@@ -68,14 +68,11 @@ func validCreateInstanceParams(node *v1alpha1.StorageNode, paramsptr *map[string
 		return errors.New("instance identifier is empty")
 	}
 
-	// TODO set options to generate password and write back to storage node annos.
-	// TODO set options to set master username by user.
 	// validate master user password length. must be greater than 8. from aws doc.
+	// the master user is kept in the credentials Secret of the storage node, never in the annotations.
 	lp := len(params["masterUserPassword"])
 	if lp < 8 || lp > 41 {
 		return errors.New("master user password length should be greater than 8")
-	} else {
-		node.Annotations[v1alpha1.AnnotationsMasterUserPassword] = params["masterUserPassword"]
 	}
 
 	return nil
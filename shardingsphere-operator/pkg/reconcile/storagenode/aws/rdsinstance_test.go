@@ -50,7 +50,7 @@ var _ = Describe("validCreateInstanceParams", func() {
 
 			node.Annotations[v1alpha1.AnnotationsInstanceIdentifier] = "test-instance"
 			Expect(validCreateInstanceParams(node, &params)).To(BeNil())
-			Expect(node.Annotations[v1alpha1.AnnotationsMasterUserPassword]).To(Equal("root123456"))
+			Expect(node.Annotations).ToNot(HaveKey(v1alpha1.AnnotationsMasterUserPassword))
 		})
 		It("should return username contains invalid characters", func() {
 			params["masterUsername"] = "@masterUser"
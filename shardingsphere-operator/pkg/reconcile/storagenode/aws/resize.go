@@ -0,0 +1,339 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/database-mesh/golang-sdk/aws/client/rds"
+	"k8s.io/utils/strings/slices"
+)
+
+// withSpecResources returns a copy of the parameters of the storage provider overridden by the resources in the spec
+func withSpecResources(node *v1alpha1.StorageNode, params map[string]string) map[string]string {
+	ps := make(map[string]string, len(params))
+	for k, v := range params {
+		ps[k] = v
+	}
+	if node.Spec.InstanceClass != "" {
+		ps["instanceClass"] = node.Spec.InstanceClass
+	}
+	if node.Spec.AllocatedStorage != 0 {
+		ps["allocatedStorage"] = strconv.Itoa(int(node.Spec.AllocatedStorage))
+	}
+	if node.Spec.IOPS != 0 {
+		ps["iops"] = strconv.Itoa(int(node.Spec.IOPS))
+	}
+	return ps
+}
+
+func (c *RdsClient) core() (*awsrds.Client, error) {
+	if c.Core == nil {
+		return nil, errors.New("aws rds client is not set")
+	}
+	return c.Core, nil
+}
+
+func (c *RdsClient) describeDBInstance(ctx context.Context, identifier string) (*types.DBInstance, error) {
+	core, err := c.core()
+	if err != nil {
+		return nil, err
+	}
+	out, err := core.DescribeDBInstances(ctx, &awsrds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(identifier)})
+	if err != nil {
+		return nil, fmt.Errorf("describe db instance %s failed: %w", identifier, err)
+	}
+	if len(out.DBInstances) == 0 {
+		return nil, fmt.Errorf("db instance %s not found", identifier)
+	}
+	return &out.DBInstances[0], nil
+}
+
+func (c *RdsClient) describeDBCluster(ctx context.Context, identifier string) (*types.DBCluster, error) {
+	core, err := c.core()
+	if err != nil {
+		return nil, err
+	}
+	out, err := core.DescribeDBClusters(ctx, &awsrds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(identifier)})
+	if err != nil {
+		return nil, fmt.Errorf("describe db cluster %s failed: %w", identifier, err)
+	}
+	if len(out.DBClusters) == 0 {
+		return nil, fmt.Errorf("db cluster %s not found", identifier)
+	}
+	return &out.DBClusters[0], nil
+}
+
+// instanceResizing returns true if the instance is being modified or has modifications to apply
+func instanceResizing(ins *types.DBInstance) bool {
+	if status := aws.ToString(ins.DBInstanceStatus); status != string(rds.DBInstanceStatusAvailable) {
+		return true
+	}
+	pending := ins.PendingModifiedValues
+	return pending != nil && (pending.DBInstanceClass != nil || pending.AllocatedStorage != nil || pending.Iops != nil)
+}
+
+// GetInstanceResources returns the resources of the rds instance and whether it is being resized,
+// the replicas are not managed as the rds instance has no readers.
+func (c *RdsClient) GetInstanceResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error) {
+	ins, err := c.describeDBInstance(ctx, node.Annotations[v1alpha1.AnnotationsInstanceIdentifier])
+	if err != nil {
+		return provisioner.Resources{}, false, err
+	}
+	return provisioner.Resources{
+		InstanceClass:    aws.ToString(ins.DBInstanceClass),
+		AllocatedStorage: ins.AllocatedStorage,
+		IOPS:             aws.ToInt32(ins.Iops),
+	}, instanceResizing(ins), nil
+}
+
+// ModifyInstance modifies the instance class, the allocated storage and the IOPS of the rds instance immediately,
+// the zero desired resources are not modified.
+// ref: https://docs.aws.amazon.com/AmazonRDS/latest/APIReference/API_ModifyDBInstance.html
+func (c *RdsClient) ModifyInstance(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+
+	input := &awsrds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(node.Annotations[v1alpha1.AnnotationsInstanceIdentifier]),
+		ApplyImmediately:     true,
+	}
+	if desired.InstanceClass != "" {
+		input.DBInstanceClass = aws.String(desired.InstanceClass)
+	}
+	if desired.AllocatedStorage != 0 {
+		input.AllocatedStorage = aws.Int32(desired.AllocatedStorage)
+	}
+	if desired.IOPS != 0 {
+		input.Iops = aws.Int32(desired.IOPS)
+	}
+
+	if _, err := core.ModifyDBInstance(ctx, input); err != nil {
+		return fmt.Errorf("modify db instance failed: %w", err)
+	}
+	return nil
+}
+
+// GetRDSClusterResources returns the resources of the rds cluster and whether it is being resized,
+// the replicas are not managed as the rds cluster always has 3 instances.
+func (c *RdsClient) GetRDSClusterResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error) {
+	cluster, err := c.describeDBCluster(ctx, node.Annotations[v1alpha1.AnnotationsClusterIdentifier])
+	if err != nil {
+		return provisioner.Resources{}, false, err
+	}
+	return provisioner.Resources{
+		InstanceClass:    aws.ToString(cluster.DBClusterInstanceClass),
+		AllocatedStorage: aws.ToInt32(cluster.AllocatedStorage),
+		IOPS:             aws.ToInt32(cluster.Iops),
+	}, aws.ToString(cluster.Status) != string(rds.DBClusterStatusAvailable), nil
+}
+
+// ModifyRDSCluster modifies the instance class, the allocated storage and the IOPS of the rds cluster immediately,
+// the zero desired resources are not modified.
+// ref: https://docs.aws.amazon.com/AmazonRDS/latest/APIReference/API_ModifyDBCluster.html
+func (c *RdsClient) ModifyRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+
+	input := &awsrds.ModifyDBClusterInput{
+		DBClusterIdentifier: aws.String(node.Annotations[v1alpha1.AnnotationsClusterIdentifier]),
+		ApplyImmediately:    true,
+	}
+	if desired.InstanceClass != "" {
+		input.DBClusterInstanceClass = aws.String(desired.InstanceClass)
+	}
+	if desired.AllocatedStorage != 0 {
+		input.AllocatedStorage = aws.Int32(desired.AllocatedStorage)
+	}
+	if desired.IOPS != 0 {
+		input.Iops = aws.Int32(desired.IOPS)
+	}
+
+	if _, err := core.ModifyDBCluster(ctx, input); err != nil {
+		return fmt.Errorf("modify db cluster failed: %w", err)
+	}
+	return nil
+}
+
+// auroraInstances returns the instances of the aurora cluster, the writer goes first
+func (c *RdsClient) auroraInstances(ctx context.Context, cluster *types.DBCluster) ([]*types.DBInstance, error) {
+	members := make([]types.DBClusterMember, len(cluster.DBClusterMembers))
+	copy(members, cluster.DBClusterMembers)
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].IsClusterWriter && !members[j].IsClusterWriter
+	})
+
+	instances := make([]*types.DBInstance, 0, len(members))
+	for _, m := range members {
+		ins, err := c.describeDBInstance(ctx, aws.ToString(m.DBInstanceIdentifier))
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, ins)
+	}
+	return instances, nil
+}
+
+// GetAuroraClusterResources returns the instance class and the number of instances of the aurora cluster,
+// and whether it is being resized. The storage of aurora grows automatically, so it is not managed.
+// The instance class is the classes of all the instances joined by comma while they are rolled to another class.
+func (c *RdsClient) GetAuroraClusterResources(ctx context.Context, node *v1alpha1.StorageNode) (provisioner.Resources, bool, error) {
+	cluster, err := c.describeDBCluster(ctx, node.Annotations[v1alpha1.AnnotationsClusterIdentifier])
+	if err != nil {
+		return provisioner.Resources{}, false, err
+	}
+	instances, err := c.auroraInstances(ctx, cluster)
+	if err != nil {
+		return provisioner.Resources{}, false, err
+	}
+
+	resizing := aws.ToString(cluster.Status) != string(rds.DBClusterStatusAvailable)
+	classes := []string{}
+	for _, ins := range instances {
+		class := aws.ToString(ins.DBInstanceClass)
+		if !slices.Contains(classes, class) {
+			classes = append(classes, class)
+		}
+		if instanceResizing(ins) {
+			resizing = true
+		}
+	}
+	return provisioner.Resources{
+		InstanceClass: strings.Join(classes, ","),
+		Replicas:      int32(len(instances)),
+	}, resizing, nil
+}
+
+// ModifyAuroraCluster takes one step of resizing the aurora cluster, the next step is taken after the previous one is done:
+// the surplus readers are removed and the missing ones are added first, then the readers are modified one by one,
+// then the writer fails over to a modified reader, and the old writer is modified at last as a reader.
+// The writer of a single instance cluster is modified in place, which is unavailable during the modification.
+func (c *RdsClient) ModifyAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode, desired provisioner.Resources) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+
+	identifier := node.Annotations[v1alpha1.AnnotationsClusterIdentifier]
+	cluster, err := c.describeDBCluster(ctx, identifier)
+	if err != nil {
+		return err
+	}
+	instances, err := c.auroraInstances(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	if len(instances) == 0 {
+		return fmt.Errorf("aurora cluster %s has no instances", identifier)
+	}
+	writer, readers := instances[0], instances[1:]
+
+	class := desired.InstanceClass
+	if class == "" {
+		class = aws.ToString(writer.DBInstanceClass)
+	}
+
+	// the readers are removed from the last one, the writer is never removed
+	replicas := int(desired.Replicas)
+	if replicas != 0 && len(instances) != replicas {
+		for len(instances) > replicas && len(instances) > 1 {
+			last := instances[len(instances)-1]
+			if _, err := core.DeleteDBInstance(ctx, &awsrds.DeleteDBInstanceInput{
+				DBInstanceIdentifier: last.DBInstanceIdentifier,
+				SkipFinalSnapshot:    true,
+			}); err != nil {
+				return fmt.Errorf("delete aurora instance %s failed: %w", aws.ToString(last.DBInstanceIdentifier), err)
+			}
+			instances = instances[:len(instances)-1]
+		}
+
+		existing := map[string]bool{}
+		for _, ins := range instances {
+			existing[aws.ToString(ins.DBInstanceIdentifier)] = true
+		}
+		return addAuroraInstances(ctx, core, identifier, cluster.Engine, class, aws.Bool(writer.PubliclyAccessible), existing, replicas)
+	}
+
+	for _, ins := range readers {
+		if aws.ToString(ins.DBInstanceClass) != class {
+			return modifyAuroraInstance(ctx, core, ins, class)
+		}
+	}
+
+	if aws.ToString(writer.DBInstanceClass) == class {
+		return nil
+	}
+	if len(readers) == 0 {
+		return modifyAuroraInstance(ctx, core, writer, class)
+	}
+	if _, err := core.FailoverDBCluster(ctx, &awsrds.FailoverDBClusterInput{
+		DBClusterIdentifier:        aws.String(identifier),
+		TargetDBInstanceIdentifier: readers[0].DBInstanceIdentifier,
+	}); err != nil {
+		return fmt.Errorf("failover aurora cluster %s failed: %w", identifier, err)
+	}
+	return nil
+}
+
+func modifyAuroraInstance(ctx context.Context, core *awsrds.Client, ins *types.DBInstance, class string) error {
+	if _, err := core.ModifyDBInstance(ctx, &awsrds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: ins.DBInstanceIdentifier,
+		DBInstanceClass:      aws.String(class),
+		ApplyImmediately:     true,
+	}); err != nil {
+		return fmt.Errorf("modify aurora instance %s failed: %w", aws.ToString(ins.DBInstanceIdentifier), err)
+	}
+	return nil
+}
+
+// addAuroraInstances creates the instances of the aurora cluster until there are the given replicas,
+// the instances are named the same as the ones created with the cluster.
+func addAuroraInstances(ctx context.Context, core *awsrds.Client, identifier string, engine *string, class string, publiclyAccessible *bool, existing map[string]bool, replicas int) error {
+	for i := 0; len(existing) < replicas; i++ {
+		name := fmt.Sprintf("%s-instance-%d", identifier, i)
+		if existing[name] {
+			continue
+		}
+		if _, err := core.CreateDBInstance(ctx, &awsrds.CreateDBInstanceInput{
+			DBClusterIdentifier:  aws.String(identifier),
+			DBInstanceIdentifier: aws.String(name),
+			DBInstanceClass:      aws.String(class),
+			Engine:               engine,
+			PubliclyAccessible:   publiclyAccessible,
+		}); err != nil {
+			return fmt.Errorf("create aurora instance %s failed: %w", name, err)
+		}
+		existing[name] = true
+	}
+	return nil
+}
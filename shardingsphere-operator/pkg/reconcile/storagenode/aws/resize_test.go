@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeAurora serves the aurora cluster of the instances with the RDS query API
+type fakeAurora struct {
+	members []string
+	classes map[string]string
+	writer  string
+	actions []string
+}
+
+func (f *fakeAurora) instanceXML(id string) string {
+	return fmt.Sprintf("<DBInstance><DBInstanceIdentifier>%s</DBInstanceIdentifier><DBInstanceClass>%s</DBInstanceClass><DBInstanceStatus>available</DBInstanceStatus></DBInstance>", id, f.classes[id])
+}
+
+func (f *fakeAurora) clusterXML() string {
+	members := ""
+	for _, m := range f.members {
+		members += fmt.Sprintf("<DBClusterMember><DBInstanceIdentifier>%s</DBInstanceIdentifier><IsClusterWriter>%t</IsClusterWriter></DBClusterMember>", m, m == f.writer)
+	}
+	return fmt.Sprintf("<DBCluster><DBClusterIdentifier>test</DBClusterIdentifier><Status>available</Status><Engine>aurora-mysql</Engine><DBClusterMembers>%s</DBClusterMembers></DBCluster>", members)
+}
+
+func (f *fakeAurora) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	action := r.Form.Get("Action")
+	var result string
+	switch action {
+	case "DescribeDBClusters":
+		result = "<DBClusters>" + f.clusterXML() + "</DBClusters>"
+	case "DescribeDBInstances":
+		result = "<DBInstances>" + f.instanceXML(r.Form.Get("DBInstanceIdentifier")) + "</DBInstances>"
+	case "ModifyDBInstance":
+		id := r.Form.Get("DBInstanceIdentifier")
+		f.classes[id] = r.Form.Get("DBInstanceClass")
+		f.actions = append(f.actions, "modify "+id)
+		result = f.instanceXML(id)
+	case "FailoverDBCluster":
+		f.writer = r.Form.Get("TargetDBInstanceIdentifier")
+		f.actions = append(f.actions, "failover "+f.writer)
+		result = f.clusterXML()
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, "<%sResponse><%sResult>%s</%sResult></%sResponse>", action, action, result, action, action)
+}
+
+var _ = Describe("Aurora Resize", func() {
+	It("should roll the readers first and fail over before modifying the writer", func() {
+		fake := &fakeAurora{
+			members: []string{"test-instance-0", "test-instance-1", "test-instance-2"},
+			classes: map[string]string{"test-instance-0": "db.r5.large", "test-instance-1": "db.r5.large", "test-instance-2": "db.r5.large"},
+			writer:  "test-instance-0",
+		}
+		srv := httptest.NewServer(fake)
+		defer srv.Close()
+
+		c := &RdsClient{Core: awsrds.New(awsrds.Options{
+			Region:           "us-east-1",
+			Credentials:      sdkaws.AnonymousCredentials{},
+			EndpointResolver: awsrds.EndpointResolverFromURL(srv.URL),
+		})}
+		node := &v1alpha1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test",
+				Namespace:   "default",
+				Annotations: map[string]string{v1alpha1.AnnotationsClusterIdentifier: "test"},
+			},
+		}
+		desired := provisioner.Resources{InstanceClass: "db.r5.xlarge", Replicas: 3}
+
+		for i := 0; i < 5; i++ {
+			current, resizing, err := c.GetAuroraClusterResources(ctx, node)
+			Expect(err).To(BeNil())
+			Expect(resizing).To(BeFalse())
+			if len(provisioner.Changes(current, desired)) == 0 {
+				break
+			}
+			Expect(c.ModifyAuroraCluster(ctx, node, desired)).To(Succeed())
+		}
+
+		Expect(fake.actions).To(Equal([]string{
+			"modify test-instance-1",
+			"modify test-instance-2",
+			"failover test-instance-1",
+			"modify test-instance-0",
+		}))
+		current, _, err := c.GetAuroraClusterResources(ctx, node)
+		Expect(err).To(BeNil())
+		Expect(current.InstanceClass).To(Equal("db.r5.xlarge"))
+	})
+})
@@ -0,0 +1,290 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsrds "github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const snapshotStatusAvailable = "available"
+
+// snapshot returns the snapshot by the status reported by aws
+func snapshot(status string, created *time.Time, allocatedStorage int32) *provisioner.Snapshot {
+	s := &provisioner.Snapshot{
+		Status:           status,
+		Ready:            status == snapshotStatusAvailable,
+		Failed:           status == "failed" || strings.HasPrefix(status, "incompatible"),
+		AllocatedStorage: allocatedStorage,
+	}
+	if s.Failed {
+		s.Message = fmt.Sprintf("snapshot is %s", status)
+	}
+	if created != nil {
+		s.CreationTime = *created
+	}
+	return s
+}
+
+// CreateInstanceSnapshot takes a snapshot of the rds instance
+// ref: https://docs.aws.amazon.com/AmazonRDS/latest/APIReference/API_CreateDBSnapshot.html
+func (c *RdsClient) CreateInstanceSnapshot(ctx context.Context, node *v1alpha1.StorageNode, identifier string) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+	if _, err := core.CreateDBSnapshot(ctx, &awsrds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: aws.String(node.Annotations[v1alpha1.AnnotationsInstanceIdentifier]),
+		DBSnapshotIdentifier: aws.String(identifier),
+	}); err != nil {
+		var exists *types.DBSnapshotAlreadyExistsFault
+		if errors.As(err, &exists) {
+			return nil
+		}
+		return fmt.Errorf("create db snapshot %s failed: %w", identifier, err)
+	}
+	return nil
+}
+
+// GetInstanceSnapshot returns the snapshot of rds instance, or nil if it does not exist
+func (c *RdsClient) GetInstanceSnapshot(ctx context.Context, identifier string) (*provisioner.Snapshot, error) {
+	core, err := c.core()
+	if err != nil {
+		return nil, err
+	}
+	out, err := core.DescribeDBSnapshots(ctx, &awsrds.DescribeDBSnapshotsInput{DBSnapshotIdentifier: aws.String(identifier)})
+	if err != nil {
+		var notFound *types.DBSnapshotNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("describe db snapshot %s failed: %w", identifier, err)
+	}
+	if len(out.DBSnapshots) == 0 {
+		return nil, nil
+	}
+	s := out.DBSnapshots[0]
+	return snapshot(aws.ToString(s.Status), s.SnapshotCreateTime, s.AllocatedStorage), nil
+}
+
+// DeleteInstanceSnapshot deletes the snapshot of rds instance
+func (c *RdsClient) DeleteInstanceSnapshot(ctx context.Context, identifier string) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+	if _, err := core.DeleteDBSnapshot(ctx, &awsrds.DeleteDBSnapshotInput{DBSnapshotIdentifier: aws.String(identifier)}); err != nil {
+		var notFound *types.DBSnapshotNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("delete db snapshot %s failed: %w", identifier, err)
+	}
+	return nil
+}
+
+// RestoreInstance creates the rds instance from the snapshot in the annotations of the storage node,
+// the master user is the one of the snapshot.
+// ref: https://docs.aws.amazon.com/AmazonRDS/latest/APIReference/API_RestoreDBInstanceFromDBSnapshot.html
+func (c *RdsClient) RestoreInstance(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+	identifier := node.Annotations[v1alpha1.AnnotationsInstanceIdentifier]
+	if identifier == "" {
+		return errors.New("instance identifier is empty")
+	}
+
+	input := &awsrds.RestoreDBInstanceFromDBSnapshotInput{
+		DBInstanceIdentifier: aws.String(identifier),
+		DBSnapshotIdentifier: aws.String(node.Annotations[v1alpha1.AnnotationsSnapshotIdentifier]),
+	}
+	if v := params["instanceClass"]; v != "" {
+		input.DBInstanceClass = aws.String(v)
+	}
+	if v := params["iops"]; v != "" {
+		iops, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("iops is not a number, %v", err)
+		}
+		input.Iops = aws.Int32(int32(iops))
+	}
+	if v := params["publicAccessible"]; v != "" {
+		input.PubliclyAccessible = aws.Bool(v != "false")
+	}
+	if v := params["vpcSecurityGroupIds"]; v != "" {
+		input.VpcSecurityGroupIds = strings.Split(v, ",")
+	}
+	if v := node.Annotations[v1alpha1.AnnotationsSubnetGroupName]; v != "" {
+		input.DBSubnetGroupName = aws.String(v)
+	}
+
+	if _, err := core.RestoreDBInstanceFromDBSnapshot(ctx, input); err != nil {
+		return fmt.Errorf("restore db instance %s failed: %w", identifier, err)
+	}
+	return nil
+}
+
+// CreateClusterSnapshot takes a snapshot of the rds cluster or the aurora cluster
+// ref: https://docs.aws.amazon.com/AmazonRDS/latest/APIReference/API_CreateDBClusterSnapshot.html
+func (c *RdsClient) CreateClusterSnapshot(ctx context.Context, node *v1alpha1.StorageNode, identifier string) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+	if _, err := core.CreateDBClusterSnapshot(ctx, &awsrds.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         aws.String(node.Annotations[v1alpha1.AnnotationsClusterIdentifier]),
+		DBClusterSnapshotIdentifier: aws.String(identifier),
+	}); err != nil {
+		var exists *types.DBClusterSnapshotAlreadyExistsFault
+		if errors.As(err, &exists) {
+			return nil
+		}
+		return fmt.Errorf("create db cluster snapshot %s failed: %w", identifier, err)
+	}
+	return nil
+}
+
+// GetClusterSnapshot returns the snapshot of the rds cluster or the aurora cluster, or nil if it does not exist
+func (c *RdsClient) GetClusterSnapshot(ctx context.Context, identifier string) (*provisioner.Snapshot, error) {
+	core, err := c.core()
+	if err != nil {
+		return nil, err
+	}
+	out, err := core.DescribeDBClusterSnapshots(ctx, &awsrds.DescribeDBClusterSnapshotsInput{DBClusterSnapshotIdentifier: aws.String(identifier)})
+	if err != nil {
+		var notFound *types.DBClusterSnapshotNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("describe db cluster snapshot %s failed: %w", identifier, err)
+	}
+	if len(out.DBClusterSnapshots) == 0 {
+		return nil, nil
+	}
+	s := out.DBClusterSnapshots[0]
+	return snapshot(aws.ToString(s.Status), s.SnapshotCreateTime, s.AllocatedStorage), nil
+}
+
+// DeleteClusterSnapshot deletes the snapshot of the rds cluster or the aurora cluster
+func (c *RdsClient) DeleteClusterSnapshot(ctx context.Context, identifier string) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+	if _, err := core.DeleteDBClusterSnapshot(ctx, &awsrds.DeleteDBClusterSnapshotInput{DBClusterSnapshotIdentifier: aws.String(identifier)}); err != nil {
+		var notFound *types.DBClusterSnapshotNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("delete db cluster snapshot %s failed: %w", identifier, err)
+	}
+	return nil
+}
+
+// restoreCluster creates the cluster from the snapshot in the annotations of the storage node,
+// the master user is the one of the snapshot.
+// ref: https://docs.aws.amazon.com/AmazonRDS/latest/APIReference/API_RestoreDBClusterFromSnapshot.html
+func (c *RdsClient) restoreCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string, input *awsrds.RestoreDBClusterFromSnapshotInput) error {
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+	identifier := node.Annotations[v1alpha1.AnnotationsClusterIdentifier]
+	if identifier == "" {
+		return errors.New("cluster identifier is empty")
+	}
+	if params["engine"] == "" {
+		return errors.New("engine is empty")
+	}
+
+	input.DBClusterIdentifier = aws.String(identifier)
+	input.SnapshotIdentifier = aws.String(node.Annotations[v1alpha1.AnnotationsSnapshotIdentifier])
+	input.Engine = aws.String(params["engine"])
+	if v := params["engineVersion"]; v != "" {
+		input.EngineVersion = aws.String(v)
+	}
+	if v := params["vpcSecurityGroupIds"]; v != "" {
+		input.VpcSecurityGroupIds = strings.Split(v, ",")
+	}
+	if v := node.Annotations[v1alpha1.AnnotationsSubnetGroupName]; v != "" {
+		input.DBSubnetGroupName = aws.String(v)
+	}
+
+	if _, err := core.RestoreDBClusterFromSnapshot(ctx, input); err != nil {
+		return fmt.Errorf("restore db cluster %s failed: %w", identifier, err)
+	}
+	return nil
+}
+
+// RestoreRDSCluster creates the rds cluster from the snapshot in the annotations of the storage node
+func (c *RdsClient) RestoreRDSCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error {
+	input := &awsrds.RestoreDBClusterFromSnapshotInput{}
+	if v := params["instanceClass"]; v != "" {
+		input.DBClusterInstanceClass = aws.String(v)
+	}
+	if v := params["storageType"]; v != "" {
+		input.StorageType = aws.String(v)
+	}
+	if v := params["iops"]; v != "" {
+		iops, err := getIOPS(v)
+		if err != nil {
+			return err
+		}
+		input.Iops = aws.Int32(int32(iops))
+	}
+	if v := params["publicAccessible"]; v != "" {
+		input.PubliclyAccessible = aws.Bool(v != "false")
+	}
+	return c.restoreCluster(ctx, node, params, input)
+}
+
+// RestoreAuroraCluster creates the aurora cluster from the snapshot in the annotations of the storage node,
+// and the instances of the cluster, which are not restored from the snapshot.
+func (c *RdsClient) RestoreAuroraCluster(ctx context.Context, node *v1alpha1.StorageNode, params map[string]string) error {
+	if params["instanceClass"] == "" {
+		return errors.New("instance class is empty")
+	}
+	if err := c.restoreCluster(ctx, node, params, &awsrds.RestoreDBClusterFromSnapshotInput{}); err != nil {
+		return err
+	}
+
+	core, err := c.core()
+	if err != nil {
+		return err
+	}
+	replicas := int(node.Spec.Replicas)
+	if replicas < 1 {
+		replicas = 1
+	}
+	publiclyAccessible := aws.Bool(params["publicAccessible"] != "false")
+	return addAuroraInstances(ctx, core, node.Annotations[v1alpha1.AnnotationsClusterIdentifier], aws.String(params["engine"]),
+		params["instanceClass"], publiclyAccessible, map[string]bool{}, replicas)
+}
@@ -0,0 +1,294 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativepg
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	cloudnativepg "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/cloudnative-pg"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	cnpg "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	cnpgutils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultPort = 5432
+
+type cnpgProvisioner struct {
+	cnpg   cloudnativepg.CloudNativePG
+	reader client.Reader
+}
+
+var (
+	_ provisioner.Provisioner = (*cnpgProvisioner)(nil)
+	_ provisioner.Resizer     = (*cnpgProvisioner)(nil)
+	_ provisioner.Snapshotter = (*cnpgProvisioner)(nil)
+)
+
+// NewProvisioner returns the provisioner of CloudNativePG clusters
+func NewProvisioner(c client.Client) provisioner.Provisioner {
+	return &cnpgProvisioner{
+		cnpg:   cloudnativepg.NewCloudNativePGClient(c),
+		reader: c,
+	}
+}
+
+func (p *cnpgProvisioner) Get(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider) (*provisioner.Database, error) {
+	cluster, err := p.cnpg.GetClusterByNamespacedName(ctx, types.NamespacedName{Namespace: node.Namespace, Name: node.Name})
+	if err != nil || cluster == nil {
+		return nil, err
+	}
+	return &provisioner.Database{
+		Deleting: !cluster.DeletionTimestamp.IsZero(),
+		Object:   cluster,
+	}, nil
+}
+
+// withNodeParameters returns a copy of the storage provider with the parameters of the storage node:
+// the superuser is kept in the credentials Secret of the storage node, unless the storage provider has its own superuser Secret,
+// the storage size is the allocated storage in the spec and the instances are the replicas in the spec if they are set.
+func withNodeParameters(node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) *v1alpha1.StorageProvider {
+	sp := storageProvider.DeepCopy()
+	if sp.Spec.Parameters == nil {
+		sp.Spec.Parameters = map[string]string{}
+	}
+	if node.Status.CredentialsSecretRef != nil && storageProvider.Spec.Parameters["superuserSecret"] == "" {
+		sp.Spec.Parameters["superuserSecret"] = node.Status.CredentialsSecretRef.Name
+		sp.Spec.Parameters["enableSuperuserAccess"] = "true"
+	}
+	if node.Spec.AllocatedStorage != 0 {
+		sp.Spec.Parameters["storage.size"] = storageSize(node.Spec.AllocatedStorage)
+	}
+	if node.Spec.Replicas != 0 {
+		sp.Spec.Parameters["instances"] = strconv.Itoa(int(node.Spec.Replicas))
+	}
+	return sp
+}
+
+func storageSize(gi int32) string {
+	return fmt.Sprintf("%dGi", gi)
+}
+
+func (p *cnpgProvisioner) Create(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+	cluster := p.cnpg.Build(ctx, node, withNodeParameters(node, storageProvider))
+	err := p.cnpg.Create(ctx, cluster)
+	if err != nil && apierrors.IsAlreadyExists(err) || err == nil {
+		return nil
+	}
+	return err
+}
+
+func (p *cnpgProvisioner) Update(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, db *provisioner.Database) error {
+	cluster, ok := db.Object.(*cnpg.Cluster)
+	if !ok {
+		return fmt.Errorf("unexpected database object %T", db.Object)
+	}
+
+	exp := p.cnpg.Build(ctx, node, withNodeParameters(node, storageProvider))
+	exp.ObjectMeta = cluster.ObjectMeta
+	exp.Labels = cluster.Labels
+	exp.Annotations = cluster.Annotations
+	// the bootstrap is only used on creation
+	exp.Spec.Bootstrap = cluster.Spec.Bootstrap
+	// the storage and the instances managed by the spec of the storage node are changed by Resize
+	if node.Spec.AllocatedStorage != 0 {
+		exp.Spec.StorageConfiguration.Size = cluster.Spec.StorageConfiguration.Size
+	}
+	if node.Spec.Replicas != 0 {
+		exp.Spec.Instances = cluster.Spec.Instances
+	}
+
+	if !reflect.DeepEqual(cluster.Spec, exp.Spec) {
+		return p.cnpg.Update(ctx, exp)
+	}
+	return nil
+}
+
+// Resources returns the storage and the instances of the cluster
+func (p *cnpgProvisioner) Resources(_ context.Context, _ *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, db *provisioner.Database) (provisioner.Resources, bool, error) {
+	cluster, ok := db.Object.(*cnpg.Cluster)
+	if !ok {
+		return provisioner.Resources{}, false, fmt.Errorf("unexpected database object %T", db.Object)
+	}
+
+	size, err := resource.ParseQuantity(cluster.Spec.StorageConfiguration.Size)
+	if err != nil {
+		return provisioner.Resources{}, false, fmt.Errorf("invalid storage size %s: %w", cluster.Spec.StorageConfiguration.Size, err)
+	}
+
+	// the volumes are expanded and the instances are scaled one by one, the cluster is not healthy until all of them are done
+	resizing := cluster.Status.Phase != "" && cluster.Status.Phase != cnpg.PhaseHealthy
+	return provisioner.Resources{
+		AllocatedStorage: int32(size.Value() >> 30),
+		Replicas:         int32(cluster.Spec.Instances),
+	}, resizing, nil
+}
+
+// Resize expands the volumes of the cluster, which requires the storage class to allow volume expansion,
+// and scales the instances of the cluster to the replicas.
+func (p *cnpgProvisioner) Resize(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, _ *provisioner.Database, desired provisioner.Resources) error {
+	if desired.AllocatedStorage == 0 && desired.Replicas == 0 {
+		return nil
+	}
+
+	cluster, err := p.cnpg.GetClusterByNamespacedName(ctx, types.NamespacedName{Namespace: node.Namespace, Name: node.Name})
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster %s/%s not found", node.Namespace, node.Name)
+	}
+
+	if desired.AllocatedStorage != 0 {
+		cluster.Spec.StorageConfiguration.Size = storageSize(desired.AllocatedStorage)
+	}
+	if desired.Replicas != 0 {
+		cluster.Spec.Instances = int(desired.Replicas)
+	}
+	return p.cnpg.Update(ctx, cluster)
+}
+
+// CreateSnapshot creates a Backup of the cluster named after the snapshot identifier,
+// which requires the object store of the backups in the parameters of the storage provider.
+func (p *cnpgProvisioner) CreateSnapshot(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) error {
+	backup := &cnpg.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshot.Status.SnapshotIdentifier,
+			Namespace: node.Namespace,
+		},
+		Spec: cnpg.BackupSpec{
+			Cluster: cnpg.LocalObjectReference{Name: node.Name},
+		},
+	}
+	if err := p.cnpg.CreateBackup(ctx, backup); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("create backup %s failed: %w", backup.Name, err)
+	}
+	return nil
+}
+
+func (p *cnpgProvisioner) GetSnapshot(ctx context.Context, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) (*provisioner.Snapshot, error) {
+	backup, err := p.cnpg.GetBackupByNamespacedName(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Status.SnapshotIdentifier})
+	if err != nil || backup == nil {
+		return nil, err
+	}
+
+	s := &provisioner.Snapshot{
+		Status:  string(backup.Status.Phase),
+		Ready:   backup.Status.Phase == cnpg.BackupPhaseCompleted,
+		Failed:  backup.Status.Phase == cnpg.BackupPhaseFailed,
+		Message: backup.Status.Error,
+	}
+	if backup.Status.StoppedAt != nil {
+		s.CreationTime = backup.Status.StoppedAt.Time
+	}
+	return s, nil
+}
+
+// DeleteSnapshot deletes the Backup, the data in the object store is kept by CloudNativePG
+func (p *cnpgProvisioner) DeleteSnapshot(ctx context.Context, _ *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) error {
+	backup, err := p.cnpg.GetBackupByNamespacedName(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Status.SnapshotIdentifier})
+	if err != nil || backup == nil {
+		return err
+	}
+	return client.IgnoreNotFound(p.cnpg.DeleteBackup(ctx, backup))
+}
+
+func (p *cnpgProvisioner) Delete(ctx context.Context, _ *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider, db *provisioner.Database) error {
+	cluster, ok := db.Object.(*cnpg.Cluster)
+	if !ok {
+		return fmt.Errorf("unexpected database object %T", db.Object)
+	}
+	return client.IgnoreNotFound(p.cnpg.Delete(ctx, cluster))
+}
+
+func (p *cnpgProvisioner) Status(_ context.Context, _ *v1alpha1.StorageNode, db *provisioner.Database) (v1alpha1.ClusterStatus, []v1alpha1.InstanceStatus, error) {
+	instances := []v1alpha1.InstanceStatus{}
+	if db == nil {
+		return v1alpha1.ClusterStatus{}, instances, nil
+	}
+
+	cluster, ok := db.Object.(*cnpg.Cluster)
+	if !ok {
+		return v1alpha1.ClusterStatus{}, nil, fmt.Errorf("unexpected database object %T", db.Object)
+	}
+
+	var cs string
+	if cluster.Status.Phase == cnpg.PhaseHealthy {
+		cs = "available"
+	}
+
+	clusterStatus := v1alpha1.ClusterStatus{
+		Status: cs,
+		PrimaryEndpoint: v1alpha1.Endpoint{
+			Address: cluster.Status.WriteService,
+			Port:    defaultPort,
+		},
+		ReaderEndpoints: []v1alpha1.Endpoint{
+			{
+				Address: cluster.Status.ReadService,
+				Port:    defaultPort,
+			},
+		},
+	}
+
+	for s, pgins := range cluster.Status.InstancesStatus {
+		var stat string
+		if s == cnpgutils.PodHealthy {
+			stat = "available"
+		} else {
+			stat = string(s)
+		}
+		for _, pg := range pgins {
+			instances = append(instances, v1alpha1.InstanceStatus{
+				Status: stat,
+				Endpoint: v1alpha1.Endpoint{
+					Address: pg,
+					Port:    defaultPort,
+				},
+			})
+		}
+	}
+
+	// the instances are grouped by a map, sort them to keep the status stable
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Endpoint.Address < instances[j].Endpoint.Address
+	})
+	return clusterStatus, instances, nil
+}
+
+func (p *cnpgProvisioner) Endpoints(node *v1alpha1.StorageNode) (v1alpha1.Endpoint, []v1alpha1.Endpoint) {
+	return node.Status.Cluster.PrimaryEndpoint, node.Status.Cluster.ReaderEndpoints
+}
+
+// Health returns an error if the clusters of CloudNativePG can not be listed, e.g. CloudNativePG is not installed
+func (p *cnpgProvisioner) Health(ctx context.Context) error {
+	if err := p.reader.List(ctx, &cnpg.ClusterList{}, client.Limit(1)); err != nil {
+		return fmt.Errorf("list CloudNativePG clusters failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	EngineMySQL    = "mysql"
+	EnginePostgres = "postgres"
+
+	// parameters of StorageProvider
+	ParamEngine           = "engine"
+	ParamEngineVersion    = "engineVersion"
+	ParamImage            = "image"
+	ParamStorageSize      = "storage.size"
+	ParamStorageClassName = "storage.className"
+	ParamMasterUsername   = "masterUsername"
+	ParamMasterPassword   = "masterUserPassword"
+
+	defaultMySQLVersion    = "5.7"
+	defaultPostgresVersion = "14"
+	defaultStorageSize     = "10Gi"
+
+	dataVolumeName = "data"
+
+	labelStorageNode = "shardingsphere.apache.org/storage-node"
+	labelManagedBy   = "app.kubernetes.io/managed-by"
+	managedBy        = "shardingsphere-operator"
+)
+
+// engineOf returns the engine of the storage provider, which defaults to MySQL
+func engineOf(sp *v1alpha1.StorageProvider) (string, error) {
+	engine := strings.ToLower(sp.Spec.Parameters[ParamEngine])
+	switch {
+	case engine == "", strings.Contains(engine, "mysql"):
+		return EngineMySQL, nil
+	case strings.Contains(engine, "postgres"):
+		return EnginePostgres, nil
+	default:
+		return "", fmt.Errorf("unsupported engine %s", sp.Spec.Parameters[ParamEngine])
+	}
+}
+
+// Port returns the port of the engine
+func Port(engine string) int32 {
+	if engine == EnginePostgres {
+		return 5432
+	}
+	return 3306
+}
+
+// PrimaryAddress returns the address of the only instance of the storage node, resolved by the headless Service
+func PrimaryAddress(node *v1alpha1.StorageNode) string {
+	return fmt.Sprintf("%s-0.%s.%s", node.Name, node.Name, node.Namespace)
+}
+
+// SelectorLabels returns the labels of all the resources of the storage node
+func SelectorLabels(node *v1alpha1.StorageNode) map[string]string {
+	return map[string]string{
+		labelStorageNode: node.Name,
+		labelManagedBy:   managedBy,
+	}
+}
+
+// Username returns the master user of the storage node, which is set by the StorageNode controller
+// from the credentials Secret of the storage node
+func Username(sp *v1alpha1.StorageProvider) string {
+	return sp.Spec.Parameters[ParamMasterUsername]
+}
+
+func objectMeta(node *v1alpha1.StorageNode, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: node.Namespace,
+		Labels:    SelectorLabels(node),
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(node.GetObjectMeta(), v1alpha1.GroupVersion.WithKind("StorageNode")),
+		},
+	}
+}
+
+// NewHeadlessService returns the headless Service giving the instance of the storage node a stable address
+func NewHeadlessService(node *v1alpha1.StorageNode, sp *v1alpha1.StorageProvider) (*corev1.Service, error) {
+	engine, err := engineOf(sp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Service{
+		ObjectMeta: objectMeta(node, node.Name),
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			Selector:                 SelectorLabels(node),
+			PublishNotReadyAddresses: true,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       engine,
+					Port:       Port(engine),
+					TargetPort: intstr.FromInt(int(Port(engine))),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}, nil
+}
+
+// NewStatefulSet returns the StatefulSet running the only instance of the storage node
+func NewStatefulSet(node *v1alpha1.StorageNode, sp *v1alpha1.StorageProvider) (*appsv1.StatefulSet, error) {
+	engine, err := engineOf(sp)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := resource.ParseQuantity(storageSize(sp))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ParamStorageSize, err)
+	}
+
+	envs, err := env(node, sp, engine)
+	if err != nil {
+		return nil, err
+	}
+
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   dataVolumeName,
+			Labels: SelectorLabels(node),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	if className := sp.Spec.Parameters[ParamStorageClassName]; className != "" {
+		pvc.Spec.StorageClassName = pointer.String(className)
+	}
+
+	container := corev1.Container{
+		Name:  engine,
+		Image: Image(sp),
+		Ports: []corev1.ContainerPort{
+			{Name: engine, ContainerPort: Port(engine), Protocol: corev1.ProtocolTCP},
+		},
+		Env: envs,
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(int(Port(engine)))},
+			},
+			InitialDelaySeconds: 10,
+			PeriodSeconds:       5,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: dataVolumeName, MountPath: dataDir(engine)},
+		},
+	}
+
+	return &appsv1.StatefulSet{
+		ObjectMeta: objectMeta(node, node.Name),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    pointer.Int32(1),
+			ServiceName: node.Name,
+			Selector:    &metav1.LabelSelector{MatchLabels: SelectorLabels(node)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: SelectorLabels(node)},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{container},
+				},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{pvc},
+		},
+	}, nil
+}
+
+// Image returns the image of the database, which is decided by the engine and the engine version by default
+func Image(sp *v1alpha1.StorageProvider) string {
+	if image := sp.Spec.Parameters[ParamImage]; image != "" {
+		return image
+	}
+
+	engine, _ := engineOf(sp)
+	version := sp.Spec.Parameters[ParamEngineVersion]
+	if engine == EnginePostgres {
+		if version == "" {
+			version = defaultPostgresVersion
+		}
+		return fmt.Sprintf("postgres:%s", version)
+	}
+	if version == "" {
+		version = defaultMySQLVersion
+	}
+	return fmt.Sprintf("mysql:%s", version)
+}
+
+func storageSize(sp *v1alpha1.StorageProvider) string {
+	if size := sp.Spec.Parameters[ParamStorageSize]; size != "" {
+		return size
+	}
+	return defaultStorageSize
+}
+
+func dataDir(engine string) string {
+	if engine == EnginePostgres {
+		return "/var/lib/postgresql/data"
+	}
+	return "/var/lib/mysql"
+}
+
+func passwordEnv(name, secretName string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  v1alpha1.StorageNodeCredentialsPasswordKey,
+			},
+		},
+	}
+}
+
+// env returns the environments initializing the master user and the database of the official images,
+// the password is read from the credentials Secret of the storage node
+func env(node *v1alpha1.StorageNode, sp *v1alpha1.StorageProvider, engine string) ([]corev1.EnvVar, error) {
+	if node.Status.CredentialsSecretRef == nil {
+		return nil, fmt.Errorf("credentials secret of %s/%s not set", node.Namespace, node.Name)
+	}
+	secretName := node.Status.CredentialsSecretRef.Name
+	username := Username(sp)
+	if username == "" {
+		return nil, fmt.Errorf("%s is required", ParamMasterUsername)
+	}
+	dbName := node.Annotations[v1alpha1.AnnotationsInstanceDBName]
+
+	if engine == EnginePostgres {
+		envs := []corev1.EnvVar{
+			{Name: "POSTGRES_USER", Value: username},
+			passwordEnv("POSTGRES_PASSWORD", secretName),
+			// the mount point of the volume is not empty, so the data lives in a sub directory
+			{Name: "PGDATA", Value: fmt.Sprintf("%s/pgdata", dataDir(engine))},
+		}
+		if dbName != "" {
+			envs = append(envs, corev1.EnvVar{Name: "POSTGRES_DB", Value: dbName})
+		}
+		return envs, nil
+	}
+
+	// root is always created by the MySQL image, the master user is created besides it with the same password
+	envs := []corev1.EnvVar{
+		passwordEnv("MYSQL_ROOT_PASSWORD", secretName),
+	}
+	if username != "root" {
+		envs = append(envs,
+			corev1.EnvVar{Name: "MYSQL_USER", Value: username},
+			passwordEnv("MYSQL_PASSWORD", secretName),
+		)
+	}
+	if dbName != "" {
+		envs = append(envs, corev1.EnvVar{Name: "MYSQL_DATABASE", Value: dbName})
+	}
+	return envs, nil
+}
@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type localProvisioner struct {
+	client.Client
+}
+
+var _ provisioner.Provisioner = (*localProvisioner)(nil)
+
+// NewProvisioner returns the provisioner running the databases with StatefulSets in the cluster,
+// which is meant for development and CI clusters without cloud databases
+func NewProvisioner(c client.Client) provisioner.Provisioner {
+	return &localProvisioner{Client: c}
+}
+
+func (p *localProvisioner) Get(ctx context.Context, node *v1alpha1.StorageNode, _ *v1alpha1.StorageProvider) (*provisioner.Database, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := p.Client.Get(ctx, types.NamespacedName{Namespace: node.Namespace, Name: node.Name}, sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &provisioner.Database{
+		Deleting: !sts.DeletionTimestamp.IsZero(),
+		Object:   sts,
+	}, nil
+}
+
+func (p *localProvisioner) Create(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error {
+	svc, err := NewHeadlessService(node, storageProvider)
+	if err != nil {
+		return err
+	}
+	sts, err := NewStatefulSet(node, storageProvider)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range []client.Object{svc, sts} {
+		if err := p.Client.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("create %s %s/%s failed: %w", reflect.TypeOf(obj).Elem().Name(), obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// Update keeps the database container of the StatefulSet up to date, e.g. upgrades the engine version
+func (p *localProvisioner) Update(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, db *provisioner.Database) error {
+	sts, ok := db.Object.(*appsv1.StatefulSet)
+	if !ok {
+		return fmt.Errorf("unexpected database object %T", db.Object)
+	}
+
+	exp, err := NewStatefulSet(node, storageProvider)
+	if err != nil {
+		return err
+	}
+	if len(sts.Spec.Template.Spec.Containers) == 0 {
+		return fmt.Errorf("no container in StatefulSet %s/%s", sts.Namespace, sts.Name)
+	}
+
+	expContainer := exp.Spec.Template.Spec.Containers[0]
+	container := &sts.Spec.Template.Spec.Containers[0]
+	if container.Image == expContainer.Image && reflect.DeepEqual(container.Env, expContainer.Env) {
+		return nil
+	}
+
+	container.Image = expContainer.Image
+	container.Env = expContainer.Env
+	return p.Client.Update(ctx, sts)
+}
+
+// Delete deletes the StatefulSet and the Service of the storage node, the credentials Secret is owned by the storage node.
+// The volumes are deleted only with the Delete reclaim policy, as there is no snapshot for them.
+func (p *localProvisioner) Delete(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, db *provisioner.Database) error {
+	meta := metav1.ObjectMeta{Namespace: node.Namespace, Name: node.Name}
+	objs := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: meta},
+		&corev1.Service{ObjectMeta: meta},
+	}
+	for _, obj := range objs {
+		if err := p.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete %s %s/%s failed: %w", reflect.TypeOf(obj).Elem().Name(), obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+
+	if storageProvider.Spec.ReclaimPolicy == v1alpha1.StorageReclaimPolicyDelete {
+		if err := p.Client.DeleteAllOf(ctx, &corev1.PersistentVolumeClaim{}, client.InNamespace(node.Namespace), client.MatchingLabels(SelectorLabels(node))); err != nil {
+			return fmt.Errorf("delete volumes of %s/%s failed: %w", node.Namespace, node.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *localProvisioner) Status(ctx context.Context, node *v1alpha1.StorageNode, db *provisioner.Database) (v1alpha1.ClusterStatus, []v1alpha1.InstanceStatus, error) {
+	instances := []v1alpha1.InstanceStatus{}
+	if db == nil {
+		return v1alpha1.ClusterStatus{}, instances, nil
+	}
+
+	sts, ok := db.Object.(*appsv1.StatefulSet)
+	if !ok {
+		return v1alpha1.ClusterStatus{}, nil, fmt.Errorf("unexpected database object %T", db.Object)
+	}
+
+	port := int32(0)
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		for _, cp := range c.Ports {
+			port = cp.ContainerPort
+		}
+	}
+
+	var cs string
+	if sts.Spec.Replicas != nil && *sts.Spec.Replicas > 0 && sts.Status.ReadyReplicas == *sts.Spec.Replicas {
+		cs = "available"
+	}
+
+	clusterStatus := v1alpha1.ClusterStatus{
+		Status: cs,
+		PrimaryEndpoint: v1alpha1.Endpoint{
+			Address: PrimaryAddress(node),
+			Port:    port,
+		},
+	}
+
+	pods := &corev1.PodList{}
+	if err := p.Client.List(ctx, pods, client.InNamespace(node.Namespace), client.MatchingLabels(SelectorLabels(node))); err != nil {
+		return clusterStatus, nil, fmt.Errorf("list pods failed: %w", err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		stat := strings.ToLower(string(pod.Status.Phase))
+		if podReady(pod) {
+			stat = "available"
+		}
+		instances = append(instances, v1alpha1.InstanceStatus{
+			Status: stat,
+			Endpoint: v1alpha1.Endpoint{
+				Address: fmt.Sprintf("%s.%s.%s", pod.Name, sts.Spec.ServiceName, pod.Namespace),
+				Port:    port,
+			},
+		})
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Endpoint.Address < instances[j].Endpoint.Address
+	})
+	return clusterStatus, instances, nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	if !pod.DeletionTimestamp.IsZero() {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Endpoints returns the only instance as the primary, there is no reader as the instance is not replicated
+func (p *localProvisioner) Endpoints(node *v1alpha1.StorageNode) (v1alpha1.Endpoint, []v1alpha1.Endpoint) {
+	return node.Status.Cluster.PrimaryEndpoint, nil
+}
+
+// Health returns an error if the StatefulSets can not be listed, e.g. the operator is not permitted to
+func (p *localProvisioner) Health(ctx context.Context) error {
+	if err := p.Client.List(ctx, &appsv1.StatefulSetList{}, client.Limit(1)); err != nil {
+		return fmt.Errorf("list StatefulSets failed: %w", err)
+	}
+	return nil
+}
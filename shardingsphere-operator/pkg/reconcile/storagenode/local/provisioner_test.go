@@ -0,0 +1,204 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Local StatefulSet Provisioner", func() {
+	var (
+		ctx  = context.Background()
+		c    client.Client
+		p    provisioner.Provisioner
+		node *v1alpha1.StorageNode
+		sp   *v1alpha1.StorageProvider
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+		p = NewProvisioner(c)
+
+		node = &v1alpha1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-local",
+				Namespace: "default",
+				UID:       "test-uid",
+				Annotations: map[string]string{
+					v1alpha1.AnnotationsInstanceDBName: "test_db",
+				},
+			},
+			Status: v1alpha1.StorageNodeStatus{
+				CredentialsSecretRef: &corev1.LocalObjectReference{Name: "test-local-credentials"},
+			},
+		}
+		sp = &v1alpha1.StorageProvider{
+			Spec: v1alpha1.StorageProviderSpec{
+				Provisioner: v1alpha1.ProvisionerLocalStatefulSet,
+				Parameters: map[string]string{
+					"engine":             "mysql",
+					"engineVersion":      "8.0",
+					"storage.size":       "1Gi",
+					"masterUsername":     "user",
+					"masterUserPassword": "password",
+				},
+			},
+		}
+	})
+
+	Context("Test builders", func() {
+		It("should build the StatefulSet of MySQL", func() {
+			sts, err := NewStatefulSet(node, sp)
+			Expect(err).To(BeNil())
+			Expect(sts.Spec.ServiceName).To(Equal(node.Name))
+			Expect(*sts.Spec.Replicas).To(Equal(int32(1)))
+			Expect(sts.OwnerReferences).To(HaveLen(1))
+			Expect(sts.OwnerReferences[0].Kind).To(Equal("StorageNode"))
+
+			container := sts.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("mysql:8.0"))
+			Expect(container.Ports[0].ContainerPort).To(Equal(int32(3306)))
+			var names []string
+			for _, e := range container.Env {
+				names = append(names, e.Name)
+			}
+			Expect(names).To(Equal([]string{"MYSQL_ROOT_PASSWORD", "MYSQL_USER", "MYSQL_PASSWORD", "MYSQL_DATABASE"}))
+			Expect(container.Env[0].ValueFrom.SecretKeyRef).To(Equal(&corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "test-local-credentials"},
+				Key:                  v1alpha1.StorageNodeCredentialsPasswordKey,
+			}))
+			Expect(container.Env[1].Value).To(Equal("user"))
+
+			Expect(sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+			Expect(sts.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(BeNil())
+		})
+
+		It("should build the StatefulSet of PostgreSQL", func() {
+			sp.Spec.Parameters["engine"] = "postgres"
+			sp.Spec.Parameters["engineVersion"] = ""
+			sp.Spec.Parameters["storage.className"] = "standard"
+
+			sts, err := NewStatefulSet(node, sp)
+			Expect(err).To(BeNil())
+			container := sts.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("postgres:14"))
+			Expect(container.Ports[0].ContainerPort).To(Equal(int32(5432)))
+			Expect(container.Env[0]).To(Equal(corev1.EnvVar{Name: "POSTGRES_USER", Value: "user"}))
+			Expect(container.Env[1].ValueFrom.SecretKeyRef.Name).To(Equal("test-local-credentials"))
+			Expect(*sts.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(Equal("standard"))
+
+			svc, err := NewHeadlessService(node, sp)
+			Expect(err).To(BeNil())
+			Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			Expect(svc.Spec.Ports[0].Port).To(Equal(int32(5432)))
+		})
+
+		It("should fail with unsupported engine or without credentials", func() {
+			sp.Spec.Parameters["engine"] = "oracle"
+			_, err := NewStatefulSet(node, sp)
+			Expect(err).ToNot(BeNil())
+
+			sp.Spec.Parameters["engine"] = "mysql"
+			node.Status.CredentialsSecretRef = nil
+			_, err = NewStatefulSet(node, sp)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	It("should create, report and delete the database", func() {
+		db, err := p.Get(ctx, node, sp)
+		Expect(err).To(BeNil())
+		Expect(db).To(BeNil())
+
+		Expect(p.Create(ctx, node, sp)).To(Succeed())
+		// create is idempotent
+		Expect(p.Create(ctx, node, sp)).To(Succeed())
+
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: node.Name}, &corev1.Service{})).To(Succeed())
+
+		db, err = p.Get(ctx, node, sp)
+		Expect(err).To(BeNil())
+		Expect(db.Deleting).To(BeFalse())
+
+		// the instance is not ready
+		cs, instances, err := p.Status(ctx, node, db)
+		Expect(err).To(BeNil())
+		Expect(cs.Status).To(BeEmpty())
+		Expect(cs.PrimaryEndpoint).To(Equal(v1alpha1.Endpoint{Address: "test-local-0.test-local.default", Port: 3306}))
+		Expect(instances).To(BeEmpty())
+
+		// the instance is ready
+		sts := db.Object.(*appsv1.StatefulSet)
+		sts.Status.ReadyReplicas = 1
+		Expect(c.Status().Update(ctx, sts)).To(Succeed())
+		Expect(c.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-local-0", Namespace: "default", Labels: SelectorLabels(node)},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		})).To(Succeed())
+
+		db, err = p.Get(ctx, node, sp)
+		Expect(err).To(BeNil())
+		cs, instances, err = p.Status(ctx, node, db)
+		Expect(err).To(BeNil())
+		Expect(cs.Status).To(Equal("available"))
+		Expect(instances).To(Equal([]v1alpha1.InstanceStatus{
+			{Status: "available", Endpoint: v1alpha1.Endpoint{Address: "test-local-0.test-local.default", Port: 3306}},
+		}))
+
+		// the engine version is upgraded
+		sp.Spec.Parameters["engineVersion"] = "8.0.33"
+		Expect(p.Update(ctx, node, sp, db)).To(Succeed())
+		sts = &appsv1.StatefulSet{}
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: node.Name}, sts)).To(Succeed())
+		Expect(sts.Spec.Template.Spec.Containers[0].Image).To(Equal("mysql:8.0.33"))
+
+		// the volumes are deleted with the Delete reclaim policy
+		Expect(c.Create(ctx, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-test-local-0", Namespace: "default", Labels: SelectorLabels(node)},
+		})).To(Succeed())
+		sp.Spec.ReclaimPolicy = v1alpha1.StorageReclaimPolicyDelete
+		Expect(p.Delete(ctx, node, sp, db)).To(Succeed())
+
+		db, err = p.Get(ctx, node, sp)
+		Expect(err).To(BeNil())
+		Expect(db).To(BeNil())
+		pvcs := &corev1.PersistentVolumeClaimList{}
+		Expect(c.List(ctx, pvcs)).To(Succeed())
+		Expect(pvcs.Items).To(BeEmpty())
+	})
+})
@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+)
+
+// Database is the database of a StorageNode as described by its provisioner
+type Database struct {
+	// Deleting is true when the database is being deleted
+	Deleting bool
+	// Object is the description of the database in the backend, e.g. *rds.DescInstance
+	Object any
+}
+
+// Provisioner provisions the databases of the StorageNodes whose StorageProvider uses it
+type Provisioner interface {
+	// Get returns the database of the storage node, or nil if it does not exist
+	Get(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) (*Database, error)
+	// Create creates the database of the storage node
+	Create(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider) error
+	// Update makes the existing database match the storage node and the storage provider
+	Update(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, db *Database) error
+	// Delete deletes the database, the reclaim policy of the storage provider is respected
+	Delete(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, db *Database) error
+	// Status returns the cluster status and the instances status of the database, db is nil if it does not exist
+	Status(ctx context.Context, node *v1alpha1.StorageNode, db *Database) (v1alpha1.ClusterStatus, []v1alpha1.InstanceStatus, error)
+	// Endpoints returns the primary endpoint and the reader endpoints of the storage node by its status
+	Endpoints(node *v1alpha1.StorageNode) (primary v1alpha1.Endpoint, readers []v1alpha1.Endpoint)
+	// Health returns an error if the provisioner can not work, e.g. the credentials are not set
+	Health(ctx context.Context) error
+}
+
+// Resources are the resources of a database which are resized with the spec of the StorageNode
+type Resources struct {
+	InstanceClass string
+	// AllocatedStorage is the storage in GiB
+	AllocatedStorage int32
+	IOPS             int32
+	Replicas         int32
+}
+
+// DesiredResources returns the resources in the spec of the storage node, zero values are not managed by it
+func DesiredResources(node *v1alpha1.StorageNode) Resources {
+	return Resources{
+		InstanceClass:    node.Spec.InstanceClass,
+		AllocatedStorage: node.Spec.AllocatedStorage,
+		IOPS:             node.Spec.IOPS,
+		Replicas:         node.Spec.Replicas,
+	}
+}
+
+// Resizer is implemented by the provisioners whose databases are resized with the spec of the StorageNode
+type Resizer interface {
+	// Resources returns the current resources of the database and whether it is being resized.
+	// The resources not managed by the provisioner are zero.
+	Resources(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, db *Database) (current Resources, resizing bool, err error)
+	// Resize starts resizing the database to the desired resources
+	Resize(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, db *Database, desired Resources) error
+}
+
+// Snapshot is a snapshot of a database as described by its provisioner
+type Snapshot struct {
+	// Status is the status of the snapshot in the backend, e.g. available
+	Status string
+	// Ready is true when the snapshot can be restored
+	Ready bool
+	// Failed is true when the snapshot will never be ready
+	Failed bool
+	// Message is the reason of the failure
+	Message string
+	// CreationTime is zero until the snapshot is taken
+	CreationTime time.Time
+	// AllocatedStorage is the storage in GiB, zero if it is unknown
+	AllocatedStorage int32
+}
+
+// Snapshotter is implemented by the provisioners taking snapshots of the databases.
+// The snapshot is identified by the status of the StorageNodeSnapshot, and the database created with
+// the annotation `storageproviders.shardingsphere.apache.org/snapshot-identifier` is restored from it.
+type Snapshotter interface {
+	// CreateSnapshot starts taking the snapshot of the database of the storage node
+	CreateSnapshot(ctx context.Context, node *v1alpha1.StorageNode, storageProvider *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) error
+	// GetSnapshot returns the snapshot, or nil if it does not exist
+	GetSnapshot(ctx context.Context, storageProvider *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) (*Snapshot, error)
+	// DeleteSnapshot deletes the snapshot, it is not an error if the snapshot does not exist
+	DeleteSnapshot(ctx context.Context, storageProvider *v1alpha1.StorageProvider, snapshot *v1alpha1.StorageNodeSnapshot) error
+}
+
+// Registry holds the provisioners keyed by the provisioner of StorageProvider
+type Registry struct {
+	mu           sync.RWMutex
+	provisioners map[string]Provisioner
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		provisioners: map[string]Provisioner{},
+	}
+}
+
+// Register registers the provisioner with the name, the previous one with the same name is replaced
+func (r *Registry) Register(name string, p Provisioner) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.provisioners[name] = p
+}
+
+// Get returns the provisioner with the name
+func (r *Registry) Get(name string) (Provisioner, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.provisioners[name]
+	return p, ok
+}
+
+// Names returns the sorted names of all the registered provisioners
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.provisioners))
+	for name := range r.provisioners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Changes returns the changes from the current resources to the desired ones, e.g. "instanceClass: db.t3.micro -> db.t3.small".
+// The zero desired resources and the replicas not managed by the provisioner are ignored.
+func Changes(current, desired Resources) []string {
+	var changes []string
+	if desired.InstanceClass != "" && desired.InstanceClass != current.InstanceClass {
+		changes = append(changes, fmt.Sprintf("instanceClass: %s -> %s", current.InstanceClass, desired.InstanceClass))
+	}
+	if desired.AllocatedStorage != 0 && desired.AllocatedStorage != current.AllocatedStorage {
+		changes = append(changes, fmt.Sprintf("allocatedStorage: %dGi -> %dGi", current.AllocatedStorage, desired.AllocatedStorage))
+	}
+	if desired.IOPS != 0 && desired.IOPS != current.IOPS {
+		changes = append(changes, fmt.Sprintf("iops: %d -> %d", current.IOPS, desired.IOPS))
+	}
+	if desired.Replicas != 0 && current.Replicas != 0 && desired.Replicas != current.Replicas {
+		changes = append(changes, fmt.Sprintf("replicas: %d -> %d", current.Replicas, desired.Replicas))
+	}
+	return changes
+}
@@ -0,0 +1,230 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shardingsphere
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	// DistSQLLockClusterForBackup stops writing and locks the CSN of the cluster, which is used for backups.
+	DistSQLLockClusterForBackup = `LOCK CLUSTER WITH LOCK_STRATEGY(TYPE(NAME="WRITE", PROPERTIES("lock_csn"=true)));`
+	// DistSQLUnlockCluster unlocks the cluster.
+	DistSQLUnlockCluster = `UNLOCK CLUSTER;`
+	// DistSQLExportMetadata exports the metadata of the cluster as base64 encoded JSON.
+	DistSQLExportMetadata = `EXPORT METADATA;`
+	// DistSQLExportStorageNodes exports the storage nodes of all the logic databases as JSON.
+	DistSQLExportStorageNodes = `EXPORT STORAGE NODES;`
+	// DistSQLImportMetadata imports the base64 encoded metadata exported by `EXPORT METADATA`.
+	DistSQLImportMetadata = `IMPORT METADATA '%s';`
+	// DistSQLDropDatabase drops the logic database.
+	DistSQLDropDatabase = `DROP DATABASE %s;`
+)
+
+// ClusterMetadata is the result of `EXPORT METADATA`
+type ClusterMetadata struct {
+	ID         string
+	CreateTime string
+	// Data is the base64 encoded JSON, which is imported as it is
+	Data string
+	// CSN is the commit sequence number locked by `LOCK CLUSTER`, it is empty if the CSN is not locked
+	CSN string
+	// Databases are the names of the logic databases in the metadata
+	Databases []string
+}
+
+// ExportedStorageNode is a storage node returned by `EXPORT STORAGE NODES`
+type ExportedStorageNode struct {
+	IP       string `json:"ip"`
+	Port     uint16 `json:"port,string"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Database string `json:"database"`
+	Remark   string `json:"remark,omitempty"`
+}
+
+// clusterInfo is the decoded data of `EXPORT METADATA`
+type clusterInfo struct {
+	MetaData struct {
+		Databases map[string]string `json:"databases"`
+	} `json:"meta_data"`
+	SnapshotInfo *struct {
+		Csn string `json:"csn"`
+	} `json:"snapshot_info,omitempty"`
+}
+
+func lockClusterForBackup(db *sql.DB) error {
+	if _, err := db.Exec(DistSQLLockClusterForBackup); err != nil {
+		return fmt.Errorf("lock cluster error: %w", err)
+	}
+	return nil
+}
+
+func unlockCluster(db *sql.DB) error {
+	if _, err := db.Exec(DistSQLUnlockCluster); err != nil {
+		return fmt.Errorf("unlock cluster error: %w", err)
+	}
+	return nil
+}
+
+// exportRow returns the columns id, create_time and data of the single row exported
+func exportRow(db *sql.DB, distSQL string) (id, createTime, data string, err error) {
+	rows, err := db.Query(distSQL)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&id, &createTime, &data); err != nil {
+			return "", "", "", fmt.Errorf("scan error: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", "", "", fmt.Errorf("rows error: %w", err)
+	}
+	return id, createTime, data, nil
+}
+
+func exportMetadata(db *sql.DB) (*ClusterMetadata, error) {
+	id, createTime, data, err := exportRow(db, DistSQLExportMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("export metadata error: %w", err)
+	}
+	return parseClusterMetadata(id, createTime, data)
+}
+
+func parseClusterMetadata(id, createTime, data string) (*ClusterMetadata, error) {
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode metadata error: %w", err)
+	}
+	info := &clusterInfo{}
+	if err := json.Unmarshal(decoded, info); err != nil {
+		return nil, fmt.Errorf("unmarshal metadata error: %w", err)
+	}
+
+	md := &ClusterMetadata{ID: id, CreateTime: createTime, Data: data}
+	if info.SnapshotInfo != nil {
+		md.CSN = info.SnapshotInfo.Csn
+	}
+	for name := range info.MetaData.Databases {
+		md.Databases = append(md.Databases, name)
+	}
+	sort.Strings(md.Databases)
+	return md, nil
+}
+
+func exportStorageNodes(db *sql.DB) ([]*ExportedStorageNode, error) {
+	_, _, data, err := exportRow(db, DistSQLExportStorageNodes)
+	if err != nil {
+		return nil, fmt.Errorf("export storage nodes error: %w", err)
+	}
+	return parseStorageNodes(data)
+}
+
+// parseStorageNodes returns the storage nodes of all the logic databases, the nodes shared by logic databases are returned once.
+func parseStorageNodes(data string) ([]*ExportedStorageNode, error) {
+	out := struct {
+		StorageNodes map[string][]*ExportedStorageNode `json:"storage_nodes"`
+	}{}
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		return nil, fmt.Errorf("unmarshal storage nodes error: %w", err)
+	}
+
+	dbNames := make([]string, 0, len(out.StorageNodes))
+	for name := range out.StorageNodes {
+		dbNames = append(dbNames, name)
+	}
+	sort.Strings(dbNames)
+
+	nodes := make([]*ExportedStorageNode, 0)
+	seen := map[string]bool{}
+	for _, name := range dbNames {
+		for _, node := range out.StorageNodes[name] {
+			addr := fmt.Sprintf("%s:%d", node.IP, node.Port)
+			if seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+func importMetadata(db *sql.DB, data string) error {
+	if _, err := db.Exec(fmt.Sprintf(DistSQLImportMetadata, data)); err != nil {
+		return fmt.Errorf("import metadata error: %w", err)
+	}
+	return nil
+}
+
+func dropDatabase(db *sql.DB, dbName string) error {
+	if _, err := db.Exec(fmt.Sprintf(DistSQLDropDatabase, dbName)); err != nil {
+		return fmt.Errorf("drop database error: %w", err)
+	}
+	return nil
+}
+
+// LockClusterForBackup stops writing to the cluster and locks the CSN, so the storage nodes are backed up consistently.
+func (s *server) LockClusterForBackup() error { return lockClusterForBackup(s.db) }
+
+// UnlockCluster unlocks the cluster locked by LockClusterForBackup.
+func (s *server) UnlockCluster() error { return unlockCluster(s.db) }
+
+// ExportMetadata exports the metadata of the cluster.
+func (s *server) ExportMetadata() (*ClusterMetadata, error) { return exportMetadata(s.db) }
+
+// ExportStorageNodes exports the storage nodes of all the logic databases.
+func (s *server) ExportStorageNodes() ([]*ExportedStorageNode, error) {
+	return exportStorageNodes(s.db)
+}
+
+// ImportMetadata imports the metadata exported by ExportMetadata.
+func (s *server) ImportMetadata(data string) error { return importMetadata(s.db, data) }
+
+// DropDatabase drops the logic database.
+func (s *server) DropDatabase(dbName string) error { return dropDatabase(s.db, dbName) }
+
+func (s *postgresServer) LockClusterForBackup() error { return lockClusterForBackup(s.db) }
+
+func (s *postgresServer) UnlockCluster() error { return unlockCluster(s.db) }
+
+func (s *postgresServer) ExportMetadata() (*ClusterMetadata, error) { return exportMetadata(s.db) }
+
+func (s *postgresServer) ExportStorageNodes() ([]*ExportedStorageNode, error) {
+	return exportStorageNodes(s.db)
+}
+
+func (s *postgresServer) ImportMetadata(data string) error { return importMetadata(s.db, data) }
+
+// DropDatabase drops the logic database, the connection opened on it is closed first.
+func (s *postgresServer) DropDatabase(dbName string) error {
+	s.mu.Lock()
+	if db, ok := s.dbs[dbName]; ok {
+		_ = db.Close()
+		delete(s.dbs, dbName)
+	}
+	s.mu.Unlock()
+	return dropDatabase(s.db, dbName)
+}
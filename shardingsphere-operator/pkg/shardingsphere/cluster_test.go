@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shardingsphere
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test the backup and restore of the cluster", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		err  error
+		s    IServer
+	)
+
+	BeforeEach(func() {
+		db, mock, err = sqlmock.New()
+		Expect(err).ShouldNot(HaveOccurred())
+		s = &server{db: db}
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		db.Close()
+	})
+
+	It("should lock and unlock the cluster", func() {
+		mock.ExpectExec(regexp.QuoteMeta(DistSQLLockClusterForBackup)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(DistSQLUnlockCluster)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(s.LockClusterForBackup()).Should(Succeed())
+		Expect(s.UnlockCluster()).Should(Succeed())
+	})
+
+	It("should export the metadata with the CSN and the sorted databases", func() {
+		data := base64.StdEncoding.EncodeToString([]byte(`{"meta_data":{"databases":{"sharding_db":"","a_db":""}},"snapshot_info":{"csn":"16842752"}}`))
+		mock.ExpectQuery(regexp.QuoteMeta(DistSQLExportMetadata)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "create_time", "cluster_info"}).AddRow("id-0", "2023-05-01 00:00:00", data))
+
+		md, err := s.ExportMetadata()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(md).To(Equal(&ClusterMetadata{
+			ID:         "id-0",
+			CreateTime: "2023-05-01 00:00:00",
+			Data:       data,
+			CSN:        "16842752",
+			Databases:  []string{"a_db", "sharding_db"},
+		}))
+	})
+
+	It("should export the storage nodes shared by the databases once", func() {
+		data := `{"storage_nodes":{
+			"sharding_db":[{"ip":"10.0.0.1","port":"5432","username":"gaussdb","password":"p","database":"ds_0"},{"ip":"10.0.0.2","port":"5432","username":"gaussdb","password":"p","database":"ds_1"}],
+			"a_db":[{"ip":"10.0.0.2","port":"5432","username":"gaussdb","password":"p","database":"ds_1"}]}}`
+		mock.ExpectQuery(regexp.QuoteMeta(DistSQLExportStorageNodes)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "create_time", "storage_nodes"}).AddRow("id-0", "2023-05-01 00:00:00", data))
+
+		nodes, err := s.ExportStorageNodes()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodes).To(Equal([]*ExportedStorageNode{
+			{IP: "10.0.0.2", Port: 5432, Username: "gaussdb", Password: "p", Database: "ds_1"},
+			{IP: "10.0.0.1", Port: 5432, Username: "gaussdb", Password: "p", Database: "ds_0"},
+		}))
+	})
+
+	It("should drop the databases and import the metadata", func() {
+		mock.ExpectExec(regexp.QuoteMeta("DROP DATABASE sharding_db;")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("IMPORT METADATA 'eyJ9';")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(s.DropDatabase("sharding_db")).Should(Succeed())
+		Expect(s.ImportMetadata("eyJ9")).Should(Succeed())
+	})
+})
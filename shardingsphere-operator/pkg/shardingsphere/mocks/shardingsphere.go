@@ -18,12 +18,15 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: shardingsphere.go
 
-// Package mock_shardingsphere is a generated GoMock package.
-package mock_shardingsphere
+// Package mocks is a generated GoMock package.
+package mocks
 
 import (
+	context "context"
+	sql "database/sql"
 	reflect "reflect"
 
+	shardingsphere "github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/shardingsphere"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -50,6 +53,20 @@ func (m *MockIServer) EXPECT() *MockIServerMockRecorder {
 	return m.recorder
 }
 
+// AlterStorageUnit mocks base method.
+func (m *MockIServer) AlterStorageUnit(logicDBName, dsName, dsHost string, dsPort uint, dsDBName, dsUser, dsPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AlterStorageUnit", logicDBName, dsName, dsHost, dsPort, dsDBName, dsUser, dsPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AlterStorageUnit indicates an expected call of AlterStorageUnit.
+func (mr *MockIServerMockRecorder) AlterStorageUnit(logicDBName, dsName, dsHost, dsPort, dsDBName, dsUser, dsPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlterStorageUnit", reflect.TypeOf((*MockIServer)(nil).AlterStorageUnit), logicDBName, dsName, dsHost, dsPort, dsDBName, dsUser, dsPassword)
+}
+
 // Close mocks base method.
 func (m *MockIServer) Close() error {
 	m.ctrl.T.Helper()
@@ -78,6 +95,125 @@ func (mr *MockIServerMockRecorder) CreateDatabase(dbName interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDatabase", reflect.TypeOf((*MockIServer)(nil).CreateDatabase), dbName)
 }
 
+// DropDatabase mocks base method.
+func (m *MockIServer) DropDatabase(dbName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropDatabase", dbName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropDatabase indicates an expected call of DropDatabase.
+func (mr *MockIServerMockRecorder) DropDatabase(dbName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropDatabase", reflect.TypeOf((*MockIServer)(nil).DropDatabase), dbName)
+}
+
+// DropRule mocks base method.
+func (m *MockIServer) DropRule(logicDBName, ruleType, ruleName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropRule", logicDBName, ruleType, ruleName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropRule indicates an expected call of DropRule.
+func (mr *MockIServerMockRecorder) DropRule(logicDBName, ruleType, ruleName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropRule", reflect.TypeOf((*MockIServer)(nil).DropRule), logicDBName, ruleType, ruleName)
+}
+
+// ExecuteDistSQL mocks base method.
+func (m *MockIServer) ExecuteDistSQL(logicDBName string, distSQLs ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{logicDBName}
+	for _, a := range distSQLs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecuteDistSQL", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteDistSQL indicates an expected call of ExecuteDistSQL.
+func (mr *MockIServerMockRecorder) ExecuteDistSQL(logicDBName interface{}, distSQLs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{logicDBName}, distSQLs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteDistSQL", reflect.TypeOf((*MockIServer)(nil).ExecuteDistSQL), varargs...)
+}
+
+// ExportMetadata mocks base method.
+func (m *MockIServer) ExportMetadata() (*shardingsphere.ClusterMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportMetadata")
+	ret0, _ := ret[0].(*shardingsphere.ClusterMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportMetadata indicates an expected call of ExportMetadata.
+func (mr *MockIServerMockRecorder) ExportMetadata() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportMetadata", reflect.TypeOf((*MockIServer)(nil).ExportMetadata))
+}
+
+// ExportStorageNodes mocks base method.
+func (m *MockIServer) ExportStorageNodes() ([]*shardingsphere.ExportedStorageNode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportStorageNodes")
+	ret0, _ := ret[0].([]*shardingsphere.ExportedStorageNode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportStorageNodes indicates an expected call of ExportStorageNodes.
+func (mr *MockIServerMockRecorder) ExportStorageNodes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportStorageNodes", reflect.TypeOf((*MockIServer)(nil).ExportStorageNodes))
+}
+
+// ImportMetadata mocks base method.
+func (m *MockIServer) ImportMetadata(data string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportMetadata", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportMetadata indicates an expected call of ImportMetadata.
+func (mr *MockIServerMockRecorder) ImportMetadata(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportMetadata", reflect.TypeOf((*MockIServer)(nil).ImportMetadata), data)
+}
+
+// LockClusterForBackup mocks base method.
+func (m *MockIServer) LockClusterForBackup() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockClusterForBackup")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockClusterForBackup indicates an expected call of LockClusterForBackup.
+func (mr *MockIServerMockRecorder) LockClusterForBackup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockClusterForBackup", reflect.TypeOf((*MockIServer)(nil).LockClusterForBackup))
+}
+
+// PingStorageUnit mocks base method.
+func (m *MockIServer) PingStorageUnit(logicDBName, dsName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingStorageUnit", logicDBName, dsName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PingStorageUnit indicates an expected call of PingStorageUnit.
+func (mr *MockIServerMockRecorder) PingStorageUnit(logicDBName, dsName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingStorageUnit", reflect.TypeOf((*MockIServer)(nil).PingStorageUnit), logicDBName, dsName)
+}
+
 // RegisterStorageUnit mocks base method.
 func (m *MockIServer) RegisterStorageUnit(logicDBName, dsName, dsHost string, dsPort uint, dsDBName, dsUser, dsPassword string) error {
 	m.ctrl.T.Helper()
@@ -92,6 +228,81 @@ func (mr *MockIServerMockRecorder) RegisterStorageUnit(logicDBName, dsName, dsHo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterStorageUnit", reflect.TypeOf((*MockIServer)(nil).RegisterStorageUnit), logicDBName, dsName, dsHost, dsPort, dsDBName, dsUser, dsPassword)
 }
 
+// ShowComputeNodes mocks base method.
+func (m *MockIServer) ShowComputeNodes() ([]*shardingsphere.ComputeNodeInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowComputeNodes")
+	ret0, _ := ret[0].([]*shardingsphere.ComputeNodeInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowComputeNodes indicates an expected call of ShowComputeNodes.
+func (mr *MockIServerMockRecorder) ShowComputeNodes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowComputeNodes", reflect.TypeOf((*MockIServer)(nil).ShowComputeNodes))
+}
+
+// ShowDatabases mocks base method.
+func (m *MockIServer) ShowDatabases() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowDatabases")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowDatabases indicates an expected call of ShowDatabases.
+func (mr *MockIServerMockRecorder) ShowDatabases() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowDatabases", reflect.TypeOf((*MockIServer)(nil).ShowDatabases))
+}
+
+// ShowRules mocks base method.
+func (m *MockIServer) ShowRules(logicDBName, ruleType string) ([]*shardingsphere.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowRules", logicDBName, ruleType)
+	ret0, _ := ret[0].([]*shardingsphere.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowRules indicates an expected call of ShowRules.
+func (mr *MockIServerMockRecorder) ShowRules(logicDBName, ruleType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowRules", reflect.TypeOf((*MockIServer)(nil).ShowRules), logicDBName, ruleType)
+}
+
+// ShowRulesUsed mocks base method.
+func (m *MockIServer) ShowRulesUsed(logicDBName, dsName string) ([]*shardingsphere.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowRulesUsed", logicDBName, dsName)
+	ret0, _ := ret[0].([]*shardingsphere.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowRulesUsed indicates an expected call of ShowRulesUsed.
+func (mr *MockIServerMockRecorder) ShowRulesUsed(logicDBName, dsName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowRulesUsed", reflect.TypeOf((*MockIServer)(nil).ShowRulesUsed), logicDBName, dsName)
+}
+
+// ShowStorageUnits mocks base method.
+func (m *MockIServer) ShowStorageUnits(logicDBName string) ([]*shardingsphere.StorageUnit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowStorageUnits", logicDBName)
+	ret0, _ := ret[0].([]*shardingsphere.StorageUnit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowStorageUnits indicates an expected call of ShowStorageUnits.
+func (mr *MockIServerMockRecorder) ShowStorageUnits(logicDBName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowStorageUnits", reflect.TypeOf((*MockIServer)(nil).ShowStorageUnits), logicDBName)
+}
+
 // UnRegisterStorageUnit mocks base method.
 func (m *MockIServer) UnRegisterStorageUnit(logicDBName, dsName string) error {
 	m.ctrl.T.Helper()
@@ -105,3 +316,60 @@ func (mr *MockIServerMockRecorder) UnRegisterStorageUnit(logicDBName, dsName int
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnRegisterStorageUnit", reflect.TypeOf((*MockIServer)(nil).UnRegisterStorageUnit), logicDBName, dsName)
 }
+
+// UnlockCluster mocks base method.
+func (m *MockIServer) UnlockCluster() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockCluster")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockCluster indicates an expected call of UnlockCluster.
+func (mr *MockIServerMockRecorder) UnlockCluster() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockCluster", reflect.TypeOf((*MockIServer)(nil).UnlockCluster))
+}
+
+// Mockqueryer is a mock of queryer interface.
+type Mockqueryer struct {
+	ctrl     *gomock.Controller
+	recorder *MockqueryerMockRecorder
+}
+
+// MockqueryerMockRecorder is the mock recorder for Mockqueryer.
+type MockqueryerMockRecorder struct {
+	mock *Mockqueryer
+}
+
+// NewMockqueryer creates a new mock instance.
+func NewMockqueryer(ctrl *gomock.Controller) *Mockqueryer {
+	mock := &Mockqueryer{ctrl: ctrl}
+	mock.recorder = &MockqueryerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockqueryer) EXPECT() *MockqueryerMockRecorder {
+	return m.recorder
+}
+
+// QueryContext mocks base method.
+func (m *Mockqueryer) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryContext", varargs...)
+	ret0, _ := ret[0].(*sql.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryContext indicates an expected call of QueryContext.
+func (mr *MockqueryerMockRecorder) QueryContext(ctx, query interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryContext", reflect.TypeOf((*Mockqueryer)(nil).QueryContext), varargs...)
+}
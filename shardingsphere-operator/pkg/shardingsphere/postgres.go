@@ -0,0 +1,329 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package shardingsphere
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// pgDistSQLCreateDatabase create database, PostgreSQL has no `IF NOT EXISTS` for databases.
+	pgDistSQLCreateDatabase = `CREATE DATABASE %s;`
+	// pgDistSQLRegisterStorageUnit register storage unit with single quoted string literals.
+	pgDistSQLRegisterStorageUnit = `REGISTER STORAGE UNIT IF NOT EXISTS %s (HOST=%s,PORT=%d,DB=%s,USER=%s,PASSWORD=%s);`
+	// pgDistSQLAlterStorageUnit alter storage unit with single quoted string literals.
+	pgDistSQLAlterStorageUnit = `ALTER STORAGE UNIT %s (HOST=%s,PORT=%d,DB=%s,USER=%s,PASSWORD=%s);`
+	// pgShowDatabases lists the logic databases, `SHOW DATABASES` is not a PostgreSQL statement.
+	pgShowDatabases = `SELECT datname FROM pg_catalog.pg_database;`
+
+	// pgDuplicateDatabase is the SQLSTATE returned when the database to create already exists
+	pgDuplicateDatabase = "42P04"
+)
+
+// postgresServer is a DistSQL connection to ShardingSphere-Proxy with the PostgreSQL or openGauss frontend.
+// There is no `USE` statement in PostgreSQL, so the statements of a logic database are executed
+// through a connection opened on that database.
+type postgresServer struct {
+	host     string
+	port     uint
+	user     string
+	password string
+	// deadline is the deadline of all the calls, zero means no deadline
+	deadline time.Time
+
+	// db is not connected to any logic database, and is used to create logic databases
+	db *sql.DB
+
+	mu  sync.Mutex
+	dbs map[string]*sql.DB
+}
+
+var _ IServer = (*postgresServer)(nil)
+
+func newPostgresServer(host string, port uint, user, password string, deadline time.Time) (IServer, error) {
+	s := &postgresServer{
+		host:     host,
+		port:     port,
+		user:     user,
+		password: password,
+		deadline: deadline,
+		dbs:      map[string]*sql.DB{},
+	}
+
+	db, err := sql.Open(DriverPostgres, s.dataSourceName(""))
+	if err != nil {
+		return nil, fmt.Errorf("open database=%s:%d error: %w", host, port, err)
+	}
+
+	// check database connection
+	ctx, cancel := newContext(deadline)
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database=%s:%d error: %w", host, port, err)
+	}
+
+	s.db = db
+	return s, nil
+}
+
+// dataSourceName returns the lib/pq connection URL of the logic database,
+// the database is omitted when dbName is empty. lib/pq only has the connect timeout in seconds,
+// the queries are canceled by the context at the deadline.
+func (s *postgresServer) dataSourceName(dbName string) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(s.user, s.password),
+		Host:     net.JoinHostPort(s.host, strconv.FormatUint(uint64(s.port), 10)),
+		RawQuery: "sslmode=disable",
+	}
+	if !s.deadline.IsZero() {
+		// the timeout 0 is infinite, so it is at least 1 second
+		timeout := int64(time.Until(s.deadline)/time.Second) + 1
+		u.RawQuery += fmt.Sprintf("&connect_timeout=%d", timeout)
+	}
+	if dbName != "" {
+		u.Path = "/" + dbName
+	}
+	return u.String()
+}
+
+// database returns the connection to the logic database, the connection is opened at first use.
+func (s *postgresServer) database(logicDBName string) (*sql.DB, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if db, ok := s.dbs[logicDBName]; ok {
+		return db, nil
+	}
+
+	db, err := sql.Open(DriverPostgres, s.dataSourceName(logicDBName))
+	if err != nil {
+		return nil, fmt.Errorf("open logic database=%s error: %w", logicDBName, err)
+	}
+	s.dbs[logicDBName] = db
+	return db, nil
+}
+
+// quoteLiteral quotes the string as a DistSQL string literal.
+// Single quotes are used since double quotes stand for identifiers in PostgreSQL.
+func quoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func (s *postgresServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for name, db := range s.dbs {
+		if e := db.Close(); e != nil && err == nil {
+			err = e
+		}
+		delete(s.dbs, name)
+	}
+	if e := s.db.Close(); e != nil && err == nil {
+		err = e
+	}
+	return err
+}
+
+func (s *postgresServer) CreateDatabase(dbName string) error {
+	_, err := s.db.Exec(fmt.Sprintf(pgDistSQLCreateDatabase, dbName))
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgDuplicateDatabase {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("create database error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresServer) RegisterStorageUnit(logicDBName, dsName, dsHost string, dsPort uint, dsDBName, dsUser, dsPassword string) error {
+	distSQL := fmt.Sprintf(pgDistSQLRegisterStorageUnit, dsName, quoteLiteral(dsHost), dsPort, quoteLiteral(dsDBName), quoteLiteral(dsUser), quoteLiteral(dsPassword))
+	if err := s.ExecuteDistSQL(logicDBName, distSQL); err != nil {
+		return fmt.Errorf("register database error: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresServer) AlterStorageUnit(logicDBName, dsName, dsHost string, dsPort uint, dsDBName, dsUser, dsPassword string) error {
+	distSQL := fmt.Sprintf(pgDistSQLAlterStorageUnit, dsName, quoteLiteral(dsHost), dsPort, quoteLiteral(dsDBName), quoteLiteral(dsUser), quoteLiteral(dsPassword))
+	if err := s.ExecuteDistSQL(logicDBName, distSQL); err != nil {
+		return fmt.Errorf("alter storage unit error: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresServer) UnRegisterStorageUnit(logicDBName, dsName string) error {
+	rules, err := s.ShowRulesUsed(logicDBName, dsName)
+	if err != nil {
+		return fmt.Errorf("get rules used error: %w", err)
+	}
+
+	if err := checkRulesDroppable(rules); err != nil {
+		return err
+	}
+
+	// clean all rules used by storage unit
+	SortRulesForDrop(rules)
+	for _, rule := range rules {
+		if err := s.DropRule(logicDBName, rule.Type, rule.Name); err != nil {
+			return fmt.Errorf("drop rule error: %w", err)
+		}
+	}
+
+	if err := s.ExecuteDistSQL(logicDBName, fmt.Sprintf(DistSQLUnRegisterStorageUnit, dsName)); err != nil {
+		return fmt.Errorf("unregister database error: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresServer) ShowStorageUnits(logicDBName string) ([]*StorageUnit, error) {
+	db, err := s.database(logicDBName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	_, rows, err := query(ctx, db, fmt.Sprintf(DistSQLShowStorageUnits, logicDBName))
+	if err != nil {
+		return nil, fmt.Errorf("show storage units error: %w", err)
+	}
+	return parseStorageUnits(rows), nil
+}
+
+func (s *postgresServer) PingStorageUnit(logicDBName, dsName string) error {
+	db, err := s.database(logicDBName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	if _, _, err := query(ctx, db, fmt.Sprintf(DistSQLPingStorageUnit, dsName)); err != nil {
+		return fmt.Errorf("ping storage unit %s error: %w", dsName, err)
+	}
+	return nil
+}
+
+func (s *postgresServer) ShowComputeNodes() ([]*ComputeNodeInstance, error) {
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	_, rows, err := query(ctx, s.db, DistSQLShowComputeNodes)
+	if err != nil {
+		return nil, fmt.Errorf("show compute nodes error: %w", err)
+	}
+	return parseComputeNodes(rows), nil
+}
+
+func (s *postgresServer) ShowDatabases() ([]string, error) {
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	cols, rows, err := query(ctx, s.db, pgShowDatabases)
+	if err != nil {
+		return nil, fmt.Errorf("show databases error: %w", err)
+	}
+	return parseDatabases(cols, rows), nil
+}
+
+func (s *postgresServer) ShowRules(logicDBName, ruleType string) ([]*Rule, error) {
+	tpl, ok := showRulesMap[ruleType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported rule type: %s", ruleType)
+	}
+
+	db, err := s.database(logicDBName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	cols, rows, err := query(ctx, db, fmt.Sprintf(tpl, logicDBName))
+	if err != nil {
+		return nil, fmt.Errorf("show %s rules error: %w", ruleType, err)
+	}
+	return parseRules(ruleType, cols, rows), nil
+}
+
+func (s *postgresServer) ShowRulesUsed(logicDBName, dsName string) ([]*Rule, error) {
+	db, err := s.database(logicDBName)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	_, rows, err := query(ctx, db, fmt.Sprintf(DistSQLShowRulesUsed, dsName))
+	if err != nil {
+		return nil, fmt.Errorf("get rules used error: %w", err)
+	}
+
+	rules := make([]*Rule, 0)
+	seen := map[[2]string]bool{}
+	for _, row := range rows {
+		key := [2]string{row["type"], row["name"]}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		rules = append(rules, &Rule{Type: key[0], Name: key[1]})
+	}
+	return rules, nil
+}
+
+func (s *postgresServer) DropRule(logicDBName, ruleType, ruleName string) error {
+	distSQL, err := dropRuleDistSQL(ruleType, ruleName)
+	if err != nil {
+		return err
+	}
+	if err := s.ExecuteDistSQL(logicDBName, distSQL); err != nil {
+		return fmt.Errorf("drop rule fail, err: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresServer) ExecuteDistSQL(logicDBName string, distSQLs ...string) error {
+	db, err := s.database(logicDBName)
+	if err != nil {
+		return err
+	}
+
+	for _, distSQL := range distSQLs {
+		if _, err := db.Exec(distSQL); err != nil {
+			return fmt.Errorf("execute distsql error: %w", err)
+		}
+	}
+	return nil
+}
@@ -18,8 +18,13 @@
 package shardingsphere
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -29,8 +34,18 @@ const (
 	DistSQLCreateDatabase = `CREATE DATABASE IF NOT EXISTS %s;`
 	// DistSQLUseDatabase use database.
 	DistSQLUseDatabase = `USE %s;`
-	// DistSQLRegisterStorageUnit register database to shardingsphere by storage unit name and database info.
-	DistSQLRegisterStorageUnit = `REGISTER STORAGE UNIT IF NOT EXISTS %s (HOST="%s",PORT=%d,DB="%s",USER="%s",PASSWORD="%s");`
+	// DistSQLRegisterStorageUnit register database to shardingsphere by storage unit name and database info,
+	// the string values are quoted by quoteString.
+	DistSQLRegisterStorageUnit = `REGISTER STORAGE UNIT IF NOT EXISTS %s (HOST=%s,PORT=%d,DB=%s,USER=%s,PASSWORD=%s);`
+	// DistSQLAlterStorageUnit alter the database info of a registered storage unit,
+	// the string values are quoted by quoteString.
+	DistSQLAlterStorageUnit = `ALTER STORAGE UNIT %s (HOST=%s,PORT=%d,DB=%s,USER=%s,PASSWORD=%s);`
+	// DistSQLShowStorageUnits show all storage units of the logic database.
+	DistSQLShowStorageUnits = `SHOW STORAGE UNITS FROM %s;`
+	// DistSQLShowComputeNodes show all compute nodes known by the governance center of the cluster.
+	DistSQLShowComputeNodes = `SHOW COMPUTE NODES;`
+	// DistSQLShowDatabases show all logic databases.
+	DistSQLShowDatabases = `SHOW DATABASES;`
 	// DistSQLShowRulesUsed show all rules used by storage unit name.
 	DistSQLShowRulesUsed = `SHOW RULES USED STORAGE UNIT %s;`
 	// DistSQLUnRegisterStorageUnit unregister database from shardingsphere by storage unit name.
@@ -39,50 +54,218 @@ const (
 	DistSQLDropRule = `DROP %s RULE %s;`
 	// DistSQLDropTable drop table by table name.
 	DistSQLDropTable = `DROP TABLE %s;`
+	// DistSQLShowShardingTableRules show all sharding table rules of the logic database.
+	DistSQLShowShardingTableRules = `SHOW SHARDING TABLE RULES FROM %s;`
+	// DistSQLShowEncryptRules show all encrypt rules of the logic database.
+	DistSQLShowEncryptRules = `SHOW ENCRYPT RULES FROM %s;`
+	// DistSQLShowMaskRules show all mask rules of the logic database.
+	DistSQLShowMaskRules = `SHOW MASK RULES FROM %s;`
+	// DistSQLShowShadowRules show all shadow rules of the logic database.
+	DistSQLShowShadowRules = `SHOW SHADOW RULES FROM %s;`
+	// DistSQLShowReadwriteSplittingRules show all readwrite-splitting rules of the logic database.
+	DistSQLShowReadwriteSplittingRules = `SHOW READWRITE_SPLITTING RULES FROM %s;`
+	// DistSQLShowShardingTableReferenceRules show all sharding table reference rules of the logic database.
+	DistSQLShowShardingTableReferenceRules = `SHOW SHARDING TABLE REFERENCE RULES FROM %s;`
+	// DistSQLShowBroadcastTableRules show all broadcast table rules of the logic database.
+	DistSQLShowBroadcastTableRules = `SHOW BROADCAST TABLE RULES FROM %s;`
+	// DistSQLPingStorageUnit routes a query to the storage unit by the hint, which fails if the storage unit is unreachable.
+	DistSQLPingStorageUnit = `/* SHARDINGSPHERE_HINT: DATA_SOURCE_NAME=%s */ SELECT 1;`
 )
 
+// Rule types, the same as the type column returned by `SHOW RULES USED STORAGE UNIT`.
+const (
+	RuleTypeSharding           = "sharding"
+	RuleTypeEncrypt            = "encrypt"
+	RuleTypeMask               = "mask"
+	RuleTypeShadow             = "shadow"
+	RuleTypeReadwriteSplitting = "readwrite_splitting"
+
+	RuleTypeShardingTableReference = "sharding_table_reference"
+	RuleTypeBroadcastTable         = "broadcast_table"
+)
+
+// RuleTypes are all the supported rule types in dependency order,
+// a rule may use the storage units and the rules of the types before it.
+// Rules should be dropped in the reverse order.
+var RuleTypes = []string{
+	RuleTypeReadwriteSplitting,
+	RuleTypeShadow,
+	RuleTypeSharding,
+	RuleTypeBroadcastTable,
+	RuleTypeShardingTableReference,
+	RuleTypeEncrypt,
+	RuleTypeMask,
+}
+
+// ruleTypeMap maps rule type to the DistSQL keywords used to drop the rule.
 var ruleTypeMap = map[string]string{}
 
+// showRulesMap maps rule type to the DistSQL which lists the rules of this type.
+var showRulesMap = map[string]string{
+	RuleTypeSharding:           DistSQLShowShardingTableRules,
+	RuleTypeEncrypt:            DistSQLShowEncryptRules,
+	RuleTypeMask:               DistSQLShowMaskRules,
+	RuleTypeShadow:             DistSQLShowShadowRules,
+	RuleTypeReadwriteSplitting: DistSQLShowReadwriteSplittingRules,
+
+	RuleTypeShardingTableReference: DistSQLShowShardingTableReferenceRules,
+	RuleTypeBroadcastTable:         DistSQLShowBroadcastTableRules,
+}
+
 type Rule struct {
 	Type string
 	Name string
+	// Rows are the rows of `SHOW xxx RULES` of the rule keyed by the lower case column names,
+	// a rule may have several rows, e.g. one per column of an encrypt rule. It is empty for the used rules.
+	Rows []map[string]string
+}
+
+// StorageUnit is a row of `SHOW STORAGE UNITS`
+type StorageUnit struct {
+	Name string
+	Type string
+	Host string
+	Port uint
+	DB   string
+
+	ConnectionTimeoutMilliseconds int64
+	IdleTimeoutMilliseconds       int64
+	MaxLifetimeMilliseconds       int64
+	MaxPoolSize                   int64
+	MinPoolSize                   int64
+	ReadOnly                      bool
+	OtherAttributes               string
+}
+
+// ComputeNodeInstance is a row of `SHOW COMPUTE NODES`
+type ComputeNodeInstance struct {
+	InstanceID string
+	Host       string
+	Port       uint
+	// Status is the state of the compute node, e.g. OK and CIRCUIT_BREAK
+	Status   string
+	ModeType string
+	WorkerID string
+	Labels   string
+	Version  string
+}
+
+// SortRulesForDrop sorts the rules with the reverse order of RuleTypes,
+// so that a rule is always dropped before the rules it uses.
+func SortRulesForDrop(rules []*Rule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return ruleTypeIndex(rules[i].Type) > ruleTypeIndex(rules[j].Type)
+	})
+}
+
+func ruleTypeIndex(ruleType string) int {
+	for i, t := range RuleTypes {
+		if t == ruleType {
+			return i
+		}
+	}
+	return -1
 }
 
 type server struct {
 	db *sql.DB
+	// deadline is the deadline of all the calls, zero means no deadline
+	deadline time.Time
 }
 
 type IServer interface {
 	CreateDatabase(dbName string) error
 	RegisterStorageUnit(logicDBName, dsName, dsHost string, dsPort uint, dsDBName, dsUser, dsPassword string) error
+	AlterStorageUnit(logicDBName, dsName, dsHost string, dsPort uint, dsDBName, dsUser, dsPassword string) error
 	UnRegisterStorageUnit(logicDBName, dsName string) error
+	ShowStorageUnits(logicDBName string) ([]*StorageUnit, error)
+	PingStorageUnit(logicDBName, dsName string) error
+	ShowComputeNodes() ([]*ComputeNodeInstance, error)
+	ShowDatabases() ([]string, error)
+	ShowRules(logicDBName, ruleType string) ([]*Rule, error)
+	ShowRulesUsed(logicDBName, dsName string) ([]*Rule, error)
+	DropRule(logicDBName, ruleType, ruleName string) error
+	ExecuteDistSQL(logicDBName string, distSQLs ...string) error
+	LockClusterForBackup() error
+	UnlockCluster() error
+	ExportMetadata() (*ClusterMetadata, error)
+	ExportStorageNodes() ([]*ExportedStorageNode, error)
+	ImportMetadata(data string) error
+	DropDatabase(dbName string) error
 	Close() error
 }
 
 var _ IServer = (*server)(nil)
 
+// Drivers of the frontend database protocols of ShardingSphere-Proxy.
+const (
+	DriverMySQL     = "mysql"
+	DriverPostgres  = "postgres"
+	DriverOpenGauss = "opengauss"
+)
+
+// DriverOf returns the driver of the given ShardingSphere-Proxy frontend database protocol type,
+// e.g. MySQL, PostgreSQL and openGauss. Unknown protocol types are returned in lower case.
+func DriverOf(protocolType string) string {
+	switch t := strings.ToLower(protocolType); t {
+	case "mysql", "mariadb":
+		return DriverMySQL
+	case "postgresql", "postgres":
+		return DriverPostgres
+	default:
+		return t
+	}
+}
+
+// NewServer returns a DistSQL connection to ShardingSphere-Proxy, speaking the wire protocol of the driver.
 func NewServer(driver, host string, port uint, user, password string) (IServer, error) {
-	if driver != "mysql" && driver != "postgres" {
+	return NewServerWithDeadline(driver, host, port, user, password, time.Time{})
+}
+
+// NewServerWithDeadline returns a DistSQL connection to ShardingSphere-Proxy whose connecting, pinging and queries
+// all fail after the deadline, so that a probe never outlives it. A zero deadline means no deadline.
+func NewServerWithDeadline(driver, host string, port uint, user, password string, deadline time.Time) (IServer, error) {
+	if driver != DriverMySQL && driver != DriverPostgres && driver != DriverOpenGauss {
 		return nil, fmt.Errorf("unsupported database driver: %s", driver)
 	}
 
 	if host == "" || port == 0 || user == "" || password == "" {
-		return nil, fmt.Errorf("invalid database config, host=%s, port=%d, user=%s, password=%s", host, port, user, password)
+		// the password is never formatted into the errors, they are surfaced in the status and the events
+		return nil, fmt.Errorf("invalid database config, host=%s, port=%d, user=%s, password is empty=%t", host, port, user, password == "")
+	}
+
+	if driver != DriverMySQL {
+		return newPostgresServer(host, port, user, password, deadline)
 	}
 
 	dataSourceName := fmt.Sprintf("%s:%s@tcp(%s:%d)/", user, password, host, port)
+	if !deadline.IsZero() {
+		timeout := time.Until(deadline)
+		dataSourceName += fmt.Sprintf("?timeout=%s&readTimeout=%s&writeTimeout=%s", timeout, timeout, timeout)
+	}
 
 	db, err := sql.Open(driver, dataSourceName)
 	if err != nil {
-		return nil, fmt.Errorf("open database=%s error: %w", dataSourceName, err)
+		return nil, fmt.Errorf("open database=%s:%d error: %w", host, port, err)
 	}
 
 	// check database connection
-	if err = db.Ping(); err != nil {
-		return nil, fmt.Errorf("ping database=%s error: %w", dataSourceName, err)
+	ctx, cancel := newContext(deadline)
+	defer cancel()
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database=%s:%d error: %w", host, port, err)
 	}
 
-	return &server{db: db}, nil
+	return &server{db: db, deadline: deadline}, nil
+}
+
+// newContext returns the context of a call, which is canceled at the deadline if it is not zero.
+func newContext(deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithDeadline(context.Background(), deadline)
 }
 
 func (s *server) Close() error {
@@ -101,37 +284,114 @@ func (s *server) CreateDatabase(dbName string) error {
 }
 
 func (s *server) RegisterStorageUnit(logicDBName, dsName, dsHost string, dsPort uint, dsDBName, dsUser, dsPassword string) error {
-	_, err := s.db.Exec(fmt.Sprintf(DistSQLUseDatabase, logicDBName))
+	distSQL := fmt.Sprintf(DistSQLRegisterStorageUnit, dsName, quoteString(dsHost), dsPort, quoteString(dsDBName), quoteString(dsUser), quoteString(dsPassword))
+	if err := s.ExecuteDistSQL(logicDBName, distSQL); err != nil {
+		return fmt.Errorf("register database error: %w", err)
+	}
+	return nil
+}
+
+// AlterStorageUnit alters the database info of a registered storage unit.
+func (s *server) AlterStorageUnit(logicDBName, dsName, dsHost string, dsPort uint, dsDBName, dsUser, dsPassword string) error {
+	distSQL := fmt.Sprintf(DistSQLAlterStorageUnit, dsName, quoteString(dsHost), dsPort, quoteString(dsDBName), quoteString(dsUser), quoteString(dsPassword))
+	if err := s.ExecuteDistSQL(logicDBName, distSQL); err != nil {
+		return fmt.Errorf("alter storage unit error: %w", err)
+	}
+	return nil
+}
+
+// ShowStorageUnits returns all storage units of the logic database.
+func (s *server) ShowStorageUnits(logicDBName string) ([]*StorageUnit, error) {
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	_, rows, err := query(ctx, s.db, fmt.Sprintf(DistSQLShowStorageUnits, logicDBName))
 	if err != nil {
-		return fmt.Errorf("use database error: %w", err)
+		return nil, fmt.Errorf("show storage units error: %w", err)
 	}
+	return parseStorageUnits(rows), nil
+}
 
-	distSQL := fmt.Sprintf(DistSQLRegisterStorageUnit, dsName, dsHost, dsPort, dsDBName, dsUser, dsPassword)
+// PingStorageUnit checks the proxy can query the storage unit of the logic database.
+func (s *server) PingStorageUnit(logicDBName, dsName string) error {
+	return s.useDatabase(logicDBName, func(ctx context.Context, conn *sql.Conn) error {
+		if _, _, err := query(ctx, conn, fmt.Sprintf(DistSQLPingStorageUnit, dsName)); err != nil {
+			return fmt.Errorf("ping storage unit %s error: %w", dsName, err)
+		}
+		return nil
+	})
+}
 
-	_, err = s.db.Exec(distSQL)
+// ShowComputeNodes returns all compute nodes of the cluster.
+func (s *server) ShowComputeNodes() ([]*ComputeNodeInstance, error) {
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	_, rows, err := query(ctx, s.db, DistSQLShowComputeNodes)
 	if err != nil {
-		return fmt.Errorf("register database error: %w", err)
+		return nil, fmt.Errorf("show compute nodes error: %w", err)
 	}
+	return parseComputeNodes(rows), nil
+}
 
-	return nil
+// ShowDatabases returns the names of all logic databases.
+func (s *server) ShowDatabases() ([]string, error) {
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	cols, rows, err := query(ctx, s.db, DistSQLShowDatabases)
+	if err != nil {
+		return nil, fmt.Errorf("show databases error: %w", err)
+	}
+	return parseDatabases(cols, rows), nil
+}
+
+// ShowRulesUsed returns all rules used by the storage unit in the logic database.
+func (s *server) ShowRulesUsed(logicDBName, dsName string) (rules []*Rule, err error) {
+	err = s.useDatabase(logicDBName, func(ctx context.Context, conn *sql.Conn) error {
+		rules, err = getRulesUsed(ctx, conn, dsName)
+		return err
+	})
+	return rules, err
+}
+
+// useDatabase runs fn on a single connection which uses the logic database, the connections of the pool
+// may use other databases or none
+func (s *server) useDatabase(logicDBName string, fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection error: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(DistSQLUseDatabase, logicDBName)); err != nil {
+		return fmt.Errorf("use database error: %w", err)
+	}
+	return fn(ctx, conn)
 }
 
 // getRulesUsed returns all rules used by storage unit name.
-func (s *server) getRulesUsed(dsName string) (rules []*Rule, err error) {
+func getRulesUsed(ctx context.Context, conn *sql.Conn, dsName string) (rules []*Rule, err error) {
 	rules = make([]*Rule, 0)
 	distSQL := fmt.Sprintf(DistSQLShowRulesUsed, dsName)
 
-	rows, err := s.db.Query(distSQL)
+	rows, err := conn.QueryContext(ctx, distSQL)
 	if err != nil {
 		return nil, fmt.Errorf("get rules used error: %w", err)
 	}
 	defer rows.Close()
 
+	// a rule may use the storage unit more than once, e.g. a sharding table rule with several data nodes
+	seen := map[[2]string]bool{}
 	for rows.Next() {
 		var ruleT, ruleN string
 		if err := rows.Scan(&ruleT, &ruleN); err != nil {
 			return nil, fmt.Errorf("scan rules used error: %w", err)
 		}
+		key := [2]string{ruleT, ruleN}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
 		rules = append(rules, &Rule{Type: ruleT, Name: ruleN})
 	}
 
@@ -142,38 +402,233 @@ func (s *server) getRulesUsed(dsName string) (rules []*Rule, err error) {
 }
 
 func (s *server) UnRegisterStorageUnit(logicDBName, dsName string) error {
-	_, err := s.db.Exec(fmt.Sprintf(DistSQLUseDatabase, logicDBName))
+	return s.useDatabase(logicDBName, func(ctx context.Context, conn *sql.Conn) error {
+		rules, err := getRulesUsed(ctx, conn, dsName)
+		if err != nil {
+			return fmt.Errorf("get rules used error: %w", err)
+		}
+
+		if err := checkRulesDroppable(rules); err != nil {
+			return err
+		}
+
+		// clean all rules used by storage unit
+		SortRulesForDrop(rules)
+		for _, rule := range rules {
+			if err := dropRule(ctx, conn, rule.Type, rule.Name); err != nil {
+				return fmt.Errorf("drop rule error: %w", err)
+			}
+		}
+
+		distSQL := fmt.Sprintf(DistSQLUnRegisterStorageUnit, dsName)
+		if _, err := conn.ExecContext(ctx, distSQL); err != nil {
+			return fmt.Errorf("unregister database error: %w", err)
+		}
+		return nil
+	})
+}
+
+// ShowRules returns the rules of the given type defined in the logic database.
+func (s *server) ShowRules(logicDBName, ruleType string) ([]*Rule, error) {
+	tpl, ok := showRulesMap[ruleType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported rule type: %s", ruleType)
+	}
+
+	ctx, cancel := newContext(s.deadline)
+	defer cancel()
+	cols, rows, err := query(ctx, s.db, fmt.Sprintf(tpl, logicDBName))
 	if err != nil {
-		return fmt.Errorf("use database error: %w", err)
+		return nil, fmt.Errorf("show %s rules error: %w", ruleType, err)
+	}
+	return parseRules(ruleType, cols, rows), nil
+}
+
+// DropRule drops the rule with the given type and name in the logic database.
+func (s *server) DropRule(logicDBName, ruleType, ruleName string) error {
+	return s.useDatabase(logicDBName, func(ctx context.Context, conn *sql.Conn) error {
+		return dropRule(ctx, conn, ruleType, ruleName)
+	})
+}
+
+// queryer is implemented by both *sql.DB and *sql.Conn
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// query returns the lower case column names and the rows as maps from column names to values.
+func query(ctx context.Context, q queryer, distSQL string) (cols []string, result []map[string]string, err error) {
+	rows, err := q.QueryContext(ctx, distSQL)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer rows.Close()
 
-	rules, err := s.getRulesUsed(dsName)
+	cols, err = rows.Columns()
 	if err != nil {
-		return fmt.Errorf("get rules used error: %w", err)
+		return nil, nil, fmt.Errorf("get columns error: %w", err)
+	}
+	for i := range cols {
+		cols[i] = strings.ToLower(cols[i])
 	}
 
-	// clean all rules used by storage unit
-	for _, rule := range rules {
-		if err := s.dropRule(rule.Type, rule.Name); err != nil {
-			return fmt.Errorf("drop rule error: %w", err)
+	result = make([]map[string]string, 0)
+	for rows.Next() {
+		values := make([]sql.RawBytes, len(cols))
+		dest := make([]any, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, fmt.Errorf("scan error: %w", err)
 		}
+
+		row := make(map[string]string, len(cols))
+		for i, col := range cols {
+			row[col] = string(values[i])
+		}
+		result = append(result, row)
 	}
 
-	distSQL := fmt.Sprintf(DistSQLUnRegisterStorageUnit, dsName)
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows error: %w", err)
+	}
+	return cols, result, nil
+}
 
-	_, err = s.db.Exec(distSQL)
-	if err != nil {
-		return fmt.Errorf("unregister database error: %w", err)
+// parseStorageUnits converts the rows of `SHOW STORAGE UNITS` to storage units.
+func parseStorageUnits(rows []map[string]string) []*StorageUnit {
+	units := make([]*StorageUnit, 0, len(rows))
+	for _, row := range rows {
+		unit := &StorageUnit{
+			Name:            row["name"],
+			Type:            row["type"],
+			Host:            row["host"],
+			DB:              row["db"],
+			OtherAttributes: row["other_attributes"],
+		}
+		if v, err := strconv.ParseUint(row["port"], 10, 32); err == nil {
+			unit.Port = uint(v)
+		}
+		unit.ConnectionTimeoutMilliseconds, _ = strconv.ParseInt(row["connection_timeout_milliseconds"], 10, 64)
+		unit.IdleTimeoutMilliseconds, _ = strconv.ParseInt(row["idle_timeout_milliseconds"], 10, 64)
+		unit.MaxLifetimeMilliseconds, _ = strconv.ParseInt(row["max_lifetime_milliseconds"], 10, 64)
+		unit.MaxPoolSize, _ = strconv.ParseInt(row["max_pool_size"], 10, 64)
+		unit.MinPoolSize, _ = strconv.ParseInt(row["min_pool_size"], 10, 64)
+		unit.ReadOnly, _ = strconv.ParseBool(row["read_only"])
+		units = append(units, unit)
 	}
+	return units
+}
+
+// parseComputeNodes converts the rows of `SHOW COMPUTE NODES` to compute node instances.
+func parseComputeNodes(rows []map[string]string) []*ComputeNodeInstance {
+	instances := make([]*ComputeNodeInstance, 0, len(rows))
+	for _, row := range rows {
+		instance := &ComputeNodeInstance{
+			InstanceID: row["instance_id"],
+			Host:       row["host"],
+			Status:     row["status"],
+			ModeType:   row["mode_type"],
+			WorkerID:   row["worker_id"],
+			Labels:     row["labels"],
+			Version:    row["version"],
+		}
+		if v, err := strconv.ParseUint(row["port"], 10, 32); err == nil {
+			instance.Port = uint(v)
+		}
+		instances = append(instances, instance)
+	}
+	return instances
+}
+
+// systemDatabases are the system schemas listed along with the logic databases.
+var systemDatabases = map[string]bool{
+	"information_schema": true,
+	"performance_schema": true,
+	"mysql":              true,
+	"sys":                true,
+	"shardingsphere":     true,
+	"postgres":           true,
+	"pg_catalog":         true,
+}
 
+// parseDatabases returns the logic databases in the first column, the column name differs between the frontend protocols.
+func parseDatabases(cols []string, rows []map[string]string) []string {
+	databases := make([]string, 0, len(rows))
+	if len(cols) == 0 {
+		return databases
+	}
+	for _, row := range rows {
+		if name := row[cols[0]]; !systemDatabases[strings.ToLower(name)] {
+			databases = append(databases, name)
+		}
+	}
+	return databases
+}
+
+// parseRules converts the rows of `SHOW xxx RULES` to rules of the given type with their rows.
+func parseRules(ruleType string, cols []string, rows []map[string]string) []*Rule {
+	rules := make([]*Rule, 0)
+	// the first column is the rule name, or the table name for table-level rules.
+	// some rules, like encrypt and mask, return one row per column
+	seen := map[string]*Rule{}
+	for _, row := range rows {
+		name := row[cols[0]]
+		if rule, ok := seen[name]; ok {
+			rule.Rows = append(rule.Rows, row)
+			continue
+		}
+		seen[name] = &Rule{Type: ruleType, Name: name, Rows: []map[string]string{row}}
+		rules = append(rules, seen[name])
+	}
+	return rules
+}
+
+// quoteString quotes the string as a double quoted DistSQL string literal.
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// checkRulesDroppable makes sure all the rules can be dropped before dropping any of them
+func checkRulesDroppable(rules []*Rule) error {
+	for _, rule := range rules {
+		if _, ok := ruleTypeMap[rule.Type]; !ok {
+			return fmt.Errorf("unsupported rule type %s of rule %s", rule.Type, rule.Name)
+		}
+	}
 	return nil
 }
 
-func (s *server) dropRule(ruleType, ruleName string) error {
-	// convert rule type
-	ruleType = ruleTypeMap[ruleType]
-	distSQL := fmt.Sprintf(DistSQLDropRule, ruleType, ruleName)
-	_, err := s.db.Exec(distSQL)
+// dropRuleDistSQL returns the DistSQL which drops the rule with the given type and name.
+func dropRuleDistSQL(ruleType, ruleName string) (string, error) {
+	keywords, ok := ruleTypeMap[ruleType]
+	if !ok {
+		return "", fmt.Errorf("unsupported rule type: %s", ruleType)
+	}
+	return fmt.Sprintf(DistSQLDropRule, keywords, ruleName), nil
+}
+
+// ExecuteDistSQL executes the given DistSQL statements in the logic database one by one.
+func (s *server) ExecuteDistSQL(logicDBName string, distSQLs ...string) error {
+	// use a single connection to make sure all the statements run in the used database
+	return s.useDatabase(logicDBName, func(ctx context.Context, conn *sql.Conn) error {
+		for _, distSQL := range distSQLs {
+			if _, err := conn.ExecContext(ctx, distSQL); err != nil {
+				return fmt.Errorf("execute distsql error: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func dropRule(ctx context.Context, conn *sql.Conn, ruleType, ruleName string) error {
+	distSQL, err := dropRuleDistSQL(ruleType, ruleName)
+	if err != nil {
+		return err
+	}
+	_, err = conn.ExecContext(ctx, distSQL)
 	if err != nil {
 		return fmt.Errorf("drop rule fail, err: %s", err)
 	}
@@ -184,6 +639,12 @@ func init() {
 	// init rule type map
 	// implement more rule type if needed
 	ruleTypeMap = map[string]string{
-		"sharding": "SHARDING TABLE",
+		RuleTypeSharding:               "SHARDING TABLE",
+		RuleTypeShardingTableReference: "SHARDING TABLE REFERENCE",
+		RuleTypeBroadcastTable:         "BROADCAST TABLE",
+		RuleTypeEncrypt:                "ENCRYPT",
+		RuleTypeMask:                   "MASK",
+		RuleTypeShadow:                 "SHADOW",
+		RuleTypeReadwriteSplitting:     "READWRITE_SPLITTING",
 	}
 }
@@ -21,6 +21,7 @@ import (
 	"database/sql"
 	"fmt"
 	"regexp"
+	"time"
 
 	"bou.ke/monkey"
 	"github.com/DATA-DOG/go-sqlmock"
@@ -80,9 +81,10 @@ var _ = Describe("Test ShardingSphere Server", func() {
 		// should return a sharding table rule named 't_order'.
 		It("should return a sharding table rule named 't_order'", func() {
 			// mock db and return sharding table rule
+			dbmock.ExpectExec(regexp.QuoteMeta("USE sharding_db")).WillReturnResult(sqlmock.NewResult(0, 0))
 			dbmock.ExpectQuery(regexp.QuoteMeta("SHOW RULES USED STORAGE UNIT")).WillReturnRows(sqlmock.NewRows([]string{"type", "name"}).AddRow("sharding", "t_order"))
 
-			result, err := s.(*server).getRulesUsed("ds_0")
+			result, err := s.ShowRulesUsed("sharding_db", "ds_0")
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(result).Should(Equal([]*Rule{{Type: "sharding", Name: "t_order"}}))
 		})
@@ -92,9 +94,10 @@ var _ = Describe("Test ShardingSphere Server", func() {
 	Context("Test drop rule by rule type 'sharding' and rule name 't_order'", func() {
 		It("should drop success", func() {
 			// mock db and return drop rule success
+			dbmock.ExpectExec(regexp.QuoteMeta("USE sharding_db")).WillReturnResult(sqlmock.NewResult(0, 0))
 			dbmock.ExpectExec("DROP SHARDING TABLE RULE").WillReturnResult(sqlmock.NewResult(1, 1))
 
-			err := s.(*server).dropRule("sharding", "t_order")
+			err := s.DropRule("sharding_db", "sharding", "t_order")
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 	})
@@ -109,6 +112,208 @@ var _ = Describe("Test ShardingSphere Server", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 	})
+
+	Context("Test unregister storage node used by several rules", func() {
+		It("should drop the rules in dependency order", func() {
+			dbmock.ExpectExec(regexp.QuoteMeta("USE")).WillReturnResult(sqlmock.NewResult(1, 1))
+			dbmock.ExpectQuery(regexp.QuoteMeta("SHOW RULES USED STORAGE UNIT")).WillReturnRows(sqlmock.NewRows([]string{"type", "name"}).
+				AddRow("readwrite_splitting", "rw_ds").
+				AddRow("sharding", "t_order").
+				AddRow("sharding", "t_order").
+				AddRow("encrypt", "t_user"))
+			dbmock.ExpectExec(regexp.QuoteMeta("DROP ENCRYPT RULE t_user")).WillReturnResult(sqlmock.NewResult(1, 1))
+			dbmock.ExpectExec(regexp.QuoteMeta("DROP SHARDING TABLE RULE t_order")).WillReturnResult(sqlmock.NewResult(1, 1))
+			dbmock.ExpectExec(regexp.QuoteMeta("DROP READWRITE_SPLITTING RULE rw_ds")).WillReturnResult(sqlmock.NewResult(1, 1))
+			dbmock.ExpectExec(regexp.QuoteMeta("UNREGISTER STORAGE UNIT")).WillReturnResult(sqlmock.NewResult(1, 1))
+
+			err = s.UnRegisterStorageUnit("sharding_db", "ds_0")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(dbmock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		})
+
+		It("should not drop any rule when a rule type is unsupported", func() {
+			dbmock.ExpectExec(regexp.QuoteMeta("USE")).WillReturnResult(sqlmock.NewResult(1, 1))
+			dbmock.ExpectQuery(regexp.QuoteMeta("SHOW RULES USED STORAGE UNIT")).WillReturnRows(sqlmock.NewRows([]string{"type", "name"}).
+				AddRow("sharding", "t_order").
+				AddRow("unknown", "unknown_rule"))
+
+			err = s.UnRegisterStorageUnit("sharding_db", "ds_0")
+			Expect(err).Should(HaveOccurred())
+			Expect(dbmock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("Test ping storage unit", func() {
+		It("should query the storage unit by the hint", func() {
+			dbmock.ExpectExec(regexp.QuoteMeta("USE sharding_db")).WillReturnResult(sqlmock.NewResult(0, 0))
+			dbmock.ExpectQuery(regexp.QuoteMeta("/* SHARDINGSPHERE_HINT: DATA_SOURCE_NAME=ds_0 */ SELECT 1;")).WillReturnError(fmt.Errorf("connection refused"))
+
+			err = s.PingStorageUnit("sharding_db", "ds_0")
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("ping storage unit ds_0 error"))
+			Expect(dbmock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("Test server with deadline", func() {
+		It("should set the timeouts in the data source name", func() {
+			var dataSourceName string
+			monkey.Patch(sql.Open, func(_, dsn string) (*sql.DB, error) {
+				dataSourceName = dsn
+				return db, nil
+			})
+
+			_, err = NewServerWithDeadline("mysql", "localhost", uint(3307), "user", "password", time.Now().Add(5*time.Second))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(dataSourceName).Should(MatchRegexp(`^user:password@tcp\(localhost:3307\)/\?timeout=4\.\d+s&readTimeout=4\.\d+s&writeTimeout=4\.\d+s$`))
+		})
+
+		It("should fail after the deadline", func() {
+			_, err = NewServerWithDeadline("mysql", "localhost", uint(3307), "user", "password", time.Now().Add(-time.Second))
+			Expect(err).Should(MatchError(ContainSubstring("deadline exceeded")))
+		})
+	})
+
+	Context("Test alter storage unit", func() {
+		It("should alter success", func() {
+			dbmock.ExpectExec(regexp.QuoteMeta("USE sharding_db")).WillReturnResult(sqlmock.NewResult(0, 0))
+			dbmock.ExpectExec(regexp.QuoteMeta(`ALTER STORAGE UNIT ds_0 (HOST="127.0.0.1",PORT=3306,DB="ds_0",USER="root",PASSWORD="root");`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			err = s.AlterStorageUnit("sharding_db", "ds_0", "127.0.0.1", uint(3306), "ds_0", "root", "root")
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should escape the string literals", func() {
+			dbmock.ExpectExec(regexp.QuoteMeta("USE sharding_db")).WillReturnResult(sqlmock.NewResult(0, 0))
+			dbmock.ExpectExec(regexp.QuoteMeta(`ALTER STORAGE UNIT ds_0 (HOST="127.0.0.1",PORT=3306,DB="ds_0",USER="root",PASSWORD="a""b\\c'd");`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			err = s.AlterStorageUnit("sharding_db", "ds_0", "127.0.0.1", uint(3306), "ds_0", "root", `a"b\c'd`)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("Test show storage units", func() {
+		It("should return typed storage units", func() {
+			dbmock.ExpectQuery(regexp.QuoteMeta("SHOW STORAGE UNITS FROM sharding_db")).WillReturnRows(
+				sqlmock.NewRows([]string{"name", "type", "host", "port", "db", "connection_timeout_milliseconds", "idle_timeout_milliseconds", "max_lifetime_milliseconds", "max_pool_size", "min_pool_size", "read_only", "other_attributes"}).
+					AddRow("ds_0", "MySQL", "127.0.0.1", "3306", "ds_0", "30000", "60000", "2100000", "50", "1", "false", "{}"))
+
+			units, err := s.ShowStorageUnits("sharding_db")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(units).Should(Equal([]*StorageUnit{
+				{
+					Name:                          "ds_0",
+					Type:                          "MySQL",
+					Host:                          "127.0.0.1",
+					Port:                          3306,
+					DB:                            "ds_0",
+					ConnectionTimeoutMilliseconds: 30000,
+					IdleTimeoutMilliseconds:       60000,
+					MaxLifetimeMilliseconds:       2100000,
+					MaxPoolSize:                   50,
+					MinPoolSize:                   1,
+					ReadOnly:                      false,
+					OtherAttributes:               "{}",
+				},
+			}))
+		})
+	})
+
+	Context("Test show compute nodes", func() {
+		It("should return typed compute node instances", func() {
+			dbmock.ExpectQuery(regexp.QuoteMeta("SHOW COMPUTE NODES")).WillReturnRows(
+				sqlmock.NewRows([]string{"instance_id", "host", "port", "status", "mode_type", "worker_id", "labels", "version"}).
+					AddRow("734bb036-b15d-4af0-be87-2372d8d6a2f8", "10.0.0.1", "3307", "OK", "Cluster", "-1", "", "5.4.0"))
+
+			instances, err := s.ShowComputeNodes()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(instances).Should(Equal([]*ComputeNodeInstance{
+				{
+					InstanceID: "734bb036-b15d-4af0-be87-2372d8d6a2f8",
+					Host:       "10.0.0.1",
+					Port:       3307,
+					Status:     "OK",
+					ModeType:   "Cluster",
+					WorkerID:   "-1",
+					Version:    "5.4.0",
+				},
+			}))
+		})
+	})
+
+	Context("Test show databases", func() {
+		It("should return the names of the logic databases without system schemas", func() {
+			dbmock.ExpectQuery(regexp.QuoteMeta("SHOW DATABASES")).WillReturnRows(
+				sqlmock.NewRows([]string{"schema_name"}).AddRow("sharding_db").AddRow("information_schema").AddRow("encrypt_db"))
+
+			databases, err := s.ShowDatabases()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(databases).Should(Equal([]string{"sharding_db", "encrypt_db"}))
+		})
+	})
+
+	Context("Test drop rule of every rule type", func() {
+		DescribeTable("should drop with the keywords of the rule type",
+			func(ruleType, distSQL string) {
+				dbmock.ExpectExec(regexp.QuoteMeta("USE sharding_db")).WillReturnResult(sqlmock.NewResult(0, 0))
+				dbmock.ExpectExec(regexp.QuoteMeta(distSQL)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				Expect(s.DropRule("sharding_db", ruleType, "rule_0")).ShouldNot(HaveOccurred())
+				Expect(dbmock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+			},
+			Entry("sharding", RuleTypeSharding, "DROP SHARDING TABLE RULE rule_0;"),
+			Entry("sharding table reference", RuleTypeShardingTableReference, "DROP SHARDING TABLE REFERENCE RULE rule_0;"),
+			Entry("broadcast table", RuleTypeBroadcastTable, "DROP BROADCAST TABLE RULE rule_0;"),
+			Entry("encrypt", RuleTypeEncrypt, "DROP ENCRYPT RULE rule_0;"),
+			Entry("mask", RuleTypeMask, "DROP MASK RULE rule_0;"),
+			Entry("shadow", RuleTypeShadow, "DROP SHADOW RULE rule_0;"),
+			Entry("readwrite splitting", RuleTypeReadwriteSplitting, "DROP READWRITE_SPLITTING RULE rule_0;"),
+		)
+
+		It("should return error with unsupported rule type", func() {
+			dbmock.ExpectExec(regexp.QuoteMeta("USE sharding_db")).WillReturnResult(sqlmock.NewResult(0, 0))
+			Expect(s.DropRule("sharding_db", "unknown", "rule_0")).Should(HaveOccurred())
+		})
+	})
+
+	Context("Test show rules", func() {
+		It("should return encrypt rules without duplicated tables", func() {
+			dbmock.ExpectQuery(regexp.QuoteMeta("SHOW ENCRYPT RULES FROM sharding_db")).WillReturnRows(
+				sqlmock.NewRows([]string{"table", "logic_column", "cipher_column"}).
+					AddRow("t_user", "password", "password_cipher").
+					AddRow("t_user", "email", "email_cipher").
+					AddRow("t_order", "address", "address_cipher"))
+
+			rules, err := s.ShowRules("sharding_db", RuleTypeEncrypt)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rules).Should(Equal([]*Rule{
+				{Type: RuleTypeEncrypt, Name: "t_user", Rows: []map[string]string{
+					{"table": "t_user", "logic_column": "password", "cipher_column": "password_cipher"},
+					{"table": "t_user", "logic_column": "email", "cipher_column": "email_cipher"},
+				}},
+				{Type: RuleTypeEncrypt, Name: "t_order", Rows: []map[string]string{
+					{"table": "t_order", "logic_column": "address", "cipher_column": "address_cipher"},
+				}},
+			}))
+		})
+
+		It("should return error with unsupported rule type", func() {
+			_, err := s.ShowRules("sharding_db", "unknown")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("Test execute distsql", func() {
+		It("should execute all the statements in the logic database", func() {
+			dbmock.ExpectExec(regexp.QuoteMeta("USE sharding_db")).WillReturnResult(sqlmock.NewResult(0, 0))
+			dbmock.ExpectExec(regexp.QuoteMeta("CREATE SHARDING TABLE RULE")).WillReturnResult(sqlmock.NewResult(0, 0))
+			dbmock.ExpectExec(regexp.QuoteMeta("CREATE ENCRYPT RULE")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			err = s.ExecuteDistSQL("sharding_db", "CREATE SHARDING TABLE RULE t_order (...)", "CREATE ENCRYPT RULE t_user (...)")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(dbmock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		})
+	})
 })
 
 var _ = Describe("Test ShardingSphere Server Manually", func() {
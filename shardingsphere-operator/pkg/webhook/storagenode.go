@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// the path is served by the webhook server of the operator, see generateValidatePath
+//+kubebuilder:webhook:path=/apis/admission.shardingsphere.apache.org/v1alpha1/validate-shardingsphere-apache-org-v1alpha1-storagenode,mutating=false,failurePolicy=fail,sideEffects=None,groups=shardingsphere.apache.org,resources=storagenodes,verbs=create;update,versions=v1alpha1,name=vstoragenode.shardingsphere.apache.org,admissionReviewVersions=v1
+
+// StorageNodeValidator validates the resources in the spec of StorageNode against the provisioner of its StorageProvider
+type StorageNodeValidator struct {
+	Client client.Reader
+}
+
+var _ admission.CustomValidator = &StorageNodeValidator{}
+
+func (v *StorageNodeValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	node, ok := obj.(*v1alpha1.StorageNode)
+	if !ok {
+		return fmt.Errorf("expected a StorageNode but got a %T", obj)
+	}
+	return v.validate(ctx, nil, node)
+}
+
+func (v *StorageNodeValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	old, ok := oldObj.(*v1alpha1.StorageNode)
+	if !ok {
+		return fmt.Errorf("expected a StorageNode but got a %T", oldObj)
+	}
+	node, ok := newObj.(*v1alpha1.StorageNode)
+	if !ok {
+		return fmt.Errorf("expected a StorageNode but got a %T", newObj)
+	}
+	return v.validate(ctx, old, node)
+}
+
+func (v *StorageNodeValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *StorageNodeValidator) validate(ctx context.Context, old, node *v1alpha1.StorageNode) error {
+	sp := &v1alpha1.StorageProvider{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: node.Spec.StorageProviderName}, sp); err != nil {
+		// the storage provider may be created later, the resources are validated by the provisioner then
+		return client.IgnoreNotFound(err)
+	}
+
+	errs := ValidateStorageNodeResources(old, node, sp.Spec.Provisioner)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(v1alpha1.GroupVersion.WithKind("StorageNode").GroupKind(), node.Name, errs)
+}
+
+// ValidateStorageNodeResources validates the resources in the spec of the storage node for the provisioner,
+// the old storage node is nil on creation.
+func ValidateStorageNodeResources(old, node *v1alpha1.StorageNode, p string) field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	isAWS := p == v1alpha1.ProvisionerAWSRDSInstance || p == v1alpha1.ProvisionerAWSRDSCluster || p == v1alpha1.ProvisionerAWSAurora
+	if node.Spec.InstanceClass != "" && !isAWS {
+		errs = append(errs, field.Forbidden(spec.Child("instanceClass"), fmt.Sprintf("not supported by %s", p)))
+	}
+
+	if node.Spec.AllocatedStorage != 0 {
+		switch p {
+		case v1alpha1.ProvisionerAWSAurora:
+			errs = append(errs, field.Forbidden(spec.Child("allocatedStorage"), "the storage of aws aurora grows automatically"))
+		case v1alpha1.ProvisionerLocalStatefulSet:
+			errs = append(errs, field.Forbidden(spec.Child("allocatedStorage"), fmt.Sprintf("not supported by %s", p)))
+		}
+	}
+	if old != nil && node.Spec.AllocatedStorage != 0 && node.Spec.AllocatedStorage < old.Spec.AllocatedStorage {
+		errs = append(errs, field.Invalid(spec.Child("allocatedStorage"), node.Spec.AllocatedStorage,
+			fmt.Sprintf("can not be shrunk from %d", old.Spec.AllocatedStorage)))
+	}
+
+	if node.Spec.IOPS != 0 && p != v1alpha1.ProvisionerAWSRDSInstance && p != v1alpha1.ProvisionerAWSRDSCluster {
+		errs = append(errs, field.Forbidden(spec.Child("iops"), fmt.Sprintf("not supported by %s", p)))
+	}
+
+	if node.Spec.Replicas > 1 && p == v1alpha1.ProvisionerAWSRDSInstance {
+		errs = append(errs, field.Invalid(spec.Child("replicas"), node.Spec.Replicas, "the rds instance has no readers"))
+	}
+	return errs
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ValidateStorageNodeResources(t *testing.T) {
+	cases := []struct {
+		name        string
+		old         *v1alpha1.StorageNodeSpec
+		spec        v1alpha1.StorageNodeSpec
+		provisioner string
+		fields      []string
+	}{
+		{
+			name:        "rds instance with all the resources",
+			spec:        v1alpha1.StorageNodeSpec{InstanceClass: "db.t3.small", AllocatedStorage: 20, IOPS: 3000, Replicas: 1},
+			provisioner: v1alpha1.ProvisionerAWSRDSInstance,
+		},
+		{
+			name:        "rds instance with readers",
+			spec:        v1alpha1.StorageNodeSpec{Replicas: 2},
+			provisioner: v1alpha1.ProvisionerAWSRDSInstance,
+			fields:      []string{"spec.replicas"},
+		},
+		{
+			name:        "aurora with storage and iops",
+			spec:        v1alpha1.StorageNodeSpec{InstanceClass: "db.r5.large", AllocatedStorage: 20, IOPS: 3000, Replicas: 3},
+			provisioner: v1alpha1.ProvisionerAWSAurora,
+			fields:      []string{"spec.allocatedStorage", "spec.iops"},
+		},
+		{
+			name:        "cloudnative-pg with instance class",
+			spec:        v1alpha1.StorageNodeSpec{InstanceClass: "db.t3.small", AllocatedStorage: 20},
+			provisioner: v1alpha1.ProvisionerCloudNativePG,
+			fields:      []string{"spec.instanceClass"},
+		},
+		{
+			name:        "cloudnative-pg with replicas",
+			spec:        v1alpha1.StorageNodeSpec{AllocatedStorage: 20, Replicas: 3},
+			provisioner: v1alpha1.ProvisionerCloudNativePG,
+		},
+		{
+			name:        "local statefulset with storage",
+			spec:        v1alpha1.StorageNodeSpec{AllocatedStorage: 20},
+			provisioner: v1alpha1.ProvisionerLocalStatefulSet,
+			fields:      []string{"spec.allocatedStorage"},
+		},
+		{
+			name:        "storage expanded",
+			old:         &v1alpha1.StorageNodeSpec{AllocatedStorage: 20},
+			spec:        v1alpha1.StorageNodeSpec{AllocatedStorage: 30},
+			provisioner: v1alpha1.ProvisionerCloudNativePG,
+		},
+		{
+			name:        "storage shrunk",
+			old:         &v1alpha1.StorageNodeSpec{AllocatedStorage: 30},
+			spec:        v1alpha1.StorageNodeSpec{AllocatedStorage: 20},
+			provisioner: v1alpha1.ProvisionerAWSRDSCluster,
+			fields:      []string{"spec.allocatedStorage"},
+		},
+	}
+
+	for _, c := range cases {
+		var old *v1alpha1.StorageNode
+		if c.old != nil {
+			old = &v1alpha1.StorageNode{Spec: *c.old}
+		}
+		errs := ValidateStorageNodeResources(old, &v1alpha1.StorageNode{Spec: c.spec}, c.provisioner)
+
+		fields := []string{}
+		for _, err := range errs {
+			fields = append(fields, err.Field)
+		}
+		assert.ElementsMatch(t, c.fields, fields, c.name)
+	}
+}
+
+func Test_StorageNodeValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	v := &StorageNodeValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&v1alpha1.StorageProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: "aurora"},
+			Spec:       v1alpha1.StorageProviderSpec{Provisioner: v1alpha1.ProvisionerAWSAurora},
+		}).Build(),
+	}
+
+	node := &v1alpha1.StorageNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node", Namespace: "default"},
+		Spec:       v1alpha1.StorageNodeSpec{StorageProviderName: "aurora", InstanceClass: "db.r5.large", Replicas: 2},
+	}
+	assert.NoError(t, v.ValidateCreate(context.TODO(), node))
+
+	invalid := node.DeepCopy()
+	invalid.Spec.AllocatedStorage = 20
+	err := v.ValidateUpdate(context.TODO(), node, invalid)
+	assert.True(t, apierrors.IsInvalid(err), "unexpected error %v", err)
+
+	// the storage provider is not created yet
+	invalid.Spec.StorageProviderName = "missing"
+	assert.NoError(t, v.ValidateCreate(context.TODO(), invalid))
+}
+
+func Test_StorageNodeValidatePath(t *testing.T) {
+	// the path is hard coded in the kubebuilder marker and the chart
+	assert.Equal(t, "/apis/admission.shardingsphere.apache.org/v1alpha1/validate-shardingsphere-apache-org-v1alpha1-storagenode",
+		generateValidatePath(v1alpha1.GroupVersion.WithKind("StorageNode")))
+}
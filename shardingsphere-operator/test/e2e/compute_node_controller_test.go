@@ -23,6 +23,7 @@ import (
 
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/configmap"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/computenode"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"gopkg.in/yaml.v2"
@@ -671,7 +672,6 @@ var _ = Describe("Default ConfigMap", func() {
 		}
 		expect.Data = map[string]string{}
 		expect.Data[configmap.ConfigDataKeyForLogback] = configmap.DefaultLogback
-		expect.Data[configmap.ConfigDataKeyForServer] = configmap.DefaultServerConfig
 		expect.Data[configmap.ConfigDataKeyForAgent] = ""
 	})
 
@@ -693,9 +693,11 @@ var _ = Describe("Default ConfigMap", func() {
 	Context("Assert Default Spec Data", func() {
 		c := configmap.NewConfigMapClient(nil)
 		cm := c.Build(ctx, cn)
+		secret, _ := computenode.NewBuilder().BuildServerConfigSecret(ctx, cn, nil)
 
 		It("default server config should be equal", func() {
-			Expect(expect.Data[configmap.ConfigDataKeyForServer]).To(Equal(cm.Data[configmap.ConfigDataKeyForServer]))
+			Expect(configmap.DefaultServerConfig).To(Equal(string(secret.Data[configmap.ConfigDataKeyForServer])))
+			Expect(cm.Data).NotTo(HaveKey(configmap.ConfigDataKeyForServer))
 		})
 		It("default logback should be equal", func() {
 			Expect(expect.Data[configmap.ConfigDataKeyForLogback]).To(Equal(cm.Data[configmap.ConfigDataKeyForLogback]))
@@ -751,8 +753,9 @@ var _ = Describe("Default ConfigMap", func() {
 		c := configmap.NewConfigMapClient(nil)
 		cm := c.Build(ctx, cn)
 		cm = configmap.UpdateComputeNodeConfigMap(cn, cm)
+		secret, _ := computenode.NewBuilder().BuildServerConfigSecret(ctx, cn, nil)
 		cfg := &v1alpha1.ServerConfig{}
-		err := yaml.Unmarshal([]byte(cm.Data[configmap.ConfigDataKeyForServer]), &cfg)
+		err := yaml.Unmarshal(secret.Data[configmap.ConfigDataKeyForServer], &cfg)
 		if err != nil {
 			fmt.Printf("Err: %s\n", err)
 		}
@@ -790,9 +793,8 @@ var _ = Describe("Standalone Server Config", func() {
 		}
 
 		expect := &v1alpha1.ServerConfig{}
-		c := configmap.NewConfigMapClient(nil)
-		cm := c.Build(ctx, cn)
-		err := yaml.Unmarshal([]byte(cm.Data[configmap.ConfigDataKeyForServer]), &expect)
+		secret, _ := computenode.NewBuilder().BuildServerConfigSecret(ctx, cn, nil)
+		err := yaml.Unmarshal(secret.Data[configmap.ConfigDataKeyForServer], &expect)
 		if err != nil {
 			fmt.Printf("Err: %s\n", err)
 		}
@@ -840,9 +842,8 @@ var _ = Describe("Standalone Server Config", func() {
 		}
 
 		expect := &v1alpha1.ServerConfig{}
-		c := configmap.NewConfigMapClient(nil)
-		cm := c.Build(ctx, cn)
-		err := yaml.Unmarshal([]byte(cm.Data[configmap.ConfigDataKeyForServer]), &expect)
+		secret, _ := computenode.NewBuilder().BuildServerConfigSecret(ctx, cn, nil)
+		err := yaml.Unmarshal(secret.Data[configmap.ConfigDataKeyForServer], &expect)
 		if err != nil {
 			fmt.Printf("Err: %s\n", err)
 		}
@@ -906,10 +907,9 @@ var _ = Describe("Cluster Server Config", func() {
 	)
 
 	BeforeEach(func() {
-		c := configmap.NewConfigMapClient(nil)
-		cm := c.Build(ctx, cn)
+		secret, _ := computenode.NewBuilder().BuildServerConfigSecret(ctx, cn, nil)
 
-		err := yaml.Unmarshal([]byte(cm.Data[configmap.ConfigDataKeyForServer]), &expect)
+		err := yaml.Unmarshal(secret.Data[configmap.ConfigDataKeyForServer], &expect)
 		if err != nil {
 			fmt.Printf("Err: %s\n", err)
 		}
@@ -30,6 +30,9 @@ import (
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/configmap"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/kubernetes/service"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/computenode"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/aws"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/local"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/provisioner"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -92,15 +95,16 @@ var _ = BeforeSuite(func() {
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{})
 	Expect(err).ToNot(HaveOccurred())
 	// print k8sManager Options
+	provisioners := provisioner.NewRegistry()
+	aws.RegisterProvisioners(provisioners, "AwsRegion", "AwsAccessKeyID", "AwsSecretAccessKey")
+	provisioners.Register(v1alpha1.ProvisionerLocalStatefulSet, local.NewProvisioner(k8sManager.GetClient()))
 	err = (&controllers.StorageNodeReconciler{
-		Client:             k8sManager.GetClient(),
-		Scheme:             k8sManager.GetScheme(),
-		Log:                ctrl.Log.WithName("controllers").WithName("StorageNode"),
-		Recorder:           k8sManager.GetEventRecorderFor("StorageNode"),
-		AwsRegion:          "AwsRegion",
-		AwsAccessKeyID:     "AwsAccessKeyID",
-		AwsSecretAccessKey: "AwsSecretAccessKey",
-		Service:            service.NewServiceClient(k8sManager.GetClient()),
+		Client:       k8sManager.GetClient(),
+		Scheme:       k8sManager.GetScheme(),
+		Log:          ctrl.Log.WithName("controllers").WithName("StorageNode"),
+		Recorder:     k8sManager.GetEventRecorderFor("StorageNode"),
+		Service:      service.NewServiceClient(k8sManager.GetClient()),
+		Provisioners: provisioners,
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -28,12 +28,15 @@ import (
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/api/v1alpha1"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/controllers"
 	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/aws"
+	"github.com/apache/shardingsphere-on-cloud/shardingsphere-operator/pkg/reconcile/storagenode/local"
 
 	"bou.ke/monkey"
 	"github.com/DATA-DOG/go-sqlmock"
 	dbmeshawsrds "github.com/database-mesh/golang-sdk/aws/client/rds"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -416,3 +419,87 @@ var _ = Describe("StorageNode Controller Suite Test For AWS Aurora Cluster", fun
 		})
 	})
 })
+
+var _ = Describe("StorageNode Controller Suite Test For Local StatefulSet", func() {
+	storageProviderName := "test-local-storage-provider"
+
+	BeforeEach(func() {
+		storageProvider := &v1alpha1.StorageProvider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: storageProviderName,
+			},
+			Spec: v1alpha1.StorageProviderSpec{
+				Provisioner: v1alpha1.ProvisionerLocalStatefulSet,
+				Parameters: map[string]string{
+					"engine":             "mysql",
+					"engineVersion":      "5.7",
+					"masterUsername":     "root",
+					"masterUserPassword": "root123456",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, storageProvider)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		storageProvider := &v1alpha1.StorageProvider{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: storageProviderName}, storageProvider)).Should(Succeed())
+		Expect(k8sClient.Delete(ctx, storageProvider)).Should(Succeed())
+	})
+
+	It("should be ready when the instance is ready", func() {
+		nodeName := "test-local-storage-node"
+		node := &v1alpha1.StorageNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nodeName,
+				Namespace: "default",
+			},
+			Spec: v1alpha1.StorageNodeSpec{
+				StorageProviderName: storageProviderName,
+			},
+		}
+		Expect(k8sClient.Create(ctx, node)).Should(Succeed())
+
+		// the StatefulSet, the headless Service and the credentials Secret are created
+		sts := &appsv1.StatefulSet{}
+		Eventually(func() error {
+			return k8sClient.Get(ctx, client.ObjectKey{Name: nodeName, Namespace: "default"}, sts)
+		}, 10*time.Second, 250*time.Millisecond).Should(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: nodeName, Namespace: "default"}, &corev1.Service{})).Should(Succeed())
+		newNode := &v1alpha1.StorageNode{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: nodeName, Namespace: "default"}, newNode)).Should(Succeed())
+		Expect(newNode.Status.CredentialsSecretRef).ShouldNot(BeNil())
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: newNode.Status.CredentialsSecretRef.Name, Namespace: "default"}, &corev1.Secret{})).Should(Succeed())
+
+		// there is no StatefulSet controller in the test environment, so the instance is made ready here
+		sts.Status.Replicas = 1
+		sts.Status.ReadyReplicas = 1
+		Expect(k8sClient.Status().Update(ctx, sts)).Should(Succeed())
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-0", nodeName),
+				Namespace: "default",
+				Labels:    local.SelectorLabels(node),
+			},
+			Spec: sts.Spec.Template.Spec,
+		}
+		Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		Expect(k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+
+		newSN := &v1alpha1.StorageNode{}
+		Eventually(func() v1alpha1.StorageNodePhaseStatus {
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: nodeName, Namespace: "default"}, newSN)).Should(Succeed())
+			return newSN.Status.Phase
+		}, 20*time.Second, 250*time.Millisecond).Should(Equal(v1alpha1.StorageNodePhaseReady))
+		Expect(newSN.Status.Cluster.PrimaryEndpoint).Should(Equal(v1alpha1.Endpoint{Address: local.PrimaryAddress(node), Port: 3306}))
+
+		// the StatefulSet is deleted with the storage node
+		Expect(k8sClient.Delete(ctx, newSN)).Should(Succeed())
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKey{Name: nodeName, Namespace: "default"}, &appsv1.StatefulSet{}))
+		}, 20*time.Second, 250*time.Millisecond).Should(BeTrue())
+		Expect(k8sClient.Delete(ctx, pod)).Should(Succeed())
+	})
+})
@@ -27,11 +27,13 @@ const (
 	OGBackupStatusRunning = "RUNNING"
 	OGBackupStatusOk      = "OK"
 	OGBackupStatusError   = "ERROR"
+	OGBackupStatusCorrupt = "CORRUPT"
 
 	// agent backup status
 	DBBackupStatusRunning   = "Running"
 	DBBackupStatusCompleted = "Completed"
 	DBBackupStatusFailed    = "Failed"
+	DBBackupStatusCorrupt   = "Corrupt"
 	// DBBackupStatusOther is used to indicate that the backup status is not in the above three states. and we will not handle it.
 	// the `Other` status may be some intermediate status, such as `Waiting`, `CheckError`, `Ok` etc.
 	DBBackupStatusOther = "Other"
@@ -67,4 +67,16 @@ var (
 	CmdStopOpenGaussFailed   = xerror.New(10042, "Command `gs_ctl stop` failed.")
 	CmdStatusOpenGaussFailed = xerror.New(10043, "Command `gs_ctl status` failed.")
 	CmdAsyncRestoreFailed    = xerror.New(10044, "Command `gs_ctl restore` failed.")
+	InvalidRecoveryTarget    = xerror.New(10045, "Invalid recovery target.")
+	WriteRecoveryConfFailed  = xerror.New(10046, "Write recovery.conf failed.")
+	InvalidDBName            = xerror.New(10047, "Invalid db name.")
+	RestoreDatabaseFailed    = xerror.New(10048, "Failed to restore the database.")
+	JobNotFound              = xerror.New(10049, "Job not found.")
+	JobAlreadyDone           = xerror.New(10050, "The job is already done.")
+	JobCanceled              = xerror.New(10051, "The job is canceled.")
+	Unauthorized             = xerror.New(10052, "Unauthorized, a valid client certificate or bearer token is required.")
+	Forbidden                = xerror.New(10053, "Forbidden, the role is not allowed to operate.")
+	CmdValidateBackupFailed  = xerror.New(10054, "Command `gs_probackup validate` failed.")
+	CmdMergeBackupFailed     = xerror.New(10055, "Command `gs_probackup merge` failed.")
+	JobNotCancelable         = xerror.New(10056, "The job can not be canceled.")
 )
@@ -20,10 +20,12 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/view"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
 
 	"github.com/gofiber/fiber/v2"
@@ -54,7 +56,11 @@ func Backup(ctx *fiber.Ctx) error {
 		return fmt.Errorf("add instance failed, err wrap: %w", err)
 	}
 
-	backupID, err := pkg.OG.AsyncBackup(in.DnBackupPath, in.Instance, in.DnBackupMode, in.DnThreadsNum, in.DBPort)
+	if in.RequestKey != "" {
+		return backupOnce(ctx, in)
+	}
+
+	backupID, err := pkg.OG.AsyncBackup(in.DnBackupPath, in.Instance, in.DnBackupMode, in.DnThreadsNum, in.DBPort, "")
 	if err != nil {
 		efmt := "pkg.OG.AsyncBackup[path=%s,instance=%s,mode=%s] failure, err wrap: %w"
 		return fmt.Errorf(efmt, in.DnBackupPath, in.Instance, in.DnBackupMode, err)
@@ -65,6 +71,46 @@ func Backup(ctx *fiber.Ctx) error {
 	})
 }
 
+// backupStartPollInterval the interval to check whether the backup of the request key is started
+var backupStartPollInterval = 200 * time.Millisecond
+
+// backupOnce returns the backup of the running or succeeded job of the request key,
+// or starts the backup and returns once the id of the backup is known, the backup goes on in the background.
+func backupOnce(ctx *fiber.Ctx, in *view.BackupIn) error {
+	labels := map[string]string{model.LabelRequestKey: in.RequestKey}
+	if j := pkg.Jobs.Find(model.JobKindBackup, labels); j != nil && j.BackupID != "" && (j.State == model.JobRunning || j.State == model.JobSucceeded) {
+		return responder.Success(ctx, view.BackupOut{ID: j.BackupID})
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		_, err := pkg.OG.AsyncBackup(in.DnBackupPath, in.Instance, in.DnBackupMode, in.DnThreadsNum, in.DBPort, in.RequestKey)
+		done <- err
+	}()
+
+	ticker := time.NewTicker(backupStartPollInterval)
+	defer ticker.Stop()
+	for finished := false; ; {
+		select {
+		case err := <-done:
+			if err != nil {
+				efmt := "pkg.OG.AsyncBackup[path=%s,instance=%s,mode=%s,key=%s] failure, err wrap: %w"
+				return fmt.Errorf(efmt, in.DnBackupPath, in.Instance, in.DnBackupMode, in.RequestKey, err)
+			}
+			finished = true
+		case <-ticker.C:
+		}
+
+		if j := pkg.Jobs.Find(model.JobKindBackup, labels); j != nil && !j.StartAt.Before(start) && j.BackupID != "" {
+			return responder.Success(ctx, view.BackupOut{ID: j.BackupID})
+		}
+		if finished {
+			return fmt.Errorf("no backup id of the backup[key=%s], wrap: %w", in.RequestKey, cons.UnmatchBackupID)
+		}
+	}
+}
+
 func DeleteBackup(ctx *fiber.Ctx) error {
 	in := &view.DeleteBackupIn{}
 	if err := ctx.BodyParser(in); err != nil {
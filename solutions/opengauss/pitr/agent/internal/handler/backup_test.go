@@ -18,12 +18,15 @@
 package handler_test
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
 	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/mocks"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -73,4 +76,75 @@ var _ = Describe("Backup", func() {
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 		})
 	})
+
+	Context("backup with request key", func() {
+		var (
+			mockOG   *mock_pkg.MockIOpenGauss
+			mockJobs *mock_pkg.MockIJobManager
+		)
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockOG = mock_pkg.NewMockIOpenGauss(ctrl)
+			mockJobs = mock_pkg.NewMockIJobManager(ctrl)
+			pkg.OG = mockOG
+			pkg.Jobs = mockJobs
+			mockOG.EXPECT().Auth(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockOG.EXPECT().Init("/tmp").Return(nil)
+			mockOG.EXPECT().AddInstance("/tmp", "instance").Return(nil)
+		})
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		requestBody := `{
+			"db_port": 5432,
+			"db_name": "test_db",
+			"username": "user",
+			"password": "password",
+			"dn_backup_path": "/tmp",
+			"dn_threads_num": 1,
+			"dn_backup_mode": "FULL",
+			"instance": "instance",
+			"request_key": "key"
+		}`
+
+		It("return the backup of the previous request", func() {
+			mockJobs.EXPECT().Find(model.JobKindBackup, map[string]string{model.LabelRequestKey: "key"}).
+				Return(&model.Job{ID: "job-1", State: model.JobRunning, BackupID: "RTKQ1T"})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/backup", strings.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(ContainSubstring(`"backup_id":"RTKQ1T"`))
+		})
+
+		It("start the backup after the previous one failed", func() {
+			gomock.InOrder(
+				mockJobs.EXPECT().Find(model.JobKindBackup, gomock.Any()).
+					Return(&model.Job{ID: "job-1", State: model.JobFailed, BackupID: "RTKQ1T"}),
+				mockJobs.EXPECT().Find(model.JobKindBackup, gomock.Any()).
+					Return(&model.Job{ID: "job-2", State: model.JobRunning, BackupID: "RTKQ2T", StartAt: time.Now().Add(time.Minute)}),
+			)
+			mockOG.EXPECT().AsyncBackup("/tmp", "instance", "FULL", uint8(1), uint16(5432), "key").
+				DoAndReturn(func(_, _, _ string, _ uint8, _ uint16, _ string) (string, error) {
+					time.Sleep(time.Second)
+					return "RTKQ2T", nil
+				})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/backup", strings.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, -1)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(ContainSubstring(`"backup_id":"RTKQ2T"`))
+		})
+	})
 })
@@ -23,7 +23,7 @@ import (
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/view"
-	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/cmds"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
 
 	"github.com/gofiber/fiber/v2"
@@ -40,15 +40,9 @@ func DiskSpace(ctx *fiber.Ctx) error {
 		return fmt.Errorf("invalid parameter, err wrap: %w", err)
 	}
 
-	if err := os.MkdirAll(in.DiskPath, 0755); err != nil {
-		return fmt.Errorf("mkdir [%s] failure, err wrap: %w", in.DiskPath, err)
-	}
-
-	// show disk space
-	cmd := fmt.Sprintf("df -h %s", in.DiskPath)
-	output, err := cmds.Exec(os.Getenv("SHELL"), cmd)
+	output, err := pkg.DiskSpace(os.Getenv("SHELL"), in.DiskPath)
 	if err != nil {
-		return fmt.Errorf("exec cmd [%s] failure, err wrap: %w", cmd, err)
+		return err
 	}
 
 	return responder.Success(ctx, output)
@@ -53,10 +53,19 @@ var _ = BeforeSuite(func() {
 	app.Get("/ping", func(ctx *fiber.Ctx) error {
 		return responder.Success(ctx, "pong")
 	})
+	app.Get("/metrics", handler.Metrics)
 
 	app.Route("/api", func(r fiber.Router) {
 		r.Post("/diskspace", handler.DiskSpace)
+		r.Post("/backup", handler.Backup)
 		r.Delete("/backup", handler.DeleteBackup)
 		r.Post("/healthz", handler.HealthCheck)
+		r.Post("/restore", handler.Restore)
+		r.Post("/restore/database", handler.RestoreDatabase)
+		r.Post("/validate", handler.Validate)
+		r.Post("/merge", handler.Merge)
+		r.Get("/jobs", handler.ListJobs)
+		r.Get("/jobs/:id", handler.GetJob)
+		r.Post("/jobs/:id/cancel", handler.CancelJob)
 	})
 })
@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/middleware"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ListJobs(ctx *fiber.Ctx) error {
+	return responder.Success(ctx, pkg.Jobs.List())
+}
+
+func GetJob(ctx *fiber.Ctx) error {
+	job, err := pkg.Jobs.Get(ctx.Params("id"))
+	if err != nil {
+		return fmt.Errorf("pkg.Jobs.Get return err wrap: %w", err)
+	}
+	return responder.Success(ctx, job)
+}
+
+// CancelJob kills the process group of the job, the job turns to canceled after the processes exit
+func CancelJob(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	if err := pkg.Jobs.Cancel(id); err != nil {
+		return fmt.Errorf("pkg.Jobs.Cancel[id=%s] return err wrap: %w", id, err)
+	}
+	return responder.Success(ctx, nil)
+}
+
+// CancelRole the role required to cancel the job, which is the role required to start it.
+// The job not found requires the operator role, and it is not found by CancelJob either.
+func CancelRole(ctx *fiber.Ctx) middleware.Role {
+	job, err := pkg.Jobs.Get(ctx.Params("id"))
+	if err != nil {
+		return middleware.RoleOperator
+	}
+	switch job.Kind {
+	case model.JobKindRestore, model.JobKindInstanceRestore, model.JobKindMerge:
+		return middleware.RoleAdmin
+	default:
+		return middleware.RoleOperator
+	}
+}
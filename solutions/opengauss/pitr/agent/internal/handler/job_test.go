@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/middleware"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/mocks"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Job", func() {
+	var mockJobs *mock_pkg.MockIJobManager
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockJobs = mock_pkg.NewMockIJobManager(ctrl)
+		pkg.Jobs = mockJobs
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("list jobs", func() {
+		mockJobs.EXPECT().List().Return([]*model.Job{{ID: "job-1", Kind: model.JobKindBackup, State: model.JobRunning, Progress: 42}})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/jobs", nil))
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		out := struct {
+			Data []*model.Job `json:"data"`
+		}{}
+		Expect(json.Unmarshal(body, &out)).To(Succeed())
+		Expect(out.Data).To(HaveLen(1))
+		Expect(out.Data[0].Progress).To(Equal(42))
+	})
+
+	It("get job", func() {
+		mockJobs.EXPECT().Get("job-1").Return(&model.Job{ID: "job-1", State: model.JobSucceeded}, nil)
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/jobs/job-1", nil))
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		mockJobs.EXPECT().Get("job-2").Return(nil, fmt.Errorf("wrap: %w", cons.JobNotFound))
+		resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/jobs/job-2", nil))
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("cancel job", func() {
+		mockJobs.EXPECT().Cancel("job-1").Return(nil)
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/api/jobs/job-1/cancel", nil))
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		mockJobs.EXPECT().Cancel("job-1").Return(fmt.Errorf("wrap: %w", cons.JobAlreadyDone))
+		resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/jobs/job-1/cancel", nil))
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+	})
+	It("cancel role", func() {
+		roles := fiber.New()
+		roles.Post("/jobs/:id/cancel", func(ctx *fiber.Ctx) error {
+			return ctx.SendString(fmt.Sprint(handler.CancelRole(ctx)))
+		})
+		role := func(id string) string {
+			resp, err := roles.Test(httptest.NewRequest(http.MethodPost, "/jobs/"+id+"/cancel", nil))
+			Expect(err).To(BeNil())
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			return string(body)
+		}
+
+		mockJobs.EXPECT().Get("job-1").Return(&model.Job{ID: "job-1", Kind: model.JobKindBackup}, nil)
+		Expect(role("job-1")).To(Equal(fmt.Sprint(middleware.RoleOperator)))
+		mockJobs.EXPECT().Get("job-2").Return(&model.Job{ID: "job-2", Kind: model.JobKindInstanceRestore}, nil)
+		Expect(role("job-2")).To(Equal(fmt.Sprint(middleware.RoleAdmin)))
+		mockJobs.EXPECT().Get("job-3").Return(&model.Job{ID: "job-3", Kind: model.JobKindMerge}, nil)
+		Expect(role("job-3")).To(Equal(fmt.Sprint(middleware.RoleAdmin)))
+		mockJobs.EXPECT().Get("job-4").Return(nil, fmt.Errorf("wrap: %w", cons.JobNotFound))
+		Expect(role("job-4")).To(Equal(fmt.Sprint(middleware.RoleOperator)))
+	})
+})
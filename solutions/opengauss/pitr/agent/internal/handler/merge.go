@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/view"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+/*
+Merge merges the PTRACK backup with its parents into a FULL backup, and responds the merged backup.
+The merged backup takes the id of the FULL backup of the chain or the PTRACK backup, which depends on the version of gs_probackup.
+*/
+func Merge(ctx *fiber.Ctx) error {
+	in := &view.MergeIn{}
+
+	if err := ctx.BodyParser(in); err != nil {
+		return fmt.Errorf("body parse err: %s, wrap: %w", err, cons.BodyParseFailed)
+	}
+
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("invalid parameter, err wrap: %w", err)
+	}
+
+	if err := pkg.OG.Auth(in.Username, in.Password, in.DBName, in.DBPort); err != nil {
+		efmt := "pkg.OG.Auth failure[un=%s,pw.len=%d,db=%s], err wrap: %w"
+		return fmt.Errorf(efmt, in.Username, len(in.Password), in.DBName, err)
+	}
+
+	list, err := pkg.OG.ShowBackupList(in.DnBackupPath, in.Instance)
+	if err != nil {
+		efmt := "pkg.OG.ShowBackupList failure[backupPath=%s,instance=%s], err wrap: %w"
+		return fmt.Errorf(efmt, in.DnBackupPath, in.Instance, err)
+	}
+	rootID, err := chainRoot(list, in.DnBackupID)
+	if err != nil {
+		return err
+	}
+
+	if err := pkg.OG.Merge(in.DnBackupPath, in.Instance, in.DnBackupID, in.DnThreadsNum); err != nil {
+		efmt := "pkg.OG.Merge failure[backupPath=%s,instance=%s,backupID=%s], err wrap: %w"
+		return fmt.Errorf(efmt, in.DnBackupPath, in.Instance, in.DnBackupID, err)
+	}
+
+	data, err := pkg.OG.ShowBackup(in.DnBackupPath, in.Instance, in.DnBackupID)
+	if err != nil || data == nil || data.BackupMode != cons.DBBackModeFull {
+		data, err = pkg.OG.ShowBackup(in.DnBackupPath, in.Instance, rootID)
+	}
+	if err != nil || data == nil {
+		efmt := "pkg.OG.ShowBackup failure[backupPath=%s,instance=%s,backupID=%s], err: %v, wrap: %w"
+		return fmt.Errorf(efmt, in.DnBackupPath, in.Instance, rootID, err, cons.DataNotFound)
+	}
+
+	return responder.Success(ctx, view.NewBackupInfo(data, in.DnBackupPath, in.Instance))
+}
+
+// chainRoot returns the id of the FULL backup which the PTRACK backup depends on
+func chainRoot(list []*model.Backup, backupID string) (string, error) {
+	backups := make(map[string]*model.Backup, len(list))
+	for _, b := range list {
+		backups[b.ID] = b
+	}
+
+	b, ok := backups[backupID]
+	if !ok {
+		return "", fmt.Errorf("backup[id=%s] not found, wrap: %w", backupID, cons.UnmatchBackupID)
+	}
+	if b.BackupMode != cons.DBBackModePTrack {
+		return "", fmt.Errorf("backup[id=%s,mode=%s] is not a PTRACK backup, wrap: %w", backupID, b.BackupMode, cons.InvalidDnBackupMode)
+	}
+
+	for b.BackupMode == cons.DBBackModePTrack {
+		parent, ok := backups[b.ParentBackupID]
+		if !ok {
+			return "", fmt.Errorf("parent backup[id=%s] of backup[id=%s] not found, wrap: %w", b.ParentBackupID, b.ID, cons.UnmatchBackupID)
+		}
+		b = parent
+	}
+	return b.ID, nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/mocks"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merge", func() {
+	var mockOG *mock_pkg.MockIOpenGauss
+	requestBody := `{
+		"db_port": 3306,
+		"db_name": "test_db",
+		"username": "user",
+		"password": "password",
+		"dn_backup_id": "P2",
+		"dn_backup_path": "/tmp",
+		"dn_threads_num": 1,
+		"instance": "instance"
+	}`
+	list := []*model.Backup{
+		{ID: "P2", BackupMode: cons.DBBackModePTrack, ParentBackupID: "P1", Status: cons.OGBackupStatusOk},
+		{ID: "P1", BackupMode: cons.DBBackModePTrack, ParentBackupID: "F1", Status: cons.OGBackupStatusOk},
+		{ID: "F1", BackupMode: cons.DBBackModeFull, Status: cons.OGBackupStatusOk},
+	}
+
+	merge := func(body string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, "/api/merge", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).To(BeNil())
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		out := struct {
+			Data map[string]any `json:"data"`
+		}{}
+		Expect(json.Unmarshal(data, &out)).To(Succeed())
+		return resp.StatusCode, out.Data
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockOG = mock_pkg.NewMockIOpenGauss(ctrl)
+		pkg.OG = mockOG
+		mockOG.EXPECT().Auth(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		mockOG.EXPECT().ShowBackupList("/tmp", "instance").Return(list, nil).AnyTimes()
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("merged into the backup", func() {
+		mockOG.EXPECT().Merge("/tmp", "instance", "P2", uint8(1)).Return(nil)
+		mockOG.EXPECT().ShowBackup("/tmp", "instance", "P2").Return(&model.Backup{ID: "P2", BackupMode: cons.DBBackModeFull, Status: cons.OGBackupStatusOk}, nil)
+
+		code, data := merge(requestBody)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(data["dn_backup_id"]).To(Equal("P2"))
+		Expect(data["db_backup_mode"]).To(Equal(cons.DBBackModeFull))
+	})
+
+	It("merged into the full backup", func() {
+		mockOG.EXPECT().Merge("/tmp", "instance", "P2", uint8(1)).Return(nil)
+		mockOG.EXPECT().ShowBackup("/tmp", "instance", "P2").Return(nil, cons.CmdShowBackupFailed)
+		mockOG.EXPECT().ShowBackup("/tmp", "instance", "F1").Return(&model.Backup{ID: "F1", BackupMode: cons.DBBackModeFull, Status: cons.OGBackupStatusOk}, nil)
+
+		code, data := merge(requestBody)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(data["dn_backup_id"]).To(Equal("F1"))
+	})
+
+	It("not a ptrack backup", func() {
+		code, _ := merge(strings.Replace(requestBody, `"P2"`, `"F1"`, 1))
+		Expect(code).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("merge failed", func() {
+		mockOG.EXPECT().Merge(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(cons.CmdMergeBackupFailed)
+
+		code, _ := merge(requestBody)
+		Expect(code).To(Equal(http.StatusInternalServerError))
+	})
+})
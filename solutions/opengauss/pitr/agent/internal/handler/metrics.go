@@ -0,0 +1,44 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Metrics exposes the agent metrics in the Prometheus text format.
+func Metrics(ctx *fiber.Ctx) error {
+	mfs, err := pkg.Registry.Gather()
+	if err != nil {
+		return fmt.Errorf("gather metrics failure, err wrap: %w", err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, string(expfmt.FmtText))
+	enc := expfmt.NewEncoder(ctx, expfmt.FmtText)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			return fmt.Errorf("encode metric family[%s] failure, err wrap: %w", mf.GetName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/mocks"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	var mockOG *mock_pkg.MockIOpenGauss
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockOG = mock_pkg.NewMockIOpenGauss(ctrl)
+		pkg.OG = mockOG
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("expose the metrics", func() {
+		mockOG.EXPECT().Status().Return(pkg.OGStatusStopped, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		resp, err := app.Test(req)
+		Expect(err).To(BeNil())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/plain"))
+
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		Expect(string(data)).To(ContainSubstring(`pitr_agent_opengauss_status{status="Stopped"} 1`))
+		Expect(string(data)).To(ContainSubstring(`pitr_agent_opengauss_status{status="Running"} 0`))
+		Expect(string(data)).To(ContainSubstring("go_goroutines"))
+	})
+})
@@ -0,0 +1,131 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/logging"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/token"
+)
+
+// Role the roles are ordered, a role is allowed to do all the operations of the lower roles
+type Role int
+
+const (
+	// RoleViewer health check, show backups, disk space, jobs and metrics
+	RoleViewer Role = iota + 1
+	// RoleOperator backup, validate and cancel the backup and validate jobs
+	RoleOperator
+	// RoleAdmin delete backups, restore, merge and cancel the restore and merge jobs
+	RoleAdmin
+)
+
+var roles = map[string]Role{
+	"viewer":   RoleViewer,
+	"operator": RoleOperator,
+	"admin":    RoleAdmin,
+}
+
+func ParseRole(s string) (Role, bool) {
+	r, ok := roles[s]
+	return r, ok
+}
+
+type Auth struct {
+	// tokenKey the key to verify the bearer tokens, tokens are not accepted if it is empty
+	tokenKey []byte
+	// mutualTLS the client certificates are verified by the TLS listener, the role of a client is the OU of its certificate
+	mutualTLS bool
+	log       logging.ILog
+}
+
+func NewAuth(tokenKey []byte, mutualTLS bool, log logging.ILog) *Auth {
+	return &Auth{
+		tokenKey:  tokenKey,
+		mutualTLS: mutualTLS,
+		log:       log,
+	}
+}
+
+// Enabled the requests are not authenticated if neither the tokens nor the client certificates are accepted
+func (a *Auth) Enabled() bool {
+	return len(a.tokenKey) > 0 || a.mutualTLS
+}
+
+// Require authenticates the request with the bearer token or the client certificate, and checks the role is allowed
+func (a *Auth) Require(role Role) fiber.Handler {
+	return a.RequireFunc(func(*fiber.Ctx) Role { return role })
+}
+
+// RequireFunc is like Require, but the role is decided by the request, e.g. canceling a job requires the role of its kind
+func (a *Auth) RequireFunc(roleOf func(ctx *fiber.Ctx) Role) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if !a.Enabled() {
+			return ctx.Next()
+		}
+
+		subject, r, err := a.authenticate(ctx)
+		if err != nil {
+			a.log.Field(logging.RequestID, ctx.Get(cons.RequestID)).Warn(fmt.Sprintf("unauthorized request[path=%s], err: %s", ctx.Path(), err))
+			return responder.Unauthorized(ctx, cons.Unauthorized)
+		}
+		if r < roleOf(ctx) {
+			a.log.Field(logging.RequestID, ctx.Get(cons.RequestID)).Warn(fmt.Sprintf("forbidden request[path=%s,subject=%s]", ctx.Path(), subject))
+			return responder.Forbidden(ctx, cons.Forbidden)
+		}
+		return ctx.Next()
+	}
+}
+
+func (a *Auth) authenticate(ctx *fiber.Ctx) (string, Role, error) {
+	if bearer := ctx.Get(fiber.HeaderAuthorization); bearer != "" {
+		if len(a.tokenKey) == 0 {
+			return "", 0, fmt.Errorf("bearer token is not accepted")
+		}
+		c, err := token.Verify(a.tokenKey, strings.TrimPrefix(bearer, "Bearer "))
+		if err != nil {
+			return "", 0, err
+		}
+		r, ok := ParseRole(c.Role)
+		if !ok {
+			return "", 0, fmt.Errorf("unknown role[%s] of the token", c.Role)
+		}
+		return c.Subject, r, nil
+	}
+
+	if a.mutualTLS {
+		state := ctx.Context().TLSConnectionState()
+		if state == nil || len(state.VerifiedChains) == 0 {
+			return "", 0, fmt.Errorf("no verified client certificate")
+		}
+		cert := state.VerifiedChains[0][0]
+		for _, ou := range cert.Subject.OrganizationalUnit {
+			if r, ok := ParseRole(ou); ok {
+				return cert.Subject.CommonName, r, nil
+			}
+		}
+		return "", 0, fmt.Errorf("no role in the OU of the client certificate[cn=%s]", cert.Subject.CommonName)
+	}
+	return "", 0, fmt.Errorf("missing bearer token")
+}
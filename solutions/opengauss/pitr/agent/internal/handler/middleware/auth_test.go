@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/token"
+
+	"github.com/gofiber/fiber/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auth", func() {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	newApp := func(auth *Auth) *fiber.App {
+		app := fiber.New()
+		ok := func(ctx *fiber.Ctx) error {
+			return responder.Success(ctx, nil)
+		}
+		app.Post("/show", auth.Require(RoleViewer), ok)
+		app.Post("/restore", auth.Require(RoleAdmin), ok)
+		app.Post("/jobs/:id/cancel", auth.RequireFunc(func(ctx *fiber.Ctx) Role {
+			if ctx.Params("id") == "restore" {
+				return RoleAdmin
+			}
+			return RoleOperator
+		}), ok)
+		return app
+	}
+
+	status := func(app *fiber.App, path, bearer string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		resp, err := app.Test(req)
+		Expect(err).To(BeNil())
+		return resp.StatusCode
+	}
+
+	sign := func(k []byte, role string, ttl time.Duration) string {
+		t, err := token.Sign(k, &token.Claims{Subject: "test", Role: role, ExpiresAt: time.Now().Add(ttl).Unix()})
+		Expect(err).To(BeNil())
+		return t
+	}
+
+	It("disabled", func() {
+		app := newApp(NewAuth(nil, false, log))
+		Expect(status(app, "/restore", "")).To(Equal(http.StatusOK))
+	})
+
+	It("bearer tokens with roles", func() {
+		app := newApp(NewAuth(key, false, log))
+
+		Expect(status(app, "/show", "")).To(Equal(http.StatusUnauthorized))
+		Expect(status(app, "/show", sign([]byte("other"), "admin", time.Hour))).To(Equal(http.StatusUnauthorized))
+		Expect(status(app, "/show", sign(key, "admin", -time.Hour))).To(Equal(http.StatusUnauthorized))
+		Expect(status(app, "/show", sign(key, "root", time.Hour))).To(Equal(http.StatusUnauthorized))
+
+		viewer := sign(key, "viewer", time.Hour)
+		Expect(status(app, "/show", viewer)).To(Equal(http.StatusOK))
+		Expect(status(app, "/restore", viewer)).To(Equal(http.StatusForbidden))
+		Expect(status(app, "/restore", sign(key, "operator", time.Hour))).To(Equal(http.StatusForbidden))
+		Expect(status(app, "/restore", sign(key, "admin", time.Hour))).To(Equal(http.StatusOK))
+	})
+
+	It("roles decided by the request", func() {
+		app := newApp(NewAuth(key, false, log))
+
+		operator := sign(key, "operator", time.Hour)
+		Expect(status(app, "/jobs/backup/cancel", sign(key, "viewer", time.Hour))).To(Equal(http.StatusForbidden))
+		Expect(status(app, "/jobs/backup/cancel", operator)).To(Equal(http.StatusOK))
+		Expect(status(app, "/jobs/restore/cancel", operator)).To(Equal(http.StatusForbidden))
+		Expect(status(app, "/jobs/restore/cancel", sign(key, "admin", time.Hour))).To(Equal(http.StatusOK))
+	})
+
+	It("client certificates are required", func() {
+		app := newApp(NewAuth(nil, true, log))
+		Expect(status(app, "/show", "")).To(Equal(http.StatusUnauthorized))
+		Expect(status(app, "/show", sign(key, "admin", time.Hour))).To(Equal(http.StatusUnauthorized))
+	})
+})
@@ -23,30 +23,52 @@ import (
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/view"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func Restore(ctx *fiber.Ctx) (err error) {
+func Restore(ctx *fiber.Ctx) error {
 	in := &view.RestoreIn{}
 
-	if err = ctx.BodyParser(in); err != nil {
-		err = fmt.Errorf("body parse err: %s, wrap: %w", err, cons.BodyParseFailed)
-		return
+	if err := ctx.BodyParser(in); err != nil {
+		return fmt.Errorf("body parse err: %s, wrap: %w", err, cons.BodyParseFailed)
 	}
 
-	if err = in.Validate(); err != nil {
-		err = fmt.Errorf("invalid parameter, err wrap: %w", err)
-		return
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("invalid parameter, err wrap: %w", err)
 	}
 
-	if err = pkg.OG.Auth(in.Username, in.Password, in.DBName, in.DBPort); err != nil {
+	if err := pkg.OG.Auth(in.Username, in.Password, in.DBName, in.DBPort); err != nil {
 		efmt := "pkg.OG.Auth failure[un=%s,pw.len=%d,db=%s], err wrap: %w"
-		err = fmt.Errorf(efmt, in.Username, len(in.Password), in.DBName, err)
-		return
+		return fmt.Errorf(efmt, in.Username, len(in.Password), in.DBName, err)
 	}
 
+	// the restore runs as a job, the request of the same request key returns the job of the previous request
+	if in.Async {
+		labels := map[string]string{"backup_id": in.DnBackupID}
+		if in.RequestKey != "" {
+			labels[model.LabelRequestKey] = in.RequestKey
+			j := pkg.Jobs.Find(model.JobKindInstanceRestore, map[string]string{model.LabelRequestKey: in.RequestKey})
+			if j != nil && (j.State == model.JobRunning || j.State == model.JobSucceeded) {
+				return responder.Success(ctx, view.RestoreOut{JobID: j.ID})
+			}
+		}
+		id := pkg.Jobs.Submit(model.JobKindInstanceRestore, labels, func() error {
+			return restore(in)
+		})
+		return responder.Success(ctx, view.RestoreOut{JobID: id})
+	}
+
+	if err := restore(in); err != nil {
+		return err
+	}
+	return responder.Success(ctx, nil)
+}
+
+// restore stops openGauss, restores the backup to pgdata and starts openGauss, the old pgdata is moved back on failure
+func restore(in *view.RestoreIn) (err error) {
 	// stop openGauss
 	if err = pkg.OG.Stop(); err != nil {
 		err = fmt.Errorf("stop openGauss failure, err wrap: %w", err)
@@ -83,7 +105,7 @@ func Restore(ctx *fiber.Ctx) (err error) {
 	}()
 
 	// restore data from backup
-	if err = pkg.OG.Restore(in.DnBackupPath, in.Instance, in.DnBackupID, in.DnThreadsNum); err != nil {
+	if err = pkg.OG.Restore(in.DnBackupPath, in.Instance, in.DnBackupID, in.DnThreadsNum, in.RecoveryTarget); err != nil {
 		status = "restore failure"
 		return
 	}
@@ -99,6 +121,30 @@ func Restore(ctx *fiber.Ctx) (err error) {
 		err = fmt.Errorf("pkg.OG.Start return err wrap: %w", err)
 		return
 	}
+	return nil
+}
+
+// RestoreDatabase restores one database of openGauss from the backup, openGauss keeps running
+func RestoreDatabase(ctx *fiber.Ctx) error {
+	in := &view.RestoreDatabaseIn{}
+
+	if err := ctx.BodyParser(in); err != nil {
+		return fmt.Errorf("body parse err: %s, wrap: %w", err, cons.BodyParseFailed)
+	}
+
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("invalid parameter, err wrap: %w", err)
+	}
+
+	if err := pkg.OG.Auth(in.Username, in.Password, in.DBName, in.DBPort); err != nil {
+		efmt := "pkg.OG.Auth failure[un=%s,pw.len=%d,db=%s], err wrap: %w"
+		return fmt.Errorf(efmt, in.Username, len(in.Password), in.DBName, err)
+	}
+
+	if err := pkg.OG.RestoreDatabase(in.DnBackupPath, in.Instance, in.DnBackupID, in.DnThreadsNum, in.RecoveryTarget, in.DBName, in.DBPort); err != nil {
+		efmt := "pkg.OG.RestoreDatabase[path=%s,instance=%s,backupID=%s,db=%s] failure, err wrap: %w"
+		return fmt.Errorf(efmt, in.DnBackupPath, in.Instance, in.DnBackupID, in.DBName, err)
+	}
 
 	return responder.Success(ctx, nil)
 }
@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/mocks"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Restore", func() {
+	Context("restore database", func() {
+		var mockOG *mock_pkg.MockIOpenGauss
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockOG = mock_pkg.NewMockIOpenGauss(ctrl)
+			pkg.OG = mockOG
+		})
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("restore failed with invalid db name", func() {
+			requestBody := `{
+				"db_port": 5432,
+				"db_name": "test_db; drop",
+				"username": "user",
+				"password": "password",
+				"instance": "instance",
+				"dn_backup_path": "/tmp",
+				"dn_backup_id": "backup_id"
+			}`
+			req := httptest.NewRequest(http.MethodPost, "/api/restore/database", strings.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(500))
+		})
+
+		It("restore success", func() {
+			requestBody := `{
+				"db_port": 5432,
+				"db_name": "test_db",
+				"username": "user",
+				"password": "password",
+				"instance": "instance",
+				"dn_backup_path": "/tmp",
+				"dn_backup_id": "backup_id",
+				"dn_threads_num": 2,
+				"target_csn": "3012"
+			}`
+
+			mockOG.EXPECT().Auth(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			mockOG.EXPECT().RestoreDatabase("/tmp", "instance", "backup_id", uint8(2), &model.RecoveryTarget{CSN: "3012"}, "test_db", uint16(5432)).Return(nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/restore/database", strings.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("restore asynchronously", func() {
+		var (
+			mockOG   *mock_pkg.MockIOpenGauss
+			mockJobs *mock_pkg.MockIJobManager
+		)
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockOG = mock_pkg.NewMockIOpenGauss(ctrl)
+			mockJobs = mock_pkg.NewMockIJobManager(ctrl)
+			pkg.OG = mockOG
+			pkg.Jobs = mockJobs
+			mockOG.EXPECT().Auth(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		})
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		requestBody := `{
+			"db_port": 5432,
+			"db_name": "test_db",
+			"username": "user",
+			"password": "password",
+			"instance": "instance",
+			"dn_backup_path": "/tmp",
+			"dn_backup_id": "backup_id",
+			"async": true,
+			"request_key": "key"
+		}`
+
+		It("submit the restore job", func() {
+			mockJobs.EXPECT().Find(model.JobKindInstanceRestore, map[string]string{model.LabelRequestKey: "key"}).Return(nil)
+			mockJobs.EXPECT().Submit(model.JobKindInstanceRestore, map[string]string{"backup_id": "backup_id", model.LabelRequestKey: "key"}, gomock.Any()).Return("job-1")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/restore", strings.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(ContainSubstring(`"job_id":"job-1"`))
+		})
+
+		It("return the job of the previous request", func() {
+			mockJobs.EXPECT().Find(model.JobKindInstanceRestore, gomock.Any()).Return(&model.Job{ID: "job-1", State: model.JobRunning})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/restore", strings.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			Expect(err).To(BeNil())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(ContainSubstring(`"job_id":"job-1"`))
+		})
+	})
+})
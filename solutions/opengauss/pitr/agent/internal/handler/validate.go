@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/view"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Validate validates the backup by `gs_probackup validate`, a backup which is not valid is not an error of the request
+func Validate(ctx *fiber.Ctx) error {
+	in := &view.ValidateIn{}
+
+	if err := ctx.BodyParser(in); err != nil {
+		return fmt.Errorf("body parse err: %s, wrap: %w", err, cons.BodyParseFailed)
+	}
+
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("invalid parameter, err wrap: %w", err)
+	}
+
+	if err := pkg.OG.Auth(in.Username, in.Password, in.DBName, in.DBPort); err != nil {
+		efmt := "pkg.OG.Auth failure[un=%s,pw.len=%d,db=%s], err wrap: %w"
+		return fmt.Errorf(efmt, in.Username, len(in.Password), in.DBName, err)
+	}
+
+	out := view.ValidateOut{Valid: true}
+	if err := pkg.OG.Validate(in.DnBackupPath, in.Instance, in.DnBackupID, in.DnThreadsNum); err != nil {
+		if !errors.Is(err, cons.CmdValidateBackupFailed) {
+			efmt := "pkg.OG.Validate failure[backupPath=%s,instance=%s,backupID=%s], err wrap: %w"
+			return fmt.Errorf(efmt, in.DnBackupPath, in.Instance, in.DnBackupID, err)
+		}
+		out.Valid = false
+		out.Msg = err.Error()
+	}
+
+	data, err := pkg.OG.ShowBackup(in.DnBackupPath, in.Instance, in.DnBackupID)
+	if err != nil {
+		efmt := "pkg.OG.ShowBackup failure[backupPath=%s,instance=%s,backupID=%s], err wrap: %w"
+		return fmt.Errorf(efmt, in.DnBackupPath, in.Instance, in.DnBackupID, err)
+	}
+	out.Backup = view.NewBackupInfo(data, in.DnBackupPath, in.Instance)
+	if out.Backup == nil || out.Backup.Status != cons.DBBackupStatusCompleted {
+		out.Valid = false
+	}
+
+	return responder.Success(ctx, out)
+}
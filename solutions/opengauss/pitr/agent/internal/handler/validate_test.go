@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/mocks"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	var mockOG *mock_pkg.MockIOpenGauss
+	requestBody := `{
+		"db_port": 3306,
+		"db_name": "test_db",
+		"username": "user",
+		"password": "password",
+		"dn_backup_id": "RS3ZGQ",
+		"dn_backup_path": "/tmp",
+		"dn_threads_num": 1,
+		"instance": "instance"
+	}`
+
+	validate := func(body string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).To(BeNil())
+
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		out := struct {
+			Data map[string]any `json:"data"`
+		}{}
+		Expect(json.Unmarshal(data, &out)).To(Succeed())
+		return resp.StatusCode, out.Data
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockOG = mock_pkg.NewMockIOpenGauss(ctrl)
+		pkg.OG = mockOG
+		mockOG.EXPECT().Auth(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("missing threads num", func() {
+		code, _ := validate(`{"db_port":3306,"db_name":"test_db","username":"user","password":"password","dn_backup_id":"RS3ZGQ","dn_backup_path":"/tmp","instance":"instance"}`)
+		Expect(code).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("valid backup", func() {
+		mockOG.EXPECT().Validate("/tmp", "instance", "RS3ZGQ", uint8(1)).Return(nil)
+		mockOG.EXPECT().ShowBackup("/tmp", "instance", "RS3ZGQ").Return(&model.Backup{
+			ID:         "RS3ZGQ",
+			Status:     cons.OGBackupStatusOk,
+			StartLsn:   "0/2000028",
+			StopLsn:    "0/20001C0",
+			ContentCrc: 1365742592,
+		}, nil)
+
+		code, data := validate(requestBody)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(data["valid"]).To(BeTrue())
+		backup := data["backup"].(map[string]any)
+		Expect(backup["status"]).To(Equal(cons.DBBackupStatusCompleted))
+		Expect(backup["start_lsn"]).To(Equal("0/2000028"))
+		Expect(backup["content_crc"]).To(BeEquivalentTo(1365742592))
+	})
+
+	It("corrupt backup", func() {
+		mockOG.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("ERROR: Backup RS3ZGQ is corrupt, wrap: %w", cons.CmdValidateBackupFailed))
+		mockOG.EXPECT().ShowBackup(gomock.Any(), gomock.Any(), gomock.Any()).Return(&model.Backup{
+			ID:     "RS3ZGQ",
+			Status: cons.OGBackupStatusCorrupt,
+		}, nil)
+
+		code, data := validate(requestBody)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(data["valid"]).To(BeFalse())
+		Expect(data["msg"]).To(ContainSubstring("is corrupt"))
+		Expect(data["backup"].(map[string]any)["status"]).To(Equal(cons.DBBackupStatusCorrupt))
+	})
+
+	It("validate failed", func() {
+		mockOG.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(cons.CmdOperateFailed)
+
+		code, _ := validate(requestBody)
+		Expect(code).To(Equal(http.StatusInternalServerError))
+	})
+})
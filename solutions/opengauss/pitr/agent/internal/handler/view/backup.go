@@ -32,6 +32,9 @@ type (
 		DnThreadsNum uint8  `json:"dn_threads_num"`
 		DnBackupMode string `json:"dn_backup_mode"`
 		Instance     string `json:"instance"`
+		// RequestKey makes the backup idempotent, the request returns once the backup is started,
+		// and the backup of the running or succeeded job of the same key is returned
+		RequestKey string `json:"request_key"`
 	}
 
 	BackupOut struct {
@@ -17,7 +17,20 @@
 
 package view
 
-import "github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+)
+
+var (
+	lsnRegexp = regexp.MustCompile(`^[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8}$`)
+	// the name is put in the shell commands of gs_dump and gs_restore, so `$` is not allowed
+	dbNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+)
 
 type RestoreIn struct {
 	DBPort       uint16 `json:"db_port"`
@@ -28,6 +41,18 @@ type RestoreIn struct {
 	DnBackupPath string `json:"dn_backup_path"`
 	DnBackupID   string `json:"dn_backup_id"`
 	DnThreadsNum uint8  `json:"dn_threads_num"`
+
+	// Async runs the restore as a job and returns the id of the job
+	Async bool `json:"async"`
+	// RequestKey makes the async restore idempotent, the running or succeeded job of the same key is returned
+	RequestKey string `json:"request_key"`
+
+	// replay the WAL to the target after the backup is restored if it is set
+	*model.RecoveryTarget
+}
+
+type RestoreOut struct {
+	JobID string `json:"job_id"`
 }
 
 //nolint:dupl
@@ -63,5 +88,56 @@ func (in *RestoreIn) Validate() error {
 	if in.Instance == "" {
 		return cons.MissingInstance
 	}
+
+	if in.RecoveryTarget != nil {
+		return validateRecoveryTarget(in.RecoveryTarget)
+	}
+	return nil
+}
+
+// RestoreDatabaseIn restores the database DBName only, the other databases of openGauss keep online
+type RestoreDatabaseIn struct {
+	RestoreIn
+}
+
+func (in *RestoreDatabaseIn) Validate() error {
+	if in == nil {
+		return cons.InvalidHTTPRequestBody
+	}
+
+	if err := in.RestoreIn.Validate(); err != nil {
+		return err
+	}
+
+	if !dbNameRegexp.MatchString(in.DBName) {
+		return cons.InvalidDBName
+	}
+	return nil
+}
+
+func validateRecoveryTarget(t *model.RecoveryTarget) error {
+	set := 0
+	if t.Time != "" {
+		if _, err := time.Parse(model.RecoveryTargetTimeLayout, t.Time); err != nil {
+			return cons.InvalidRecoveryTarget
+		}
+		set++
+	}
+	if t.CSN != "" {
+		if _, err := strconv.ParseUint(t.CSN, 10, 64); err != nil {
+			return cons.InvalidRecoveryTarget
+		}
+		set++
+	}
+	if t.LSN != "" {
+		if !lsnRegexp.MatchString(t.LSN) {
+			return cons.InvalidRecoveryTarget
+		}
+		set++
+	}
+
+	if set != 1 {
+		return cons.InvalidRecoveryTarget
+	}
 	return nil
 }
@@ -43,13 +43,19 @@ type (
 	}
 
 	BackupInfo struct {
-		ID        string `json:"dn_backup_id"`
-		Path      string `json:"dn_backup_path"`
-		Mode      string `json:"db_backup_mode"`
+		ID   string `json:"dn_backup_id"`
+		Path string `json:"dn_backup_path"`
+		Mode string `json:"db_backup_mode"`
+		// ParentID is the id of the parent backup of a PTRACK backup
+		ParentID  string `json:"parent_backup_id,omitempty"`
 		Instance  string `json:"instance"`
 		StartTime string `json:"start_time"`
 		EndTime   string `json:"end_time"`
+		StartLsn  string `json:"start_lsn"`
+		StopLsn   string `json:"stop_lsn"`
 		Status    string `json:"status"`
+		// ContentCrc is the crc of the backup content, which changes if the files of the backup change
+		ContentCrc int64 `json:"content_crc"`
 	}
 )
 
@@ -94,13 +100,17 @@ func NewBackupInfo(data *model.Backup, path, instance string) *BackupInfo {
 		return nil
 	}
 	return &BackupInfo{
-		ID:        data.ID,
-		Path:      path,
-		Mode:      data.BackupMode,
-		Instance:  instance,
-		StartTime: data.StartTime,
-		EndTime:   data.EndTime,
-		Status:    statusTrans(data.Status),
+		ID:         data.ID,
+		Path:       path,
+		Mode:       data.BackupMode,
+		ParentID:   data.ParentBackupID,
+		Instance:   instance,
+		StartTime:  data.StartTime,
+		EndTime:    data.EndTime,
+		StartLsn:   data.StartLsn,
+		StopLsn:    data.StopLsn,
+		Status:     statusTrans(data.Status),
+		ContentCrc: data.ContentCrc,
 	}
 }
 
@@ -111,13 +121,17 @@ func NewBackupInfoList(list []*model.Backup, path, instance string) []BackupInfo
 	ret := make([]BackupInfo, 0, len(list))
 	for _, v := range list {
 		ret = append(ret, BackupInfo{
-			ID:        v.ID,
-			Path:      path,
-			Mode:      v.BackupMode,
-			Instance:  instance,
-			StartTime: v.StartTime,
-			EndTime:   v.EndTime,
-			Status:    statusTrans(v.Status),
+			ID:         v.ID,
+			Path:       path,
+			Mode:       v.BackupMode,
+			ParentID:   v.ParentBackupID,
+			Instance:   instance,
+			StartTime:  v.StartTime,
+			EndTime:    v.EndTime,
+			StartLsn:   v.StartLsn,
+			StopLsn:    v.StopLsn,
+			Status:     statusTrans(v.Status),
+			ContentCrc: v.ContentCrc,
 		})
 	}
 	return ret
@@ -131,6 +145,8 @@ func statusTrans(status string) string {
 		return cons.DBBackupStatusFailed
 	case cons.OGBackupStatusRunning:
 		return cons.DBBackupStatusRunning
+	case cons.OGBackupStatusCorrupt:
+		return cons.DBBackupStatusCorrupt
 	default:
 		return cons.DBBackupStatusOther
 	}
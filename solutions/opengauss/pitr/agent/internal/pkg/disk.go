@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/cmds"
+)
+
+const (
+	_diskSpaceFmt = "df -h %s"
+	// POSIX output format with 1024-byte blocks, the available blocks is the 4th column
+	_diskFreeFmt = "df -P -k %s"
+)
+
+// DiskSpace shows the disk space of the disk path in the human-readable format, the path is created if it does not exist.
+func DiskSpace(shell, diskPath string) (string, error) {
+	if err := os.MkdirAll(diskPath, 0755); err != nil {
+		return "", fmt.Errorf("mkdir [%s] failure, err wrap: %w", diskPath, err)
+	}
+
+	cmd := fmt.Sprintf(_diskSpaceFmt, diskPath)
+	output, err := cmds.Exec(shell, cmd)
+	if err != nil {
+		return "", fmt.Errorf("exec cmd [%s] failure, err wrap: %w", cmd, err)
+	}
+	return output, nil
+}
+
+// DiskFree returns the available bytes of the disk which the disk path is on.
+func DiskFree(shell, diskPath string) (int64, error) {
+	cmd := fmt.Sprintf(_diskFreeFmt, diskPath)
+	output, err := cmds.Exec(shell, cmd)
+	if err != nil {
+		return 0, fmt.Errorf("exec cmd [%s] failure, err wrap: %w", cmd, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return 0, fmt.Errorf("unexpected output of cmd [%s]: %s", cmd, output)
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return 0, fmt.Errorf("unexpected output of cmd [%s]: %s", cmd, output)
+	}
+	blocks, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse available blocks [%s] failure, err wrap: %w", fields[3], err)
+	}
+	return blocks * 1024, nil
+}
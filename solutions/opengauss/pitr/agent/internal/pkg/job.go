@@ -0,0 +1,474 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/cmds"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/logging"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/strutil"
+)
+
+type (
+	jobManager struct {
+		shell string
+		dir   string
+		log   logging.ILog
+
+		mu   sync.RWMutex
+		jobs map[string]*job
+	}
+
+	job struct {
+		*model.Job
+		canceled bool
+		savedAt  time.Time
+	}
+
+	/*
+		IJobManager runs the long-running commands as jobs:
+
+		every job is persisted to a json file under the job dir, the jobs which were running
+		when the agent exited are recovered at the agent startup.
+	*/
+	IJobManager interface {
+		// Run runs the command as a job until it exits, fn is called with every output of the command.
+		Run(kind string, labels map[string]string, cmd string, fn func(output *cmds.Output) error) error
+		// Submit runs fn as a job in the background and returns the id of the job, the job can not be canceled.
+		Submit(kind string, labels map[string]string, fn func() error) string
+		List() []*model.Job
+		Get(id string) (*model.Job, error)
+		// Find returns the latest job of the kind with all the labels, or nil.
+		Find(kind string, labels map[string]string) *model.Job
+		// Cancel kills the process group of the job.
+		Cancel(id string) error
+	}
+)
+
+var _ IJobManager = (*jobManager)(nil)
+
+const (
+	_jobLogTailLines = 20
+	_jobRetainNum    = 100
+	_jobFileExt      = ".json"
+
+	_interruptedErr = "the agent restarted while the job was running, the result is unknown"
+)
+
+var (
+	jobSaveInterval  = time.Second
+	jobWatchInterval = 2 * time.Second
+	jobKillTimeout   = 10 * time.Second
+
+	// e.g. INFO: Progress: (12/1024). Process file "base/15590/2608"
+	progressRegexp = regexp.MustCompile(`Progress: \((\d+)/(\d+)\)`)
+	// e.g. INFO: Backup start, gs_probackup version: 2.4.2, instance: ins-default-0, backup ID: RTKQ1T, backup mode: FULL
+	backupIDRegexp = regexp.MustCompile(`backup ID:\s+(\w+),`)
+	// e.g. INFO: PGDATA size: 38MB
+	pgDataSizeRegexp = regexp.MustCompile(`PGDATA size: (\d+(?:\.\d+)?)(B|kB|MB|GB|TB)`)
+	sizeUnits        = map[string]float64{"B": 1, "kB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30, "TB": 1 << 40}
+)
+
+func NewJobManager(shell, dir string, log logging.ILog) (IJobManager, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("mkdir job dir[%s] failure, err: %w", dir, err)
+	}
+
+	m := &jobManager{
+		shell: shell,
+		dir:   dir,
+		log:   log,
+		jobs:  map[string]*job{},
+	}
+	if err := m.recover(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *jobManager) Run(kind string, labels map[string]string, cmd string, fn func(output *cmds.Output) error) error {
+	pid, outputs, err := cmds.AsyncExecGroup(m.shell, cmd)
+	if err != nil {
+		return fmt.Errorf("cmds.AsyncExecGroup[shell=%s,cmd=%s] return err: %s, wrap: %w", m.shell, cmd, err, cons.CmdOperateFailed)
+	}
+
+	// the start time is unknown if the process has exited, its group is not signaled any more
+	startTime, err := cmds.StartTime(pid)
+	if err != nil {
+		m.log.Warn(err.Error())
+	}
+
+	j := m.create(kind, labels, pid, startTime)
+	m.log.Field("job_id", j.ID).Info(fmt.Sprintf("job[kind=%s,pid=%d] started", kind, pid))
+
+	var runErr error
+	for output := range outputs {
+		m.update(j, output)
+		// drain the outputs after the job failed
+		if runErr != nil {
+			continue
+		}
+
+		if fn != nil {
+			err = fn(output)
+		} else {
+			err = output.Error
+		}
+		if err != nil {
+			runErr = err
+			if kerr := m.kill(j.ID, pid, startTime, syscall.SIGKILL); kerr != nil {
+				m.log.Field("job_id", j.ID).Error(kerr.Error())
+			}
+		}
+	}
+	return m.finish(j, runErr)
+}
+
+func (m *jobManager) Submit(kind string, labels map[string]string, fn func() error) string {
+	j := m.create(kind, labels, 0, 0)
+	m.log.Field("job_id", j.ID).Info(fmt.Sprintf("job[kind=%s] submitted", kind))
+
+	go func() {
+		if err := m.finish(j, fn()); err != nil {
+			m.log.Field("job_id", j.ID).Error(err.Error())
+		}
+	}()
+	return j.ID
+}
+
+func (m *jobManager) List() []*model.Job {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]*model.Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		list = append(list, j.snapshot())
+	}
+	// the latest job first
+	sort.Slice(list, func(i, k int) bool {
+		return list[i].StartAt.After(list[k].StartAt)
+	})
+	return list
+}
+
+func (m *jobManager) Get(id string) (*model.Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job[id=%s] not found, wrap: %w", id, cons.JobNotFound)
+	}
+	return j.snapshot(), nil
+}
+
+func (m *jobManager) Find(kind string, labels map[string]string) *model.Job {
+	for _, j := range m.List() {
+		if j.Kind != kind {
+			continue
+		}
+		matched := true
+		for k, v := range labels {
+			if j.Labels[k] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return j
+		}
+	}
+	return nil
+}
+
+func (m *jobManager) Cancel(id string) error {
+	m.mu.Lock()
+	j, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("job[id=%s] not found, wrap: %w", id, cons.JobNotFound)
+	}
+	if j.Done() {
+		m.mu.Unlock()
+		return fmt.Errorf("job[id=%s,state=%s], wrap: %w", id, j.State, cons.JobAlreadyDone)
+	}
+	if j.Pid == 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("job[id=%s,kind=%s], wrap: %w", id, j.Kind, cons.JobNotCancelable)
+	}
+	j.canceled = true
+	pid, startTime := j.Pid, j.PidStartTime
+	m.mu.Unlock()
+
+	if err := m.kill(id, pid, startTime, syscall.SIGTERM); err != nil {
+		return err
+	}
+	m.log.Field("job_id", id).Info(fmt.Sprintf("job[pid=%d] canceled", pid))
+
+	// kill the process group if the command ignores the SIGTERM
+	go func() {
+		time.Sleep(jobKillTimeout)
+		if j := m.job(id); j != nil && !j.Done() {
+			if err := m.kill(id, pid, startTime, syscall.SIGKILL); err != nil {
+				m.log.Field("job_id", id).Error(err.Error())
+			}
+		}
+	}()
+	return nil
+}
+
+// kill signals the process group of the job, the group is skipped if it has exited or its pid was reused.
+func (m *jobManager) kill(id string, pid int, startTime uint64, sig syscall.Signal) error {
+	if !cmds.GroupAliveStartedAt(pid, startTime) {
+		m.log.Field("job_id", id).Warn(fmt.Sprintf("process group[pgid=%d] of the job is gone, signal %s is skipped", pid, sig))
+		return nil
+	}
+	return cmds.KillGroup(pid, sig)
+}
+
+func (m *jobManager) job(id string) *model.Job {
+	j, err := m.Get(id)
+	if err != nil {
+		return nil
+	}
+	return j
+}
+
+func (m *jobManager) create(kind string, labels map[string]string, pid int, startTime uint64) *job {
+	j := &job{
+		Job: &model.Job{
+			ID:           fmt.Sprintf("%s-%s", time.Now().Format("20060102150405"), strutil.Random(6)),
+			Kind:         kind,
+			State:        model.JobRunning,
+			Labels:       labels,
+			Pid:          pid,
+			PidStartTime: startTime,
+			LogTail:      []string{},
+			StartAt:      time.Now(),
+		},
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[j.ID] = j
+	m.prune()
+	m.save(j)
+	return j
+}
+
+func (m *jobManager) update(j *job, output *cmds.Output) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if output.Message != "" {
+		j.LogTail = append(j.LogTail, output.Message)
+		if len(j.LogTail) > _jobLogTailLines {
+			j.LogTail = j.LogTail[len(j.LogTail)-_jobLogTailLines:]
+		}
+		parseProgress(j.Job, output.Message)
+		if j.Kind == model.JobKindBackup && j.BackupID == "" {
+			if m := backupIDRegexp.FindStringSubmatch(output.Message); m != nil {
+				j.BackupID = m[1]
+			}
+		}
+	}
+
+	if time.Since(j.savedAt) >= jobSaveInterval {
+		m.save(j)
+	}
+}
+
+func (m *jobManager) finish(j *job, runErr error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	j.EndAt = &now
+	switch {
+	case j.canceled:
+		j.State = model.JobCanceled
+		runErr = fmt.Errorf("job[id=%s] canceled, wrap: %w", j.ID, cons.JobCanceled)
+		j.Error = runErr.Error()
+	case runErr != nil:
+		j.State = model.JobFailed
+		j.Error = runErr.Error()
+	default:
+		j.State = model.JobSucceeded
+		j.Progress = 100
+		j.DoneBytes = j.TotalBytes
+	}
+	m.save(j)
+
+	m.log.Field("job_id", j.ID).Info(fmt.Sprintf("job[kind=%s] %s", j.Kind, j.State))
+	return runErr
+}
+
+// recover loads the jobs from the job dir, the running jobs whose process group is gone or whose pid was reused are interrupted.
+func (m *jobManager) recover() error {
+	files, err := filepath.Glob(filepath.Join(m.dir, "*"+_jobFileExt))
+	if err != nil {
+		return fmt.Errorf("list job files failure, err: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return fmt.Errorf("read job file[%s] failure, err: %w", f, err)
+		}
+		j := &job{Job: &model.Job{}}
+		if err := json.Unmarshal(data, j.Job); err != nil {
+			m.log.Error(fmt.Sprintf("invalid job file[%s] is skipped, err: %s", f, err))
+			continue
+		}
+		m.jobs[j.ID] = j
+
+		if j.Done() {
+			continue
+		}
+		// the jobs without a command exited with the agent
+		if j.Pid != 0 && cmds.GroupAliveStartedAt(j.Pid, j.PidStartTime) {
+			go m.watch(j)
+			continue
+		}
+		m.interrupt(j)
+	}
+	return nil
+}
+
+// watch waits for the process group of a recovered job to exit, the output of the job is lost.
+func (m *jobManager) watch(j *job) {
+	for {
+		time.Sleep(jobWatchInterval)
+		if !cmds.GroupAliveStartedAt(j.Pid, j.PidStartTime) {
+			break
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.interrupt(j)
+}
+
+func (m *jobManager) interrupt(j *job) {
+	now := time.Now()
+	j.EndAt = &now
+	if j.canceled {
+		j.State = model.JobCanceled
+		j.Error = cons.JobCanceled.Error()
+	} else {
+		j.State = model.JobInterrupted
+		j.Error = _interruptedErr
+	}
+	m.save(j)
+}
+
+// prune removes the oldest done jobs, keeps _jobRetainNum jobs at most.
+func (m *jobManager) prune() {
+	if len(m.jobs) <= _jobRetainNum {
+		return
+	}
+
+	done := make([]*job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		if j.Done() {
+			done = append(done, j)
+		}
+	}
+	sort.Slice(done, func(i, k int) bool {
+		return done[i].StartAt.Before(done[k].StartAt)
+	})
+
+	for i := 0; i < len(m.jobs)-_jobRetainNum && i < len(done); i++ {
+		delete(m.jobs, done[i].ID)
+		if err := os.Remove(m.path(done[i].ID)); err != nil && !os.IsNotExist(err) {
+			m.log.Error(fmt.Sprintf("remove job file failure, err: %s", err))
+		}
+	}
+}
+
+// save writes the job to a temp file, then renames it to keep the job file intact.
+func (m *jobManager) save(j *job) {
+	j.savedAt = time.Now()
+	data, err := json.Marshal(j.Job)
+	if err != nil {
+		m.log.Error(fmt.Sprintf("json.Marshal job[id=%s] failure, err: %s", j.ID, err))
+		return
+	}
+
+	tmp := m.path(j.ID) + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		m.log.Error(fmt.Sprintf("write job file[%s] failure, err: %s", tmp, err))
+		return
+	}
+	if err = os.Rename(tmp, m.path(j.ID)); err != nil {
+		m.log.Error(fmt.Sprintf("rename job file[%s] failure, err: %s", tmp, err))
+	}
+}
+
+func (m *jobManager) path(id string) string {
+	return filepath.Join(m.dir, id+_jobFileExt)
+}
+
+func (j *job) snapshot() *model.Job {
+	s := *j.Job
+	s.LogTail = append([]string{}, j.LogTail...)
+	if j.Labels != nil {
+		s.Labels = make(map[string]string, len(j.Labels))
+		for k, v := range j.Labels {
+			s.Labels[k] = v
+		}
+	}
+	return &s
+}
+
+// parseProgress parses the progress of gs_probackup, the done bytes is estimated by the progress of files.
+func parseProgress(j *model.Job, msg string) {
+	if m := pgDataSizeRegexp.FindStringSubmatch(msg); m != nil {
+		size, _ := strconv.ParseFloat(m[1], 64)
+		j.TotalBytes = int64(size * sizeUnits[m[2]])
+		return
+	}
+
+	if !strings.Contains(msg, "Progress") {
+		return
+	}
+	if m := progressRegexp.FindStringSubmatch(msg); m != nil {
+		done, _ := strconv.ParseInt(m[1], 10, 64)
+		total, _ := strconv.ParseInt(m[2], 10, 64)
+		if total == 0 || done > total {
+			return
+		}
+		j.Progress = int(done * 100 / total)
+		j.DoneBytes = j.TotalBytes * done / total
+	}
+}
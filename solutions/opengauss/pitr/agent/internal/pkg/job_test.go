@@ -0,0 +1,191 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/cmds"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JobManager", func() {
+	var (
+		dir  string
+		jobs IJobManager
+	)
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		var err error
+		jobs, err = NewJobManager("/bin/sh", dir, log)
+		Expect(err).To(BeNil())
+	})
+
+	It("run a job with progress", func() {
+		cmd := `echo "INFO: PGDATA size: 2MB"; echo "INFO: Progress: (1/4). Process file"; echo "INFO: Progress: (2/4). Process file"`
+		var lines []string
+		err := jobs.Run(model.JobKindBackup, map[string]string{"instance": "ins"}, cmd, func(output *cmds.Output) error {
+			lines = append(lines, output.Message)
+			return output.Error
+		})
+		Expect(err).To(BeNil())
+		Expect(lines).To(HaveLen(3))
+
+		list := jobs.List()
+		Expect(list).To(HaveLen(1))
+		j := list[0]
+		Expect(j.State).To(Equal(model.JobSucceeded))
+		Expect(j.Progress).To(Equal(100))
+		Expect(j.TotalBytes).To(Equal(int64(2 << 20)))
+		Expect(j.LogTail).To(Equal(lines))
+		Expect(j.Labels).To(HaveKeyWithValue("instance", "ins"))
+		Expect(j.EndAt).NotTo(BeNil())
+
+		data, err := os.ReadFile(filepath.Join(dir, j.ID+".json"))
+		Expect(err).To(BeNil())
+		saved := &model.Job{}
+		Expect(json.Unmarshal(data, saved)).To(Succeed())
+		Expect(saved.State).To(Equal(model.JobSucceeded))
+	})
+
+	It("find the backup of a request key", func() {
+		cmd := `echo "INFO: Backup start, gs_probackup version: 2.4.2, instance: ins, backup ID: RTKQ1T, backup mode: FULL"`
+		Expect(jobs.Run(model.JobKindBackup, map[string]string{model.LabelRequestKey: "key"}, cmd, nil)).To(Succeed())
+		Expect(jobs.Run(model.JobKindBackup, map[string]string{model.LabelRequestKey: "other"}, "echo", nil)).To(Succeed())
+
+		j := jobs.Find(model.JobKindBackup, map[string]string{model.LabelRequestKey: "key"})
+		Expect(j).NotTo(BeNil())
+		Expect(j.BackupID).To(Equal("RTKQ1T"))
+		Expect(jobs.Find(model.JobKindRestore, map[string]string{model.LabelRequestKey: "key"})).To(BeNil())
+		Expect(jobs.Find(model.JobKindBackup, map[string]string{model.LabelRequestKey: "none"})).To(BeNil())
+	})
+
+	It("submit a job", func() {
+		release := make(chan struct{})
+		id := jobs.Submit(model.JobKindInstanceRestore, map[string]string{"backup_id": "RTKQ1T"}, func() error {
+			<-release
+			return errors.New("restore failed")
+		})
+
+		j, err := jobs.Get(id)
+		Expect(err).To(BeNil())
+		Expect(j.State).To(Equal(model.JobRunning))
+		Expect(j.Pid).To(BeZero())
+		Expect(errors.Is(jobs.Cancel(id), cons.JobNotCancelable)).To(BeTrue())
+
+		close(release)
+		Eventually(func() model.JobState {
+			j, _ := jobs.Get(id)
+			return j.State
+		}).Should(Equal(model.JobFailed))
+		j, _ = jobs.Get(id)
+		Expect(j.Error).To(Equal("restore failed"))
+	})
+
+	It("run a failed job", func() {
+		err := jobs.Run(model.JobKindRestore, nil, "echo failed; exit 1", nil)
+		Expect(errors.Is(err, cons.CmdOperateFailed)).To(BeTrue())
+		Expect(jobs.List()[0].State).To(Equal(model.JobFailed))
+	})
+
+	It("cancel a running job", func() {
+		done := make(chan error)
+		go func() {
+			done <- jobs.Run(model.JobKindBackup, nil, "sleep 30 & sleep 30", nil)
+		}()
+		Eventually(jobs.List).Should(HaveLen(1))
+		id := jobs.List()[0].ID
+
+		Expect(jobs.Cancel(id)).To(Succeed())
+		var err error
+		Eventually(done, 5*time.Second).Should(Receive(&err))
+		Expect(errors.Is(err, cons.JobCanceled)).To(BeTrue())
+
+		j, err := jobs.Get(id)
+		Expect(err).To(BeNil())
+		Expect(j.State).To(Equal(model.JobCanceled))
+		Eventually(func() bool { return cmds.GroupAlive(j.Pid) }).Should(BeFalse())
+		Expect(errors.Is(jobs.Cancel(id), cons.JobAlreadyDone)).To(BeTrue())
+		Expect(errors.Is(jobs.Cancel("not-exist"), cons.JobNotFound)).To(BeTrue())
+	})
+
+	It("recover the jobs after restart", func() {
+		pid, outputs, err := cmds.AsyncExecGroup("/bin/sh", "sleep 30")
+		Expect(err).To(BeNil())
+		startTime, err := cmds.StartTime(pid)
+		Expect(err).To(BeNil())
+
+		for _, j := range []*model.Job{
+			{ID: "exited", Kind: model.JobKindBackup, State: model.JobRunning, Pid: 1 << 22},
+			{ID: "running", Kind: model.JobKindRestore, State: model.JobRunning, Pid: pid, PidStartTime: startTime},
+			// the pid of the job was reused by another process group
+			{ID: "reused", Kind: model.JobKindBackup, State: model.JobRunning, Pid: pid, PidStartTime: startTime + 1},
+			{ID: "done", Kind: model.JobKindBackup, State: model.JobSucceeded},
+			{ID: "submitted", Kind: model.JobKindInstanceRestore, State: model.JobRunning},
+		} {
+			data, err := json.Marshal(j)
+			Expect(err).To(BeNil())
+			Expect(os.WriteFile(filepath.Join(dir, j.ID+".json"), data, 0600)).To(Succeed())
+		}
+
+		jobWatchInterval = 10 * time.Millisecond
+		recovered, err := NewJobManager("/bin/sh", dir, log)
+		Expect(err).To(BeNil())
+		Expect(recovered.List()).To(HaveLen(5))
+
+		j, err := recovered.Get("exited")
+		Expect(err).To(BeNil())
+		Expect(j.State).To(Equal(model.JobInterrupted))
+		j, err = recovered.Get("reused")
+		Expect(err).To(BeNil())
+		Expect(j.State).To(Equal(model.JobInterrupted))
+		j, err = recovered.Get("submitted")
+		Expect(err).To(BeNil())
+		Expect(j.State).To(Equal(model.JobInterrupted))
+		j, err = recovered.Get("running")
+		Expect(err).To(BeNil())
+		Expect(j.State).To(Equal(model.JobRunning))
+
+		Expect(recovered.Cancel("running")).To(Succeed())
+		for range outputs {
+		}
+		Eventually(func() model.JobState {
+			j, _ := recovered.Get("running")
+			return j.State
+		}).Should(Equal(model.JobCanceled))
+	})
+
+	It("parse progress", func() {
+		j := &model.Job{}
+		parseProgress(j, "INFO: PGDATA size: 1.5GB")
+		Expect(j.TotalBytes).To(Equal(int64(3 << 29)))
+		parseProgress(j, `INFO: Progress: (3/4). Process file "base/15590/2608"`)
+		Expect(j.Progress).To(Equal(75))
+		Expect(j.DoneBytes).To(Equal(int64(9 << 27)))
+		parseProgress(j, "INFO: Progress: (5/4)")
+		Expect(j.Progress).To(Equal(75))
+	})
+})
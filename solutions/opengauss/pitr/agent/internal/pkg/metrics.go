@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/logging"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const (
+	_metricsNamespace = "pitr_agent"
+
+	OperationBackup  = "backup"
+	OperationRestore = "restore"
+	OperationDelete  = "delete"
+
+	// the restore modes
+	RestoreModeInstance = "instance"
+	RestoreModeDatabase = "database"
+
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCanceled  = "canceled"
+
+	OGStatusRunning = "Running"
+	OGStatusStopped = "Stopped"
+	OGStatusUnknown = "Unknown"
+)
+
+var (
+	// Registry the registry of the agent metrics, which is exposed by `/metrics`
+	Registry = prometheus.NewRegistry()
+
+	operationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: _metricsNamespace,
+		Name:      "operations_total",
+		Help:      "The number of the backup, restore and delete operations.",
+	}, []string{"operation", "mode", "status"})
+
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: _metricsNamespace,
+		Name:      "operation_duration_seconds",
+		Help:      "The duration of the backup, restore and delete operations.",
+		// 1s to about 9h
+		Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+	}, []string{"operation", "mode", "status"})
+
+	backupWrittenBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: _metricsNamespace,
+		Name:      "backup_written_bytes_total",
+		Help:      "The data bytes of the successful backups.",
+	}, []string{"backup_path", "instance"})
+
+	lastBackupTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: _metricsNamespace,
+		Name:      "last_successful_backup_timestamp_seconds",
+		Help:      "The unix time of the last successful backup.",
+	}, []string{"backup_path", "instance"})
+
+	backupPathFreeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(_metricsNamespace, "", "backup_path_free_bytes"),
+		"The available bytes of the disk of the backup path.",
+		[]string{"backup_path"}, nil,
+	)
+	ogStatusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(_metricsNamespace, "", "opengauss_status"),
+		"The status of openGauss, the value of the current status is 1.",
+		[]string{"status"}, nil,
+	)
+
+	// backupPaths the backup paths the agent has backed up to, whose free space is collected
+	backupPaths sync.Map
+)
+
+func init() {
+	Registry.MustRegister(
+		operationsTotal,
+		operationDuration,
+		backupWrittenBytes,
+		lastBackupTimestamp,
+		&stateCollector{},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// stateCollector collects the free space of the backup paths and the status of openGauss when the metrics are scraped.
+type stateCollector struct{}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- backupPathFreeDesc
+	ch <- ogStatusDesc
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	shell := os.Getenv("SHELL")
+	backupPaths.Range(func(key, _ any) bool {
+		p := key.(string)
+		free, err := DiskFree(shell, p)
+		if err != nil {
+			logging.Warn(fmt.Sprintf("collect the free space of backup path[%s] failure, err: %s", p, err))
+			return true
+		}
+		ch <- prometheus.MustNewConstMetric(backupPathFreeDesc, prometheus.GaugeValue, float64(free), p)
+		return true
+	})
+
+	if OG == nil {
+		return
+	}
+	status, err := OG.Status()
+	if err != nil || (status != OGStatusRunning && status != OGStatusStopped) {
+		status = OGStatusUnknown
+	}
+	for _, s := range []string{OGStatusRunning, OGStatusStopped, OGStatusUnknown} {
+		var v float64
+		if s == status {
+			v = 1
+		}
+		ch <- prometheus.MustNewConstMetric(ogStatusDesc, prometheus.GaugeValue, v, s)
+	}
+}
+
+// observeOperation records the operation and its duration since start by the result.
+func observeOperation(operation, mode string, start time.Time, err error) {
+	status := OperationSucceeded
+	switch {
+	case errors.Is(err, cons.JobCanceled):
+		status = OperationCanceled
+	case err != nil:
+		status = OperationFailed
+	}
+	operationsTotal.WithLabelValues(operation, mode, status).Inc()
+	operationDuration.WithLabelValues(operation, mode, status).Observe(time.Since(start).Seconds())
+}
+
+// observeBackup records the successful backup of the instance.
+func observeBackup(backupPath, instance string, endAt time.Time, dataBytes int) {
+	backupPaths.Store(backupPath, struct{}{})
+	lastBackupTimestamp.WithLabelValues(backupPath, instance).Set(float64(endAt.Unix()))
+	if dataBytes > 0 {
+		backupWrittenBytes.WithLabelValues(backupPath, instance).Add(float64(dataBytes))
+	}
+}
+
+// recoverMetrics restores the last successful backups from the persisted backup jobs after the agent restarts.
+func recoverMetrics(jobs IJobManager) {
+	list := jobs.List()
+	// the earliest job first, so that the last backup wins
+	sort.Slice(list, func(i, k int) bool {
+		return list[i].StartAt.Before(list[k].StartAt)
+	})
+	for _, j := range list {
+		if j.Kind != model.JobKindBackup || j.State != model.JobSucceeded || j.EndAt == nil {
+			continue
+		}
+		observeBackup(j.Labels["backup_path"], j.Labels["instance"], *j.EndAt, 0)
+	}
+}
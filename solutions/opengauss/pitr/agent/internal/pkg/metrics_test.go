@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Metrics", func() {
+	It("observe the operations by status", func() {
+		start := time.Now()
+		observeOperation(OperationBackup, "PTRACK", start, nil)
+		observeOperation(OperationBackup, "PTRACK", start, fmt.Errorf("wrap: %w", cons.CmdAsyncBackupFailed))
+		observeOperation(OperationBackup, "PTRACK", start, fmt.Errorf("wrap: %w", cons.JobCanceled))
+
+		for _, status := range []string{OperationSucceeded, OperationFailed, OperationCanceled} {
+			Expect(testutil.ToFloat64(operationsTotal.WithLabelValues(OperationBackup, "PTRACK", status))).To(Equal(float64(1)))
+		}
+	})
+
+	It("observe the successful backups", func() {
+		endAt := time.Unix(1680000000, 0)
+		observeBackup("/tmp", "ins-metrics", endAt, 1024)
+		observeBackup("/tmp", "ins-metrics", endAt.Add(time.Hour), 2048)
+
+		Expect(testutil.ToFloat64(lastBackupTimestamp.WithLabelValues("/tmp", "ins-metrics"))).To(Equal(float64(endAt.Add(time.Hour).Unix())))
+		Expect(testutil.ToFloat64(backupWrittenBytes.WithLabelValues("/tmp", "ins-metrics"))).To(Equal(float64(3072)))
+
+		// the free space of the backup path is collected
+		Expect(testutil.CollectAndCount(&stateCollector{}, "pitr_agent_backup_path_free_bytes")).To(BeNumerically(">=", 1))
+	})
+
+	It("recover the last successful backups from the jobs", func() {
+		earlier, later := time.Unix(1680000000, 0), time.Unix(1680003600, 0)
+		labels := map[string]string{"backup_path": "/tmp", "instance": "ins-recover"}
+		m := &jobManager{jobs: map[string]*job{
+			"j1": {Job: &model.Job{ID: "j1", Kind: model.JobKindBackup, State: model.JobSucceeded, Labels: labels, StartAt: later, EndAt: &later}},
+			"j2": {Job: &model.Job{ID: "j2", Kind: model.JobKindBackup, State: model.JobSucceeded, Labels: labels, StartAt: earlier, EndAt: &earlier}},
+			"j3": {Job: &model.Job{ID: "j3", Kind: model.JobKindBackup, State: model.JobRunning, Labels: labels, StartAt: later.Add(time.Hour)}},
+		}}
+
+		recoverMetrics(m)
+		Expect(testutil.ToFloat64(lastBackupTimestamp.WithLabelValues("/tmp", "ins-recover"))).To(Equal(float64(later.Unix())))
+	})
+
+	It("disk free of the path", func() {
+		free, err := DiskFree("/bin/bash", "/tmp")
+		Expect(err).To(BeNil())
+		Expect(free).To(BeNumerically(">", 0))
+
+		_, err = DiskFree("/bin/bash", "/not-exist-path")
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -34,18 +34,18 @@ func (m *MockIOpenGauss) EXPECT() *MockIOpenGaussMockRecorder {
 }
 
 // AsyncBackup mocks base method
-func (m *MockIOpenGauss) AsyncBackup(backupPath, instanceName, backupMode string, threadsNum uint8, dbPort uint16) (string, error) {
+func (m *MockIOpenGauss) AsyncBackup(backupPath, instanceName, backupMode string, threadsNum uint8, dbPort uint16, requestKey string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AsyncBackup", backupPath, instanceName, backupMode, threadsNum, dbPort)
+	ret := m.ctrl.Call(m, "AsyncBackup", backupPath, instanceName, backupMode, threadsNum, dbPort, requestKey)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AsyncBackup indicates an expected call of AsyncBackup
-func (mr *MockIOpenGaussMockRecorder) AsyncBackup(backupPath, instanceName, backupMode, threadsNum, dbPort interface{}) *gomock.Call {
+func (mr *MockIOpenGaussMockRecorder) AsyncBackup(backupPath, instanceName, backupMode, threadsNum, dbPort, requestKey interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncBackup", reflect.TypeOf((*MockIOpenGauss)(nil).AsyncBackup), backupPath, instanceName, backupMode, threadsNum, dbPort)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AsyncBackup", reflect.TypeOf((*MockIOpenGauss)(nil).AsyncBackup), backupPath, instanceName, backupMode, threadsNum, dbPort, requestKey)
 }
 
 // ShowBackup mocks base method
@@ -63,6 +63,34 @@ func (mr *MockIOpenGaussMockRecorder) ShowBackup(backupPath, instanceName, backu
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowBackup", reflect.TypeOf((*MockIOpenGauss)(nil).ShowBackup), backupPath, instanceName, backupID)
 }
 
+// Validate mocks base method
+func (m *MockIOpenGauss) Validate(backupPath, instanceName, backupID string, threadsNum uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", backupPath, instanceName, backupID, threadsNum)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate
+func (mr *MockIOpenGaussMockRecorder) Validate(backupPath, instanceName, backupID, threadsNum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockIOpenGauss)(nil).Validate), backupPath, instanceName, backupID, threadsNum)
+}
+
+// Merge mocks base method
+func (m *MockIOpenGauss) Merge(backupPath, instanceName, backupID string, threadsNum uint8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", backupPath, instanceName, backupID, threadsNum)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge
+func (mr *MockIOpenGaussMockRecorder) Merge(backupPath, instanceName, backupID, threadsNum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockIOpenGauss)(nil).Merge), backupPath, instanceName, backupID, threadsNum)
+}
+
 // Init mocks base method
 func (m *MockIOpenGauss) Init(backupPath string) error {
 	m.ctrl.T.Helper()
@@ -163,17 +191,31 @@ func (mr *MockIOpenGaussMockRecorder) Status() *gomock.Call {
 }
 
 // Restore mocks base method
-func (m *MockIOpenGauss) Restore(backupPath, instance, backupID string, threadsNum uint8) error {
+func (m *MockIOpenGauss) Restore(backupPath, instance, backupID string, threadsNum uint8, target *model.RecoveryTarget) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Restore", backupPath, instance, backupID, threadsNum)
+	ret := m.ctrl.Call(m, "Restore", backupPath, instance, backupID, threadsNum, target)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Restore indicates an expected call of Restore
-func (mr *MockIOpenGaussMockRecorder) Restore(backupPath, instance, backupID, threadsNum interface{}) *gomock.Call {
+func (mr *MockIOpenGaussMockRecorder) Restore(backupPath, instance, backupID, threadsNum, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockIOpenGauss)(nil).Restore), backupPath, instance, backupID, threadsNum, target)
+}
+
+// RestoreDatabase mocks base method
+func (m *MockIOpenGauss) RestoreDatabase(backupPath, instance, backupID string, threadsNum uint8, target *model.RecoveryTarget, dbName string, dbPort uint16) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDatabase", backupPath, instance, backupID, threadsNum, target, dbName, dbPort)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreDatabase indicates an expected call of RestoreDatabase
+func (mr *MockIOpenGaussMockRecorder) RestoreDatabase(backupPath, instance, backupID, threadsNum, target, dbName, dbPort interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockIOpenGauss)(nil).Restore), backupPath, instance, backupID, threadsNum)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDatabase", reflect.TypeOf((*MockIOpenGauss)(nil).RestoreDatabase), backupPath, instance, backupID, threadsNum, target, dbName, dbPort)
 }
 
 // ShowBackupList mocks base method
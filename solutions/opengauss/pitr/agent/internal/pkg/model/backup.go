@@ -21,6 +21,7 @@ type (
 	Backup struct {
 		ID                string `json:"id"`
 		BackupMode        string `json:"backup-mode"`
+		ParentBackupID    string `json:"parent-backup-id"`
 		Wal               string `json:"wal"`
 		CompressAlg       string `json:"compress-alg"`
 		CompressLevel     int    `json:"compress-level"`
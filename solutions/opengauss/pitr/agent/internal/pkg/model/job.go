@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "time"
+
+type JobState string
+
+const (
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCanceled  JobState = "canceled"
+	// JobInterrupted the agent restarted while the job was running, the result of the job is unknown
+	JobInterrupted JobState = "interrupted"
+
+	JobKindBackup   = "backup"
+	JobKindRestore  = "restore"
+	JobKindValidate = "validate"
+	JobKindMerge    = "merge"
+	// JobKindInstanceRestore restores the whole openGauss in the background: stop, restore the backup and start
+	JobKindInstanceRestore = "instance-restore"
+
+	// LabelRequestKey the key given by the client to make the request idempotent
+	LabelRequestKey = "request_key"
+)
+
+type Job struct {
+	ID     string            `json:"id"`
+	Kind   string            `json:"kind"`
+	State  JobState          `json:"state"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// Pid the process id of the command, which is also the id of the process group.
+	// It is 0 if the job runs in the agent without a command.
+	Pid int `json:"pid"`
+	// PidStartTime the start time of the process in clock ticks after the system boot,
+	// the process group is verified by it before signaled, in case the pid was reused after the agent restarted.
+	PidStartTime uint64 `json:"pid_start_time,omitempty"`
+	// BackupID the id of the backup taken by the backup job
+	BackupID string `json:"backup_id,omitempty"`
+
+	// Progress the percentage of the processed files
+	Progress   int   `json:"progress"`
+	DoneBytes  int64 `json:"done_bytes"`
+	TotalBytes int64 `json:"total_bytes"`
+
+	Error   string     `json:"error,omitempty"`
+	LogTail []string   `json:"log_tail"`
+	StartAt time.Time  `json:"start_at"`
+	EndAt   *time.Time `json:"end_at,omitempty"`
+}
+
+func (j *Job) Done() bool {
+	return j.State != JobRunning
+}
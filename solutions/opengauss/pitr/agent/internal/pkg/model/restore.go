@@ -0,0 +1,28 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+// RecoveryTargetTimeLayout is the layout of RecoveryTarget.Time, e.g. `2023-03-01 12:00:00+08:00`
+const RecoveryTargetTimeLayout = "2006-01-02 15:04:05-07:00"
+
+// RecoveryTarget is the point to replay the WAL to after the backup is restored, only one of them is set
+type RecoveryTarget struct {
+	Time string `json:"target_time,omitempty"`
+	CSN  string `json:"target_csn,omitempty"`
+	LSN  string `json:"target_lsn,omitempty"`
+}
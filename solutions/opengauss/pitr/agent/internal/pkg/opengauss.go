@@ -21,13 +21,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/cmds"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/gsutil"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/logging"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/strutil"
 
 	"github.com/dlclark/regexp2"
 )
@@ -37,12 +42,15 @@ type (
 		shell      string
 		pgData     string
 		pgDataTemp string
+		jobs       IJobManager
 		log        logging.ILog
 	}
 
 	IOpenGauss interface {
-		AsyncBackup(backupPath, instanceName, backupMode string, threadsNum uint8, dbPort uint16) (string, error)
+		AsyncBackup(backupPath, instanceName, backupMode string, threadsNum uint8, dbPort uint16, requestKey string) (string, error)
 		ShowBackup(backupPath, instanceName, backupID string) (*model.Backup, error)
+		Validate(backupPath, instanceName, backupID string, threadsNum uint8) error
+		Merge(backupPath, instanceName, backupID string, threadsNum uint8) error
 		Init(backupPath string) error
 		AddInstance(backupPath, instance string) error
 		DelInstance(backupPath, instance string) error
@@ -50,7 +58,8 @@ type (
 		Start() error
 		Stop() error
 		Status() (string, error)
-		Restore(backupPath, instance, backupID string, threadsNum uint8) error
+		Restore(backupPath, instance, backupID string, threadsNum uint8, target *model.RecoveryTarget) error
+		RestoreDatabase(backupPath, instance, backupID string, threadsNum uint8, target *model.RecoveryTarget, dbName string, dbPort uint16) error
 		ShowBackupList(backupPath, instanceName string) ([]*model.Backup, error)
 		Auth(user, password, dbName string, dbPort uint16) error
 		CheckSchema(user, password, dbName string, dbPort uint16, schema string) error
@@ -62,7 +71,7 @@ type (
 
 var _ IOpenGauss = (*openGauss)(nil)
 
-func NewOpenGauss(shell, pgData string, log logging.ILog) IOpenGauss {
+func NewOpenGauss(shell, pgData string, jobs IJobManager, log logging.ILog) IOpenGauss {
 	dirs := strings.Split(pgData, "/")
 	dirs = append(dirs[0:len(dirs)-1], "temp")
 
@@ -70,15 +79,24 @@ func NewOpenGauss(shell, pgData string, log logging.ILog) IOpenGauss {
 		shell:      shell,
 		pgData:     pgData,
 		pgDataTemp: strings.Join(dirs, "/"),
+		jobs:       jobs,
 		log:        log,
 	}
 }
 
 const (
-	_backupFmt    = "gs_probackup backup --backup-path=%s --instance=%s --backup-mode=%s --pgdata=%s --threads=%d --pgport %d 2>&1"
+	_backupFmt    = "gs_probackup backup --backup-path=%s --instance=%s --backup-mode=%s --pgdata=%s --threads=%d --pgport %d --progress 2>&1"
 	_showFmt      = "gs_probackup show --instance=%s --backup-path=%s --backup-id=%s --format=json 2>&1"
 	_delBackupFmt = "gs_probackup delete --backup-path=%s --instance=%s --backup-id=%s 2>&1"
-	_restoreFmt   = "gs_probackup restore --backup-path=%s --instance=%s --backup-id=%s --pgdata=%s --threads=%d 2>&1"
+	_validateFmt  = "gs_probackup validate --backup-path=%s --instance=%s --backup-id=%s --threads=%d --progress 2>&1"
+	_mergeFmt     = "gs_probackup merge --backup-path=%s --instance=%s --backup-id=%s --threads=%d --progress 2>&1"
+	_restoreFmt   = "gs_probackup restore --backup-path=%s --instance=%s --backup-id=%s --pgdata=%s --threads=%d --progress%s 2>&1"
+
+	_recoveryTargetTimeFmt = " --recovery-target-time='%s'"
+	_recoveryTargetLsnFmt  = " --recovery-target-lsn=%s"
+	// gs_probackup does not support the csn target, replay all the WAL and stop at the csn set in recovery.conf
+	_recoveryTargetLatest = " --recovery-target=latest"
+	_recoveryTargetCsnFmt = "recovery_target_csn = '%s'\n"
 
 	_initFmt  = "gs_probackup init --backup-path=%s 2>&1"
 	_rmDirFmt = "rm -r %s"
@@ -94,21 +112,34 @@ const (
 
 	_mvFmt = "mv %s %s"
 
+	// the side instance never archives the WAL to the backup path
+	_startSideFmt    = "gs_ctl start --pgdata=%s -w -o '-p %d -c archive_mode=off' 2>&1"
+	_stopSideFmt     = "gs_ctl stop --pgdata=%s -m fast 2>&1"
+	_dumpFmt         = "gs_dump --port=%d --format=c --file=%s %s 2>&1"
+	_dropDatabaseFmt = "gsql --port=%d --dbname=postgres --command='DROP DATABASE IF EXISTS \"%s\"' 2>&1"
+	_renameDbFmt     = "gsql --port=%d --dbname=postgres --command='ALTER DATABASE \"%s\" RENAME TO \"%s\"' 2>&1"
+	_loadDumpFmt     = "gs_restore --port=%d --dbname=postgres --create %s 2>&1"
+
 	_CmdErrorFmt = "cmds.Exec[shell=%s,cmd=%s] return err wrap: %s"
 )
 
-func (og *openGauss) AsyncBackup(backupPath, instanceName, backupMode string, threadsNum uint8, dbPort uint16) (string, error) {
+func (og *openGauss) AsyncBackup(backupPath, instanceName, backupMode string, threadsNum uint8, dbPort uint16, requestKey string) (string, error) {
 	var (
-		bid string
-		err error
+		bid   string
+		err   error
+		start = time.Now()
 	)
 	cmd := fmt.Sprintf(_backupFmt, backupPath, instanceName, backupMode, og.pgData, threadsNum, dbPort)
-	outputs, err := cmds.AsyncExec(og.shell, cmd)
-	if err != nil {
-		return "", fmt.Errorf("cmds.AsyncExec[shell=%s,cmd=%s] return err wrap: %w", og.shell, cmd, cons.CmdAsyncBackupFailed)
+	labels := map[string]string{
+		"backup_path": backupPath,
+		"instance":    instanceName,
+		"backup_mode": backupMode,
+	}
+	if requestKey != "" {
+		labels[model.LabelRequestKey] = requestKey
 	}
 
-	for output := range outputs {
+	err = og.jobs.Run(model.JobKindBackup, labels, cmd, func(output *cmds.Output) error {
 		og.log.
 			Field("backup_path", backupPath).
 			Field("instance", instanceName).
@@ -118,18 +149,31 @@ func (og *openGauss) AsyncBackup(backupPath, instanceName, backupMode string, th
 
 		if output.Error != nil {
 			og.log.Error(fmt.Sprintf("output.Error[%s] is not nil", output.Error))
-			return "", output.Error
+			return output.Error
 		}
 
 		if strings.Contains(output.Message, "INFO: Backup start") {
 			bid, err = og.getBackupID(output.Message)
 			if err != nil {
 				og.log.Error(fmt.Sprintf("og.getBackupID[source=%s] return err wrap: %s", output.Message, err))
-				return "", err
+				return err
 			}
 		}
+		return nil
+	})
+	observeOperation(OperationBackup, backupMode, start, err)
+	if err != nil {
+		return "", fmt.Errorf("og.jobs.Run[cmd=%s] return err: %s, wrap: %w", cmd, err, cons.CmdAsyncBackupFailed)
+	}
+
+	var dataBytes int
+	if backup, err := og.ShowBackup(backupPath, instanceName, bid); err != nil || backup == nil {
+		og.log.Warn(fmt.Sprintf("show backup[id=%s] failure, the written bytes is not recorded, err: %v", bid, err))
+	} else {
+		dataBytes = backup.DataBytes
 	}
-	return bid, nil //nolint
+	observeBackup(backupPath, instanceName, time.Now(), dataBytes)
+	return bid, nil
 }
 
 //nolint:dupl
@@ -145,9 +189,74 @@ func (og *openGauss) ShowBackup(backupPath, instanceName, backupID string) (*mod
 	return nil, err
 }
 
+/*
+Validate checks the files of the backup and the WAL needed to restore it by `gs_probackup validate`,
+the status of the backup turns `CORRUPT` if it is not valid.
+*/
+func (og *openGauss) Validate(backupPath, instanceName, backupID string, threadsNum uint8) error {
+	cmd := fmt.Sprintf(_validateFmt, backupPath, instanceName, backupID, threadsNum)
+	labels := map[string]string{
+		"backup_path": backupPath,
+		"instance":    instanceName,
+		"backup_id":   backupID,
+	}
+
+	var msgs []string
+	err := og.jobs.Run(model.JobKindValidate, labels, cmd, func(output *cmds.Output) error {
+		og.log.
+			//nolint:exhaustive
+			Fields(map[logging.FieldKey]string{
+				"backup_path": backupPath,
+				"instance":    instanceName,
+				"backup_id":   backupID,
+			}).
+			Debug(fmt.Sprintf("Validate output[lineNo=%d,msg=%s]", output.LineNo, output.Message))
+
+		if strings.HasPrefix(output.Message, "ERROR:") || strings.HasPrefix(output.Message, "WARNING:") {
+			msgs = append(msgs, output.Message)
+		}
+		return output.Error
+	})
+	if err != nil {
+		return fmt.Errorf("og.jobs.Run[cmd=%s] return err: %s, output: %s, wrap: %w", cmd, err, strings.Join(msgs, "; "), cons.CmdValidateBackupFailed)
+	}
+	return nil
+}
+
+/*
+Merge merges the PTRACK backup with its parents into a FULL backup by `gs_probackup merge`,
+the parents are removed after they are merged.
+*/
+func (og *openGauss) Merge(backupPath, instanceName, backupID string, threadsNum uint8) error {
+	cmd := fmt.Sprintf(_mergeFmt, backupPath, instanceName, backupID, threadsNum)
+	labels := map[string]string{
+		"backup_path": backupPath,
+		"instance":    instanceName,
+		"backup_id":   backupID,
+	}
+
+	err := og.jobs.Run(model.JobKindMerge, labels, cmd, func(output *cmds.Output) error {
+		og.log.
+			//nolint:exhaustive
+			Fields(map[logging.FieldKey]string{
+				"backup_path": backupPath,
+				"instance":    instanceName,
+				"backup_id":   backupID,
+			}).
+			Debug(fmt.Sprintf("Merge output[lineNo=%d,msg=%s]", output.LineNo, output.Message))
+		return output.Error
+	})
+	if err != nil {
+		return fmt.Errorf("og.jobs.Run[cmd=%s] return err: %s, wrap: %w", cmd, err, cons.CmdMergeBackupFailed)
+	}
+	return nil
+}
+
 func (og *openGauss) DelBackup(backupPath, instanceName, backupID string) error {
+	start := time.Now()
 	cmd := fmt.Sprintf(_delBackupFmt, backupPath, instanceName, backupID)
 	_, err := cmds.Exec(og.shell, cmd)
+	observeOperation(OperationDelete, "", start, err)
 	if err != nil {
 		og.log.Error(fmt.Sprintf(_CmdErrorFmt, og.shell, cmd, err))
 		return err
@@ -275,7 +384,7 @@ func (og *openGauss) Status() (string, error) {
 
 	if errors.Is(err, cons.CmdOperateFailed) {
 		if strings.Contains(err.Error(), "no server running") {
-			return "Stopped", nil
+			return OGStatusStopped, nil
 		}
 		og.log.Error(fmt.Sprintf("get openGauss status failure[output=%s], err: %s, wrap: %s", output, err, cons.StopOpenGaussFailed))
 		return "", err
@@ -286,17 +395,35 @@ func (og *openGauss) Status() (string, error) {
 	}
 
 	if strings.Contains(output, "server is running") {
-		return "Running", nil
+		return OGStatusRunning, nil
 	}
 
 	return "", cons.UnknownOgStatus
 }
 
-// Restore TODO:Dependent environments require integration testing
-func (og *openGauss) Restore(backupPath, instance, backupID string, threadsNum uint8) error {
-	cmd := fmt.Sprintf(_restoreFmt, backupPath, instance, backupID, og.pgData, threadsNum)
-	outputs, err := cmds.AsyncExec(og.shell, cmd)
-	for output := range outputs {
+/*
+Restore restores the backup to pgdata, and then the WAL is replayed to the target when openGauss starts if target is not nil.
+The WAL must be archived to the backup path by `gs_probackup archive-push` to restore to a target.
+
+TODO:Dependent environments require integration testing
+*/
+func (og *openGauss) Restore(backupPath, instance, backupID string, threadsNum uint8, target *model.RecoveryTarget) error {
+	start := time.Now()
+	err := og.restore(og.pgData, backupPath, instance, backupID, threadsNum, target)
+	observeOperation(OperationRestore, RestoreModeInstance, start, err)
+	return err
+}
+
+func (og *openGauss) restore(pgData, backupPath, instance, backupID string, threadsNum uint8, target *model.RecoveryTarget) error {
+	cmd := fmt.Sprintf(_restoreFmt, backupPath, instance, backupID, pgData, threadsNum, recoveryTargetOpts(target))
+	labels := map[string]string{
+		"backup_path": backupPath,
+		"instance":    instance,
+		"backup_id":   backupID,
+		"pgdata":      pgData,
+	}
+
+	err := og.jobs.Run(model.JobKindRestore, labels, cmd, func(output *cmds.Output) error {
 		og.log.
 			//nolint:exhaustive
 			Fields(map[logging.FieldKey]string{
@@ -306,15 +433,139 @@ func (og *openGauss) Restore(backupPath, instance, backupID string, threadsNum u
 			}).
 			Debug(fmt.Sprintf("Restore openGauss[lineNo=%d,msg=%s]", output.LineNo, output.Message))
 
-		if err != nil {
-			og.log.Error(fmt.Sprintf("cmds.AsyncExec[output=%s] return err: %s, wrap: %s", output.Message, output.Error, cons.RestoreFailed))
-			return err
-		}
-
 		if output.Error != nil {
-			og.log.Error(fmt.Sprintf("cmds.AsyncExec outputs: Error[%s] is not nil, wrap: %s", output.Error, cons.RestoreFailed))
+			og.log.Error(fmt.Sprintf("cmds.AsyncExecGroup outputs: Error[%s] is not nil, wrap: %s", output.Error, cons.RestoreFailed))
 			return output.Error
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if target != nil && target.CSN != "" {
+		return og.writeRecoveryTargetCSN(pgData, target.CSN)
+	}
+	return nil
+}
+
+/*
+RestoreDatabase restores the database only, the other databases keep online:
+
+	the backup is restored to a side instance started on a free port,
+	then the database is dumped from the side instance, and replaces the database of openGauss.
+*/
+func (og *openGauss) RestoreDatabase(backupPath, instance, backupID string, threadsNum uint8, target *model.RecoveryTarget, dbName string, dbPort uint16) (err error) {
+	start := time.Now()
+	defer func() {
+		observeOperation(OperationRestore, RestoreModeDatabase, start, err)
+	}()
+
+	sidePgData := fmt.Sprintf("%s/side-%s", path.Dir(og.pgData), strutil.Random(8))
+	defer og.remove(sidePgData)
+	if err := og.restore(sidePgData, backupPath, instance, backupID, threadsNum, target); err != nil {
+		return fmt.Errorf("restore side instance failure, err: %s, wrap: %w", err, cons.RestoreDatabaseFailed)
+	}
+
+	sidePort, err := freePort()
+	if err != nil {
+		return fmt.Errorf("get free port failure, err: %s, wrap: %w", err, cons.RestoreDatabaseFailed)
+	}
+	if err := og.exec(fmt.Sprintf(_startSideFmt, sidePgData, sidePort)); err != nil {
+		return fmt.Errorf("start side instance failure, err: %s, wrap: %w", err, cons.RestoreDatabaseFailed)
+	}
+	defer func() {
+		_ = og.exec(fmt.Sprintf(_stopSideFmt, sidePgData))
+	}()
+
+	dump := fmt.Sprintf("%s.dump", sidePgData)
+	defer og.remove(dump)
+	if err := og.exec(fmt.Sprintf(_dumpFmt, sidePort, dump, dbName)); err != nil {
+		return fmt.Errorf("dump database %s failure, err: %s, wrap: %w", dbName, err, cons.RestoreDatabaseFailed)
+	}
+
+	return og.replaceDatabase(dbPort, dbName, dump)
+}
+
+// replaceDatabase renames the database aside and loads the dump, the old database is dropped after the dump is loaded,
+// or renamed back if the dump is not loaded
+func (og *openGauss) replaceDatabase(dbPort uint16, dbName, dump string) error {
+	// the name of a database is up to 63 bytes
+	aside := fmt.Sprintf("%.48s_pitr_%s", dbName, strings.ToLower(strutil.Random(8)))
+	if err := og.exec(fmt.Sprintf(_renameDbFmt, dbPort, dbName, aside)); err != nil {
+		return fmt.Errorf("rename database %s to %s failure, err: %s, wrap: %w", dbName, aside, err, cons.RestoreDatabaseFailed)
+	}
+
+	if err := og.exec(fmt.Sprintf(_loadDumpFmt, dbPort, dump)); err != nil {
+		loadErr := fmt.Errorf("load database %s failure, err: %s, wrap: %w", dbName, err, cons.RestoreDatabaseFailed)
+		if err := og.exec(fmt.Sprintf(_dropDatabaseFmt, dbPort, dbName)); err != nil {
+			return fmt.Errorf("drop the partially loaded database %s failure, err: %s, the old database is kept as %s, %w", dbName, err, aside, loadErr)
+		}
+		if err := og.exec(fmt.Sprintf(_renameDbFmt, dbPort, aside, dbName)); err != nil {
+			return fmt.Errorf("rename database %s back failure, err: %s, the old database is kept as %s, %w", aside, err, aside, loadErr)
+		}
+		return loadErr
+	}
+
+	if err := og.exec(fmt.Sprintf(_dropDatabaseFmt, dbPort, aside)); err != nil {
+		og.log.Warn(fmt.Sprintf("drop the old database %s failure, err: %s", aside, err))
+	}
+	return nil
+}
+
+func (og *openGauss) exec(cmd string) error {
+	output, err := cmds.Exec(og.shell, cmd)
+	og.log.Debug(fmt.Sprintf("Exec[cmd=%s,output=%s,err=%v]", cmd, output, err))
+	if err != nil {
+		og.log.Error(fmt.Sprintf(_CmdErrorFmt, og.shell, cmd, err))
+		return err
+	}
+	return nil
+}
+
+func (og *openGauss) remove(path string) {
+	if err := os.RemoveAll(path); err != nil {
+		og.log.Error(fmt.Sprintf("remove %s failure, err: %s", path, err))
+	}
+}
+
+func freePort() (uint16, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return uint16(l.Addr().(*net.TCPAddr).Port), nil
+}
+
+func recoveryTargetOpts(target *model.RecoveryTarget) string {
+	switch {
+	case target == nil:
+		return ""
+	case target.Time != "":
+		return fmt.Sprintf(_recoveryTargetTimeFmt, target.Time)
+	case target.LSN != "":
+		return fmt.Sprintf(_recoveryTargetLsnFmt, target.LSN)
+	case target.CSN != "":
+		return _recoveryTargetLatest
+	default:
+		return ""
+	}
+}
+
+// writeRecoveryTargetCSN appends the csn target to the recovery.conf written by `gs_probackup restore`
+func (og *openGauss) writeRecoveryTargetCSN(pgData, csn string) error {
+	conf := fmt.Sprintf("%s/recovery.conf", strings.TrimSuffix(pgData, "/"))
+	f, err := os.OpenFile(conf, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		og.log.Error(fmt.Sprintf("open recovery.conf[path=%s] failure, err: %s, wrap: %s", conf, err, cons.WriteRecoveryConfFailed))
+		return fmt.Errorf("open recovery.conf[path=%s] failure, err: %s, wrap: %w", conf, err, cons.WriteRecoveryConfFailed)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, _recoveryTargetCsnFmt, csn); err != nil {
+		og.log.Error(fmt.Sprintf("write recovery.conf[path=%s] failure, err: %s, wrap: %s", conf, err, cons.WriteRecoveryConfFailed))
+		return fmt.Errorf("write recovery.conf[path=%s] failure, err: %s, wrap: %w", conf, err, cons.WriteRecoveryConfFailed)
 	}
 	return nil
 }
@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	. "github.com/onsi/gomega"
@@ -28,6 +30,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg/model"
 )
 
 var _ = Describe("OpenGauss,requires opengauss environment", func() {
@@ -51,6 +54,7 @@ var _ = Describe("OpenGauss,requires opengauss environment", func() {
 				"full",
 				1,
 				3306,
+				"",
 			)
 
 			Expect(err).To(BeNil())
@@ -203,4 +207,86 @@ var _ = Describe("OpenGauss,requires opengauss environment", func() {
 			fmt.Println(string(indent))
 		})
 	})
+
+	Context("Restore to the recovery target", func() {
+		It("recovery target options", func() {
+			Expect(recoveryTargetOpts(nil)).To(BeEmpty())
+			Expect(recoveryTargetOpts(&model.RecoveryTarget{Time: "2023-03-01 12:00:00+08:00"})).
+				To(Equal(" --recovery-target-time='2023-03-01 12:00:00+08:00'"))
+			Expect(recoveryTargetOpts(&model.RecoveryTarget{LSN: "0/3000028"})).To(Equal(" --recovery-target-lsn=0/3000028"))
+			Expect(recoveryTargetOpts(&model.RecoveryTarget{CSN: "3012"})).To(Equal(" --recovery-target=latest"))
+		})
+
+		It("write the csn target to recovery.conf", func() {
+			pgData, err := os.MkdirTemp("", "pgdata")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(pgData)
+
+			og := &openGauss{
+				shell:  "/bin/sh",
+				pgData: pgData,
+				log:    log,
+			}
+			Expect(errors.Is(og.writeRecoveryTargetCSN(pgData, "3012"), cons.WriteRecoveryConfFailed)).To(BeTrue())
+
+			conf := fmt.Sprintf("%s/recovery.conf", pgData)
+			Expect(os.WriteFile(conf, []byte("restore_command = 'gs_probackup archive-get'\n"), 0600)).To(Succeed())
+			Expect(og.writeRecoveryTargetCSN(pgData, "3012")).To(Succeed())
+
+			data, err := os.ReadFile(conf)
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(Equal("restore_command = 'gs_probackup archive-get'\nrecovery_target_csn = '3012'\n"))
+		})
+	})
+	Context("Replace the database with the dump", func() {
+		var (
+			bin  string
+			path = os.Getenv("PATH")
+			og   *openGauss
+		)
+
+		BeforeEach(func() {
+			var err error
+			bin, err = os.MkdirTemp("", "bin")
+			Expect(err).To(BeNil())
+			// gsql and gs_restore record the commands, gs_restore fails if the file `fail` exists
+			Expect(os.WriteFile(bin+"/gsql", []byte("#!/bin/sh\necho \"gsql $*\" >> "+bin+"/log\n"), 0700)).To(Succeed())
+			Expect(os.WriteFile(bin+"/gs_restore", []byte("#!/bin/sh\necho \"gs_restore $*\" >> "+bin+"/log\n[ ! -f "+bin+"/fail ]\n"), 0700)).To(Succeed())
+			Expect(os.Setenv("PATH", bin+":"+path)).To(Succeed())
+			og = &openGauss{shell: "/bin/sh", log: log}
+		})
+
+		AfterEach(func() {
+			Expect(os.Setenv("PATH", path)).To(Succeed())
+			Expect(os.RemoveAll(bin)).To(Succeed())
+		})
+
+		commands := func() []string {
+			data, err := os.ReadFile(bin + "/log")
+			Expect(err).To(BeNil())
+			return strings.Split(strings.TrimSpace(string(data)), "\n")
+		}
+
+		It("drop the old database after the dump is loaded", func() {
+			Expect(og.replaceDatabase(5432, "db", "/tmp/db.dump")).To(Succeed())
+
+			cmds := commands()
+			Expect(cmds).To(HaveLen(3))
+			Expect(cmds[0]).To(MatchRegexp(`^gsql --port=5432 --dbname=postgres --command=ALTER DATABASE "db" RENAME TO "db_pitr_[a-z0-9]{8}"$`))
+			aside := strings.Trim(cmds[0][strings.LastIndex(cmds[0], " ")+1:], `"`)
+			Expect(cmds[1]).To(Equal("gs_restore --port=5432 --dbname=postgres --create /tmp/db.dump"))
+			Expect(cmds[2]).To(Equal(fmt.Sprintf(`gsql --port=5432 --dbname=postgres --command=DROP DATABASE IF EXISTS "%s"`, aside)))
+		})
+
+		It("rename the old database back if the dump is not loaded", func() {
+			Expect(os.WriteFile(bin+"/fail", nil, 0600)).To(Succeed())
+			Expect(errors.Is(og.replaceDatabase(5432, "db", "/tmp/db.dump"), cons.RestoreDatabaseFailed)).To(BeTrue())
+
+			cmds := commands()
+			Expect(cmds).To(HaveLen(4))
+			aside := strings.Trim(cmds[0][strings.LastIndex(cmds[0], " ")+1:], `"`)
+			Expect(cmds[2]).To(Equal(`gsql --port=5432 --dbname=postgres --command=DROP DATABASE IF EXISTS "db"`))
+			Expect(cmds[3]).To(Equal(fmt.Sprintf(`gsql --port=5432 --dbname=postgres --command=ALTER DATABASE "%s" RENAME TO "db"`, aside)))
+		})
+	})
 })
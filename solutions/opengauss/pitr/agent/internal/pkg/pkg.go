@@ -20,9 +20,17 @@ package pkg
 import "github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/logging"
 
 var (
-	OG IOpenGauss
+	OG   IOpenGauss
+	Jobs IJobManager
 )
 
-func Init(shell, pgData string, log logging.ILog) {
-	OG = NewOpenGauss(shell, pgData, log)
+func Init(shell, pgData, jobDir string, log logging.ILog) error {
+	jobs, err := NewJobManager(shell, jobDir, log)
+	if err != nil {
+		return err
+	}
+	Jobs = jobs
+	recoverMetrics(Jobs)
+	OG = NewOpenGauss(shell, pgData, Jobs, log)
+	return nil
 }
@@ -18,18 +18,24 @@
 package main
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"path"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/middleware"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/logging"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/responder"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/token"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
@@ -52,6 +58,12 @@ var (
 	tlsCrt        string
 	tlsKey        string
 	envSourceFile string
+	jobDir        string
+
+	tlsClientCA string
+	tokenKey    string
+	signToken   string
+	tokenTTL    time.Duration
 )
 
 func init() {
@@ -61,15 +73,31 @@ func init() {
 
 	flag.StringVar(&tlsCrt, "tls-crt", "", "Require:TLS certificate file path")
 	flag.StringVar(&tlsKey, "tls-key", "", "Require:TLS key file path")
+	flag.StringVar(&tlsClientCA, "tls-client-ca", "", "Optional:CA bundle file path to verify the client certificates, the role of a client is the OU of its certificate")
+
+	flag.StringVar(&tokenKey, "token-key", "", "Optional:key file path to sign and verify the bearer tokens")
+	flag.StringVar(&signToken, "sign-token", "", "Optional:print a bearer token of the role (viewer, operator or admin) signed with the token key, then exit")
+	flag.DurationVar(&tokenTTL, "token-ttl", 30*24*time.Hour, "Optional:the validity period of the signed bearer token")
 
 	flag.StringVar(&pgData, "pgdata", "", "Optional:Get the value from cli flags or env")
 
 	flag.StringVar(&envSourceFile, "env-source-file", "", "Optional:env source file path")
+
+	flag.StringVar(&jobDir, "job-dir", "", "Optional:the dir to persist the jobs, default is the pitr_jobs dir next to PGDATA")
 }
 
 func main() {
 	flag.Parse()
 
+	if signToken != "" {
+		t, err := sign(signToken)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(t)
+		return
+	}
+
 	if envSourceFile != "" {
 		err := godotenv.Load(envSourceFile)
 		if err != nil {
@@ -100,6 +128,10 @@ func main() {
 		pgData = strings.Join(dirs, "/")
 	}
 
+	if jobDir == "" {
+		jobDir = path.Join(path.Dir(pgData), "pitr_jobs")
+	}
+
 	if strings.Trim(tlsCrt, " ") == "" || strings.Trim(tlsKey, " ") == "" {
 		panic(fmt.Errorf("lack of HTTPs certificate"))
 	}
@@ -110,6 +142,11 @@ func main() {
 	if _, err := os.Stat(tlsKey); os.IsNotExist(err) {
 		panic(fmt.Errorf("TLS key file does not exist"))
 	}
+	if tlsClientCA != "" {
+		if _, err := os.Stat(tlsClientCA); os.IsNotExist(err) {
+			panic(fmt.Errorf("TLS client CA file does not exist"))
+		}
+	}
 
 	var level = zapcore.InfoLevel
 	if logLevel == debugLogLevel {
@@ -117,9 +154,20 @@ func main() {
 	}
 
 	log = logging.Init(level)
-	pkg.Init(shell, pgData, log)
+	if err := pkg.Init(shell, pgData, jobDir, log); err != nil {
+		panic(fmt.Errorf("init failure, err: %w", err))
+	}
 
-	SetupApp()
+	key, err := readTokenKey()
+	if err != nil {
+		panic(err)
+	}
+	auth := middleware.NewAuth(key, tlsClientCA != "", log)
+	if !auth.Enabled() {
+		log.Warn("neither tls-client-ca nor token-key is set, the requests are not authenticated")
+	}
+
+	SetupApp(auth)
 
 	go func() {
 		if err := Serve(port); err != nil {
@@ -141,7 +189,7 @@ func main() {
 	log.Info("app has exited...")
 }
 
-func SetupApp() {
+func SetupApp(auth *middleware.Auth) {
 	app = fiber.New()
 
 	app.Use(
@@ -154,6 +202,8 @@ func SetupApp() {
 
 		return responder.Success(ctx, "pong")
 	})
+	// Prometheus scrapes the metrics with the bearer token or the client certificate of a viewer
+	app.Get("/metrics", auth.Require(middleware.RoleViewer), handler.Metrics)
 
 	app.Use(
 		middleware.AccessLog(logging.Log()),
@@ -163,13 +213,26 @@ func SetupApp() {
 	app.Route("/api", func(r fiber.Router) {
 		r.Use(middleware.RequestIDChecker())
 
-		r.Post("/healthz", handler.HealthCheck)
-		r.Post("/backup", handler.Backup)
-		r.Delete("/backup", handler.DeleteBackup)
-		r.Post("/restore", handler.Restore)
-		r.Post("/show", handler.Show)
-		r.Post("/show/list", handler.ShowList)
-		r.Post("/diskspace", handler.DiskSpace)
+		var (
+			viewer   = auth.Require(middleware.RoleViewer)
+			operator = auth.Require(middleware.RoleOperator)
+			admin    = auth.Require(middleware.RoleAdmin)
+		)
+
+		r.Post("/healthz", viewer, handler.HealthCheck)
+		r.Post("/backup", operator, handler.Backup)
+		r.Delete("/backup", admin, handler.DeleteBackup)
+		r.Post("/restore", admin, handler.Restore)
+		r.Post("/restore/database", admin, handler.RestoreDatabase)
+		r.Post("/show", viewer, handler.Show)
+		r.Post("/validate", operator, handler.Validate)
+		r.Post("/merge", admin, handler.Merge)
+		r.Post("/show/list", viewer, handler.ShowList)
+		r.Post("/diskspace", viewer, handler.DiskSpace)
+
+		r.Get("/jobs", viewer, handler.ListJobs)
+		r.Get("/jobs/:id", viewer, handler.GetJob)
+		r.Post("/jobs/:id/cancel", auth.RequireFunc(handler.CancelRole), handler.CancelJob)
 	})
 
 	// 404
@@ -180,7 +243,72 @@ func SetupApp() {
 
 // Serve run a http server on the specified port.
 func Serve(port string) error {
+	cert, err := tls.LoadX509KeyPair(tlsCrt, tlsKey)
+	if err != nil {
+		return fmt.Errorf("tls.LoadX509KeyPair return err: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
 
-	//return app.Listen(":18080")
-	return app.ListenTLS(fmt.Sprintf(":%s", port), tlsCrt, tlsKey)
+	if tlsClientCA != "" {
+		pem, err := os.ReadFile(tlsClientCA)
+		if err != nil {
+			return fmt.Errorf("read TLS client CA file failure, err: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate in the TLS client CA file")
+		}
+		config.ClientCAs = pool
+		// the clients could use the bearer tokens instead of the certificates
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		if tokenKey != "" {
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	ln, err := tls.Listen("tcp", fmt.Sprintf(":%s", port), config)
+	if err != nil {
+		return fmt.Errorf("tls.Listen return err: %w", err)
+	}
+	return app.Listener(ln)
+}
+
+func readTokenKey() ([]byte, error) {
+	if tokenKey == "" {
+		return nil, nil
+	}
+	key, err := os.ReadFile(tokenKey)
+	if err != nil {
+		return nil, fmt.Errorf("read token key file failure, err: %w", err)
+	}
+	key = bytes.TrimSpace(key)
+	if len(key) < 32 {
+		return nil, fmt.Errorf("the token key should be 32 bytes at least")
+	}
+	return key, nil
+}
+
+// sign returns a bearer token of the role signed with the token key
+func sign(role string) (string, error) {
+	if _, ok := middleware.ParseRole(role); !ok {
+		return "", fmt.Errorf("unknown role %s, option values: viewer, operator or admin", role)
+	}
+	key, err := readTokenKey()
+	if err != nil {
+		return "", err
+	}
+	if key == nil {
+		return "", fmt.Errorf("token-key is required to sign the token")
+	}
+
+	now := time.Now()
+	return token.Sign(key, &token.Claims{
+		Subject:   "gs_pitr",
+		Role:      role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(tokenTTL).Unix(),
+	})
 }
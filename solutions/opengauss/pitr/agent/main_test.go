@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/handler/middleware"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/logging"
+	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/token"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+)
+
+// the requests are sent with the headers of the client of the agent in the operator
+var _ = Describe("App", func() {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	BeforeEach(func() {
+		log = logging.Init(zapcore.DebugLevel)
+		jobs, err := pkg.NewJobManager("/bin/sh", GinkgoT().TempDir(), log)
+		Expect(err).To(BeNil())
+		pkg.Jobs = jobs
+		SetupApp(middleware.NewAuth(key, false, log))
+	})
+
+	send := func(requestID, role string) (int, int) {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		if requestID != "" {
+			req.Header.Set("x-request-id", requestID)
+		}
+		if role != "" {
+			t, err := token.Sign(key, &token.Claims{Subject: "shardingsphere-operator", Role: role, ExpiresAt: time.Now().Add(time.Hour).Unix()})
+			Expect(err).To(BeNil())
+			req.Header.Set("Authorization", "Bearer "+t)
+		}
+		resp, err := app.Test(req)
+		Expect(err).To(BeNil())
+		out := struct {
+			Code int `json:"code"`
+		}{}
+		Expect(json.NewDecoder(resp.Body).Decode(&out)).To(Succeed())
+		return resp.StatusCode, out.Code
+	}
+
+	It("requires the request id", func() {
+		status, code := send("", "admin")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(code).NotTo(BeZero())
+	})
+
+	It("requires the bearer token", func() {
+		status, _ := send("3f0b0f5e-7f5c-4b1e-9a53-1b2f2f0d6a3c", "")
+		Expect(status).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("accepts the requests of the operator", func() {
+		status, code := send("3f0b0f5e-7f5c-4b1e-9a53-1b2f2f0d6a3c", "admin")
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(code).To(BeZero())
+	})
+})
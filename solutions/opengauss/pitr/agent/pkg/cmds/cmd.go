@@ -19,11 +19,15 @@ package cmds
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/internal/cons"
 	"github.com/apache/shardingsphere-on-cloud/pitr/agent/pkg/logging"
@@ -43,7 +47,97 @@ func AsyncExec(name string, args ...string) (chan *Output, error) {
 	c := "-c"
 	args = append([]string{c}, args...)
 
+	return asyncExec(exec.Command(name, args...))
+}
+
+// AsyncExecGroup Async exec a command in a new process group, the returned pid is also the id of the group
+func AsyncExecGroup(name string, args ...string) (int, chan *Output, error) {
+	args = loadArgs(args...)
+
+	c := "-c"
+	args = append([]string{c}, args...)
+
 	cmd := exec.Command(name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	output, err := asyncExec(cmd)
+	if err != nil {
+		return 0, nil, err
+	}
+	return cmd.Process.Pid, output, nil
+}
+
+// KillGroup sends the signal to all the processes of the group, a group which has exited is ignored
+func KillGroup(pgid int, sig syscall.Signal) error {
+	if err := syscall.Kill(-pgid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("kill process group[pgid=%d,signal=%s] failure, err: %w", pgid, sig, err)
+	}
+	return nil
+}
+
+// GroupAlive returns true if any process of the group is still running, the zombies which are never reaped are ignored
+func GroupAlive(pgid int) bool {
+	if pgid <= 0 || syscall.Kill(-pgid, 0) != nil {
+		return false
+	}
+
+	stats, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil || len(stats) == 0 {
+		return true
+	}
+	for _, f := range stats {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		// pid (comm) state ppid pgrp ...
+		stat := string(data)
+		fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+		if len(fields) > 2 && fields[2] == strconv.Itoa(pgid) && fields[0] != "Z" {
+			return true
+		}
+	}
+	return false
+}
+
+// StartTime returns the start time of the process in clock ticks after the system boot, the field 22 of /proc/<pid>/stat
+func StartTime(pid int) (uint64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, fmt.Errorf("read stat of process[pid=%d] failure, err: %w", pid, err)
+	}
+	// pid (comm) state ppid pgrp ... starttime
+	stat := string(data)
+	fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("invalid stat of process[pid=%d]: %s", pid, stat)
+	}
+	startTime, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start time of process[pid=%d]: %s", pid, fields[19])
+	}
+	return startTime, nil
+}
+
+/*
+GroupAliveStartedAt returns true if the process group is alive and it is the group started at the start time:
+
+	the leader of the group must have been started at the start time if it is still running, otherwise the pid was reused.
+	A pid is never reused as long as its group exists, so the group without the leader is always the started one.
+	The start time 0 is unknown, the group with a running leader can not be verified and is not the started one.
+*/
+func GroupAliveStartedAt(pgid int, startTime uint64) bool {
+	if !GroupAlive(pgid) {
+		return false
+	}
+	st, err := StartTime(pgid)
+	if err != nil {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	return startTime != 0 && st == startTime
+}
+
+func asyncExec(cmd *exec.Cmd) (chan *Output, error) {
+	args := cmd.Args[1:]
 	logging.Debug(cmd.String())
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -20,6 +20,7 @@ package cmds
 import (
 	"os"
 	"strings"
+	"syscall"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -46,6 +47,41 @@ var _ = Describe("Commands", func() {
 		})
 	})
 
+	Context("AsyncExecGroup", func() {
+		It("kill the process group", func() {
+			pid, output, err := AsyncExecGroup(sh, "sleep 30 & sleep 30; echo done")
+			Expect(err).To(BeNil())
+			Expect(GroupAlive(pid)).To(BeTrue())
+
+			Expect(KillGroup(pid, syscall.SIGKILL)).To(Succeed())
+			var last *Output
+			for out := range output {
+				last = out
+			}
+			Expect(last.Error).NotTo(BeNil())
+			Eventually(func() bool { return GroupAlive(pid) }).Should(BeFalse())
+			Expect(KillGroup(pid, syscall.SIGKILL)).To(Succeed())
+		})
+
+		It("verify the process group by the start time", func() {
+			pid, output, err := AsyncExecGroup(sh, "sleep 30")
+			Expect(err).To(BeNil())
+			startTime, err := StartTime(pid)
+			Expect(err).To(BeNil())
+			Expect(GroupAliveStartedAt(pid, startTime)).To(BeTrue())
+			// the pid was reused by another process, or the start time is unknown
+			Expect(GroupAliveStartedAt(pid, startTime+1)).To(BeFalse())
+			Expect(GroupAliveStartedAt(pid, 0)).To(BeFalse())
+
+			Expect(KillGroup(pid, syscall.SIGKILL)).To(Succeed())
+			for range output {
+			}
+			Eventually(func() bool { return GroupAliveStartedAt(pid, startTime) }).Should(BeFalse())
+			_, err = StartTime(pid)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
 	Context("Exec", func() {
 		It("echo", func() {
 			output, err := Exec(sh, "sleep 1;echo 10;sleep 1;echo 20;")
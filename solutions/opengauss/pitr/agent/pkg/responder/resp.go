@@ -97,6 +97,24 @@ func ErrorWithData(ctx *fiber.Ctx, e error, data any) error {
 	})
 }
 
+// Unauthorized responds the error with the status 401
+func Unauthorized(ctx *fiber.Ctx, e error) error {
+	if err := Error(ctx, e); err != nil {
+		return err
+	}
+	ctx.Status(http.StatusUnauthorized)
+	return nil
+}
+
+// Forbidden responds the error with the status 403
+func Forbidden(ctx *fiber.Ctx, e error) error {
+	if err := Error(ctx, e); err != nil {
+		return err
+	}
+	ctx.Status(http.StatusForbidden)
+	return nil
+}
+
 func NotFound(ctx *fiber.Ctx, msg string) error {
 	ctx.Status(http.StatusNotFound)
 	return ctx.JSON(&resp{
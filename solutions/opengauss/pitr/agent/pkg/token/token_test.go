@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package token
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Token", func() {
+	key := []byte("secret")
+
+	It("sign and verify", func() {
+		t, err := Sign(key, &Claims{Subject: "gs_pitr", Role: "admin", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		Expect(err).To(BeNil())
+		Expect(strings.Count(t, ".")).To(Equal(2))
+
+		c, err := Verify(key, t)
+		Expect(err).To(BeNil())
+		Expect(c.Subject).To(Equal("gs_pitr"))
+		Expect(c.Role).To(Equal("admin"))
+	})
+
+	It("verify invalid tokens", func() {
+		t, err := Sign(key, &Claims{Role: "viewer"})
+		Expect(err).To(BeNil())
+
+		_, err = Verify([]byte("other"), t)
+		Expect(err).To(Equal(ErrInvalidToken))
+
+		// the role is changed without signing again
+		forged, err := Sign([]byte("other"), &Claims{Role: "admin"})
+		Expect(err).To(BeNil())
+		parts := strings.Split(t, ".")
+		_, err = Verify(key, strings.Join([]string{parts[0], strings.Split(forged, ".")[1], parts[2]}, "."))
+		Expect(err).To(Equal(ErrInvalidToken))
+
+		_, err = Verify(key, "a.b")
+		Expect(err).To(Equal(ErrInvalidToken))
+	})
+
+	It("verify expired token", func() {
+		t, err := Sign(key, &Claims{Role: "admin", ExpiresAt: time.Now().Add(-time.Second).Unix()})
+		Expect(err).To(BeNil())
+		_, err = Verify(key, t)
+		Expect(err).To(Equal(ErrTokenExpired))
+	})
+})
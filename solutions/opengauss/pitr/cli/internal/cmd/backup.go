@@ -20,6 +20,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
@@ -34,7 +35,6 @@ import (
 	"github.com/jedib0t/go-pretty/v6/progress"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -54,9 +54,7 @@ var BackupCmd = &cobra.Command{
 	Use:   "backup",
 	Short: "Backup a database cluster",
 	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
-			fmt.Printf("Flag: %s Value: %s\n", flag.Name, flag.Value)
-		})
+		cmd.Flags().VisitAll(printFlag)
 
 		// convert BackupModeStr to BackupMode
 		switch BackupModeStr {
@@ -69,7 +67,11 @@ var BackupCmd = &cobra.Command{
 			logging.Warn("Please make sure all openGauss nodes have been set correct configuration about ptrack. You can refer to https://support.huaweicloud.com/intl/zh-cn/devg-opengauss/opengauss_devg_1362.html for more details.")
 		}
 
-		logging.Info(fmt.Sprintf("Default backup path: %s", pkg.DefaultRootDir()))
+		if Catalog == "" {
+			logging.Info(fmt.Sprintf("Default backup path: %s", pkg.DefaultRootDir()))
+		} else {
+			logging.Info(fmt.Sprintf("Backup catalog: %s", Catalog))
+		}
 
 		// Start backup
 		if err := backup(); err != nil {
@@ -95,7 +97,12 @@ func init() {
 	_ = BackupCmd.MarkFlagRequired("dn-backup-mode")
 	BackupCmd.Flags().Uint8VarP(&ThreadsNum, "dn-threads-num", "j", 1, "openGauss data backup threads nums")
 	BackupCmd.Flags().Uint16VarP(&AgentPort, "agent-port", "a", 443, "agent server port")
+	BackupCmd.Flags().StringVarP(&AgentCA, "agent-ca", "", "", "CA file to verify the agent servers (default not verified)")
+	BackupCmd.Flags().StringVarP(&AgentCert, "agent-cert", "", "", "client certificate file for the agent servers requiring mutual TLS")
+	BackupCmd.Flags().StringVarP(&AgentKey, "agent-key", "", "", "key file of the agent client certificate")
+	BackupCmd.Flags().StringVarP(&AgentToken, "agent-token", "", "", "bearer token for the agent servers (default env GS_PITR_AGENT_TOKEN)")
 	_ = BackupCmd.MarkFlagRequired("agent-port")
+	BackupCmd.Flags().StringVarP(&Catalog, "catalog", "", "", "backup catalog url, e.g. file:///home/omm/.gs_pitr or s3://bucket/prefix?endpoint=http://127.0.0.1:9000 (default local catalog)")
 
 }
 
@@ -119,9 +126,9 @@ func backup() error {
 		return xerr.NewCliErr(fmt.Sprintf("Connect shardingsphere proxy failed, err: %s", err))
 	}
 
-	ls, err := pkg.NewLocalStorage(pkg.DefaultRootDir())
+	ls, err := pkg.NewCatalog(Catalog)
 	if err != nil {
-		return xerr.NewCliErr(fmt.Sprintf("Create local storage failed. err: %s", err))
+		return xerr.NewCliErr(fmt.Sprintf("Create backup catalog failed. err: %s", err))
 	}
 
 	defer func() {
@@ -207,6 +214,9 @@ func backup() error {
 	}
 
 	// Step9. finished backup and update backup file
+	if BackupMode == model.DBBackModePTrack {
+		checkParents(ls, lsBackup)
+	}
 	logging.Info("Starting update backup file ...")
 	err = ls.WriteByJSON(filename, lsBackup)
 	if err != nil {
@@ -225,7 +235,7 @@ func exportData(proxy pkg.IShardingSphereProxy, ls pkg.ILocalStorage) (lsBackup
 	}
 
 	// Step2. export storage nodes from ss-proxy
-	nodes, err := proxy.ExportStorageNodes()
+	nodesInfo, err := proxy.ExportStorageNodes()
 	if err != nil {
 		return nil, xerr.NewCliErr(fmt.Sprintf("export storage nodes failed. err: %s", err))
 	}
@@ -246,9 +256,10 @@ func exportData(proxy pkg.IShardingSphereProxy, ls pkg.ILocalStorage) (lsBackup
 			BackupMode: BackupMode,
 		},
 		SsBackup: &model.SsBackup{
-			Status:       model.SsBackupStatusWaiting, // default status of backup is model.SsBackupStatusWaiting
-			ClusterInfo:  cluster,
-			StorageNodes: nodes,
+			Status:         model.SsBackupStatusWaiting, // default status of backup is model.SsBackupStatusWaiting
+			ClusterInfo:    cluster,
+			StorageNodes:   nodesInfo.Nodes(),
+			LogicDatabases: nodesInfo.StorageNodes,
 		},
 	}
 
@@ -269,7 +280,7 @@ func execBackup(lsBackup *model.LsBackup) error {
 
 	for _, node := range sNodes {
 		sn := node
-		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), AgentPort))
+		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
 		g.Go(func() error {
 			return _execBackup(as, sn, dnCh)
 		})
@@ -294,6 +305,12 @@ func execBackup(lsBackup *model.LsBackup) error {
 }
 
 func _execBackup(as pkg.IAgentServer, node *model.StorageNode, dnCh chan *model.DataNode) error {
+	mode := BackupMode
+	if mode == model.DBBackModePTrack && !hasValidParent(as, node) {
+		logging.Warn(fmt.Sprintf("Data node %s:%d has no valid parent backup, fall back to FULL backup.", node.IP, node.Port))
+		mode = model.DBBackModeFull
+	}
+
 	in := &model.BackupIn{
 		DBPort:       node.Port,
 		DBName:       node.Database,
@@ -301,7 +318,7 @@ func _execBackup(as pkg.IAgentServer, node *model.StorageNode, dnCh chan *model.
 		Password:     node.Password,
 		DnBackupPath: BackupPath,
 		DnThreadsNum: ThreadsNum,
-		DnBackupMode: BackupMode,
+		DnBackupMode: mode,
 		Instance:     defaultInstance,
 	}
 	backupID, err := as.Backup(in)
@@ -312,12 +329,13 @@ func _execBackup(as pkg.IAgentServer, node *model.StorageNode, dnCh chan *model.
 
 	// update DnList of lsBackup
 	dn := &model.DataNode{
-		IP:        node.IP,
-		Port:      node.Port,
-		Status:    status,
-		BackupID:  backupID,
-		StartTime: timeutil.Now().String(),
-		EndTime:   timeutil.Init(),
+		IP:         node.IP,
+		Port:       node.Port,
+		Status:     status,
+		BackupID:   backupID,
+		BackupMode: mode,
+		StartTime:  timeutil.Now().String(),
+		EndTime:    timeutil.Init(),
 	}
 	dnCh <- dn
 	if err != nil {
@@ -326,6 +344,70 @@ func _execBackup(as pkg.IAgentServer, node *model.StorageNode, dnCh chan *model.
 	return nil
 }
 
+// hasValidParent returns true if the data node has a completed backup, which gs_probackup takes as the parent of a PTRACK backup
+func hasValidParent(as pkg.IAgentServer, node *model.StorageNode) bool {
+	in := &model.ShowListIn{
+		DBPort:       node.Port,
+		DBName:       node.Database,
+		Username:     node.Username,
+		Password:     node.Password,
+		DnBackupPath: BackupPath,
+		Instance:     defaultInstance,
+	}
+	list, err := as.ShowList(in)
+	if err != nil {
+		return false
+	}
+	for _, b := range list {
+		if b.Status == model.SsBackupStatusCompleted {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+checkParents checks the parent backups of the data nodes of the PTRACK record are in the backup records,
+which may be different records for different data nodes.
+The record turns FULL if all the data nodes fell back to FULL backups.
+*/
+func checkParents(ls pkg.ILocalStorage, lsBackup *model.LsBackup) {
+	full := true
+	for _, dn := range lsBackup.DnList {
+		if dn.BackupMode != model.DBBackModeFull {
+			full = false
+		}
+	}
+	if full {
+		lsBackup.Info.BackupMode = model.DBBackModeFull
+		return
+	}
+
+	baks, err := ls.ReadAll()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Read backup records failed, the parent backups are not checked. err: %s", err))
+		return
+	}
+	all := []*model.LsBackup{lsBackup}
+	for _, b := range baks {
+		if b.Info.ID != lsBackup.Info.ID {
+			all = append(all, b)
+		}
+	}
+	parents := backupParents(all)[lsBackup.Info.ID]
+	if missing := missingParents(lsBackup, parents); len(missing) > 0 {
+		logging.Warn(fmt.Sprintf("The parent backups %s of the PTRACK backup are not found.", strings.Join(missing, ", ")))
+	}
+	if len(parents) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(parents))
+	for _, p := range parents {
+		ids = append(ids, p.Info.ID)
+	}
+	logging.Info(fmt.Sprintf("The PTRACK backup depends on the backup records %s.", strings.Join(ids, ", ")))
+}
+
 func checkBackupStatus(lsBackup *model.LsBackup) model.BackupStatus {
 	var (
 		dataNodeMap       = make(map[string]*model.DataNode)
@@ -341,7 +423,7 @@ func checkBackupStatus(lsBackup *model.LsBackup) model.BackupStatus {
 	}
 
 	for _, dn := range lsBackup.DnList {
-		dataNodeMap[dn.IP] = dn
+		dataNodeMap[nodeKey(dn.IP, dn.Port)] = dn
 	}
 
 	pw := prettyoutput.NewProgressPrinter(prettyoutput.ProgressPrintOption{
@@ -352,8 +434,8 @@ func checkBackupStatus(lsBackup *model.LsBackup) model.BackupStatus {
 
 	for i := 0; i < totalNum; i++ {
 		sn := lsBackup.SsBackup.StorageNodes[i]
-		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), AgentPort))
-		dn := dataNodeMap[sn.IP]
+		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
+		dn := dataNodeMap[nodeKey(sn.IP, sn.Port)]
 		backupInfo := &model.BackupInfo{}
 		task := &backuptask{
 			As:      as,
@@ -438,6 +520,10 @@ func (t *backuptask) checkProgress() (bool, error) {
 
 	t.Dn.Status = t.Backup.Status
 	t.Dn.EndTime = timeutil.Now().String()
+	t.Dn.ParentBackupID = t.Backup.ParentID
+	t.Dn.StartLsn = t.Backup.StartLsn
+	t.Dn.StopLsn = t.Backup.StopLsn
+	t.Dn.ContentCrc = t.Backup.ContentCrc
 
 	if t.Backup.Status == model.SsBackupStatusCompleted || t.Backup.Status == model.SsBackupStatusFailed {
 		t.DnCh <- t.Dn
@@ -460,7 +546,7 @@ func deleteBackupFiles(ls pkg.ILocalStorage, lsBackup *model.LsBackup, m deleteM
 		resultCh    = make(chan *model.DeleteBackupResult, totalNum)
 	)
 	for _, dn := range lsBackup.DnList {
-		dataNodeMap[dn.IP] = dn
+		dataNodeMap[nodeKey(dn.IP, dn.Port)] = dn
 	}
 
 	if totalNum == 0 {
@@ -473,14 +559,14 @@ func deleteBackupFiles(ls pkg.ILocalStorage, lsBackup *model.LsBackup, m deleteM
 
 	for _, sn := range lsBackup.SsBackup.StorageNodes {
 		sn := sn
-		dn, ok := dataNodeMap[sn.IP]
+		dn, ok := dataNodeMap[nodeKey(sn.IP, sn.Port)]
 		if !ok {
 			if m != deleteModeQuiet {
 				logging.Warn(fmt.Sprintf("SKIPPED! data node %s:%d not found in backup info.", sn.IP, sn.Port))
 			}
 			continue
 		}
-		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), AgentPort))
+		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
 
 		go doDelete(as, sn, dn, resultCh, pw)
 	}
@@ -119,7 +119,7 @@ var _ = Describe("Backup", func() {
 			// mock proxy export metadata
 			proxy.EXPECT().ExportMetaData().Return(&model.ClusterInfo{}, nil)
 			// mock proxy export node storage data
-			proxy.EXPECT().ExportStorageNodes().Return([]*model.StorageNode{}, nil)
+			proxy.EXPECT().ExportStorageNodes().Return(&model.StorageNodesInfo{}, nil)
 			// mock ls generate filename
 			ls.EXPECT().GenFilename(pkg.ExtnJSON).Return("mock.json")
 			// mock ls write by json
@@ -185,6 +185,71 @@ var _ = Describe("Backup", func() {
 			Expect(len(dnCh)).To(Equal(2))
 
 		})
+
+		It("fall back to full backup without valid parent", func() {
+			BackupMode = model.DBBackModePTrack
+			defer func() {
+				BackupMode = ""
+			}()
+			node := &model.StorageNode{IP: "127.0.0.1", Port: 5432}
+			dnCh := make(chan *model.DataNode, 2)
+
+			as.EXPECT().ShowList(gomock.Any()).Return([]model.BackupInfo{{ID: "RS3ZGQ", Status: model.SsBackupStatusFailed}}, nil)
+			as.EXPECT().Backup(gomock.Any()).DoAndReturn(func(in *model.BackupIn) (string, error) {
+				Expect(in.DnBackupMode).To(Equal(model.DBBackModeFull))
+				return "RS3ZGR", nil
+			})
+			Expect(_execBackup(as, node, dnCh)).To(BeNil())
+			Expect((<-dnCh).BackupMode).To(Equal(model.DBBackModeFull))
+
+			as.EXPECT().ShowList(gomock.Any()).Return([]model.BackupInfo{{ID: "RS3ZGR", Status: model.SsBackupStatusCompleted}}, nil)
+			as.EXPECT().Backup(gomock.Any()).DoAndReturn(func(in *model.BackupIn) (string, error) {
+				Expect(in.DnBackupMode).To(Equal(model.DBBackModePTrack))
+				return "RS3ZGS", nil
+			})
+			Expect(_execBackup(as, node, dnCh)).To(BeNil())
+			Expect((<-dnCh).BackupMode).To(Equal(model.DBBackModePTrack))
+		})
+	})
+
+	Context("link parent", func() {
+		var ls *mock_pkg.MockILocalStorage
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			ls = mock_pkg.NewMockILocalStorage(ctrl)
+		})
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("check the parent backups in the records", func() {
+			parent := &model.LsBackup{
+				Info:   &model.BackupMetaInfo{ID: "parent", StartTime: "2023-03-01 00:00:00"},
+				DnList: []*model.DataNode{{IP: "127.0.0.1", BackupID: "RS3ZGR"}},
+			}
+			older := &model.LsBackup{
+				Info:   &model.BackupMetaInfo{ID: "older", StartTime: "2023-02-01 00:00:00"},
+				DnList: []*model.DataNode{{IP: "127.0.0.1", BackupID: "RS3ZGQ"}},
+			}
+			bak := &model.LsBackup{
+				Info:   &model.BackupMetaInfo{ID: "child", BackupMode: model.DBBackModePTrack},
+				DnList: []*model.DataNode{{IP: "127.0.0.1", BackupID: "RS3ZGS", BackupMode: model.DBBackModePTrack, ParentBackupID: "RS3ZGR"}},
+			}
+			ls.EXPECT().ReadAll().Return([]*model.LsBackup{bak, older, parent}, nil)
+
+			checkParents(ls, bak)
+			Expect(bak.Info.BackupMode).To(Equal(model.DBBackModePTrack))
+		})
+
+		It("all the data nodes fell back to full backups", func() {
+			bak := &model.LsBackup{
+				Info:   &model.BackupMetaInfo{ID: "child", BackupMode: model.DBBackModePTrack},
+				DnList: []*model.DataNode{{IP: "127.0.0.1", BackupID: "RS3ZGS", BackupMode: model.DBBackModeFull}},
+			}
+
+			checkParents(ls, bak)
+			Expect(bak.Info.BackupMode).To(Equal(model.DBBackModeFull))
+		})
 	})
 
 	Context("check backup status", func() {
@@ -361,7 +426,7 @@ var _ = Describe("test backup mock", func() {
 		It("test backup empty", func() {
 			proxy.EXPECT().LockForBackup().Return(nil)
 			proxy.EXPECT().ExportMetaData().Return(&model.ClusterInfo{}, nil)
-			proxy.EXPECT().ExportStorageNodes().Return([]*model.StorageNode{}, nil)
+			proxy.EXPECT().ExportStorageNodes().Return(&model.StorageNodesInfo{}, nil)
 			proxy.EXPECT().Unlock().Return(nil)
 			ls.EXPECT().GenFilename(gomock.Any()).Return("filename")
 			ls.EXPECT().WriteByJSON(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
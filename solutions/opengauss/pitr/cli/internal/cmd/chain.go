@@ -0,0 +1,210 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
+)
+
+/*
+backupParents returns the parent records of each PTRACK record by the record id.
+
+The backup of each data node depends on its parent backup, which is in the record holding the backup of the same data node
+with the parent backup id, so the data nodes of a record may depend on different records.
+The records created by earlier versions have no parent backups, the parent of such a PTRACK record is the completed record
+right before it, which is the one gs_probackup picks.
+*/
+func backupParents(baks []*model.LsBackup) map[string][]*model.LsBackup {
+	var (
+		byBackup = make(map[string]*model.LsBackup)
+		sorted   = make([]*model.LsBackup, 0, len(baks))
+		parents  = make(map[string][]*model.LsBackup)
+	)
+	for _, bak := range baks {
+		if bak == nil || bak.Info == nil {
+			continue
+		}
+		for _, dn := range bak.DnList {
+			byBackup[backupKey(dn.IP, dn.Port, dn.BackupID)] = bak
+		}
+		sorted = append(sorted, bak)
+	}
+	// the start time is sortable in the unified time layout
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Info.StartTime < sorted[j].Info.StartTime
+	})
+
+	var prev *model.LsBackup
+	for _, bak := range sorted {
+		linked := false
+		for _, dn := range bak.DnList {
+			if dn.ParentBackupID == "" {
+				continue
+			}
+			linked = true
+			if p, ok := byBackup[backupKey(dn.IP, dn.Port, dn.ParentBackupID)]; ok && p != bak && !containsBackup(parents[bak.Info.ID], p) {
+				parents[bak.Info.ID] = append(parents[bak.Info.ID], p)
+			}
+		}
+		if !linked && bak.Info.BackupMode == model.DBBackModePTrack && prev != nil {
+			parents[bak.Info.ID] = []*model.LsBackup{prev}
+		}
+		if bak.SsBackup != nil && bak.SsBackup.Status == model.SsBackupStatusCompleted {
+			prev = bak
+		}
+	}
+	return parents
+}
+
+// backupKey identifies the backup of a data node like `127.0.0.1:5432/RS3ZGR`
+func backupKey(ip string, port uint16, backupID string) string {
+	return fmt.Sprintf("%s/%s", nodeKey(ip, port), backupID)
+}
+
+func containsBackup(baks []*model.LsBackup, bak *model.LsBackup) bool {
+	for _, b := range baks {
+		if b.Info.ID == bak.Info.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// missingParents returns the parent backups of the data nodes which are not in the parent records
+func missingParents(bak *model.LsBackup, parents []*model.LsBackup) []string {
+	found := make(map[string]struct{})
+	for _, p := range parents {
+		for _, dn := range p.DnList {
+			found[backupKey(dn.IP, dn.Port, dn.BackupID)] = struct{}{}
+		}
+	}
+	var missing []string
+	for _, dn := range bak.DnList {
+		if dn.ParentBackupID == "" {
+			continue
+		}
+		if _, ok := found[backupKey(dn.IP, dn.Port, dn.ParentBackupID)]; !ok {
+			missing = append(missing, backupKey(dn.IP, dn.Port, dn.ParentBackupID))
+		}
+	}
+	return missing
+}
+
+// backupAncestors returns the records which the backup depends on directly or indirectly by the record id
+func backupAncestors(bak *model.LsBackup, parents map[string][]*model.LsBackup) map[string]*model.LsBackup {
+	ancestors := make(map[string]*model.LsBackup)
+	queue := append([]*model.LsBackup{}, parents[bak.Info.ID]...)
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if _, ok := ancestors[p.Info.ID]; ok {
+			continue
+		}
+		ancestors[p.Info.ID] = p
+		queue = append(queue, parents[p.Info.ID]...)
+	}
+	return ancestors
+}
+
+// backupChain returns the records which the backups of all the data nodes depend on, from the oldest FULL one to the backup itself
+func backupChain(bak *model.LsBackup, parents map[string][]*model.LsBackup) ([]*model.LsBackup, error) {
+	ancestors := backupAncestors(bak, parents)
+	if _, ok := ancestors[bak.Info.ID]; ok {
+		return []*model.LsBackup{bak}, fmt.Errorf("the parents of backup record %s are cyclic", bak.Info.ID)
+	}
+
+	chain := make([]*model.LsBackup, 0, len(ancestors)+1)
+	for _, a := range ancestors {
+		chain = append(chain, a)
+	}
+	sort.SliceStable(chain, func(i, j int) bool {
+		if chain[i].Info.StartTime != chain[j].Info.StartTime {
+			return chain[i].Info.StartTime < chain[j].Info.StartTime
+		}
+		return chain[i].Info.ID < chain[j].Info.ID
+	})
+	chain = append(chain, bak)
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		b := chain[i]
+		if b.Info.BackupMode != model.DBBackModePTrack {
+			continue
+		}
+		if missing := missingParents(b, parents[b.Info.ID]); len(missing) > 0 {
+			return chain, fmt.Errorf("the parent backups %s of backup record %s are missing", strings.Join(missing, ", "), b.Info.ID)
+		}
+		if len(parents[b.Info.ID]) == 0 {
+			return chain, fmt.Errorf("the parent record of backup record %s is not found", b.Info.ID)
+		}
+	}
+	return chain, nil
+}
+
+// backupDescendants returns the records depending on the backup, the newest first
+func backupDescendants(bak *model.LsBackup, baks []*model.LsBackup, parents map[string][]*model.LsBackup) []*model.LsBackup {
+	var descendants []*model.LsBackup
+	for _, b := range baks {
+		if b == nil || b.Info == nil || b.Info.ID == bak.Info.ID {
+			continue
+		}
+		if _, ok := backupAncestors(b, parents)[bak.Info.ID]; ok {
+			descendants = append(descendants, b)
+		}
+	}
+	sort.SliceStable(descendants, func(i, j int) bool {
+		return descendants[i].Info.StartTime > descendants[j].Info.StartTime
+	})
+	return descendants
+}
+
+// chainString formats the chain like `FULL a -> PTRACK b -> PTRACK c`
+func chainString(chain []*model.LsBackup) string {
+	var s string
+	for i, b := range chain {
+		if i > 0 {
+			s += " -> "
+		}
+		s += fmt.Sprintf("%s %s", b.Info.BackupMode, b.Info.ID)
+	}
+	return s
+}
+
+// explainChain logs the records which the PTRACK backup depends on, it fails if any of them is missing
+func explainChain(ls pkg.ILocalStorage, bak *model.LsBackup) error {
+	if bak.Info.BackupMode != model.DBBackModePTrack {
+		return nil
+	}
+
+	baks, err := ls.ReadAll()
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("read backup records failed. err: %s", err))
+	}
+	chain, err := backupChain(bak, backupParents(baks))
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("backup record %s can not be restored. err: %s", bak.Info.ID, err))
+	}
+	logging.Info(fmt.Sprintf("Backup record %s depends on the FULL backup record %s: %s", bak.Info.ID, chain[0].Info.ID, chainString(chain)))
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bou.ke/monkey"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
+	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/mocks"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/promptutil"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("test backup chain", func() {
+	record := func(id string, mode model.DBBackupMode, parentID, startTime string) *model.LsBackup {
+		dn := &model.DataNode{IP: "127.0.0.1", Port: 5432, BackupID: "dn-" + id, BackupMode: mode}
+		if parentID != "" {
+			dn.ParentBackupID = "dn-" + parentID
+		}
+		return &model.LsBackup{
+			Info:   &model.BackupMetaInfo{ID: id, BackupMode: mode, StartTime: startTime, FileName: id + ".json"},
+			DnList: []*model.DataNode{dn},
+			SsBackup: &model.SsBackup{
+				Status:       model.SsBackupStatusCompleted,
+				StorageNodes: []*model.StorageNode{{IP: "127.0.0.1", Port: 5432}},
+			},
+		}
+	}
+
+	var (
+		f1, p1, p2, f2, p3, legacy *model.LsBackup
+		all                        []*model.LsBackup
+	)
+	BeforeEach(func() {
+		f1 = record("f1", model.DBBackModeFull, "", "2023-03-01 00:00:00")
+		p1 = record("p1", model.DBBackModePTrack, "f1", "2023-03-02 00:00:00")
+		p2 = record("p2", model.DBBackModePTrack, "p1", "2023-03-03 00:00:00")
+		f2 = record("f2", model.DBBackModeFull, "", "2023-03-04 00:00:00")
+		p3 = record("p3", model.DBBackModePTrack, "f1", "2023-03-05 00:00:00")
+		legacy = record("legacy", model.DBBackModePTrack, "", "2023-03-06 00:00:00")
+		all = []*model.LsBackup{legacy, p3, f2, p2, p1, f1}
+	})
+
+	It("parents", func() {
+		parents := backupParents(all)
+		Expect(parents).To(HaveLen(4))
+		Expect(parents["p1"]).To(Equal([]*model.LsBackup{f1}))
+		Expect(parents["p3"]).To(Equal([]*model.LsBackup{f1}))
+		// the record created by earlier versions depends on the completed record right before it
+		Expect(parents["legacy"]).To(Equal([]*model.LsBackup{p3}))
+	})
+
+	It("data nodes depending on different records", func() {
+		dn2 := func(id string, mode model.DBBackupMode, parentID string) *model.DataNode {
+			return &model.DataNode{IP: "127.0.0.1", Port: 5433, BackupID: "dn2-" + id, BackupMode: mode, ParentBackupID: parentID}
+		}
+		// the second data node fell back to a FULL backup in p1, so p2 depends on f1 for the first one and p1 for the second one
+		f1.DnList = append(f1.DnList, dn2("f1", model.DBBackModeFull, ""))
+		p1.DnList = append(p1.DnList, dn2("p1", model.DBBackModeFull, ""))
+		p2.DnList[0].ParentBackupID = "dn-f1"
+		p2.DnList = append(p2.DnList, dn2("p2", model.DBBackModePTrack, "dn2-p1"))
+		all = []*model.LsBackup{p2, p1, f1}
+
+		parents := backupParents(all)
+		Expect(parents["p2"]).To(ConsistOf(f1, p1))
+		chain, err := backupChain(p2, parents)
+		Expect(err).To(BeNil())
+		Expect(chainString(chain)).To(Equal("FULL f1 -> PTRACK p1 -> PTRACK p2"))
+		Expect(backupDescendants(p1, all, parents)).To(Equal([]*model.LsBackup{p2}))
+		Expect(formatBackupTree(all)).To(ContainSubstring("PTRACK p2 (CSN: , start time: 2023-03-03 00:00:00, status: Completed) [also depends on f1]"))
+
+		_, err = backupChain(p2, backupParents([]*model.LsBackup{p2, f1}))
+		Expect(err).To(MatchError("the parent backups 127.0.0.1:5433/dn2-p1 of backup record p2 are missing"))
+	})
+
+	It("chain", func() {
+		parents := backupParents(all)
+		chain, err := backupChain(p2, parents)
+		Expect(err).To(BeNil())
+		Expect(chainString(chain)).To(Equal("FULL f1 -> PTRACK p1 -> PTRACK p2"))
+
+		chain, err = backupChain(f2, parents)
+		Expect(err).To(BeNil())
+		Expect(chain).To(Equal([]*model.LsBackup{f2}))
+
+		_, err = backupChain(p2, backupParents([]*model.LsBackup{p2, p1}))
+		Expect(err).To(MatchError("the parent backups 127.0.0.1:5432/dn-f1 of backup record p1 are missing"))
+	})
+
+	It("descendants", func() {
+		parents := backupParents(all)
+		Expect(backupDescendants(f1, all, parents)).To(Equal([]*model.LsBackup{legacy, p3, p2, p1}))
+		Expect(backupDescendants(p1, all, parents)).To(Equal([]*model.LsBackup{p2}))
+		Expect(backupDescendants(f2, all, parents)).To(BeEmpty())
+	})
+
+	It("tree", func() {
+		tree := formatBackupTree(append(all, record("orphan", model.DBBackModePTrack, "gone", "2023-03-07 00:00:00")))
+		Expect(tree).To(ContainSubstring("FULL f1"))
+		Expect(tree).To(ContainSubstring("PTRACK orphan (CSN: , start time: 2023-03-07 00:00:00, status: Completed) [parent missing]"))
+		Expect(tree).To(MatchRegexp(`FULL f1[^\n]*\n[^\n]*PTRACK p1[^\n]*\n[^\n]*PTRACK p2[^\n]*\n[^\n]*PTRACK p3[^\n]*\n[^\n]*PTRACK legacy`))
+	})
+
+	Context("merge", func() {
+		var (
+			ls *mock_pkg.MockILocalStorage
+			as *mock_pkg.MockIAgentServer
+		)
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			ls = mock_pkg.NewMockILocalStorage(ctrl)
+			as = mock_pkg.NewMockIAgentServer(ctrl)
+			monkey.Patch(pkg.NewLocalStorage, func(rootDir string) (pkg.ILocalStorage, error) {
+				return ls, nil
+			})
+			monkey.Patch(pkg.NewAgentServer, func(_ string) pkg.IAgentServer {
+				return as
+			})
+			monkey.Patch(promptutil.GetUserApproveInTerminal, func(_ string) error {
+				return nil
+			})
+		})
+		AfterEach(func() {
+			RecordID = ""
+			ctrl.Finish()
+			monkey.UnpatchAll()
+		})
+
+		It("merge the chain", func() {
+			all = []*model.LsBackup{p2, p1, f1}
+			child := record("child", model.DBBackModePTrack, "p2", "2023-03-04 00:00:00")
+			all = append(all, child)
+
+			RecordID = "p2"
+			ls.EXPECT().ReadByID("p2").Return(p2, nil)
+			ls.EXPECT().ReadAll().Return(all, nil)
+			as.EXPECT().CheckStatus(gomock.Any()).Return(nil)
+			as.EXPECT().Merge(gomock.Any()).Return(&model.BackupInfo{ID: "dn-f1", Mode: "FULL", StartLsn: "0/1000028", StopLsn: "0/30001C0", ContentCrc: 1}, nil)
+			ls.EXPECT().WriteByJSON("child.json", child).Return(nil)
+			ls.EXPECT().WriteByJSON("p2.json", p2).Return(nil)
+			for _, id := range []string{"p1", "f1"} {
+				ls.EXPECT().HideByName(id + ".json").Return(nil)
+				ls.EXPECT().DeleteByHidedName(id + ".json").Return(nil)
+			}
+
+			Expect(mergeRecord()).To(BeNil())
+			Expect(p2.Info.BackupMode).To(Equal(model.DBBackModeFull))
+			Expect(p2.DnList[0].BackupID).To(Equal("dn-f1"))
+			Expect(p2.DnList[0].ContentCrc).To(Equal(int64(1)))
+			Expect(child.DnList[0].ParentBackupID).To(Equal("dn-f1"))
+		})
+
+		It("other records depend on the chain", func() {
+			RecordID = "p2"
+			ls.EXPECT().ReadByID("p2").Return(p2, nil)
+			ls.EXPECT().ReadAll().Return(all, nil)
+
+			Expect(mergeRecord()).To(MatchError(ContainSubstring("backup record(ID: p3) depends on backup record(ID: f1)")))
+		})
+
+		It("not a ptrack record", func() {
+			RecordID = "f1"
+			ls.EXPECT().ReadByID("f1").Return(f1, nil)
+
+			Expect(mergeRecord()).NotTo(BeNil())
+		})
+	})
+})
@@ -26,8 +26,26 @@ import (
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/pflag"
 )
 
+// the flags whose values are masked when printed
+var secretFlags = map[string]struct{}{
+	"password":    {},
+	"agent-token": {},
+}
+
+func printFlag(flag *pflag.Flag) {
+	fmt.Printf("Flag: %s Value: %s\n", flag.Name, flagValue(flag))
+}
+
+func flagValue(flag *pflag.Flag) string {
+	if _, ok := secretFlags[flag.Name]; ok && flag.Value.String() != "" {
+		return "******"
+	}
+	return flag.Value.String()
+}
+
 func validate(ls pkg.ILocalStorage, csn, recordID string) ([]*model.LsBackup, error) {
 	var (
 		baks []*model.LsBackup
@@ -72,13 +90,13 @@ func checkAgentServerStatus(lsBackup *model.LsBackup) bool {
 	// all agent server are available
 	available := true
 
-	// IMPORTANT: we don't support multiple agent server run on the same host
+	// IMPORTANT: we don't support multiple storage nodes served by the same agent server
 	asMap := make(map[string]bool)
 	asDuplicate := false
 
 	for _, node := range lsBackup.SsBackup.StorageNodes {
 		sn := node
-		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), AgentPort))
+		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
 		in := &model.HealthCheckIn{
 			DBPort:   sn.Port,
 			DBName:   sn.Database,
@@ -86,10 +104,10 @@ func checkAgentServerStatus(lsBackup *model.LsBackup) bool {
 			Password: sn.Password,
 		}
 		if err := as.CheckStatus(in); err != nil {
-			statusList = append(statusList, &model.AgentServerStatus{IP: sn.IP, Port: AgentPort, Status: fmt.Sprintf("Unavailable: %s", err)})
+			statusList = append(statusList, &model.AgentServerStatus{IP: sn.IP, Port: agentPortOf(sn.IP, sn.Port), Status: fmt.Sprintf("Unavailable: %s", err)})
 			available = false
 		} else {
-			statusList = append(statusList, &model.AgentServerStatus{IP: sn.IP, Port: AgentPort, Status: "Available"})
+			statusList = append(statusList, &model.AgentServerStatus{IP: sn.IP, Port: agentPortOf(sn.IP, sn.Port), Status: "Available"})
 		}
 	}
 
@@ -106,15 +124,16 @@ func checkAgentServerStatus(lsBackup *model.LsBackup) bool {
 	t.Render()
 
 	for _, node := range lsBackup.SsBackup.StorageNodes {
-		if _, ok := asMap[node.IP]; ok {
+		addr := nodeKey(node.IP, agentPortOf(node.IP, node.Port))
+		if _, ok := asMap[addr]; ok {
 			asDuplicate = true
 			break
 		}
-		asMap[node.IP] = true
+		asMap[addr] = true
 	}
 
 	if asDuplicate {
-		logging.Error("IMPORTANT!: we don't support multiple agent server run on the same host.\n")
+		logging.Error("IMPORTANT!: we don't support multiple storage nodes served by the same agent server.\n")
 		return false
 	}
 
@@ -127,7 +146,7 @@ func checkDiskSpace(lsBackup *model.LsBackup) error {
 	)
 	for _, sn := range lsBackup.SsBackup.StorageNodes {
 		var data string
-		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), AgentPort))
+		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
 		in := &model.DiskSpaceIn{
 			DiskPath: BackupPath,
 		}
@@ -20,6 +20,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
@@ -31,7 +32,6 @@ import (
 	"github.com/jedib0t/go-pretty/v6/table"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 )
 
 //nolint:dupl
@@ -39,9 +39,7 @@ var DeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a backup record",
 	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
-			fmt.Printf("Flag: %s Value: %s\n", flag.Name, flag.Value)
-		})
+		cmd.Flags().VisitAll(printFlag)
 
 		if CSN == "" && RecordID == "" {
 			logging.Error("Please specify csn or record id")
@@ -74,23 +72,30 @@ func init() {
 	DeleteCmd.Flags().StringVarP(&BackupPath, "dn-backup-path", "B", "", "openGauss data backup path")
 	_ = DeleteCmd.MarkFlagRequired("dn-backup-path")
 	DeleteCmd.Flags().Uint16VarP(&AgentPort, "agent-port", "a", 443, "agent server port")
+	DeleteCmd.Flags().StringVarP(&AgentCA, "agent-ca", "", "", "CA file to verify the agent servers (default not verified)")
+	DeleteCmd.Flags().StringVarP(&AgentCert, "agent-cert", "", "", "client certificate file for the agent servers requiring mutual TLS")
+	DeleteCmd.Flags().StringVarP(&AgentKey, "agent-key", "", "", "key file of the agent client certificate")
+	DeleteCmd.Flags().StringVarP(&AgentToken, "agent-token", "", "", "bearer token for the agent servers (default env GS_PITR_AGENT_TOKEN)")
 	_ = DeleteCmd.MarkFlagRequired("agent-port")
 
 	DeleteCmd.Flags().StringVarP(&CSN, "csn", "", "", "commit sequence number")
 	DeleteCmd.Flags().StringVarP(&RecordID, "id", "", "", "backup record id")
+	DeleteCmd.Flags().StringVarP(&Catalog, "catalog", "", "", "backup catalog url, e.g. file:///home/omm/.gs_pitr or s3://bucket/prefix?endpoint=http://127.0.0.1:9000 (default local catalog)")
 }
 
 const (
 	deletePromptFmt = "The backup record(ID: %s, CSN: %s) will be deleted forever.\n" +
 		"Are you sure to continue? (Y/N)"
+	deleteChainPromptFmt = "The backup record(ID: %s, CSN: %s) and the PTRACK backup records depending on it(ID: %s) will be deleted forever.\n" +
+		"Are you sure to continue? (Y/N)"
 )
 
 //nolint:dupl
 func deleteRecord() error {
 	// init local storage
-	ls, err := pkg.NewLocalStorage(pkg.DefaultRootDir())
+	ls, err := pkg.NewCatalog(Catalog)
 	if err != nil {
-		return xerr.NewCliErr(fmt.Sprintf("new local storage failed. err: %s", err.Error()))
+		return xerr.NewCliErr(fmt.Sprintf("new backup catalog failed. err: %s", err.Error()))
 	}
 
 	// get backup record
@@ -107,16 +112,42 @@ func deleteRecord() error {
 		return xerr.NewCliErr("one or more agent server are not available.")
 	}
 
+	// the PTRACK records depending on the backup are deleted with it, gs_probackup deletes their backups anyway
+	all, err := ls.ReadAll()
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("read backup records failed. err: %s", err))
+	}
+	descendants := backupDescendants(bak, all, backupParents(all))
+
 	prompt := fmt.Sprintf(deletePromptFmt, bak.Info.ID, bak.Info.CSN)
+	if len(descendants) > 0 {
+		ids := make([]string, 0, len(descendants))
+		for _, d := range descendants {
+			ids = append(ids, d.Info.ID)
+		}
+		prompt = fmt.Sprintf(deleteChainPromptFmt, bak.Info.ID, bak.Info.CSN, strings.Join(ids, ", "))
+	}
 	err = promptutil.GetUserApproveInTerminal(prompt)
 	if err != nil {
 		return xerr.NewCliErr(fmt.Sprintf("%s", err))
 	}
 
+	for _, b := range append(descendants, bak) {
+		if err := deleteBackup(ls, b); err != nil {
+			return err
+		}
+	}
+
+	logging.Info("Delete success!")
+	return nil
+}
+
+// deleteBackup deletes the backup data on every data node, then the backup record
+func deleteBackup(ls pkg.ILocalStorage, bak *model.LsBackup) error {
 	// mark the target backup record to be deleted
 	// meanwhile this record cannot be restored
 	if err := ls.HideByName(bak.Info.FileName); err != nil {
-		return xerr.NewCliErr("cannot mark backup record.")
+		return xerr.NewCliErr(fmt.Sprintf("cannot mark backup record. err: %s", err))
 	}
 
 	// exec delete
@@ -130,8 +161,6 @@ func deleteRecord() error {
 	if err := ls.DeleteByHidedName(bak.Info.FileName); err != nil {
 		return xerr.NewCliErr(fmt.Sprintf("exec delete backup record failed. err: %s", err))
 	}
-
-	logging.Info("Delete success!")
 	return nil
 }
 
@@ -144,7 +173,7 @@ func _execDelete(lsBackup *model.LsBackup) error {
 		deleteFinalStatus = "Completed"
 	)
 	for _, dn := range lsBackup.DnList {
-		dataNodeMap[dn.IP] = dn
+		dataNodeMap[nodeKey(dn.IP, dn.Port)] = dn
 	}
 
 	if totalNum == 0 {
@@ -160,11 +189,11 @@ func _execDelete(lsBackup *model.LsBackup) error {
 
 	for _, storagenode := range lsBackup.SsBackup.StorageNodes {
 		sn := storagenode
-		if dn, ok := dataNodeMap[sn.IP]; !ok {
+		if dn, ok := dataNodeMap[nodeKey(sn.IP, sn.Port)]; !ok {
 			logging.Warn(fmt.Sprintf("SKIPPED! data node %s:%d not found in backup info.", sn.IP, sn.Port))
 			continue
 		} else {
-			as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), AgentPort))
+			as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
 			backupInfo := &model.BackupInfo{
 				ID: dn.BackupID,
 			}
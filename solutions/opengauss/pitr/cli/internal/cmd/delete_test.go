@@ -93,12 +93,40 @@ var _ = Describe("test delete", func() {
 
 		RecordID = "backup-id"
 		as.EXPECT().CheckStatus(gomock.Any()).Return(nil)
+		ls.EXPECT().ReadAll().Return([]*model.LsBackup{bak}, nil)
 		ls.EXPECT().HideByName(bak.Info.FileName).Return(nil)
 		as.EXPECT().DeleteBackup(gomock.Any()).Return(nil)
 		ls.EXPECT().DeleteByHidedName(bak.Info.FileName).Return(nil)
 		Expect(deleteRecord()).To(BeNil())
 	})
 
+	It("delete the ptrack records depending on the backup", func() {
+		bak := &model.LsBackup{
+			Info:     bak.Info,
+			DnList:   []*model.DataNode{{IP: "127.0.0.1", BackupID: "dn-1"}},
+			SsBackup: bak.SsBackup,
+		}
+		child := &model.LsBackup{
+			Info:     &model.BackupMetaInfo{ID: "backup-id-2", FileName: "backup-2", BackupMode: model.DBBackModePTrack},
+			DnList:   []*model.DataNode{{IP: "127.0.0.1", BackupID: "dn-2", BackupMode: model.DBBackModePTrack, ParentBackupID: "dn-1"}},
+			SsBackup: bak.SsBackup,
+		}
+		monkey.PatchInstanceMethod(reflect.TypeOf(ls), "ReadByID", func(_ *mock_pkg.MockILocalStorage, _ string) (*model.LsBackup, error) { return bak, nil })
+		monkey.Patch(pkg.NewAgentServer, func(_ string) pkg.IAgentServer { return as })
+
+		RecordID = "backup-id"
+		as.EXPECT().CheckStatus(gomock.Any()).Return(nil)
+		ls.EXPECT().ReadAll().Return([]*model.LsBackup{bak, child}, nil)
+		gomock.InOrder(
+			ls.EXPECT().HideByName(child.Info.FileName).Return(nil),
+			ls.EXPECT().DeleteByHidedName(child.Info.FileName).Return(nil),
+			ls.EXPECT().HideByName(bak.Info.FileName).Return(nil),
+			ls.EXPECT().DeleteByHidedName(bak.Info.FileName).Return(nil),
+		)
+		as.EXPECT().DeleteBackup(gomock.Any()).Return(nil).Times(2)
+		Expect(deleteRecord()).To(BeNil())
+	})
+
 	Context("test exec delete", func() {
 		It("should be success", func() {
 			ctrl := gomock.NewController(GinkgoT())
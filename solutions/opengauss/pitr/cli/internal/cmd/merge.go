@@ -0,0 +1,260 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/promptutil"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/spf13/cobra"
+)
+
+var MergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Merge a PTRACK backup record with the records it depends on into a FULL one",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Flags().VisitAll(printFlag)
+
+		if CSN == "" && RecordID == "" {
+			logging.Error("Please specify csn or record id")
+			return
+		}
+
+		if CSN != "" && RecordID != "" {
+			logging.Error("Please specify only one of csn and record id")
+			return
+		}
+
+		if err := mergeRecord(); err != nil {
+			logging.Error(err.Error())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(MergeCmd)
+
+	MergeCmd.Flags().StringVarP(&Host, "host", "H", "", "ss-proxy hostname or ip")
+	_ = MergeCmd.MarkFlagRequired("host")
+	MergeCmd.Flags().StringVarP(&BackupPath, "dn-backup-path", "B", "", "openGauss data backup path")
+	_ = MergeCmd.MarkFlagRequired("dn-backup-path")
+	MergeCmd.Flags().Uint8VarP(&ThreadsNum, "dn-threads-num", "j", 1, "openGauss data merge threads nums")
+	MergeCmd.Flags().Uint16VarP(&AgentPort, "agent-port", "a", 443, "agent server port")
+	_ = MergeCmd.MarkFlagRequired("agent-port")
+	MergeCmd.Flags().StringVarP(&AgentCA, "agent-ca", "", "", "CA file to verify the agent servers (default not verified)")
+	MergeCmd.Flags().StringVarP(&AgentCert, "agent-cert", "", "", "client certificate file for the agent servers requiring mutual TLS")
+	MergeCmd.Flags().StringVarP(&AgentKey, "agent-key", "", "", "key file of the agent client certificate")
+	MergeCmd.Flags().StringVarP(&AgentToken, "agent-token", "", "", "bearer token for the agent servers (default env GS_PITR_AGENT_TOKEN)")
+
+	MergeCmd.Flags().StringVarP(&CSN, "csn", "", "", "commit sequence number")
+	MergeCmd.Flags().StringVarP(&RecordID, "id", "", "", "backup record id")
+	MergeCmd.Flags().StringVarP(&Catalog, "catalog", "", "", "backup catalog url, e.g. file:///home/omm/.gs_pitr or s3://bucket/prefix?endpoint=http://127.0.0.1:9000 (default local catalog)")
+}
+
+const (
+	mergePromptFmt = "The backup record(ID: %s) will be merged with the records it depends on(ID: %s) into a FULL backup record,\n" +
+		"and they will be deleted after merged.\n" +
+		"Are you sure to continue? (Y/N)"
+)
+
+/*
+mergeRecord merges the PTRACK backup record with its chain into a FULL backup record:
+
+	the backup of every data node is merged by `gs_probackup merge` on its agent server,
+	then the record turns FULL, and the records it depended on are deleted since their backups are merged.
+*/
+func mergeRecord() error {
+	ls, err := pkg.NewCatalog(Catalog)
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("new backup catalog failed. err: %s", err))
+	}
+
+	baks, err := validate(ls, CSN, RecordID)
+	if err != nil {
+		return err
+	}
+	bak := baks[0]
+	if bak.Info.BackupMode != model.DBBackModePTrack || bak.SsBackup == nil || bak.SsBackup.Status != model.SsBackupStatusCompleted {
+		return xerr.NewCliErr(fmt.Sprintf("backup record(ID: %s) is not a completed PTRACK record.", bak.Info.ID))
+	}
+
+	all, err := ls.ReadAll()
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("read backup records failed. err: %s", err))
+	}
+	parents := backupParents(all)
+	chain, err := backupChain(bak, parents)
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("backup record(ID: %s) can not be merged. err: %s", bak.Info.ID, err))
+	}
+	ancestors := chain[:len(chain)-1]
+	if err := checkMergeable(all, parents, chain); err != nil {
+		return err
+	}
+	logging.Info(fmt.Sprintf("Merging the chain: %s", chainString(chain)))
+
+	logging.Info("Checking agent server status...")
+	if available := checkAgentServerStatus(bak); !available {
+		return xerr.NewCliErr("one or more agent server are not available.")
+	}
+
+	ids := make([]string, 0, len(ancestors))
+	for _, a := range ancestors {
+		ids = append(ids, a.Info.ID)
+	}
+	if err := promptutil.GetUserApproveInTerminal(fmt.Sprintf(mergePromptFmt, bak.Info.ID, strings.Join(ids, ", "))); err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("%s", err))
+	}
+
+	logging.Info("Start merge backup data on openGauss...")
+	merged, mergeErr := execMerge(bak)
+	if err := updateChildren(ls, all, parents, bak, merged); err != nil {
+		return err
+	}
+	if mergeErr != nil {
+		// the merged data nodes are saved, the record could be merged again
+		if err := ls.WriteByJSON(bak.Info.FileName, bak); err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("update backup record failed. err: %s", err))
+		}
+		return xerr.NewCliErr(fmt.Sprintf("exec merge failed. err: %s", mergeErr))
+	}
+
+	bak.Info.BackupMode = model.DBBackModeFull
+	if err := ls.WriteByJSON(bak.Info.FileName, bak); err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("update backup record failed. err: %s", err))
+	}
+
+	// the backups of the ancestors are merged, only the records are deleted
+	for _, a := range ancestors {
+		if err := ls.HideByName(a.Info.FileName); err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("cannot mark backup record. err: %s", err))
+		}
+		if err := ls.DeleteByHidedName(a.Info.FileName); err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("exec delete backup record failed. err: %s", err))
+		}
+	}
+
+	logging.Info("Merge success!")
+	return nil
+}
+
+// checkMergeable checks no other record depends on the ancestors of the backup, which would lose their parents after merged
+func checkMergeable(all []*model.LsBackup, parents map[string][]*model.LsBackup, chain []*model.LsBackup) error {
+	inChain := make(map[string]struct{}, len(chain))
+	for _, b := range chain {
+		inChain[b.Info.ID] = struct{}{}
+	}
+	for _, b := range all {
+		if _, ok := inChain[b.Info.ID]; ok {
+			continue
+		}
+		for _, p := range parents[b.Info.ID] {
+			if _, ok := inChain[p.Info.ID]; ok && p.Info.ID != chain[len(chain)-1].Info.ID {
+				return xerr.NewCliErr(fmt.Sprintf("backup record(ID: %s) depends on backup record(ID: %s) of the chain, merge or delete it first.", b.Info.ID, p.Info.ID))
+			}
+		}
+	}
+	return nil
+}
+
+// execMerge merges the backups of all the data nodes, it returns the new backup ids of the merged data nodes by the ip:port and the old ids
+func execMerge(bak *model.LsBackup) (map[string]string, error) {
+	var (
+		g         = new(errgroup.Group)
+		dataNodes = make(map[string]*model.DataNode)
+		merged    = make(chan [2]string, len(bak.DnList))
+	)
+	for _, dn := range bak.DnList {
+		dataNodes[nodeKey(dn.IP, dn.Port)] = dn
+	}
+
+	for _, node := range bak.SsBackup.StorageNodes {
+		sn := node
+		dn, ok := dataNodes[nodeKey(sn.IP, sn.Port)]
+		// the data node fell back to a FULL backup
+		if !ok || dn.BackupMode == model.DBBackModeFull {
+			continue
+		}
+
+		g.Go(func() error {
+			as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
+			in := &model.MergeIn{
+				DBPort:       sn.Port,
+				DBName:       sn.Database,
+				Username:     sn.Username,
+				Password:     sn.Password,
+				DnBackupID:   dn.BackupID,
+				DnBackupPath: BackupPath,
+				DnThreadsNum: ThreadsNum,
+				Instance:     defaultInstance,
+			}
+			out, err := as.Merge(in)
+			if err != nil {
+				return fmt.Errorf("data node %s merge error: %s", nodeKey(sn.IP, sn.Port), err)
+			}
+
+			merged <- [2]string{backupKey(dn.IP, dn.Port, dn.BackupID), out.ID}
+			dn.BackupID = out.ID
+			dn.BackupMode = model.DBBackModeFull
+			dn.ParentBackupID = ""
+			dn.StartLsn = out.StartLsn
+			dn.StopLsn = out.StopLsn
+			dn.ContentCrc = out.ContentCrc
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	close(merged)
+	ids := make(map[string]string)
+	for m := range merged {
+		ids[m[0]] = m[1]
+	}
+	return ids, err
+}
+
+// updateChildren updates the parent backup ids of the data nodes of the records depending on the merged backup
+func updateChildren(ls pkg.ILocalStorage, all []*model.LsBackup, parents map[string][]*model.LsBackup, bak *model.LsBackup, merged map[string]string) error {
+	for _, b := range all {
+		if !containsBackup(parents[b.Info.ID], bak) {
+			continue
+		}
+
+		changed := false
+		for _, dn := range b.DnList {
+			if id, ok := merged[backupKey(dn.IP, dn.Port, dn.ParentBackupID)]; ok && id != dn.ParentBackupID {
+				dn.ParentBackupID = id
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := ls.WriteByJSON(b.Info.FileName, b); err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("update backup record(ID: %s) failed. err: %s", b.Info.ID, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/promptutil"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/timeutil"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/spf13/cobra"
+)
+
+var PruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete the backup records not retained by the retention policy",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Flags().VisitAll(printFlag)
+
+		if err := prune(); err != nil {
+			logging.Error(err.Error())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(PruneCmd)
+
+	PruneCmd.Flags().StringVarP(&Host, "host", "H", "", "ss-proxy hostname or ip, only to reach the agent servers of the storage nodes at 127.0.0.1")
+	PruneCmd.Flags().StringVarP(&BackupPath, "dn-backup-path", "B", "", "openGauss data backup path")
+	_ = PruneCmd.MarkFlagRequired("dn-backup-path")
+	PruneCmd.Flags().Uint16VarP(&AgentPort, "agent-port", "a", 443, "agent server port")
+	PruneCmd.Flags().StringVarP(&AgentCA, "agent-ca", "", "", "CA file to verify the agent servers (default not verified)")
+	PruneCmd.Flags().StringVarP(&AgentCert, "agent-cert", "", "", "client certificate file for the agent servers requiring mutual TLS")
+	PruneCmd.Flags().StringVarP(&AgentKey, "agent-key", "", "", "key file of the agent client certificate")
+	PruneCmd.Flags().StringVarP(&AgentToken, "agent-token", "", "", "bearer token for the agent servers (default env GS_PITR_AGENT_TOKEN)")
+
+	PruneCmd.Flags().BoolVarP(&DryRun, "dry-run", "", false, "print the plan only, nothing is deleted")
+	PruneCmd.Flags().StringVarP(&Catalog, "catalog", "", "", "backup catalog url, e.g. file:///home/omm/.gs_pitr or s3://bucket/prefix?endpoint=http://127.0.0.1:9000 (default local catalog)")
+}
+
+const (
+	prunePromptFmt = "%d backup records will be deleted forever.\n" +
+		"Are you sure to continue? (Y/N)"
+)
+
+// pruneItem is a backup record in the prune plan, it is kept if there is any reason
+type pruneItem struct {
+	bak       *model.LsBackup
+	startTime time.Time
+	reasons   []string
+	// pinned records are kept regardless of the retention policy
+	pinned bool
+}
+
+func (it *pruneItem) keep(reason string) {
+	for _, r := range it.reasons {
+		if r == reason {
+			return
+		}
+	}
+	it.reasons = append(it.reasons, reason)
+}
+
+func (it *pruneItem) kept() bool {
+	return len(it.reasons) > 0
+}
+
+func prune() error {
+	ls, err := pkg.NewCatalog(Catalog)
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("new backup catalog failed. err: %s", err))
+	}
+
+	r, err := ls.ReadRetention()
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("read retention policy failed. err: %s", err))
+	}
+	if r.Empty() {
+		return xerr.NewCliErr("no retention policy, please set it by `gs_pitr retention`.")
+	}
+
+	baks, err := ls.ReadAll()
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("read backup records failed. err: %s", err))
+	}
+
+	plan, err := planPrune(baks, r, time.Now())
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("invalid retention policy. err: %s", err))
+	}
+	printPrunePlan(plan)
+
+	pruned := make([]*model.LsBackup, 0, len(plan))
+	for _, it := range plan {
+		if !it.kept() {
+			pruned = append(pruned, it.bak)
+		}
+	}
+
+	if len(pruned) == 0 {
+		logging.Info("Nothing to prune.")
+		return nil
+	}
+	if DryRun {
+		logging.Info(fmt.Sprintf("Dry run, %d backup records would be deleted.", len(pruned)))
+		return nil
+	}
+
+	if err := promptutil.GetUserApproveInTerminal(fmt.Sprintf(prunePromptFmt, len(pruned))); err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("%s", err))
+	}
+
+	// the newest record first, gs_probackup deletes the incremental backups with their parent
+	for _, bak := range pruned {
+		logging.Info(fmt.Sprintf("Pruning backup record(ID: %s, CSN: %s)...", bak.Info.ID, bak.Info.CSN))
+		if available := checkAgentServerStatus(bak); !available {
+			return xerr.NewCliErr("one or more agent server are not available.")
+		}
+		if err := deleteBackup(ls, bak); err != nil {
+			return err
+		}
+	}
+
+	logging.Info("Prune success!")
+	return nil
+}
+
+/*
+planPrune returns the backup records from the newest to the oldest with the reasons to keep them:
+
+	the completed records are kept by the rules of the retention policy,
+	the records not completed are always kept, they could be deleted by `delete`,
+	the FULL and PTRACK records which a kept PTRACK record depends on are kept.
+*/
+func planPrune(baks []*model.LsBackup, r *model.Retention, now time.Time) ([]*pruneItem, error) {
+	within, err := r.Within()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := make([]*pruneItem, 0, len(baks))
+	byID := make(map[string]*pruneItem, len(baks))
+	for _, bak := range baks {
+		it := &pruneItem{bak: bak}
+		plan = append(plan, it)
+		byID[bak.Info.ID] = it
+
+		t, err := time.ParseInLocation(timeutil.UnifiedTimeLayout, bak.Info.StartTime, time.Local)
+		if err != nil {
+			it.keep("invalid start time")
+			it.pinned = true
+			continue
+		}
+		it.startTime = t
+		if bak.SsBackup == nil || bak.SsBackup.Status != model.SsBackupStatusCompleted {
+			it.keep("not completed")
+			it.pinned = true
+		}
+	}
+	sort.SliceStable(plan, func(i, j int) bool {
+		return plan[i].startTime.After(plan[j].startTime)
+	})
+
+	var (
+		last    int
+		buckets = []struct {
+			name  string
+			n     int
+			key   func(t time.Time) string
+			count int
+			prev  string
+		}{
+			{name: "keep-daily", n: r.KeepDaily, key: func(t time.Time) string { return t.Format("2006-01-02") }},
+			{name: "keep-weekly", n: r.KeepWeekly, key: func(t time.Time) string {
+				y, w := t.ISOWeek()
+				return fmt.Sprintf("%d-%d", y, w)
+			}},
+			{name: "keep-monthly", n: r.KeepMonthly, key: func(t time.Time) string { return t.Format("2006-01") }},
+		}
+	)
+	for _, it := range plan {
+		if it.pinned {
+			continue
+		}
+
+		if last < r.KeepLast {
+			it.keep("keep-last")
+		}
+		last++
+
+		if within > 0 && now.Sub(it.startTime) <= within {
+			it.keep("keep-within")
+		}
+
+		for i := range buckets {
+			b := &buckets[i]
+			if key := b.key(it.startTime); key != b.prev && b.count < b.n {
+				it.keep(b.name)
+				b.count++
+				b.prev = key
+			}
+		}
+	}
+
+	// from the newest to the oldest, the records holding the parent backups of every data node of a kept PTRACK record are kept
+	var (
+		parents   = backupParents(baks)
+		protected = map[string]struct{}{}
+	)
+	for _, it := range plan {
+		if _, ok := protected[it.bak.Info.ID]; ok || !it.kept() || it.bak.Info.BackupMode != model.DBBackModePTrack {
+			continue
+		}
+		chain, _ := backupChain(it.bak, parents)
+		for _, parent := range chain[:len(chain)-1] {
+			protected[parent.Info.ID] = struct{}{}
+			if p := byID[parent.Info.ID]; p != nil {
+				p.keep(fmt.Sprintf("parent of %s", it.bak.Info.ID))
+			}
+		}
+	}
+	return plan, nil
+}
+
+func printPrunePlan(plan []*pruneItem) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetTitle("Prune Plan")
+	t.AppendHeader(table.Row{"#", "ID", "CSN", "Mode", "Start Time", "Status", "Action", "Reason"})
+
+	for i, it := range plan {
+		var status model.BackupStatus
+		if it.bak.SsBackup != nil {
+			status = it.bak.SsBackup.Status
+		}
+		action := "Prune"
+		if it.kept() {
+			action = "Keep"
+		}
+		t.AppendRow([]interface{}{i + 1, it.bak.Info.ID, it.bak.Info.CSN, it.bak.Info.BackupMode, it.bak.Info.StartTime, status, action, strings.Join(it.reasons, ", ")})
+		t.AppendSeparator()
+	}
+	t.Render()
+}
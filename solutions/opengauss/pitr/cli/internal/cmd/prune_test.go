@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
+	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/mocks"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/promptutil"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/timeutil"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("test prune", func() {
+	now := time.Date(2023, 3, 31, 12, 0, 0, 0, time.Local)
+
+	newBackup := func(id string, mode model.DBBackupMode, status model.BackupStatus, ago time.Duration) *model.LsBackup {
+		return &model.LsBackup{
+			Info: &model.BackupMetaInfo{
+				ID:         id,
+				BackupMode: mode,
+				StartTime:  timeutil.UnifiedTimeFormat(now.Add(-ago)),
+				FileName:   fmt.Sprintf("%s.json", id),
+			},
+			DnList: []*model.DataNode{{IP: "127.0.0.1", Port: 5432, BackupID: id}},
+			SsBackup: &model.SsBackup{
+				Status:       status,
+				StorageNodes: []*model.StorageNode{{IP: "127.0.0.1", Port: 5432}},
+			},
+		}
+	}
+
+	actions := func(plan []*pruneItem) map[string]bool {
+		m := map[string]bool{}
+		for _, it := range plan {
+			m[it.bak.Info.ID] = it.kept()
+		}
+		return m
+	}
+
+	Context("plan", func() {
+		day := 24 * time.Hour
+
+		It("keep last and within", func() {
+			baks := []*model.LsBackup{
+				newBackup("b1", model.DBBackModeFull, model.SsBackupStatusCompleted, 3*day),
+				newBackup("b2", model.DBBackModeFull, model.SsBackupStatusCompleted, 2*day),
+				newBackup("b3", model.DBBackModeFull, model.SsBackupStatusCompleted, day),
+				newBackup("b4", model.DBBackModeFull, model.SsBackupStatusFailed, time.Hour),
+			}
+			plan, err := planPrune(baks, &model.Retention{KeepLast: 1, KeepWithin: "2d"}, now)
+			Expect(err).To(BeNil())
+			Expect(plan[0].bak.Info.ID).To(Equal("b4"))
+			Expect(plan[0].reasons).To(Equal([]string{"not completed"}))
+			Expect(plan[1].reasons).To(Equal([]string{"keep-last", "keep-within"}))
+			Expect(actions(plan)).To(Equal(map[string]bool{"b1": false, "b2": true, "b3": true, "b4": true}))
+		})
+
+		It("keep daily and monthly", func() {
+			baks := []*model.LsBackup{
+				newBackup("b1", model.DBBackModeFull, model.SsBackupStatusCompleted, 40*day),
+				newBackup("b2", model.DBBackModeFull, model.SsBackupStatusCompleted, day+time.Hour),
+				newBackup("b3", model.DBBackModeFull, model.SsBackupStatusCompleted, day),
+				newBackup("b4", model.DBBackModeFull, model.SsBackupStatusCompleted, 2*time.Hour),
+				newBackup("b5", model.DBBackModeFull, model.SsBackupStatusCompleted, time.Hour),
+			}
+			plan, err := planPrune(baks, &model.Retention{KeepDaily: 2, KeepMonthly: 2}, now)
+			Expect(err).To(BeNil())
+			Expect(actions(plan)).To(Equal(map[string]bool{"b1": true, "b2": false, "b3": true, "b4": false, "b5": true}))
+		})
+
+		It("keep the parents of the retained ptrack backups", func() {
+			baks := []*model.LsBackup{
+				newBackup("f1", model.DBBackModeFull, model.SsBackupStatusCompleted, 5*day),
+				newBackup("p1", model.DBBackModePTrack, model.SsBackupStatusCompleted, 4*day),
+				newBackup("f2", model.DBBackModeFull, model.SsBackupStatusCompleted, 3*day),
+				newBackup("p2", model.DBBackModePTrack, model.SsBackupStatusCompleted, 2*day),
+				newBackup("p3", model.DBBackModePTrack, model.SsBackupStatusCompleted, day),
+			}
+			plan, err := planPrune(baks, &model.Retention{KeepLast: 1}, now)
+			Expect(err).To(BeNil())
+			Expect(actions(plan)).To(Equal(map[string]bool{"f1": false, "p1": false, "f2": true, "p2": true, "p3": true}))
+			Expect(plan[2].reasons).To(Equal([]string{"parent of p3"}))
+		})
+
+		It("keep the parents of every data node in different records", func() {
+			withDataNode := func(bak *model.LsBackup, port uint16, parentID string) *model.LsBackup {
+				bak.DnList = []*model.DataNode{{IP: "127.0.0.1", Port: port, BackupID: bak.Info.ID, BackupMode: bak.Info.BackupMode, ParentBackupID: parentID}}
+				return bak
+			}
+			p1 := withDataNode(newBackup("p1", model.DBBackModePTrack, model.SsBackupStatusCompleted, day), 5432, "f1")
+			p1.DnList = append(p1.DnList, &model.DataNode{IP: "127.0.0.1", Port: 5433, BackupID: "p1", BackupMode: model.DBBackModePTrack, ParentBackupID: "f2"})
+			baks := []*model.LsBackup{
+				withDataNode(newBackup("f1", model.DBBackModeFull, model.SsBackupStatusCompleted, 4*day), 5432, ""),
+				withDataNode(newBackup("f2", model.DBBackModeFull, model.SsBackupStatusCompleted, 3*day), 5433, ""),
+				withDataNode(newBackup("f3", model.DBBackModeFull, model.SsBackupStatusCompleted, 2*day), 5434, ""),
+				p1,
+			}
+			plan, err := planPrune(baks, &model.Retention{KeepLast: 1}, now)
+			Expect(err).To(BeNil())
+			Expect(actions(plan)).To(Equal(map[string]bool{"f1": true, "f2": true, "f3": false, "p1": true}))
+			Expect(plan[2].reasons).To(Equal([]string{"parent of p1"}))
+			Expect(plan[3].reasons).To(Equal([]string{"parent of p1"}))
+		})
+
+		It("invalid policy", func() {
+			_, err := planPrune(nil, &model.Retention{KeepWithin: "1y"}, now)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Context("prune", func() {
+		var (
+			ls *mock_pkg.MockILocalStorage
+			as *mock_pkg.MockIAgentServer
+		)
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			ls = mock_pkg.NewMockILocalStorage(ctrl)
+			as = mock_pkg.NewMockIAgentServer(ctrl)
+			monkey.Patch(pkg.NewLocalStorage, func(rootDir string) (pkg.ILocalStorage, error) {
+				return ls, nil
+			})
+			monkey.Patch(pkg.NewAgentServer, func(_ string) pkg.IAgentServer {
+				return as
+			})
+			monkey.Patch(promptutil.GetUserApproveInTerminal, func(_ string) error {
+				return nil
+			})
+			DryRun = false
+		})
+		AfterEach(func() {
+			DryRun = false
+			ctrl.Finish()
+			monkey.UnpatchAll()
+		})
+
+		It("no retention policy", func() {
+			ls.EXPECT().ReadRetention().Return(&model.Retention{}, nil)
+			Expect(prune()).NotTo(BeNil())
+		})
+
+		It("dry run", func() {
+			DryRun = true
+			ls.EXPECT().ReadRetention().Return(&model.Retention{KeepLast: 1}, nil)
+			ls.EXPECT().ReadAll().Return([]*model.LsBackup{
+				newBackup("b1", model.DBBackModeFull, model.SsBackupStatusCompleted, time.Hour),
+				newBackup("b2", model.DBBackModeFull, model.SsBackupStatusCompleted, 0),
+			}, nil)
+			Expect(prune()).To(BeNil())
+		})
+
+		It("delete the backups not retained", func() {
+			ls.EXPECT().ReadRetention().Return(&model.Retention{KeepLast: 1}, nil)
+			ls.EXPECT().ReadAll().Return([]*model.LsBackup{
+				newBackup("b1", model.DBBackModeFull, model.SsBackupStatusCompleted, time.Hour),
+				newBackup("b2", model.DBBackModeFull, model.SsBackupStatusCompleted, 0),
+			}, nil)
+			as.EXPECT().CheckStatus(gomock.Any()).Return(nil)
+			ls.EXPECT().HideByName("b1.json").Return(nil)
+			as.EXPECT().DeleteBackup(&model.DeleteBackupIn{DBPort: 5432, BackupID: "b1", Instance: defaultInstance, DnBackupPath: BackupPath}).Return(nil)
+			ls.EXPECT().DeleteByHidedName("b1.json").Return(nil)
+			Expect(prune()).To(BeNil())
+		})
+	})
+})
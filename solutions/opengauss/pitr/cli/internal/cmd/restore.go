@@ -20,7 +20,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
@@ -28,16 +31,18 @@ import (
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/prettyoutput"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/promptutil"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/timeutil"
 
 	"github.com/jedib0t/go-pretty/v6/progress"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 )
 
 var (
 	// database names exist in ss proxy and backup, will to be dropped
 	databaseNamesExist []string
+	// the WAL is replayed to the target after the backup data is restored, nil if restoring to the backup
+	restoreTarget *model.RecoveryTarget
 )
 
 //nolint:dupl
@@ -45,17 +50,22 @@ var RestoreCmd = &cobra.Command{
 	Use:   "restore",
 	Short: "Restore a database cluster ",
 	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
-			fmt.Printf("Flag: %s Value: %s\n", flag.Name, flag.Value)
-		})
+		cmd.Flags().VisitAll(printFlag)
+
+		specified := 0
+		for _, v := range []string{CSN, RecordID, TargetTime, TargetCSN, TargetLSN} {
+			if v != "" {
+				specified++
+			}
+		}
 
-		if CSN == "" && RecordID == "" {
-			logging.Error("Please specify csn or record id")
+		if specified == 0 {
+			logging.Error("Please specify csn, record id or recovery target")
 			return
 		}
 
-		if CSN != "" && RecordID != "" {
-			logging.Error("Please specify only one of csn and record id")
+		if specified > 1 {
+			logging.Error("Please specify only one of csn, record id, target time, target csn and target lsn")
 			return
 		}
 
@@ -79,11 +89,21 @@ func init() {
 	RestoreCmd.Flags().StringVarP(&BackupPath, "dn-backup-path", "B", "", "openGauss data backup path")
 	_ = RestoreCmd.MarkFlagRequired("dn-backup-path")
 	RestoreCmd.Flags().Uint16VarP(&AgentPort, "agent-port", "a", 443, "agent server port")
+	RestoreCmd.Flags().StringVarP(&AgentCA, "agent-ca", "", "", "CA file to verify the agent servers (default not verified)")
+	RestoreCmd.Flags().StringVarP(&AgentCert, "agent-cert", "", "", "client certificate file for the agent servers requiring mutual TLS")
+	RestoreCmd.Flags().StringVarP(&AgentKey, "agent-key", "", "", "key file of the agent client certificate")
+	RestoreCmd.Flags().StringVarP(&AgentToken, "agent-token", "", "", "bearer token for the agent servers (default env GS_PITR_AGENT_TOKEN)")
 	_ = RestoreCmd.MarkFlagRequired("agent-port")
 
 	RestoreCmd.Flags().Uint8VarP(&ThreadsNum, "dn-threads-num", "j", 1, "openGauss data restore threads nums")
 	RestoreCmd.Flags().StringVarP(&CSN, "csn", "", "", "commit sequence number")
 	RestoreCmd.Flags().StringVarP(&RecordID, "id", "", "", "backup record id")
+	RestoreCmd.Flags().StringVarP(&TargetTime, "target-time", "", "", "restore to the time in local time zone, e.g. \"2023-03-01 12:00:00\", only for one data node")
+	RestoreCmd.Flags().StringVarP(&TargetCSN, "target-csn", "", "", "restore to the commit sequence number")
+	RestoreCmd.Flags().StringVarP(&TargetLSN, "target-lsn", "", "", "restore to the log sequence number, e.g. 0/3000028, only for one data node")
+	RestoreCmd.Flags().StringVarP(&LogicDatabase, "database", "", "", "restore the logic database only, the other logic databases keep online")
+	RestoreCmd.Flags().StringVarP(&TargetMap, "target-map", "", "", "json file to remap the storage nodes of the backup to the ones of another cluster")
+	RestoreCmd.Flags().StringVarP(&Catalog, "catalog", "", "", "backup catalog url, e.g. file:///home/omm/.gs_pitr or s3://bucket/prefix?endpoint=http://127.0.0.1:9000 (default local catalog)")
 }
 
 const (
@@ -95,9 +115,9 @@ const (
 //nolint:dupl
 func restore() error {
 	// init local storage
-	ls, err := pkg.NewLocalStorage(pkg.DefaultRootDir())
+	ls, err := pkg.NewCatalog(Catalog)
 	if err != nil {
-		return xerr.NewCliErr(fmt.Sprintf("new local storage failed. err: %s", err.Error()))
+		return xerr.NewCliErr(fmt.Sprintf("new backup catalog failed. err: %s", err.Error()))
 	}
 	proxy, err := pkg.NewShardingSphereProxy(Username, Password, pkg.DefaultDBName, Host, Port)
 	if err != nil {
@@ -105,13 +125,28 @@ func restore() error {
 	}
 
 	// get backup record
-	var baks []*model.LsBackup
-	baks, err = validate(ls, CSN, RecordID)
+	bak, err := getRestoreBackup(ls)
 	if err != nil {
 		return err
 	}
+	if err := explainChain(ls, bak); err != nil {
+		return err
+	}
+
+	agentPorts = map[string]uint16{}
+	if TargetMap != "" {
+		targets, err := loadTargetMap(TargetMap)
+		if err != nil {
+			return err
+		}
+		if bak, err = remapBackup(bak, targets); err != nil {
+			return err
+		}
+	}
 
-	bak := baks[0]
+	if LogicDatabase != "" {
+		return restoreDatabase(proxy, bak, LogicDatabase)
+	}
 	// check if the backup logic database exits,
 	// if exits, we need to warning user that we will drop the database.
 	if err := checkDatabaseExist(proxy, bak); err != nil {
@@ -145,6 +180,254 @@ func restore() error {
 	return nil
 }
 
+/*
+restoreDatabase restores the logic database only, the other logic databases keep online:
+
+	the logic database is dropped from ss-proxy,
+	then the physical databases of it are restored by the agent servers without stopping openGauss,
+	and finally the metadata of it is imported to ss-proxy.
+*/
+func restoreDatabase(proxy pkg.IShardingSphereProxy, bak *model.LsBackup, name string) error {
+	if bak.SsBackup.LogicDatabases == nil {
+		return xerr.NewCliErr(fmt.Sprintf("backup record [%s] has no storage nodes of the logic databases, please restore the whole cluster.", bak.Info.ID))
+	}
+	nodes, ok := bak.SsBackup.LogicDatabases[name]
+	if !ok || len(nodes) == 0 {
+		return xerr.NewCliErr(fmt.Sprintf("logic database [%s] not found in backup record [%s].", name, bak.Info.ID))
+	}
+	config, ok := bak.SsBackup.ClusterInfo.MetaData.Databases[name]
+	if !ok {
+		return xerr.NewCliErr(fmt.Sprintf("metadata of logic database [%s] not found in backup record [%s].", name, bak.Info.ID))
+	}
+
+	current, err := proxy.ExportMetaData()
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("get cluster metadata failed. err: %s", err))
+	}
+	currentConfig, exists := current.MetaData.Databases[name]
+	if exists {
+		if err := promptutil.GetUserApproveInTerminal(fmt.Sprintf(restorePromptFmt, name)); err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("%s", err))
+		}
+	}
+
+	// the physical databases are restored, the agent servers are checked once for each storage node
+	sub := &model.LsBackup{
+		Info:   bak.Info,
+		DnList: bak.DnList,
+		SsBackup: &model.SsBackup{
+			Status:       bak.SsBackup.Status,
+			ClusterInfo:  bak.SsBackup.ClusterInfo,
+			StorageNodes: (&model.StorageNodesInfo{StorageNodes: map[string][]*model.StorageNode{name: nodes}}).Nodes(),
+		},
+	}
+	logging.Info("Checking agent server status...")
+	if available := checkAgentServerStatus(sub); !available {
+		return xerr.NewCliErr("one or more agent server are not available.")
+	}
+
+	importDatabase := func(config string) error {
+		return proxy.ImportMetaData(&model.ClusterInfo{
+			MetaData: model.MetaData{
+				Databases: map[string]string{name: config},
+				Props:     current.MetaData.Props,
+				Rules:     current.MetaData.Rules,
+			},
+		})
+	}
+
+	if exists {
+		logging.Info(fmt.Sprintf("Dropping database: [%s] ...", name))
+		if err := proxy.DropDatabase(name); err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("drop database failed. err: %s", err))
+		}
+	}
+
+	logging.Info(fmt.Sprintf("Start restore the physical databases of [%s] to openGauss...", name))
+	sub.SsBackup.StorageNodes = nodes
+	if err := execRestore(sub); err != nil {
+		// bring the logic database back online
+		if exists {
+			if importErr := importDatabase(currentConfig); importErr != nil {
+				return xerr.NewCliErr(fmt.Sprintf("exec restore failed. err: %s, import the metadata of database [%s] back failed. err: %s", err, name, importErr))
+			}
+		}
+		return xerr.NewCliErr(fmt.Sprintf("exec restore failed. err: %s", err))
+	}
+
+	if err := importDatabase(config); err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("Import metadata to ss-proxy failed. err: %s", err))
+	}
+	logging.Info("Restore success!")
+	return nil
+}
+
+func getRestoreBackup(ls pkg.ILocalStorage) (*model.LsBackup, error) {
+	restoreTarget = nil
+	if TargetTime == "" && TargetCSN == "" && TargetLSN == "" {
+		baks, err := validate(ls, CSN, RecordID)
+		if err != nil {
+			return nil, err
+		}
+		return baks[0], nil
+	}
+
+	target, err := newRecoveryTarget(TargetTime, TargetCSN, TargetLSN)
+	if err != nil {
+		return nil, err
+	}
+
+	bak, err := pickBaseBackup(ls, target)
+	if err != nil {
+		return nil, err
+	}
+	restoreTarget = target.RecoveryTarget
+	return bak, nil
+}
+
+// recoveryTarget is the parsed model.RecoveryTarget
+type recoveryTarget struct {
+	*model.RecoveryTarget
+	time time.Time
+	csn  uint64
+	lsn  uint64
+}
+
+func newRecoveryTarget(targetTime, targetCSN, targetLSN string) (*recoveryTarget, error) {
+	var err error
+	target := &recoveryTarget{RecoveryTarget: &model.RecoveryTarget{}}
+	switch {
+	case targetTime != "":
+		target.time, err = time.ParseInLocation(timeutil.UnifiedTimeLayout, targetTime, time.Local)
+		if err != nil {
+			return nil, xerr.NewCliErr(fmt.Sprintf("invalid target time: %s, the format is like \"2023-03-01 12:00:00\"", targetTime))
+		}
+		target.Time = target.time.Format(model.RecoveryTargetTimeLayout)
+	case targetCSN != "":
+		target.csn, err = strconv.ParseUint(targetCSN, 10, 64)
+		if err != nil {
+			return nil, xerr.NewCliErr(fmt.Sprintf("invalid target csn: %s", targetCSN))
+		}
+		target.CSN = targetCSN
+	case targetLSN != "":
+		target.lsn, err = parseLSN(targetLSN)
+		if err != nil {
+			return nil, xerr.NewCliErr(fmt.Sprintf("invalid target lsn: %s, the format is like 0/3000028", targetLSN))
+		}
+		target.LSN = targetLSN
+	}
+	return target, nil
+}
+
+// parseLSN parses the log sequence number like `0/3000028`
+func parseLSN(lsn string) (uint64, error) {
+	parts := strings.Split(lsn, "/")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid lsn: %s", lsn)
+	}
+	hi, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil {
+		return 0, err
+	}
+	lo, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return 0, err
+	}
+	return hi<<32 | lo, nil
+}
+
+// before returns true if the data node backup in the record is before the target, the WAL can be replayed from it to the target.
+// All the data nodes are checked if dn is nil.
+func (t *recoveryTarget) before(bak *model.LsBackup, dn *model.DataNode) bool {
+	if bak.Info == nil || bak.Info.EndTime == "" {
+		return false
+	}
+
+	switch {
+	case t.Time != "":
+		end, err := time.ParseInLocation(timeutil.UnifiedTimeLayout, bak.Info.EndTime, time.Local)
+		return err == nil && !end.After(t.time)
+	case t.CSN != "":
+		csn, err := strconv.ParseUint(bak.Info.CSN, 10, 64)
+		return err == nil && csn <= t.csn
+	case t.LSN != "":
+		if dn == nil {
+			return true
+		}
+		lsn, err := parseLSN(dn.StopLsn)
+		return err == nil && lsn <= t.lsn
+	default:
+		return false
+	}
+}
+
+/*
+pickBaseBackup picks the backups to restore to the target:
+
+	the metadata and storage nodes are restored from the latest backup before the target,
+	each data node is restored from its latest completed backup before the target, and then the WAL is replayed to the target.
+
+All the data nodes are replayed to the same target csn, which keeps them consistent with each other.
+The target time and lsn are local to each data node, so they only support the cluster with one data node.
+*/
+func pickBaseBackup(ls pkg.ILocalStorage, target *recoveryTarget) (*model.LsBackup, error) {
+	list, err := ls.ReadAll()
+	if err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("read backup records failed. err: %s", err))
+	}
+
+	// the latest first
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Info.EndTime > list[j].Info.EndTime
+	})
+
+	var base *model.LsBackup
+	for _, bak := range list {
+		if bak.SsBackup != nil && bak.SsBackup.ClusterInfo != nil && len(bak.SsBackup.StorageNodes) > 0 && target.before(bak, nil) {
+			base = bak
+			break
+		}
+	}
+	if base == nil {
+		return nil, xerr.NewCliErr("no backup record found before the recovery target")
+	}
+
+	if target.LSN != "" && len(base.SsBackup.StorageNodes) > 1 {
+		return nil, xerr.NewCliErr("the target lsn only supports one data node, please use the target csn")
+	}
+	// each data node would be replayed to the time on its own, so a transaction across the data nodes may be restored partially
+	if target.Time != "" && len(base.SsBackup.StorageNodes) > 1 {
+		return nil, xerr.NewCliErr("the target time only supports one data node, please use the target csn to keep the data nodes consistent")
+	}
+
+	dnList := make([]*model.DataNode, 0, len(base.SsBackup.StorageNodes))
+	for _, sn := range base.SsBackup.StorageNodes {
+		dn := pickDataNodeBackup(list, sn, target)
+		if dn == nil {
+			return nil, xerr.NewCliErr(fmt.Sprintf("no completed backup of data node %s:%d found before the recovery target", sn.IP, sn.Port))
+		}
+		logging.Info(fmt.Sprintf("Data node %s:%d will be restored from backup %s", sn.IP, sn.Port, dn.BackupID))
+		dnList = append(dnList, dn)
+	}
+
+	return &model.LsBackup{
+		Info:     base.Info,
+		DnList:   dnList,
+		SsBackup: base.SsBackup,
+	}, nil
+}
+
+func pickDataNodeBackup(list []*model.LsBackup, sn *model.StorageNode, target *recoveryTarget) *model.DataNode {
+	for _, bak := range list {
+		for _, dn := range bak.DnList {
+			if dn.IP == sn.IP && dn.Port == sn.Port && dn.Status == model.SsBackupStatusCompleted && target.before(bak, dn) {
+				return dn
+			}
+		}
+	}
+	return nil
+}
+
 func checkDatabaseExist(proxy pkg.IShardingSphereProxy, bak *model.LsBackup) error {
 	clusterNow, err := proxy.ExportMetaData()
 	if err != nil {
@@ -190,14 +473,28 @@ func execRestore(lsBackup *model.LsBackup) error {
 		restoreFinalStatus = "Completed"
 	)
 
+	// the backup of a data node is the backup of the whole openGauss instance on ip:port
 	for _, dataNode := range lsBackup.DnList {
-		dataNodeMap[dataNode.IP] = dataNode
+		dataNodeMap[nodeKey(dataNode.IP, dataNode.Port)] = dataNode
 	}
 
 	if totalNum == 0 {
 		return xerr.NewCliErr(fmt.Sprintf("no storage node found, please check backup record [%s].", lsBackup.Info.ID))
 	}
 
+	// every storage node is restored from the backup of its instance, a storage node without the backup fails the restore
+	restored := make(map[string]struct{}, totalNum)
+	for _, sn := range lsBackup.SsBackup.StorageNodes {
+		if _, ok := dataNodeMap[nodeKey(sn.IP, sn.Port)]; !ok {
+			return xerr.NewCliErr(fmt.Sprintf("no backup of storage node %s:%d found in backup record [%s].", sn.IP, sn.Port, lsBackup.Info.ID))
+		}
+		key := fmt.Sprintf("%s:%d/%s", sn.IP, sn.Port, sn.Database)
+		if _, ok := restored[key]; ok {
+			return xerr.NewCliErr(fmt.Sprintf("storage node %s is duplicated in backup record [%s].", key, lsBackup.Info.ID))
+		}
+		restored[key] = struct{}{}
+	}
+
 	pw := prettyoutput.NewProgressPrinter(prettyoutput.ProgressPrintOption{
 		NumTrackersExpected: totalNum,
 	})
@@ -205,8 +502,8 @@ func execRestore(lsBackup *model.LsBackup) error {
 	go pw.Render()
 	for i := 0; i < totalNum; i++ {
 		sn := lsBackup.SsBackup.StorageNodes[i]
-		dn := dataNodeMap[sn.IP]
-		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), AgentPort))
+		dn := dataNodeMap[nodeKey(sn.IP, sn.Port)]
+		as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
 		backupInfo := &model.BackupInfo{
 			ID: dn.BackupID,
 		}
@@ -219,7 +516,7 @@ func execRestore(lsBackup *model.LsBackup) error {
 		}
 
 		tracker := &progress.Tracker{
-			Message: fmt.Sprintf("Restore data to openGauss: %s", sn.IP),
+			Message: fmt.Sprintf("Restore data to openGauss: %s:%d/%s", sn.IP, sn.Port, sn.Database),
 		}
 		pw.AppendTracker(tracker)
 		go pw.UpdateProgress(tracker, task.checkProgress)
@@ -277,6 +574,8 @@ func (t *restoretask) checkProgress() (bool, error) {
 		DnBackupPath: BackupPath,
 		Instance:     defaultInstance,
 		DnThreadsNum: ThreadsNum,
+
+		RecoveryTarget: restoreTarget,
 	}
 
 	r := &model.RestoreResult{
@@ -284,7 +583,12 @@ func (t *restoretask) checkProgress() (bool, error) {
 		Port: t.Sn.Port,
 	}
 
-	if err = t.As.Restore(in); err != nil {
+	restoreFn := t.As.Restore
+	if LogicDatabase != "" {
+		restoreFn = t.As.RestoreDatabase
+	}
+
+	if err = restoreFn(in); err != nil {
 		r.Status = fmt.Sprintf("Failed: %s", err)
 		t.ResultCh <- r
 		return false, err
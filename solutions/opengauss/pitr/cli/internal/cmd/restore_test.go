@@ -18,6 +18,7 @@
 package cmd
 
 import (
+	"fmt"
 	"reflect"
 	"time"
 
@@ -167,4 +168,195 @@ var _ = Describe("test restore", func() {
 			Expect(execRestore(bak)).To(BeNil())
 		})
 	})
+
+	Context("restore to the recovery target", func() {
+		newBackup := func(id, csn, endTime string, dns ...*model.DataNode) *model.LsBackup {
+			return &model.LsBackup{
+				Info:   &model.BackupMetaInfo{ID: id, CSN: csn, EndTime: endTime},
+				DnList: dns,
+				SsBackup: &model.SsBackup{
+					Status:       model.SsBackupStatusCompleted,
+					ClusterInfo:  &model.ClusterInfo{},
+					StorageNodes: []*model.StorageNode{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+				},
+			}
+		}
+		completed := func(ip, backupID string) *model.DataNode {
+			return &model.DataNode{IP: ip, BackupID: backupID, Status: model.SsBackupStatusCompleted}
+		}
+
+		It("should parse the target", func() {
+			target, err := newRecoveryTarget("2023-03-01 12:00:00", "", "")
+			Expect(err).To(BeNil())
+			Expect(target.Time).To(Equal(target.time.Format(model.RecoveryTargetTimeLayout)))
+
+			target, err = newRecoveryTarget("", "", "1/3000028")
+			Expect(err).To(BeNil())
+			Expect(target.lsn).To(Equal(uint64(0x1_03000028)))
+
+			_, err = newRecoveryTarget("2023-03-01", "", "")
+			Expect(err).NotTo(BeNil())
+			_, err = newRecoveryTarget("", "csn", "")
+			Expect(err).NotTo(BeNil())
+			_, err = newRecoveryTarget("", "", "3000028")
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should pick the latest backups before the target for each data node", func() {
+			ls.EXPECT().ReadAll().Return([]*model.LsBackup{
+				newBackup("b1", "100", "2023-03-01 10:00:00", completed("10.0.0.1", "dn1-b1"), completed("10.0.0.2", "dn2-b1")),
+				newBackup("b3", "300", "2023-03-01 14:00:00", completed("10.0.0.1", "dn1-b3"), completed("10.0.0.2", "dn2-b3")),
+				newBackup("b2", "200", "2023-03-01 12:00:00", completed("10.0.0.1", "dn1-b2"),
+					&model.DataNode{IP: "10.0.0.2", BackupID: "dn2-b2", Status: model.SsBackupStatusFailed}),
+			}, nil).Times(2)
+
+			target, err := newRecoveryTarget("", "250", "")
+			Expect(err).To(BeNil())
+			bak, err := pickBaseBackup(ls, target)
+			Expect(err).To(BeNil())
+			Expect(bak.Info.ID).To(Equal("b2"))
+			Expect(bak.DnList).To(HaveLen(2))
+			Expect(bak.DnList[0].BackupID).To(Equal("dn1-b2"))
+			Expect(bak.DnList[1].BackupID).To(Equal("dn2-b1"))
+
+			target, err = newRecoveryTarget("2023-03-01 09:00:00", "", "")
+			Expect(err).To(BeNil())
+			_, err = pickBaseBackup(ls, target)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should only restore one data node to the target lsn or time", func() {
+			ls.EXPECT().ReadAll().Return([]*model.LsBackup{
+				newBackup("b1", "100", "2023-03-01 10:00:00", completed("10.0.0.1", "dn1-b1"), completed("10.0.0.2", "dn2-b1")),
+			}, nil).Times(2)
+
+			target, err := newRecoveryTarget("", "", "0/3000028")
+			Expect(err).To(BeNil())
+			_, err = pickBaseBackup(ls, target)
+			Expect(err).NotTo(BeNil())
+
+			target, err = newRecoveryTarget("2023-03-01 12:00:00", "", "")
+			Expect(err).To(BeNil())
+			_, err = pickBaseBackup(ls, target)
+			Expect(err).To(MatchError(ContainSubstring("the target time only supports one data node")))
+		})
+
+		It("should send the target to the agent server", func() {
+			monkey.Patch(pkg.NewAgentServer, func(_ string) pkg.IAgentServer { return as })
+			defer func() {
+				restoreTarget = nil
+			}()
+			restoreTarget = &model.RecoveryTarget{CSN: "250"}
+			as.EXPECT().Restore(gomock.Any()).Do(func(in *model.RestoreIn) {
+				Expect(in.CSN).To(Equal("250"))
+			}).Return(nil)
+			Expect(execRestore(bak)).To(BeNil())
+		})
+	})
+
+	Context("restore the logic database", func() {
+		var dbBak *model.LsBackup
+
+		BeforeEach(func() {
+			monkey.Patch(pkg.NewAgentServer, func(_ string) pkg.IAgentServer { return as })
+			LogicDatabase = "sharding_db"
+			dbBak = &model.LsBackup{
+				Info:   &model.BackupMetaInfo{ID: "backup-id-1"},
+				DnList: []*model.DataNode{{IP: "10.0.0.1", Port: 5432, BackupID: "dn1"}, {IP: "10.0.0.1", Port: 5433, BackupID: "dn2"}},
+				SsBackup: &model.SsBackup{
+					ClusterInfo: &model.ClusterInfo{
+						MetaData: model.MetaData{Databases: map[string]string{"sharding_db": "backup", "other_db": "backup"}},
+					},
+					StorageNodes: []*model.StorageNode{{IP: "10.0.0.1", Port: 5432, Database: "postgres"}},
+					LogicDatabases: map[string][]*model.StorageNode{
+						"sharding_db": {
+							{IP: "10.0.0.1", Port: 5432, Database: "ds_0"},
+							{IP: "10.0.0.1", Port: 5432, Database: "ds_1"},
+						},
+						"other_db": {{IP: "10.0.0.1", Port: 5432, Database: "ds_2"}},
+					},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			LogicDatabase = ""
+		})
+
+		It("should restore the physical databases and import the metadata of the logic database only", func() {
+			proxy.EXPECT().ExportMetaData().Return(&model.ClusterInfo{
+				MetaData: model.MetaData{Databases: map[string]string{"sharding_db": "current", "other_db": "current"}, Props: "props", Rules: "rules"},
+			}, nil)
+			as.EXPECT().CheckStatus(gomock.Any()).Return(nil)
+			proxy.EXPECT().DropDatabase("sharding_db").Return(nil)
+			restored := make(chan string, 2)
+			as.EXPECT().RestoreDatabase(gomock.Any()).Do(func(in *model.RestoreIn) {
+				Expect(in.DnBackupID).To(Equal("dn1"))
+				restored <- in.DBName
+			}).Return(nil).Times(2)
+			proxy.EXPECT().ImportMetaData(&model.ClusterInfo{
+				MetaData: model.MetaData{Databases: map[string]string{"sharding_db": "backup"}, Props: "props", Rules: "rules"},
+			}).Return(nil)
+
+			Expect(restoreDatabase(proxy, dbBak, "sharding_db")).To(BeNil())
+			close(restored)
+			dbs := []string{}
+			for db := range restored {
+				dbs = append(dbs, db)
+			}
+			Expect(dbs).To(ConsistOf("ds_0", "ds_1"))
+		})
+
+		It("should restore the physical databases of each instance on the same host from its backup", func() {
+			dbBak.SsBackup.LogicDatabases["sharding_db"] = append(dbBak.SsBackup.LogicDatabases["sharding_db"],
+				&model.StorageNode{IP: "10.0.0.1", Port: 5433, Database: "ds_0"})
+			// the instances on the same host are served by their own agent servers
+			agentPorts = map[string]uint16{"10.0.0.1:5433": 18081}
+			defer func() { agentPorts = map[string]uint16{} }()
+			proxy.EXPECT().ExportMetaData().Return(&model.ClusterInfo{}, nil)
+			as.EXPECT().CheckStatus(gomock.Any()).Return(nil).Times(2)
+			restored := make(chan string, 3)
+			as.EXPECT().RestoreDatabase(gomock.Any()).Do(func(in *model.RestoreIn) {
+				restored <- fmt.Sprintf("%d/%s:%s", in.DBPort, in.DBName, in.DnBackupID)
+			}).Return(nil).Times(3)
+			proxy.EXPECT().ImportMetaData(gomock.Any()).Return(nil)
+
+			Expect(restoreDatabase(proxy, dbBak, "sharding_db")).To(BeNil())
+			close(restored)
+			dbs := []string{}
+			for db := range restored {
+				dbs = append(dbs, db)
+			}
+			Expect(dbs).To(ConsistOf("5432/ds_0:dn1", "5432/ds_1:dn1", "5433/ds_0:dn2"))
+		})
+
+		It("should import the current metadata back if restore failed", func() {
+			proxy.EXPECT().ExportMetaData().Return(&model.ClusterInfo{
+				MetaData: model.MetaData{Databases: map[string]string{"sharding_db": "current"}},
+			}, nil)
+			as.EXPECT().CheckStatus(gomock.Any()).Return(nil)
+			proxy.EXPECT().DropDatabase("sharding_db").Return(nil)
+			as.EXPECT().RestoreDatabase(gomock.Any()).Return(xerr.NewCliErr("failed")).Times(2)
+			proxy.EXPECT().ImportMetaData(&model.ClusterInfo{
+				MetaData: model.MetaData{Databases: map[string]string{"sharding_db": "current"}},
+			}).Return(nil)
+
+			Expect(restoreDatabase(proxy, dbBak, "sharding_db")).NotTo(BeNil())
+		})
+
+		It("should fail before any restore if a storage node has no backup", func() {
+			dbBak.DnList = dbBak.DnList[1:]
+			Expect(execRestore(&model.LsBackup{
+				Info:     dbBak.Info,
+				DnList:   dbBak.DnList,
+				SsBackup: &model.SsBackup{StorageNodes: dbBak.SsBackup.LogicDatabases["sharding_db"]},
+			})).NotTo(BeNil())
+		})
+
+		It("should fail if the logic database is not in the backup record", func() {
+			Expect(restoreDatabase(proxy, dbBak, "unknown_db")).NotTo(BeNil())
+			dbBak.SsBackup.LogicDatabases = nil
+			Expect(restoreDatabase(proxy, dbBak, "sharding_db")).NotTo(BeNil())
+		})
+	})
 })
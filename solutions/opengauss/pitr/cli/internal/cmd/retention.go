@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/spf13/cobra"
+)
+
+var RetentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Set or show the retention policy of the backup records",
+	Long: "Set the retention policy if any rule is specified, the rules not specified are cleared.\n" +
+		"A backup record is retained if any rule keeps it, the records not retained are deleted by `prune`.",
+	Run: func(cmd *cobra.Command, args []string) {
+		set := false
+		for _, name := range []string{"keep-last", "keep-within", "keep-daily", "keep-weekly", "keep-monthly"} {
+			set = set || cmd.Flags().Changed(name)
+		}
+
+		if err := retention(set); err != nil {
+			logging.Error(err.Error())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(RetentionCmd)
+	RetentionCmd.Flags().IntVarP(&Retention.KeepLast, "keep-last", "", 0, "keep the last n backup records")
+	RetentionCmd.Flags().StringVarP(&Retention.KeepWithin, "keep-within", "", "", "keep the backup records started within the duration, e.g. 72h or 30d")
+	RetentionCmd.Flags().IntVarP(&Retention.KeepDaily, "keep-daily", "", 0, "keep the last backup record of each of the last n days")
+	RetentionCmd.Flags().IntVarP(&Retention.KeepWeekly, "keep-weekly", "", 0, "keep the last backup record of each of the last n weeks")
+	RetentionCmd.Flags().IntVarP(&Retention.KeepMonthly, "keep-monthly", "", 0, "keep the last backup record of each of the last n months")
+	RetentionCmd.Flags().StringVarP(&Catalog, "catalog", "", "", "backup catalog url, e.g. file:///home/omm/.gs_pitr or s3://bucket/prefix?endpoint=http://127.0.0.1:9000 (default local catalog)")
+}
+
+func retention(set bool) error {
+	ls, err := pkg.NewCatalog(Catalog)
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("connect to backup catalog failed. err: %s", err))
+	}
+
+	if set {
+		if err := Retention.Validate(); err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("invalid retention policy. err: %s", err))
+		}
+		if err := ls.WriteRetention(Retention); err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("write retention policy failed. err: %s", err))
+		}
+		logging.Info("Set retention policy success!")
+	}
+
+	r, err := ls.ReadRetention()
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("read retention policy failed. err: %s", err))
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetTitle("Retention Policy")
+	t.AppendHeader(table.Row{"Rule", "Value"})
+	t.AppendRows([]table.Row{
+		{"keep-last", r.KeepLast},
+		{"keep-within", r.KeepWithin},
+		{"keep-daily", r.KeepDaily},
+		{"keep-weekly", r.KeepWeekly},
+		{"keep-monthly", r.KeepMonthly},
+	})
+	t.Render()
+
+	if r.Empty() {
+		logging.Warn("No retention policy, nothing is pruned.")
+	}
+	return nil
+}
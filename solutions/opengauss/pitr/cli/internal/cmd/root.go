@@ -18,7 +18,12 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/httputils"
 
 	"github.com/spf13/cobra"
 )
@@ -46,6 +51,32 @@ var (
 	CSN string
 	// RecordID openGauss data backup record id
 	RecordID string
+	// Catalog backup catalog url, see pkg.NewCatalog
+	Catalog string
+	// TargetTime restore to the time like `2023-03-01 12:00:00`
+	TargetTime string
+	// TargetCSN restore to the commit sequence number
+	TargetCSN string
+	// TargetLSN restore to the log sequence number like `0/3000028`
+	TargetLSN string
+	// LogicDatabase restore the logic database only
+	LogicDatabase string
+	// TargetMap the file to remap the storage nodes of the backup, see loadTargetMap
+	TargetMap string
+	// AgentCA the CA file to verify the agent servers, they are not verified if it is empty
+	AgentCA string
+	// AgentCert the client certificate file for the agent servers requiring mutual TLS
+	AgentCert string
+	// AgentKey the key file of the client certificate
+	AgentKey string
+	// AgentToken the bearer token for the agent servers, read from env GS_PITR_AGENT_TOKEN if it is empty
+	AgentToken string
+	// Retention the rules of the retention policy, see model.Retention
+	Retention = &model.Retention{}
+	// DryRun prints the plan only
+	DryRun bool
+	// Tree shows the backup records as the trees of the PTRACK chains
+	Tree bool
 )
 
 var RootCmd = &cobra.Command{
@@ -56,4 +87,21 @@ var RootCmd = &cobra.Command{
 		DisableDefaultCmd: true,
 		HiddenDefaultCmd:  true,
 	},
+
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setAgentAuth()
+	},
+}
+
+// setAgentAuth sets the client certificate and the bearer token for the requests to the agent servers
+func setAgentAuth() error {
+	if AgentToken == "" {
+		AgentToken = os.Getenv("GS_PITR_AGENT_TOKEN")
+	}
+	httputils.SetBearerToken(AgentToken)
+
+	if err := httputils.SetTLS(AgentCA, AgentCert, AgentKey); err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("set agent tls failed. err: %s", err))
+	}
+	return nil
 }
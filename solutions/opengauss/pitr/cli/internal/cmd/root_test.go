@@ -26,6 +26,7 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
 )
 
 var _ = Describe("Root", func() {
@@ -68,4 +69,18 @@ var _ = Describe("Root", func() {
 			Expect(err).To(BeNil())
 		})
 	})
+
+	Context("when print flags", func() {
+		It("should mask the password and the agent token", func() {
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			fs.String("password", "", "")
+			fs.String("agent-token", "", "")
+			fs.String("host", "", "")
+			Expect(fs.Parse([]string{"--password=secret", "--host=127.0.0.1"})).To(Succeed())
+
+			Expect(flagValue(fs.Lookup("password"))).To(Equal("******"))
+			Expect(flagValue(fs.Lookup("agent-token"))).To(BeEmpty())
+			Expect(flagValue(fs.Lookup("host"))).To(Equal("127.0.0.1"))
+		})
+	})
 })
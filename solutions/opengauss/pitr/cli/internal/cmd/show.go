@@ -20,11 +20,14 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
+	"github.com/jedib0t/go-pretty/v6/list"
 
 	"github.com/spf13/cobra"
 )
@@ -48,12 +51,23 @@ func init() {
 	RootCmd.AddCommand(ShowCmd)
 	ShowCmd.Flags().StringVarP(&CSN, "csn", "", "", "commit sequence number")
 	ShowCmd.Flags().StringVarP(&RecordID, "id", "", "", "backup record id")
+	ShowCmd.Flags().BoolVarP(&Tree, "tree", "", false, "show the backup records as the trees of the FULL records and the PTRACK records depending on them")
+	ShowCmd.Flags().StringVarP(&Catalog, "catalog", "", "", "backup catalog url, e.g. file:///home/omm/.gs_pitr or s3://bucket/prefix?endpoint=http://127.0.0.1:9000 (default local catalog)")
 }
 
 func show() error {
-	ls, err := pkg.NewLocalStorage(pkg.DefaultRootDir())
+	ls, err := pkg.NewCatalog(Catalog)
 	if err != nil {
-		return xerr.NewCliErr(fmt.Sprintf("connect to local storage failed. err: %s", err))
+		return xerr.NewCliErr(fmt.Sprintf("connect to backup catalog failed. err: %s", err))
+	}
+
+	if Tree {
+		baks, err := ls.ReadAll()
+		if err != nil {
+			return xerr.NewCliErr(fmt.Sprintf("read backup record failed. err: %s", err))
+		}
+		fmt.Println(formatBackupTree(baks))
+		return nil
 	}
 
 	// show backup record by csn
@@ -139,3 +153,88 @@ func formatRecordJSON(backups []*model.LsBackup) error {
 	fmt.Println(ds)
 	return nil
 }
+
+// formatBackupTree renders the backup records from the oldest to the newest, the PTRACK records are under their latest parents
+func formatBackupTree(baks []*model.LsBackup) string {
+	var (
+		parents  = backupParents(baks)
+		children = make(map[string][]*model.LsBackup)
+		roots    []*model.LsBackup
+		sorted   = make([]*model.LsBackup, 0, len(baks))
+	)
+	for _, b := range baks {
+		if b != nil && b.Info != nil {
+			sorted = append(sorted, b)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Info.StartTime < sorted[j].Info.StartTime
+	})
+	for _, b := range sorted {
+		if ps := parents[b.Info.ID]; len(ps) > 0 {
+			p := latestBackup(ps)
+			children[p.Info.ID] = append(children[p.Info.ID], b)
+		} else {
+			roots = append(roots, b)
+		}
+	}
+
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedRounded)
+	var appendTree func(b *model.LsBackup)
+	appendTree = func(b *model.LsBackup) {
+		l.AppendItem(backupTreeItem(b, parents))
+		if cs := children[b.Info.ID]; len(cs) > 0 {
+			l.Indent()
+			for _, c := range cs {
+				appendTree(c)
+			}
+			l.UnIndent()
+		}
+	}
+	for _, r := range roots {
+		appendTree(r)
+	}
+	return l.Render()
+}
+
+func latestBackup(baks []*model.LsBackup) *model.LsBackup {
+	latest := baks[0]
+	for _, b := range baks[1:] {
+		if b.Info.StartTime > latest.Info.StartTime {
+			latest = b
+		}
+	}
+	return latest
+}
+
+func backupTreeItem(b *model.LsBackup, parents map[string][]*model.LsBackup) string {
+	var status model.BackupStatus
+	if b.SsBackup != nil {
+		status = b.SsBackup.Status
+	}
+	item := fmt.Sprintf("%s %s (CSN: %s, start time: %s, status: %s", b.Info.BackupMode, b.Info.ID, b.Info.CSN, b.Info.StartTime, status)
+	if b.Validation != nil {
+		item += fmt.Sprintf(", validation: %s", b.Validation.Status)
+	}
+	item += ")"
+	if b.Info.BackupMode != model.DBBackModePTrack {
+		return item
+	}
+	ps := parents[b.Info.ID]
+	if len(ps) > 1 {
+		// the data nodes depend on different records, the record is under the latest one
+		latest := latestBackup(ps)
+		ids := make([]string, 0, len(ps)-1)
+		for _, p := range ps {
+			if p != latest {
+				ids = append(ids, p.Info.ID)
+			}
+		}
+		item += fmt.Sprintf(" [also depends on %s]", strings.Join(ids, ", "))
+	}
+	if len(ps) == 0 || len(missingParents(b, ps)) > 0 {
+		item += " [parent missing]"
+	}
+	return item
+}
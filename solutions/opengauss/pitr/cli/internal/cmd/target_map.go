@@ -0,0 +1,194 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
+)
+
+var (
+	// agent server ports of the storage nodes remapped by the target map by ip:port, the others use AgentPort
+	agentPorts = map[string]uint16{}
+
+	// the data source urls like `jdbc:opengauss://127.0.0.1:5432/ds_0?batchMode=on` or `jdbc:opengauss://[::1]:5432/ds_0`
+	dataSourceURLRegexp = regexp.MustCompile(`jdbc:\w+://[^\s'"]+`)
+)
+
+func agentPortOf(ip string, port uint16) uint16 {
+	if p, ok := agentPorts[nodeKey(ip, port)]; ok {
+		return p
+	}
+	return AgentPort
+}
+
+func nodeKey(ip string, port uint16) string {
+	return net.JoinHostPort(ip, strconv.Itoa(int(port)))
+}
+
+/*
+loadTargetMap loads the target map file like:
+
+	{
+	  "storage_nodes": [
+	    {"source": "10.0.0.1:5432", "ip": "10.1.0.1", "port": 5432, "agent_port": 18080}
+	  ]
+	}
+
+the agent_port is optional, AgentPort is used if it is not set.
+*/
+func loadTargetMap(path string) (map[string]*model.TargetStorageNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("read target map failed. err: %s", err))
+	}
+
+	tm := &model.TargetMap{}
+	if err := json.Unmarshal(data, tm); err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("invalid target map. err: %s", err))
+	}
+
+	targets := make(map[string]*model.TargetStorageNode, len(tm.StorageNodes))
+	for _, t := range tm.StorageNodes {
+		host, port, err := net.SplitHostPort(t.Source)
+		if err != nil {
+			return nil, xerr.NewCliErr(fmt.Sprintf("invalid source [%s] of target map, the format is ip:port.", t.Source))
+		}
+		if t.IP == "" || t.Port == 0 {
+			return nil, xerr.NewCliErr(fmt.Sprintf("the ip and port of source [%s] are required in target map.", t.Source))
+		}
+
+		source := net.JoinHostPort(host, port)
+		if _, ok := targets[source]; ok {
+			return nil, xerr.NewCliErr(fmt.Sprintf("duplicate source [%s] in target map.", t.Source))
+		}
+		targets[source] = t
+	}
+	return targets, nil
+}
+
+/*
+remapBackup returns a copy of the backup record, with the storage nodes remapped to the targets:
+
+	the storage nodes and data nodes are remapped, the data nodes are restored to the targets,
+	and the host and port of the data source urls in the metadata are rewritten, which is imported to ss-proxy.
+
+Every storage node must be remapped, so that the backup is never restored to the source cluster by mistake.
+*/
+func remapBackup(bak *model.LsBackup, targets map[string]*model.TargetStorageNode) (*model.LsBackup, error) {
+	remap := func(sn *model.StorageNode) (*model.StorageNode, error) {
+		t, ok := targets[nodeKey(sn.IP, sn.Port)]
+		if !ok {
+			return nil, xerr.NewCliErr(fmt.Sprintf("storage node [%s] is not found in target map.", nodeKey(sn.IP, sn.Port)))
+		}
+		node := *sn
+		node.IP, node.Port = t.IP, t.Port
+		return &node, nil
+	}
+
+	ss := *bak.SsBackup
+	ss.StorageNodes = make([]*model.StorageNode, 0, len(bak.SsBackup.StorageNodes))
+	for _, sn := range bak.SsBackup.StorageNodes {
+		node, err := remap(sn)
+		if err != nil {
+			return nil, err
+		}
+		ss.StorageNodes = append(ss.StorageNodes, node)
+	}
+
+	if bak.SsBackup.LogicDatabases != nil {
+		ss.LogicDatabases = make(map[string][]*model.StorageNode, len(bak.SsBackup.LogicDatabases))
+		for db, nodes := range bak.SsBackup.LogicDatabases {
+			for _, sn := range nodes {
+				node, err := remap(sn)
+				if err != nil {
+					return nil, err
+				}
+				ss.LogicDatabases[db] = append(ss.LogicDatabases[db], node)
+			}
+		}
+	}
+
+	if bak.SsBackup.ClusterInfo != nil {
+		cluster := *bak.SsBackup.ClusterInfo
+		cluster.MetaData.Databases = make(map[string]string, len(bak.SsBackup.ClusterInfo.MetaData.Databases))
+		for db, config := range bak.SsBackup.ClusterInfo.MetaData.Databases {
+			cluster.MetaData.Databases[db] = remapDataSourceURLs(config, targets)
+		}
+		ss.ClusterInfo = &cluster
+	}
+
+	dnList := make([]*model.DataNode, 0, len(bak.DnList))
+	for _, dn := range bak.DnList {
+		t, ok := targets[nodeKey(dn.IP, dn.Port)]
+		if !ok {
+			return nil, xerr.NewCliErr(fmt.Sprintf("data node [%s] is not found in target map.", nodeKey(dn.IP, dn.Port)))
+		}
+		node := *dn
+		node.IP, node.Port = t.IP, t.Port
+		dnList = append(dnList, &node)
+	}
+
+	for source, t := range targets {
+		if t.AgentPort != 0 {
+			agentPorts[nodeKey(t.IP, t.Port)] = t.AgentPort
+		}
+		logging.Info(fmt.Sprintf("Storage node %s will be restored to %s", source, nodeKey(t.IP, t.Port)))
+	}
+
+	return &model.LsBackup{
+		Info:     bak.Info,
+		DnList:   dnList,
+		SsBackup: &ss,
+	}, nil
+}
+
+// dataSourceOf parses the data source url and returns its ip:port, it returns false if the url has no host and port
+func dataSourceOf(s string) (*url.URL, string, bool) {
+	u, err := url.Parse(strings.TrimPrefix(s, "jdbc:"))
+	if err != nil || u.Hostname() == "" || u.Port() == "" {
+		return nil, "", false
+	}
+	return u, net.JoinHostPort(u.Hostname(), u.Port()), true
+}
+
+// remapDataSourceURLs rewrites the host and port of the data source urls in the database config at once,
+// so a target is never remapped again even if it is the source of another one.
+func remapDataSourceURLs(config string, targets map[string]*model.TargetStorageNode) string {
+	return dataSourceURLRegexp.ReplaceAllStringFunc(config, func(s string) string {
+		u, source, ok := dataSourceOf(s)
+		if !ok {
+			return s
+		}
+		t, ok := targets[source]
+		if !ok {
+			return s
+		}
+		return strings.Replace(s, "//"+u.Host, "//"+nodeKey(t.IP, t.Port), 1)
+	})
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Target map", func() {
+	writeTargetMap := func(contents string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "target-map.json")
+		Expect(os.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return path
+	}
+
+	Context("load target map", func() {
+		It("should load the target map", func() {
+			targets, err := loadTargetMap(writeTargetMap(`{"storage_nodes": [
+				{"source": "10.0.0.1:5432", "ip": "10.1.0.1", "port": 15432, "agent_port": 18080},
+				{"source": "10.0.0.2:5432", "ip": "10.1.0.2", "port": 5432}
+			]}`))
+			Expect(err).To(BeNil())
+			Expect(targets).To(HaveLen(2))
+			Expect(targets["10.0.0.1:5432"].IP).To(Equal("10.1.0.1"))
+		})
+
+		It("should fail with the invalid target map", func() {
+			_, err := loadTargetMap(writeTargetMap(`{"storage_nodes": [{"source": "10.0.0.1", "ip": "10.1.0.1", "port": 5432}]}`))
+			Expect(err).NotTo(BeNil())
+			_, err = loadTargetMap(writeTargetMap(`{"storage_nodes": [{"source": "10.0.0.1:5432", "ip": "10.1.0.1"}]}`))
+			Expect(err).NotTo(BeNil())
+			_, err = loadTargetMap(writeTargetMap(`{"storage_nodes": [
+				{"source": "10.0.0.1:5432", "ip": "10.1.0.1", "port": 5432},
+				{"source": "10.0.0.1:5432", "ip": "10.1.0.2", "port": 5432}
+			]}`))
+			Expect(err).NotTo(BeNil())
+			_, err = loadTargetMap(filepath.Join(GinkgoT().TempDir(), "not-exist.json"))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Context("remap backup", func() {
+		var bak *model.LsBackup
+
+		BeforeEach(func() {
+			agentPorts = map[string]uint16{}
+			bak = &model.LsBackup{
+				Info: &model.BackupMetaInfo{ID: "backup-id-1"},
+				DnList: []*model.DataNode{
+					{IP: "10.0.0.1", Port: 5432, BackupID: "dn1"},
+					{IP: "10.0.0.2", Port: 5432, BackupID: "dn2"},
+				},
+				SsBackup: &model.SsBackup{
+					ClusterInfo: &model.ClusterInfo{
+						MetaData: model.MetaData{Databases: map[string]string{
+							"sharding_db": "dataSources:\n  ds_0:\n    url: jdbc:opengauss://10.0.0.1:5432/ds_0?batchMode=on\n" +
+								"  ds_1:\n    url: jdbc:opengauss://10.0.0.2:5432/ds_1\n",
+						}},
+					},
+					StorageNodes: []*model.StorageNode{{IP: "10.0.0.1", Port: 5432}, {IP: "10.0.0.2", Port: 5432}},
+					LogicDatabases: map[string][]*model.StorageNode{
+						"sharding_db": {{IP: "10.0.0.1", Port: 5432, Database: "ds_0"}, {IP: "10.0.0.2", Port: 5432, Database: "ds_1"}},
+					},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			agentPorts = map[string]uint16{}
+		})
+
+		It("should remap the storage nodes and the data source urls", func() {
+			// swap the storage nodes
+			remapped, err := remapBackup(bak, map[string]*model.TargetStorageNode{
+				"10.0.0.1:5432": {Source: "10.0.0.1:5432", IP: "10.0.0.2", Port: 5432, AgentPort: 18080},
+				"10.0.0.2:5432": {Source: "10.0.0.2:5432", IP: "10.0.0.1", Port: 5432},
+			})
+			Expect(err).To(BeNil())
+
+			Expect(remapped.SsBackup.StorageNodes[0].IP).To(Equal("10.0.0.2"))
+			Expect(remapped.SsBackup.StorageNodes[1].IP).To(Equal("10.0.0.1"))
+			Expect(remapped.DnList[0].IP).To(Equal("10.0.0.2"))
+			Expect(remapped.DnList[0].BackupID).To(Equal("dn1"))
+			Expect(remapped.SsBackup.LogicDatabases["sharding_db"][0].IP).To(Equal("10.0.0.2"))
+			Expect(remapped.SsBackup.LogicDatabases["sharding_db"][0].Database).To(Equal("ds_0"))
+			Expect(remapped.SsBackup.ClusterInfo.MetaData.Databases["sharding_db"]).To(Equal(
+				"dataSources:\n  ds_0:\n    url: jdbc:opengauss://10.0.0.2:5432/ds_0?batchMode=on\n" +
+					"  ds_1:\n    url: jdbc:opengauss://10.0.0.1:5432/ds_1\n"))
+			Expect(agentPortOf("10.0.0.2", 5432)).To(Equal(uint16(18080)))
+			Expect(agentPortOf("10.0.0.2", 5433)).To(Equal(AgentPort))
+			Expect(agentPortOf("10.0.0.1", 5432)).To(Equal(AgentPort))
+
+			// the backup record is not changed
+			Expect(bak.SsBackup.StorageNodes[0].IP).To(Equal("10.0.0.1"))
+			Expect(bak.DnList[0].IP).To(Equal("10.0.0.1"))
+			Expect(bak.SsBackup.ClusterInfo.MetaData.Databases["sharding_db"]).To(ContainSubstring("//10.0.0.1:5432/ds_0"))
+		})
+
+		It("should remap the agent ports and the data source urls of the instances on the same host", func() {
+			bak.DnList[1].IP, bak.DnList[1].Port = "10.0.0.1", 5433
+			bak.SsBackup.StorageNodes[1].IP, bak.SsBackup.StorageNodes[1].Port = "10.0.0.1", 5433
+			bak.SsBackup.LogicDatabases["sharding_db"][1].IP, bak.SsBackup.LogicDatabases["sharding_db"][1].Port = "10.0.0.1", 5433
+			bak.SsBackup.ClusterInfo.MetaData.Databases["sharding_db"] = "dataSources:\n  ds_0:\n    url: jdbc:opengauss://10.0.0.1:5432/ds_0?batchMode=on\n" +
+				"  ds_1:\n    url: 'jdbc:opengauss://10.0.0.1:5433/ds_1'\n"
+
+			remapped, err := remapBackup(bak, map[string]*model.TargetStorageNode{
+				"10.0.0.1:5432": {Source: "10.0.0.1:5432", IP: "fd00::1", Port: 5432, AgentPort: 18080},
+				"10.0.0.1:5433": {Source: "10.0.0.1:5433", IP: "fd00::1", Port: 5433, AgentPort: 18081},
+			})
+			Expect(err).To(BeNil())
+			Expect(remapped.DnList[1].Port).To(Equal(uint16(5433)))
+			Expect(remapped.SsBackup.ClusterInfo.MetaData.Databases["sharding_db"]).To(Equal(
+				"dataSources:\n  ds_0:\n    url: jdbc:opengauss://[fd00::1]:5432/ds_0?batchMode=on\n" +
+					"  ds_1:\n    url: 'jdbc:opengauss://[fd00::1]:5433/ds_1'\n"))
+			Expect(agentPortOf("fd00::1", 5432)).To(Equal(uint16(18080)))
+			Expect(agentPortOf("fd00::1", 5433)).To(Equal(uint16(18081)))
+		})
+
+		It("should remap the data source urls of the ipv6 hosts", func() {
+			config := "dataSources:\n  ds_0:\n    url: jdbc:opengauss://[fd00::1]:5432/ds_0?batchMode=on\n" +
+				"  ds_1:\n    url: jdbc:opengauss://[fd00::2]:5432/ds_1\n"
+			Expect(remapDataSourceURLs(config, map[string]*model.TargetStorageNode{
+				"[fd00::1]:5432": {Source: "[fd00::1]:5432", IP: "10.1.0.1", Port: 15432},
+			})).To(Equal("dataSources:\n  ds_0:\n    url: jdbc:opengauss://10.1.0.1:15432/ds_0?batchMode=on\n" +
+				"  ds_1:\n    url: jdbc:opengauss://[fd00::2]:5432/ds_1\n"))
+		})
+
+		It("should fail if any storage node is not remapped", func() {
+			_, err := remapBackup(bak, map[string]*model.TargetStorageNode{
+				"10.0.0.1:5432": {Source: "10.0.0.1:5432", IP: "10.1.0.1", Port: 5432},
+			})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,282 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/logging"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/timeutil"
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/spf13/cobra"
+)
+
+var ValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate a backup record is restorable",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Flags().VisitAll(printFlag)
+
+		if CSN == "" && RecordID == "" {
+			logging.Error("Please specify csn or record id")
+			return
+		}
+
+		if CSN != "" && RecordID != "" {
+			logging.Error("Please specify only one of csn and record id")
+			return
+		}
+
+		if err := validateRecord(); err != nil {
+			logging.Error(err.Error())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(ValidateCmd)
+
+	ValidateCmd.Flags().StringVarP(&Host, "host", "H", "", "ss-proxy hostname or ip, only to reach the agent servers of the storage nodes at 127.0.0.1")
+	ValidateCmd.Flags().StringVarP(&BackupPath, "dn-backup-path", "B", "", "openGauss data backup path")
+	_ = ValidateCmd.MarkFlagRequired("dn-backup-path")
+	ValidateCmd.Flags().Uint8VarP(&ThreadsNum, "dn-threads-num", "j", 1, "openGauss data validate threads nums")
+	ValidateCmd.Flags().Uint16VarP(&AgentPort, "agent-port", "a", 443, "agent server port")
+	_ = ValidateCmd.MarkFlagRequired("agent-port")
+	ValidateCmd.Flags().StringVarP(&AgentCA, "agent-ca", "", "", "CA file to verify the agent servers (default not verified)")
+	ValidateCmd.Flags().StringVarP(&AgentCert, "agent-cert", "", "", "client certificate file for the agent servers requiring mutual TLS")
+	ValidateCmd.Flags().StringVarP(&AgentKey, "agent-key", "", "", "key file of the agent client certificate")
+	ValidateCmd.Flags().StringVarP(&AgentToken, "agent-token", "", "", "bearer token for the agent servers (default env GS_PITR_AGENT_TOKEN)")
+
+	ValidateCmd.Flags().StringVarP(&CSN, "csn", "", "", "commit sequence number")
+	ValidateCmd.Flags().StringVarP(&RecordID, "id", "", "", "backup record id")
+	ValidateCmd.Flags().StringVarP(&Catalog, "catalog", "", "", "backup catalog url, e.g. file:///home/omm/.gs_pitr or s3://bucket/prefix?endpoint=http://127.0.0.1:9000 (default local catalog)")
+}
+
+/*
+validateRecord validates the backup record and stores the verdict in it:
+
+	the backup of every data node is validated by `gs_probackup validate` on its agent server,
+	and its content-crc and LSN range must be the ones recorded when the backup is done,
+	the cluster info must reference exactly the backed up storage nodes.
+*/
+func validateRecord() error {
+	ls, err := pkg.NewCatalog(Catalog)
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("new backup catalog failed. err: %s", err))
+	}
+
+	baks, err := validate(ls, CSN, RecordID)
+	if err != nil {
+		return err
+	}
+	bak := baks[0]
+
+	if bak.SsBackup == nil || bak.SsBackup.Status != model.SsBackupStatusCompleted {
+		return xerr.NewCliErr(fmt.Sprintf("backup record(ID: %s) is not completed.", bak.Info.ID))
+	}
+
+	logging.Info("Checking agent server status...")
+	if available := checkAgentServerStatus(bak); !available {
+		return xerr.NewCliErr("one or more agent server are not available.")
+	}
+
+	errs := checkClusterInfo(bak)
+	logging.Info("Start validate backup data on openGauss...")
+	errs = append(errs, execValidate(bak)...)
+
+	v := &model.Validation{
+		Status: model.ValidationStatusValid,
+		Time:   timeutil.Now().String(),
+		Errors: errs,
+	}
+	if len(errs) > 0 {
+		v.Status = model.ValidationStatusInvalid
+	}
+	bak.Validation = v
+	printValidation(bak)
+
+	if err := ls.WriteByJSON(bak.Info.FileName, bak); err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("update backup record failed. err: %s", err))
+	}
+
+	if v.Status != model.ValidationStatusValid {
+		return xerr.NewCliErr(fmt.Sprintf("backup record(ID: %s) is invalid.", bak.Info.ID))
+	}
+	logging.Info("Validate success!")
+	return nil
+}
+
+// checkClusterInfo checks the data sources of the cluster info are exactly the backed up storage nodes, and each of them has a data node backup
+func checkClusterInfo(bak *model.LsBackup) []string {
+	if bak.SsBackup.ClusterInfo == nil {
+		return []string{"the cluster info is missing"}
+	}
+
+	var (
+		errs        []string
+		dataSources = map[string]struct{}{}
+		nodes       = map[string]struct{}{}
+		dataNodes   = map[string]*model.DataNode{}
+	)
+	for _, config := range bak.SsBackup.ClusterInfo.MetaData.Databases {
+		for _, s := range dataSourceURLRegexp.FindAllString(config, -1) {
+			if _, ds, ok := dataSourceOf(s); ok {
+				dataSources[ds] = struct{}{}
+			}
+		}
+	}
+	for _, dn := range bak.DnList {
+		dataNodes[nodeKey(dn.IP, dn.Port)] = dn
+	}
+
+	for _, sn := range bak.SsBackup.StorageNodes {
+		key := nodeKey(sn.IP, sn.Port)
+		nodes[key] = struct{}{}
+		if _, ok := dataSources[key]; !ok {
+			errs = append(errs, fmt.Sprintf("storage node %s is not referenced by the cluster info", key))
+		}
+		if dn, ok := dataNodes[nodeKey(sn.IP, sn.Port)]; !ok || dn.BackupID == "" {
+			errs = append(errs, fmt.Sprintf("storage node %s has no data node backup", key))
+		}
+	}
+
+	missing := make([]string, 0)
+	for ds := range dataSources {
+		if _, ok := nodes[ds]; !ok {
+			missing = append(missing, ds)
+		}
+	}
+	sort.Strings(missing)
+	for _, ds := range missing {
+		errs = append(errs, fmt.Sprintf("data source %s of the cluster info is not backed up", ds))
+	}
+
+	if si := bak.SsBackup.ClusterInfo.SnapshotInfo; si != nil && si.Csn != "" && bak.Info.CSN != "" && si.Csn != bak.Info.CSN {
+		errs = append(errs, fmt.Sprintf("csn %s of the cluster info is not the csn %s of the backup", si.Csn, bak.Info.CSN))
+	}
+	return errs
+}
+
+// execValidate validates the backups of all the data nodes at the same time
+func execValidate(bak *model.LsBackup) []string {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		errs      []string
+		dataNodes = map[string]*model.DataNode{}
+	)
+	for _, dn := range bak.DnList {
+		dataNodes[nodeKey(dn.IP, dn.Port)] = dn
+	}
+
+	for _, node := range bak.SsBackup.StorageNodes {
+		sn := node
+		dn, ok := dataNodes[nodeKey(sn.IP, sn.Port)]
+		if !ok || dn.BackupID == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			as := pkg.NewAgentServer(fmt.Sprintf("%s:%d", convertLocalhost(sn.IP), agentPortOf(sn.IP, sn.Port)))
+			in := &model.ValidateIn{
+				DBPort:       sn.Port,
+				DBName:       sn.Database,
+				Username:     sn.Username,
+				Password:     sn.Password,
+				DnBackupID:   dn.BackupID,
+				DnBackupPath: BackupPath,
+				DnThreadsNum: ThreadsNum,
+				Instance:     defaultInstance,
+			}
+
+			var dnErrs []string
+			out, err := as.Validate(in)
+			if err != nil {
+				dnErrs = []string{fmt.Sprintf("data node %s: validate failed. err: %s", nodeKey(sn.IP, sn.Port), err)}
+			} else {
+				dnErrs = checkDataNode(sn, dn, out)
+			}
+
+			mu.Lock()
+			errs = append(errs, dnErrs...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Strings(errs)
+	return errs
+}
+
+// checkDataNode checks the validated backup of the data node is the one recorded when the backup is done
+func checkDataNode(sn *model.StorageNode, dn *model.DataNode, out *model.ValidateOut) []string {
+	var (
+		errs []string
+		key  = nodeKey(sn.IP, sn.Port)
+	)
+	if !out.Valid {
+		errs = append(errs, fmt.Sprintf("data node %s: backup %s is not valid. %s", key, dn.BackupID, out.Msg))
+	}
+	if out.Backup == nil {
+		return append(errs, fmt.Sprintf("data node %s: backup %s is not found", key, dn.BackupID))
+	}
+
+	b := out.Backup
+	if b.Status != model.SsBackupStatusCompleted {
+		errs = append(errs, fmt.Sprintf("data node %s: backup %s status is %s", key, dn.BackupID, b.Status))
+	}
+	if dn.ContentCrc != 0 && b.ContentCrc != dn.ContentCrc {
+		errs = append(errs, fmt.Sprintf("data node %s: content-crc %d is not the recorded %d", key, b.ContentCrc, dn.ContentCrc))
+	}
+	if dn.StartLsn != "" && b.StartLsn != dn.StartLsn {
+		errs = append(errs, fmt.Sprintf("data node %s: start-lsn %s is not the recorded %s", key, b.StartLsn, dn.StartLsn))
+	}
+	if dn.StopLsn != "" && b.StopLsn != dn.StopLsn {
+		errs = append(errs, fmt.Sprintf("data node %s: stop-lsn %s is not the recorded %s", key, b.StopLsn, dn.StopLsn))
+	}
+
+	start, serr := parseLSN(b.StartLsn)
+	stop, perr := parseLSN(b.StopLsn)
+	if serr != nil || perr != nil || start > stop {
+		errs = append(errs, fmt.Sprintf("data node %s: invalid lsn range [%s, %s]", key, b.StartLsn, b.StopLsn))
+	}
+	return errs
+}
+
+func printValidation(bak *model.LsBackup) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetTitle("Validate Result: %s", bak.Validation.Status)
+	t.AppendHeader(table.Row{"#", "Error"})
+
+	for i, e := range bak.Validation.Errors {
+		t.AppendRow([]interface{}{i + 1, e})
+		t.AppendSeparator()
+	}
+
+	t.Render()
+}
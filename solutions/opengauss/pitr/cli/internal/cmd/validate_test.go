@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bou.ke/monkey"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg"
+	mock_pkg "github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/mocks"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("test validate", func() {
+	var (
+		ls  *mock_pkg.MockILocalStorage
+		as  *mock_pkg.MockIAgentServer
+		bak *model.LsBackup
+	)
+
+	BeforeEach(func() {
+		bak = &model.LsBackup{
+			Info: &model.BackupMetaInfo{ID: "backup-id-1", CSN: "csn-1", FileName: "backup"},
+			DnList: []*model.DataNode{
+				{IP: "10.0.0.1", Port: 5432, BackupID: "RS3ZGQ", StartLsn: "0/2000028", StopLsn: "0/20001C0", ContentCrc: 1365742592},
+				{IP: "10.0.0.2", Port: 5432, BackupID: "RS3ZGR", StartLsn: "0/3000028", StopLsn: "0/30001C0", ContentCrc: 1365742593},
+			},
+			SsBackup: &model.SsBackup{
+				Status: model.SsBackupStatusCompleted,
+				ClusterInfo: &model.ClusterInfo{
+					MetaData: model.MetaData{
+						Databases: map[string]string{
+							"sharding_db": "dataSources:\n  ds_0:\n    url: jdbc:opengauss://10.0.0.1:5432/ds_0\n  ds_1:\n    url: jdbc:opengauss://10.0.0.2:5432/ds_1\n",
+						},
+					},
+					SnapshotInfo: &model.SnapshotInfo{Csn: "csn-1"},
+				},
+				StorageNodes: []*model.StorageNode{
+					{IP: "10.0.0.1", Port: 5432},
+					{IP: "10.0.0.2", Port: 5432},
+				},
+			},
+		}
+	})
+
+	Context("check cluster info", func() {
+		It("consistent", func() {
+			Expect(checkClusterInfo(bak)).To(BeEmpty())
+		})
+
+		It("inconsistent storage nodes and csn", func() {
+			bak.SsBackup.ClusterInfo.MetaData.Databases["sharding_db"] = "url: jdbc:opengauss://10.0.0.1:5432/ds_0\nurl: jdbc:opengauss://10.0.0.3:5432/ds_2\n"
+			bak.SsBackup.ClusterInfo.SnapshotInfo.Csn = "csn-2"
+			bak.DnList = bak.DnList[:1]
+			Expect(checkClusterInfo(bak)).To(Equal([]string{
+				"storage node 10.0.0.2:5432 is not referenced by the cluster info",
+				"storage node 10.0.0.2:5432 has no data node backup",
+				"data source 10.0.0.3:5432 of the cluster info is not backed up",
+				"csn csn-2 of the cluster info is not the csn csn-1 of the backup",
+			}))
+		})
+	})
+
+	Context("check data node", func() {
+		It("the recorded backup", func() {
+			out := &model.ValidateOut{Valid: true, Backup: &model.BackupInfo{
+				Status: model.SsBackupStatusCompleted, StartLsn: "0/2000028", StopLsn: "0/20001C0", ContentCrc: 1365742592,
+			}}
+			Expect(checkDataNode(bak.SsBackup.StorageNodes[0], bak.DnList[0], out)).To(BeEmpty())
+		})
+
+		It("changed backup", func() {
+			out := &model.ValidateOut{Valid: false, Msg: "corrupted", Backup: &model.BackupInfo{
+				Status: model.SsBackupStatusCorrupt, StartLsn: "0/2000028", StopLsn: "0/1000000", ContentCrc: 1,
+			}}
+			Expect(checkDataNode(bak.SsBackup.StorageNodes[0], bak.DnList[0], out)).To(Equal([]string{
+				"data node 10.0.0.1:5432: backup RS3ZGQ is not valid. corrupted",
+				"data node 10.0.0.1:5432: backup RS3ZGQ status is Corrupt",
+				"data node 10.0.0.1:5432: content-crc 1 is not the recorded 1365742592",
+				"data node 10.0.0.1:5432: stop-lsn 0/1000000 is not the recorded 0/20001C0",
+				"data node 10.0.0.1:5432: invalid lsn range [0/2000028, 0/1000000]",
+			}))
+		})
+	})
+
+	Context("validate record", func() {
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			ls = mock_pkg.NewMockILocalStorage(ctrl)
+			as = mock_pkg.NewMockIAgentServer(ctrl)
+			monkey.Patch(pkg.NewLocalStorage, func(rootDir string) (pkg.ILocalStorage, error) {
+				return ls, nil
+			})
+			monkey.Patch(pkg.NewAgentServer, func(_ string) pkg.IAgentServer {
+				return as
+			})
+			RecordID = "backup-id-1"
+			ls.EXPECT().ReadByID("backup-id-1").Return(bak, nil)
+			as.EXPECT().CheckStatus(gomock.Any()).Return(nil).Times(2)
+		})
+		AfterEach(func() {
+			RecordID = ""
+			ctrl.Finish()
+			monkey.UnpatchAll()
+		})
+
+		It("valid", func() {
+			as.EXPECT().Validate(gomock.Any()).DoAndReturn(func(in *model.ValidateIn) (*model.ValidateOut, error) {
+				for _, dn := range bak.DnList {
+					if dn.BackupID == in.DnBackupID {
+						return &model.ValidateOut{Valid: true, Backup: &model.BackupInfo{
+							Status: model.SsBackupStatusCompleted, StartLsn: dn.StartLsn, StopLsn: dn.StopLsn, ContentCrc: dn.ContentCrc,
+						}}, nil
+					}
+				}
+				return nil, nil
+			}).Times(2)
+			ls.EXPECT().WriteByJSON("backup", bak).Return(nil)
+
+			Expect(validateRecord()).To(BeNil())
+			Expect(bak.Validation.Status).To(Equal(model.ValidationStatusValid))
+			Expect(bak.Validation.Errors).To(BeEmpty())
+		})
+
+		It("invalid", func() {
+			as.EXPECT().Validate(gomock.Any()).Return(&model.ValidateOut{Valid: false, Msg: "corrupted"}, nil).Times(2)
+			ls.EXPECT().WriteByJSON("backup", bak).Return(nil)
+
+			Expect(validateRecord()).NotTo(BeNil())
+			Expect(bak.Validation.Status).To(Equal(model.ValidationStatusInvalid))
+			Expect(bak.Validation.Errors).To(HaveLen(4))
+		})
+	})
+})
@@ -33,8 +33,11 @@ type agentServer struct {
 
 	_apiBackup      string
 	_apiRestore     string
+	_apiRestoreDB   string
 	_apiShowDetail  string
 	_apiShowList    string
+	_apiValidate    string
+	_apiMerge       string
 	_apiDiskspace   string
 	_apiHealthCheck string
 }
@@ -44,9 +47,12 @@ type IAgentServer interface {
 	Backup(in *model.BackupIn) (string, error)
 	DeleteBackup(in *model.DeleteBackupIn) error
 	Restore(in *model.RestoreIn) error
+	RestoreDatabase(in *model.RestoreIn) error
 	ShowDetail(in *model.ShowDetailIn) (*model.BackupInfo, error)
 	ShowList(in *model.ShowListIn) ([]model.BackupInfo, error)
 	ShowDiskSpace(in *model.DiskSpaceIn) (*model.DiskSpaceInfo, error)
+	Validate(in *model.ValidateIn) (*model.ValidateOut, error)
+	Merge(in *model.MergeIn) (*model.BackupInfo, error)
 }
 
 var _ IAgentServer = (*agentServer)(nil)
@@ -57,8 +63,11 @@ func NewAgentServer(addr string) IAgentServer {
 
 		_apiBackup:      "/api/backup",
 		_apiRestore:     "/api/restore",
+		_apiRestoreDB:   "/api/restore/database",
 		_apiShowDetail:  "/api/show",
 		_apiShowList:    "/api/show/list",
+		_apiValidate:    "/api/validate",
+		_apiMerge:       "/api/merge",
 		_apiDiskspace:   "/api/diskspace",
 		_apiHealthCheck: "/api/healthz",
 	}
@@ -126,6 +135,26 @@ func (as *agentServer) Restore(in *model.RestoreIn) error {
 	return nil
 }
 
+// RestoreDatabase restores the database in.DBName only, openGauss keeps running
+// nolint:dupl
+func (as *agentServer) RestoreDatabase(in *model.RestoreIn) error {
+	url := fmt.Sprintf("%s%s", as.addr, as._apiRestoreDB)
+
+	out := &model.RestoreResp{}
+	r := httputils.NewRequest(context.Background(), http.MethodPost, url)
+	r.Body(in)
+
+	if err := r.Send(out); err != nil {
+		return xerr.NewHTTPRawRequestErr(errors.Unwrap(err))
+	}
+
+	if out.Code != 0 {
+		return xerr.NewAgentServerErr(out.Code, out.Msg)
+	}
+
+	return nil
+}
+
 func (as *agentServer) ShowDetail(in *model.ShowDetailIn) (*model.BackupInfo, error) {
 	url := fmt.Sprintf("%s%s", as.addr, as._apiShowDetail)
 
@@ -198,3 +227,41 @@ func (as *agentServer) DeleteBackup(in *model.DeleteBackupIn) error {
 
 	return nil
 }
+
+// Validate validates the backup files on the data node, it returns after the validation is done
+func (as *agentServer) Validate(in *model.ValidateIn) (*model.ValidateOut, error) {
+	url := fmt.Sprintf("%s%s", as.addr, as._apiValidate)
+
+	out := &model.ValidateResp{}
+	r := httputils.NewRequest(context.Background(), http.MethodPost, url)
+	r.Body(in)
+
+	if err := r.Send(out); err != nil {
+		return nil, xerr.NewHTTPRawRequestErr(errors.Unwrap(err))
+	}
+
+	if out.Code != 0 {
+		return nil, xerr.NewAgentServerErr(out.Code, out.Msg)
+	}
+
+	return &out.Data, nil
+}
+
+// Merge merges the PTRACK backup with its parents on the data node, it returns the merged FULL backup
+func (as *agentServer) Merge(in *model.MergeIn) (*model.BackupInfo, error) {
+	url := fmt.Sprintf("%s%s", as.addr, as._apiMerge)
+
+	out := &model.BackupDetailResp{}
+	r := httputils.NewRequest(context.Background(), http.MethodPost, url)
+	r.Body(in)
+
+	if err := r.Send(out); err != nil {
+		return nil, xerr.NewHTTPRawRequestErr(errors.Unwrap(err))
+	}
+
+	if out.Code != 0 {
+		return nil, xerr.NewAgentServerErr(out.Code, out.Msg)
+	}
+
+	return &out.Data, nil
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/s3util"
+
+	strutil "github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/stringutil"
+)
+
+const (
+	CatalogSchemeFile = "file"
+	CatalogSchemeS3   = "s3"
+)
+
+// ErrCatalogConflict is returned if a backup record is changed by others since it is read or written
+var ErrCatalogConflict = errors.New("the backup record is changed by others, please try again")
+
+/*
+NewCatalog returns the backup catalog of the url
+
+	empty: the local catalog under DefaultRootDir()
+	file:///path/to/dir or /path/to/dir: the local catalog under the dir
+	s3://bucket/prefix?endpoint=http://127.0.0.1:9000&region=us-east-1: the catalog in an S3 compatible object storage,
+	  the credentials are read from the environment variables AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN,
+	  it is on AWS S3 if the endpoint is not set.
+*/
+func NewCatalog(rawURL string) (ILocalStorage, error) {
+	if rawURL == "" {
+		return NewLocalStorage(DefaultRootDir())
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("invalid catalog url: %s, err: %s", rawURL, err))
+	}
+
+	switch u.Scheme {
+	case "", CatalogSchemeFile:
+		if u.Path == "" {
+			return nil, xerr.NewCliErr(fmt.Sprintf("invalid catalog url: %s, dir is empty", rawURL))
+		}
+		return NewLocalStorage(strings.TrimSuffix(u.Path, "/"))
+	case CatalogSchemeS3:
+		client, err := s3util.NewClient(&s3util.Config{
+			Endpoint:        u.Query().Get("endpoint"),
+			Region:          u.Query().Get("region"),
+			Bucket:          u.Host,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		})
+		if err != nil {
+			return nil, xerr.NewCliErr(fmt.Sprintf("invalid catalog url: %s, err: %s", rawURL, err))
+		}
+		return NewS3Storage(client, strings.Trim(u.Path, "/")), nil
+	default:
+		return nil, xerr.NewCliErr(fmt.Sprintf("unsupported catalog url: %s", rawURL))
+	}
+}
+
+func readByCSN(c ILocalStorage, csn string) (*model.LsBackup, error) {
+	list, err := c.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		if v.Info.CSN == csn {
+			return v, nil
+		}
+	}
+	return nil, xerr.NewCliErr(xerr.NotFound)
+}
+
+func readAllByCSN(c ILocalStorage, csn string) ([]*model.LsBackup, error) {
+	baks := []*model.LsBackup{}
+	list, err := c.ReadAll()
+	if err != nil {
+		return baks, err
+	}
+	for _, v := range list {
+		c := v
+		if v.Info.CSN == csn {
+			baks = append(baks, c)
+		}
+	}
+
+	return baks, nil
+}
+
+func readByID(c ILocalStorage, id string) (*model.LsBackup, error) {
+	list, err := c.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		if v.Info.ID == id {
+			return v, nil
+		}
+	}
+	return nil, xerr.NewCliErr(xerr.NotFound)
+}
+
+func genFilename(extn Extension) string {
+	prefix := time.Now().UTC().Format("20060102150405")
+	suffix := strutil.Random(8)
+
+	switch extn {
+	case ExtnJSON:
+		return fmt.Sprintf("%s_%s.json", prefix, suffix)
+	default:
+		return fmt.Sprintf("%s_%s", prefix, suffix)
+	}
+}
@@ -0,0 +1,248 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/s3util"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// memBucket is an in-memory bucket, the ETag of an object is its version
+type memBucket struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	versions map[string]int
+}
+
+func newMemBucket() *memBucket {
+	return &memBucket{objects: map[string][]byte{}, versions: map[string]int{}}
+}
+
+func (b *memBucket) GetObject(_ context.Context, key string) ([]byte, string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, "", s3util.ErrNotFound
+	}
+	return data, fmt.Sprint(b.versions[key]), nil
+}
+
+func (b *memBucket) PutObject(_ context.Context, key string, data []byte, opts *s3util.PutOptions) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, exists := b.objects[key]
+	if opts != nil && opts.IfNoneMatch == "*" && exists {
+		return "", s3util.ErrPreconditionFailed
+	}
+	if opts != nil && opts.IfMatch != "" && (!exists || opts.IfMatch != fmt.Sprint(b.versions[key])) {
+		return "", s3util.ErrPreconditionFailed
+	}
+	b.objects[key] = data
+	b.versions[key]++
+	return fmt.Sprint(b.versions[key]), nil
+}
+
+func (b *memBucket) DeleteObject(_ context.Context, key string, opts *s3util.DeleteOptions) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, exists := b.objects[key]
+	if !exists {
+		return s3util.ErrNotFound
+	}
+	if opts != nil && opts.IfMatch != "" && opts.IfMatch != fmt.Sprint(b.versions[key]) {
+		return s3util.ErrPreconditionFailed
+	}
+	delete(b.objects, key)
+	return nil
+}
+
+func (b *memBucket) ListObjects(_ context.Context, prefix string) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	keys := []string{}
+	for k := range b.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func newTestBackup(id, csn string) *model.LsBackup {
+	return &model.LsBackup{
+		Info:     &model.BackupMetaInfo{ID: id, CSN: csn},
+		SsBackup: &model.SsBackup{Status: model.SsBackupStatusWaiting},
+	}
+}
+
+var _ = Describe("Catalog", func() {
+	Context("NewCatalog", func() {
+		It("should return the local catalog", func() {
+			root, err := os.MkdirTemp("", "gs_pitr")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(root)
+
+			c, err := NewCatalog(fmt.Sprintf("file://%s", root))
+			Expect(err).To(BeNil())
+			Expect(c.(*localStorage).backupDir).To(Equal(fmt.Sprintf("%s/backup", root)))
+		})
+
+		It("should return the s3 catalog", func() {
+			os.Setenv("AWS_ACCESS_KEY_ID", "minio")
+			os.Setenv("AWS_SECRET_ACCESS_KEY", "minio123")
+			defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+			defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+
+			c, err := NewCatalog("s3://pitr/cluster-0/?endpoint=http://127.0.0.1:9000")
+			Expect(err).To(BeNil())
+			Expect(c.(*s3Storage).backupDir).To(Equal("cluster-0/backup"))
+		})
+
+		It("should return error without the credentials or with unknown scheme", func() {
+			_, err := NewCatalog("s3://pitr/?endpoint=http://127.0.0.1:9000")
+			Expect(err).NotTo(BeNil())
+			_, err = NewCatalog("ftp://127.0.0.1/backup")
+			Expect(err.Error()).To(ContainSubstring("unsupported catalog url"))
+		})
+	})
+
+	Context("s3Storage", func() {
+		var bucket *memBucket
+
+		BeforeEach(func() {
+			bucket = newMemBucket()
+		})
+
+		It("should write, read and delete the records", func() {
+			c := NewS3Storage(bucket, "cluster-0")
+			name := c.GenFilename(ExtnJSON)
+			Expect(c.WriteByJSON(name, newTestBackup("id-0", "csn-0"))).To(Succeed())
+			Expect(bucket.objects).To(HaveKey(fmt.Sprintf("cluster-0/backup/%s", name)))
+
+			// a catalog on another machine
+			other := NewS3Storage(bucket, "cluster-0")
+			bak, err := other.ReadByID("id-0")
+			Expect(err).To(BeNil())
+			Expect(bak.Info.CSN).To(Equal("csn-0"))
+			Expect(bak.Info.FileName).To(Equal(name))
+
+			baks, err := other.ReadAllByCSN("csn-0")
+			Expect(err).To(BeNil())
+			Expect(baks).To(HaveLen(1))
+
+			Expect(other.HideByName(name)).To(Succeed())
+			list, err := c.ReadAll()
+			Expect(err).To(BeNil())
+			Expect(list).To(BeEmpty())
+
+			Expect(other.DeleteByHidedName(name)).To(Succeed())
+			Expect(bucket.objects).To(BeEmpty())
+		})
+
+		It("should not overwrite the records changed by others", func() {
+			c := NewS3Storage(bucket, "")
+			name := c.GenFilename(ExtnJSON)
+			bak := newTestBackup("id-0", "csn-0")
+			Expect(c.WriteByJSON(name, bak)).To(Succeed())
+
+			other := NewS3Storage(bucket, "")
+			_, err := other.ReadByID("id-0")
+			Expect(err).To(BeNil())
+			bak.SsBackup.Status = model.SsBackupStatusCompleted
+			Expect(other.WriteByJSON(name, bak)).To(Succeed())
+
+			// the etag of c is stale
+			err = c.WriteByJSON(name, bak)
+			Expect(errors.Is(err, ErrCatalogConflict)).To(BeTrue())
+			err = c.HideByName(name)
+			Expect(errors.Is(err, ErrCatalogConflict)).To(BeTrue())
+
+			// a new record never overwrites an existing one
+			err = NewS3Storage(bucket, "").WriteByJSON(name, bak)
+			Expect(errors.Is(err, ErrCatalogConflict)).To(BeTrue())
+
+			// the record changed by others is not deleted
+			err = c.DeleteByName(name)
+			Expect(errors.Is(err, ErrCatalogConflict)).To(BeTrue())
+			Expect(bucket.objects).To(HaveLen(1))
+			Expect(other.DeleteByName(name)).To(Succeed())
+			Expect(bucket.objects).To(BeEmpty())
+		})
+
+		It("should not overwrite the retention policy changed by others", func() {
+			c := NewS3Storage(bucket, "")
+			r, err := c.ReadRetention()
+			Expect(err).To(BeNil())
+			Expect(r.KeepLast).To(BeZero())
+
+			other := NewS3Storage(bucket, "")
+			Expect(other.WriteRetention(&model.Retention{KeepLast: 3})).To(Succeed())
+			Expect(other.WriteRetention(&model.Retention{KeepLast: 5})).To(Succeed())
+
+			// the policy read by c is stale
+			err = c.WriteRetention(&model.Retention{KeepLast: 1})
+			Expect(errors.Is(err, ErrCatalogConflict)).To(BeTrue())
+			r, err = NewS3Storage(bucket, "").ReadRetention()
+			Expect(err).To(BeNil())
+			Expect(r.KeepLast).To(Equal(5))
+		})
+	})
+
+	Context("localStorage", func() {
+		It("should not overwrite the records changed by others", func() {
+			root, err := os.MkdirTemp("", "gs_pitr")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(root)
+
+			c, err := NewLocalStorage(root)
+			Expect(err).To(BeNil())
+			name := c.GenFilename(ExtnJSON)
+			bak := newTestBackup("id-0", "csn-0")
+			Expect(c.WriteByJSON(name, bak)).To(Succeed())
+
+			other, err := NewLocalStorage(root)
+			Expect(err).To(BeNil())
+			err = other.WriteByJSON(name, bak)
+			Expect(errors.Is(err, ErrCatalogConflict)).To(BeTrue())
+
+			_, err = other.ReadByID("id-0")
+			Expect(err).To(BeNil())
+			bak.SsBackup.Status = model.SsBackupStatusCompleted
+			Expect(other.WriteByJSON(name, bak)).To(Succeed())
+
+			err = c.WriteByJSON(name, bak)
+			Expect(errors.Is(err, ErrCatalogConflict)).To(BeTrue())
+			err = c.HideByName(name)
+			Expect(errors.Is(err, ErrCatalogConflict)).To(BeTrue())
+			Expect(other.HideByName(name)).To(Succeed())
+		})
+	})
+})
@@ -18,12 +18,14 @@
 package pkg
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
 	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
@@ -35,8 +37,13 @@ type (
 	localStorage struct {
 		rootDir   string
 		backupDir string
+
+		mu sync.Mutex
+		// versions are the sha256 of the backup files read or written, which are checked before the files are changed
+		versions map[string]string
 	}
 
+	// ILocalStorage is the catalog of the backup records, see NewCatalog
 	ILocalStorage interface {
 		WriteByJSON(name string, contents *model.LsBackup) error
 		GenFilename(extn Extension) string
@@ -47,6 +54,9 @@ type (
 		DeleteByName(name string) error
 		HideByName(name string) error
 		DeleteByHidedName(name string) error
+		// ReadRetention returns an empty policy if the retention policy is not set
+		ReadRetention() (*model.Retention, error)
+		WriteRetention(r *model.Retention) error
 	}
 
 	Extension string
@@ -54,12 +64,15 @@ type (
 
 const (
 	ExtnJSON Extension = "JSON"
+
+	retentionFilename = "retention.json"
 )
 
 func NewLocalStorage(root string) (ILocalStorage, error) {
 	ls := &localStorage{
 		rootDir:   root,
 		backupDir: fmt.Sprintf("%s/%s", root, "backup"),
+		versions:  map[string]string{},
 	}
 
 	if err := ls.init(); err != nil {
@@ -109,6 +122,7 @@ func (ls *localStorage) init() error {
 	return nil
 }
 
+// WriteByJSON creates the backup file, or updates it if it is not changed by others since it is read or written
 func (ls *localStorage) WriteByJSON(name string, contents *model.LsBackup) error {
 	if !strings.HasSuffix(name, ".json") {
 		return fmt.Errorf("wrong file extension. file name: %s", name)
@@ -119,20 +133,55 @@ func (ls *localStorage) WriteByJSON(name string, contents *model.LsBackup) error
 		return err
 	}
 
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
 	path := fmt.Sprintf("%s/%s", ls.backupDir, name)
-	fi, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("create file failure. file path: %s", path)
+	version, known := ls.versions[name]
+	if !known {
+		// the new file must not exist
+		fi, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if os.IsExist(err) {
+			return fmt.Errorf("file %s already exists: %w", name, ErrCatalogConflict)
+		}
+		if err != nil {
+			return fmt.Errorf("create file failure. file path: %s", path)
+		}
+		defer fi.Close()
+
+		if _, err := fi.Write(data); err != nil {
+			return fmt.Errorf("write to file failure. err: %s, data: %s", err, data)
+		}
+		ls.versions[name] = versionOf(data)
+		return nil
 	}
 
-	_, err = fi.Write(data)
+	current, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("write to file failure. err: %s, data: %s", err, data)
+		return fmt.Errorf("read file %s failed: %s: %w", name, err, ErrCatalogConflict)
+	}
+	if versionOf(current) != version {
+		return fmt.Errorf("file %s is changed: %w", name, ErrCatalogConflict)
 	}
 
+	// replace the file at once, so the readers never see a partial file
+	tmp := fmt.Sprintf("%s/.%s.%s", ls.backupDir, name, strutil.Random(8))
+	if err := os.WriteFile(tmp, data, 0666); err != nil {
+		return fmt.Errorf("write to file failure. err: %s, data: %s", err, data)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace file failure. file path: %s, err: %s", path, err)
+	}
+	ls.versions[name] = versionOf(data)
 	return nil
 }
 
+func versionOf(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
 func (ls *localStorage) ReadAll() ([]*model.LsBackup, error) {
 	entries, err := os.ReadDir(ls.backupDir)
 	if err != nil {
@@ -172,50 +221,24 @@ func (ls *localStorage) ReadAll() ([]*model.LsBackup, error) {
 		}
 		b.Info.FileName = info.Name()
 		backups = append(backups, b)
+
+		ls.mu.Lock()
+		ls.versions[info.Name()] = versionOf(file)
+		ls.mu.Unlock()
 	}
 	return backups, nil
 }
 
 func (ls *localStorage) ReadByCSN(csn string) (*model.LsBackup, error) {
-	list, err := ls.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-	for _, v := range list {
-		if v.Info.CSN == csn {
-			return v, nil
-		}
-	}
-	return nil, xerr.NewCliErr(xerr.NotFound)
+	return readByCSN(ls, csn)
 }
 
 func (ls *localStorage) ReadAllByCSN(csn string) ([]*model.LsBackup, error) {
-	baks := []*model.LsBackup{}
-	list, err := ls.ReadAll()
-	if err != nil {
-		return baks, err
-	}
-	for _, v := range list {
-		c := v
-		if v.Info.CSN == csn {
-			baks = append(baks, c)
-		}
-	}
-
-	return baks, nil
+	return readAllByCSN(ls, csn)
 }
 
 func (ls *localStorage) ReadByID(id string) (*model.LsBackup, error) {
-	list, err := ls.ReadAll()
-	if err != nil {
-		return nil, err
-	}
-	for _, v := range list {
-		if v.Info.ID == id {
-			return v, nil
-		}
-	}
-	return nil, xerr.NewCliErr(xerr.NotFound)
+	return readByID(ls, id)
 }
 
 /*
@@ -225,15 +248,7 @@ GenFilename gen a filename based on the file extension
 	if extn=JSON,return the JSON filename like **.json
 */
 func (ls *localStorage) GenFilename(extn Extension) string {
-	prefix := time.Now().UTC().Format("20060102150405")
-	suffix := strutil.Random(8)
-
-	switch extn {
-	case ExtnJSON:
-		return fmt.Sprintf("%s_%s.json", prefix, suffix)
-	default:
-		return fmt.Sprintf("%s_%s", prefix, suffix)
-	}
+	return genFilename(extn)
 }
 
 type mode int
@@ -248,6 +263,10 @@ func (ls *localStorage) deleteByName(name string, mode mode) error {
 	if err := os.Remove(path); err != nil {
 		return xerr.NewCliErr(fmt.Sprintf("delete file failed. err: %s", err))
 	}
+
+	ls.mu.Lock()
+	delete(ls.versions, name)
+	ls.mu.Unlock()
 	return nil
 }
 
@@ -259,11 +278,48 @@ func (ls *localStorage) DeleteByHidedName(name string) error {
 	return ls.deleteByName(fmt.Sprintf(".%s", name), hided)
 }
 
+func (ls *localStorage) ReadRetention() (*model.Retention, error) {
+	r := &model.Retention{}
+	file, err := os.ReadFile(fmt.Sprintf("%s/%s", ls.rootDir, retentionFilename))
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("read retention policy failed. err: %s", err))
+	}
+	if err := json.Unmarshal(file, r); err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("invalid retention policy. err: %s", err))
+	}
+	return r, nil
+}
+
+func (ls *localStorage) WriteRetention(r *model.Retention) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(fmt.Sprintf("%s/%s", ls.rootDir, retentionFilename), data, 0600); err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("write retention policy failed. err: %s", err))
+	}
+	return nil
+}
+
+// HideByName hides the backup file if it is not changed by others since it is read or written
 func (ls *localStorage) HideByName(name string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
 	path := fmt.Sprintf("%s/%s", ls.backupDir, name)
 	hided := fmt.Sprintf("%s/.%s", ls.backupDir, name)
+	if version, ok := ls.versions[name]; ok {
+		current, err := os.ReadFile(path)
+		if err != nil || versionOf(current) != version {
+			return fmt.Errorf("hide file %s failed: %w", name, ErrCatalogConflict)
+		}
+	}
 	if err := os.Rename(path, hided); err != nil {
 		return xerr.NewCliErr(fmt.Sprintf("hide file failed. err: %s", err))
 	}
+	delete(ls.versions, name)
 	return nil
 }
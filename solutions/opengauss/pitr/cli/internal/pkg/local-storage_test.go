@@ -143,5 +143,20 @@ var _ = Describe("ILocalStorage", func() {
 			Expect(err).To(BeNil())
 			Expect(bak).NotTo(BeNil())
 		})
+
+		It("ReadRetention and WriteRetention", func() {
+			root := fmt.Sprintf("%s/%s", os.Getenv("HOME"), ".gs_pitr")
+			ls, err := NewLocalStorage(root)
+			Expect(err).To(BeNil())
+
+			r, err := ls.ReadRetention()
+			Expect(err).To(BeNil())
+			Expect(r.Empty()).To(BeTrue())
+
+			Expect(ls.WriteRetention(&model.Retention{KeepLast: 7, KeepWithin: "30d"})).To(Succeed())
+			r, err = ls.ReadRetention()
+			Expect(err).To(BeNil())
+			Expect(r).To(Equal(&model.Retention{KeepLast: 7, KeepWithin: "30d"}))
+		})
 	})
 })
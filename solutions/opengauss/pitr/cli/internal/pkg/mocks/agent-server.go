@@ -77,6 +77,21 @@ func (mr *MockIAgentServerMockRecorder) DeleteBackup(in interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBackup", reflect.TypeOf((*MockIAgentServer)(nil).DeleteBackup), in)
 }
 
+// Merge mocks base method.
+func (m *MockIAgentServer) Merge(in *model.MergeIn) (*model.BackupInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", in)
+	ret0, _ := ret[0].(*model.BackupInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockIAgentServerMockRecorder) Merge(in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockIAgentServer)(nil).Merge), in)
+}
+
 // Restore mocks base method.
 func (m *MockIAgentServer) Restore(in *model.RestoreIn) error {
 	m.ctrl.T.Helper()
@@ -91,6 +106,20 @@ func (mr *MockIAgentServerMockRecorder) Restore(in interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockIAgentServer)(nil).Restore), in)
 }
 
+// RestoreDatabase mocks base method.
+func (m *MockIAgentServer) RestoreDatabase(in *model.RestoreIn) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDatabase", in)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreDatabase indicates an expected call of RestoreDatabase.
+func (mr *MockIAgentServerMockRecorder) RestoreDatabase(in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDatabase", reflect.TypeOf((*MockIAgentServer)(nil).RestoreDatabase), in)
+}
+
 // ShowDetail mocks base method.
 func (m *MockIAgentServer) ShowDetail(in *model.ShowDetailIn) (*model.BackupInfo, error) {
 	m.ctrl.T.Helper()
@@ -135,3 +164,18 @@ func (mr *MockIAgentServerMockRecorder) ShowList(in interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowList", reflect.TypeOf((*MockIAgentServer)(nil).ShowList), in)
 }
+
+// Validate mocks base method.
+func (m *MockIAgentServer) Validate(in *model.ValidateIn) (*model.ValidateOut, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", in)
+	ret0, _ := ret[0].(*model.ValidateOut)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockIAgentServerMockRecorder) Validate(in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockIAgentServer)(nil).Validate), in)
+}
@@ -163,3 +163,32 @@ func (mr *MockILocalStorageMockRecorder) DeleteByHidedName(name interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByHidedName", reflect.TypeOf((*MockILocalStorage)(nil).DeleteByHidedName), name)
 }
+
+// ReadRetention mocks base method
+func (m *MockILocalStorage) ReadRetention() (*model.Retention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRetention")
+	ret0, _ := ret[0].(*model.Retention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRetention indicates an expected call of ReadRetention
+func (mr *MockILocalStorageMockRecorder) ReadRetention() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRetention", reflect.TypeOf((*MockILocalStorage)(nil).ReadRetention))
+}
+
+// WriteRetention mocks base method
+func (m *MockILocalStorage) WriteRetention(r *model.Retention) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteRetention", r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteRetention indicates an expected call of WriteRetention
+func (mr *MockILocalStorageMockRecorder) WriteRetention(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteRetention", reflect.TypeOf((*MockILocalStorage)(nil).WriteRetention), r)
+}
@@ -81,10 +81,10 @@ func (mr *MockIShardingSphereProxyMockRecorder) ExportMetaData() *gomock.Call {
 }
 
 // ExportStorageNodes mocks base method.
-func (m *MockIShardingSphereProxy) ExportStorageNodes() ([]*model.StorageNode, error) {
+func (m *MockIShardingSphereProxy) ExportStorageNodes() (*model.StorageNodesInfo, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ExportStorageNodes")
-	ret0, _ := ret[0].([]*model.StorageNode)
+	ret0, _ := ret[0].(*model.StorageNodesInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -17,6 +17,8 @@
 
 package model
 
+const RecoveryTargetTimeLayout = "2006-01-02 15:04:05-07:00"
+
 type (
 	RestoreIn struct {
 		DBPort       uint16 `json:"db_port"`
@@ -27,6 +29,16 @@ type (
 		DnBackupPath string `json:"dn_backup_path"`
 		DnBackupID   string `json:"dn_backup_id"`
 		DnThreadsNum uint8  `json:"dn_threads_num"`
+
+		*RecoveryTarget
+	}
+
+	// RecoveryTarget is the point to replay the WAL to after the backup is restored, only one of them is set
+	RecoveryTarget struct {
+		// Time is like `2023-03-01 12:00:00+08:00`, see RecoveryTargetTimeLayout
+		Time string `json:"target_time,omitempty"`
+		CSN  string `json:"target_csn,omitempty"`
+		LSN  string `json:"target_lsn,omitempty"`
 	}
 
 	RestoreResp struct {
@@ -38,13 +38,17 @@ type (
 	}
 
 	BackupInfo struct {
-		ID        string       `json:"dn_backup_id"`
-		Path      string       `json:"dn_backup_path"`
-		Mode      string       `json:"db_backup_mode"`
-		Instance  string       `json:"instance"`
-		StartTime string       `json:"start_time"`
-		EndTime   string       `json:"end_time"`
-		Status    BackupStatus `json:"status"`
+		ID         string       `json:"dn_backup_id"`
+		Path       string       `json:"dn_backup_path"`
+		Mode       string       `json:"db_backup_mode"`
+		ParentID   string       `json:"parent_backup_id"`
+		Instance   string       `json:"instance"`
+		StartTime  string       `json:"start_time"`
+		EndTime    string       `json:"end_time"`
+		StartLsn   string       `json:"start_lsn"`
+		StopLsn    string       `json:"stop_lsn"`
+		Status     BackupStatus `json:"status"`
+		ContentCrc int64        `json:"content_crc"`
 	}
 
 	BackupDetailResp struct {
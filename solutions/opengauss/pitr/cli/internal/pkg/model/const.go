@@ -27,7 +27,15 @@ const (
 	SsBackupStatusFailed     BackupStatus = "Failed"
 	SsBackupStatusCheckError BackupStatus = "CheckError"
 	SsBackupStatusCanceled   BackupStatus = "Canceled"
+	SsBackupStatusCorrupt    BackupStatus = "Corrupt"
 
 	DBBackModeFull   DBBackupMode = "FULL"
 	DBBackModePTrack DBBackupMode = "PTRACK"
 )
+
+type ValidationStatus string
+
+const (
+	ValidationStatusValid   ValidationStatus = "Valid"
+	ValidationStatusInvalid ValidationStatus = "Invalid"
+)
@@ -23,6 +23,8 @@ type (
 		Info     *BackupMetaInfo `json:"info"`
 		DnList   []*DataNode     `json:"dn_list"`
 		SsBackup *SsBackup       `json:"ss_backup"`
+		// Validation is the verdict of the last `validate`, nil if the backup is never validated
+		Validation *Validation `json:"validation,omitempty"`
 	}
 
 	BackupMetaInfo struct {
@@ -35,12 +37,26 @@ type (
 	}
 
 	DataNode struct {
-		IP        string       `json:"ip"`
-		Port      uint16       `json:"port"`
-		Status    BackupStatus `json:"status"`
-		BackupID  string       `json:"backup_id"`
-		StartTime string       `json:"start_time"`
-		EndTime   string       `json:"end_time"`
+		IP       string       `json:"ip"`
+		Port     uint16       `json:"port"`
+		Status   BackupStatus `json:"status"`
+		BackupID string       `json:"backup_id"`
+		// BackupMode is FULL if the data node has no valid parent for a PTRACK backup
+		BackupMode     DBBackupMode `json:"backup_mode,omitempty"`
+		ParentBackupID string       `json:"parent_backup_id,omitempty"`
+		StartTime      string       `json:"start_time"`
+		EndTime        string       `json:"end_time"`
+		StartLsn       string       `json:"start_lsn,omitempty"`
+		StopLsn        string       `json:"stop_lsn,omitempty"`
+		// ContentCrc is reported by gs_probackup when the backup is done, it changes if the backup files change
+		ContentCrc int64 `json:"content_crc,omitempty"`
+	}
+
+	Validation struct {
+		Status ValidationStatus `json:"status"`
+		Time   string           `json:"time"`
+		// Errors are the reasons why the backup is invalid
+		Errors []string `json:"errors,omitempty"`
 	}
 )
 
@@ -49,6 +65,8 @@ type (
 		Status       BackupStatus   `json:"status"`
 		ClusterInfo  *ClusterInfo   `json:"cluster_info"`
 		StorageNodes []*StorageNode `json:"storage_nodes"`
+		// LogicDatabases are the storage nodes of each logic database, the Database of a node is the physical database
+		LogicDatabases map[string][]*StorageNode `json:"logic_databases,omitempty"`
 	}
 
 	StorageNode struct {
@@ -64,3 +82,18 @@ type (
 		StorageNodes map[string][]*StorageNode `json:"storage_nodes"`
 	}
 )
+
+type (
+	// TargetMap remaps the storage nodes of the backup to the ones of another cluster
+	TargetMap struct {
+		StorageNodes []*TargetStorageNode `json:"storage_nodes"`
+	}
+
+	TargetStorageNode struct {
+		// Source is the `ip:port` of the storage node in the backup
+		Source    string `json:"source"`
+		IP        string `json:"ip"`
+		Port      uint16 `json:"port"`
+		AgentPort uint16 `json:"agent_port,omitempty"`
+	}
+)
@@ -0,0 +1,71 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retention is the retention policy of the backup records kept in the catalog, a record is retained if any rule keeps it.
+type Retention struct {
+	// KeepLast keeps the last n records
+	KeepLast int `json:"keep_last,omitempty"`
+	// KeepWithin keeps the records started within the duration like `72h` or `30d`
+	KeepWithin string `json:"keep_within,omitempty"`
+	// KeepDaily, KeepWeekly and KeepMonthly keep the last record of each of the last n days, weeks and months
+	KeepDaily   int `json:"keep_daily,omitempty"`
+	KeepWeekly  int `json:"keep_weekly,omitempty"`
+	KeepMonthly int `json:"keep_monthly,omitempty"`
+}
+
+// Empty returns true if no rule is set, nothing should be pruned by an empty policy
+func (r *Retention) Empty() bool {
+	return r == nil || *r == Retention{}
+}
+
+// Within parses KeepWithin, which supports the day unit `d` besides the units of time.ParseDuration
+func (r *Retention) Within() (time.Duration, error) {
+	if r.KeepWithin == "" {
+		return 0, nil
+	}
+
+	if days, ok := strings.CutSuffix(r.KeepWithin, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid keep within: %s", r.KeepWithin)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(r.KeepWithin)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid keep within: %s", r.KeepWithin)
+	}
+	return d, nil
+}
+
+func (r *Retention) Validate() error {
+	if r.KeepLast < 0 || r.KeepDaily < 0 || r.KeepWeekly < 0 || r.KeepMonthly < 0 {
+		return fmt.Errorf("the number of the records to keep should not be negative")
+	}
+	_, err := r.Within()
+	return err
+}
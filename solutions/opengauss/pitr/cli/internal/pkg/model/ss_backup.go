@@ -17,6 +17,11 @@
 
 package model
 
+import (
+	"fmt"
+	"sort"
+)
+
 type (
 	SsBackupInfo struct {
 		ClusterInfo ClusterInfo `json:"cluster_info"`
@@ -38,3 +43,29 @@ type (
 		CreateTime string `json:"create_time"`
 	}
 )
+
+// Nodes returns all the storage nodes without the duplicate ones
+func (info *StorageNodesInfo) Nodes() []*StorageNode {
+	// sort the databases to keep the order of the nodes
+	databases := make([]string, 0, len(info.StorageNodes))
+	for k := range info.StorageNodes {
+		databases = append(databases, k)
+	}
+	sort.Strings(databases)
+
+	var (
+		storageNodes []*StorageNode
+		tmpNodesMap  = make(map[string]struct{})
+	)
+	for _, db := range databases {
+		for _, node := range info.StorageNodes[db] {
+			// filter duplicate nodes
+			if _, ok := tmpNodesMap[fmt.Sprintf("%s:%d", node.IP, node.Port)]; ok {
+				continue
+			}
+			tmpNodesMap[fmt.Sprintf("%s:%d", node.IP, node.Port)] = struct{}{}
+			storageNodes = append(storageNodes, node)
+		}
+	}
+	return storageNodes
+}
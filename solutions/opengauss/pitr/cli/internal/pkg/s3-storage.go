@@ -0,0 +1,253 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/model"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/internal/pkg/xerr"
+	"github.com/apache/shardingsphere-on-cloud/pitr/cli/pkg/s3util"
+)
+
+// s3Storage keeps the backup records as the objects `<prefix>/backup/<name>` in a bucket, and the retention policy as `<prefix>/retention.json`.
+// The objects are written and deleted with the ETags read or written, so the objects changed by others are never overwritten.
+type s3Storage struct {
+	client       s3util.IClient
+	backupDir    string
+	retentionKey string
+
+	mu sync.Mutex
+	// etags are the ETags of the objects by key, the ETag is empty if the object does not exist
+	etags map[string]string
+}
+
+func NewS3Storage(client s3util.IClient, prefix string) ILocalStorage {
+	backupDir, retentionKey := "backup", retentionFilename
+	if prefix != "" {
+		backupDir = fmt.Sprintf("%s/%s", prefix, backupDir)
+		retentionKey = fmt.Sprintf("%s/%s", prefix, retentionKey)
+	}
+	return &s3Storage{
+		client:       client,
+		backupDir:    backupDir,
+		retentionKey: retentionKey,
+		etags:        map[string]string{},
+	}
+}
+
+func (ss *s3Storage) key(name string) string {
+	return fmt.Sprintf("%s/%s", ss.backupDir, name)
+}
+
+func (ss *s3Storage) put(key string, data []byte) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	opts := &s3util.PutOptions{IfNoneMatch: "*"}
+	if etag := ss.etags[key]; etag != "" {
+		opts = &s3util.PutOptions{IfMatch: etag}
+	}
+	etag, err := ss.client.PutObject(context.Background(), key, data, opts)
+	if errors.Is(err, s3util.ErrPreconditionFailed) {
+		return fmt.Errorf("write object %s failed: %w", key, ErrCatalogConflict)
+	}
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("write object %s failed. err: %s", key, err))
+	}
+	ss.etags[key] = etag
+	return nil
+}
+
+// etag returns the ETag of the object read or written before, or reads the object for it
+func (ss *s3Storage) etag(key string) (string, error) {
+	ss.mu.Lock()
+	etag, ok := ss.etags[key]
+	ss.mu.Unlock()
+	if ok {
+		return etag, nil
+	}
+
+	_, etag, err := ss.client.GetObject(context.Background(), key)
+	if errors.Is(err, s3util.ErrNotFound) {
+		err = nil
+	}
+	if err != nil {
+		return "", xerr.NewCliErr(fmt.Sprintf("read object %s failed. err: %s", key, err))
+	}
+
+	ss.mu.Lock()
+	ss.etags[key] = etag
+	ss.mu.Unlock()
+	return etag, nil
+}
+
+func (ss *s3Storage) WriteByJSON(name string, contents *model.LsBackup) error {
+	if !strings.HasSuffix(name, ".json") {
+		return fmt.Errorf("wrong file extension. file name: %s", name)
+	}
+
+	data, err := json.MarshalIndent(contents, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ss.put(ss.key(name), data)
+}
+
+func (ss *s3Storage) GenFilename(extn Extension) string {
+	return genFilename(extn)
+}
+
+func (ss *s3Storage) ReadAll() ([]*model.LsBackup, error) {
+	keys, err := ss.client.ListObjects(context.Background(), ss.backupDir+"/")
+	if err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("list objects[prefix:%s] failed. err: %s", ss.backupDir, err))
+	}
+
+	backups := make([]*model.LsBackup, 0, len(keys))
+	for _, key := range keys {
+		name := path.Base(key)
+		if key != ss.key(name) || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		data, etag, err := ss.client.GetObject(context.Background(), key)
+		// the record is deleted after it is listed
+		if errors.Is(err, s3util.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, xerr.NewCliErr(fmt.Sprintf("read object %s failed. err: %s", key, err))
+		}
+
+		b := &model.LsBackup{}
+		if err := json.Unmarshal(data, b); err != nil {
+			return nil, xerr.NewCliErr(fmt.Sprintf("invalid contents[object=%s]. err: %s", key, err))
+		}
+		b.Info.FileName = name
+		backups = append(backups, b)
+
+		ss.mu.Lock()
+		ss.etags[key] = etag
+		ss.mu.Unlock()
+	}
+	return backups, nil
+}
+
+func (ss *s3Storage) ReadRetention() (*model.Retention, error) {
+	r := &model.Retention{}
+	data, etag, err := ss.client.GetObject(context.Background(), ss.retentionKey)
+	if errors.Is(err, s3util.ErrNotFound) {
+		ss.mu.Lock()
+		ss.etags[ss.retentionKey] = ""
+		ss.mu.Unlock()
+		return r, nil
+	}
+	if err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("read object %s failed. err: %s", ss.retentionKey, err))
+	}
+	ss.mu.Lock()
+	ss.etags[ss.retentionKey] = etag
+	ss.mu.Unlock()
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, xerr.NewCliErr(fmt.Sprintf("invalid retention policy[object=%s]. err: %s", ss.retentionKey, err))
+	}
+	return r, nil
+}
+
+// WriteRetention replaces the retention policy read before, or the current one if it is not read
+func (ss *s3Storage) WriteRetention(r *model.Retention) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := ss.etag(ss.retentionKey); err != nil {
+		return err
+	}
+	return ss.put(ss.retentionKey, data)
+}
+
+func (ss *s3Storage) ReadByID(id string) (*model.LsBackup, error) {
+	return readByID(ss, id)
+}
+
+func (ss *s3Storage) ReadByCSN(csn string) (*model.LsBackup, error) {
+	return readByCSN(ss, csn)
+}
+
+func (ss *s3Storage) ReadAllByCSN(csn string) ([]*model.LsBackup, error) {
+	return readAllByCSN(ss, csn)
+}
+
+// DeleteByName deletes the record if it is not changed since it is read or written
+func (ss *s3Storage) DeleteByName(name string) error {
+	key := ss.key(name)
+	etag, err := ss.etag(key)
+	if err != nil {
+		return err
+	}
+	if etag != "" {
+		err = ss.client.DeleteObject(context.Background(), key, &s3util.DeleteOptions{IfMatch: etag})
+	}
+	if errors.Is(err, s3util.ErrPreconditionFailed) {
+		return fmt.Errorf("delete object %s failed: %w", name, ErrCatalogConflict)
+	}
+	if err != nil && !errors.Is(err, s3util.ErrNotFound) {
+		return xerr.NewCliErr(fmt.Sprintf("delete object %s failed. err: %s", name, err))
+	}
+
+	ss.mu.Lock()
+	delete(ss.etags, key)
+	ss.mu.Unlock()
+	return nil
+}
+
+// HideByName copies the record to `.<name>` and deletes it. The copy fails if the record is being hidden by others,
+// and the record is checked to be unchanged before it is deleted.
+func (ss *s3Storage) HideByName(name string) error {
+	data, etag, err := ss.client.GetObject(context.Background(), ss.key(name))
+	if err != nil {
+		return xerr.NewCliErr(fmt.Sprintf("hide object %s failed. err: %s", name, err))
+	}
+
+	ss.mu.Lock()
+	known, ok := ss.etags[ss.key(name)]
+	if !ok {
+		ss.etags[ss.key(name)] = etag
+	}
+	ss.mu.Unlock()
+	if ok && known != etag {
+		return fmt.Errorf("hide object %s failed: %w", name, ErrCatalogConflict)
+	}
+
+	hided := fmt.Sprintf(".%s", name)
+	if err := ss.put(ss.key(hided), data); err != nil {
+		return err
+	}
+	return ss.DeleteByName(name)
+}
+
+func (ss *s3Storage) DeleteByHidedName(name string) error {
+	return ss.DeleteByName(fmt.Sprintf(".%s", name))
+}
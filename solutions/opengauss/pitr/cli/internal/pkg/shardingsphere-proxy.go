@@ -35,7 +35,7 @@ type (
 
 	IShardingSphereProxy interface {
 		ExportMetaData() (*model.ClusterInfo, error)
-		ExportStorageNodes() ([]*model.StorageNode, error)
+		ExportStorageNodes() (*model.StorageNodesInfo, error)
 		LockForRestore() error
 		LockForBackup() error
 		Unlock() error
@@ -147,7 +147,7 @@ ExportStorageNodes 导出存储节点数据
 | 734bb036-b15d-4af0-be87-237 | 2023-01-01 12:00:00 897 | {"storage_nodes":{"xx_db":[],"xx2_db":[]}} |
 +-------------------------------------------------------+--------------------------------------------+
 */
-func (ss *shardingSphereProxy) ExportStorageNodes() ([]*model.StorageNode, error) {
+func (ss *shardingSphereProxy) ExportStorageNodes() (*model.StorageNodesInfo, error) {
 	query, err := ss.db.Query(`EXPORT STORAGE NODES;`)
 	if err != nil {
 		return nil, xerr.NewCliErr(fmt.Sprintf("export storage nodes failure. err: %s", err))
@@ -176,25 +176,7 @@ func (ss *shardingSphereProxy) ExportStorageNodes() ([]*model.StorageNode, error
 		return nil, fmt.Errorf("json unmarshal return err: %s", err)
 	}
 
-	// get all storage nodes and filter duplicate nodes
-	var storageNodes []*model.StorageNode
-
-	var tmpNodesMap = make(map[string]struct{})
-
-	for _, v := range out.StorageNodes {
-		for _, vv := range v {
-			// filter duplicate nodes
-			if _, ok := tmpNodesMap[fmt.Sprintf("%s:%d", vv.IP, vv.Port)]; ok {
-				continue
-			}
-
-			tmpNodesMap[fmt.Sprintf("%s:%d", vv.IP, vv.Port)] = struct{}{}
-
-			storageNodes = append(storageNodes, vv)
-		}
-	}
-
-	return storageNodes, nil
+	return out, nil
 }
 
 // ImportMetaData 备份数据恢复
@@ -21,10 +21,12 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 
 	"github.com/google/uuid"
@@ -39,6 +41,12 @@ type req struct {
 	query  map[string]string
 }
 
+var (
+	//nolint:gosec
+	tlsConfig   = &tls.Config{InsecureSkipVerify: true}
+	bearerToken string
+)
+
 type Ireq interface {
 	Header(h map[string]string)
 	Body(b any)
@@ -58,6 +66,41 @@ func NewRequest(ctx context.Context, method, url string) Ireq {
 	return r
 }
 
+// SetTLS sets the CA to verify the servers and the certificate of the client for mutual TLS,
+// the servers are not verified if caFile is empty.
+func SetTLS(caFile, certFile, keyFile string) error {
+	//nolint:gosec
+	config := &tls.Config{InsecureSkipVerify: true}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("read ca file failure, err=%w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate in the ca file %s", caFile)
+		}
+		config = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("load client certificate failure, err=%w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	tlsConfig = config
+	return nil
+}
+
+// SetBearerToken sets the token in the Authorization header of the requests
+func SetBearerToken(token string) {
+	bearerToken = token
+}
+
 func (r *req) Header(h map[string]string) {
 	r.header = h
 }
@@ -98,8 +141,7 @@ func (r *req) Send(body any) error {
 	}
 
 	tr := &http.Transport{
-		//nolint:gosec
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		TLSClientConfig: tlsConfig,
 	}
 	c := &http.Client{Transport: tr}
 	resp, err := c.Do(_req)
@@ -131,6 +173,10 @@ func (r *req) setReqHeader(req *http.Request) *http.Request {
 		req.Header.Set(k, v)
 	}
 
+	if bearerToken != "" && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+
 	if req.Header.Get("x-request-id") == "" {
 		req.Header.Set("x-request-id", uuid.New().String())
 	}
@@ -27,6 +27,14 @@ import (
 )
 
 var _ = Describe("Test req", func() {
+	Context("Test set tls", func() {
+		It("should fail with invalid files", func() {
+			Expect(SetTLS("/not/exist/ca.crt", "", "")).NotTo(Succeed())
+			Expect(SetTLS("", "/not/exist/tls.crt", "/not/exist/tls.key")).NotTo(Succeed())
+			Expect(tlsConfig.InsecureSkipVerify).To(BeTrue())
+		})
+	})
+
 	Context("Test set header", func() {
 		It("should set header for post", func() {
 			bs := []byte("test")
@@ -39,5 +47,16 @@ var _ = Describe("Test req", func() {
 			Expect(_req.Header.Get("Content-Type")).To(Equal("application/json"))
 			Expect(_req.Header.Get("x-request-id")).ToNot(BeEmpty())
 		})
+
+		It("should set bearer token", func() {
+			SetBearerToken("token")
+			defer SetBearerToken("")
+
+			r := NewRequest(context.Background(), "GET", "http://localhost:8080")
+			_req, err := http.NewRequestWithContext(context.Background(), "GET", "http://localhost:8080", nil)
+			Expect(err).To(BeNil())
+			_req = r.(*req).setReqHeader(_req)
+			Expect(_req.Header.Get("Authorization")).To(Equal("Bearer token"))
+		})
 	})
 })
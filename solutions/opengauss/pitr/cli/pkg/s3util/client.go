@@ -0,0 +1,204 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package s3util is a minimal client of S3 compatible object storages, e.g. AWS S3 and MinIO, on top of the AWS SDK.
+package s3util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+var (
+	// ErrNotFound is returned if the object does not exist
+	ErrNotFound = errors.New("object not found")
+	// ErrPreconditionFailed is returned if the condition of a conditional write is not met
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+type (
+	// Config of the client
+	Config struct {
+		// Endpoint is the url of the object storage, e.g. http://127.0.0.1:9000.
+		// The bucket is addressed in the path if it is set, otherwise https://<bucket>.s3.<region>.amazonaws.com is used.
+		Endpoint        string
+		Region          string
+		Bucket          string
+		AccessKeyID     string
+		SecretAccessKey string
+		SessionToken    string
+	}
+
+	// PutOptions are the conditions of a write
+	PutOptions struct {
+		// IfMatch writes the object only if its current ETag is the same
+		IfMatch string
+		// IfNoneMatch writes the object only if it does not exist when it is `*`
+		IfNoneMatch string
+	}
+
+	// DeleteOptions are the conditions of a delete
+	DeleteOptions struct {
+		// IfMatch deletes the object only if its current ETag is the same
+		IfMatch string
+	}
+
+	IClient interface {
+		// GetObject returns the data and the ETag of the object
+		GetObject(ctx context.Context, key string) ([]byte, string, error)
+		// PutObject writes the object and returns its new ETag
+		PutObject(ctx context.Context, key string, data []byte, opts *PutOptions) (string, error)
+		DeleteObject(ctx context.Context, key string, opts *DeleteOptions) error
+		// ListObjects returns the keys of all the objects with the prefix
+		ListObjects(ctx context.Context, prefix string) ([]string, error)
+	}
+
+	client struct {
+		bucket string
+		s3     *s3.Client
+	}
+)
+
+func NewClient(conf *Config) (IClient, error) {
+	if conf.Bucket == "" {
+		return nil, fmt.Errorf("bucket is empty")
+	}
+	if conf.AccessKeyID == "" || conf.SecretAccessKey == "" {
+		return nil, fmt.Errorf("access key id or secret access key is empty")
+	}
+	region := conf.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	creds := aws.Credentials{
+		AccessKeyID:     conf.AccessKeyID,
+		SecretAccessKey: conf.SecretAccessKey,
+		SessionToken:    conf.SessionToken,
+		Source:          "s3util",
+	}
+	opts := s3.Options{
+		Region: region,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return creds, nil
+		}),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	if conf.Endpoint != "" {
+		opts.BaseEndpoint = aws.String(conf.Endpoint)
+		opts.UsePathStyle = true
+	}
+
+	return &client{
+		bucket: conf.Bucket,
+		s3:     s3.New(opts),
+	}, nil
+}
+
+// wrap turns the error responses of the missing objects and the failed conditions to ErrNotFound and ErrPreconditionFailed
+func wrap(op, key string, err error) error {
+	re := &awshttp.ResponseError{}
+	if errors.As(err, &re) {
+		switch re.HTTPStatusCode() {
+		case http.StatusNotFound:
+			return ErrNotFound
+		// 409 is returned by AWS S3 if a conditional write is conflicted with another one in progress
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			return ErrPreconditionFailed
+		}
+	}
+	return fmt.Errorf("%s %s failed, err=%w", op, key, err)
+}
+
+func (c *client) GetObject(ctx context.Context, key string) ([]byte, string, error) {
+	out, err := c.s3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, "", wrap("get", key, err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid response, err=%w", err)
+	}
+	return data, aws.ToString(out.ETag), nil
+}
+
+func (c *client) PutObject(ctx context.Context, key string, data []byte, opts *PutOptions) (string, error) {
+	in := &s3.PutObjectInput{
+		Bucket:      aws.String(c.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	}
+	if opts != nil && opts.IfMatch != "" {
+		in.IfMatch = aws.String(opts.IfMatch)
+	}
+	if opts != nil && opts.IfNoneMatch != "" {
+		in.IfNoneMatch = aws.String(opts.IfNoneMatch)
+	}
+
+	out, err := c.s3.PutObject(ctx, in)
+	if err != nil {
+		return "", wrap("put", key, err)
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+func (c *client) DeleteObject(ctx context.Context, key string, opts *DeleteOptions) error {
+	in := &s3.DeleteObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	}
+	if opts != nil && opts.IfMatch != "" {
+		in.IfMatch = aws.String(opts.IfMatch)
+	}
+
+	if _, err := c.s3.DeleteObject(ctx, in); err != nil {
+		return wrap("delete", key, err)
+	}
+	return nil
+}
+
+func (c *client) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	pages := s3.NewListObjectsV2Paginator(c.s3, &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, wrap("list", prefix, err)
+		}
+		for _, v := range page.Contents {
+			keys = append(keys, aws.ToString(v.Key))
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,156 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3util
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeS3 is an in-memory bucket supporting the conditional writes
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+}
+
+func etagOf(data []byte) string {
+	h := md5.Sum(data)
+	return fmt.Sprintf("%q", hex.EncodeToString(h[:]))
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/%s/", f.bucket))
+	data, exists := f.objects[key]
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		keys := []string{}
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		_, _ = io.WriteString(w, "<ListBucketResult>")
+		for _, k := range keys {
+			_, _ = fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", k)
+		}
+		_, _ = io.WriteString(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+	case r.Method == http.MethodGet:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etagOf(data))
+		_, _ = w.Write(data)
+	case r.Method == http.MethodPut:
+		if m := r.Header.Get("If-Match"); m != "" && (!exists || m != etagOf(data)) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+		w.Header().Set("ETag", etagOf(body))
+	case r.Method == http.MethodDelete:
+		if m := r.Header.Get("If-Match"); m != "" && (!exists || m != etagOf(data)) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+var _ = Describe("S3 client", func() {
+	Context("Test objects", func() {
+		var (
+			server *httptest.Server
+			c      IClient
+			ctx    = context.Background()
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(&fakeS3{bucket: "pitr", objects: map[string][]byte{}})
+			var err error
+			c, err = NewClient(&Config{Endpoint: server.URL, Bucket: "pitr", AccessKeyID: "minio", SecretAccessKey: "minio123"})
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should put, get, list and delete objects", func() {
+			etag, err := c.PutObject(ctx, "catalog/backup/a.json", []byte(`{"a":1}`), nil)
+			Expect(err).To(BeNil())
+			Expect(etag).To(Equal(etagOf([]byte(`{"a":1}`))))
+
+			data, got, err := c.GetObject(ctx, "catalog/backup/a.json")
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(Equal(`{"a":1}`))
+			Expect(got).To(Equal(etag))
+
+			keys, err := c.ListObjects(ctx, "catalog/")
+			Expect(err).To(BeNil())
+			Expect(keys).To(Equal([]string{"catalog/backup/a.json"}))
+
+			Expect(c.DeleteObject(ctx, "catalog/backup/a.json", nil)).To(Succeed())
+			_, _, err = c.GetObject(ctx, "catalog/backup/a.json")
+			Expect(err).To(Equal(ErrNotFound))
+		})
+
+		It("should reject the writes if the conditions are not met", func() {
+			etag, err := c.PutObject(ctx, "a.json", []byte(`{}`), &PutOptions{IfNoneMatch: "*"})
+			Expect(err).To(BeNil())
+
+			_, err = c.PutObject(ctx, "a.json", []byte(`{}`), &PutOptions{IfNoneMatch: "*"})
+			Expect(err).To(Equal(ErrPreconditionFailed))
+
+			_, err = c.PutObject(ctx, "a.json", []byte(`{"a":1}`), &PutOptions{IfMatch: etag})
+			Expect(err).To(BeNil())
+			_, err = c.PutObject(ctx, "a.json", []byte(`{"a":2}`), &PutOptions{IfMatch: etag})
+			Expect(err).To(Equal(ErrPreconditionFailed))
+
+			Expect(c.DeleteObject(ctx, "a.json", &DeleteOptions{IfMatch: etag})).To(Equal(ErrPreconditionFailed))
+			Expect(c.DeleteObject(ctx, "a.json", &DeleteOptions{IfMatch: etagOf([]byte(`{"a":1}`))})).To(Succeed())
+		})
+	})
+})
@@ -20,7 +20,8 @@ package timeutil
 import "time"
 
 const (
-	unifiedTimeFormat = "2006-01-02 15:04:05"
+	// UnifiedTimeLayout is the layout of the times in the backup records
+	UnifiedTimeLayout = "2006-01-02 15:04:05"
 )
 
 type atime struct {
@@ -49,7 +50,7 @@ func Init() string {
 }
 
 func UnifiedTimeFormat(t time.Time) string {
-	return t.Format(unifiedTimeFormat)
+	return t.Format(UnifiedTimeLayout)
 }
 
 func UnixTimestampFormat(t time.Time) int64 {